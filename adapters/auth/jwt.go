@@ -18,22 +18,26 @@ type jwtClaims struct {
 type JWTService struct {
 	secretKey  []byte
 	expiration time.Duration
+	leeway     time.Duration
 }
 
-// NewJWTService creates a new JWT service with the provided secret key and token expiration duration.
-func NewJWTService(secret string, expiration time.Duration) *JWTService {
+// NewJWTService creates a new JWT service with the provided secret key, token expiration duration
+// and the clock skew leeway tolerated when validating exp, nbf and iat claims.
+func NewJWTService(secret string, expiration, leeway time.Duration) *JWTService {
 	secretKey := []byte(secret)
 	return &JWTService{
 		secretKey:  secretKey,
 		expiration: expiration,
+		leeway:     leeway,
 	}
 }
 
-// GenerateToken creates a signed JWT token for the specified user ID with configured expiration.
-func (j *JWTService) GenerateToken(userID int) (string, error) {
+// GenerateToken creates a signed JWT token for the specified user ID and role with configured expiration.
+func (j *JWTService) GenerateToken(userID int, role string) (string, error) {
 	claims := jwtClaims{
 		Claims: domain.Claims{
 			UserID: userID,
+			Role:   role,
 		},
 		RegisteredClaims: jwt.RegisteredClaims{
 			ExpiresAt: jwt.NewNumericDate(time.Now().Add(j.expiration)),
@@ -50,14 +54,15 @@ func (j *JWTService) GenerateToken(userID int) (string, error) {
 	return tokenString, nil
 }
 
-// ValidateToken verifies the token signature and expiration, returning the extracted claims.
+// ValidateToken verifies the token signature and time-based claims within the configured leeway,
+// returning the extracted claims. A token without a role claim gets domain.RoleUser.
 func (j *JWTService) ValidateToken(tokenString string) (*domain.Claims, error) {
 	token, err := jwt.ParseWithClaims(tokenString, &jwtClaims{}, func(token *jwt.Token) (any, error) {
 		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
 			return nil, fmt.Errorf("unexpected signing method, got %v", token.Header["alg"])
 		}
 		return j.secretKey, nil
-	})
+	}, jwt.WithLeeway(j.leeway), jwt.WithIssuedAt())
 	if err != nil {
 		return nil, fmt.Errorf("failed to parse token: %w", err)
 	}
@@ -66,6 +71,10 @@ func (j *JWTService) ValidateToken(tokenString string) (*domain.Claims, error) {
 	if !ok {
 		return nil, fmt.Errorf("invalid token claims type")
 	}
+	// Tokens issued before roles existed carry none; they belong to regular users.
+	if claims.Role == "" {
+		claims.Role = domain.RoleUser
+	}
 
 	return &claims.Claims, nil
 }
@@ -0,0 +1,112 @@
+package auth
+
+import (
+	"myproject/domain"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testSecret = "this-is-a-test-secret-key-with-32-chars"
+
+func signTestToken(t *testing.T, claims jwtClaims) string {
+	t.Helper()
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString([]byte(testSecret))
+	require.NoError(t, err)
+	return token
+}
+
+func TestJWTService_ValidateToken_ClockSkew(t *testing.T) {
+	leeway := 30 * time.Second
+	now := time.Now()
+
+	tests := []struct {
+		name    string
+		claims  jwt.RegisteredClaims
+		wantErr bool
+	}{
+		{
+			name: "accepts token expired within leeway",
+			claims: jwt.RegisteredClaims{
+				IssuedAt:  jwt.NewNumericDate(now.Add(-time.Hour)),
+				ExpiresAt: jwt.NewNumericDate(now.Add(-10 * time.Second)),
+			},
+			wantErr: false,
+		},
+		{
+			name: "rejects token expired beyond leeway",
+			claims: jwt.RegisteredClaims{
+				IssuedAt:  jwt.NewNumericDate(now.Add(-time.Hour)),
+				ExpiresAt: jwt.NewNumericDate(now.Add(-time.Minute)),
+			},
+			wantErr: true,
+		},
+		{
+			name: "accepts token issued slightly in the future",
+			claims: jwt.RegisteredClaims{
+				IssuedAt:  jwt.NewNumericDate(now.Add(10 * time.Second)),
+				NotBefore: jwt.NewNumericDate(now.Add(10 * time.Second)),
+				ExpiresAt: jwt.NewNumericDate(now.Add(time.Hour)),
+			},
+			wantErr: false,
+		},
+		{
+			name: "rejects token issued beyond leeway in the future",
+			claims: jwt.RegisteredClaims{
+				IssuedAt:  jwt.NewNumericDate(now.Add(time.Minute)),
+				ExpiresAt: jwt.NewNumericDate(now.Add(time.Hour)),
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := NewJWTService(testSecret, time.Hour, leeway)
+			token := signTestToken(t, jwtClaims{
+				Claims:           domain.Claims{UserID: 7},
+				RegisteredClaims: tt.claims,
+			})
+
+			claims, err := service.ValidateToken(token)
+
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, 7, claims.UserID)
+		})
+	}
+}
+
+func TestJWTService_Role(t *testing.T) {
+	service := NewJWTService(testSecret, time.Hour, 0)
+
+	token, err := service.GenerateToken(7, domain.RoleAdmin)
+	require.NoError(t, err)
+
+	claims, err := service.ValidateToken(token)
+	require.NoError(t, err)
+	assert.Equal(t, 7, claims.UserID)
+	assert.Equal(t, domain.RoleAdmin, claims.Role)
+}
+
+func TestJWTService_TokenWithoutRole(t *testing.T) {
+	service := NewJWTService(testSecret, time.Hour, 0)
+	token := signTestToken(t, jwtClaims{
+		Claims: domain.Claims{UserID: 7},
+		RegisteredClaims: jwt.RegisteredClaims{
+			IssuedAt:  jwt.NewNumericDate(time.Now()),
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Hour)),
+		},
+	})
+
+	claims, err := service.ValidateToken(token)
+	require.NoError(t, err)
+	assert.Equal(t, 7, claims.UserID)
+	assert.Equal(t, domain.RoleUser, claims.Role)
+}
@@ -0,0 +1,117 @@
+package auth
+
+import (
+	"encoding/json"
+	"math"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// bucket is the token bucket of a single client.
+type bucket struct {
+	tokens   float64
+	lastSeen time.Time
+}
+
+// RateLimiter hands out up to limit requests per window to each client IP,
+// refilling tokens continuously so bursts are allowed up to the full limit.
+type RateLimiter struct {
+	mu        sync.Mutex
+	buckets   map[string]*bucket
+	limit     float64
+	window    time.Duration
+	lastSweep time.Time
+	now       func() time.Time
+}
+
+// NewRateLimiter creates a limiter allowing limit requests per client within window.
+func NewRateLimiter(limit int, window time.Duration) *RateLimiter {
+	return &RateLimiter{
+		buckets: make(map[string]*bucket),
+		limit:   float64(limit),
+		window:  window,
+		now:     time.Now,
+	}
+}
+
+// Allow takes a token from the client's bucket. When the bucket is empty it
+// reports false together with the time until the next token is available.
+func (rl *RateLimiter) Allow(client string) (bool, time.Duration) {
+	rl.mu.Lock()
+	defer rl.mu.Unlock()
+
+	now := rl.now()
+	rl.sweep(now)
+
+	b, ok := rl.buckets[client]
+	if !ok {
+		b = &bucket{tokens: rl.limit, lastSeen: now}
+		rl.buckets[client] = b
+	}
+
+	rate := rl.limit / rl.window.Seconds()
+	b.tokens = math.Min(rl.limit, b.tokens+now.Sub(b.lastSeen).Seconds()*rate)
+	b.lastSeen = now
+
+	if b.tokens < 1 {
+		wait := time.Duration((1 - b.tokens) / rate * float64(time.Second))
+		return false, wait
+	}
+	b.tokens--
+	return true, 0
+}
+
+// sweep drops the buckets of clients idle for a whole window, at most once per window.
+// Such buckets are full again, so forgetting them does not change any decision.
+func (rl *RateLimiter) sweep(now time.Time) {
+	if now.Sub(rl.lastSweep) < rl.window {
+		return
+	}
+	for client, b := range rl.buckets {
+		if now.Sub(b.lastSeen) >= rl.window {
+			delete(rl.buckets, client)
+		}
+	}
+	rl.lastSweep = now
+}
+
+// Middleware rejects requests from clients that exceeded their limit with
+// 429 Too Many Requests and a Retry-After header in whole seconds.
+func (rl *RateLimiter) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		allowed, wait := rl.Allow(requestIP(r))
+		if !allowed {
+			w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(wait.Seconds()))))
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusTooManyRequests)
+			json.NewEncoder(w).Encode(map[string]string{"error": "Too many requests"})
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// RateLimitMiddleware returns HTTP middleware limiting each client IP to limit requests per window.
+func RateLimitMiddleware(limit int, window time.Duration) func(http.Handler) http.Handler {
+	return NewRateLimiter(limit, window).Middleware
+}
+
+// requestIP identifies the client by the first X-Forwarded-For address when the
+// server sits behind a proxy, falling back to the host of the remote address.
+func requestIP(r *http.Request) string {
+	if forwarded := r.Header.Get("X-Forwarded-For"); forwarded != "" {
+		first, _, _ := strings.Cut(forwarded, ",")
+		if ip := strings.TrimSpace(first); ip != "" {
+			return ip
+		}
+	}
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
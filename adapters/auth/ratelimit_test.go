@@ -0,0 +1,103 @@
+package auth
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestRateLimiter(limit int, window time.Duration) (*RateLimiter, *time.Time) {
+	now := time.Date(2025, 6, 15, 12, 0, 0, 0, time.UTC)
+	rl := NewRateLimiter(limit, window)
+	rl.now = func() time.Time { return now }
+	return rl, &now
+}
+
+func TestRateLimiter_Allow(t *testing.T) {
+	t.Run("allows a burst up to the limit", func(t *testing.T) {
+		rl, _ := newTestRateLimiter(3, time.Minute)
+
+		for i := 0; i < 3; i++ {
+			allowed, _ := rl.Allow("10.0.0.1")
+			assert.True(t, allowed, "request %d", i+1)
+		}
+		allowed, wait := rl.Allow("10.0.0.1")
+		assert.False(t, allowed)
+		assert.Equal(t, 20*time.Second, wait)
+	})
+
+	t.Run("refills tokens over time", func(t *testing.T) {
+		rl, now := newTestRateLimiter(3, time.Minute)
+		for i := 0; i < 3; i++ {
+			rl.Allow("10.0.0.1")
+		}
+
+		*now = now.Add(20 * time.Second)
+
+		allowed, _ := rl.Allow("10.0.0.1")
+		assert.True(t, allowed)
+		allowed, _ = rl.Allow("10.0.0.1")
+		assert.False(t, allowed)
+	})
+
+	t.Run("tracks clients separately", func(t *testing.T) {
+		rl, _ := newTestRateLimiter(1, time.Minute)
+
+		allowed, _ := rl.Allow("10.0.0.1")
+		assert.True(t, allowed)
+		allowed, _ = rl.Allow("10.0.0.2")
+		assert.True(t, allowed)
+		allowed, _ = rl.Allow("10.0.0.1")
+		assert.False(t, allowed)
+	})
+
+	t.Run("forgets clients idle for a whole window", func(t *testing.T) {
+		rl, now := newTestRateLimiter(1, time.Minute)
+		rl.Allow("10.0.0.1")
+		*now = now.Add(30 * time.Second)
+		rl.Allow("10.0.0.2")
+
+		*now = now.Add(40 * time.Second)
+		rl.Allow("10.0.0.3")
+
+		assert.NotContains(t, rl.buckets, "10.0.0.1")
+		assert.Contains(t, rl.buckets, "10.0.0.2")
+		assert.Contains(t, rl.buckets, "10.0.0.3")
+	})
+}
+
+func TestRateLimiter_Middleware(t *testing.T) {
+	rl, _ := newTestRateLimiter(1, time.Minute)
+	handler := rl.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	send := func(remoteAddr, forwardedFor string) *httptest.ResponseRecorder {
+		request := httptest.NewRequest(http.MethodPost, "/login", nil)
+		request.RemoteAddr = remoteAddr
+		if forwardedFor != "" {
+			request.Header.Set("X-Forwarded-For", forwardedFor)
+		}
+		response := httptest.NewRecorder()
+		handler.ServeHTTP(response, request)
+		return response
+	}
+
+	t.Run("returns 429 with Retry-After once the limit is exceeded", func(t *testing.T) {
+		assert.Equal(t, http.StatusOK, send("192.0.2.1:5000", "").Code)
+
+		response := send("192.0.2.1:5001", "")
+		assert.Equal(t, http.StatusTooManyRequests, response.Code)
+		assert.Equal(t, "60", response.Header().Get("Retry-After"))
+		assert.JSONEq(t, `{"error":"Too many requests"}`, response.Body.String())
+	})
+
+	t.Run("identifies clients behind a proxy by X-Forwarded-For", func(t *testing.T) {
+		assert.Equal(t, http.StatusOK, send("10.0.0.100:80", "203.0.113.7, 10.0.0.50").Code)
+		assert.Equal(t, http.StatusTooManyRequests, send("10.0.0.101:80", "203.0.113.7").Code)
+		assert.Equal(t, http.StatusOK, send("10.0.0.100:80", "203.0.113.8").Code)
+	})
+}
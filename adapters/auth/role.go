@@ -0,0 +1,27 @@
+package auth
+
+import (
+	"encoding/json"
+	"fmt"
+	"myproject/application"
+	"net/http"
+	"slices"
+	"strings"
+)
+
+// RequireRole returns HTTP middleware that lets only users with one of the given roles through and
+// answers everyone else with 403 Forbidden. It must run after authentication, which puts the role into the context.
+func RequireRole(roles ...string) func(http.Handler) http.Handler {
+	message := fmt.Sprintf("Insufficient permissions: requires role %s", strings.Join(roles, " or "))
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if !slices.Contains(roles, application.GetUserRoleFromContext(r.Context())) {
+				w.Header().Set("Content-Type", "application/json")
+				w.WriteHeader(http.StatusForbidden)
+				json.NewEncoder(w).Encode(map[string]string{"error": message})
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
@@ -0,0 +1,48 @@
+package auth
+
+import (
+	"context"
+	"myproject/application"
+	"myproject/domain"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRequireRole(t *testing.T) {
+	withRole := func(role string) context.Context {
+		return context.WithValue(context.Background(), application.RoleKey, role)
+	}
+	tests := []struct {
+		name        string
+		roles       []string
+		ctx         context.Context
+		wantStatus  int
+		wantMessage string
+	}{
+		{"lets admins through", []string{domain.RoleAdmin}, withRole(domain.RoleAdmin), http.StatusOK, ""},
+		{"lets any of several roles through", []string{domain.RoleAdmin, domain.RoleUser}, withRole(domain.RoleUser), http.StatusOK, ""},
+		{"rejects other roles", []string{domain.RoleAdmin}, withRole(domain.RoleUser), http.StatusForbidden, "Insufficient permissions: requires role admin"},
+		{"rejects requests without a role", []string{domain.RoleAdmin}, context.Background(), http.StatusForbidden, "Insufficient permissions: requires role admin"},
+		{"names every accepted role", []string{domain.RoleAdmin, "auditor"}, withRole(domain.RoleUser), http.StatusForbidden, "Insufficient permissions: requires role admin or auditor"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := RequireRole(tt.roles...)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusOK)
+			}))
+			request := httptest.NewRequest(http.MethodGet, "/admin/users", nil).WithContext(tt.ctx)
+			response := httptest.NewRecorder()
+
+			handler.ServeHTTP(response, request)
+
+			assert.Equal(t, tt.wantStatus, response.Code)
+			if tt.wantStatus == http.StatusForbidden {
+				assert.JSONEq(t, `{"error":"`+tt.wantMessage+`"}`, response.Body.String())
+			}
+		})
+	}
+}
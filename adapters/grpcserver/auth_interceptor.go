@@ -7,11 +7,13 @@ import (
 	"myproject/application"
 	"myproject/domain"
 	"myproject/logger"
+	"net"
 	"strings"
 
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
 	"google.golang.org/grpc/status"
 )
 
@@ -30,6 +32,10 @@ func NewAuthInterceptor(tokenGenerator domain.TokenGenerator, logger *slog.Logge
 }
 
 func (a *AuthInterceptor) UnaryInterceptor(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	if p, ok := peer.FromContext(ctx); ok && p.Addr != nil {
+		ctx = logger.WithClientIP(ctx, peerHost(p.Addr))
+	}
+
 	if a.isPublicMethod(info.FullMethod) {
 		return handler(ctx, req)
 	}
@@ -62,10 +68,20 @@ func (a *AuthInterceptor) UnaryInterceptor(ctx context.Context, req interface{},
 	)
 
 	ctx = context.WithValue(ctx, application.UserIDKey, userID)
+	ctx = context.WithValue(ctx, application.RoleKey, claims.Role)
 
 	return handler(ctx, req)
 }
 
+// peerHost returns the host part of a peer address.
+func peerHost(addr net.Addr) string {
+	host, _, err := net.SplitHostPort(addr.String())
+	if err != nil {
+		return addr.String()
+	}
+	return host
+}
+
 func (a *AuthInterceptor) extractToken(ctx context.Context) (string, error) {
 	md, ok := metadata.FromIncomingContext(ctx)
 	if !ok {
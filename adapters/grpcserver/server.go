@@ -6,26 +6,53 @@ import (
 	"log/slog"
 	"myproject/application"
 	"myproject/domain"
+	"myproject/domain/validation"
 
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
 )
 
 type TaskManageServer struct {
 	UnimplementedTaskManagerServer
-	authService domain.AuthService
-	taskService domain.TaskService
-	logger      *slog.Logger
+	authService     domain.AuthService
+	taskService     domain.TaskService
+	logger          *slog.Logger
+	defaultPageSize int
+	maxPageSize     int
 }
 
-func NewTaskManageServer(authService domain.AuthService, taskService domain.TaskService, logger *slog.Logger) *TaskManageServer {
-	return &TaskManageServer{
-		authService: authService,
-		taskService: taskService,
-		logger:      logger,
+// Option configures optional TaskManageServer behaviour.
+type Option func(*TaskManageServer)
+
+// WithPageSizes sets the page size ListTasks uses when no limit is given and the largest
+// limit it honours; larger limits are clamped and the reply reports the limit applied.
+// Non-positive values keep domain.DefaultPageLimit and domain.MaxPageLimit.
+func WithPageSizes(defaultSize, maxSize int) Option {
+	return func(g *TaskManageServer) {
+		if defaultSize > 0 {
+			g.defaultPageSize = defaultSize
+		}
+		if maxSize > 0 {
+			g.maxPageSize = maxSize
+		}
 	}
 }
 
+func NewTaskManageServer(authService domain.AuthService, taskService domain.TaskService, logger *slog.Logger, opts ...Option) *TaskManageServer {
+	g := &TaskManageServer{
+		authService:     authService,
+		taskService:     taskService,
+		logger:          logger,
+		defaultPageSize: domain.DefaultPageLimit,
+		maxPageSize:     domain.MaxPageLimit,
+	}
+	for _, opt := range opts {
+		opt(g)
+	}
+	return g
+}
+
 func (g TaskManageServer) Register(ctx context.Context, request *RegisterRequest) (*RegisterReply, error) {
 	token, err := g.authService.Register(ctx, request.Email, request.Password)
 	if err != nil {
@@ -48,12 +75,12 @@ func (g TaskManageServer) CreateTask(ctx context.Context, request *CreateTaskReq
 		return nil, status.Errorf(codes.Unauthenticated, "failed to get user ID from context: %v", err)
 	}
 
-	task, err := g.taskService.CreateTask(ctx, request.Description, userID)
+	task, err := g.taskService.CreateTask(ctx, request.Description, request.Done, nil, domain.PriorityNone, nil, userID)
 	if err != nil {
 		return nil, mapError(err, g.logger)
 	}
 
-	return &CreateTaskReply{TaskId: int32(task.ID)}, nil
+	return &CreateTaskReply{TaskId: int32(task.ID), Task: toProtoTask(task)}, nil
 }
 
 func (g TaskManageServer) GetTasks(ctx context.Context, request *GetTasksRequest) (*GetTasksReply, error) {
@@ -78,6 +105,114 @@ func (g TaskManageServer) GetTasks(ctx context.Context, request *GetTasksRequest
 	return &GetTasksReply{Tasks: reply}, nil
 }
 
+func (g TaskManageServer) GetTask(ctx context.Context, request *GetTaskRequest) (*TaskReply, error) {
+	userID, err := application.GetUserIDFromContext(ctx)
+	if err != nil {
+		return nil, status.Errorf(codes.Unauthenticated, "failed to get user ID from context: %v", err)
+	}
+	if request.Id <= 0 {
+		return nil, status.Error(codes.InvalidArgument, validation.ErrInvalidTaskID.Error())
+	}
+
+	task, err := g.taskService.GetTask(ctx, int(request.Id), userID)
+	if err != nil {
+		return nil, mapError(err, g.logger)
+	}
+
+	return &TaskReply{Task: toProtoTask(task)}, nil
+}
+
+func (g TaskManageServer) ListTasks(ctx context.Context, request *ListTasksRequest) (*ListTasksReply, error) {
+	userID, err := application.GetUserIDFromContext(ctx)
+	if err != nil {
+		return nil, status.Errorf(codes.Unauthenticated, "failed to get user ID from context: %v", err)
+	}
+
+	opts := domain.ListOptions{Limit: g.defaultPageSize, Offset: int(request.Offset)}
+	if request.Limit != 0 {
+		opts.Limit = int(request.Limit)
+	}
+	if opts.Limit < 0 {
+		return nil, status.Error(codes.InvalidArgument, validation.ErrInvalidLimit.Error())
+	}
+	if opts.Offset < 0 {
+		return nil, status.Error(codes.InvalidArgument, validation.ErrInvalidOffset.Error())
+	}
+	// Clamp instead of rejecting, like GET /tasks; ListTasksReply.Limit tells the client the limit applied
+	opts.Limit = min(opts.Limit, g.maxPageSize)
+
+	page, err := g.taskService.ListTasks(ctx, userID, opts)
+	if err != nil {
+		return nil, mapError(err, g.logger)
+	}
+
+	reply := &ListTasksReply{
+		Tasks:  make([]*Task, len(page.Tasks)),
+		Total:  int32(page.Total),
+		Limit:  int32(page.Limit),
+		Offset: int32(page.Offset),
+	}
+	for i, task := range page.Tasks {
+		reply.Tasks[i] = toProtoTask(task)
+	}
+
+	return reply, nil
+}
+
+func (g TaskManageServer) UpdateTask(ctx context.Context, request *UpdateTaskRequest) (*TaskReply, error) {
+	userID, err := application.GetUserIDFromContext(ctx)
+	if err != nil {
+		return nil, status.Errorf(codes.Unauthenticated, "failed to get user ID from context: %v", err)
+	}
+	if request.Id <= 0 {
+		return nil, status.Error(codes.InvalidArgument, validation.ErrInvalidTaskID.Error())
+	}
+
+	var version *int
+	if request.Version != nil {
+		v := int(*request.Version)
+		version = &v
+	}
+	task, err := g.taskService.UpdateTask(ctx, int(request.Id), userID, request.Description, request.Done, nil, nil, version)
+	if err != nil {
+		return nil, mapError(err, g.logger)
+	}
+
+	return &TaskReply{Task: toProtoTask(task)}, nil
+}
+
+func (g TaskManageServer) DeleteTask(ctx context.Context, request *DeleteTaskRequest) (*DeleteTaskReply, error) {
+	userID, err := application.GetUserIDFromContext(ctx)
+	if err != nil {
+		return nil, status.Errorf(codes.Unauthenticated, "failed to get user ID from context: %v", err)
+	}
+	if request.Id <= 0 {
+		return nil, status.Error(codes.InvalidArgument, validation.ErrInvalidTaskID.Error())
+	}
+
+	if err := g.taskService.DeleteTask(ctx, int(request.Id), userID); err != nil {
+		return nil, mapError(err, g.logger)
+	}
+
+	return &DeleteTaskReply{}, nil
+}
+
+// toProtoTask converts a domain task into its protobuf representation.
+func toProtoTask(task domain.Task) *Task {
+	pt := &Task{
+		Id:          int32(task.ID),
+		Description: task.Description,
+		Done:        task.Done,
+		CreatedAt:   timestamppb.New(task.CreatedAt),
+		UpdatedAt:   timestamppb.New(task.UpdatedAt),
+		Version:     int32(task.Version),
+	}
+	if task.CompletedAt != nil {
+		pt.CompletedAt = timestamppb.New(*task.CompletedAt)
+	}
+	return pt
+}
+
 func mapError(err error, logger *slog.Logger) error {
 	if err == nil {
 		return nil
@@ -90,14 +225,31 @@ func mapError(err error, logger *slog.Logger) error {
 	switch {
 	case errors.Is(err, domain.ErrDescriptionRequired),
 		errors.Is(err, domain.ErrDescriptionTooLong),
-		errors.Is(err, domain.ErrInvalidEmail):
+		errors.Is(err, domain.ErrEmptyFieldsToUpdate),
+		errors.Is(err, domain.ErrDueDateInPast),
+		errors.Is(err, domain.ErrInvalidPriority),
+		errors.Is(err, domain.ErrInvalidTag),
+		errors.Is(err, domain.ErrEmptyBatch),
+		errors.Is(err, domain.ErrInvalidEmail),
+		errors.Is(err, domain.ErrSamePassword),
+		domain.IsPasswordPolicyError(err):
 		return status.Error(codes.InvalidArgument, err.Error())
+	case errors.Is(err, domain.ErrTaskForbidden):
+		return status.Error(codes.PermissionDenied, "task belongs to another user")
+	case errors.Is(err, domain.ErrVersionConflict):
+		return status.Error(codes.Aborted, domain.ErrVersionConflict.Error())
+	case errors.Is(err, domain.ErrTaskNotFound):
+		return status.Error(codes.NotFound, "task not found")
 	case errors.Is(err, domain.ErrStorageFailure):
 		return status.Error(codes.Internal, "internal server error")
 	case errors.Is(err, domain.ErrEmailAlreadyExists):
 		return status.Error(codes.AlreadyExists, "email already registered")
+	case errors.Is(err, domain.ErrDuplicateTask):
+		return status.Error(codes.AlreadyExists, "task already exists")
 	case errors.Is(err, domain.ErrInvalidCredentials):
 		return status.Error(codes.Unauthenticated, "invalid credentials")
+	case errors.Is(err, domain.ErrAccountLocked):
+		return status.Error(codes.PermissionDenied, err.Error())
 	default:
 		return status.Error(codes.Internal, "internal server error")
 	}
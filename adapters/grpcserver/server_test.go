@@ -10,6 +10,7 @@ import (
 	"myproject/infrastructure/testhelpers"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -70,6 +71,120 @@ func TestGetTasks(t *testing.T) {
 	assert.Equal(t, testUserID, taskService.LastUserID)
 }
 
+func TestGetTask(t *testing.T) {
+	completedAt := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	taskService := &testhelpers.SpyTaskService{
+		ResultTask: domain.Task{ID: 7, Description: "Buy milk", Done: true, CompletedAt: &completedAt, CreatedAt: completedAt.Add(-time.Hour), UpdatedAt: completedAt},
+	}
+	authService := &testhelpers.SpyAuthService{}
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	server := NewTaskManageServer(authService, taskService, logger)
+
+	testUserID := 99
+	ctx := context.WithValue(context.Background(), application.UserIDKey, testUserID)
+
+	reply, err := server.GetTask(ctx, &GetTaskRequest{Id: 7})
+	require.NoError(t, err)
+
+	assert.Equal(t, 7, taskService.LastTaskID)
+	assert.Equal(t, testUserID, taskService.LastUserID)
+	assert.Equal(t, int32(7), reply.Task.Id)
+	assert.Equal(t, "Buy milk", reply.Task.Description)
+	assert.True(t, reply.Task.Done)
+	assert.True(t, completedAt.Equal(reply.Task.CompletedAt.AsTime()))
+	assert.True(t, completedAt.Add(-time.Hour).Equal(reply.Task.CreatedAt.AsTime()))
+	assert.True(t, completedAt.Equal(reply.Task.UpdatedAt.AsTime()))
+}
+
+func TestListTasks(t *testing.T) {
+	t.Run("applies default limit when none is given", func(t *testing.T) {
+		taskService := &testhelpers.SpyTaskService{
+			TasksTable: []domain.Task{
+				{ID: 1, Description: "task 1"},
+				{ID: 2, Description: "task 2", Done: true},
+			},
+		}
+		logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+		server := NewTaskManageServer(&testhelpers.SpyAuthService{}, taskService, logger)
+		ctx := context.WithValue(context.Background(), application.UserIDKey, 99)
+
+		reply, err := server.ListTasks(ctx, &ListTasksRequest{})
+		require.NoError(t, err)
+
+		assert.Equal(t, domain.ListOptions{Limit: domain.DefaultPageLimit}, taskService.LastListOptions)
+		assert.Len(t, reply.Tasks, 2)
+		assert.Equal(t, int32(2), reply.Total)
+		assert.Equal(t, int32(domain.DefaultPageLimit), reply.Limit)
+		assert.Nil(t, reply.Tasks[0].CompletedAt)
+	})
+
+	t.Run("passes limit and offset to the service", func(t *testing.T) {
+		taskService := &testhelpers.SpyTaskService{}
+		logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+		server := NewTaskManageServer(&testhelpers.SpyAuthService{}, taskService, logger)
+		ctx := context.WithValue(context.Background(), application.UserIDKey, 99)
+
+		reply, err := server.ListTasks(ctx, &ListTasksRequest{Limit: 5, Offset: 10})
+		require.NoError(t, err)
+
+		assert.Equal(t, domain.ListOptions{Limit: 5, Offset: 10}, taskService.LastListOptions)
+		assert.Equal(t, int32(5), reply.Limit)
+		assert.Equal(t, int32(10), reply.Offset)
+	})
+
+	t.Run("clamps the limit to the max page size", func(t *testing.T) {
+		taskService := &testhelpers.SpyTaskService{}
+		logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+		server := NewTaskManageServer(&testhelpers.SpyAuthService{}, taskService, logger, WithPageSizes(10, 50))
+		ctx := context.WithValue(context.Background(), application.UserIDKey, 99)
+
+		reply, err := server.ListTasks(ctx, &ListTasksRequest{Limit: 1000})
+		require.NoError(t, err)
+		assert.Equal(t, 50, taskService.LastListOptions.Limit)
+		assert.Equal(t, int32(50), reply.Limit)
+
+		_, err = server.ListTasks(ctx, &ListTasksRequest{})
+		require.NoError(t, err)
+		assert.Equal(t, 10, taskService.LastListOptions.Limit, "the configured default applies without a limit")
+	})
+}
+
+func TestUpdateTask(t *testing.T) {
+	taskService := &testhelpers.SpyTaskService{
+		ResultTask: domain.Task{ID: 3, Description: "Buy bread", Done: true, Version: 5},
+	}
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	server := NewTaskManageServer(&testhelpers.SpyAuthService{}, taskService, logger)
+	ctx := context.WithValue(context.Background(), application.UserIDKey, 99)
+
+	done := true
+	version := int32(4)
+	reply, err := server.UpdateTask(ctx, &UpdateTaskRequest{Id: 3, Done: &done, Version: &version})
+	require.NoError(t, err)
+
+	assert.Equal(t, 3, taskService.LastTaskID)
+	assert.Nil(t, taskService.UpdateDescription)
+	require.NotNil(t, taskService.UpdateDone)
+	assert.True(t, *taskService.UpdateDone)
+	require.NotNil(t, taskService.UpdateVersion)
+	assert.Equal(t, 4, *taskService.UpdateVersion)
+	assert.Equal(t, "Buy bread", reply.Task.Description)
+	assert.Equal(t, int32(5), reply.Task.Version)
+}
+
+func TestDeleteTask(t *testing.T) {
+	taskService := &testhelpers.SpyTaskService{}
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	server := NewTaskManageServer(&testhelpers.SpyAuthService{}, taskService, logger)
+	ctx := context.WithValue(context.Background(), application.UserIDKey, 99)
+
+	_, err := server.DeleteTask(ctx, &DeleteTaskRequest{Id: 4})
+	require.NoError(t, err)
+
+	assert.Equal(t, []int{4}, taskService.DeletedTaskIDs)
+	assert.Equal(t, 99, taskService.LastUserID)
+}
+
 func TestErrorsMapping(t *testing.T) {
 	tests := []struct {
 		name         string
@@ -101,6 +216,44 @@ func TestErrorsMapping(t *testing.T) {
 				return s.GetTasks(ctx, &GetTasksRequest{})
 			},
 		},
+		{
+			name:         "GetTask not found",
+			serviceErr:   domain.ErrTaskNotFound,
+			expectedCode: codes.NotFound,
+			call: func(ctx context.Context, s *TaskManageServer) (any, error) {
+				return s.GetTask(ctx, &GetTaskRequest{Id: 1})
+			},
+		},
+		{
+			name:         "GetTask invalid id",
+			expectedCode: codes.InvalidArgument,
+			call: func(ctx context.Context, s *TaskManageServer) (any, error) {
+				return s.GetTask(ctx, &GetTaskRequest{Id: 0})
+			},
+		},
+		{
+			name:         "ListTasks negative offset",
+			expectedCode: codes.InvalidArgument,
+			call: func(ctx context.Context, s *TaskManageServer) (any, error) {
+				return s.ListTasks(ctx, &ListTasksRequest{Offset: -1})
+			},
+		},
+		{
+			name:         "UpdateTask without fields",
+			serviceErr:   domain.ErrEmptyFieldsToUpdate,
+			expectedCode: codes.InvalidArgument,
+			call: func(ctx context.Context, s *TaskManageServer) (any, error) {
+				return s.UpdateTask(ctx, &UpdateTaskRequest{Id: 1})
+			},
+		},
+		{
+			name:         "DeleteTask not found",
+			serviceErr:   domain.ErrTaskNotFound,
+			expectedCode: codes.NotFound,
+			call: func(ctx context.Context, s *TaskManageServer) (any, error) {
+				return s.DeleteTask(ctx, &DeleteTaskRequest{Id: 1})
+			},
+		},
 		{
 			name:         "Register email already exists",
 			serviceErr:   domain.ErrEmailAlreadyExists,
@@ -159,3 +312,25 @@ func TestErrorsMapping(t *testing.T) {
 		})
 	}
 }
+
+func TestMapError(t *testing.T) {
+	tests := []struct {
+		err          error
+		expectedCode codes.Code
+	}{
+		{err: domain.ErrVersionConflict, expectedCode: codes.Aborted},
+		{err: domain.ErrInvalidPriority, expectedCode: codes.InvalidArgument},
+		{err: domain.ErrDueDateInPast, expectedCode: codes.InvalidArgument},
+		{err: domain.ErrInvalidTag, expectedCode: codes.InvalidArgument},
+		{err: domain.ErrEmptyBatch, expectedCode: codes.InvalidArgument},
+		{err: domain.ErrSamePassword, expectedCode: codes.InvalidArgument},
+		{err: domain.ErrTaskForbidden, expectedCode: codes.PermissionDenied},
+		{err: fmt.Errorf("update task: %w", domain.ErrVersionConflict), expectedCode: codes.Aborted},
+		{err: domain.ErrStorageFailure, expectedCode: codes.Internal},
+	}
+	for _, tt := range tests {
+		t.Run(tt.err.Error(), func(t *testing.T) {
+			assert.Equal(t, tt.expectedCode, status.Code(mapError(tt.err, nil)))
+		})
+	}
+}
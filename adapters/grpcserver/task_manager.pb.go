@@ -9,6 +9,7 @@ package grpcserver
 import (
 	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
 	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
 	reflect "reflect"
 	sync "sync"
 	unsafe "unsafe"
@@ -21,6 +22,106 @@ const (
 	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
 )
 
+// Task mirrors the task JSON returned by the REST API.
+type Task struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Unique task identifier.
+	Id int32 `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	// Task description.
+	Description string `protobuf:"bytes,2,opt,name=description,proto3" json:"description,omitempty"`
+	// Completion status (true = done).
+	Done bool `protobuf:"varint,3,opt,name=done,proto3" json:"done,omitempty"`
+	// Time the task was completed, unset while it is open.
+	CompletedAt *timestamppb.Timestamp `protobuf:"bytes,4,opt,name=completed_at,json=completedAt,proto3" json:"completed_at,omitempty"`
+	// Time the task was created.
+	CreatedAt *timestamppb.Timestamp `protobuf:"bytes,5,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	// Time the task was last changed.
+	UpdatedAt *timestamppb.Timestamp `protobuf:"bytes,6,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
+	// Incremented on every change; send it back in UpdateTaskRequest to reject stale writes.
+	Version       int32 `protobuf:"varint,7,opt,name=version,proto3" json:"version,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Task) Reset() {
+	*x = Task{}
+	mi := &file_task_manager_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Task) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Task) ProtoMessage() {}
+
+func (x *Task) ProtoReflect() protoreflect.Message {
+	mi := &file_task_manager_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Task.ProtoReflect.Descriptor instead.
+func (*Task) Descriptor() ([]byte, []int) {
+	return file_task_manager_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *Task) GetId() int32 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *Task) GetDescription() string {
+	if x != nil {
+		return x.Description
+	}
+	return ""
+}
+
+func (x *Task) GetDone() bool {
+	if x != nil {
+		return x.Done
+	}
+	return false
+}
+
+func (x *Task) GetCompletedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CompletedAt
+	}
+	return nil
+}
+
+func (x *Task) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+func (x *Task) GetUpdatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.UpdatedAt
+	}
+	return nil
+}
+
+func (x *Task) GetVersion() int32 {
+	if x != nil {
+		return x.Version
+	}
+	return 0
+}
+
 // RegisterRequest contains user registration credentials.
 type RegisterRequest struct {
 	state protoimpl.MessageState `protogen:"open.v1"`
@@ -34,7 +135,7 @@ type RegisterRequest struct {
 
 func (x *RegisterRequest) Reset() {
 	*x = RegisterRequest{}
-	mi := &file_task_manager_proto_msgTypes[0]
+	mi := &file_task_manager_proto_msgTypes[1]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -46,7 +147,7 @@ func (x *RegisterRequest) String() string {
 func (*RegisterRequest) ProtoMessage() {}
 
 func (x *RegisterRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_task_manager_proto_msgTypes[0]
+	mi := &file_task_manager_proto_msgTypes[1]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -59,7 +160,7 @@ func (x *RegisterRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use RegisterRequest.ProtoReflect.Descriptor instead.
 func (*RegisterRequest) Descriptor() ([]byte, []int) {
-	return file_task_manager_proto_rawDescGZIP(), []int{0}
+	return file_task_manager_proto_rawDescGZIP(), []int{1}
 }
 
 func (x *RegisterRequest) GetEmail() string {
@@ -87,7 +188,7 @@ type RegisterReply struct {
 
 func (x *RegisterReply) Reset() {
 	*x = RegisterReply{}
-	mi := &file_task_manager_proto_msgTypes[1]
+	mi := &file_task_manager_proto_msgTypes[2]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -99,7 +200,7 @@ func (x *RegisterReply) String() string {
 func (*RegisterReply) ProtoMessage() {}
 
 func (x *RegisterReply) ProtoReflect() protoreflect.Message {
-	mi := &file_task_manager_proto_msgTypes[1]
+	mi := &file_task_manager_proto_msgTypes[2]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -112,7 +213,7 @@ func (x *RegisterReply) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use RegisterReply.ProtoReflect.Descriptor instead.
 func (*RegisterReply) Descriptor() ([]byte, []int) {
-	return file_task_manager_proto_rawDescGZIP(), []int{1}
+	return file_task_manager_proto_rawDescGZIP(), []int{2}
 }
 
 func (x *RegisterReply) GetToken() string {
@@ -135,7 +236,7 @@ type LoginRequest struct {
 
 func (x *LoginRequest) Reset() {
 	*x = LoginRequest{}
-	mi := &file_task_manager_proto_msgTypes[2]
+	mi := &file_task_manager_proto_msgTypes[3]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -147,7 +248,7 @@ func (x *LoginRequest) String() string {
 func (*LoginRequest) ProtoMessage() {}
 
 func (x *LoginRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_task_manager_proto_msgTypes[2]
+	mi := &file_task_manager_proto_msgTypes[3]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -160,7 +261,7 @@ func (x *LoginRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use LoginRequest.ProtoReflect.Descriptor instead.
 func (*LoginRequest) Descriptor() ([]byte, []int) {
-	return file_task_manager_proto_rawDescGZIP(), []int{2}
+	return file_task_manager_proto_rawDescGZIP(), []int{3}
 }
 
 func (x *LoginRequest) GetEmail() string {
@@ -188,7 +289,7 @@ type LoginReply struct {
 
 func (x *LoginReply) Reset() {
 	*x = LoginReply{}
-	mi := &file_task_manager_proto_msgTypes[3]
+	mi := &file_task_manager_proto_msgTypes[4]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -200,7 +301,7 @@ func (x *LoginReply) String() string {
 func (*LoginReply) ProtoMessage() {}
 
 func (x *LoginReply) ProtoReflect() protoreflect.Message {
-	mi := &file_task_manager_proto_msgTypes[3]
+	mi := &file_task_manager_proto_msgTypes[4]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -213,7 +314,7 @@ func (x *LoginReply) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use LoginReply.ProtoReflect.Descriptor instead.
 func (*LoginReply) Descriptor() ([]byte, []int) {
-	return file_task_manager_proto_rawDescGZIP(), []int{3}
+	return file_task_manager_proto_rawDescGZIP(), []int{4}
 }
 
 func (x *LoginReply) GetToken() string {
@@ -229,14 +330,16 @@ type CreateTaskRequest struct {
 	// JWT token for authentication (will be moved to metadata).
 	Token string `protobuf:"bytes,1,opt,name=token,proto3" json:"token,omitempty"`
 	// Task description (required, max 200 characters).
-	Description   string `protobuf:"bytes,2,opt,name=description,proto3" json:"description,omitempty"`
+	Description string `protobuf:"bytes,2,opt,name=description,proto3" json:"description,omitempty"`
+	// Create the task as already completed.
+	Done          bool `protobuf:"varint,3,opt,name=done,proto3" json:"done,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
 func (x *CreateTaskRequest) Reset() {
 	*x = CreateTaskRequest{}
-	mi := &file_task_manager_proto_msgTypes[4]
+	mi := &file_task_manager_proto_msgTypes[5]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -248,7 +351,7 @@ func (x *CreateTaskRequest) String() string {
 func (*CreateTaskRequest) ProtoMessage() {}
 
 func (x *CreateTaskRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_task_manager_proto_msgTypes[4]
+	mi := &file_task_manager_proto_msgTypes[5]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -261,7 +364,7 @@ func (x *CreateTaskRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use CreateTaskRequest.ProtoReflect.Descriptor instead.
 func (*CreateTaskRequest) Descriptor() ([]byte, []int) {
-	return file_task_manager_proto_rawDescGZIP(), []int{4}
+	return file_task_manager_proto_rawDescGZIP(), []int{5}
 }
 
 func (x *CreateTaskRequest) GetToken() string {
@@ -278,18 +381,27 @@ func (x *CreateTaskRequest) GetDescription() string {
 	return ""
 }
 
+func (x *CreateTaskRequest) GetDone() bool {
+	if x != nil {
+		return x.Done
+	}
+	return false
+}
+
 // CreateTaskReply contains the ID of the created task.
 type CreateTaskReply struct {
 	state protoimpl.MessageState `protogen:"open.v1"`
 	// Unique task identifier.
-	TaskId        int32 `protobuf:"varint,1,opt,name=task_id,json=taskId,proto3" json:"task_id,omitempty"`
+	TaskId int32 `protobuf:"varint,1,opt,name=task_id,json=taskId,proto3" json:"task_id,omitempty"`
+	// The created task.
+	Task          *Task `protobuf:"bytes,2,opt,name=task,proto3" json:"task,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
 func (x *CreateTaskReply) Reset() {
 	*x = CreateTaskReply{}
-	mi := &file_task_manager_proto_msgTypes[5]
+	mi := &file_task_manager_proto_msgTypes[6]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -301,7 +413,7 @@ func (x *CreateTaskReply) String() string {
 func (*CreateTaskReply) ProtoMessage() {}
 
 func (x *CreateTaskReply) ProtoReflect() protoreflect.Message {
-	mi := &file_task_manager_proto_msgTypes[5]
+	mi := &file_task_manager_proto_msgTypes[6]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -314,7 +426,7 @@ func (x *CreateTaskReply) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use CreateTaskReply.ProtoReflect.Descriptor instead.
 func (*CreateTaskReply) Descriptor() ([]byte, []int) {
-	return file_task_manager_proto_rawDescGZIP(), []int{5}
+	return file_task_manager_proto_rawDescGZIP(), []int{6}
 }
 
 func (x *CreateTaskReply) GetTaskId() int32 {
@@ -324,6 +436,13 @@ func (x *CreateTaskReply) GetTaskId() int32 {
 	return 0
 }
 
+func (x *CreateTaskReply) GetTask() *Task {
+	if x != nil {
+		return x.Task
+	}
+	return nil
+}
+
 // GetTasksRequest for retrieving user's tasks.
 type GetTasksRequest struct {
 	state protoimpl.MessageState `protogen:"open.v1"`
@@ -335,7 +454,7 @@ type GetTasksRequest struct {
 
 func (x *GetTasksRequest) Reset() {
 	*x = GetTasksRequest{}
-	mi := &file_task_manager_proto_msgTypes[6]
+	mi := &file_task_manager_proto_msgTypes[7]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -347,7 +466,7 @@ func (x *GetTasksRequest) String() string {
 func (*GetTasksRequest) ProtoMessage() {}
 
 func (x *GetTasksRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_task_manager_proto_msgTypes[6]
+	mi := &file_task_manager_proto_msgTypes[7]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -360,7 +479,7 @@ func (x *GetTasksRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use GetTasksRequest.ProtoReflect.Descriptor instead.
 func (*GetTasksRequest) Descriptor() ([]byte, []int) {
-	return file_task_manager_proto_rawDescGZIP(), []int{6}
+	return file_task_manager_proto_rawDescGZIP(), []int{7}
 }
 
 func (x *GetTasksRequest) GetToken() string {
@@ -381,7 +500,7 @@ type GetTasksReply struct {
 
 func (x *GetTasksReply) Reset() {
 	*x = GetTasksReply{}
-	mi := &file_task_manager_proto_msgTypes[7]
+	mi := &file_task_manager_proto_msgTypes[8]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -393,7 +512,7 @@ func (x *GetTasksReply) String() string {
 func (*GetTasksReply) ProtoMessage() {}
 
 func (x *GetTasksReply) ProtoReflect() protoreflect.Message {
-	mi := &file_task_manager_proto_msgTypes[7]
+	mi := &file_task_manager_proto_msgTypes[8]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -406,7 +525,7 @@ func (x *GetTasksReply) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use GetTasksReply.ProtoReflect.Descriptor instead.
 func (*GetTasksReply) Descriptor() ([]byte, []int) {
-	return file_task_manager_proto_rawDescGZIP(), []int{7}
+	return file_task_manager_proto_rawDescGZIP(), []int{8}
 }
 
 func (x *GetTasksReply) GetTasks() []*GetTasksReply_Task {
@@ -416,6 +535,382 @@ func (x *GetTasksReply) GetTasks() []*GetTasksReply_Task {
 	return nil
 }
 
+// GetTaskRequest identifies the task to retrieve.
+type GetTaskRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Task identifier.
+	Id            int32 `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetTaskRequest) Reset() {
+	*x = GetTaskRequest{}
+	mi := &file_task_manager_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetTaskRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetTaskRequest) ProtoMessage() {}
+
+func (x *GetTaskRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_task_manager_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetTaskRequest.ProtoReflect.Descriptor instead.
+func (*GetTaskRequest) Descriptor() ([]byte, []int) {
+	return file_task_manager_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *GetTaskRequest) GetId() int32 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+// TaskReply contains a single task.
+type TaskReply struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// The requested or updated task.
+	Task          *Task `protobuf:"bytes,1,opt,name=task,proto3" json:"task,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TaskReply) Reset() {
+	*x = TaskReply{}
+	mi := &file_task_manager_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TaskReply) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TaskReply) ProtoMessage() {}
+
+func (x *TaskReply) ProtoReflect() protoreflect.Message {
+	mi := &file_task_manager_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TaskReply.ProtoReflect.Descriptor instead.
+func (*TaskReply) Descriptor() ([]byte, []int) {
+	return file_task_manager_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *TaskReply) GetTask() *Task {
+	if x != nil {
+		return x.Task
+	}
+	return nil
+}
+
+// ListTasksRequest selects a page of tasks.
+type ListTasksRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Maximum number of tasks to return (default 50).
+	Limit int32 `protobuf:"varint,1,opt,name=limit,proto3" json:"limit,omitempty"`
+	// Number of tasks to skip.
+	Offset        int32 `protobuf:"varint,2,opt,name=offset,proto3" json:"offset,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListTasksRequest) Reset() {
+	*x = ListTasksRequest{}
+	mi := &file_task_manager_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListTasksRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListTasksRequest) ProtoMessage() {}
+
+func (x *ListTasksRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_task_manager_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListTasksRequest.ProtoReflect.Descriptor instead.
+func (*ListTasksRequest) Descriptor() ([]byte, []int) {
+	return file_task_manager_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *ListTasksRequest) GetLimit() int32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+func (x *ListTasksRequest) GetOffset() int32 {
+	if x != nil {
+		return x.Offset
+	}
+	return 0
+}
+
+// ListTasksReply contains a page of tasks and the total count.
+type ListTasksReply struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Tasks in the requested page.
+	Tasks []*Task `protobuf:"bytes,1,rep,name=tasks,proto3" json:"tasks,omitempty"`
+	// Total number of tasks the user owns.
+	Total int32 `protobuf:"varint,2,opt,name=total,proto3" json:"total,omitempty"`
+	// Page size applied.
+	Limit int32 `protobuf:"varint,3,opt,name=limit,proto3" json:"limit,omitempty"`
+	// Offset applied.
+	Offset        int32 `protobuf:"varint,4,opt,name=offset,proto3" json:"offset,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListTasksReply) Reset() {
+	*x = ListTasksReply{}
+	mi := &file_task_manager_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListTasksReply) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListTasksReply) ProtoMessage() {}
+
+func (x *ListTasksReply) ProtoReflect() protoreflect.Message {
+	mi := &file_task_manager_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListTasksReply.ProtoReflect.Descriptor instead.
+func (*ListTasksReply) Descriptor() ([]byte, []int) {
+	return file_task_manager_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *ListTasksReply) GetTasks() []*Task {
+	if x != nil {
+		return x.Tasks
+	}
+	return nil
+}
+
+func (x *ListTasksReply) GetTotal() int32 {
+	if x != nil {
+		return x.Total
+	}
+	return 0
+}
+
+func (x *ListTasksReply) GetLimit() int32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+func (x *ListTasksReply) GetOffset() int32 {
+	if x != nil {
+		return x.Offset
+	}
+	return 0
+}
+
+// UpdateTaskRequest contains the fields to change; unset fields are left as-is.
+type UpdateTaskRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Task identifier.
+	Id int32 `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	// New task description.
+	Description *string `protobuf:"bytes,2,opt,name=description,proto3,oneof" json:"description,omitempty"`
+	// New completion status.
+	Done *bool `protobuf:"varint,3,opt,name=done,proto3,oneof" json:"done,omitempty"`
+	// Version the change is based on; the update fails with ABORTED if the task changed since. Unset skips the check.
+	Version       *int32 `protobuf:"varint,4,opt,name=version,proto3,oneof" json:"version,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UpdateTaskRequest) Reset() {
+	*x = UpdateTaskRequest{}
+	mi := &file_task_manager_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpdateTaskRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateTaskRequest) ProtoMessage() {}
+
+func (x *UpdateTaskRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_task_manager_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateTaskRequest.ProtoReflect.Descriptor instead.
+func (*UpdateTaskRequest) Descriptor() ([]byte, []int) {
+	return file_task_manager_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *UpdateTaskRequest) GetId() int32 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *UpdateTaskRequest) GetDescription() string {
+	if x != nil && x.Description != nil {
+		return *x.Description
+	}
+	return ""
+}
+
+func (x *UpdateTaskRequest) GetDone() bool {
+	if x != nil && x.Done != nil {
+		return *x.Done
+	}
+	return false
+}
+
+func (x *UpdateTaskRequest) GetVersion() int32 {
+	if x != nil && x.Version != nil {
+		return *x.Version
+	}
+	return 0
+}
+
+// DeleteTaskRequest identifies the task to delete.
+type DeleteTaskRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Task identifier.
+	Id            int32 `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteTaskRequest) Reset() {
+	*x = DeleteTaskRequest{}
+	mi := &file_task_manager_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteTaskRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteTaskRequest) ProtoMessage() {}
+
+func (x *DeleteTaskRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_task_manager_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteTaskRequest.ProtoReflect.Descriptor instead.
+func (*DeleteTaskRequest) Descriptor() ([]byte, []int) {
+	return file_task_manager_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *DeleteTaskRequest) GetId() int32 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+// DeleteTaskReply is returned after a task was deleted.
+type DeleteTaskReply struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteTaskReply) Reset() {
+	*x = DeleteTaskReply{}
+	mi := &file_task_manager_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteTaskReply) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteTaskReply) ProtoMessage() {}
+
+func (x *DeleteTaskReply) ProtoReflect() protoreflect.Message {
+	mi := &file_task_manager_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteTaskReply.ProtoReflect.Descriptor instead.
+func (*DeleteTaskReply) Descriptor() ([]byte, []int) {
+	return file_task_manager_proto_rawDescGZIP(), []int{15}
+}
+
 // Task represents a single task item.
 type GetTasksReply_Task struct {
 	state protoimpl.MessageState `protogen:"open.v1"`
@@ -431,7 +926,7 @@ type GetTasksReply_Task struct {
 
 func (x *GetTasksReply_Task) Reset() {
 	*x = GetTasksReply_Task{}
-	mi := &file_task_manager_proto_msgTypes[8]
+	mi := &file_task_manager_proto_msgTypes[16]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -443,7 +938,7 @@ func (x *GetTasksReply_Task) String() string {
 func (*GetTasksReply_Task) ProtoMessage() {}
 
 func (x *GetTasksReply_Task) ProtoReflect() protoreflect.Message {
-	mi := &file_task_manager_proto_msgTypes[8]
+	mi := &file_task_manager_proto_msgTypes[16]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -456,7 +951,7 @@ func (x *GetTasksReply_Task) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use GetTasksReply_Task.ProtoReflect.Descriptor instead.
 func (*GetTasksReply_Task) Descriptor() ([]byte, []int) {
-	return file_task_manager_proto_rawDescGZIP(), []int{7, 0}
+	return file_task_manager_proto_rawDescGZIP(), []int{8, 0}
 }
 
 func (x *GetTasksReply_Task) GetId() int32 {
@@ -485,7 +980,17 @@ var File_task_manager_proto protoreflect.FileDescriptor
 const file_task_manager_proto_rawDesc = "" +
 	"\n" +
 	"\x12task_manager.proto\x12\n" +
-	"grpcserver\"C\n" +
+	"grpcserver\x1a\x1fgoogle/protobuf/timestamp.proto\"\x9b\x02\n" +
+	"\x04Task\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x05R\x02id\x12 \n" +
+	"\vdescription\x18\x02 \x01(\tR\vdescription\x12\x12\n" +
+	"\x04done\x18\x03 \x01(\bR\x04done\x12=\n" +
+	"\fcompleted_at\x18\x04 \x01(\v2\x1a.google.protobuf.TimestampR\vcompletedAt\x129\n" +
+	"\n" +
+	"created_at\x18\x05 \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\x129\n" +
+	"\n" +
+	"updated_at\x18\x06 \x01(\v2\x1a.google.protobuf.TimestampR\tupdatedAt\x12\x18\n" +
+	"\aversion\x18\a \x01(\x05R\aversion\"C\n" +
 	"\x0fRegisterRequest\x12\x14\n" +
 	"\x05email\x18\x01 \x01(\tR\x05email\x12\x1a\n" +
 	"\bpassword\x18\x02 \x01(\tR\bpassword\"%\n" +
@@ -496,12 +1001,14 @@ const file_task_manager_proto_rawDesc = "" +
 	"\bpassword\x18\x02 \x01(\tR\bpassword\"\"\n" +
 	"\n" +
 	"LoginReply\x12\x14\n" +
-	"\x05token\x18\x01 \x01(\tR\x05token\"K\n" +
+	"\x05token\x18\x01 \x01(\tR\x05token\"_\n" +
 	"\x11CreateTaskRequest\x12\x14\n" +
 	"\x05token\x18\x01 \x01(\tR\x05token\x12 \n" +
-	"\vdescription\x18\x02 \x01(\tR\vdescription\"*\n" +
+	"\vdescription\x18\x02 \x01(\tR\vdescription\x12\x12\n" +
+	"\x04done\x18\x03 \x01(\bR\x04done\"P\n" +
 	"\x0fCreateTaskReply\x12\x17\n" +
-	"\atask_id\x18\x01 \x01(\x05R\x06taskId\"'\n" +
+	"\atask_id\x18\x01 \x01(\x05R\x06taskId\x12$\n" +
+	"\x04task\x18\x02 \x01(\v2\x10.grpcserver.TaskR\x04task\"'\n" +
 	"\x0fGetTasksRequest\x12\x14\n" +
 	"\x05token\x18\x01 \x01(\tR\x05token\"\x93\x01\n" +
 	"\rGetTasksReply\x124\n" +
@@ -509,13 +1016,43 @@ const file_task_manager_proto_rawDesc = "" +
 	"\x04Task\x12\x0e\n" +
 	"\x02id\x18\x01 \x01(\x05R\x02id\x12 \n" +
 	"\vdescription\x18\x02 \x01(\tR\vdescription\x12\x12\n" +
-	"\x04done\x18\x03 \x01(\bR\x04done2\xa2\x02\n" +
+	"\x04done\x18\x03 \x01(\bR\x04done\" \n" +
+	"\x0eGetTaskRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x05R\x02id\"1\n" +
+	"\tTaskReply\x12$\n" +
+	"\x04task\x18\x01 \x01(\v2\x10.grpcserver.TaskR\x04task\"@\n" +
+	"\x10ListTasksRequest\x12\x14\n" +
+	"\x05limit\x18\x01 \x01(\x05R\x05limit\x12\x16\n" +
+	"\x06offset\x18\x02 \x01(\x05R\x06offset\"|\n" +
+	"\x0eListTasksReply\x12&\n" +
+	"\x05tasks\x18\x01 \x03(\v2\x10.grpcserver.TaskR\x05tasks\x12\x14\n" +
+	"\x05total\x18\x02 \x01(\x05R\x05total\x12\x14\n" +
+	"\x05limit\x18\x03 \x01(\x05R\x05limit\x12\x16\n" +
+	"\x06offset\x18\x04 \x01(\x05R\x06offset\"\xa7\x01\n" +
+	"\x11UpdateTaskRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x05R\x02id\x12%\n" +
+	"\vdescription\x18\x02 \x01(\tH\x00R\vdescription\x88\x01\x01\x12\x17\n" +
+	"\x04done\x18\x03 \x01(\bH\x01R\x04done\x88\x01\x01\x12\x1d\n" +
+	"\aversion\x18\x04 \x01(\x05H\x02R\aversion\x88\x01\x01B\x0e\n" +
+	"\f_descriptionB\a\n" +
+	"\x05_doneB\n" +
+	"\n" +
+	"\b_version\"#\n" +
+	"\x11DeleteTaskRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x05R\x02id\"\x11\n" +
+	"\x0fDeleteTaskReply2\xbd\x04\n" +
 	"\vTaskManager\x12D\n" +
 	"\bRegister\x12\x1b.grpcserver.RegisterRequest\x1a\x19.grpcserver.RegisterReply\"\x00\x12;\n" +
 	"\x05Login\x12\x18.grpcserver.LoginRequest\x1a\x16.grpcserver.LoginReply\"\x00\x12J\n" +
 	"\n" +
 	"CreateTask\x12\x1d.grpcserver.CreateTaskRequest\x1a\x1b.grpcserver.CreateTaskReply\"\x00\x12D\n" +
-	"\bGetTasks\x12\x1b.grpcserver.GetTasksRequest\x1a\x19.grpcserver.GetTasksReply\"\x00B\x1fZ\x1dmyproject/adapters/grpcserverb\x06proto3"
+	"\bGetTasks\x12\x1b.grpcserver.GetTasksRequest\x1a\x19.grpcserver.GetTasksReply\"\x00\x12>\n" +
+	"\aGetTask\x12\x1a.grpcserver.GetTaskRequest\x1a\x15.grpcserver.TaskReply\"\x00\x12G\n" +
+	"\tListTasks\x12\x1c.grpcserver.ListTasksRequest\x1a\x1a.grpcserver.ListTasksReply\"\x00\x12D\n" +
+	"\n" +
+	"UpdateTask\x12\x1d.grpcserver.UpdateTaskRequest\x1a\x15.grpcserver.TaskReply\"\x00\x12J\n" +
+	"\n" +
+	"DeleteTask\x12\x1d.grpcserver.DeleteTaskRequest\x1a\x1b.grpcserver.DeleteTaskReply\"\x00B\x1fZ\x1dmyproject/adapters/grpcserverb\x06proto3"
 
 var (
 	file_task_manager_proto_rawDescOnce sync.Once
@@ -529,33 +1066,56 @@ func file_task_manager_proto_rawDescGZIP() []byte {
 	return file_task_manager_proto_rawDescData
 }
 
-var file_task_manager_proto_msgTypes = make([]protoimpl.MessageInfo, 9)
+var file_task_manager_proto_msgTypes = make([]protoimpl.MessageInfo, 17)
 var file_task_manager_proto_goTypes = []any{
-	(*RegisterRequest)(nil),    // 0: grpcserver.RegisterRequest
-	(*RegisterReply)(nil),      // 1: grpcserver.RegisterReply
-	(*LoginRequest)(nil),       // 2: grpcserver.LoginRequest
-	(*LoginReply)(nil),         // 3: grpcserver.LoginReply
-	(*CreateTaskRequest)(nil),  // 4: grpcserver.CreateTaskRequest
-	(*CreateTaskReply)(nil),    // 5: grpcserver.CreateTaskReply
-	(*GetTasksRequest)(nil),    // 6: grpcserver.GetTasksRequest
-	(*GetTasksReply)(nil),      // 7: grpcserver.GetTasksReply
-	(*GetTasksReply_Task)(nil), // 8: grpcserver.GetTasksReply.Task
+	(*Task)(nil),                  // 0: grpcserver.Task
+	(*RegisterRequest)(nil),       // 1: grpcserver.RegisterRequest
+	(*RegisterReply)(nil),         // 2: grpcserver.RegisterReply
+	(*LoginRequest)(nil),          // 3: grpcserver.LoginRequest
+	(*LoginReply)(nil),            // 4: grpcserver.LoginReply
+	(*CreateTaskRequest)(nil),     // 5: grpcserver.CreateTaskRequest
+	(*CreateTaskReply)(nil),       // 6: grpcserver.CreateTaskReply
+	(*GetTasksRequest)(nil),       // 7: grpcserver.GetTasksRequest
+	(*GetTasksReply)(nil),         // 8: grpcserver.GetTasksReply
+	(*GetTaskRequest)(nil),        // 9: grpcserver.GetTaskRequest
+	(*TaskReply)(nil),             // 10: grpcserver.TaskReply
+	(*ListTasksRequest)(nil),      // 11: grpcserver.ListTasksRequest
+	(*ListTasksReply)(nil),        // 12: grpcserver.ListTasksReply
+	(*UpdateTaskRequest)(nil),     // 13: grpcserver.UpdateTaskRequest
+	(*DeleteTaskRequest)(nil),     // 14: grpcserver.DeleteTaskRequest
+	(*DeleteTaskReply)(nil),       // 15: grpcserver.DeleteTaskReply
+	(*GetTasksReply_Task)(nil),    // 16: grpcserver.GetTasksReply.Task
+	(*timestamppb.Timestamp)(nil), // 17: google.protobuf.Timestamp
 }
 var file_task_manager_proto_depIdxs = []int32{
-	8, // 0: grpcserver.GetTasksReply.tasks:type_name -> grpcserver.GetTasksReply.Task
-	0, // 1: grpcserver.TaskManager.Register:input_type -> grpcserver.RegisterRequest
-	2, // 2: grpcserver.TaskManager.Login:input_type -> grpcserver.LoginRequest
-	4, // 3: grpcserver.TaskManager.CreateTask:input_type -> grpcserver.CreateTaskRequest
-	6, // 4: grpcserver.TaskManager.GetTasks:input_type -> grpcserver.GetTasksRequest
-	1, // 5: grpcserver.TaskManager.Register:output_type -> grpcserver.RegisterReply
-	3, // 6: grpcserver.TaskManager.Login:output_type -> grpcserver.LoginReply
-	5, // 7: grpcserver.TaskManager.CreateTask:output_type -> grpcserver.CreateTaskReply
-	7, // 8: grpcserver.TaskManager.GetTasks:output_type -> grpcserver.GetTasksReply
-	5, // [5:9] is the sub-list for method output_type
-	1, // [1:5] is the sub-list for method input_type
-	1, // [1:1] is the sub-list for extension type_name
-	1, // [1:1] is the sub-list for extension extendee
-	0, // [0:1] is the sub-list for field type_name
+	17, // 0: grpcserver.Task.completed_at:type_name -> google.protobuf.Timestamp
+	17, // 1: grpcserver.Task.created_at:type_name -> google.protobuf.Timestamp
+	17, // 2: grpcserver.Task.updated_at:type_name -> google.protobuf.Timestamp
+	0,  // 3: grpcserver.CreateTaskReply.task:type_name -> grpcserver.Task
+	16, // 4: grpcserver.GetTasksReply.tasks:type_name -> grpcserver.GetTasksReply.Task
+	0,  // 5: grpcserver.TaskReply.task:type_name -> grpcserver.Task
+	0,  // 6: grpcserver.ListTasksReply.tasks:type_name -> grpcserver.Task
+	1,  // 7: grpcserver.TaskManager.Register:input_type -> grpcserver.RegisterRequest
+	3,  // 8: grpcserver.TaskManager.Login:input_type -> grpcserver.LoginRequest
+	5,  // 9: grpcserver.TaskManager.CreateTask:input_type -> grpcserver.CreateTaskRequest
+	7,  // 10: grpcserver.TaskManager.GetTasks:input_type -> grpcserver.GetTasksRequest
+	9,  // 11: grpcserver.TaskManager.GetTask:input_type -> grpcserver.GetTaskRequest
+	11, // 12: grpcserver.TaskManager.ListTasks:input_type -> grpcserver.ListTasksRequest
+	13, // 13: grpcserver.TaskManager.UpdateTask:input_type -> grpcserver.UpdateTaskRequest
+	14, // 14: grpcserver.TaskManager.DeleteTask:input_type -> grpcserver.DeleteTaskRequest
+	2,  // 15: grpcserver.TaskManager.Register:output_type -> grpcserver.RegisterReply
+	4,  // 16: grpcserver.TaskManager.Login:output_type -> grpcserver.LoginReply
+	6,  // 17: grpcserver.TaskManager.CreateTask:output_type -> grpcserver.CreateTaskReply
+	8,  // 18: grpcserver.TaskManager.GetTasks:output_type -> grpcserver.GetTasksReply
+	10, // 19: grpcserver.TaskManager.GetTask:output_type -> grpcserver.TaskReply
+	12, // 20: grpcserver.TaskManager.ListTasks:output_type -> grpcserver.ListTasksReply
+	10, // 21: grpcserver.TaskManager.UpdateTask:output_type -> grpcserver.TaskReply
+	15, // 22: grpcserver.TaskManager.DeleteTask:output_type -> grpcserver.DeleteTaskReply
+	15, // [15:23] is the sub-list for method output_type
+	7,  // [7:15] is the sub-list for method input_type
+	7,  // [7:7] is the sub-list for extension type_name
+	7,  // [7:7] is the sub-list for extension extendee
+	0,  // [0:7] is the sub-list for field type_name
 }
 
 func init() { file_task_manager_proto_init() }
@@ -563,13 +1123,14 @@ func file_task_manager_proto_init() {
 	if File_task_manager_proto != nil {
 		return
 	}
+	file_task_manager_proto_msgTypes[13].OneofWrappers = []any{}
 	type x struct{}
 	out := protoimpl.TypeBuilder{
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_task_manager_proto_rawDesc), len(file_task_manager_proto_rawDesc)),
 			NumEnums:      0,
-			NumMessages:   9,
+			NumMessages:   17,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
@@ -23,6 +23,10 @@ const (
 	TaskManager_Login_FullMethodName      = "/grpcserver.TaskManager/Login"
 	TaskManager_CreateTask_FullMethodName = "/grpcserver.TaskManager/CreateTask"
 	TaskManager_GetTasks_FullMethodName   = "/grpcserver.TaskManager/GetTasks"
+	TaskManager_GetTask_FullMethodName    = "/grpcserver.TaskManager/GetTask"
+	TaskManager_ListTasks_FullMethodName  = "/grpcserver.TaskManager/ListTasks"
+	TaskManager_UpdateTask_FullMethodName = "/grpcserver.TaskManager/UpdateTask"
+	TaskManager_DeleteTask_FullMethodName = "/grpcserver.TaskManager/DeleteTask"
 )
 
 // TaskManagerClient is the client API for TaskManager service.
@@ -39,6 +43,14 @@ type TaskManagerClient interface {
 	CreateTask(ctx context.Context, in *CreateTaskRequest, opts ...grpc.CallOption) (*CreateTaskReply, error)
 	// GetTasks retrieves all tasks for the authenticated user.
 	GetTasks(ctx context.Context, in *GetTasksRequest, opts ...grpc.CallOption) (*GetTasksReply, error)
+	// GetTask retrieves a single task of the authenticated user by ID.
+	GetTask(ctx context.Context, in *GetTaskRequest, opts ...grpc.CallOption) (*TaskReply, error)
+	// ListTasks retrieves one page of tasks together with the total count.
+	ListTasks(ctx context.Context, in *ListTasksRequest, opts ...grpc.CallOption) (*ListTasksReply, error)
+	// UpdateTask changes the description and/or completion status of a task.
+	UpdateTask(ctx context.Context, in *UpdateTaskRequest, opts ...grpc.CallOption) (*TaskReply, error)
+	// DeleteTask removes a task of the authenticated user.
+	DeleteTask(ctx context.Context, in *DeleteTaskRequest, opts ...grpc.CallOption) (*DeleteTaskReply, error)
 }
 
 type taskManagerClient struct {
@@ -89,6 +101,46 @@ func (c *taskManagerClient) GetTasks(ctx context.Context, in *GetTasksRequest, o
 	return out, nil
 }
 
+func (c *taskManagerClient) GetTask(ctx context.Context, in *GetTaskRequest, opts ...grpc.CallOption) (*TaskReply, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(TaskReply)
+	err := c.cc.Invoke(ctx, TaskManager_GetTask_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *taskManagerClient) ListTasks(ctx context.Context, in *ListTasksRequest, opts ...grpc.CallOption) (*ListTasksReply, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListTasksReply)
+	err := c.cc.Invoke(ctx, TaskManager_ListTasks_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *taskManagerClient) UpdateTask(ctx context.Context, in *UpdateTaskRequest, opts ...grpc.CallOption) (*TaskReply, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(TaskReply)
+	err := c.cc.Invoke(ctx, TaskManager_UpdateTask_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *taskManagerClient) DeleteTask(ctx context.Context, in *DeleteTaskRequest, opts ...grpc.CallOption) (*DeleteTaskReply, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(DeleteTaskReply)
+	err := c.cc.Invoke(ctx, TaskManager_DeleteTask_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // TaskManagerServer is the server API for TaskManager service.
 // All implementations must embed UnimplementedTaskManagerServer
 // for forward compatibility.
@@ -103,6 +155,14 @@ type TaskManagerServer interface {
 	CreateTask(context.Context, *CreateTaskRequest) (*CreateTaskReply, error)
 	// GetTasks retrieves all tasks for the authenticated user.
 	GetTasks(context.Context, *GetTasksRequest) (*GetTasksReply, error)
+	// GetTask retrieves a single task of the authenticated user by ID.
+	GetTask(context.Context, *GetTaskRequest) (*TaskReply, error)
+	// ListTasks retrieves one page of tasks together with the total count.
+	ListTasks(context.Context, *ListTasksRequest) (*ListTasksReply, error)
+	// UpdateTask changes the description and/or completion status of a task.
+	UpdateTask(context.Context, *UpdateTaskRequest) (*TaskReply, error)
+	// DeleteTask removes a task of the authenticated user.
+	DeleteTask(context.Context, *DeleteTaskRequest) (*DeleteTaskReply, error)
 	mustEmbedUnimplementedTaskManagerServer()
 }
 
@@ -125,6 +185,18 @@ func (UnimplementedTaskManagerServer) CreateTask(context.Context, *CreateTaskReq
 func (UnimplementedTaskManagerServer) GetTasks(context.Context, *GetTasksRequest) (*GetTasksReply, error) {
 	return nil, status.Error(codes.Unimplemented, "method GetTasks not implemented")
 }
+func (UnimplementedTaskManagerServer) GetTask(context.Context, *GetTaskRequest) (*TaskReply, error) {
+	return nil, status.Error(codes.Unimplemented, "method GetTask not implemented")
+}
+func (UnimplementedTaskManagerServer) ListTasks(context.Context, *ListTasksRequest) (*ListTasksReply, error) {
+	return nil, status.Error(codes.Unimplemented, "method ListTasks not implemented")
+}
+func (UnimplementedTaskManagerServer) UpdateTask(context.Context, *UpdateTaskRequest) (*TaskReply, error) {
+	return nil, status.Error(codes.Unimplemented, "method UpdateTask not implemented")
+}
+func (UnimplementedTaskManagerServer) DeleteTask(context.Context, *DeleteTaskRequest) (*DeleteTaskReply, error) {
+	return nil, status.Error(codes.Unimplemented, "method DeleteTask not implemented")
+}
 func (UnimplementedTaskManagerServer) mustEmbedUnimplementedTaskManagerServer() {}
 func (UnimplementedTaskManagerServer) testEmbeddedByValue()                     {}
 
@@ -218,6 +290,78 @@ func _TaskManager_GetTasks_Handler(srv interface{}, ctx context.Context, dec fun
 	return interceptor(ctx, in, info, handler)
 }
 
+func _TaskManager_GetTask_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetTaskRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TaskManagerServer).GetTask(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TaskManager_GetTask_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TaskManagerServer).GetTask(ctx, req.(*GetTaskRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TaskManager_ListTasks_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListTasksRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TaskManagerServer).ListTasks(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TaskManager_ListTasks_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TaskManagerServer).ListTasks(ctx, req.(*ListTasksRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TaskManager_UpdateTask_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdateTaskRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TaskManagerServer).UpdateTask(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TaskManager_UpdateTask_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TaskManagerServer).UpdateTask(ctx, req.(*UpdateTaskRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TaskManager_DeleteTask_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteTaskRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TaskManagerServer).DeleteTask(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TaskManager_DeleteTask_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TaskManagerServer).DeleteTask(ctx, req.(*DeleteTaskRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // TaskManager_ServiceDesc is the grpc.ServiceDesc for TaskManager service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -241,6 +385,22 @@ var TaskManager_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "GetTasks",
 			Handler:    _TaskManager_GetTasks_Handler,
 		},
+		{
+			MethodName: "GetTask",
+			Handler:    _TaskManager_GetTask_Handler,
+		},
+		{
+			MethodName: "ListTasks",
+			Handler:    _TaskManager_ListTasks_Handler,
+		},
+		{
+			MethodName: "UpdateTask",
+			Handler:    _TaskManager_UpdateTask_Handler,
+		},
+		{
+			MethodName: "DeleteTask",
+			Handler:    _TaskManager_DeleteTask_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "task_manager.proto",
@@ -2,6 +2,7 @@ package storage
 
 import (
 	"database/sql"
+	"fmt"
 	"math"
 	"time"
 
@@ -10,11 +11,29 @@ import (
 
 // ConnectionConfig defines database connection pool settings.
 // All fields control connection lifecycle and resource limits.
+// BusyTimeout is how long a statement waits for another connection's write lock
+// before failing with ErrDatabaseLocked; zero fails immediately.
 type ConnectionConfig struct {
 	MaxOpenConns    int
 	MaxIdleConns    int
 	ConnMaxLifetime time.Duration
 	ConnMaxIdleTime time.Duration
+	BusyTimeout     time.Duration
+}
+
+// DefaultConnectionConfig returns pool settings that avoid lock contention in SQLite.
+// SQLite allows a single writer, so one open connection serializes writes inside the
+// process instead of letting them race for the lock; WAL mode (always enabled by
+// CreateConnection) still lets readers in other processes proceed during a write, and
+// the busy timeout covers writers in other processes.
+func DefaultConnectionConfig() ConnectionConfig {
+	return ConnectionConfig{
+		MaxOpenConns:    1,
+		MaxIdleConns:    1,
+		ConnMaxLifetime: time.Hour,
+		ConnMaxIdleTime: 15 * time.Minute,
+		BusyTimeout:     5 * time.Second,
+	}
 }
 
 // ConnectionManager holds a database connection and its configuration.
@@ -27,7 +46,7 @@ type ConnectionManager struct {
 // CreateConnection establishes a SQLite database connection with retry logic.
 // It applies connection pool settings and tests connectivity before returning.
 func CreateConnection(config *ConnectionConfig, path string) (*sql.DB, error) {
-	db, err := sql.Open("sqlite", path+"?_pragma=foreign_keys(1)&_pragma=journal_mode(WAL)&_busy_timeout=5000")
+	db, err := sql.Open("sqlite", connectionDSN(path, config))
 	if err != nil {
 		return nil, mapSQLiteError(err)
 	}
@@ -46,6 +65,13 @@ func CreateConnection(config *ConnectionConfig, path string) (*sql.DB, error) {
 	return db, nil
 }
 
+// connectionDSN builds the SQLite data source name. The pragmas are applied to every
+// connection the pool opens: foreign keys, WAL journaling and the busy timeout.
+func connectionDSN(path string, config *ConnectionConfig) string {
+	return fmt.Sprintf("%s?_pragma=foreign_keys(1)&_pragma=journal_mode(WAL)&_pragma=busy_timeout(%d)",
+		path, config.BusyTimeout.Milliseconds())
+}
+
 // Retry executes an operation with exponential backoff on failure.
 // It attempts the operation up to maxAttempts times with 1ms, 5s, 25s delays.
 func Retry[T any](operations func() (T, error), maxAttempts int) (T, error) {
@@ -3,8 +3,10 @@ package storage
 import (
 	"path/filepath"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestCreateConnection(t *testing.T) {
@@ -50,3 +52,21 @@ func TestCreateConnection(t *testing.T) {
 		assert.Error(t, err, "should failed with FOREIGN KEY constraint")
 	})
 }
+
+func TestCreateConnection_Pragmas(t *testing.T) {
+	cfg := DefaultConnectionConfig()
+	cfg.BusyTimeout = 2500 * time.Millisecond
+	db, err := CreateConnection(&cfg, filepath.Join(t.TempDir(), "test.db"))
+	require.NoError(t, err)
+	t.Cleanup(func() { db.Close() })
+
+	var busyTimeout int
+	require.NoError(t, db.QueryRow("PRAGMA busy_timeout").Scan(&busyTimeout))
+	assert.Equal(t, 2500, busyTimeout)
+
+	var journalMode string
+	require.NoError(t, db.QueryRow("PRAGMA journal_mode").Scan(&journalMode))
+	assert.Equal(t, "wal", journalMode)
+
+	assert.Equal(t, cfg.MaxOpenConns, db.Stats().MaxOpenConnections)
+}
@@ -8,16 +8,27 @@ import (
 	"myproject/domain"
 	"myproject/logger"
 	"os"
+	"strings"
 	"time"
 )
 
 // DatabaseStorage provides SQLite-based task persistence with automatic migrations.
 type DatabaseStorage struct {
-	db       *sql.DB
+	db *sql.DB
+	// conn runs the statements: db itself, or tx for the storage WithTx hands out
+	conn     dbConn
+	tx       *sql.Tx
 	migrator *Migrator
 	logger   *slog.Logger
 }
 
+// dbConn is satisfied by *sql.DB and *sql.Tx.
+type dbConn interface {
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+	QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row
+}
+
 // GetDatabasePath returns the database file path from TASK_DB_PATH env or "./tasks.db".
 func GetDatabasePath() string {
 	if dbPath := os.Getenv("TASK_DB_PATH"); dbPath != "" {
@@ -26,14 +37,13 @@ func GetDatabasePath() string {
 	return "./tasks.db"
 }
 
-// NewDatabaseStorage creates a new database storage with connection pooling and migrations.
+// NewDatabaseStorage creates a new database storage with the default connection pool and migrations.
 func NewDatabaseStorage(dbPath string, logger *slog.Logger) (*DatabaseStorage, error) {
-	config := ConnectionConfig{
-		MaxOpenConns:    1,
-		MaxIdleConns:    5,
-		ConnMaxLifetime: time.Hour,
-		ConnMaxIdleTime: 15 * time.Minute,
-	}
+	return NewDatabaseStorageWithConfig(dbPath, DefaultConnectionConfig(), logger)
+}
+
+// NewDatabaseStorageWithConfig creates a new database storage with the given connection pool settings and migrations.
+func NewDatabaseStorageWithConfig(dbPath string, config ConnectionConfig, logger *slog.Logger) (*DatabaseStorage, error) {
 	db, err := CreateConnection(&config, dbPath)
 	if err != nil {
 		return nil, mapSQLiteError(err)
@@ -41,6 +51,8 @@ func NewDatabaseStorage(dbPath string, logger *slog.Logger) (*DatabaseStorage, e
 
 	logger.Info("Database connection established",
 		slog.String("db_path", dbPath),
+		slog.Int("max_open_conns", config.MaxOpenConns),
+		slog.Duration("busy_timeout", config.BusyTimeout),
 	)
 
 	migrator := NewMigratorWithDefaults(db)
@@ -54,45 +66,144 @@ func NewDatabaseStorage(dbPath string, logger *slog.Logger) (*DatabaseStorage, e
 	// Create storage instance
 	storage := &DatabaseStorage{
 		db:       db,
+		conn:     db,
 		migrator: migrator,
 		logger:   logger,
 	}
 	return storage, nil
 }
 
-// CreateTask inserts a new task and returns the generated ID.
+const insertTaskQuery = "INSERT INTO tasks (description, done, completed_at, due_date, priority, user_id) VALUES (?, ?, ?, ?, ?, ?)"
+
+// updateTaskQuery changes a task only while its version is still the given one.
+const updateTaskQuery = "UPDATE tasks SET description = ?, done = ?, completed_at = ?, due_date = ?, priority = ?, version = version + 1, updated_at = CURRENT_TIMESTAMP WHERE id = ? AND user_id = ? AND version = ? AND deleted_at IS NULL"
+
+// sqliteTimestampLayout is the text format of CURRENT_TIMESTAMP, which created_at and updated_at are stored in.
+const sqliteTimestampLayout = "2006-01-02 15:04:05"
+
+// taskColumns is the column list every task query selects, in the order scanTask reads them.
+const taskColumns = "id, description, done, completed_at, due_date, priority, archived_at, version, created_at, updated_at"
+
+// CreateTask inserts a new task together with its tags and returns the generated ID.
 func (ds *DatabaseStorage) CreateTask(ctx context.Context, task domain.Task, userID int) (int, error) {
 	ds.logger.Debug("Creating task",
 		slog.String(logger.FieldOperation, "create_task"),
 		slog.Int(logger.FieldUserID, userID),
 		slog.String("description", task.Description),
 	)
-	result, err := ds.db.ExecContext(ctx,
-		"INSERT INTO tasks (description, done, user_id) VALUES (?, ?, ?)",
-		task.Description, task.Done, userID,
-	)
+	tx, err := ds.beginTx(ctx)
 	if err != nil {
-		ds.logger.Error("Failed to execute database insert",
+		ds.logger.Error("Failed to begin transaction",
 			slog.String(logger.FieldOperation, "create_task"),
 			slog.Int(logger.FieldUserID, userID),
 			slog.String(logger.FieldError, err.Error()),
 		)
 		return 0, mapSQLiteError(err)
 	}
+	defer ds.rollbackTx(tx)
 
-	id, err := result.LastInsertId()
+	id, err := insertTask(ctx, tx, task, userID)
 	if err != nil {
-		ds.logger.Error("Failed to return id generated by database",
+		ds.logger.Error("Failed to execute database insert",
 			slog.String(logger.FieldOperation, "create_task"),
 			slog.Int(logger.FieldUserID, userID),
 			slog.String(logger.FieldError, err.Error()),
 		)
 		return 0, mapSQLiteError(err)
 	}
+
+	if err := ds.commitTx(tx); err != nil {
+		ds.logger.Error("Failed to commit transaction",
+			slog.String(logger.FieldOperation, "create_task"),
+			slog.Int(logger.FieldUserID, userID),
+			slog.String(logger.FieldError, err.Error()),
+		)
+		return 0, mapSQLiteError(err)
+	}
+	return id, nil
+}
+
+// CreateTasks inserts all tasks in a single transaction and returns the generated IDs in order.
+// If any insert fails, none of the tasks are stored.
+func (ds *DatabaseStorage) CreateTasks(ctx context.Context, tasks []domain.Task, userID int) ([]int, error) {
+	ds.logger.Debug("Creating tasks",
+		slog.String(logger.FieldOperation, "create_tasks"),
+		slog.Int(logger.FieldUserID, userID),
+		slog.Int("count", len(tasks)),
+	)
+	tx, err := ds.beginTx(ctx)
+	if err != nil {
+		ds.logger.Error("Failed to begin transaction",
+			slog.String(logger.FieldOperation, "create_tasks"),
+			slog.Int(logger.FieldUserID, userID),
+			slog.String(logger.FieldError, err.Error()),
+		)
+		return nil, mapSQLiteError(err)
+	}
+	defer ds.rollbackTx(tx)
+
+	ids := make([]int, len(tasks))
+	for i, task := range tasks {
+		ids[i], err = insertTask(ctx, tx, task, userID)
+		if err != nil {
+			ds.logger.Error("Failed to execute database insert",
+				slog.String(logger.FieldOperation, "create_tasks"),
+				slog.Int(logger.FieldUserID, userID),
+				slog.Int("index", i),
+				slog.String(logger.FieldError, err.Error()),
+			)
+			return nil, mapSQLiteError(err)
+		}
+	}
+
+	if err := ds.commitTx(tx); err != nil {
+		ds.logger.Error("Failed to commit transaction",
+			slog.String(logger.FieldOperation, "create_tasks"),
+			slog.Int(logger.FieldUserID, userID),
+			slog.String(logger.FieldError, err.Error()),
+		)
+		return nil, mapSQLiteError(err)
+	}
+	return ids, nil
+}
+
+// insertTask inserts one task and attaches its tags within tx, returning the generated ID.
+func insertTask(ctx context.Context, tx *sql.Tx, task domain.Task, userID int) (int, error) {
+	result, err := tx.ExecContext(ctx, insertTaskQuery,
+		task.Description, task.Done, nullTime(task.CompletedAt), nullTime(task.DueDate), task.Priority, userID,
+	)
+	if err != nil {
+		return 0, err
+	}
+	id, err := result.LastInsertId()
+	if err != nil {
+		return 0, err
+	}
+	for _, tag := range task.Tags {
+		if err := attachTag(ctx, tx, int(id), tag, userID); err != nil {
+			return 0, err
+		}
+	}
 	return int(id), nil
 }
 
+// attachTag creates the user's tag if needed and links it to the task; an existing link is kept.
+func attachTag(ctx context.Context, tx *sql.Tx, taskID int, tag string, userID int) error {
+	if _, err := tx.ExecContext(ctx,
+		"INSERT INTO tags (user_id, name) VALUES (?, ?) ON CONFLICT(user_id, name) DO NOTHING", userID, tag,
+	); err != nil {
+		return err
+	}
+	_, err := tx.ExecContext(ctx,
+		"INSERT OR IGNORE INTO task_tags (task_id, tag_id) SELECT ?, id FROM tags WHERE user_id = ? AND name = ?",
+		taskID, userID, tag,
+	)
+	return err
+}
+
 // UpdateTask modifies a task's description and status, returns ErrTaskNotFound if not owned by user.
+// The row is only changed while its version still equals task.Version, and the version is incremented;
+// a task that changed in the meantime is reported as ErrVersionConflict.
 func (ds *DatabaseStorage) UpdateTask(ctx context.Context, task domain.Task, userID int) error {
 	ds.logger.Debug("Updating task",
 		slog.String(logger.FieldOperation, "update_task"),
@@ -100,9 +211,8 @@ func (ds *DatabaseStorage) UpdateTask(ctx context.Context, task domain.Task, use
 		slog.Int(logger.FieldUserID, userID),
 		slog.Bool("done", task.Done),
 	)
-	result, err := ds.db.ExecContext(ctx,
-		"UPDATE tasks SET description = ?, done = ?, updated_at = CURRENT_TIMESTAMP WHERE id = ? AND user_id = ?",
-		task.Description, task.Done, task.ID, userID,
+	result, err := ds.conn.ExecContext(ctx, updateTaskQuery,
+		task.Description, task.Done, nullTime(task.CompletedAt), nullTime(task.DueDate), task.Priority, task.ID, userID, task.Version,
 	)
 	if err != nil {
 		ds.logger.Error("Failed to execute database update",
@@ -133,26 +243,342 @@ func (ds *DatabaseStorage) UpdateTask(ctx context.Context, task domain.Task, use
 	)
 
 	if rowsAffected == 0 {
-		return domain.ErrTaskNotFound
+		return ds.updateMissError(ctx, ds.conn, task.ID, userID)
 	}
 
 	return nil
 }
 
-// DeleteTask removes a task by ID, returns ErrTaskNotFound if not owned by user.
-func (ds *DatabaseStorage) DeleteTask(ctx context.Context, id int, userID int) error {
-	ds.logger.Debug("Deleting task",
-		slog.String(logger.FieldOperation, "delete_task"),
+// UpdateTasks stores every task like UpdateTask within one transaction.
+// The first task that is missing or changed in the meantime rolls back the whole batch
+// and is reported as a *domain.BatchItemError.
+func (ds *DatabaseStorage) UpdateTasks(ctx context.Context, tasks []domain.Task, userID int) error {
+	ds.logger.Debug("Updating tasks",
+		slog.String(logger.FieldOperation, "update_tasks"),
+		slog.Int(logger.FieldUserID, userID),
+		slog.Int("count", len(tasks)),
+	)
+	tx, err := ds.beginTx(ctx)
+	if err != nil {
+		ds.logger.Error("Failed to begin transaction",
+			slog.String(logger.FieldOperation, "update_tasks"),
+			slog.Int(logger.FieldUserID, userID),
+			slog.String(logger.FieldError, err.Error()),
+		)
+		return mapSQLiteError(err)
+	}
+	defer ds.rollbackTx(tx)
+
+	for i, task := range tasks {
+		result, err := tx.ExecContext(ctx, updateTaskQuery,
+			task.Description, task.Done, nullTime(task.CompletedAt), nullTime(task.DueDate), task.Priority, task.ID, userID, task.Version,
+		)
+		var rowsAffected int64
+		if err == nil {
+			rowsAffected, err = result.RowsAffected()
+		}
+		if err != nil {
+			ds.logger.Error("Failed to execute database update",
+				slog.String(logger.FieldOperation, "update_tasks"),
+				slog.Int(logger.FieldTaskID, task.ID),
+				slog.Int(logger.FieldUserID, userID),
+				slog.String(logger.FieldError, err.Error()),
+			)
+			return mapSQLiteError(err)
+		}
+		if rowsAffected == 0 {
+			return &domain.BatchItemError{Index: i, Err: ds.updateMissError(ctx, tx, task.ID, userID)}
+		}
+	}
+
+	if err := ds.commitTx(tx); err != nil {
+		ds.logger.Error("Failed to commit transaction",
+			slog.String(logger.FieldOperation, "update_tasks"),
+			slog.Int(logger.FieldUserID, userID),
+			slog.String(logger.FieldError, err.Error()),
+		)
+		return mapSQLiteError(err)
+	}
+	return nil
+}
+
+// updateMissError tells why a versioned update changed no row: ErrVersionConflict when the
+// user's task still exists, so only its version differed, and ErrTaskNotFound otherwise.
+func (ds *DatabaseStorage) updateMissError(ctx context.Context, q dbConn, id, userID int) error {
+	var exists bool
+	err := q.QueryRowContext(ctx,
+		"SELECT EXISTS(SELECT 1 FROM tasks WHERE id = ? AND user_id = ? AND deleted_at IS NULL)", id, userID,
+	).Scan(&exists)
+	if err != nil {
+		ds.logger.Error("Failed to query database select from tasks",
+			slog.String(logger.FieldOperation, "update_task"),
+			slog.Int(logger.FieldTaskID, id),
+			slog.Int(logger.FieldUserID, userID),
+			slog.String(logger.FieldError, err.Error()),
+		)
+		return mapSQLiteError(err)
+	}
+	if exists {
+		return domain.ErrVersionConflict
+	}
+	return domain.ErrTaskNotFound
+}
+
+// ToggleTaskDone flips a task's status with a single UPDATE ... RETURNING, so no other write can
+// slip in between reading and changing it. Returns ErrTaskNotFound if not owned by user.
+func (ds *DatabaseStorage) ToggleTaskDone(ctx context.Context, id int, userID int) (task domain.Task, err error) {
+	ds.logger.Debug("Toggling task status",
+		slog.String(logger.FieldOperation, "toggle_task_done"),
 		slog.Int(logger.FieldTaskID, id),
 		slog.Int(logger.FieldUserID, userID),
 	)
-	result, err := ds.db.ExecContext(ctx,
+	// SET expressions see the old row, so completed_at is set only when an open task becomes done.
+	now := time.Now().UTC()
+	err = scanTask(ds.conn.QueryRowContext(ctx,
+		"UPDATE tasks SET done = NOT done, completed_at = CASE WHEN done THEN NULL ELSE ? END, version = version + 1, updated_at = CURRENT_TIMESTAMP WHERE id = ? AND user_id = ? AND deleted_at IS NULL RETURNING "+taskColumns,
+		nullTime(&now), id, userID,
+	), &task)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return domain.Task{}, domain.ErrTaskNotFound
+		}
+		ds.logger.Error("Failed to execute database update",
+			slog.String(logger.FieldOperation, "toggle_task_done"),
+			slog.Int(logger.FieldTaskID, id),
+			slog.Int(logger.FieldUserID, userID),
+			slog.String(logger.FieldError, err.Error()),
+		)
+		return domain.Task{}, mapSQLiteError(err)
+	}
+
+	tasks := []domain.Task{task}
+	if err := ds.loadTags(ctx, tasks, "toggle_task_done", userID); err != nil {
+		return domain.Task{}, err
+	}
+	return tasks[0], nil
+}
+
+// AddTag attaches a tag to a task, returns ErrTaskNotFound if not owned by user.
+// The tag is created in the user's namespace on first use; tagging a task twice changes nothing.
+func (ds *DatabaseStorage) AddTag(ctx context.Context, taskID int, tag string, userID int) error {
+	return ds.changeTaskTags(ctx, "add_tag", taskID, userID, func(tx *sql.Tx) error {
+		return attachTag(ctx, tx, taskID, tag, userID)
+	})
+}
+
+// RemoveTag detaches a tag from a task, returns ErrTaskNotFound if not owned by user.
+// Removing a tag the task does not carry changes nothing.
+func (ds *DatabaseStorage) RemoveTag(ctx context.Context, taskID int, tag string, userID int) error {
+	return ds.changeTaskTags(ctx, "remove_tag", taskID, userID, func(tx *sql.Tx) error {
+		_, err := tx.ExecContext(ctx,
+			"DELETE FROM task_tags WHERE task_id = ? AND tag_id IN (SELECT id FROM tags WHERE user_id = ? AND name = ?)",
+			taskID, userID, tag,
+		)
+		return err
+	})
+}
+
+// changeTaskTags touches updated_at and version of the user's task and runs change in the same transaction,
+// returning ErrTaskNotFound when the user owns no such non-deleted task.
+func (ds *DatabaseStorage) changeTaskTags(ctx context.Context, operation string, taskID, userID int, change func(tx *sql.Tx) error) error {
+	ds.logger.Debug("Changing task tags",
+		slog.String(logger.FieldOperation, operation),
+		slog.Int(logger.FieldTaskID, taskID),
+		slog.Int(logger.FieldUserID, userID),
+	)
+	tx, err := ds.beginTx(ctx)
+	if err != nil {
+		ds.logger.Error("Failed to begin transaction",
+			slog.String(logger.FieldOperation, operation),
+			slog.Int(logger.FieldTaskID, taskID),
+			slog.Int(logger.FieldUserID, userID),
+			slog.String(logger.FieldError, err.Error()),
+		)
+		return mapSQLiteError(err)
+	}
+	defer ds.rollbackTx(tx)
+
+	result, err := tx.ExecContext(ctx,
+		"UPDATE tasks SET version = version + 1, updated_at = CURRENT_TIMESTAMP WHERE id = ? AND user_id = ? AND deleted_at IS NULL", taskID, userID,
+	)
+	var rowsAffected int64
+	if err == nil {
+		rowsAffected, err = result.RowsAffected()
+	}
+	if err == nil && rowsAffected == 0 {
+		return domain.ErrTaskNotFound
+	}
+	if err == nil {
+		err = change(tx)
+	}
+	if err == nil {
+		err = ds.commitTx(tx)
+	}
+	if err != nil {
+		ds.logger.Error("Failed to change task tags",
+			slog.String(logger.FieldOperation, operation),
+			slog.Int(logger.FieldTaskID, taskID),
+			slog.Int(logger.FieldUserID, userID),
+			slog.String(logger.FieldError, err.Error()),
+		)
+		return mapSQLiteError(err)
+	}
+	return nil
+}
+
+// UpdateAllTaskStatus marks all of the user's tasks done or open in a single statement.
+// Only tasks whose status changes are updated, so completion times of already done tasks are kept.
+// Returns the number of changed tasks.
+func (ds *DatabaseStorage) UpdateAllTaskStatus(ctx context.Context, userID int, done bool) (int, error) {
+	ds.logger.Debug("Updating status of all tasks",
+		slog.String(logger.FieldOperation, "update_all_task_status"),
+		slog.Int(logger.FieldUserID, userID),
+		slog.Bool("done", done),
+	)
+	var completedAt *time.Time
+	if done {
+		now := time.Now().UTC()
+		completedAt = &now
+	}
+	result, err := ds.conn.ExecContext(ctx,
+		"UPDATE tasks SET done = ?, completed_at = ?, version = version + 1, updated_at = CURRENT_TIMESTAMP WHERE user_id = ? AND done <> ? AND deleted_at IS NULL",
+		done, nullTime(completedAt), userID, done,
+	)
+	var rowsAffected int64
+	if err == nil {
+		rowsAffected, err = result.RowsAffected()
+	}
+	if err != nil {
+		ds.logger.Error("Failed to execute database update",
+			slog.String(logger.FieldOperation, "update_all_task_status"),
+			slog.Int(logger.FieldUserID, userID),
+			slog.String(logger.FieldError, err.Error()),
+		)
+		return 0, mapSQLiteError(err)
+	}
+	return int(rowsAffected), nil
+}
+
+// DeleteTask soft-deletes a task by setting deleted_at, returns ErrTaskNotFound if not owned by user.
+// Deleted tasks are hidden from every read until restored with RestoreTask.
+func (ds *DatabaseStorage) DeleteTask(ctx context.Context, id int, userID int) error {
+	return ds.execTaskChange(ctx, "delete_task", id, userID,
+		"UPDATE tasks SET deleted_at = CURRENT_TIMESTAMP WHERE id = ? AND user_id = ? AND deleted_at IS NULL",
+	)
+}
+
+// DeleteTasks soft-deletes the user's tasks with the given IDs in one transaction.
+// Missing, already deleted and other users' tasks are skipped; the number of deleted tasks is returned.
+func (ds *DatabaseStorage) DeleteTasks(ctx context.Context, ids []int, userID int) (int, error) {
+	ds.logger.Debug("Deleting tasks",
+		slog.String(logger.FieldOperation, "delete_tasks"),
+		slog.Int(logger.FieldUserID, userID),
+		slog.Int("count", len(ids)),
+	)
+	tx, err := ds.beginTx(ctx)
+	if err != nil {
+		ds.logger.Error("Failed to begin transaction",
+			slog.String(logger.FieldOperation, "delete_tasks"),
+			slog.Int(logger.FieldUserID, userID),
+			slog.String(logger.FieldError, err.Error()),
+		)
+		return 0, mapSQLiteError(err)
+	}
+	defer ds.rollbackTx(tx)
+
+	deleted := 0
+	for _, id := range ids {
+		result, err := tx.ExecContext(ctx,
+			"UPDATE tasks SET deleted_at = CURRENT_TIMESTAMP WHERE id = ? AND user_id = ? AND deleted_at IS NULL", id, userID,
+		)
+		var rowsAffected int64
+		if err == nil {
+			rowsAffected, err = result.RowsAffected()
+		}
+		if err != nil {
+			ds.logger.Error("Failed to execute database update",
+				slog.String(logger.FieldOperation, "delete_tasks"),
+				slog.Int(logger.FieldTaskID, id),
+				slog.Int(logger.FieldUserID, userID),
+				slog.String(logger.FieldError, err.Error()),
+			)
+			return 0, mapSQLiteError(err)
+		}
+		deleted += int(rowsAffected)
+	}
+
+	if err := ds.commitTx(tx); err != nil {
+		ds.logger.Error("Failed to commit transaction",
+			slog.String(logger.FieldOperation, "delete_tasks"),
+			slog.Int(logger.FieldUserID, userID),
+			slog.String(logger.FieldError, err.Error()),
+		)
+		return 0, mapSQLiteError(err)
+	}
+	return deleted, nil
+}
+
+// RestoreTask brings back a soft-deleted task, returns ErrTaskNotFound if the user owns no such deleted task.
+func (ds *DatabaseStorage) RestoreTask(ctx context.Context, id int, userID int) error {
+	return ds.execTaskChange(ctx, "restore_task", id, userID,
+		"UPDATE tasks SET deleted_at = NULL, version = version + 1, updated_at = CURRENT_TIMESTAMP WHERE id = ? AND user_id = ? AND deleted_at IS NOT NULL",
+	)
+}
+
+// ArchiveTask hides a task from default task lists by setting archived_at, returns ErrTaskNotFound if not owned by user.
+// An already archived task keeps its original archive time.
+func (ds *DatabaseStorage) ArchiveTask(ctx context.Context, id int, userID int) error {
+	return ds.execTaskChange(ctx, "archive_task", id, userID,
+		"UPDATE tasks SET archived_at = COALESCE(archived_at, CURRENT_TIMESTAMP), version = version + 1, updated_at = CURRENT_TIMESTAMP WHERE id = ? AND user_id = ? AND deleted_at IS NULL",
+	)
+}
+
+// ArchiveCompleted archives all of the user's done tasks in a single statement and returns how many were archived.
+// Tasks that are already archived are not counted again.
+func (ds *DatabaseStorage) ArchiveCompleted(ctx context.Context, userID int) (int, error) {
+	ds.logger.Debug("Archiving completed tasks",
+		slog.String(logger.FieldOperation, "archive_completed"),
+		slog.Int(logger.FieldUserID, userID),
+	)
+	result, err := ds.conn.ExecContext(ctx,
+		"UPDATE tasks SET archived_at = CURRENT_TIMESTAMP, version = version + 1, updated_at = CURRENT_TIMESTAMP WHERE user_id = ? AND done = 1 AND archived_at IS NULL AND deleted_at IS NULL",
+		userID,
+	)
+	var rowsAffected int64
+	if err == nil {
+		rowsAffected, err = result.RowsAffected()
+	}
+	if err != nil {
+		ds.logger.Error("Failed to execute database update",
+			slog.String(logger.FieldOperation, "archive_completed"),
+			slog.Int(logger.FieldUserID, userID),
+			slog.String(logger.FieldError, err.Error()),
+		)
+		return 0, mapSQLiteError(err)
+	}
+	return int(rowsAffected), nil
+}
+
+// PurgeTask permanently removes a task by ID, deleted or not, returns ErrTaskNotFound if not owned by user.
+// Its tag links are removed with it by the task_tags foreign key; soft deletes keep them for RestoreTask.
+func (ds *DatabaseStorage) PurgeTask(ctx context.Context, id int, userID int) error {
+	return ds.execTaskChange(ctx, "purge_task", id, userID,
 		"DELETE FROM tasks WHERE id = ? AND user_id = ?",
-		id, userID,
 	)
+}
+
+// execTaskChange runs a statement taking (id, user_id) that changes a single task,
+// returning ErrTaskNotFound when no row was affected.
+func (ds *DatabaseStorage) execTaskChange(ctx context.Context, operation string, id, userID int, query string) error {
+	ds.logger.Debug("Changing task",
+		slog.String(logger.FieldOperation, operation),
+		slog.Int(logger.FieldTaskID, id),
+		slog.Int(logger.FieldUserID, userID),
+	)
+	result, err := ds.conn.ExecContext(ctx, query, id, userID)
 	if err != nil {
-		ds.logger.Error("Failed to execute database delete",
-			slog.String(logger.FieldOperation, "delete_task"),
+		ds.logger.Error("Failed to execute database statement",
+			slog.String(logger.FieldOperation, operation),
 			slog.Int(logger.FieldTaskID, id),
 			slog.Int(logger.FieldUserID, userID),
 			slog.String(logger.FieldError, err.Error()),
@@ -163,7 +589,7 @@ func (ds *DatabaseStorage) DeleteTask(ctx context.Context, id int, userID int) e
 	rowsAffected, err := result.RowsAffected()
 	if err != nil {
 		ds.logger.Error("Failed to affect database row",
-			slog.String(logger.FieldOperation, "delete_task"),
+			slog.String(logger.FieldOperation, operation),
 			slog.Int(logger.FieldTaskID, id),
 			slog.Int(logger.FieldUserID, userID),
 			slog.String(logger.FieldError, err.Error()),
@@ -171,7 +597,7 @@ func (ds *DatabaseStorage) DeleteTask(ctx context.Context, id int, userID int) e
 		return mapSQLiteError(err)
 	}
 	ds.logger.Debug("Database operation completed: affected rows",
-		slog.String(logger.FieldOperation, "delete_task"),
+		slog.String(logger.FieldOperation, operation),
 		slog.Int(logger.FieldTaskID, id),
 		slog.Int(logger.FieldUserID, userID),
 		slog.Int64("rows_affected", rowsAffected),
@@ -184,6 +610,40 @@ func (ds *DatabaseStorage) DeleteTask(ctx context.Context, id int, userID int) e
 	return nil
 }
 
+// TaskExists reports whether a non-deleted task with the ID exists, regardless of its owner.
+func (ds *DatabaseStorage) TaskExists(ctx context.Context, id int) (bool, error) {
+	var exists bool
+	err := ds.conn.QueryRowContext(ctx,
+		"SELECT EXISTS(SELECT 1 FROM tasks WHERE id = ? AND deleted_at IS NULL)", id,
+	).Scan(&exists)
+	if err != nil {
+		ds.logger.Error("Failed to query database select from tasks",
+			slog.String(logger.FieldOperation, "task_exists"),
+			slog.Int(logger.FieldTaskID, id),
+			slog.String(logger.FieldError, err.Error()),
+		)
+		return false, mapSQLiteError(err)
+	}
+	return exists, nil
+}
+
+// TaskExistsByDescription reports whether the user has a non-deleted task with exactly this description.
+func (ds *DatabaseStorage) TaskExistsByDescription(ctx context.Context, userID int, description string) (bool, error) {
+	var exists bool
+	err := ds.conn.QueryRowContext(ctx,
+		"SELECT EXISTS(SELECT 1 FROM tasks WHERE user_id = ? AND description = ? AND deleted_at IS NULL)", userID, description,
+	).Scan(&exists)
+	if err != nil {
+		ds.logger.Error("Failed to query database select from tasks",
+			slog.String(logger.FieldOperation, "task_exists_by_description"),
+			slog.Int(logger.FieldUserID, userID),
+			slog.String(logger.FieldError, err.Error()),
+		)
+		return false, mapSQLiteError(err)
+	}
+	return exists, nil
+}
+
 // GetTaskByID retrieves a task by ID, returns ErrTaskNotFound if not owned by user.
 func (ds *DatabaseStorage) GetTaskByID(ctx context.Context, id int, userID int) (task domain.Task, err error) {
 	ds.logger.Debug("Fetching task",
@@ -191,10 +651,10 @@ func (ds *DatabaseStorage) GetTaskByID(ctx context.Context, id int, userID int)
 		slog.Int(logger.FieldTaskID, id),
 		slog.Int(logger.FieldUserID, userID),
 	)
-	err = ds.db.QueryRowContext(ctx,
-		"SELECT id, description, done FROM tasks WHERE id = ? AND user_id = ?",
+	err = scanTask(ds.conn.QueryRowContext(ctx,
+		"SELECT "+taskColumns+" FROM tasks WHERE id = ? AND user_id = ? AND deleted_at IS NULL",
 		id, userID,
-	).Scan(&task.ID, &task.Description, &task.Done)
+	), &task)
 
 	if err != nil {
 		if err == sql.ErrNoRows {
@@ -209,17 +669,30 @@ func (ds *DatabaseStorage) GetTaskByID(ctx context.Context, id int, userID int)
 		return domain.Task{}, mapSQLiteError(err)
 	}
 
-	return task, nil
+	tasks := []domain.Task{task}
+	if err := ds.loadTags(ctx, tasks, "get_task_by_id", userID); err != nil {
+		return domain.Task{}, err
+	}
+	return tasks[0], nil
 }
 
-// LoadTasks retrieves all tasks for a user ordered by ID.
-func (ds *DatabaseStorage) LoadTasks(ctx context.Context, userID int) ([]domain.Task, error) {
+// LoadTasks retrieves a page of the user's tasks matching opts.Filter, open tasks first and newest first.
+// A zero opts.Limit loads all tasks starting at opts.Offset.
+func (ds *DatabaseStorage) LoadTasks(ctx context.Context, userID int, opts domain.ListOptions) ([]domain.Task, error) {
 	ds.logger.Debug("Loading tasks",
 		slog.String(logger.FieldOperation, "load_task"),
 		slog.Int(logger.FieldUserID, userID),
+		slog.Int("limit", opts.Limit),
+		slog.Int("offset", opts.Offset),
 	)
-	query := "SELECT id, description, done FROM tasks WHERE user_id = ? ORDER BY done ASC, created_at DESC"
-	rows, err := ds.db.QueryContext(ctx, query, userID)
+	limit := opts.Limit
+	if limit <= 0 {
+		limit = -1 // SQLite: no upper bound
+	}
+	where, args := filterClause(opts.Filter)
+	query := "SELECT " + taskColumns + " FROM tasks WHERE user_id = ? AND deleted_at IS NULL" + where + " ORDER BY " + orderByClause(opts.Sort) + " LIMIT ? OFFSET ?"
+	args = append([]any{userID}, args...)
+	rows, err := ds.conn.QueryContext(ctx, query, append(args, limit, opts.Offset)...)
 	if err != nil {
 		ds.logger.Error("Failed to query database select",
 			slog.String(logger.FieldOperation, "load_task"),
@@ -230,12 +703,117 @@ func (ds *DatabaseStorage) LoadTasks(ctx context.Context, userID int) ([]domain.
 	}
 
 	defer rows.Close()
+	return ds.scanTasksWithTags(ctx, rows, "load_task", userID)
+}
+
+// filterClause turns a filter into extra WHERE conditions and their arguments.
+// created_at holds SQLite's CURRENT_TIMESTAMP text, so the bounds are formatted the same way to compare correctly.
+func filterClause(filter domain.TaskFilter) (string, []any) {
+	var where string
+	var args []any
+	if !filter.IncludeArchived {
+		where += " AND archived_at IS NULL"
+	}
+	if filter.CreatedAfter != nil {
+		where += " AND created_at >= ?"
+		args = append(args, filter.CreatedAfter.UTC().Format(sqliteTimestampLayout))
+	}
+	if filter.CreatedBefore != nil {
+		where += " AND created_at <= ?"
+		args = append(args, filter.CreatedBefore.UTC().Format(sqliteTimestampLayout))
+	}
+	if filter.OverdueAt != nil {
+		where += " AND done = FALSE AND due_date < ?"
+		args = append(args, filter.OverdueAt.UTC().Truncate(time.Second))
+	}
+	if filter.Tag != "" {
+		where += " AND id IN (SELECT task_tags.task_id FROM task_tags JOIN tags ON tags.id = task_tags.tag_id WHERE tags.name = ?)"
+		args = append(args, filter.Tag)
+	}
+	return where, args
+}
+
+// orderByClause maps a sort onto a fixed ORDER BY clause, so user input never reaches the SQL text.
+// Every clause ends with id to keep pages stable when the sorted column has equal values.
+func orderByClause(sort domain.TaskSort) string {
+	direction := "ASC"
+	if sort.Descending {
+		direction = "DESC"
+	}
+	switch sort.Field {
+	case domain.SortByID:
+		return "id " + direction
+	case domain.SortByCreated:
+		return "created_at " + direction + ", id " + direction
+	case domain.SortByUpdated:
+		return "updated_at " + direction + ", id " + direction
+	case domain.SortByDone:
+		return "done " + direction + ", id ASC"
+	case domain.SortByPriority:
+		return "priority " + direction + ", id ASC"
+	case domain.SortByDue:
+		return "due_date IS NULL, due_date " + direction + ", id ASC"
+	default:
+		return "done ASC, created_at DESC"
+	}
+}
+
+// SearchTasks returns the user's tasks whose description contains query, ignoring case, leaving out
+// archived ones like the default list does. LIKE wildcards in query are matched literally.
+func (ds *DatabaseStorage) SearchTasks(ctx context.Context, userID int, query string) ([]domain.Task, error) {
+	ds.logger.Debug("Searching tasks",
+		slog.String(logger.FieldOperation, "search_tasks"),
+		slog.Int(logger.FieldUserID, userID),
+	)
+	rows, err := ds.conn.QueryContext(ctx,
+		"SELECT "+taskColumns+` FROM tasks
+		WHERE user_id = ? AND deleted_at IS NULL AND archived_at IS NULL AND LOWER(description) LIKE '%' || LOWER(?) || '%' ESCAPE '\'
+		ORDER BY done ASC, created_at DESC`,
+		userID, likeEscaper.Replace(query),
+	)
+	if err != nil {
+		ds.logger.Error("Failed to query database select",
+			slog.String(logger.FieldOperation, "search_tasks"),
+			slog.Int(logger.FieldUserID, userID),
+			slog.String(logger.FieldError, err.Error()),
+		)
+		return nil, mapSQLiteError(err)
+	}
+
+	defer rows.Close()
+	return ds.scanTasksWithTags(ctx, rows, "search_tasks", userID)
+}
+
+// likeEscaper escapes the LIKE wildcards so user input is matched as plain text.
+var likeEscaper = strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`)
+
+// rowScanner is implemented by both *sql.Row and *sql.Rows.
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+// scanTask reads one row selected with taskColumns into task.
+func scanTask(row rowScanner, task *domain.Task) error {
+	var completedAt, dueDate, archivedAt sql.NullTime
+	if err := row.Scan(&task.ID, &task.Description, &task.Done, &completedAt, &dueDate, &task.Priority, &archivedAt, &task.Version, &task.CreatedAt, &task.UpdatedAt); err != nil {
+		return err
+	}
+	task.CompletedAt = timePtr(completedAt)
+	task.DueDate = timePtr(dueDate)
+	task.ArchivedAt = timePtr(archivedAt)
+	task.CreatedAt = task.CreatedAt.UTC()
+	task.UpdatedAt = task.UpdatedAt.UTC()
+	return nil
+}
+
+// scanTasks reads every task row selected with taskColumns.
+func (ds *DatabaseStorage) scanTasks(rows *sql.Rows, operation string, userID int) ([]domain.Task, error) {
 	tasks := make([]domain.Task, 0)
 	for rows.Next() {
 		var task domain.Task
-		if err := rows.Scan(&task.ID, &task.Description, &task.Done); err != nil {
+		if err := scanTask(rows, &task); err != nil {
 			ds.logger.Error("Failed to scan database rows",
-				slog.String(logger.FieldOperation, "load_task"),
+				slog.String(logger.FieldOperation, operation),
 				slog.Int(logger.FieldUserID, userID),
 				slog.String(logger.FieldError, err.Error()),
 			)
@@ -244,9 +822,9 @@ func (ds *DatabaseStorage) LoadTasks(ctx context.Context, userID int) ([]domain.
 		tasks = append(tasks, task)
 	}
 
-	if err = rows.Err(); err != nil {
+	if err := rows.Err(); err != nil {
 		ds.logger.Error("Failed to query or scan database rows",
-			slog.String(logger.FieldOperation, "load_task"),
+			slog.String(logger.FieldOperation, operation),
 			slog.Int(logger.FieldUserID, userID),
 			slog.String(logger.FieldError, err.Error()),
 		)
@@ -256,6 +834,160 @@ func (ds *DatabaseStorage) LoadTasks(ctx context.Context, userID int) ([]domain.
 	return tasks, nil
 }
 
+// scanTasksWithTags reads every task row like scanTasks and then fills in the tags.
+// The rows are fully read first, so the tag query does not wait for the connection they hold.
+func (ds *DatabaseStorage) scanTasksWithTags(ctx context.Context, rows *sql.Rows, operation string, userID int) ([]domain.Task, error) {
+	tasks, err := ds.scanTasks(rows, operation, userID)
+	if err != nil {
+		return nil, err
+	}
+	if err := ds.loadTags(ctx, tasks, operation, userID); err != nil {
+		return nil, err
+	}
+	return tasks, nil
+}
+
+// loadTags sets the Tags of every task, sorted by name. It reads all of the user's tag links
+// at once, which keeps the query independent of the number of tasks.
+func (ds *DatabaseStorage) loadTags(ctx context.Context, tasks []domain.Task, operation string, userID int) error {
+	if len(tasks) == 0 {
+		return nil
+	}
+	rows, err := ds.conn.QueryContext(ctx,
+		`SELECT task_tags.task_id, tags.name FROM task_tags JOIN tags ON tags.id = task_tags.tag_id
+		WHERE tags.user_id = ? ORDER BY tags.name`,
+		userID,
+	)
+	if err != nil {
+		ds.logger.Error("Failed to query database select from tags",
+			slog.String(logger.FieldOperation, operation),
+			slog.Int(logger.FieldUserID, userID),
+			slog.String(logger.FieldError, err.Error()),
+		)
+		return mapSQLiteError(err)
+	}
+	defer rows.Close()
+
+	tags := make(map[int][]string)
+	for rows.Next() {
+		var taskID int
+		var name string
+		if err := rows.Scan(&taskID, &name); err != nil {
+			ds.logger.Error("Failed to scan database rows",
+				slog.String(logger.FieldOperation, operation),
+				slog.Int(logger.FieldUserID, userID),
+				slog.String(logger.FieldError, err.Error()),
+			)
+			return mapSQLiteError(err)
+		}
+		tags[taskID] = append(tags[taskID], name)
+	}
+	if err := rows.Err(); err != nil {
+		ds.logger.Error("Failed to query or scan database rows",
+			slog.String(logger.FieldOperation, operation),
+			slog.Int(logger.FieldUserID, userID),
+			slog.String(logger.FieldError, err.Error()),
+		)
+		return mapSQLiteError(err)
+	}
+
+	for i := range tasks {
+		tasks[i].Tags = tags[tasks[i].ID]
+	}
+	return nil
+}
+
+// CountTasks returns the number of tasks owned by a user that match the filter, excluding deleted ones
+// and, unless the filter includes them, archived ones.
+func (ds *DatabaseStorage) CountTasks(ctx context.Context, userID int, filter domain.TaskFilter) (int, error) {
+	var count int
+	where, args := filterClause(filter)
+	err := ds.conn.QueryRowContext(ctx, "SELECT COUNT(*) FROM tasks WHERE user_id = ? AND deleted_at IS NULL"+where, append([]any{userID}, args...)...).Scan(&count)
+	if err != nil {
+		ds.logger.Error("Failed to count tasks",
+			slog.String(logger.FieldOperation, "count_tasks"),
+			slog.Int(logger.FieldUserID, userID),
+			slog.String(logger.FieldError, err.Error()),
+		)
+		return 0, mapSQLiteError(err)
+	}
+	return count, nil
+}
+
+// TaskStats returns how many of a user's tasks exist, are done and are pending, excluding deleted and archived ones.
+func (ds *DatabaseStorage) TaskStats(ctx context.Context, userID int) (domain.Stats, error) {
+	var stats domain.Stats
+	err := ds.conn.QueryRowContext(ctx,
+		"SELECT COUNT(*), COALESCE(SUM(done), 0) FROM tasks WHERE user_id = ? AND deleted_at IS NULL AND archived_at IS NULL", userID,
+	).Scan(&stats.Total, &stats.Done)
+	if err != nil {
+		ds.logger.Error("Failed to count task stats",
+			slog.String(logger.FieldOperation, "task_stats"),
+			slog.Int(logger.FieldUserID, userID),
+			slog.String(logger.FieldError, err.Error()),
+		)
+		return domain.Stats{}, mapSQLiteError(err)
+	}
+	stats.Pending = stats.Total - stats.Done
+	return stats, nil
+}
+
+// WithTx runs fn with a storage bound to a new database transaction and commits it when fn returns nil.
+// Any error from fn, or a panic, rolls back every statement fn ran. Called on a storage that is
+// already bound, fn joins the running transaction.
+func (ds *DatabaseStorage) WithTx(ctx context.Context, fn func(tx domain.TxStorage) error) error {
+	if ds.tx != nil {
+		return fn(ds)
+	}
+	tx, err := ds.db.BeginTx(ctx, nil)
+	if err != nil {
+		ds.logger.Error("Failed to begin transaction",
+			slog.String(logger.FieldOperation, "with_tx"),
+			slog.String(logger.FieldError, err.Error()),
+		)
+		return mapSQLiteError(err)
+	}
+	defer tx.Rollback()
+
+	bound := *ds
+	bound.conn = tx
+	bound.tx = tx
+	if err := fn(&bound); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		ds.logger.Error("Failed to commit transaction",
+			slog.String(logger.FieldOperation, "with_tx"),
+			slog.String(logger.FieldError, err.Error()),
+		)
+		return mapSQLiteError(err)
+	}
+	return nil
+}
+
+// beginTx starts a transaction for a multi-statement operation, or joins the one the storage is bound to.
+// A joined transaction is committed or rolled back by WithTx, so commitTx and rollbackTx leave it alone.
+func (ds *DatabaseStorage) beginTx(ctx context.Context) (*sql.Tx, error) {
+	if ds.tx != nil {
+		return ds.tx, nil
+	}
+	return ds.db.BeginTx(ctx, nil)
+}
+
+func (ds *DatabaseStorage) commitTx(tx *sql.Tx) error {
+	if tx == ds.tx {
+		return nil
+	}
+	return tx.Commit()
+}
+
+func (ds *DatabaseStorage) rollbackTx(tx *sql.Tx) {
+	if tx != ds.tx {
+		tx.Rollback()
+	}
+}
+
 // Close closes the database connection and releases resources.
 func (ds *DatabaseStorage) Close(ctx context.Context) error {
 	ds.logger.Debug("Close database connection",
@@ -275,3 +1007,20 @@ func (ds *DatabaseStorage) Close(ctx context.Context) error {
 	)
 	return nil
 }
+
+// nullTime converts an optional timestamp into a value suitable for a nullable column.
+func nullTime(t *time.Time) sql.NullTime {
+	if t == nil {
+		return sql.NullTime{}
+	}
+	return sql.NullTime{Time: t.UTC(), Valid: true}
+}
+
+// timePtr converts a nullable column value back into an optional timestamp.
+func timePtr(t sql.NullTime) *time.Time {
+	if !t.Valid {
+		return nil
+	}
+	v := t.Time.UTC()
+	return &v
+}
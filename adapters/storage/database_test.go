@@ -2,13 +2,17 @@ package storage
 
 import (
 	"context"
+	"database/sql"
+	"errors"
 	"fmt"
 	"myproject/domain"
 	"path/filepath"
+	"sync"
 	"testing"
 	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestCreateTask(t *testing.T) {
@@ -32,6 +36,64 @@ func TestCreateTask(t *testing.T) {
 		_, err := store.CreateTask(ctx, task, 99999)
 		assert.Error(t, err)
 	})
+	t.Run("persists done status and completion timestamp", func(t *testing.T) {
+		store := setupTestStore(t)
+		userID := createTestUser(t, store)
+
+		completedAt := time.Date(2024, 6, 1, 10, 30, 0, 0, time.UTC)
+		task := domain.Task{Description: "task 1", Done: true, CompletedAt: &completedAt}
+		taskID, err := store.CreateTask(ctx, task, userID)
+		assert.NoError(t, err)
+
+		got, err := store.GetTaskByID(ctx, taskID, userID)
+		assert.NoError(t, err)
+		assert.True(t, got.Done)
+		if assert.NotNil(t, got.CompletedAt) {
+			assert.True(t, completedAt.Equal(*got.CompletedAt))
+		}
+	})
+	t.Run("persists priority", func(t *testing.T) {
+		store := setupTestStore(t)
+		userID := createTestUser(t, store)
+
+		taskID, err := store.CreateTask(ctx, domain.Task{Description: "task 1", Priority: domain.PriorityLow}, userID)
+		assert.NoError(t, err)
+
+		got, err := store.GetTaskByID(ctx, taskID, userID)
+		assert.NoError(t, err)
+		assert.Equal(t, domain.PriorityLow, got.Priority)
+
+		got.Priority = domain.PriorityHigh
+		assert.NoError(t, store.UpdateTask(ctx, got, userID))
+
+		got, err = store.GetTaskByID(ctx, taskID, userID)
+		assert.NoError(t, err)
+		assert.Equal(t, domain.PriorityHigh, got.Priority)
+	})
+	t.Run("persists due date", func(t *testing.T) {
+		store := setupTestStore(t)
+		userID := createTestUser(t, store)
+
+		dueDate := time.Date(2030, 1, 31, 22, 59, 59, 0, time.UTC)
+		taskID, err := store.CreateTask(ctx, domain.Task{Description: "task 1", DueDate: &dueDate}, userID)
+		assert.NoError(t, err)
+
+		got, err := store.GetTaskByID(ctx, taskID, userID)
+		assert.NoError(t, err)
+		if assert.NotNil(t, got.DueDate) {
+			assert.True(t, dueDate.Equal(*got.DueDate))
+		}
+
+		movedDate := dueDate.AddDate(0, 1, 0)
+		got.DueDate = &movedDate
+		assert.NoError(t, store.UpdateTask(ctx, got, userID))
+
+		got, err = store.GetTaskByID(ctx, taskID, userID)
+		assert.NoError(t, err)
+		if assert.NotNil(t, got.DueDate) {
+			assert.True(t, movedDate.Equal(*got.DueDate))
+		}
+	})
 }
 
 func setupTestStore(t *testing.T) *DatabaseStorage {
@@ -69,6 +131,67 @@ func createTestUser(t *testing.T, store *DatabaseStorage) (userID int) {
 	return int(id)
 }
 
+// withoutTimestamps checks that every task carries storage timestamps and clears them for comparison.
+func withoutTimestamps(t *testing.T, tasks []domain.Task) []domain.Task {
+	t.Helper()
+	for i := range tasks {
+		assert.False(t, tasks[i].CreatedAt.IsZero(), "task %d has no created_at", tasks[i].ID)
+		assert.False(t, tasks[i].UpdatedAt.IsZero(), "task %d has no updated_at", tasks[i].ID)
+		tasks[i].CreatedAt = time.Time{}
+		tasks[i].UpdatedAt = time.Time{}
+	}
+	return tasks
+}
+
+func TestCreateTask_Concurrent(t *testing.T) {
+	tests := []struct {
+		name   string
+		config func() ConnectionConfig
+	}{
+		{
+			name:   "default single connection",
+			config: DefaultConnectionConfig,
+		},
+		{
+			name: "several connections wait on busy timeout",
+			config: func() ConnectionConfig {
+				cfg := DefaultConnectionConfig()
+				cfg.MaxOpenConns = 8
+				cfg.MaxIdleConns = 8
+				return cfg
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx := context.Background()
+			store, err := NewDatabaseStorageWithConfig(filepath.Join(t.TempDir(), "test.db"), tc.config(), dummyLogger)
+			require.NoError(t, err)
+			t.Cleanup(func() { store.Close(context.Background()) })
+			userID := createTestUser(t, store)
+
+			const workers, perWorker = 8, 25
+			var wg sync.WaitGroup
+			for range workers {
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					for range perWorker {
+						_, err := store.CreateTask(ctx, domain.Task{Description: "task"}, userID)
+						assert.NoError(t, err, "concurrent writes must not fail with a lock error")
+					}
+				}()
+			}
+			wg.Wait()
+
+			count, err := store.CountTasks(ctx, userID, domain.TaskFilter{})
+			require.NoError(t, err)
+			assert.Equal(t, workers*perWorker, count)
+		})
+	}
+}
+
 func TestUpdateTask(t *testing.T) {
 	ctx := context.Background()
 	t.Run("successfully updates task for valid user", func(t *testing.T) {
@@ -89,6 +212,45 @@ func TestUpdateTask(t *testing.T) {
 		assert.Equal(t, "new task description", description)
 		assert.True(t, done)
 	})
+	t.Run("bumps updated_at but keeps created_at", func(t *testing.T) {
+		store := setupTestStore(t)
+		userID := createTestUser(t, store)
+		taskID, err := store.CreateTask(ctx, domain.Task{Description: "task 1"}, userID)
+		assert.NoError(t, err)
+		_, err = store.db.Exec("UPDATE tasks SET created_at = '2024-01-02 03:04:05', updated_at = '2024-01-02 03:04:05' WHERE id = ?", taskID)
+		assert.NoError(t, err)
+
+		err = store.UpdateTask(ctx, domain.Task{ID: taskID, Description: "task 1 edited"}, userID)
+		assert.NoError(t, err)
+
+		task, err := store.GetTaskByID(ctx, taskID, userID)
+		assert.NoError(t, err)
+		assert.Equal(t, time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC), task.CreatedAt)
+		assert.True(t, task.UpdatedAt.After(task.CreatedAt))
+	})
+	t.Run("increments the version and rejects a stale one", func(t *testing.T) {
+		store := setupTestStore(t)
+		userID := createTestUser(t, store)
+		taskID, err := store.CreateTask(ctx, domain.Task{Description: "task 1"}, userID)
+		require.NoError(t, err)
+		stale, err := store.GetTaskByID(ctx, taskID, userID)
+		require.NoError(t, err)
+		assert.Equal(t, 0, stale.Version)
+
+		fresh := stale
+		fresh.Description = "task 1 edited"
+		require.NoError(t, store.UpdateTask(ctx, fresh, userID))
+
+		stale.Done = true
+		err = store.UpdateTask(ctx, stale, userID)
+		assert.ErrorIs(t, err, domain.ErrVersionConflict)
+
+		task, err := store.GetTaskByID(ctx, taskID, userID)
+		require.NoError(t, err)
+		assert.Equal(t, 1, task.Version)
+		assert.Equal(t, "task 1 edited", task.Description)
+		assert.False(t, task.Done)
+	})
 	t.Run("fails when task belongs to different user", func(t *testing.T) {
 		store := setupTestStore(t)
 		userID := createTestUser(t, store)
@@ -123,10 +285,40 @@ func TestDeleteTask(t *testing.T) {
 		err = store.DeleteTask(ctx, taskID, userID)
 		assert.NoError(t, err)
 
-		var count int
-		err = store.db.QueryRowContext(ctx, "SELECT COUNT(*) FROM tasks").Scan(&count)
+		tasks, err := store.LoadTasks(ctx, userID, domain.ListOptions{})
+		assert.NoError(t, err)
+		assert.Empty(t, tasks)
+
+		count, err := store.CountTasks(ctx, userID, domain.TaskFilter{})
 		assert.NoError(t, err)
 		assert.Equal(t, 0, count)
+
+		_, err = store.GetTaskByID(ctx, taskID, userID)
+		assert.ErrorIs(t, err, domain.ErrTaskNotFound)
+	})
+	t.Run("keeps the row and hides it from search and update", func(t *testing.T) {
+		store := setupTestStore(t)
+		userID := createTestUser(t, store)
+		taskID, err := store.CreateTask(ctx, domain.Task{Description: "buy milk"}, userID)
+		assert.NoError(t, err)
+
+		err = store.DeleteTask(ctx, taskID, userID)
+		assert.NoError(t, err)
+
+		var deletedAt sql.NullTime
+		err = store.db.QueryRowContext(ctx, "SELECT deleted_at FROM tasks WHERE id = ?", taskID).Scan(&deletedAt)
+		assert.NoError(t, err)
+		assert.True(t, deletedAt.Valid)
+
+		tasks, err := store.SearchTasks(ctx, userID, "milk")
+		assert.NoError(t, err)
+		assert.Empty(t, tasks)
+
+		err = store.UpdateTask(ctx, domain.Task{ID: taskID, Description: "buy bread"}, userID)
+		assert.ErrorIs(t, err, domain.ErrTaskNotFound)
+
+		err = store.DeleteTask(ctx, taskID, userID)
+		assert.ErrorIs(t, err, domain.ErrTaskNotFound)
 	})
 	t.Run("fails when task belongs to different user", func(t *testing.T) {
 		store := setupTestStore(t)
@@ -153,6 +345,198 @@ func TestDeleteTask(t *testing.T) {
 	})
 }
 
+func TestRestoreTask(t *testing.T) {
+	ctx := context.Background()
+	t.Run("brings back a deleted task", func(t *testing.T) {
+		store := setupTestStore(t)
+		userID := createTestUser(t, store)
+		taskID, err := store.CreateTask(ctx, domain.Task{Description: "task 1"}, userID)
+		assert.NoError(t, err)
+		assert.NoError(t, store.DeleteTask(ctx, taskID, userID))
+
+		err = store.RestoreTask(ctx, taskID, userID)
+		assert.NoError(t, err)
+
+		task, err := store.GetTaskByID(ctx, taskID, userID)
+		assert.NoError(t, err)
+		assert.Equal(t, "task 1", task.Description)
+		assert.Equal(t, 1, task.Version, "restoring bumps the version, so stale writes are rejected")
+
+		tasks, err := store.LoadTasks(ctx, userID, domain.ListOptions{})
+		assert.NoError(t, err)
+		assert.Len(t, tasks, 1)
+	})
+	t.Run("fails when task is not deleted", func(t *testing.T) {
+		store := setupTestStore(t)
+		userID := createTestUser(t, store)
+		taskID, err := store.CreateTask(ctx, domain.Task{Description: "task 1"}, userID)
+		assert.NoError(t, err)
+
+		err = store.RestoreTask(ctx, taskID, userID)
+		assert.ErrorIs(t, err, domain.ErrTaskNotFound)
+	})
+	t.Run("fails when task belongs to different user", func(t *testing.T) {
+		store := setupTestStore(t)
+		userID := createTestUser(t, store)
+		taskID, err := store.CreateTask(ctx, domain.Task{Description: "task 1"}, userID)
+		assert.NoError(t, err)
+		assert.NoError(t, store.DeleteTask(ctx, taskID, userID))
+
+		otherUserID := createTestUser(t, store)
+		err = store.RestoreTask(ctx, taskID, otherUserID)
+		assert.ErrorIs(t, err, domain.ErrTaskNotFound)
+	})
+}
+
+func TestArchiveTask(t *testing.T) {
+	ctx := context.Background()
+	t.Run("hides the task from the default list", func(t *testing.T) {
+		store := setupTestStore(t)
+		userID := createTestUser(t, store)
+		archivedID, err := store.CreateTask(ctx, domain.Task{Description: "task 1"}, userID)
+		require.NoError(t, err)
+		_, err = store.CreateTask(ctx, domain.Task{Description: "task 2"}, userID)
+		require.NoError(t, err)
+
+		require.NoError(t, store.ArchiveTask(ctx, archivedID, userID))
+
+		tasks, err := store.LoadTasks(ctx, userID, domain.ListOptions{})
+		require.NoError(t, err)
+		require.Len(t, tasks, 1)
+		assert.Equal(t, "task 2", tasks[0].Description)
+		count, err := store.CountTasks(ctx, userID, domain.TaskFilter{})
+		require.NoError(t, err)
+		assert.Equal(t, 1, count)
+
+		all := domain.ListOptions{Filter: domain.TaskFilter{IncludeArchived: true}}
+		tasks, err = store.LoadTasks(ctx, userID, all)
+		require.NoError(t, err)
+		assert.Len(t, tasks, 2)
+		count, err = store.CountTasks(ctx, userID, all.Filter)
+		require.NoError(t, err)
+		assert.Equal(t, 2, count)
+
+		task, err := store.GetTaskByID(ctx, archivedID, userID)
+		require.NoError(t, err)
+		assert.NotNil(t, task.ArchivedAt)
+	})
+	t.Run("bumps the version so the task's ETag changes", func(t *testing.T) {
+		store := setupTestStore(t)
+		userID := createTestUser(t, store)
+		taskID, err := store.CreateTask(ctx, domain.Task{Description: "task 1"}, userID)
+		require.NoError(t, err)
+		before, err := store.GetTaskByID(ctx, taskID, userID)
+		require.NoError(t, err)
+
+		require.NoError(t, store.ArchiveTask(ctx, taskID, userID))
+
+		after, err := store.GetTaskByID(ctx, taskID, userID)
+		require.NoError(t, err)
+		assert.Equal(t, before.Version+1, after.Version)
+	})
+	t.Run("keeps the first archive time", func(t *testing.T) {
+		store := setupTestStore(t)
+		userID := createTestUser(t, store)
+		taskID, err := store.CreateTask(ctx, domain.Task{Description: "task 1"}, userID)
+		require.NoError(t, err)
+		_, err = store.db.ExecContext(ctx, "UPDATE tasks SET archived_at = '2025-01-01 00:00:00' WHERE id = ?", taskID)
+		require.NoError(t, err)
+
+		require.NoError(t, store.ArchiveTask(ctx, taskID, userID))
+
+		task, err := store.GetTaskByID(ctx, taskID, userID)
+		require.NoError(t, err)
+		require.NotNil(t, task.ArchivedAt)
+		assert.Equal(t, time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC), *task.ArchivedAt)
+	})
+	t.Run("fails when task is deleted or belongs to different user", func(t *testing.T) {
+		store := setupTestStore(t)
+		userID := createTestUser(t, store)
+		taskID, err := store.CreateTask(ctx, domain.Task{Description: "task 1"}, userID)
+		require.NoError(t, err)
+		deletedID, err := store.CreateTask(ctx, domain.Task{Description: "task 2"}, userID)
+		require.NoError(t, err)
+		require.NoError(t, store.DeleteTask(ctx, deletedID, userID))
+
+		otherUserID := createTestUser(t, store)
+		assert.ErrorIs(t, store.ArchiveTask(ctx, taskID, otherUserID), domain.ErrTaskNotFound)
+		assert.ErrorIs(t, store.ArchiveTask(ctx, deletedID, userID), domain.ErrTaskNotFound)
+	})
+}
+
+func TestArchiveCompleted(t *testing.T) {
+	ctx := context.Background()
+	store := setupTestStore(t)
+	userID := createTestUser(t, store)
+	otherUserID := createTestUser(t, store)
+
+	create := func(task domain.Task, userID int) int {
+		t.Helper()
+		id, err := store.CreateTask(ctx, task, userID)
+		require.NoError(t, err)
+		return id
+	}
+	done := create(domain.Task{Description: "done 1", Done: true}, userID)
+	create(domain.Task{Description: "done 2", Done: true}, userID)
+	open := create(domain.Task{Description: "open"}, userID)
+	deleted := create(domain.Task{Description: "deleted", Done: true}, userID)
+	require.NoError(t, store.DeleteTask(ctx, deleted, userID))
+	foreign := create(domain.Task{Description: "foreign", Done: true}, otherUserID)
+
+	archived, err := store.ArchiveCompleted(ctx, userID)
+	require.NoError(t, err)
+	assert.Equal(t, 2, archived, "open, deleted and foreign tasks are skipped")
+
+	archived, err = store.ArchiveCompleted(ctx, userID)
+	require.NoError(t, err)
+	assert.Zero(t, archived, "archived tasks are not counted again")
+
+	tasks, err := store.LoadTasks(ctx, userID, domain.ListOptions{})
+	require.NoError(t, err)
+	require.Len(t, tasks, 1)
+	assert.Equal(t, open, tasks[0].ID)
+	task, err := store.GetTaskByID(ctx, foreign, otherUserID)
+	require.NoError(t, err)
+	assert.Nil(t, task.ArchivedAt)
+	task, err = store.GetTaskByID(ctx, done, userID)
+	require.NoError(t, err)
+	assert.Equal(t, 1, task.Version, "archiving bumps the version")
+}
+
+func TestPurgeTask(t *testing.T) {
+	ctx := context.Background()
+	t.Run("removes the row of a live or deleted task", func(t *testing.T) {
+		store := setupTestStore(t)
+		userID := createTestUser(t, store)
+		liveID, err := store.CreateTask(ctx, domain.Task{Description: "task 1"}, userID)
+		assert.NoError(t, err)
+		deletedID, err := store.CreateTask(ctx, domain.Task{Description: "task 2"}, userID)
+		assert.NoError(t, err)
+		assert.NoError(t, store.DeleteTask(ctx, deletedID, userID))
+
+		assert.NoError(t, store.PurgeTask(ctx, liveID, userID))
+		assert.NoError(t, store.PurgeTask(ctx, deletedID, userID))
+
+		var count int
+		err = store.db.QueryRowContext(ctx, "SELECT COUNT(*) FROM tasks").Scan(&count)
+		assert.NoError(t, err)
+		assert.Equal(t, 0, count)
+
+		err = store.RestoreTask(ctx, deletedID, userID)
+		assert.ErrorIs(t, err, domain.ErrTaskNotFound)
+	})
+	t.Run("fails when task belongs to different user", func(t *testing.T) {
+		store := setupTestStore(t)
+		userID := createTestUser(t, store)
+		taskID, err := store.CreateTask(ctx, domain.Task{Description: "task 1"}, userID)
+		assert.NoError(t, err)
+
+		otherUserID := createTestUser(t, store)
+		err = store.PurgeTask(ctx, taskID, otherUserID)
+		assert.ErrorIs(t, err, domain.ErrTaskNotFound)
+	})
+}
+
 func TestGetTaskByID(t *testing.T) {
 	ctx := context.Background()
 	t.Run("successfully gets task for valid user", func(t *testing.T) {
@@ -190,14 +574,231 @@ func TestGetTaskByID(t *testing.T) {
 	})
 }
 
+func TestTaskExists(t *testing.T) {
+	ctx := context.Background()
+	store := setupTestStore(t)
+	userID := createTestUser(t, store)
+
+	taskID, err := store.CreateTask(ctx, domain.Task{Description: "task 1"}, userID)
+	require.NoError(t, err)
+	deletedID, err := store.CreateTask(ctx, domain.Task{Description: "task 2"}, userID)
+	require.NoError(t, err)
+	require.NoError(t, store.DeleteTask(ctx, deletedID, userID))
+
+	exists, err := store.TaskExists(ctx, taskID)
+	require.NoError(t, err)
+	assert.True(t, exists, "task exists regardless of the asking user")
+
+	exists, err = store.TaskExists(ctx, deletedID)
+	require.NoError(t, err)
+	assert.False(t, exists, "soft-deleted tasks count as missing")
+
+	exists, err = store.TaskExists(ctx, 99999)
+	require.NoError(t, err)
+	assert.False(t, exists)
+}
+
+func TestTaskExistsByDescription(t *testing.T) {
+	ctx := context.Background()
+	store := setupTestStore(t)
+	userID := createTestUser(t, store)
+	otherUserID := createTestUser(t, store)
+
+	_, err := store.CreateTask(ctx, domain.Task{Description: "task 1"}, userID)
+	require.NoError(t, err)
+	deletedID, err := store.CreateTask(ctx, domain.Task{Description: "task 2"}, userID)
+	require.NoError(t, err)
+	require.NoError(t, store.DeleteTask(ctx, deletedID, userID))
+
+	exists, err := store.TaskExistsByDescription(ctx, userID, "task 1")
+	require.NoError(t, err)
+	assert.True(t, exists)
+
+	exists, err = store.TaskExistsByDescription(ctx, userID, "Task 1")
+	require.NoError(t, err)
+	assert.False(t, exists, "descriptions are compared exactly")
+
+	exists, err = store.TaskExistsByDescription(ctx, userID, "task 2")
+	require.NoError(t, err)
+	assert.False(t, exists, "soft-deleted tasks do not count")
+
+	exists, err = store.TaskExistsByDescription(ctx, otherUserID, "task 1")
+	require.NoError(t, err)
+	assert.False(t, exists, "tasks of other users do not count")
+}
+
+func TestTaskStats(t *testing.T) {
+	ctx := context.Background()
+	store := setupTestStore(t)
+	userID := createTestUser(t, store)
+	otherUserID := createTestUser(t, store)
+
+	stats, err := store.TaskStats(ctx, userID)
+	require.NoError(t, err)
+	assert.Equal(t, domain.Stats{}, stats, "a user without tasks has zero counts")
+
+	for _, task := range []domain.Task{
+		{Description: "task 1", Done: true},
+		{Description: "task 2"},
+		{Description: "task 3"},
+	} {
+		_, err := store.CreateTask(ctx, task, userID)
+		require.NoError(t, err)
+	}
+	deletedID, err := store.CreateTask(ctx, domain.Task{Description: "deleted", Done: true}, userID)
+	require.NoError(t, err)
+	require.NoError(t, store.DeleteTask(ctx, deletedID, userID))
+	archivedID, err := store.CreateTask(ctx, domain.Task{Description: "archived", Done: true}, userID)
+	require.NoError(t, err)
+	require.NoError(t, store.ArchiveTask(ctx, archivedID, userID))
+	_, err = store.CreateTask(ctx, domain.Task{Description: "other user"}, otherUserID)
+	require.NoError(t, err)
+
+	stats, err = store.TaskStats(ctx, userID)
+	require.NoError(t, err)
+	assert.Equal(t, domain.Stats{Total: 3, Done: 1, Pending: 2}, stats)
+}
+
+func TestUpdateAllTaskStatus(t *testing.T) {
+	ctx := context.Background()
+	store := setupTestStore(t)
+	userID := createTestUser(t, store)
+	otherUserID := createTestUser(t, store)
+
+	completedAt := time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC)
+	create := func(task domain.Task, userID int) int {
+		t.Helper()
+		id, err := store.CreateTask(ctx, task, userID)
+		require.NoError(t, err)
+		return id
+	}
+	doneID := create(domain.Task{Description: "done", Done: true, CompletedAt: &completedAt}, userID)
+	openID := create(domain.Task{Description: "open"}, userID)
+	create(domain.Task{Description: "open 2"}, userID)
+	deletedID := create(domain.Task{Description: "deleted"}, userID)
+	require.NoError(t, store.DeleteTask(ctx, deletedID, userID))
+	foreignID := create(domain.Task{Description: "foreign"}, otherUserID)
+	_, err := store.db.ExecContext(ctx, "UPDATE tasks SET updated_at = '2000-01-01 00:00:00'")
+	require.NoError(t, err)
+
+	t.Run("marks open tasks done", func(t *testing.T) {
+		updated, err := store.UpdateAllTaskStatus(ctx, userID, true)
+		require.NoError(t, err)
+		assert.Equal(t, 2, updated, "already done and deleted tasks are not counted")
+
+		open, err := store.GetTaskByID(ctx, openID, userID)
+		require.NoError(t, err)
+		assert.True(t, open.Done)
+		require.NotNil(t, open.CompletedAt)
+		assert.True(t, open.UpdatedAt.After(completedAt), "updated_at is bumped")
+
+		done, err := store.GetTaskByID(ctx, doneID, userID)
+		require.NoError(t, err)
+		assert.True(t, completedAt.Equal(*done.CompletedAt), "completion time of done tasks is kept")
+		assert.Equal(t, 2000, done.UpdatedAt.Year(), "unchanged tasks keep updated_at")
+
+		foreign, err := store.GetTaskByID(ctx, foreignID, otherUserID)
+		require.NoError(t, err)
+		assert.False(t, foreign.Done)
+
+		require.NoError(t, store.RestoreTask(ctx, deletedID, userID))
+		deleted, err := store.GetTaskByID(ctx, deletedID, userID)
+		require.NoError(t, err)
+		assert.False(t, deleted.Done)
+		require.NoError(t, store.DeleteTask(ctx, deletedID, userID))
+	})
+	t.Run("marks done tasks open", func(t *testing.T) {
+		updated, err := store.UpdateAllTaskStatus(ctx, userID, false)
+		require.NoError(t, err)
+		assert.Equal(t, 3, updated)
+
+		tasks, err := store.LoadTasks(ctx, userID, domain.ListOptions{})
+		require.NoError(t, err)
+		for _, task := range tasks {
+			assert.False(t, task.Done)
+			assert.Nil(t, task.CompletedAt)
+		}
+	})
+}
+
+func TestToggleTaskDone(t *testing.T) {
+	ctx := context.Background()
+	store := setupTestStore(t)
+	userID := createTestUser(t, store)
+	otherUserID := createTestUser(t, store)
+
+	id, err := store.CreateTask(ctx, domain.Task{Description: "toggle me", Tags: []string{"work"}}, userID)
+	require.NoError(t, err)
+
+	t.Run("marks an open task done", func(t *testing.T) {
+		task, err := store.ToggleTaskDone(ctx, id, userID)
+		require.NoError(t, err)
+		assert.Equal(t, id, task.ID)
+		assert.Equal(t, "toggle me", task.Description)
+		assert.True(t, task.Done)
+		assert.NotNil(t, task.CompletedAt)
+		assert.Equal(t, []string{"work"}, task.Tags)
+	})
+	t.Run("marks a done task open", func(t *testing.T) {
+		task, err := store.ToggleTaskDone(ctx, id, userID)
+		require.NoError(t, err)
+		assert.False(t, task.Done)
+		assert.Nil(t, task.CompletedAt)
+
+		stored, err := store.GetTaskByID(ctx, id, userID)
+		require.NoError(t, err)
+		assert.Equal(t, task, stored)
+	})
+	t.Run("returns ErrTaskNotFound for another user's task", func(t *testing.T) {
+		_, err := store.ToggleTaskDone(ctx, id, otherUserID)
+		assert.ErrorIs(t, err, domain.ErrTaskNotFound)
+	})
+	t.Run("returns ErrTaskNotFound for a deleted task", func(t *testing.T) {
+		require.NoError(t, store.DeleteTask(ctx, id, userID))
+		_, err := store.ToggleTaskDone(ctx, id, userID)
+		assert.ErrorIs(t, err, domain.ErrTaskNotFound)
+	})
+}
+
+func TestDeleteTasks(t *testing.T) {
+	ctx := context.Background()
+	store := setupTestStore(t)
+	userID := createTestUser(t, store)
+	otherUserID := createTestUser(t, store)
+
+	create := func(userID int) int {
+		t.Helper()
+		id, err := store.CreateTask(ctx, domain.Task{Description: "task"}, userID)
+		require.NoError(t, err)
+		return id
+	}
+	first, second, kept := create(userID), create(userID), create(userID)
+	alreadyDeleted := create(userID)
+	require.NoError(t, store.DeleteTask(ctx, alreadyDeleted, userID))
+	foreign := create(otherUserID)
+
+	deleted, err := store.DeleteTasks(ctx, []int{first, second, alreadyDeleted, foreign, 99999}, userID)
+	require.NoError(t, err)
+	assert.Equal(t, 2, deleted, "missing, deleted and foreign IDs are skipped")
+
+	count, err := store.CountTasks(ctx, userID, domain.TaskFilter{})
+	require.NoError(t, err)
+	assert.Equal(t, 1, count)
+	_, err = store.GetTaskByID(ctx, kept, userID)
+	assert.NoError(t, err)
+	_, err = store.GetTaskByID(ctx, foreign, otherUserID)
+	assert.NoError(t, err)
+	require.NoError(t, store.RestoreTask(ctx, first, userID), "batch deletes can be restored")
+}
+
 func TestLoadTasks(t *testing.T) {
 	ctx := context.Background()
 	store := setupTestStore(t)
 	userID := createTestUser(t, store)
 
 	tasks := []domain.Task{
-		{ID: 1, Description: "task 1", Done: false},
-		{ID: 2, Description: "task 2", Done: false},
+		{ID: 1, Description: "task 1", Done: false, Priority: domain.PriorityMedium},
+		{ID: 2, Description: "task 2", Done: false, Priority: domain.PriorityHigh},
 		{ID: 3, Description: "task 3", Done: true},
 	}
 	for _, task := range tasks {
@@ -205,14 +806,436 @@ func TestLoadTasks(t *testing.T) {
 		assert.NoError(t, err)
 	}
 	t.Run("successfully loads tasks for valid user", func(t *testing.T) {
-		loadTasks, err := store.LoadTasks(ctx, userID)
+		loadTasks, err := store.LoadTasks(ctx, userID, domain.ListOptions{})
 		assert.NoError(t, err)
-		assert.Equal(t, tasks, loadTasks)
+		assert.Equal(t, tasks, withoutTimestamps(t, loadTasks))
+	})
+	t.Run("returns requested page", func(t *testing.T) {
+		loadTasks, err := store.LoadTasks(ctx, userID, domain.ListOptions{Limit: 1, Offset: 1})
+		assert.NoError(t, err)
+		assert.Equal(t, tasks[1:2], withoutTimestamps(t, loadTasks))
+	})
+	t.Run("returns empty page past the end", func(t *testing.T) {
+		loadTasks, err := store.LoadTasks(ctx, userID, domain.ListOptions{Limit: 10, Offset: 3})
+		assert.NoError(t, err)
+		assert.Empty(t, loadTasks)
+	})
+	t.Run("orders by requested field", func(t *testing.T) {
+		byIDDesc, err := store.LoadTasks(ctx, userID, domain.ListOptions{Sort: domain.TaskSort{Field: domain.SortByID, Descending: true}})
+		assert.NoError(t, err)
+		assert.Equal(t, []domain.Task{tasks[2], tasks[1], tasks[0]}, withoutTimestamps(t, byIDDesc))
+
+		byDoneDesc, err := store.LoadTasks(ctx, userID, domain.ListOptions{Sort: domain.TaskSort{Field: domain.SortByDone, Descending: true}})
+		assert.NoError(t, err)
+		assert.Equal(t, []domain.Task{tasks[2], tasks[0], tasks[1]}, withoutTimestamps(t, byDoneDesc))
+
+		byPriorityDesc, err := store.LoadTasks(ctx, userID, domain.ListOptions{Sort: domain.TaskSort{Field: domain.SortByPriority, Descending: true}})
+		assert.NoError(t, err)
+		assert.Equal(t, []domain.Task{tasks[1], tasks[0], tasks[2]}, withoutTimestamps(t, byPriorityDesc))
+	})
+	t.Run("counts all tasks of the user", func(t *testing.T) {
+		count, err := store.CountTasks(ctx, userID, domain.TaskFilter{})
+		assert.NoError(t, err)
+		assert.Equal(t, 3, count)
+	})
+	t.Run("filters by creation time with inclusive bounds", func(t *testing.T) {
+		_, err := store.db.Exec("UPDATE tasks SET created_at = '2024-01-02 03:04:05' WHERE id = 1")
+		require.NoError(t, err)
+		old := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+		afterOld := old.Add(time.Second)
+
+		testCases := []struct {
+			name     string
+			filter   domain.TaskFilter
+			expected []domain.Task
+		}{
+			{name: "created before", filter: domain.TaskFilter{CreatedBefore: &old}, expected: tasks[0:1]},
+			{name: "created after", filter: domain.TaskFilter{CreatedAfter: &afterOld}, expected: []domain.Task{tasks[1], tasks[2]}},
+			{name: "single second range", filter: domain.TaskFilter{CreatedAfter: &old, CreatedBefore: &old}, expected: tasks[0:1]},
+		}
+		for _, tc := range testCases {
+			loaded, err := store.LoadTasks(ctx, userID, domain.ListOptions{Filter: tc.filter, Sort: domain.TaskSort{Field: domain.SortByID}})
+			require.NoError(t, err, tc.name)
+			assert.Equal(t, tc.expected, withoutTimestamps(t, loaded), tc.name)
+
+			count, err := store.CountTasks(ctx, userID, tc.filter)
+			require.NoError(t, err, tc.name)
+			assert.Equal(t, len(tc.expected), count, tc.name)
+		}
 	})
 	t.Run("returns 0 tasks when tasks belongs to different user", func(t *testing.T) {
 		userID := createTestUser(t, store)
-		loadTasks, err := store.LoadTasks(ctx, userID)
+		loadTasks, err := store.LoadTasks(ctx, userID, domain.ListOptions{})
 		assert.NoError(t, err)
 		assert.Empty(t, loadTasks)
 	})
 }
+
+func TestSearchTasks(t *testing.T) {
+	ctx := context.Background()
+	store := setupTestStore(t)
+	userID := createTestUser(t, store)
+
+	tasks := []domain.Task{
+		{ID: 1, Description: "Buy milk", Done: false},
+		{ID: 2, Description: "Walk the dog", Done: false},
+		{ID: 3, Description: "buy bread", Done: true},
+		{ID: 4, Description: "Reach 100% coverage", Done: false},
+	}
+	for _, task := range tasks {
+		_, err := store.CreateTask(ctx, task, userID)
+		assert.NoError(t, err)
+	}
+	t.Run("matches case-insensitively", func(t *testing.T) {
+		found, err := store.SearchTasks(ctx, userID, "BUY")
+		assert.NoError(t, err)
+		assert.Equal(t, []domain.Task{tasks[0], tasks[2]}, withoutTimestamps(t, found))
+	})
+	t.Run("treats wildcards literally", func(t *testing.T) {
+		found, err := store.SearchTasks(ctx, userID, "0%")
+		assert.NoError(t, err)
+		assert.Equal(t, []domain.Task{tasks[3]}, withoutTimestamps(t, found))
+
+		found, err = store.SearchTasks(ctx, userID, "_")
+		assert.NoError(t, err)
+		assert.Empty(t, found)
+	})
+	t.Run("returns 0 tasks when tasks belongs to different user", func(t *testing.T) {
+		userID := createTestUser(t, store)
+		found, err := store.SearchTasks(ctx, userID, "buy")
+		assert.NoError(t, err)
+		assert.Empty(t, found)
+	})
+	t.Run("leaves out archived tasks", func(t *testing.T) {
+		id, err := store.CreateTask(ctx, domain.Task{Description: "archived coverage"}, userID)
+		require.NoError(t, err)
+		require.NoError(t, store.ArchiveTask(ctx, id, userID))
+
+		found, err := store.SearchTasks(ctx, userID, "coverage")
+		assert.NoError(t, err)
+		assert.Equal(t, []domain.Task{tasks[3]}, withoutTimestamps(t, found))
+	})
+}
+
+func TestLoadTasks_Overdue(t *testing.T) {
+	ctx := context.Background()
+	store := setupTestStore(t)
+	userID := createTestUser(t, store)
+	otherUserID := createTestUser(t, store)
+
+	now := time.Date(2030, 6, 15, 12, 0, 0, 0, time.UTC)
+	at := func(d time.Duration) *time.Time {
+		v := now.Add(d)
+		return &v
+	}
+
+	create := func(task domain.Task, userID int) int {
+		t.Helper()
+		id, err := store.CreateTask(ctx, task, userID)
+		assert.NoError(t, err)
+		return id
+	}
+	lastWeek := create(domain.Task{Description: "last week", DueDate: at(-7 * 24 * time.Hour)}, userID)
+	anHourAgo := create(domain.Task{Description: "an hour ago", DueDate: at(-time.Hour)}, userID)
+	tomorrow := create(domain.Task{Description: "tomorrow", DueDate: at(24 * time.Hour)}, userID)
+	noDueDate := create(domain.Task{Description: "no due date"}, userID)
+	done := create(domain.Task{Description: "done", Done: true, DueDate: at(-time.Hour)}, userID)
+	deleted := create(domain.Task{Description: "deleted", DueDate: at(-time.Hour)}, userID)
+	assert.NoError(t, store.DeleteTask(ctx, deleted, userID))
+	create(domain.Task{Description: "other user", DueDate: at(-time.Hour)}, otherUserID)
+
+	taskIDs := func(tasks []domain.Task) []int {
+		ids := make([]int, len(tasks))
+		for i, task := range tasks {
+			ids[i] = task.ID
+		}
+		return ids
+	}
+	byDue := domain.TaskSort{Field: domain.SortByDue}
+	overdue := domain.TaskFilter{OverdueAt: &now}
+
+	t.Run("keeps open, live, past-due tasks of the user", func(t *testing.T) {
+		tasks, err := store.LoadTasks(ctx, userID, domain.ListOptions{Sort: byDue, Filter: overdue})
+		assert.NoError(t, err)
+		assert.Equal(t, []int{lastWeek, anHourAgo}, taskIDs(tasks), "earliest due first")
+
+		count, err := store.CountTasks(ctx, userID, overdue)
+		assert.NoError(t, err)
+		assert.Equal(t, 2, count)
+	})
+	t.Run("pages through overdue tasks", func(t *testing.T) {
+		tasks, err := store.LoadTasks(ctx, userID, domain.ListOptions{Limit: 1, Offset: 1, Sort: byDue, Filter: overdue})
+		assert.NoError(t, err)
+		assert.Equal(t, []int{anHourAgo}, taskIDs(tasks))
+	})
+	t.Run("sorts tasks without a due date last", func(t *testing.T) {
+		tasks, err := store.LoadTasks(ctx, userID, domain.ListOptions{Sort: byDue})
+		assert.NoError(t, err)
+		assert.Equal(t, []int{lastWeek, anHourAgo, done, tomorrow, noDueDate}, taskIDs(tasks))
+	})
+}
+
+func TestTaskTags(t *testing.T) {
+	ctx := context.Background()
+	t.Run("stores tags given on create", func(t *testing.T) {
+		store := setupTestStore(t)
+		userID := createTestUser(t, store)
+
+		taskID, err := store.CreateTask(ctx, domain.Task{Description: "task 1", Tags: []string{"home", "work"}}, userID)
+		assert.NoError(t, err)
+
+		got, err := store.GetTaskByID(ctx, taskID, userID)
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"home", "work"}, got.Tags)
+	})
+	t.Run("adds and removes tags", func(t *testing.T) {
+		store := setupTestStore(t)
+		userID := createTestUser(t, store)
+		taskID, err := store.CreateTask(ctx, domain.Task{Description: "task 1"}, userID)
+		assert.NoError(t, err)
+
+		assert.NoError(t, store.AddTag(ctx, taskID, "work", userID))
+		assert.NoError(t, store.AddTag(ctx, taskID, "home", userID))
+		assert.NoError(t, store.AddTag(ctx, taskID, "work", userID), "adding a tag twice is a no-op")
+		got, err := store.GetTaskByID(ctx, taskID, userID)
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"home", "work"}, got.Tags)
+
+		assert.NoError(t, store.RemoveTag(ctx, taskID, "work", userID))
+		assert.NoError(t, store.RemoveTag(ctx, taskID, "errands", userID), "removing a missing tag is a no-op")
+		got, err = store.GetTaskByID(ctx, taskID, userID)
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"home"}, got.Tags)
+	})
+	t.Run("fails when task belongs to different user", func(t *testing.T) {
+		store := setupTestStore(t)
+		userID := createTestUser(t, store)
+		taskID, err := store.CreateTask(ctx, domain.Task{Description: "task 1"}, userID)
+		assert.NoError(t, err)
+
+		otherUserID := createTestUser(t, store)
+		assert.ErrorIs(t, store.AddTag(ctx, taskID, "work", otherUserID), domain.ErrTaskNotFound)
+		assert.ErrorIs(t, store.RemoveTag(ctx, taskID, "work", otherUserID), domain.ErrTaskNotFound)
+	})
+	t.Run("loads tasks by tag per user", func(t *testing.T) {
+		store := setupTestStore(t)
+		userID := createTestUser(t, store)
+		otherUserID := createTestUser(t, store)
+
+		create := func(task domain.Task, userID int) int {
+			t.Helper()
+			id, err := store.CreateTask(ctx, task, userID)
+			assert.NoError(t, err)
+			return id
+		}
+		older := create(domain.Task{Description: "older", Tags: []string{"work"}}, userID)
+		newer := create(domain.Task{Description: "newer", Tags: []string{"home", "work"}}, userID)
+		create(domain.Task{Description: "home only", Tags: []string{"home"}}, userID)
+		deleted := create(domain.Task{Description: "deleted", Tags: []string{"work"}}, userID)
+		assert.NoError(t, store.DeleteTask(ctx, deleted, userID))
+		create(domain.Task{Description: "other user", Tags: []string{"work"}}, otherUserID)
+
+		byTag := domain.TaskFilter{Tag: "work"}
+		tasks, err := store.LoadTasks(ctx, userID, domain.ListOptions{Filter: byTag})
+		assert.NoError(t, err)
+		ids := make([]int, len(tasks))
+		for i, task := range tasks {
+			ids[i] = task.ID
+		}
+		assert.ElementsMatch(t, []int{older, newer}, ids, "only live tasks of the user carrying the tag")
+		count, err := store.CountTasks(ctx, userID, byTag)
+		assert.NoError(t, err)
+		assert.Equal(t, 2, count)
+
+		page, err := store.LoadTasks(ctx, userID, domain.ListOptions{Limit: 1, Offset: 1, Sort: domain.TaskSort{Field: domain.SortByID}, Filter: byTag})
+		assert.NoError(t, err)
+		if assert.Len(t, page, 1) {
+			assert.Equal(t, newer, page[0].ID)
+		}
+
+		assert.NoError(t, store.ArchiveTask(ctx, older, userID))
+		tasks, err = store.LoadTasks(ctx, userID, domain.ListOptions{Filter: byTag})
+		assert.NoError(t, err)
+		assert.Len(t, tasks, 1, "archived tasks are left out")
+		tasks, err = store.LoadTasks(ctx, userID, domain.ListOptions{Filter: domain.TaskFilter{Tag: "work", IncludeArchived: true}})
+		assert.NoError(t, err)
+		assert.Len(t, tasks, 2, "archived tasks come back with IncludeArchived")
+
+		var tagCount int
+		assert.NoError(t, store.db.QueryRowContext(ctx, "SELECT COUNT(*) FROM tags WHERE name = 'work'").Scan(&tagCount))
+		assert.Equal(t, 2, tagCount, "each user has their own tag")
+	})
+	t.Run("purging a task removes its tag links", func(t *testing.T) {
+		store := setupTestStore(t)
+		userID := createTestUser(t, store)
+		taskID, err := store.CreateTask(ctx, domain.Task{Description: "task 1", Tags: []string{"work"}}, userID)
+		assert.NoError(t, err)
+
+		assert.NoError(t, store.DeleteTask(ctx, taskID, userID))
+		assert.NoError(t, store.RestoreTask(ctx, taskID, userID))
+		got, err := store.GetTaskByID(ctx, taskID, userID)
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"work"}, got.Tags, "a restored task keeps its tags")
+
+		assert.NoError(t, store.PurgeTask(ctx, taskID, userID))
+		var links int
+		assert.NoError(t, store.db.QueryRowContext(ctx, "SELECT COUNT(*) FROM task_tags").Scan(&links))
+		assert.Zero(t, links)
+	})
+}
+
+func TestCreateTasks(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("stores the batch and returns ids in order", func(t *testing.T) {
+		store := setupTestStore(t)
+		userID := createTestUser(t, store)
+
+		ids, err := store.CreateTasks(ctx, []domain.Task{
+			{Description: "first"},
+			{Description: "second"},
+			{Description: "third"},
+		}, userID)
+		assert.NoError(t, err)
+		assert.Len(t, ids, 3)
+
+		for i, description := range []string{"first", "second", "third"} {
+			task, err := store.GetTaskByID(ctx, ids[i], userID)
+			assert.NoError(t, err)
+			assert.Equal(t, description, task.Description)
+		}
+	})
+
+	t.Run("fails when user does not exist", func(t *testing.T) {
+		store := setupTestStore(t)
+
+		_, err := store.CreateTasks(ctx, []domain.Task{{Description: "orphan"}}, 99999)
+		assert.Error(t, err)
+
+		count, err := store.CountTasks(ctx, 99999, domain.TaskFilter{})
+		assert.NoError(t, err)
+		assert.Zero(t, count)
+	})
+}
+
+func TestUpdateTasks(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("updates every task in one transaction", func(t *testing.T) {
+		store := setupTestStore(t)
+		userID := createTestUser(t, store)
+		ids, err := store.CreateTasks(ctx, []domain.Task{{Description: "first"}, {Description: "second"}}, userID)
+		require.NoError(t, err)
+
+		first, err := store.GetTaskByID(ctx, ids[0], userID)
+		require.NoError(t, err)
+		second, err := store.GetTaskByID(ctx, ids[1], userID)
+		require.NoError(t, err)
+		first.Description = "first updated"
+		second.Done = true
+
+		require.NoError(t, store.UpdateTasks(ctx, []domain.Task{first, second}, userID))
+
+		first, err = store.GetTaskByID(ctx, ids[0], userID)
+		require.NoError(t, err)
+		assert.Equal(t, "first updated", first.Description)
+		assert.Equal(t, 1, first.Version)
+		second, err = store.GetTaskByID(ctx, ids[1], userID)
+		require.NoError(t, err)
+		assert.True(t, second.Done)
+		assert.Equal(t, 1, second.Version)
+	})
+
+	t.Run("rolls back all tasks when one fails", func(t *testing.T) {
+		store := setupTestStore(t)
+		userID := createTestUser(t, store)
+		ids, err := store.CreateTasks(ctx, []domain.Task{{Description: "first"}, {Description: "second"}}, userID)
+		require.NoError(t, err)
+
+		first, err := store.GetTaskByID(ctx, ids[0], userID)
+		require.NoError(t, err)
+		stale, err := store.GetTaskByID(ctx, ids[1], userID)
+		require.NoError(t, err)
+		_, err = store.ToggleTaskDone(ctx, ids[1], userID)
+		require.NoError(t, err)
+		first.Description = "first updated"
+		stale.Description = "second updated"
+
+		err = store.UpdateTasks(ctx, []domain.Task{first, stale}, userID)
+		assert.ErrorIs(t, err, domain.ErrVersionConflict)
+		var itemErr *domain.BatchItemError
+		require.ErrorAs(t, err, &itemErr)
+		assert.Equal(t, 1, itemErr.Index)
+
+		first, err = store.GetTaskByID(ctx, ids[0], userID)
+		require.NoError(t, err)
+		assert.Equal(t, "first", first.Description)
+		assert.Zero(t, first.Version)
+
+		err = store.UpdateTasks(ctx, []domain.Task{first, {ID: ids[1] + 100, Description: "missing"}}, userID)
+		assert.ErrorIs(t, err, domain.ErrTaskNotFound)
+	})
+}
+
+func TestWithTx(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("commits every step", func(t *testing.T) {
+		store := setupTestStore(t)
+		userID := createTestUser(t, store)
+
+		var id int
+		err := store.WithTx(ctx, func(tx domain.TxStorage) error {
+			var err error
+			id, err = tx.CreateTask(ctx, domain.Task{Description: "first"}, userID)
+			if err != nil {
+				return err
+			}
+			return tx.AddTag(ctx, id, "work", userID)
+		})
+		require.NoError(t, err)
+
+		task, err := store.GetTaskByID(ctx, id, userID)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"work"}, task.Tags)
+	})
+
+	t.Run("a failing second step rolls back the first", func(t *testing.T) {
+		store := setupTestStore(t)
+		userID := createTestUser(t, store)
+
+		err := store.WithTx(ctx, func(tx domain.TxStorage) error {
+			id, err := tx.CreateTask(ctx, domain.Task{Description: "first"}, userID)
+			if err != nil {
+				return err
+			}
+			// The task is visible within the transaction
+			if _, err := tx.GetTaskByID(ctx, id, userID); err != nil {
+				return err
+			}
+			return tx.DeleteTask(ctx, id+100, userID)
+		})
+		assert.ErrorIs(t, err, domain.ErrTaskNotFound)
+
+		count, err := store.CountTasks(ctx, userID, domain.TaskFilter{})
+		require.NoError(t, err)
+		assert.Zero(t, count)
+	})
+
+	t.Run("batch operations join the transaction", func(t *testing.T) {
+		store := setupTestStore(t)
+		userID := createTestUser(t, store)
+
+		err := store.WithTx(ctx, func(tx domain.TxStorage) error {
+			if _, err := tx.CreateTasks(ctx, []domain.Task{{Description: "first"}, {Description: "second"}}, userID); err != nil {
+				return err
+			}
+			return errors.New("abort")
+		})
+		assert.EqualError(t, err, "abort")
+
+		count, err := store.CountTasks(ctx, userID, domain.TaskFilter{})
+		require.NoError(t, err)
+		assert.Zero(t, count)
+	})
+}
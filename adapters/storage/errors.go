@@ -12,6 +12,7 @@ var (
 	ErrConstraintViolation = errors.New("database constraint violation")
 	ErrDatabaseLocked      = errors.New("database is locked")
 	ErrDiskFull            = errors.New("database disk is full")
+	ErrFileLocked          = errors.New("task file is locked by another process")
 )
 
 // mapSQLiteError converts SQLite-specific errors to custom error types.
@@ -0,0 +1,660 @@
+package storage
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"myproject/domain"
+	"myproject/logger"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+// JSONFileStorage provides task persistence in a single JSON file, a dependency-free
+// alternative to SQLite for small deployments. All tasks are kept in memory; every change
+// is written to a temporary file that then replaces the original, so a crash never leaves
+// a half-written file behind. It is safe for concurrent use within one process; other
+// processes are kept out by an advisory lock on path+".lock", held until Close.
+type JSONFileStorage struct {
+	mu     sync.RWMutex
+	path   string
+	lock   *os.File
+	data   jsonFileData
+	now    func() time.Time
+	logger *slog.Logger
+	// inTx marks the storage WithTx hands out: its changes stay in memory until WithTx writes them
+	inTx bool
+}
+
+// jsonFileData is the file layout: every user's tasks keyed by user ID.
+type jsonFileData struct {
+	LastID int                    `json:"last_id"`
+	Tasks  map[int][]jsonFileTask `json:"tasks"`
+}
+
+// jsonFileTask is a stored task together with its soft-delete timestamp.
+type jsonFileTask struct {
+	domain.Task
+	DeletedAt *time.Time `json:"deleted_at,omitempty"`
+}
+
+// NewJSONFileStorage opens the task file at path, starting empty when it does not exist yet.
+// It locks the file for the lifetime of the storage and returns ErrFileLocked when another
+// storage already has it open.
+func NewJSONFileStorage(path string, logger *slog.Logger) (*JSONFileStorage, error) {
+	lock, err := lockFile(path)
+	if err != nil {
+		return nil, err
+	}
+	storage := &JSONFileStorage{
+		path:   path,
+		lock:   lock,
+		data:   jsonFileData{Tasks: make(map[int][]jsonFileTask)},
+		now:    time.Now,
+		logger: logger,
+	}
+
+	content, err := os.ReadFile(path)
+	switch {
+	case errors.Is(err, os.ErrNotExist):
+		logger.Info("JSON task file not found, starting empty", slog.String("path", path))
+	case err != nil:
+		lock.Close()
+		return nil, fmt.Errorf("failed to read task file: %w", err)
+	default:
+		if err := json.Unmarshal(content, &storage.data); err != nil {
+			lock.Close()
+			return nil, fmt.Errorf("failed to parse task file %s: %w", path, err)
+		}
+		if storage.data.Tasks == nil {
+			storage.data.Tasks = make(map[int][]jsonFileTask)
+		}
+		logger.Info("JSON task file loaded", slog.String("path", path))
+	}
+	return storage, nil
+}
+
+// CreateTask stores a new task and returns the generated ID.
+func (js *JSONFileStorage) CreateTask(ctx context.Context, task domain.Task, userID int) (int, error) {
+	ids, err := js.CreateTasks(ctx, []domain.Task{task}, userID)
+	if err != nil {
+		return 0, err
+	}
+	return ids[0], nil
+}
+
+// CreateTasks stores all tasks with a single file write and returns the generated IDs in order.
+// If the write fails, none of the tasks are stored.
+func (js *JSONFileStorage) CreateTasks(ctx context.Context, tasks []domain.Task, userID int) ([]int, error) {
+	ids := make([]int, len(tasks))
+	err := js.update("create_tasks", userID, func(data *jsonFileData, now time.Time) error {
+		for i, task := range tasks {
+			data.LastID++
+			task.ID = data.LastID
+			task.CompletedAt = utcPtr(task.CompletedAt)
+			task.DueDate = utcPtr(task.DueDate)
+			task.Tags = slices.Clone(task.Tags)
+			task.CreatedAt = now
+			task.UpdatedAt = now
+			data.Tasks[userID] = append(data.Tasks[userID], jsonFileTask{Task: task})
+			ids[i] = task.ID
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return ids, nil
+}
+
+// UpdateTask modifies a task's description, status, due date and priority, returns ErrTaskNotFound if not owned by user.
+// The task is only changed while its version still equals task.Version, and the version is incremented;
+// a task that changed in the meantime is reported as ErrVersionConflict.
+func (js *JSONFileStorage) UpdateTask(ctx context.Context, task domain.Task, userID int) error {
+	return js.update("update_task", userID, func(data *jsonFileData, now time.Time) error {
+		return updateJSONFileTask(data.Tasks[userID], task, now)
+	})
+}
+
+// UpdateTasks stores every task like UpdateTask with a single file write.
+// The first task that is missing or changed in the meantime leaves the file untouched
+// and is reported as a *domain.BatchItemError.
+func (js *JSONFileStorage) UpdateTasks(ctx context.Context, tasks []domain.Task, userID int) error {
+	return js.update("update_tasks", userID, func(data *jsonFileData, now time.Time) error {
+		for i, task := range tasks {
+			if err := updateJSONFileTask(data.Tasks[userID], task, now); err != nil {
+				return &domain.BatchItemError{Index: i, Err: err}
+			}
+		}
+		return nil
+	})
+}
+
+// updateJSONFileTask copies the editable fields of task onto the stored one if the versions match.
+func updateJSONFileTask(tasks []jsonFileTask, task domain.Task, now time.Time) error {
+	stored := findJSONFileTask(tasks, task.ID, false)
+	if stored == nil {
+		return domain.ErrTaskNotFound
+	}
+	if stored.Version != task.Version {
+		return domain.ErrVersionConflict
+	}
+	stored.Version++
+	stored.Description = task.Description
+	stored.Done = task.Done
+	stored.CompletedAt = utcPtr(task.CompletedAt)
+	stored.DueDate = utcPtr(task.DueDate)
+	stored.Priority = task.Priority
+	stored.UpdatedAt = now
+	return nil
+}
+
+// ToggleTaskDone flips a task's status under the write lock and returns the changed task,
+// returns ErrTaskNotFound if not owned by user.
+func (js *JSONFileStorage) ToggleTaskDone(ctx context.Context, id int, userID int) (domain.Task, error) {
+	var task domain.Task
+	err := js.update("toggle_task_done", userID, func(data *jsonFileData, now time.Time) error {
+		stored := findJSONFileTask(data.Tasks[userID], id, false)
+		if stored == nil {
+			return domain.ErrTaskNotFound
+		}
+		stored.Done = !stored.Done
+		stored.Version++
+		stored.CompletedAt = nil
+		if stored.Done {
+			completedAt := now
+			stored.CompletedAt = &completedAt
+		}
+		stored.UpdatedAt = now
+		task = stored.Task
+		return nil
+	})
+	if err != nil {
+		return domain.Task{}, err
+	}
+	return task, nil
+}
+
+// AddTag attaches a tag to a task, returns ErrTaskNotFound if not owned by user.
+// Tagging a task twice changes nothing.
+func (js *JSONFileStorage) AddTag(ctx context.Context, taskID int, tag string, userID int) error {
+	return js.update("add_tag", userID, func(data *jsonFileData, now time.Time) error {
+		stored := findJSONFileTask(data.Tasks[userID], taskID, false)
+		if stored == nil {
+			return domain.ErrTaskNotFound
+		}
+		i, found := slices.BinarySearch(stored.Tags, tag)
+		if !found {
+			stored.Tags = slices.Insert(slices.Clone(stored.Tags), i, tag)
+		}
+		stored.Version++
+		stored.UpdatedAt = now
+		return nil
+	})
+}
+
+// RemoveTag detaches a tag from a task, returns ErrTaskNotFound if not owned by user.
+// Removing a tag the task does not carry changes nothing.
+func (js *JSONFileStorage) RemoveTag(ctx context.Context, taskID int, tag string, userID int) error {
+	return js.update("remove_tag", userID, func(data *jsonFileData, now time.Time) error {
+		stored := findJSONFileTask(data.Tasks[userID], taskID, false)
+		if stored == nil {
+			return domain.ErrTaskNotFound
+		}
+		stored.Tags = slices.DeleteFunc(slices.Clone(stored.Tags), func(t string) bool { return t == tag })
+		if len(stored.Tags) == 0 {
+			stored.Tags = nil
+		}
+		stored.Version++
+		stored.UpdatedAt = now
+		return nil
+	})
+}
+
+// UpdateAllTaskStatus marks all of the user's tasks done or open in a single write.
+// Only tasks whose status changes are updated, so completion times of already done tasks are kept.
+// Returns the number of changed tasks.
+func (js *JSONFileStorage) UpdateAllTaskStatus(ctx context.Context, userID int, done bool) (int, error) {
+	updated := 0
+	err := js.update("update_all_task_status", userID, func(data *jsonFileData, now time.Time) error {
+		tasks := data.Tasks[userID]
+		for i := range tasks {
+			if tasks[i].DeletedAt != nil || tasks[i].Done == done {
+				continue
+			}
+			tasks[i].Done = done
+			tasks[i].Version++
+			tasks[i].CompletedAt = nil
+			if done {
+				completedAt := now
+				tasks[i].CompletedAt = &completedAt
+			}
+			tasks[i].UpdatedAt = now
+			updated++
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	return updated, nil
+}
+
+// DeleteTask soft-deletes a task, returns ErrTaskNotFound if not owned by user.
+// Deleted tasks are hidden from every read until restored with RestoreTask.
+func (js *JSONFileStorage) DeleteTask(ctx context.Context, id int, userID int) error {
+	return js.update("delete_task", userID, func(data *jsonFileData, now time.Time) error {
+		stored := findJSONFileTask(data.Tasks[userID], id, false)
+		if stored == nil {
+			return domain.ErrTaskNotFound
+		}
+		stored.DeletedAt = &now
+		return nil
+	})
+}
+
+// DeleteTasks soft-deletes the user's tasks with the given IDs in a single write.
+// Missing, already deleted and other users' tasks are skipped; the number of deleted tasks is returned.
+func (js *JSONFileStorage) DeleteTasks(ctx context.Context, ids []int, userID int) (int, error) {
+	deleted := 0
+	err := js.update("delete_tasks", userID, func(data *jsonFileData, now time.Time) error {
+		for _, id := range ids {
+			if stored := findJSONFileTask(data.Tasks[userID], id, false); stored != nil {
+				stored.DeletedAt = &now
+				deleted++
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	return deleted, nil
+}
+
+// RestoreTask brings back a soft-deleted task, returns ErrTaskNotFound if the user owns no such deleted task.
+func (js *JSONFileStorage) RestoreTask(ctx context.Context, id int, userID int) error {
+	return js.update("restore_task", userID, func(data *jsonFileData, now time.Time) error {
+		stored := findJSONFileTask(data.Tasks[userID], id, true)
+		if stored == nil {
+			return domain.ErrTaskNotFound
+		}
+		stored.DeletedAt = nil
+		stored.Version++
+		stored.UpdatedAt = now
+		return nil
+	})
+}
+
+// ArchiveTask hides a task from default task lists, returns ErrTaskNotFound if not owned by user.
+// An already archived task keeps its original archive time.
+func (js *JSONFileStorage) ArchiveTask(ctx context.Context, id int, userID int) error {
+	return js.update("archive_task", userID, func(data *jsonFileData, now time.Time) error {
+		stored := findJSONFileTask(data.Tasks[userID], id, false)
+		if stored == nil {
+			return domain.ErrTaskNotFound
+		}
+		if stored.ArchivedAt == nil {
+			archivedAt := now
+			stored.ArchivedAt = &archivedAt
+		}
+		stored.Version++
+		stored.UpdatedAt = now
+		return nil
+	})
+}
+
+// ArchiveCompleted archives all of the user's done tasks in a single write and returns how many were archived.
+// Tasks that are already archived are not counted again.
+func (js *JSONFileStorage) ArchiveCompleted(ctx context.Context, userID int) (int, error) {
+	archived := 0
+	err := js.update("archive_completed", userID, func(data *jsonFileData, now time.Time) error {
+		tasks := data.Tasks[userID]
+		for i := range tasks {
+			if tasks[i].DeletedAt != nil || !tasks[i].Done || tasks[i].ArchivedAt != nil {
+				continue
+			}
+			archivedAt := now
+			tasks[i].ArchivedAt = &archivedAt
+			tasks[i].Version++
+			tasks[i].UpdatedAt = now
+			archived++
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	return archived, nil
+}
+
+// PurgeTask permanently removes a task by ID, deleted or not, returns ErrTaskNotFound if not owned by user.
+func (js *JSONFileStorage) PurgeTask(ctx context.Context, id int, userID int) error {
+	return js.update("purge_task", userID, func(data *jsonFileData, now time.Time) error {
+		tasks := data.Tasks[userID]
+		i := slices.IndexFunc(tasks, func(t jsonFileTask) bool { return t.ID == id })
+		if i < 0 {
+			return domain.ErrTaskNotFound
+		}
+		data.Tasks[userID] = slices.Delete(tasks, i, i+1)
+		return nil
+	})
+}
+
+// GetTaskByID retrieves a task by ID, returns ErrTaskNotFound if not owned by user.
+func (js *JSONFileStorage) GetTaskByID(ctx context.Context, id int, userID int) (domain.Task, error) {
+	js.mu.RLock()
+	defer js.mu.RUnlock()
+
+	stored := findJSONFileTask(js.data.Tasks[userID], id, false)
+	if stored == nil {
+		return domain.Task{}, domain.ErrTaskNotFound
+	}
+	return stored.Task, nil
+}
+
+// TaskExists reports whether a non-deleted task with the ID exists, regardless of its owner.
+func (js *JSONFileStorage) TaskExists(ctx context.Context, id int) (bool, error) {
+	js.mu.RLock()
+	defer js.mu.RUnlock()
+
+	for _, tasks := range js.data.Tasks {
+		if findJSONFileTask(tasks, id, false) != nil {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// TaskExistsByDescription reports whether the user has a non-deleted task with exactly this description.
+func (js *JSONFileStorage) TaskExistsByDescription(ctx context.Context, userID int, description string) (bool, error) {
+	js.mu.RLock()
+	defer js.mu.RUnlock()
+
+	for _, task := range js.data.Tasks[userID] {
+		if task.DeletedAt == nil && task.Description == description {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// LoadTasks retrieves a page of the user's tasks matching opts.Filter in the same order DatabaseStorage uses.
+// A zero opts.Limit loads all tasks starting at opts.Offset.
+func (js *JSONFileStorage) LoadTasks(ctx context.Context, userID int, opts domain.ListOptions) ([]domain.Task, error) {
+	tasks := js.activeTasks(userID, opts.Filter.Matches)
+	slices.SortFunc(tasks, compareTasks(opts.Sort))
+
+	start := min(max(opts.Offset, 0), len(tasks))
+	end := len(tasks)
+	if opts.Limit > 0 {
+		end = min(start+opts.Limit, len(tasks))
+	}
+	return tasks[start:end], nil
+}
+
+// SearchTasks returns the user's tasks whose description contains query, ignoring case, leaving out archived ones.
+func (js *JSONFileStorage) SearchTasks(ctx context.Context, userID int, query string) ([]domain.Task, error) {
+	query = strings.ToLower(query)
+	tasks := js.activeTasks(userID, func(task domain.Task) bool {
+		return task.ArchivedAt == nil && strings.Contains(strings.ToLower(task.Description), query)
+	})
+	slices.SortFunc(tasks, compareTasks(domain.TaskSort{}))
+	return tasks, nil
+}
+
+// CountTasks returns the number of tasks owned by a user that match the filter, excluding deleted ones
+// and, unless the filter includes them, archived ones.
+func (js *JSONFileStorage) CountTasks(ctx context.Context, userID int, filter domain.TaskFilter) (int, error) {
+	return len(js.activeTasks(userID, filter.Matches)), nil
+}
+
+// TaskStats returns how many of a user's tasks exist, are done and are pending, excluding deleted and archived ones.
+func (js *JSONFileStorage) TaskStats(ctx context.Context, userID int) (domain.Stats, error) {
+	js.mu.RLock()
+	defer js.mu.RUnlock()
+
+	var stats domain.Stats
+	for _, task := range js.data.Tasks[userID] {
+		if task.DeletedAt != nil || task.ArchivedAt != nil {
+			continue
+		}
+		stats.Total++
+		if task.Done {
+			stats.Done++
+		}
+	}
+	stats.Pending = stats.Total - stats.Done
+	return stats, nil
+}
+
+// Close releases the file lock. Every change is already on disk, so there is nothing to flush.
+// Closing twice is a no-op.
+func (js *JSONFileStorage) Close(ctx context.Context) error {
+	js.logger.Debug("Close JSON task file",
+		slog.String(logger.FieldOperation, "close"),
+		slog.String("path", js.path),
+	)
+
+	js.mu.Lock()
+	defer js.mu.Unlock()
+	if js.lock == nil {
+		return nil
+	}
+	err := js.lock.Close()
+	js.lock = nil
+	if err != nil {
+		return fmt.Errorf("failed to release task file lock: %w", err)
+	}
+	return nil
+}
+
+// WithTx emulates a transaction: it holds the write lock while fn changes a copy of the data,
+// then writes the file once. When fn returns an error the copy is dropped and nothing changes.
+// Called on the storage fn receives, fn joins the running transaction.
+func (js *JSONFileStorage) WithTx(ctx context.Context, fn func(tx domain.TxStorage) error) error {
+	if js.inTx {
+		return fn(js)
+	}
+	js.mu.Lock()
+	defer js.mu.Unlock()
+
+	tx := &JSONFileStorage{path: js.path, data: js.data.clone(), now: js.now, logger: js.logger, inTx: true}
+	if err := fn(tx); err != nil {
+		return err
+	}
+
+	if err := writeFileAtomic(js.path, tx.data); err != nil {
+		js.logger.Error("Failed to write JSON task file",
+			slog.String(logger.FieldOperation, "with_tx"),
+			slog.String(logger.FieldError, err.Error()),
+		)
+		return err
+	}
+	js.data = tx.data
+	return nil
+}
+
+// activeTasks returns copies of the user's non-deleted tasks that match keep.
+func (js *JSONFileStorage) activeTasks(userID int, keep func(domain.Task) bool) []domain.Task {
+	js.mu.RLock()
+	defer js.mu.RUnlock()
+
+	tasks := make([]domain.Task, 0)
+	for _, task := range js.data.Tasks[userID] {
+		if task.DeletedAt == nil && keep(task.Task) {
+			tasks = append(tasks, task.Task)
+		}
+	}
+	return tasks
+}
+
+// update applies change to a copy of the data and writes it to disk; the in-memory data
+// is replaced only when the write succeeds, so a failed write leaves both unchanged.
+func (js *JSONFileStorage) update(operation string, userID int, change func(data *jsonFileData, now time.Time) error) error {
+	js.mu.Lock()
+	defer js.mu.Unlock()
+
+	next := js.data.clone()
+	if err := change(&next, js.now().UTC().Truncate(time.Second)); err != nil {
+		return err
+	}
+	if js.inTx {
+		js.data = next
+		return nil
+	}
+
+	if err := writeFileAtomic(js.path, next); err != nil {
+		js.logger.Error("Failed to write JSON task file",
+			slog.String(logger.FieldOperation, operation),
+			slog.Int(logger.FieldUserID, userID),
+			slog.String(logger.FieldError, err.Error()),
+		)
+		return err
+	}
+	js.data = next
+	return nil
+}
+
+// clone copies the task map and slices so changes to the copy do not affect data.
+func (data jsonFileData) clone() jsonFileData {
+	tasks := make(map[int][]jsonFileTask, len(data.Tasks))
+	for userID, userTasks := range data.Tasks {
+		tasks[userID] = slices.Clone(userTasks)
+	}
+	return jsonFileData{LastID: data.LastID, Tasks: tasks}
+}
+
+// writeFileAtomic writes data to a temporary file in the same directory and renames it over path,
+// so a crash leaves either the old or the new file and never a half-written one.
+func writeFileAtomic(path string, data jsonFileData) error {
+	content, err := json.MarshalIndent(data, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode tasks: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".tmp-*")
+	if err != nil {
+		return fmt.Errorf("failed to create temporary task file: %w", err)
+	}
+	defer os.Remove(tmp.Name()) // no-op once renamed
+
+	if _, err := tmp.Write(content); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write temporary task file: %w", err)
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to sync temporary task file: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to close temporary task file: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf("failed to replace task file: %w", err)
+	}
+	return syncDir(filepath.Dir(path))
+}
+
+// syncDir flushes a directory entry change such as a rename, so it survives a crash.
+func syncDir(dir string) error {
+	d, err := os.Open(dir)
+	if err != nil {
+		return fmt.Errorf("failed to open task file directory: %w", err)
+	}
+	defer d.Close()
+	if err := d.Sync(); err != nil {
+		return fmt.Errorf("failed to sync task file directory: %w", err)
+	}
+	return nil
+}
+
+// findJSONFileTask returns the task with the given ID whose deleted state matches deleted, or nil.
+func findJSONFileTask(tasks []jsonFileTask, id int, deleted bool) *jsonFileTask {
+	for i := range tasks {
+		if tasks[i].ID == id && (tasks[i].DeletedAt != nil) == deleted {
+			return &tasks[i]
+		}
+	}
+	return nil
+}
+
+// compareTasks orders tasks like orderByClause orders rows, ending with the ID to keep pages stable.
+func compareTasks(sort domain.TaskSort) func(a, b domain.Task) int {
+	direction := 1
+	if sort.Descending {
+		direction = -1
+	}
+	switch sort.Field {
+	case domain.SortByID:
+		return func(a, b domain.Task) int {
+			return direction * cmp.Compare(a.ID, b.ID)
+		}
+	case domain.SortByCreated:
+		return func(a, b domain.Task) int {
+			return direction * cmp.Or(a.CreatedAt.Compare(b.CreatedAt), cmp.Compare(a.ID, b.ID))
+		}
+	case domain.SortByUpdated:
+		return func(a, b domain.Task) int {
+			return direction * cmp.Or(a.UpdatedAt.Compare(b.UpdatedAt), cmp.Compare(a.ID, b.ID))
+		}
+	case domain.SortByDone:
+		return func(a, b domain.Task) int {
+			return cmp.Or(direction*compareBool(a.Done, b.Done), cmp.Compare(a.ID, b.ID))
+		}
+	case domain.SortByPriority:
+		return func(a, b domain.Task) int {
+			return cmp.Or(direction*cmp.Compare(a.Priority, b.Priority), cmp.Compare(a.ID, b.ID))
+		}
+	case domain.SortByDue:
+		return func(a, b domain.Task) int {
+			return cmp.Or(compareDueDates(a.DueDate, b.DueDate, direction), cmp.Compare(a.ID, b.ID))
+		}
+	default:
+		return func(a, b domain.Task) int {
+			return cmp.Or(compareBool(a.Done, b.Done), b.CreatedAt.Compare(a.CreatedAt), cmp.Compare(b.ID, a.ID))
+		}
+	}
+}
+
+// compareDueDates orders due dates by direction, keeping tasks without one last like DatabaseStorage does.
+func compareDueDates(a, b *time.Time, direction int) int {
+	switch {
+	case a == nil && b == nil:
+		return 0
+	case a == nil:
+		return 1
+	case b == nil:
+		return -1
+	default:
+		return direction * a.Compare(*b)
+	}
+}
+
+// compareBool orders false before true.
+func compareBool(a, b bool) int {
+	switch {
+	case a == b:
+		return 0
+	case !a:
+		return -1
+	default:
+		return 1
+	}
+}
+
+// utcPtr returns a copy of t in UTC, so stored timestamps never share memory with the caller.
+func utcPtr(t *time.Time) *time.Time {
+	if t == nil {
+		return nil
+	}
+	v := t.UTC()
+	return &v
+}
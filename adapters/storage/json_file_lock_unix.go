@@ -0,0 +1,28 @@
+//go:build unix
+
+package storage
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"syscall"
+)
+
+// lockFile takes an exclusive advisory lock on path+".lock", creating it when missing.
+// The lock lasts until the returned file is closed; ErrFileLocked means another
+// JSONFileStorage, in this or another process, already holds it.
+func lockFile(path string) (*os.File, error) {
+	f, err := os.OpenFile(path+".lock", os.O_RDWR|os.O_CREATE, 0o600)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open lock file: %w", err)
+	}
+	if err := syscall.Flock(int(f.Fd()), syscall.LOCK_EX|syscall.LOCK_NB); err != nil {
+		f.Close()
+		if errors.Is(err, syscall.EWOULDBLOCK) {
+			return nil, fmt.Errorf("%w: %s", ErrFileLocked, path)
+		}
+		return nil, fmt.Errorf("failed to lock task file: %w", err)
+	}
+	return f, nil
+}
@@ -0,0 +1,564 @@
+package storage
+
+import (
+	"context"
+	"myproject/domain"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func setupJSONFileStore(t *testing.T) (*JSONFileStorage, string) {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "tasks.json")
+	store, err := NewJSONFileStorage(path, dummyLogger)
+	require.NoError(t, err)
+	t.Cleanup(func() { store.Close(context.Background()) })
+	return store, path
+}
+
+func TestJSONFileStorage_TaskLifecycle(t *testing.T) {
+	ctx := context.Background()
+	const userID, otherUserID = 1, 2
+
+	t.Run("creates, reads and updates a task", func(t *testing.T) {
+		store, _ := setupJSONFileStore(t)
+
+		id, err := store.CreateTask(ctx, domain.Task{Description: "task 1"}, userID)
+		require.NoError(t, err)
+		assert.Equal(t, 1, id)
+
+		task, err := store.GetTaskByID(ctx, id, userID)
+		require.NoError(t, err)
+		assert.Equal(t, "task 1", task.Description)
+		assert.False(t, task.CreatedAt.IsZero())
+
+		task.Description = "task 1 updated"
+		task.Done = true
+		require.NoError(t, store.UpdateTask(ctx, task, userID))
+
+		task, err = store.GetTaskByID(ctx, id, userID)
+		require.NoError(t, err)
+		assert.Equal(t, "task 1 updated", task.Description)
+		assert.True(t, task.Done)
+		assert.Equal(t, 1, task.Version)
+	})
+	t.Run("rejects an update of a stale version", func(t *testing.T) {
+		store, _ := setupJSONFileStore(t)
+		id, err := store.CreateTask(ctx, domain.Task{Description: "task 1"}, userID)
+		require.NoError(t, err)
+		stale, err := store.GetTaskByID(ctx, id, userID)
+		require.NoError(t, err)
+
+		_, err = store.ToggleTaskDone(ctx, id, userID)
+		require.NoError(t, err)
+
+		stale.Description = "task 1 updated"
+		assert.ErrorIs(t, store.UpdateTask(ctx, stale, userID), domain.ErrVersionConflict)
+
+		task, err := store.GetTaskByID(ctx, id, userID)
+		require.NoError(t, err)
+		assert.Equal(t, "task 1", task.Description)
+	})
+	t.Run("hides tasks of other users", func(t *testing.T) {
+		store, _ := setupJSONFileStore(t)
+		id, err := store.CreateTask(ctx, domain.Task{Description: "task 1"}, userID)
+		require.NoError(t, err)
+
+		_, err = store.GetTaskByID(ctx, id, otherUserID)
+		assert.ErrorIs(t, err, domain.ErrTaskNotFound)
+		assert.ErrorIs(t, store.UpdateTask(ctx, domain.Task{ID: id}, otherUserID), domain.ErrTaskNotFound)
+		assert.ErrorIs(t, store.DeleteTask(ctx, id, otherUserID), domain.ErrTaskNotFound)
+		assert.ErrorIs(t, store.PurgeTask(ctx, id, otherUserID), domain.ErrTaskNotFound)
+
+		exists, err := store.TaskExists(ctx, id)
+		require.NoError(t, err)
+		assert.True(t, exists, "existence is checked across all users")
+		exists, err = store.TaskExists(ctx, id+1)
+		require.NoError(t, err)
+		assert.False(t, exists)
+
+		exists, err = store.TaskExistsByDescription(ctx, userID, "task 1")
+		require.NoError(t, err)
+		assert.True(t, exists)
+		exists, err = store.TaskExistsByDescription(ctx, otherUserID, "task 1")
+		require.NoError(t, err)
+		assert.False(t, exists, "descriptions are checked per user")
+	})
+	t.Run("soft-deletes, restores and purges a task", func(t *testing.T) {
+		store, _ := setupJSONFileStore(t)
+		id, err := store.CreateTask(ctx, domain.Task{Description: "task 1"}, userID)
+		require.NoError(t, err)
+
+		require.NoError(t, store.DeleteTask(ctx, id, userID))
+		_, err = store.GetTaskByID(ctx, id, userID)
+		assert.ErrorIs(t, err, domain.ErrTaskNotFound)
+		count, err := store.CountTasks(ctx, userID, domain.TaskFilter{})
+		require.NoError(t, err)
+		assert.Zero(t, count)
+		stats, err := store.TaskStats(ctx, userID)
+		require.NoError(t, err)
+		assert.Equal(t, domain.Stats{}, stats)
+		assert.ErrorIs(t, store.UpdateTask(ctx, domain.Task{ID: id}, userID), domain.ErrTaskNotFound)
+
+		require.NoError(t, store.RestoreTask(ctx, id, userID))
+		assert.ErrorIs(t, store.RestoreTask(ctx, id, userID), domain.ErrTaskNotFound, "only deleted tasks can be restored")
+		restored, err := store.GetTaskByID(ctx, id, userID)
+		assert.NoError(t, err)
+		assert.Equal(t, 1, restored.Version, "restoring bumps the version")
+
+		require.NoError(t, store.PurgeTask(ctx, id, userID))
+		assert.ErrorIs(t, store.RestoreTask(ctx, id, userID), domain.ErrTaskNotFound)
+	})
+	t.Run("marks all tasks of a user done", func(t *testing.T) {
+		store, _ := setupJSONFileStore(t)
+		openID, err := store.CreateTask(ctx, domain.Task{Description: "task 1"}, userID)
+		require.NoError(t, err)
+		_, err = store.CreateTask(ctx, domain.Task{Description: "task 2", Done: true}, userID)
+		require.NoError(t, err)
+		foreign, err := store.CreateTask(ctx, domain.Task{Description: "task 3"}, otherUserID)
+		require.NoError(t, err)
+
+		updated, err := store.UpdateAllTaskStatus(ctx, userID, true)
+		require.NoError(t, err)
+		assert.Equal(t, 1, updated)
+
+		task, err := store.GetTaskByID(ctx, openID, userID)
+		require.NoError(t, err)
+		assert.True(t, task.Done)
+		assert.NotNil(t, task.CompletedAt)
+		task, err = store.GetTaskByID(ctx, foreign, otherUserID)
+		require.NoError(t, err)
+		assert.False(t, task.Done)
+
+		updated, err = store.UpdateAllTaskStatus(ctx, userID, false)
+		require.NoError(t, err)
+		assert.Equal(t, 2, updated)
+	})
+	t.Run("toggles the status of a task", func(t *testing.T) {
+		store, _ := setupJSONFileStore(t)
+		id, err := store.CreateTask(ctx, domain.Task{Description: "task 1"}, userID)
+		require.NoError(t, err)
+
+		task, err := store.ToggleTaskDone(ctx, id, userID)
+		require.NoError(t, err)
+		assert.True(t, task.Done)
+		assert.NotNil(t, task.CompletedAt)
+
+		task, err = store.ToggleTaskDone(ctx, id, userID)
+		require.NoError(t, err)
+		assert.False(t, task.Done)
+		assert.Nil(t, task.CompletedAt)
+
+		_, err = store.ToggleTaskDone(ctx, id, otherUserID)
+		assert.ErrorIs(t, err, domain.ErrTaskNotFound)
+	})
+	t.Run("batch-deletes owned tasks only", func(t *testing.T) {
+		store, _ := setupJSONFileStore(t)
+		first, err := store.CreateTask(ctx, domain.Task{Description: "task 1"}, userID)
+		require.NoError(t, err)
+		foreign, err := store.CreateTask(ctx, domain.Task{Description: "task 2"}, otherUserID)
+		require.NoError(t, err)
+
+		deleted, err := store.DeleteTasks(ctx, []int{first, foreign, first + 100}, userID)
+		require.NoError(t, err)
+		assert.Equal(t, 1, deleted)
+
+		_, err = store.GetTaskByID(ctx, first, userID)
+		assert.ErrorIs(t, err, domain.ErrTaskNotFound)
+		_, err = store.GetTaskByID(ctx, foreign, otherUserID)
+		assert.NoError(t, err)
+	})
+	t.Run("batch-updates tasks all or nothing", func(t *testing.T) {
+		store, _ := setupJSONFileStore(t)
+		ids, err := store.CreateTasks(ctx, []domain.Task{{Description: "task 1"}, {Description: "task 2"}}, userID)
+		require.NoError(t, err)
+		first, err := store.GetTaskByID(ctx, ids[0], userID)
+		require.NoError(t, err)
+		first.Description = "task 1 updated"
+
+		err = store.UpdateTasks(ctx, []domain.Task{first, {ID: ids[1] + 100}}, userID)
+		assert.ErrorIs(t, err, domain.ErrTaskNotFound)
+		unchanged, err := store.GetTaskByID(ctx, ids[0], userID)
+		require.NoError(t, err)
+		assert.Equal(t, "task 1", unchanged.Description)
+
+		second, err := store.GetTaskByID(ctx, ids[1], userID)
+		require.NoError(t, err)
+		second.Done = true
+		require.NoError(t, store.UpdateTasks(ctx, []domain.Task{first, second}, userID))
+
+		tasks, err := store.LoadTasks(ctx, userID, domain.ListOptions{Sort: domain.TaskSort{Field: domain.SortByID}})
+		require.NoError(t, err)
+		assert.Equal(t, "task 1 updated", tasks[0].Description)
+		assert.True(t, tasks[1].Done)
+		assert.Equal(t, 1, tasks[1].Version)
+	})
+	t.Run("tags tasks and loads them by tag", func(t *testing.T) {
+		store, _ := setupJSONFileStore(t)
+		tagged, err := store.CreateTask(ctx, domain.Task{Description: "task 1", Tags: []string{"work"}}, userID)
+		require.NoError(t, err)
+		other, err := store.CreateTask(ctx, domain.Task{Description: "task 2"}, userID)
+		require.NoError(t, err)
+		_, err = store.CreateTask(ctx, domain.Task{Description: "task 3", Tags: []string{"work"}}, otherUserID)
+		require.NoError(t, err)
+
+		require.NoError(t, store.AddTag(ctx, other, "work", userID))
+		require.NoError(t, store.AddTag(ctx, other, "home", userID))
+		require.NoError(t, store.AddTag(ctx, other, "home", userID))
+		task, err := store.GetTaskByID(ctx, other, userID)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"home", "work"}, task.Tags)
+
+		byTag := domain.ListOptions{Filter: domain.TaskFilter{Tag: "work"}}
+		tasks, err := store.LoadTasks(ctx, userID, byTag)
+		require.NoError(t, err)
+		require.Len(t, tasks, 2)
+		assert.Equal(t, other, tasks[0].ID, "newest first")
+		assert.Equal(t, tagged, tasks[1].ID)
+
+		require.NoError(t, store.RemoveTag(ctx, other, "work", userID))
+		tasks, err = store.LoadTasks(ctx, userID, byTag)
+		require.NoError(t, err)
+		require.Len(t, tasks, 1)
+		assert.Equal(t, tagged, tasks[0].ID)
+
+		assert.ErrorIs(t, store.AddTag(ctx, tagged, "home", otherUserID), domain.ErrTaskNotFound)
+		assert.ErrorIs(t, store.RemoveTag(ctx, tagged, "work", otherUserID), domain.ErrTaskNotFound)
+	})
+	t.Run("archives tasks out of the default list", func(t *testing.T) {
+		store, _ := setupJSONFileStore(t)
+		archived, err := store.CreateTask(ctx, domain.Task{Description: "task 1"}, userID)
+		require.NoError(t, err)
+		done, err := store.CreateTask(ctx, domain.Task{Description: "task 2", Done: true}, userID)
+		require.NoError(t, err)
+		_, err = store.CreateTask(ctx, domain.Task{Description: "task 3"}, userID)
+		require.NoError(t, err)
+
+		require.NoError(t, store.ArchiveTask(ctx, archived, userID))
+		assert.ErrorIs(t, store.ArchiveTask(ctx, archived, otherUserID), domain.ErrTaskNotFound)
+		count, err := store.ArchiveCompleted(ctx, userID)
+		require.NoError(t, err)
+		assert.Equal(t, 1, count)
+
+		tasks, err := store.LoadTasks(ctx, userID, domain.ListOptions{})
+		require.NoError(t, err)
+		require.Len(t, tasks, 1)
+		assert.Equal(t, "task 3", tasks[0].Description)
+		total, err := store.CountTasks(ctx, userID, domain.TaskFilter{IncludeArchived: true})
+		require.NoError(t, err)
+		assert.Equal(t, 3, total)
+		stats, err := store.TaskStats(ctx, userID)
+		require.NoError(t, err)
+		assert.Equal(t, domain.Stats{Total: 1, Pending: 1}, stats, "stats leave out archived tasks")
+		found, err := store.SearchTasks(ctx, userID, "task")
+		require.NoError(t, err)
+		require.Len(t, found, 1, "search leaves out archived tasks")
+		assert.Equal(t, "task 3", found[0].Description)
+
+		task, err := store.GetTaskByID(ctx, done, userID)
+		require.NoError(t, err)
+		assert.NotNil(t, task.ArchivedAt)
+		assert.Equal(t, 1, task.Version, "archiving bumps the version, so the ETag changes")
+		task, err = store.GetTaskByID(ctx, archived, userID)
+		require.NoError(t, err)
+		assert.Equal(t, 1, task.Version)
+	})
+}
+
+func TestJSONFileStorage_LoadTasks(t *testing.T) {
+	ctx := context.Background()
+	store, _ := setupJSONFileStore(t)
+	const userID = 1
+
+	tasks := []domain.Task{
+		{ID: 1, Description: "task 1", Done: false, Priority: domain.PriorityMedium},
+		{ID: 2, Description: "task 2", Done: false, Priority: domain.PriorityHigh},
+		{ID: 3, Description: "task 3", Done: true},
+	}
+	for _, task := range tasks {
+		_, err := store.CreateTask(ctx, task, userID)
+		require.NoError(t, err)
+	}
+
+	t.Run("orders open tasks first, newest first", func(t *testing.T) {
+		loaded, err := store.LoadTasks(ctx, userID, domain.ListOptions{})
+		require.NoError(t, err)
+		assert.Equal(t, []domain.Task{tasks[1], tasks[0], tasks[2]}, withoutTimestamps(t, loaded))
+	})
+	t.Run("returns requested page", func(t *testing.T) {
+		loaded, err := store.LoadTasks(ctx, userID, domain.ListOptions{Limit: 1, Offset: 1, Sort: domain.TaskSort{Field: domain.SortByID}})
+		require.NoError(t, err)
+		assert.Equal(t, tasks[1:2], withoutTimestamps(t, loaded))
+
+		loaded, err = store.LoadTasks(ctx, userID, domain.ListOptions{Limit: 10, Offset: 3})
+		require.NoError(t, err)
+		assert.Empty(t, loaded)
+	})
+	t.Run("orders by requested field", func(t *testing.T) {
+		byIDDesc, err := store.LoadTasks(ctx, userID, domain.ListOptions{Sort: domain.TaskSort{Field: domain.SortByID, Descending: true}})
+		require.NoError(t, err)
+		assert.Equal(t, []domain.Task{tasks[2], tasks[1], tasks[0]}, withoutTimestamps(t, byIDDesc))
+
+		byDoneDesc, err := store.LoadTasks(ctx, userID, domain.ListOptions{Sort: domain.TaskSort{Field: domain.SortByDone, Descending: true}})
+		require.NoError(t, err)
+		assert.Equal(t, []domain.Task{tasks[2], tasks[0], tasks[1]}, withoutTimestamps(t, byDoneDesc))
+
+		byPriorityDesc, err := store.LoadTasks(ctx, userID, domain.ListOptions{Sort: domain.TaskSort{Field: domain.SortByPriority, Descending: true}})
+		require.NoError(t, err)
+		assert.Equal(t, []domain.Task{tasks[1], tasks[0], tasks[2]}, withoutTimestamps(t, byPriorityDesc))
+	})
+	t.Run("counts done and pending tasks", func(t *testing.T) {
+		stats, err := store.TaskStats(ctx, userID)
+		require.NoError(t, err)
+		assert.Equal(t, domain.Stats{Total: 3, Done: 1, Pending: 2}, stats)
+	})
+	t.Run("filters by creation time", func(t *testing.T) {
+		past := time.Now().Add(-time.Hour)
+		future := time.Now().Add(time.Hour)
+
+		loaded, err := store.LoadTasks(ctx, userID, domain.ListOptions{Filter: domain.TaskFilter{CreatedAfter: &past, CreatedBefore: &future}})
+		require.NoError(t, err)
+		assert.Len(t, loaded, 3)
+
+		loaded, err = store.LoadTasks(ctx, userID, domain.ListOptions{Filter: domain.TaskFilter{CreatedAfter: &future}})
+		require.NoError(t, err)
+		assert.Empty(t, loaded)
+
+		count, err := store.CountTasks(ctx, userID, domain.TaskFilter{CreatedBefore: &past})
+		require.NoError(t, err)
+		assert.Equal(t, 0, count)
+	})
+	t.Run("searches case-insensitively", func(t *testing.T) {
+		found, err := store.SearchTasks(ctx, userID, "TASK 3")
+		require.NoError(t, err)
+		assert.Equal(t, []domain.Task{tasks[2]}, withoutTimestamps(t, found))
+	})
+	t.Run("returns empty results for another user", func(t *testing.T) {
+		loaded, err := store.LoadTasks(ctx, 2, domain.ListOptions{})
+		require.NoError(t, err)
+		assert.NotNil(t, loaded)
+		assert.Empty(t, loaded)
+	})
+}
+
+func TestJSONFileStorage_LoadTasks_Overdue(t *testing.T) {
+	ctx := context.Background()
+	store, _ := setupJSONFileStore(t)
+	const userID = 1
+
+	now := time.Date(2030, 6, 15, 12, 0, 0, 0, time.UTC)
+	at := func(d time.Duration) *time.Time {
+		v := now.Add(d)
+		return &v
+	}
+	create := func(task domain.Task) int {
+		t.Helper()
+		id, err := store.CreateTask(ctx, task, userID)
+		require.NoError(t, err)
+		return id
+	}
+	anHourAgo := create(domain.Task{Description: "an hour ago", DueDate: at(-time.Hour)})
+	lastWeek := create(domain.Task{Description: "last week", DueDate: at(-7 * 24 * time.Hour)})
+	create(domain.Task{Description: "tomorrow", DueDate: at(24 * time.Hour)})
+	create(domain.Task{Description: "no due date"})
+	create(domain.Task{Description: "done", Done: true, DueDate: at(-time.Hour)})
+	deleted := create(domain.Task{Description: "deleted", DueDate: at(-time.Hour)})
+	require.NoError(t, store.DeleteTask(ctx, deleted, userID))
+
+	overdue := domain.TaskFilter{OverdueAt: &now}
+	tasks, err := store.LoadTasks(ctx, userID, domain.ListOptions{Sort: domain.TaskSort{Field: domain.SortByDue}, Filter: overdue})
+	require.NoError(t, err)
+
+	ids := make([]int, len(tasks))
+	for i, task := range tasks {
+		ids[i] = task.ID
+	}
+	assert.Equal(t, []int{lastWeek, anHourAgo}, ids)
+
+	count, err := store.CountTasks(ctx, userID, overdue)
+	require.NoError(t, err)
+	assert.Equal(t, 2, count)
+}
+
+func TestJSONFileStorage_Persistence(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("reloads tasks from the file", func(t *testing.T) {
+		store, path := setupJSONFileStore(t)
+		due := time.Date(2030, 1, 2, 3, 4, 5, 0, time.UTC)
+		id, err := store.CreateTask(ctx, domain.Task{Description: "persisted", DueDate: &due}, 1)
+		require.NoError(t, err)
+		deleted, err := store.CreateTask(ctx, domain.Task{Description: "deleted"}, 1)
+		require.NoError(t, err)
+		require.NoError(t, store.DeleteTask(ctx, deleted, 1))
+		require.NoError(t, store.Close(ctx))
+
+		reopened, err := NewJSONFileStorage(path, dummyLogger)
+		require.NoError(t, err)
+
+		task, err := reopened.GetTaskByID(ctx, id, 1)
+		require.NoError(t, err)
+		assert.Equal(t, "persisted", task.Description)
+		assert.True(t, due.Equal(*task.DueDate))
+		require.NoError(t, reopened.RestoreTask(ctx, deleted, 1), "soft-deleted state survives a reload")
+
+		next, err := reopened.CreateTask(ctx, domain.Task{Description: "next"}, 2)
+		require.NoError(t, err)
+		assert.Equal(t, deleted+1, next, "IDs keep increasing across reloads")
+	})
+	t.Run("leaves no temporary files behind", func(t *testing.T) {
+		store, path := setupJSONFileStore(t)
+		_, err := store.CreateTask(ctx, domain.Task{Description: "task"}, 1)
+		require.NoError(t, err)
+
+		entries, err := os.ReadDir(filepath.Dir(path))
+		require.NoError(t, err)
+		require.Len(t, entries, 2)
+		assert.Equal(t, "tasks.json", entries[0].Name())
+		assert.Equal(t, "tasks.json.lock", entries[1].Name())
+	})
+	t.Run("keeps previous state when the write fails", func(t *testing.T) {
+		dir := filepath.Join(t.TempDir(), "data")
+		require.NoError(t, os.Mkdir(dir, 0o700))
+		store, err := NewJSONFileStorage(filepath.Join(dir, "tasks.json"), dummyLogger)
+		require.NoError(t, err)
+		id, err := store.CreateTask(ctx, domain.Task{Description: "task"}, 1)
+		require.NoError(t, err)
+
+		require.NoError(t, os.RemoveAll(dir))
+		_, err = store.CreateTask(ctx, domain.Task{Description: "lost"}, 1)
+		assert.Error(t, err)
+		assert.Error(t, store.DeleteTask(ctx, id, 1))
+
+		count, err := store.CountTasks(ctx, 1, domain.TaskFilter{})
+		require.NoError(t, err)
+		assert.Equal(t, 1, count)
+	})
+	t.Run("leaves the target untouched when replacing it fails", func(t *testing.T) {
+		dir := t.TempDir()
+		// A non-empty directory cannot be renamed over, so the write fails after the temporary file is complete
+		path := filepath.Join(dir, "tasks.json")
+		require.NoError(t, os.Mkdir(path, 0o700))
+		require.NoError(t, os.WriteFile(filepath.Join(path, "keep"), []byte("original"), 0o600))
+
+		err := writeFileAtomic(path, jsonFileData{Tasks: map[int][]jsonFileTask{}})
+		assert.Error(t, err)
+
+		content, err := os.ReadFile(filepath.Join(path, "keep"))
+		require.NoError(t, err)
+		assert.Equal(t, "original", string(content))
+		entries, err := os.ReadDir(dir)
+		require.NoError(t, err)
+		require.Len(t, entries, 1, "the temporary file is removed")
+	})
+	t.Run("rejects a corrupt file", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "tasks.json")
+		require.NoError(t, os.WriteFile(path, []byte("{not json"), 0o600))
+
+		_, err := NewJSONFileStorage(path, dummyLogger)
+		assert.Error(t, err)
+
+		require.NoError(t, os.WriteFile(path, []byte("{}"), 0o600))
+		store, err := NewJSONFileStorage(path, dummyLogger)
+		require.NoError(t, err, "a failed open releases the lock")
+		require.NoError(t, store.Close(ctx))
+	})
+}
+
+func TestJSONFileStorage_Lock(t *testing.T) {
+	ctx := context.Background()
+	store, path := setupJSONFileStore(t)
+
+	_, err := NewJSONFileStorage(path, dummyLogger)
+	assert.ErrorIs(t, err, ErrFileLocked)
+
+	require.NoError(t, store.Close(ctx))
+	require.NoError(t, store.Close(ctx), "closing twice is a no-op")
+	reopened, err := NewJSONFileStorage(path, dummyLogger)
+	require.NoError(t, err)
+	require.NoError(t, reopened.Close(ctx))
+}
+
+func TestJSONFileStorage_WithTx(t *testing.T) {
+	ctx := context.Background()
+	const userID = 1
+
+	t.Run("writes all steps at once", func(t *testing.T) {
+		store, path := setupJSONFileStore(t)
+
+		err := store.WithTx(ctx, func(tx domain.TxStorage) error {
+			id, err := tx.CreateTask(ctx, domain.Task{Description: "task 1"}, userID)
+			if err != nil {
+				return err
+			}
+			return tx.AddTag(ctx, id, "work", userID)
+		})
+		require.NoError(t, err)
+		require.NoError(t, store.Close(ctx))
+
+		reloaded, err := NewJSONFileStorage(path, dummyLogger)
+		require.NoError(t, err)
+		tasks, err := reloaded.LoadTasks(ctx, userID, domain.ListOptions{Filter: domain.TaskFilter{Tag: "work"}})
+		require.NoError(t, err)
+		require.Len(t, tasks, 1)
+		assert.Equal(t, "task 1", tasks[0].Description)
+	})
+
+	t.Run("a failing second step rolls back the first", func(t *testing.T) {
+		store, path := setupJSONFileStore(t)
+
+		err := store.WithTx(ctx, func(tx domain.TxStorage) error {
+			id, err := tx.CreateTask(ctx, domain.Task{Description: "task 1"}, userID)
+			if err != nil {
+				return err
+			}
+			return tx.DeleteTask(ctx, id+100, userID)
+		})
+		assert.ErrorIs(t, err, domain.ErrTaskNotFound)
+
+		count, err := store.CountTasks(ctx, userID, domain.TaskFilter{})
+		require.NoError(t, err)
+		assert.Zero(t, count)
+		_, err = os.Stat(path)
+		assert.ErrorIs(t, err, os.ErrNotExist)
+	})
+}
+
+func TestJSONFileStorage_Concurrent(t *testing.T) {
+	ctx := context.Background()
+	store, path := setupJSONFileStore(t)
+
+	const workers, perWorker = 8, 10
+	var wg sync.WaitGroup
+	for w := range workers {
+		wg.Add(1)
+		go func(userID int) {
+			defer wg.Done()
+			for range perWorker {
+				id, err := store.CreateTask(ctx, domain.Task{Description: "task"}, userID)
+				assert.NoError(t, err)
+				_, err = store.LoadTasks(ctx, userID, domain.ListOptions{})
+				assert.NoError(t, err)
+				assert.NoError(t, store.UpdateTask(ctx, domain.Task{ID: id, Description: "updated", Done: true}, userID))
+			}
+		}(w%2 + 1)
+	}
+	wg.Wait()
+	require.NoError(t, store.Close(ctx))
+
+	reopened, err := NewJSONFileStorage(path, dummyLogger)
+	require.NoError(t, err)
+	total := 0
+	for _, userID := range []int{1, 2} {
+		count, err := reopened.CountTasks(ctx, userID, domain.TaskFilter{})
+		require.NoError(t, err)
+		total += count
+	}
+	assert.Equal(t, workers*perWorker, total)
+}
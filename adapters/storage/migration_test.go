@@ -7,6 +7,7 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 var dummyLogger = slog.New(slog.NewTextHandler(io.Discard, nil))
@@ -47,3 +48,181 @@ func TestNewMigratorWithDefaults(t *testing.T) {
 		assert.True(t, count == 0, "Tasks should be deleted automatically by cascade")
 	})
 }
+
+func TestMigratorRollback(t *testing.T) {
+	newStore := func(t *testing.T) *DatabaseStorage {
+		t.Helper()
+		store, err := NewDatabaseStorage(filepath.Join(t.TempDir(), "test.db"), dummyLogger)
+		require.NoError(t, err)
+		t.Cleanup(func() { store.db.Close() })
+		return store
+	}
+
+	t.Run("rolls back to version 1", func(t *testing.T) {
+		store := newStore(t)
+		migrator := NewMigratorWithDefaults(store.db)
+
+		res, err := store.db.Exec(`INSERT INTO users(email, password_hash) VALUES (?, ?)`, "test@email.com", "hash")
+		require.NoError(t, err)
+		userID, err := res.LastInsertId()
+		require.NoError(t, err)
+		_, err = store.db.Exec(`INSERT INTO tasks(user_id, description) VALUES (?, ?)`, userID, "task 1")
+		require.NoError(t, err)
+
+		require.NoError(t, migrator.RollbackTo(1))
+
+		version, err := migrator.GetCurrentVersion()
+		require.NoError(t, err)
+		assert.Equal(t, 1, version)
+		assert.Equal(t, []string{"id", "description", "done", "created_at", "updated_at"}, tableColumns(t, store, "tasks"))
+		assert.Empty(t, tableColumns(t, store, "users"), "users table should be dropped")
+
+		var description string
+		require.NoError(t, store.db.QueryRow(`SELECT description FROM tasks`).Scan(&description))
+		assert.Equal(t, "task 1", description)
+
+		require.NoError(t, migrator.ApplyMigrations(), "migrations should apply again after rollback")
+		version, err = migrator.GetCurrentVersion()
+		require.NoError(t, err)
+		assert.Equal(t, 12, version)
+	})
+
+	t.Run("rolls back the latest migration", func(t *testing.T) {
+		store := newStore(t)
+		migrator := NewMigratorWithDefaults(store.db)
+
+		require.NoError(t, migrator.RollbackMigration())
+
+		version, err := migrator.GetCurrentVersion()
+		require.NoError(t, err)
+		assert.Equal(t, 11, version)
+		assert.NotContains(t, tableColumns(t, store, "tasks"), "archived_at")
+		assert.Contains(t, tableColumns(t, store, "users"), "role")
+	})
+
+	t.Run("rolls back every migration", func(t *testing.T) {
+		store := newStore(t)
+		migrator := NewMigratorWithDefaults(store.db)
+
+		require.NoError(t, migrator.RollbackTo(0))
+		assert.Empty(t, tableColumns(t, store, "tasks"))
+		assert.ErrorIs(t, migrator.RollbackMigration(), ErrNoMigrationsApplied)
+	})
+
+	t.Run("rejects a version above the current one", func(t *testing.T) {
+		store := newStore(t)
+		migrator := NewMigratorWithDefaults(store.db)
+
+		assert.Error(t, migrator.RollbackTo(13))
+		assert.Error(t, migrator.RollbackTo(-1))
+
+		version, err := migrator.GetCurrentVersion()
+		require.NoError(t, err)
+		assert.Equal(t, 12, version)
+	})
+}
+
+// tableColumns returns the column names of table in definition order, or none if it does not exist.
+func tableColumns(t *testing.T, store *DatabaseStorage, table string) []string {
+	t.Helper()
+	rows, err := store.db.Query(`SELECT name FROM pragma_table_info(?)`, table)
+	require.NoError(t, err)
+	defer rows.Close()
+
+	var columns []string
+	for rows.Next() {
+		var name string
+		require.NoError(t, rows.Scan(&name))
+		columns = append(columns, name)
+	}
+	require.NoError(t, rows.Err())
+	return columns
+}
+
+func TestMigratorStatus(t *testing.T) {
+	t.Run("reports applied and pending migrations", func(t *testing.T) {
+		store, err := NewDatabaseStorage(filepath.Join(t.TempDir(), "test.db"), dummyLogger)
+		require.NoError(t, err)
+		t.Cleanup(func() { store.db.Close() })
+
+		migrator := NewMigratorWithDefaults(store.db)
+		require.NoError(t, migrator.RollbackTo(5))
+
+		statuses, err := migrator.Status()
+		require.NoError(t, err)
+		require.Len(t, statuses, 12)
+
+		for _, status := range statuses {
+			if status.Version <= 5 {
+				assert.True(t, status.Applied, "migration %d should be applied", status.Version)
+				assert.NotNil(t, status.AppliedAt)
+			} else {
+				assert.False(t, status.Applied, "migration %d should be pending", status.Version)
+				assert.Nil(t, status.AppliedAt)
+			}
+		}
+		assert.Equal(t, "create_tasks_table", statuses[0].Name)
+	})
+
+	t.Run("does not modify an empty database", func(t *testing.T) {
+		db, err := CreateConnection(&ConnectionConfig{MaxOpenConns: 1}, filepath.Join(t.TempDir(), "empty.db"))
+		require.NoError(t, err)
+		t.Cleanup(func() { db.Close() })
+
+		statuses, err := NewMigratorWithDefaults(db).Status()
+		require.NoError(t, err)
+		for _, status := range statuses {
+			assert.False(t, status.Applied)
+		}
+
+		var tables int
+		require.NoError(t, db.QueryRow("SELECT COUNT(*) FROM sqlite_master WHERE type = 'table'").Scan(&tables))
+		assert.Zero(t, tables, "status should not create any tables")
+	})
+}
+
+func TestMigratorPlanMigrations(t *testing.T) {
+	t.Run("plans every migration for an empty database without applying any", func(t *testing.T) {
+		db, err := CreateConnection(&ConnectionConfig{MaxOpenConns: 1}, filepath.Join(t.TempDir(), "empty.db"))
+		require.NoError(t, err)
+		t.Cleanup(func() { db.Close() })
+		migrator := NewMigratorWithDefaults(db)
+
+		plan, err := migrator.PlanMigrations()
+
+		require.NoError(t, err)
+		assert.Equal(t, migrator.migrations, plan)
+
+		var tables int
+		require.NoError(t, db.QueryRow("SELECT COUNT(*) FROM sqlite_master WHERE type = 'table'").Scan(&tables))
+		assert.Zero(t, tables, "planning should not create any tables")
+	})
+
+	t.Run("plans only the migrations after the current version", func(t *testing.T) {
+		store, err := NewDatabaseStorage(filepath.Join(t.TempDir(), "test.db"), dummyLogger)
+		require.NoError(t, err)
+		t.Cleanup(func() { store.db.Close() })
+
+		migrator := NewMigratorWithDefaults(store.db)
+		require.NoError(t, migrator.RollbackTo(8))
+
+		plan, err := migrator.PlanMigrations()
+
+		require.NoError(t, err)
+		require.Len(t, plan, 4)
+		assert.Equal(t, 9, plan[0].Version)
+		assert.Equal(t, 12, plan[3].Version)
+		assert.NotEmpty(t, plan[0].Up)
+	})
+
+	t.Run("plans nothing when the schema is up to date", func(t *testing.T) {
+		store, err := NewDatabaseStorage(filepath.Join(t.TempDir(), "test.db"), dummyLogger)
+		require.NoError(t, err)
+		t.Cleanup(func() { store.db.Close() })
+
+		plan, err := NewMigratorWithDefaults(store.db).PlanMigrations()
+
+		require.NoError(t, err)
+		assert.Empty(t, plan)
+	})
+}
@@ -2,8 +2,14 @@ package storage
 
 import (
 	"database/sql"
+	"errors"
+	"fmt"
+	"time"
 )
 
+// ErrNoMigrationsApplied is returned when rolling back a database without applied migrations.
+var ErrNoMigrationsApplied = errors.New("no migrations applied")
+
 const (
 	createSchemaMigrationsTable = `
         CREATE TABLE IF NOT EXISTS schema_migrations (
@@ -21,6 +27,14 @@ type Migration struct {
 	Down    string // SQL for rolling back the migration
 }
 
+// MigrationStatus reports whether a registered migration has been applied to the database.
+type MigrationStatus struct {
+	Version   int
+	Name      string
+	Applied   bool
+	AppliedAt *time.Time // nil while the migration is pending
+}
+
 // Migrator manages database schema migrations and tracks applied versions.
 // It provides methods to apply, rollback, and query migration status.
 type Migrator struct {
@@ -96,6 +110,7 @@ func NewMigratorWithDefaults(db *sql.DB) *Migrator {
         `,
 		Down: `
             DROP INDEX IF EXISTS idx_tasks_user_id;
+            ALTER TABLE tasks DROP COLUMN user_id;
         `,
 	}
 
@@ -135,7 +150,8 @@ func NewMigratorWithDefaults(db *sql.DB) *Migrator {
 			updated_at DATETIME DEFAULT CURRENT_TIMESTAMP
 		);
 
-		INSERT INTO tasks_old SELECT * FROM tasks;
+		INSERT INTO tasks_old (id, description, done, user_id, created_at, updated_at)
+		SELECT id, description, done, user_id, created_at, updated_at FROM tasks;
 		DROP TABLE tasks;
 		ALTER TABLE tasks_old RENAME TO tasks;
 
@@ -147,6 +163,128 @@ func NewMigratorWithDefaults(db *sql.DB) *Migrator {
 
 	migrator.AddMigration(taskUserCleanUpMigration)
 
+	taskCompletedAtMigration := Migration{
+		Version: 5,
+		Name:    "add_tasks_completed_at",
+		Up: `
+		ALTER TABLE tasks ADD COLUMN completed_at DATETIME;
+		`,
+		Down: `
+		ALTER TABLE tasks DROP COLUMN completed_at;
+		`,
+	}
+
+	migrator.AddMigration(taskCompletedAtMigration)
+
+	taskDeletedAtMigration := Migration{
+		Version: 6,
+		Name:    "add_tasks_deleted_at",
+		Up: `
+		ALTER TABLE tasks ADD COLUMN deleted_at DATETIME;
+		`,
+		Down: `
+		ALTER TABLE tasks DROP COLUMN deleted_at;
+		`,
+	}
+
+	migrator.AddMigration(taskDeletedAtMigration)
+
+	taskDueDateMigration := Migration{
+		Version: 7,
+		Name:    "add_tasks_due_date",
+		Up: `
+		ALTER TABLE tasks ADD COLUMN due_date DATETIME;
+		`,
+		Down: `
+		ALTER TABLE tasks DROP COLUMN due_date;
+		`,
+	}
+
+	migrator.AddMigration(taskDueDateMigration)
+
+	taskPriorityMigration := Migration{
+		Version: 8,
+		Name:    "add_tasks_priority",
+		Up: `
+		ALTER TABLE tasks ADD COLUMN priority INTEGER NOT NULL DEFAULT 0;
+		`,
+		Down: `
+		ALTER TABLE tasks DROP COLUMN priority;
+		`,
+	}
+
+	migrator.AddMigration(taskPriorityMigration)
+
+	taskTagsMigration := Migration{
+		Version: 9,
+		Name:    "create_tags_tables",
+		Up: `
+		CREATE TABLE tags (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			user_id INTEGER NOT NULL,
+			name TEXT NOT NULL,
+			FOREIGN KEY(user_id) REFERENCES users(id) ON DELETE CASCADE,
+			UNIQUE(user_id, name)
+		);
+
+		CREATE TABLE task_tags (
+			task_id INTEGER NOT NULL,
+			tag_id INTEGER NOT NULL,
+			PRIMARY KEY(task_id, tag_id),
+			FOREIGN KEY(task_id) REFERENCES tasks(id) ON DELETE CASCADE,
+			FOREIGN KEY(tag_id) REFERENCES tags(id) ON DELETE CASCADE
+		);
+
+		CREATE INDEX idx_task_tags_tag_id ON task_tags(tag_id);
+		`,
+		Down: `
+		DROP INDEX IF EXISTS idx_task_tags_tag_id;
+		DROP TABLE IF EXISTS task_tags;
+		DROP TABLE IF EXISTS tags;
+		`,
+	}
+
+	migrator.AddMigration(taskTagsMigration)
+
+	taskVersionMigration := Migration{
+		Version: 10,
+		Name:    "add_tasks_version",
+		Up: `
+		ALTER TABLE tasks ADD COLUMN version INTEGER NOT NULL DEFAULT 0;
+		`,
+		Down: `
+		ALTER TABLE tasks DROP COLUMN version;
+		`,
+	}
+
+	migrator.AddMigration(taskVersionMigration)
+
+	userRoleMigration := Migration{
+		Version: 11,
+		Name:    "add_users_role",
+		Up: `
+		ALTER TABLE users ADD COLUMN role TEXT NOT NULL DEFAULT 'user';
+		`,
+		Down: `
+		ALTER TABLE users DROP COLUMN role;
+		`,
+	}
+
+	migrator.AddMigration(userRoleMigration)
+
+	taskArchivedAtMigration := Migration{
+		Version: 12,
+		Name:    "add_tasks_archived_at",
+		Up: `
+		ALTER TABLE tasks ADD COLUMN archived_at DATETIME;
+		`,
+		Down: `
+		ALTER TABLE tasks DROP COLUMN archived_at;
+		`,
+	}
+
+	migrator.AddMigration(taskArchivedAtMigration)
+
 	return migrator
 }
 
@@ -198,6 +336,143 @@ func (m *Migrator) ApplyMigrations() error {
 	return nil
 }
 
+// RollbackMigration reverts the highest applied migration by running its Down SQL
+// and removing its schema_migrations row in one transaction.
+// Returns ErrNoMigrationsApplied when there is nothing to roll back.
+func (m *Migrator) RollbackMigration() error {
+	current, err := m.GetCurrentVersion()
+	if err != nil {
+		return err
+	}
+	if current == 0 {
+		return ErrNoMigrationsApplied
+	}
+
+	migration, ok := m.findMigration(current)
+	if !ok {
+		return fmt.Errorf("migration %d is applied but not known to this version of the server", current)
+	}
+
+	tx, err := m.db.Begin()
+	if err != nil {
+		return mapSQLiteError(err)
+	}
+
+	if _, err := tx.Exec(migration.Down); err != nil {
+		tx.Rollback()
+		return fmt.Errorf("rolling back migration %d (%s): %w", migration.Version, migration.Name, mapSQLiteError(err))
+	}
+
+	if _, err := tx.Exec("DELETE FROM schema_migrations WHERE version = ?", migration.Version); err != nil {
+		tx.Rollback()
+		return mapSQLiteError(err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return mapSQLiteError(err)
+	}
+	return nil
+}
+
+// RollbackTo reverts applied migrations one at a time, newest first, until the schema is at version.
+// Rolling back to the current version does nothing; a version above it is an error.
+func (m *Migrator) RollbackTo(version int) error {
+	current, err := m.GetCurrentVersion()
+	if err != nil {
+		return err
+	}
+	if version < 0 || version > current {
+		return fmt.Errorf("cannot roll back to version %d: current version is %d", version, current)
+	}
+
+	for current > version {
+		if err := m.RollbackMigration(); err != nil {
+			return err
+		}
+		if current, err = m.GetCurrentVersion(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Status lists every registered migration with its applied state, in registration order.
+// It only reads the database: a missing schema_migrations table means nothing is applied yet.
+func (m *Migrator) Status() ([]MigrationStatus, error) {
+	var tables int
+	err := m.db.QueryRow("SELECT COUNT(*) FROM sqlite_master WHERE type = 'table' AND name = 'schema_migrations'").Scan(&tables)
+	if err != nil {
+		return nil, mapSQLiteError(err)
+	}
+
+	appliedAt := make(map[int]sql.NullTime)
+	if tables > 0 {
+		rows, err := m.db.Query("SELECT version, applied_at FROM schema_migrations")
+		if err != nil {
+			return nil, mapSQLiteError(err)
+		}
+		defer rows.Close()
+
+		for rows.Next() {
+			var version int
+			var at sql.NullTime
+			if err := rows.Scan(&version, &at); err != nil {
+				return nil, mapSQLiteError(err)
+			}
+			appliedAt[version] = at
+		}
+		if err := rows.Err(); err != nil {
+			return nil, mapSQLiteError(err)
+		}
+	}
+
+	statuses := make([]MigrationStatus, 0, len(m.migrations))
+	for _, migration := range m.migrations {
+		at, applied := appliedAt[migration.Version]
+		statuses = append(statuses, MigrationStatus{
+			Version:   migration.Version,
+			Name:      migration.Name,
+			Applied:   applied,
+			AppliedAt: timePtr(at),
+		})
+	}
+	return statuses, nil
+}
+
+// PlanMigrations returns the migrations ApplyMigrations would run, in the order it would run them.
+// Like Status it only reads the database, so it is safe to call before deciding to migrate.
+func (m *Migrator) PlanMigrations() ([]Migration, error) {
+	statuses, err := m.Status()
+	if err != nil {
+		return nil, err
+	}
+
+	current := 0
+	for _, status := range statuses {
+		if status.Applied && status.Version > current {
+			current = status.Version
+		}
+	}
+
+	var pending []Migration
+	for _, migration := range m.migrations {
+		if migration.Version > current {
+			pending = append(pending, migration)
+		}
+	}
+	return pending, nil
+}
+
+// findMigration returns the registered migration with the given version.
+func (m *Migrator) findMigration(version int) (Migration, bool) {
+	for _, migration := range m.migrations {
+		if migration.Version == version {
+			return migration, true
+		}
+	}
+	return Migration{}, false
+}
+
 // GetCurrentVersion returns the highest applied migration version from the database.
 // Returns 0 if no migrations have been applied yet.
 func (m *Migrator) GetCurrentVersion() (int, error) {
@@ -14,7 +14,7 @@ func (ds *DatabaseStorage) CreateUser(ctx context.Context, email, passwordHash s
 		slog.String(logger.FieldOperation, "create_user"),
 		slog.String(logger.FieldEmail, logger.MaskEmail(email)),
 	)
-	result, err := ds.db.ExecContext(ctx,
+	result, err := ds.conn.ExecContext(ctx,
 		"INSERT INTO users (email, password_hash, created_at) VALUES (?, ?, CURRENT_TIMESTAMP)",
 		email, passwordHash,
 	)
@@ -46,10 +46,10 @@ func (ds *DatabaseStorage) GetUserByEmail(ctx context.Context, email string) (*d
 		slog.String(logger.FieldEmail, logger.MaskEmail(email)),
 	)
 	var user domain.User
-	err := ds.db.QueryRowContext(ctx,
-		"SELECT id, email, password_hash FROM users WHERE email = ?",
+	err := ds.conn.QueryRowContext(ctx,
+		"SELECT id, email, password_hash, role FROM users WHERE email = ?",
 		email,
-	).Scan(&user.ID, &user.Email, &user.PasswordHash)
+	).Scan(&user.ID, &user.Email, &user.PasswordHash, &user.Role)
 
 	if err != nil {
 		if err == sql.ErrNoRows {
@@ -73,10 +73,10 @@ func (ds *DatabaseStorage) GetUserByID(ctx context.Context, id int) (*domain.Use
 		slog.Int(logger.FieldUserID, id),
 	)
 	var user domain.User
-	err := ds.db.QueryRowContext(ctx,
-		"SELECT id, email, password_hash FROM users WHERE id = ?",
+	err := ds.conn.QueryRowContext(ctx,
+		"SELECT id, email, password_hash, created_at, role FROM users WHERE id = ?",
 		id,
-	).Scan(&user.ID, &user.Email, &user.PasswordHash)
+	).Scan(&user.ID, &user.Email, &user.PasswordHash, &user.CreatedAt, &user.Role)
 
 	if err != nil {
 		if err == sql.ErrNoRows {
@@ -90,16 +90,80 @@ func (ds *DatabaseStorage) GetUserByID(ctx context.Context, id int) (*domain.Use
 		return nil, mapSQLiteError(err)
 	}
 
+	user.CreatedAt = user.CreatedAt.UTC()
 	return &user, nil
 }
 
+// DeleteUser removes a user, returns ErrUserNotFound if not exists.
+// The user's tasks are removed with it by the ON DELETE CASCADE foreign key.
+func (ds *DatabaseStorage) DeleteUser(ctx context.Context, id int) error {
+	ds.logger.Debug("Deleting user",
+		slog.String(logger.FieldOperation, "delete_user"),
+		slog.Int(logger.FieldUserID, id),
+	)
+	result, err := ds.conn.ExecContext(ctx, "DELETE FROM users WHERE id = ?", id)
+	if err != nil {
+		ds.logger.Error("Failed to execute database delete from users",
+			slog.String(logger.FieldOperation, "delete_user"),
+			slog.Int(logger.FieldUserID, id),
+			slog.String("error", err.Error()),
+		)
+		return mapSQLiteError(err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		ds.logger.Error("Failed to get rows affected",
+			slog.String(logger.FieldOperation, "delete_user"),
+			slog.Int(logger.FieldUserID, id),
+			slog.String("error", err.Error()),
+		)
+		return mapSQLiteError(err)
+	}
+	if rows == 0 {
+		return domain.ErrUserNotFound
+	}
+	return nil
+}
+
+// UpdatePasswordHash replaces the stored password hash, returns ErrUserNotFound if not exists.
+func (ds *DatabaseStorage) UpdatePasswordHash(ctx context.Context, id int, passwordHash string) error {
+	ds.logger.Debug("Updating password hash",
+		slog.String(logger.FieldOperation, "update_password_hash"),
+		slog.Int(logger.FieldUserID, id),
+	)
+	result, err := ds.conn.ExecContext(ctx, "UPDATE users SET password_hash = ? WHERE id = ?", passwordHash, id)
+	if err != nil {
+		ds.logger.Error("Failed to execute database update of users",
+			slog.String(logger.FieldOperation, "update_password_hash"),
+			slog.Int(logger.FieldUserID, id),
+			slog.String("error", err.Error()),
+		)
+		return mapSQLiteError(err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		ds.logger.Error("Failed to get rows affected",
+			slog.String(logger.FieldOperation, "update_password_hash"),
+			slog.Int(logger.FieldUserID, id),
+			slog.String("error", err.Error()),
+		)
+		return mapSQLiteError(err)
+	}
+	if rows == 0 {
+		return domain.ErrUserNotFound
+	}
+	return nil
+}
+
 // EmailExists checks if an email is already registered in the database.
 func (ds *DatabaseStorage) EmailExists(ctx context.Context, email string) (exists bool, err error) {
 	ds.logger.Debug("Checking email existence",
 		slog.String(logger.FieldOperation, "email_exists"),
 		slog.String(logger.FieldEmail, logger.MaskEmail(email)),
 	)
-	err = ds.db.QueryRowContext(ctx,
+	err = ds.conn.QueryRowContext(ctx,
 		"SELECT EXISTS (SELECT 1 FROM users WHERE email = ?)",
 		email,
 	).Scan(&exists)
@@ -115,3 +179,49 @@ func (ds *DatabaseStorage) EmailExists(ctx context.Context, email string) (exist
 
 	return exists, nil
 }
+
+// ListUsers returns one page of all users ordered by ID, without their password hashes.
+func (ds *DatabaseStorage) ListUsers(ctx context.Context, limit, offset int) ([]domain.User, error) {
+	ds.logger.Debug("Listing users",
+		slog.String(logger.FieldOperation, "list_users"),
+		slog.Int("limit", limit),
+		slog.Int("offset", offset),
+	)
+	if limit <= 0 {
+		limit = -1 // SQLite: no upper bound
+	}
+	rows, err := ds.conn.QueryContext(ctx,
+		"SELECT id, email, created_at, role FROM users ORDER BY id LIMIT ? OFFSET ?",
+		limit, offset,
+	)
+	if err != nil {
+		ds.logger.Error("Failed to query database select from users",
+			slog.String(logger.FieldOperation, "list_users"),
+			slog.String("error", err.Error()),
+		)
+		return nil, mapSQLiteError(err)
+	}
+	defer rows.Close()
+
+	users := make([]domain.User, 0)
+	for rows.Next() {
+		var user domain.User
+		if err := rows.Scan(&user.ID, &user.Email, &user.CreatedAt, &user.Role); err != nil {
+			ds.logger.Error("Failed to scan user row",
+				slog.String(logger.FieldOperation, "list_users"),
+				slog.String("error", err.Error()),
+			)
+			return nil, mapSQLiteError(err)
+		}
+		user.CreatedAt = user.CreatedAt.UTC()
+		users = append(users, user)
+	}
+	if err := rows.Err(); err != nil {
+		ds.logger.Error("Failed to iterate user rows",
+			slog.String(logger.FieldOperation, "list_users"),
+			slog.String("error", err.Error()),
+		)
+		return nil, mapSQLiteError(err)
+	}
+	return users, nil
+}
@@ -2,7 +2,9 @@ package storage
 
 import (
 	"context"
+	"myproject/domain"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -72,6 +74,7 @@ func TestGetUserByID(t *testing.T) {
 		assert.Equal(t, userID, user.ID)
 		assert.Equal(t, "test@email.com", user.Email)
 		assert.Equal(t, "password_hash", user.PasswordHash)
+		assert.WithinDuration(t, time.Now(), user.CreatedAt, time.Minute)
 	})
 	t.Run("fails when user not found", func(t *testing.T) {
 		store := setupTestStore(t)
@@ -103,3 +106,92 @@ func TestEmailExists(t *testing.T) {
 		assert.False(t, exists)
 	})
 }
+
+func TestDeleteUser(t *testing.T) {
+	ctx := context.Background()
+	t.Run("deletes user and cascades to their tasks", func(t *testing.T) {
+		store := setupTestStore(t)
+		userID, err := store.CreateUser(ctx, "test@email.com", "password_hash")
+		assert.NoError(t, err)
+		otherID, err := store.CreateUser(ctx, "other@email.com", "password_hash")
+		assert.NoError(t, err)
+		for _, id := range []int{userID, userID, otherID} {
+			_, err = store.CreateTask(ctx, domain.Task{Description: "task"}, id)
+			assert.NoError(t, err)
+		}
+
+		err = store.DeleteUser(ctx, userID)
+		assert.NoError(t, err)
+
+		_, err = store.GetUserByID(ctx, userID)
+		assert.ErrorIs(t, err, domain.ErrUserNotFound)
+		var remaining int
+		err = store.db.QueryRowContext(ctx, "SELECT COUNT(*) FROM tasks WHERE user_id = ?", userID).Scan(&remaining)
+		assert.NoError(t, err)
+		assert.Zero(t, remaining)
+		count, err := store.CountTasks(ctx, otherID, domain.TaskFilter{})
+		assert.NoError(t, err)
+		assert.Equal(t, 1, count)
+	})
+	t.Run("fails when user not found", func(t *testing.T) {
+		store := setupTestStore(t)
+
+		err := store.DeleteUser(ctx, 99999)
+		assert.ErrorIs(t, err, domain.ErrUserNotFound)
+	})
+}
+
+func TestUpdatePasswordHash(t *testing.T) {
+	ctx := context.Background()
+	t.Run("replaces the stored hash", func(t *testing.T) {
+		store := setupTestStore(t)
+		userID, err := store.CreateUser(ctx, "test@email.com", "old_hash")
+		assert.NoError(t, err)
+
+		err = store.UpdatePasswordHash(ctx, userID, "new_hash")
+		assert.NoError(t, err)
+
+		user, err := store.GetUserByID(ctx, userID)
+		assert.NoError(t, err)
+		assert.Equal(t, "new_hash", user.PasswordHash)
+	})
+	t.Run("fails when user not found", func(t *testing.T) {
+		store := setupTestStore(t)
+
+		err := store.UpdatePasswordHash(ctx, 99999, "new_hash")
+		assert.ErrorIs(t, err, domain.ErrUserNotFound)
+	})
+}
+
+func TestListUsers(t *testing.T) {
+	ctx := context.Background()
+	store := setupTestStore(t)
+	for _, email := range []string{"a@email.com", "b@email.com", "c@email.com"} {
+		_, err := store.CreateUser(ctx, email, "password_hash")
+		assert.NoError(t, err)
+	}
+	_, err := store.db.ExecContext(ctx, "UPDATE users SET role = ? WHERE email = ?", domain.RoleAdmin, "c@email.com")
+	assert.NoError(t, err)
+
+	t.Run("returns all users ordered by id with their role", func(t *testing.T) {
+		users, err := store.ListUsers(ctx, 0, 0)
+		assert.NoError(t, err)
+		assert.Len(t, users, 3)
+		assert.Equal(t, "a@email.com", users[0].Email)
+		assert.Equal(t, domain.RoleUser, users[0].Role)
+		assert.Equal(t, domain.RoleAdmin, users[2].Role)
+		assert.Empty(t, users[0].PasswordHash)
+		assert.WithinDuration(t, time.Now(), users[0].CreatedAt, time.Minute)
+	})
+	t.Run("returns requested page", func(t *testing.T) {
+		users, err := store.ListUsers(ctx, 1, 1)
+		assert.NoError(t, err)
+		assert.Len(t, users, 1)
+		assert.Equal(t, "b@email.com", users[0].Email)
+	})
+	t.Run("returns an empty page past the end", func(t *testing.T) {
+		users, err := store.ListUsers(ctx, 10, 5)
+		assert.NoError(t, err)
+		assert.Empty(t, users)
+	})
+}
@@ -0,0 +1,110 @@
+// Package webhook delivers task events to an external URL.
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"myproject/application"
+	"myproject/logger"
+	"net/http"
+	"sync"
+	"time"
+)
+
+const defaultBackoff = 500 * time.Millisecond
+
+// Notifier POSTs task events as JSON to a URL in the background.
+// Each attempt is bounded by timeout, and a failed delivery is retried
+// retries times with doubling backoff before it is logged and dropped.
+type Notifier struct {
+	url     string
+	client  *http.Client
+	timeout time.Duration
+	retries int
+	backoff time.Duration
+	logger  *slog.Logger
+	wg      sync.WaitGroup
+}
+
+// NewNotifier creates a notifier for url.
+func NewNotifier(url string, timeout time.Duration, retries int, l *slog.Logger) *Notifier {
+	return &Notifier{
+		url:     url,
+		client:  &http.Client{},
+		timeout: timeout,
+		retries: retries,
+		backoff: defaultBackoff,
+		logger:  l,
+	}
+}
+
+// Notify starts delivering event and returns immediately; it can be registered as an application.TaskHook.
+func (n *Notifier) Notify(event application.TaskEvent) {
+	body, err := json.Marshal(event)
+	if err != nil {
+		n.logFailure(event, 0, err)
+		return
+	}
+
+	n.wg.Add(1)
+	go func() {
+		defer n.wg.Done()
+		n.deliver(event, body)
+	}()
+}
+
+// Wait blocks until every started delivery has succeeded or given up.
+func (n *Notifier) Wait() {
+	n.wg.Wait()
+}
+
+func (n *Notifier) deliver(event application.TaskEvent, body []byte) {
+	backoff := n.backoff
+	var err error
+	for attempt := 0; attempt <= n.retries; attempt++ {
+		if attempt > 0 {
+			time.Sleep(backoff)
+			backoff *= 2
+		}
+		if err = n.post(body); err == nil {
+			return
+		}
+	}
+	n.logFailure(event, n.retries+1, err)
+}
+
+func (n *Notifier) post(body []byte) error {
+	ctx, cancel := context.WithTimeout(context.Background(), n.timeout)
+	defer cancel()
+
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, n.url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	request.Header.Set("Content-Type", "application/json")
+
+	response, err := n.client.Do(request)
+	if err != nil {
+		return fmt.Errorf("failed to send request: %w", err)
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode < 200 || response.StatusCode >= 300 {
+		return fmt.Errorf("unexpected status %d", response.StatusCode)
+	}
+	return nil
+}
+
+func (n *Notifier) logFailure(event application.TaskEvent, attempts int, err error) {
+	n.logger.Error("Failed to deliver webhook",
+		slog.String(logger.FieldOperation, "webhook_notify"),
+		slog.String("event", event.Name),
+		slog.Int(logger.FieldUserID, event.UserID),
+		slog.Int(logger.FieldTaskID, event.Task.ID),
+		slog.Int("attempts", attempts),
+		slog.String(logger.FieldError, err.Error()),
+	)
+}
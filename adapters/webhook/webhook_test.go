@@ -0,0 +1,116 @@
+package webhook
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"myproject/application"
+	"myproject/domain"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var dummyLogger = slog.New(slog.NewTextHandler(io.Discard, nil))
+
+func newTestNotifier(url string, retries int, l *slog.Logger) *Notifier {
+	n := NewNotifier(url, time.Second, retries, l)
+	n.backoff = time.Millisecond
+	return n
+}
+
+func TestNotifier_Notify(t *testing.T) {
+	event := application.TaskEvent{
+		Name:   application.EventTaskCompleted,
+		UserID: 7,
+		Task:   domain.Task{ID: 3, Description: "buy milk", Done: true},
+	}
+
+	t.Run("posts the event as JSON", func(t *testing.T) {
+		received := make(chan application.TaskEvent, 1)
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, http.MethodPost, r.Method)
+			assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+			var got application.TaskEvent
+			assert.NoError(t, json.NewDecoder(r.Body).Decode(&got))
+			received <- got
+		}))
+		defer server.Close()
+
+		n := newTestNotifier(server.URL, 0, dummyLogger)
+		n.Notify(event)
+		n.Wait()
+
+		require.Len(t, received, 1)
+		got := <-received
+		assert.Equal(t, event.Name, got.Name)
+		assert.Equal(t, event.UserID, got.UserID)
+		assert.Equal(t, event.Task.ID, got.Task.ID)
+		assert.Equal(t, event.Task.Description, got.Task.Description)
+	})
+
+	t.Run("retries until delivery succeeds", func(t *testing.T) {
+		var calls atomic.Int32
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if calls.Add(1) < 3 {
+				w.WriteHeader(http.StatusServiceUnavailable)
+			}
+		}))
+		defer server.Close()
+
+		var logs bytes.Buffer
+		n := newTestNotifier(server.URL, 3, slog.New(slog.NewTextHandler(&logs, nil)))
+		n.Notify(event)
+		n.Wait()
+
+		assert.Equal(t, int32(3), calls.Load())
+		assert.Empty(t, logs.String())
+	})
+
+	t.Run("logs and gives up after the last retry", func(t *testing.T) {
+		var calls atomic.Int32
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			calls.Add(1)
+			w.WriteHeader(http.StatusInternalServerError)
+		}))
+		defer server.Close()
+
+		var logs bytes.Buffer
+		n := newTestNotifier(server.URL, 2, slog.New(slog.NewTextHandler(&logs, nil)))
+		n.Notify(event)
+		n.Wait()
+
+		assert.Equal(t, int32(3), calls.Load())
+		assert.Contains(t, logs.String(), "Failed to deliver webhook")
+		assert.Contains(t, logs.String(), "attempts=3")
+	})
+
+	t.Run("returns before delivery completes", func(t *testing.T) {
+		release := make(chan struct{})
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			<-release
+		}))
+		defer server.Close()
+
+		n := newTestNotifier(server.URL, 0, dummyLogger)
+		done := make(chan struct{})
+		go func() {
+			n.Notify(event)
+			close(done)
+		}()
+
+		select {
+		case <-done:
+		case <-time.After(time.Second):
+			t.Fatal("Notify blocked on delivery")
+		}
+		close(release)
+		n.Wait()
+	})
+}
@@ -41,7 +41,7 @@ func (am *AuthMiddleware) extractToken(r *http.Request) (token string, err error
 	return token, nil
 }
 
-// Authenticate wraps an HTTP handler with JWT authentication, adding user ID to request context.
+// Authenticate wraps an HTTP handler with JWT authentication, adding user ID and role to request context.
 func (am *AuthMiddleware) Authenticate(handler http.HandlerFunc) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		token, err := am.extractToken(r)
@@ -80,6 +80,7 @@ func (am *AuthMiddleware) Authenticate(handler http.HandlerFunc) http.HandlerFun
 		)
 
 		ctx := context.WithValue(r.Context(), application.UserIDKey, userID)
+		ctx = context.WithValue(ctx, application.RoleKey, claims.Role)
 		r = r.WithContext(ctx)
 		handler(w, r)
 	}
@@ -36,6 +36,7 @@ func TestAuthMiddleware_Authenticate(t *testing.T) {
 				Token: "valid-jwt",
 				Claims: &domain.Claims{
 					UserID: tc.wantUserID,
+					Role:   domain.RoleAdmin,
 				},
 				Err: nil,
 			}
@@ -48,11 +49,13 @@ func TestAuthMiddleware_Authenticate(t *testing.T) {
 			middleware := NewAuthMiddleware(stubTokenGenerator, testLogger)
 
 			var capturedUserID int
+			var capturedRole string
 			handler := middleware.Authenticate(func(w http.ResponseWriter, r *http.Request) {
 				userID, err := application.GetUserIDFromContext(r.Context())
 				if err == nil {
 					capturedUserID = userID
 				}
+				capturedRole = application.GetUserRoleFromContext(r.Context())
 			})
 
 			// Act
@@ -66,6 +69,7 @@ func TestAuthMiddleware_Authenticate(t *testing.T) {
 
 			if tc.expectCall {
 				assert.Equal(t, tc.wantUserID, capturedUserID)
+				assert.Equal(t, domain.RoleAdmin, capturedRole)
 			}
 		})
 	}
@@ -0,0 +1,64 @@
+package webserver
+
+import (
+	"net/http"
+	"slices"
+	"strings"
+)
+
+// corsAllowedHeaders are the request headers browsers may send cross-origin.
+const corsAllowedHeaders = "Authorization, Content-Type, If-None-Match, X-Request-ID"
+
+// corsExposedHeaders are the response headers cross-origin scripts may read, for pagination, caching and support.
+const corsExposedHeaders = "ETag, Link, X-Total-Count, X-Request-ID"
+
+// CORSPolicy lists the browser origins allowed to call the API.
+// Origins are matched exactly; an empty list disables CORS.
+type CORSPolicy struct {
+	AllowedOrigins   []string
+	AllowedMethods   []string
+	AllowCredentials bool
+}
+
+// WithCORS enables cross-origin requests from the origins of the policy.
+func WithCORS(policy CORSPolicy) Option {
+	return func(ts *TasksServer) {
+		ts.cors = policy
+	}
+}
+
+// corsMiddleware adds Access-Control-Allow-* headers for allowlisted origins and answers
+// preflight requests with 204 before they reach the router. Other origins get no CORS
+// headers, so browsers block them; the request origin is only echoed once it matched.
+func corsMiddleware(policy CORSPolicy) func(http.Handler) http.Handler {
+	methods := strings.Join(policy.AllowedMethods, ", ")
+	return func(next http.Handler) http.Handler {
+		if len(policy.AllowedOrigins) == 0 {
+			return next
+		}
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			origin := r.Header.Get("Origin")
+			allowed := origin != "" && slices.Contains(policy.AllowedOrigins, origin)
+
+			w.Header().Add("Vary", "Origin")
+			if allowed {
+				w.Header().Set("Access-Control-Allow-Origin", origin)
+				w.Header().Set("Access-Control-Expose-Headers", corsExposedHeaders)
+				if policy.AllowCredentials {
+					w.Header().Set("Access-Control-Allow-Credentials", "true")
+				}
+			}
+
+			if r.Method == http.MethodOptions && r.Header.Get("Access-Control-Request-Method") != "" {
+				if allowed {
+					w.Header().Set("Access-Control-Allow-Methods", methods)
+					w.Header().Set("Access-Control-Allow-Headers", corsAllowedHeaders)
+				}
+				w.WriteHeader(http.StatusNoContent)
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
@@ -0,0 +1,76 @@
+package webserver
+
+import (
+	"myproject/infrastructure/testhelpers"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCORS(t *testing.T) {
+	policy := CORSPolicy{
+		AllowedOrigins:   []string{"https://app.example.com"},
+		AllowedMethods:   []string{"GET", "POST"},
+		AllowCredentials: true,
+	}
+
+	send := func(policy CORSPolicy, method, origin string, preflight bool) *httptest.ResponseRecorder {
+		svr := NewTasksServer(&testhelpers.StubTaskStore{}, &StubAuthService{}, &StubAuth{}, dummyLogger, WithCORS(policy))
+		request := httptest.NewRequest(method, "/health", nil)
+		if origin != "" {
+			request.Header.Set("Origin", origin)
+		}
+		if preflight {
+			request.Header.Set("Access-Control-Request-Method", http.MethodGet)
+		}
+		response := httptest.NewRecorder()
+		svr.ServeHTTP(response, request)
+		return response
+	}
+
+	t.Run("allows listed origin", func(t *testing.T) {
+		response := send(policy, http.MethodGet, "https://app.example.com", false)
+
+		assert.Equal(t, http.StatusOK, response.Code)
+		assert.Equal(t, "https://app.example.com", response.Header().Get("Access-Control-Allow-Origin"))
+		assert.Equal(t, "true", response.Header().Get("Access-Control-Allow-Credentials"))
+		assert.Equal(t, "Origin", response.Header().Get("Vary"))
+		assert.Equal(t, corsExposedHeaders, response.Header().Get("Access-Control-Expose-Headers"))
+	})
+	t.Run("answers preflight with 204", func(t *testing.T) {
+		response := send(policy, http.MethodOptions, "https://app.example.com", true)
+
+		assert.Equal(t, http.StatusNoContent, response.Code)
+		assert.Equal(t, "https://app.example.com", response.Header().Get("Access-Control-Allow-Origin"))
+		assert.Equal(t, "GET, POST", response.Header().Get("Access-Control-Allow-Methods"))
+		assert.Equal(t, corsAllowedHeaders, response.Header().Get("Access-Control-Allow-Headers"))
+	})
+	t.Run("never echoes other origins", func(t *testing.T) {
+		for _, preflight := range []bool{false, true} {
+			method := http.MethodGet
+			if preflight {
+				method = http.MethodOptions
+			}
+			response := send(policy, method, "https://evil.example.com", preflight)
+
+			assert.Empty(t, response.Header().Get("Access-Control-Allow-Origin"))
+			assert.Empty(t, response.Header().Get("Access-Control-Allow-Credentials"))
+			assert.Empty(t, response.Header().Get("Access-Control-Allow-Methods"))
+		}
+	})
+	t.Run("omits credentials header unless enabled", func(t *testing.T) {
+		response := send(CORSPolicy{AllowedOrigins: policy.AllowedOrigins}, http.MethodGet, "https://app.example.com", false)
+
+		assert.Equal(t, "https://app.example.com", response.Header().Get("Access-Control-Allow-Origin"))
+		assert.Empty(t, response.Header().Get("Access-Control-Allow-Credentials"))
+	})
+	t.Run("is disabled without allowed origins", func(t *testing.T) {
+		response := send(CORSPolicy{}, http.MethodOptions, "https://app.example.com", true)
+
+		assert.NotEqual(t, http.StatusNoContent, response.Code)
+		assert.Empty(t, response.Header().Get("Access-Control-Allow-Origin"))
+		assert.Empty(t, response.Header().Get("Vary"))
+	})
+}
@@ -132,10 +132,10 @@ func (d Driver) GetTasks(token string) ([]domain.Task, error) {
 		return []domain.Task{}, err
 	}
 
-	result := []domain.Task{}
-	if err = json.NewDecoder(response.Body).Decode(&result); err != nil {
+	var page domain.TaskPage
+	if err = json.NewDecoder(response.Body).Decode(&page); err != nil {
 		return []domain.Task{}, fmt.Errorf("failed to decode get tasks response: %w", err)
 	}
 
-	return result, nil
+	return page.Tasks, nil
 }
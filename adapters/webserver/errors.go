@@ -0,0 +1,56 @@
+package webserver
+
+import (
+	"errors"
+	"net/http"
+
+	"myproject/domain"
+	"myproject/domain/validation"
+)
+
+// ErrorToStatus maps a domain error to the HTTP status code and a message that is safe to send to the client.
+// Errors it does not know about map to 500 with a generic message, so internal details never leak.
+func ErrorToStatus(err error) (int, string) {
+	var itemErr *domain.BatchItemError
+	switch {
+	case errors.As(err, &itemErr):
+		switch {
+		case errors.Is(itemErr.Err, domain.ErrDuplicateTask), errors.Is(itemErr.Err, domain.ErrVersionConflict):
+			return http.StatusConflict, itemErr.Error()
+		case errors.Is(itemErr.Err, domain.ErrTaskNotFound):
+			return http.StatusNotFound, itemErr.Error()
+		}
+		return http.StatusBadRequest, itemErr.Error()
+	case errors.Is(err, domain.ErrDescriptionRequired),
+		errors.Is(err, domain.ErrDescriptionTooLong),
+		errors.Is(err, domain.ErrEmptyFieldsToUpdate),
+		errors.Is(err, domain.ErrDueDateInPast),
+		errors.Is(err, domain.ErrInvalidPriority),
+		errors.Is(err, domain.ErrInvalidTag),
+		errors.Is(err, domain.ErrEmptyBatch),
+		errors.Is(err, validation.ErrInvalidTaskID),
+		errors.Is(err, domain.ErrInvalidEmail),
+		errors.Is(err, domain.ErrSamePassword),
+		domain.IsPasswordPolicyError(err):
+		return http.StatusBadRequest, err.Error()
+	case errors.Is(err, domain.ErrInvalidCredentials):
+		return http.StatusUnauthorized, "invalid credentials"
+	case errors.Is(err, domain.ErrAccountLocked):
+		return http.StatusLocked, err.Error()
+	case errors.Is(err, domain.ErrUserNotFound):
+		// The user ID comes from a token, so a missing user means the client has to log in again.
+		return http.StatusUnauthorized, "User not found"
+	case errors.Is(err, domain.ErrTaskForbidden):
+		return http.StatusForbidden, "Task belongs to another user"
+	case errors.Is(err, domain.ErrTaskNotFound):
+		return http.StatusNotFound, "Task not found"
+	case errors.Is(err, domain.ErrEmailAlreadyExists):
+		return http.StatusConflict, err.Error()
+	case errors.Is(err, domain.ErrDuplicateTask):
+		return http.StatusConflict, domain.ErrDuplicateTask.Error()
+	case errors.Is(err, domain.ErrVersionConflict):
+		return http.StatusConflict, domain.ErrVersionConflict.Error()
+	default:
+		return http.StatusInternalServerError, "Internal server error"
+	}
+}
@@ -0,0 +1,77 @@
+package webserver
+
+import (
+	"errors"
+	"fmt"
+	"myproject/domain"
+	"myproject/domain/validation"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestErrorToStatus(t *testing.T) {
+	tests := []struct {
+		name            string
+		err             error
+		expectedStatus  int
+		expectedMessage string
+	}{
+		{"description required", domain.ErrDescriptionRequired, http.StatusBadRequest, domain.ErrDescriptionRequired.Error()},
+		{"description too long", domain.ErrDescriptionTooLong, http.StatusBadRequest, domain.ErrDescriptionTooLong.Error()},
+		{"empty fields to update", domain.ErrEmptyFieldsToUpdate, http.StatusBadRequest, domain.ErrEmptyFieldsToUpdate.Error()},
+		{"due date in past", domain.ErrDueDateInPast, http.StatusBadRequest, domain.ErrDueDateInPast.Error()},
+		{"invalid priority", domain.ErrInvalidPriority, http.StatusBadRequest, domain.ErrInvalidPriority.Error()},
+		{"invalid tag", domain.ErrInvalidTag, http.StatusBadRequest, domain.ErrInvalidTag.Error()},
+		{"empty batch", domain.ErrEmptyBatch, http.StatusBadRequest, domain.ErrEmptyBatch.Error()},
+		{"invalid task id", validation.ErrInvalidTaskID, http.StatusBadRequest, validation.ErrInvalidTaskID.Error()},
+		{
+			"batch item error without wrapping prefix",
+			fmt.Errorf("failed to validate batch: %w", &domain.BatchItemError{Index: 2, Err: domain.ErrDescriptionRequired}),
+			http.StatusBadRequest,
+			"task 2: description is required",
+		},
+		{"invalid email", domain.ErrInvalidEmail, http.StatusBadRequest, domain.ErrInvalidEmail.Error()},
+		{"password too short", domain.ErrPasswordTooShort, http.StatusBadRequest, domain.ErrPasswordTooShort.Error()},
+		{"password missing digit", domain.ErrPasswordMissingDigit, http.StatusBadRequest, domain.ErrPasswordMissingDigit.Error()},
+		{"same password", domain.ErrSamePassword, http.StatusBadRequest, domain.ErrSamePassword.Error()},
+		{"invalid credentials", domain.ErrInvalidCredentials, http.StatusUnauthorized, "invalid credentials"},
+		{"account locked", domain.ErrAccountLocked, http.StatusLocked, "too many failed logins, try again later"},
+		{"user not found", domain.ErrUserNotFound, http.StatusUnauthorized, "User not found"},
+		{"task forbidden", domain.ErrTaskForbidden, http.StatusForbidden, "Task belongs to another user"},
+		{"task not found", domain.ErrTaskNotFound, http.StatusNotFound, "Task not found"},
+		{"wrapped task not found", fmt.Errorf("failed to get task: %w", domain.ErrTaskNotFound), http.StatusNotFound, "Task not found"},
+		{"email already exists", domain.ErrEmailAlreadyExists, http.StatusConflict, domain.ErrEmailAlreadyExists.Error()},
+		{"duplicate task", fmt.Errorf("failed to create task: %w", domain.ErrDuplicateTask), http.StatusConflict, domain.ErrDuplicateTask.Error()},
+		{"version conflict", fmt.Errorf("failed to update task: %w", domain.ErrVersionConflict), http.StatusConflict, domain.ErrVersionConflict.Error()},
+		{
+			"duplicate task in batch",
+			fmt.Errorf("failed to validate batch: %w", &domain.BatchItemError{Index: 1, Err: domain.ErrDuplicateTask}),
+			http.StatusConflict,
+			"task 1: " + domain.ErrDuplicateTask.Error(),
+		},
+		{
+			"missing task in batch",
+			fmt.Errorf("failed to find task with id 7: %w", &domain.BatchItemError{Index: 2, Err: domain.ErrTaskNotFound}),
+			http.StatusNotFound,
+			"task 2: " + domain.ErrTaskNotFound.Error(),
+		},
+		{
+			"version conflict in batch",
+			fmt.Errorf("failed to update 2 tasks: %w", &domain.BatchItemError{Index: 0, Err: domain.ErrVersionConflict}),
+			http.StatusConflict,
+			"task 0: " + domain.ErrVersionConflict.Error(),
+		},
+		{"storage failure", domain.ErrStorageFailure, http.StatusInternalServerError, "Internal server error"},
+		{"unknown error", errors.New("connection refused"), http.StatusInternalServerError, "Internal server error"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			status, message := ErrorToStatus(tt.err)
+			assert.Equal(t, tt.expectedStatus, status)
+			assert.Equal(t, tt.expectedMessage, message)
+		})
+	}
+}
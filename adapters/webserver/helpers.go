@@ -3,21 +3,62 @@ package webserver
 import (
 	"encoding/json"
 	"errors"
+	"fmt"
 	"io"
+	"myproject/domain"
 	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
 )
 
 const jsonContentType = "application/json"
 
+// DefaultMaxBodyBytes is the request body limit used unless WithMaxBodyBytes sets another.
+const DefaultMaxBodyBytes int64 = 1 << 20
+
 // JSONResponse sends a JSON response with the given status code
+// It is indented when the request asked for ?pretty=true, see prettyJSONMiddleware.
 func JSONResponse(w http.ResponseWriter, statusCode int, data interface{}) {
 	w.Header().Set("Content-Type", jsonContentType)
 	w.WriteHeader(statusCode)
-	if err := json.NewEncoder(w).Encode(data); err != nil {
+	encoder := json.NewEncoder(w)
+	if _, ok := w.(*prettyJSONWriter); ok {
+		encoder.SetIndent("", "  ")
+	}
+	if err := encoder.Encode(data); err != nil {
 		http.Error(w, "Internal server error", http.StatusInternalServerError)
 	}
 }
 
+// prettyJSONWriter marks a response whose JSON body JSONResponse indents.
+type prettyJSONWriter struct {
+	http.ResponseWriter
+}
+
+// prettyJSONMiddleware marks the response of requests with ?pretty=true for indented JSON,
+// so output read by a person, e.g. from curl, needs no formatter. Responses stay compact by default.
+// It must wrap the router directly, as JSONResponse only recognizes the writer it passes on.
+func prettyJSONMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if pretty, _ := strconv.ParseBool(r.URL.Query().Get("pretty")); pretty {
+			w = &prettyJSONWriter{ResponseWriter: w}
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// maxBodyMiddleware caps every request body at limit bytes, so a client cannot make the server
+// buffer an arbitrarily large payload; reading past the limit fails with *http.MaxBytesError.
+func maxBodyMiddleware(limit int64) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			r.Body = http.MaxBytesReader(w, r.Body, limit)
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
 // JSONError sends a JSON error response
 func JSONError(w http.ResponseWriter, statusCode int, message string) {
 	errorResponse := map[string]string{
@@ -30,6 +71,53 @@ func JSONSuccess(w http.ResponseWriter, data interface{}) {
 	JSONResponse(w, http.StatusOK, data)
 }
 
+// SetPaginationHeaders sets X-Total-Count and an RFC 5988 Link header with the next and
+// prev pages of a list request, so clients can page without parsing the body.
+// Links keep the other query parameters of u; a rel without a page is omitted.
+// Must be called before the response is written.
+func SetPaginationHeaders(w http.ResponseWriter, u *url.URL, limit, offset, total int) {
+	w.Header().Set("X-Total-Count", strconv.Itoa(total))
+
+	var links []string
+	if offset+limit < total {
+		links = append(links, pageLink(u, limit, offset+limit, "next"))
+	}
+	if offset > 0 {
+		links = append(links, pageLink(u, limit, max(offset-limit, 0), "prev"))
+	}
+	if len(links) > 0 {
+		w.Header().Set("Link", strings.Join(links, ", "))
+	}
+}
+
+// pageLink renders one Link header entry for the page at offset.
+func pageLink(u *url.URL, limit, offset int, rel string) string {
+	query := u.Query()
+	query.Set("limit", strconv.Itoa(limit))
+	query.Set("offset", strconv.Itoa(offset))
+	return fmt.Sprintf(`<%s?%s>; rel="%s"`, u.Path, query.Encode(), rel)
+}
+
+// TaskETag returns the entity tag of a task. Every change to a task bumps its version and
+// updated_at, so the tag changes with it. It is weak because ?pretty=true changes the bytes, not the task.
+func TaskETag(task domain.Task) string {
+	return fmt.Sprintf(`W/"%d-%d-%d"`, task.ID, task.Version, task.UpdatedAt.UnixMicro())
+}
+
+// etagMatches reports whether an If-None-Match header value lists etag or is "*".
+// Tags are compared ignoring the weak prefix, the weak comparison RFC 9110 asks for.
+func etagMatches(header, etag string) bool {
+	if strings.TrimSpace(header) == "*" {
+		return true
+	}
+	for _, candidate := range strings.Split(header, ",") {
+		if strings.TrimPrefix(strings.TrimSpace(candidate), "W/") == strings.TrimPrefix(etag, "W/") {
+			return true
+		}
+	}
+	return false
+}
+
 // HandleMethodNotAllowed handles unsupported HTTP methods
 func HandleMethodNotAllowed(w http.ResponseWriter, allowedMethods []string) {
 	w.Header().Set("Allow", joinMethods(allowedMethods))
@@ -48,6 +136,8 @@ func joinMethods(methods []string) string {
 	return result
 }
 
+// ParseJSONRequest decodes a JSON request body into target, writing the error response itself on failure.
+// A body cut off by maxBodyMiddleware is answered with 413 Request Entity Too Large.
 func ParseJSONRequest(w http.ResponseWriter, r *http.Request, target interface{}) error {
 	if r.Header.Get("Content-Type") != jsonContentType {
 		JSONError(w, http.StatusUnsupportedMediaType, "Content-Type must be application/json")
@@ -55,6 +145,11 @@ func ParseJSONRequest(w http.ResponseWriter, r *http.Request, target interface{}
 	}
 	body, err := io.ReadAll(r.Body)
 	if err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			JSONError(w, http.StatusRequestEntityTooLarge, fmt.Sprintf("Request body too large, limit is %d bytes", tooLarge.Limit))
+			return err
+		}
 		http.Error(w, "Failed to read body", http.StatusBadRequest)
 		return err
 	}
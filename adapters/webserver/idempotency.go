@@ -0,0 +1,96 @@
+package webserver
+
+import (
+	"sync"
+	"time"
+)
+
+// IdempotencyKeyHeader lets a client retry POST /tasks without creating the task twice.
+const IdempotencyKeyHeader = "Idempotency-Key"
+
+// defaultIdempotencyWindow is how long keys are remembered unless WithIdempotencyWindow says otherwise.
+const defaultIdempotencyWindow = 24 * time.Hour
+
+// maxIdempotencyKeyLength bounds the keys kept in memory; a UUID needs 36 characters.
+const maxIdempotencyKeyLength = 255
+
+// idempotencyKey identifies a key per user, so two users cannot collide on the same key.
+type idempotencyKey struct {
+	userID int
+	key    string
+}
+
+// idempotencyEntry is the task created for a key, or zero while the first request is still running.
+type idempotencyEntry struct {
+	taskID  int
+	expires time.Time
+}
+
+// IdempotencyCache remembers which task each Idempotency-Key created for a window of time,
+// so a repeated create can be answered with the original task.
+type IdempotencyCache struct {
+	mu        sync.Mutex
+	entries   map[idempotencyKey]idempotencyEntry
+	window    time.Duration
+	lastSweep time.Time
+	now       func() time.Time
+}
+
+// NewIdempotencyCache creates a cache that forgets keys window after they were first used.
+func NewIdempotencyCache(window time.Duration) *IdempotencyCache {
+	return &IdempotencyCache{
+		entries: make(map[idempotencyKey]idempotencyEntry),
+		window:  window,
+		now:     time.Now,
+	}
+}
+
+// Reserve claims key for a new create. It reports false when the key is already taken:
+// taskID is then the task created with it, or zero while that create is still in progress.
+func (c *IdempotencyCache) Reserve(userID int, key string) (taskID int, reserved bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := c.now()
+	c.sweep(now)
+
+	k := idempotencyKey{userID: userID, key: key}
+	if entry, ok := c.entries[k]; ok && now.Before(entry.expires) {
+		return entry.taskID, false
+	}
+	c.entries[k] = idempotencyEntry{expires: now.Add(c.window)}
+	return 0, true
+}
+
+// Complete records the task created for a reserved key.
+func (c *IdempotencyCache) Complete(userID int, key string, taskID int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	k := idempotencyKey{userID: userID, key: key}
+	if entry, ok := c.entries[k]; ok {
+		entry.taskID = taskID
+		c.entries[k] = entry
+	}
+}
+
+// Release frees a reserved key after a failed create, so the client can retry with it.
+func (c *IdempotencyCache) Release(userID int, key string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	delete(c.entries, idempotencyKey{userID: userID, key: key})
+}
+
+// sweep drops expired keys, at most once per window.
+func (c *IdempotencyCache) sweep(now time.Time) {
+	if now.Sub(c.lastSweep) < c.window {
+		return
+	}
+	for k, entry := range c.entries {
+		if !now.Before(entry.expires) {
+			delete(c.entries, k)
+		}
+	}
+	c.lastSweep = now
+}
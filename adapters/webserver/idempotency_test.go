@@ -0,0 +1,68 @@
+package webserver
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestIdempotencyCache(window time.Duration) (*IdempotencyCache, *time.Time) {
+	now := time.Date(2025, 6, 15, 12, 0, 0, 0, time.UTC)
+	c := NewIdempotencyCache(window)
+	c.now = func() time.Time { return now }
+	return c, &now
+}
+
+func TestIdempotencyCache(t *testing.T) {
+	t.Run("reserves a new key once", func(t *testing.T) {
+		c, _ := newTestIdempotencyCache(time.Hour)
+
+		_, reserved := c.Reserve(1, "key")
+		assert.True(t, reserved)
+
+		taskID, reserved := c.Reserve(1, "key")
+		assert.False(t, reserved)
+		assert.Zero(t, taskID, "the first create is still in progress")
+	})
+
+	t.Run("returns the task created for a completed key", func(t *testing.T) {
+		c, _ := newTestIdempotencyCache(time.Hour)
+		c.Reserve(1, "key")
+		c.Complete(1, "key", 42)
+
+		taskID, reserved := c.Reserve(1, "key")
+		assert.False(t, reserved)
+		assert.Equal(t, 42, taskID)
+	})
+
+	t.Run("keeps keys of different users apart", func(t *testing.T) {
+		c, _ := newTestIdempotencyCache(time.Hour)
+		c.Reserve(1, "key")
+		c.Complete(1, "key", 42)
+
+		_, reserved := c.Reserve(2, "key")
+		assert.True(t, reserved)
+	})
+
+	t.Run("frees a released key", func(t *testing.T) {
+		c, _ := newTestIdempotencyCache(time.Hour)
+		c.Reserve(1, "key")
+		c.Release(1, "key")
+
+		_, reserved := c.Reserve(1, "key")
+		assert.True(t, reserved)
+	})
+
+	t.Run("forgets keys after the window", func(t *testing.T) {
+		c, now := newTestIdempotencyCache(time.Hour)
+		c.Reserve(1, "key")
+		c.Complete(1, "key", 42)
+
+		*now = now.Add(time.Hour)
+
+		_, reserved := c.Reserve(1, "key")
+		assert.True(t, reserved)
+		assert.Len(t, c.entries, 1, "the expired entry should have been swept")
+	})
+}
@@ -0,0 +1,123 @@
+package webserver
+
+import (
+	"math"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+)
+
+// defaultLatencyWindow is the number of most recent samples kept per route.
+const defaultLatencyWindow = 512
+
+// EndpointLatency summarizes the recent latency of a single route.
+type EndpointLatency struct {
+	Route   string  `json:"route"`
+	Samples int     `json:"samples"`
+	P50Ms   float64 `json:"p50_ms"`
+	P95Ms   float64 `json:"p95_ms"`
+	P99Ms   float64 `json:"p99_ms"`
+}
+
+// latencyRing is a fixed-size ring buffer of request durations.
+type latencyRing struct {
+	samples []time.Duration
+	next    int
+	full    bool
+}
+
+// LatencyTracker keeps a bounded rolling window of request durations per route.
+type LatencyTracker struct {
+	mu     sync.Mutex
+	window int
+	routes map[string]*latencyRing
+}
+
+// NewLatencyTracker creates a tracker retaining at most window samples per route.
+func NewLatencyTracker(window int) *LatencyTracker {
+	if window <= 0 {
+		window = defaultLatencyWindow
+	}
+	return &LatencyTracker{
+		window: window,
+		routes: make(map[string]*latencyRing),
+	}
+}
+
+// Record adds a request duration for the given route, evicting the oldest sample when the window is full.
+func (lt *LatencyTracker) Record(route string, d time.Duration) {
+	lt.mu.Lock()
+	defer lt.mu.Unlock()
+
+	ring, ok := lt.routes[route]
+	if !ok {
+		ring = &latencyRing{samples: make([]time.Duration, lt.window)}
+		lt.routes[route] = ring
+	}
+	ring.samples[ring.next] = d
+	ring.next = (ring.next + 1) % lt.window
+	if ring.next == 0 {
+		ring.full = true
+	}
+}
+
+// Summary returns per-route percentiles ordered from slowest to fastest p95.
+func (lt *LatencyTracker) Summary() []EndpointLatency {
+	lt.mu.Lock()
+	snapshots := make(map[string][]time.Duration, len(lt.routes))
+	for route, ring := range lt.routes {
+		n := ring.next
+		if ring.full {
+			n = lt.window
+		}
+		snapshots[route] = append([]time.Duration(nil), ring.samples[:n]...)
+	}
+	lt.mu.Unlock()
+
+	summary := make([]EndpointLatency, 0, len(snapshots))
+	for route, samples := range snapshots {
+		sort.Slice(samples, func(i, j int) bool { return samples[i] < samples[j] })
+		summary = append(summary, EndpointLatency{
+			Route:   route,
+			Samples: len(samples),
+			P50Ms:   percentileMs(samples, 50),
+			P95Ms:   percentileMs(samples, 95),
+			P99Ms:   percentileMs(samples, 99),
+		})
+	}
+	sort.Slice(summary, func(i, j int) bool {
+		if summary[i].P95Ms != summary[j].P95Ms {
+			return summary[i].P95Ms > summary[j].P95Ms
+		}
+		return summary[i].Route < summary[j].Route
+	})
+	return summary
+}
+
+// percentileMs returns the nearest-rank percentile of sorted samples in milliseconds.
+func percentileMs(sorted []time.Duration, p float64) float64 {
+	if len(sorted) == 0 {
+		return 0
+	}
+	rank := int(math.Ceil(p / 100 * float64(len(sorted))))
+	if rank < 1 {
+		rank = 1
+	}
+	return float64(sorted[rank-1]) / float64(time.Millisecond)
+}
+
+// latencyMiddleware records the duration of every request under its matched route pattern.
+// Requests that match no route are not recorded to keep the set of routes bounded.
+func latencyMiddleware(tracker *LatencyTracker, mux *http.ServeMux) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			_, pattern := mux.Handler(r)
+			start := time.Now()
+			next.ServeHTTP(w, r)
+			if pattern != "" {
+				tracker.Record(pattern, time.Since(start))
+			}
+		})
+	}
+}
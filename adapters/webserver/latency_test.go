@@ -0,0 +1,89 @@
+package webserver
+
+import (
+	"encoding/json"
+	"myproject/domain"
+	"myproject/infrastructure/testhelpers"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLatencyTracker_Summary(t *testing.T) {
+	t.Run("computes percentiles from recorded durations", func(t *testing.T) {
+		tracker := NewLatencyTracker(1000)
+		for i := 1; i <= 100; i++ {
+			tracker.Record("GET /tasks", time.Duration(i)*time.Millisecond)
+		}
+
+		summary := tracker.Summary()
+
+		require.Len(t, summary, 1)
+		assert.Equal(t, "GET /tasks", summary[0].Route)
+		assert.Equal(t, 100, summary[0].Samples)
+		assert.InDelta(t, 50, summary[0].P50Ms, 1)
+		assert.InDelta(t, 95, summary[0].P95Ms, 1)
+		assert.InDelta(t, 99, summary[0].P99Ms, 1)
+	})
+	t.Run("keeps only the most recent samples per route", func(t *testing.T) {
+		tracker := NewLatencyTracker(10)
+		for i := 0; i < 50; i++ {
+			tracker.Record("GET /tasks", time.Second)
+		}
+		for i := 0; i < 10; i++ {
+			tracker.Record("GET /tasks", time.Millisecond)
+		}
+
+		summary := tracker.Summary()
+
+		require.Len(t, summary, 1)
+		assert.Equal(t, 10, summary[0].Samples)
+		assert.InDelta(t, 1, summary[0].P99Ms, 0.001)
+	})
+	t.Run("orders routes from slowest to fastest", func(t *testing.T) {
+		tracker := NewLatencyTracker(10)
+		tracker.Record("GET /health", time.Millisecond)
+		tracker.Record("POST /tasks", 200*time.Millisecond)
+		tracker.Record("GET /tasks", 20*time.Millisecond)
+
+		summary := tracker.Summary()
+
+		require.Len(t, summary, 3)
+		assert.Equal(t, "POST /tasks", summary[0].Route)
+		assert.Equal(t, "GET /tasks", summary[1].Route)
+		assert.Equal(t, "GET /health", summary[2].Route)
+	})
+}
+
+func TestSlowEndpoints(t *testing.T) {
+	store := &testhelpers.StubTaskStore{}
+	auth := &StubAuth{role: domain.RoleAdmin}
+	svr := NewTasksServer(store, &StubAuthService{}, auth, dummyLogger)
+
+	svr.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/health", nil))
+
+	response := httptest.NewRecorder()
+	svr.ServeHTTP(response, httptest.NewRequest(http.MethodGet, "/admin/slow-endpoints", nil))
+
+	assert.Equal(t, http.StatusOK, response.Code)
+	assert.Equal(t, 1, auth.authCalled)
+
+	var summary []EndpointLatency
+	require.NoError(t, json.NewDecoder(response.Body).Decode(&summary))
+	require.Len(t, summary, 1)
+	assert.Equal(t, "GET /health", summary[0].Route)
+	assert.Equal(t, 1, summary[0].Samples)
+}
+
+func TestSlowEndpoints_RequiresAdmin(t *testing.T) {
+	svr := NewTasksServer(&testhelpers.StubTaskStore{}, &StubAuthService{}, &StubAuth{role: domain.RoleUser}, dummyLogger)
+
+	response := httptest.NewRecorder()
+	svr.ServeHTTP(response, httptest.NewRequest(http.MethodGet, "/admin/slow-endpoints", nil))
+
+	assert.Equal(t, http.StatusForbidden, response.Code)
+}
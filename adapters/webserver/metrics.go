@@ -0,0 +1,172 @@
+package webserver
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// metricsContentType is the Prometheus text exposition format version served at /metrics.
+const metricsContentType = "text/plain; version=0.0.4; charset=utf-8"
+
+// durationBuckets are the upper bounds in seconds of the request latency histogram.
+var durationBuckets = []float64{0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10}
+
+// requestKey identifies a request counter by route pattern and response status.
+type requestKey struct {
+	route  string
+	status int
+}
+
+// histogram counts observations per bucket; counts are cumulated when rendered.
+type histogram struct {
+	counts []uint64
+	sum    float64
+	count  uint64
+}
+
+// Metrics is a small in-memory registry of request and task counters rendered in
+// Prometheus text format, so the server needs no client library.
+type Metrics struct {
+	mu         sync.Mutex
+	requests   map[requestKey]uint64
+	durations  map[string]*histogram
+	operations map[string]uint64
+}
+
+// NewMetrics creates an empty registry.
+func NewMetrics() *Metrics {
+	return &Metrics{
+		requests:   make(map[requestKey]uint64),
+		durations:  make(map[string]*histogram),
+		operations: make(map[string]uint64),
+	}
+}
+
+// ObserveRequest counts a finished request and adds its duration to the route's histogram.
+func (m *Metrics) ObserveRequest(route string, status int, d time.Duration) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.requests[requestKey{route, status}]++
+
+	h, ok := m.durations[route]
+	if !ok {
+		h = &histogram{counts: make([]uint64, len(durationBuckets))}
+		m.durations[route] = h
+	}
+	seconds := d.Seconds()
+	for i, bound := range durationBuckets {
+		if seconds <= bound {
+			h.counts[i]++
+			break
+		}
+	}
+	h.sum += seconds
+	h.count++
+}
+
+// AddTaskOperations counts n successful task changes of the given kind, e.g. "create" or "delete".
+func (m *Metrics) AddTaskOperations(operation string, n int) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.operations[operation] += uint64(n)
+}
+
+// WriteTo renders all metrics in Prometheus text format with series in a stable order.
+func (m *Metrics) WriteTo(w io.Writer) (int64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var b strings.Builder
+
+	b.WriteString("# HELP http_requests_total Total HTTP requests by route and status code.\n")
+	b.WriteString("# TYPE http_requests_total counter\n")
+	keys := make([]requestKey, 0, len(m.requests))
+	for key := range m.requests {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].route != keys[j].route {
+			return keys[i].route < keys[j].route
+		}
+		return keys[i].status < keys[j].status
+	})
+	for _, key := range keys {
+		fmt.Fprintf(&b, "http_requests_total{route=%s,status=\"%d\"} %d\n", quoteLabel(key.route), key.status, m.requests[key])
+	}
+
+	b.WriteString("# HELP http_request_duration_seconds HTTP request latency by route.\n")
+	b.WriteString("# TYPE http_request_duration_seconds histogram\n")
+	for _, route := range sortedKeys(m.durations) {
+		h := m.durations[route]
+		label := quoteLabel(route)
+		var cumulative uint64
+		for i, bound := range durationBuckets {
+			cumulative += h.counts[i]
+			fmt.Fprintf(&b, "http_request_duration_seconds_bucket{route=%s,le=\"%s\"} %d\n", label, strconv.FormatFloat(bound, 'g', -1, 64), cumulative)
+		}
+		fmt.Fprintf(&b, "http_request_duration_seconds_bucket{route=%s,le=\"+Inf\"} %d\n", label, h.count)
+		fmt.Fprintf(&b, "http_request_duration_seconds_sum{route=%s} %s\n", label, strconv.FormatFloat(h.sum, 'g', -1, 64))
+		fmt.Fprintf(&b, "http_request_duration_seconds_count{route=%s} %d\n", label, h.count)
+	}
+
+	b.WriteString("# HELP task_operations_total Successful task changes by operation.\n")
+	b.WriteString("# TYPE task_operations_total counter\n")
+	for _, operation := range sortedKeys(m.operations) {
+		fmt.Fprintf(&b, "task_operations_total{operation=%s} %d\n", quoteLabel(operation), m.operations[operation])
+	}
+
+	n, err := io.WriteString(w, b.String())
+	return int64(n), err
+}
+
+// sortedKeys returns the keys of a metric map in ascending order.
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// labelEscaper escapes the characters Prometheus does not allow unescaped in label values.
+var labelEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+// quoteLabel renders a label value as a quoted, escaped string.
+func quoteLabel(value string) string {
+	return `"` + labelEscaper.Replace(value) + `"`
+}
+
+// statusRecorder captures the status code written by the wrapped handler.
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+}
+
+func (sr *statusRecorder) WriteHeader(status int) {
+	sr.status = status
+	sr.ResponseWriter.WriteHeader(status)
+}
+
+// metricsMiddleware counts every request and its duration under its matched route pattern.
+// Like latencyMiddleware it skips requests that match no route to keep label values bounded.
+func metricsMiddleware(metrics *Metrics, mux *http.ServeMux) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			_, pattern := mux.Handler(r)
+			recorder := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+			start := time.Now()
+			next.ServeHTTP(recorder, r)
+			if pattern != "" {
+				metrics.ObserveRequest(pattern, recorder.status, time.Since(start))
+			}
+		})
+	}
+}
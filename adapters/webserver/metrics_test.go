@@ -0,0 +1,89 @@
+package webserver
+
+import (
+	"bytes"
+	"myproject/infrastructure/testhelpers"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMetrics_WriteTo(t *testing.T) {
+	t.Run("renders counters per route and status", func(t *testing.T) {
+		metrics := NewMetrics()
+		metrics.ObserveRequest("GET /tasks", http.StatusOK, time.Millisecond)
+		metrics.ObserveRequest("GET /tasks", http.StatusOK, time.Millisecond)
+		metrics.ObserveRequest("GET /tasks", http.StatusBadRequest, time.Millisecond)
+		metrics.ObserveRequest("DELETE /tasks/{id}", http.StatusNoContent, time.Millisecond)
+
+		var out bytes.Buffer
+		_, err := metrics.WriteTo(&out)
+		require.NoError(t, err)
+
+		assert.Contains(t, out.String(), "# TYPE http_requests_total counter\n"+
+			`http_requests_total{route="DELETE /tasks/{id}",status="204"} 1`+"\n"+
+			`http_requests_total{route="GET /tasks",status="200"} 2`+"\n"+
+			`http_requests_total{route="GET /tasks",status="400"} 1`+"\n")
+	})
+	t.Run("renders cumulative latency buckets", func(t *testing.T) {
+		metrics := NewMetrics()
+		metrics.ObserveRequest("GET /health", http.StatusOK, 3*time.Millisecond)
+		metrics.ObserveRequest("GET /health", http.StatusOK, 200*time.Millisecond)
+		metrics.ObserveRequest("GET /health", http.StatusOK, time.Minute)
+
+		var out bytes.Buffer
+		_, err := metrics.WriteTo(&out)
+		require.NoError(t, err)
+
+		for _, line := range []string{
+			`http_request_duration_seconds_bucket{route="GET /health",le="0.005"} 1`,
+			`http_request_duration_seconds_bucket{route="GET /health",le="0.1"} 1`,
+			`http_request_duration_seconds_bucket{route="GET /health",le="0.25"} 2`,
+			`http_request_duration_seconds_bucket{route="GET /health",le="10"} 2`,
+			`http_request_duration_seconds_bucket{route="GET /health",le="+Inf"} 3`,
+			`http_request_duration_seconds_sum{route="GET /health"} 60.203`,
+			`http_request_duration_seconds_count{route="GET /health"} 3`,
+		} {
+			assert.Contains(t, out.String(), line+"\n")
+		}
+	})
+	t.Run("escapes label values", func(t *testing.T) {
+		metrics := NewMetrics()
+		metrics.AddTaskOperations(`say "hi"`+"\n", 1)
+
+		var out bytes.Buffer
+		_, err := metrics.WriteTo(&out)
+		require.NoError(t, err)
+
+		assert.Contains(t, out.String(), `task_operations_total{operation="say \"hi\"\n"} 1`)
+	})
+}
+
+func TestMetricsEndpoint(t *testing.T) {
+	store := &testhelpers.StubTaskStore{Tasks: map[int]string{1: "task 1"}}
+	svr := NewTasksServer(store, &StubAuthService{}, &StubAuth{}, dummyLogger)
+
+	svr.ServeHTTP(httptest.NewRecorder(), createTaskRequest(t, "task 2"))
+	svr.ServeHTTP(httptest.NewRecorder(), createTaskRequest(t, ""))
+	svr.ServeHTTP(httptest.NewRecorder(), deleteTaskRequest(t))
+	svr.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/no-such-route/x", nil))
+
+	response := httptest.NewRecorder()
+	svr.ServeHTTP(response, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+
+	assert.Equal(t, http.StatusOK, response.Code)
+	assert.Equal(t, metricsContentType, response.Header().Get("Content-Type"))
+	body := response.Body.String()
+	assert.Contains(t, body, `http_requests_total{route="POST /tasks",status="201"} 1`)
+	assert.Contains(t, body, `http_requests_total{route="POST /tasks",status="400"} 1`)
+	assert.Contains(t, body, `http_requests_total{route="DELETE /tasks/{id}",status="204"} 1`)
+	assert.Contains(t, body, `task_operations_total{operation="create"} 1`)
+	assert.Contains(t, body, `task_operations_total{operation="delete"} 1`)
+	assert.Contains(t, body, `http_requests_total{route="GET /",status="200"} 1`, "unknown paths are counted under the catch-all root route")
+	assert.False(t, strings.Contains(body, "no-such-route"))
+}
@@ -3,11 +3,14 @@ package webserver
 import (
 	"errors"
 	"log/slog"
+	"myproject/adapters/auth"
 	"myproject/application"
 	"myproject/domain"
 	"myproject/domain/validation"
 	"myproject/logger"
 	"net/http"
+	"strconv"
+	"strings"
 	"time"
 )
 
@@ -19,16 +22,69 @@ type HealthResponse struct {
 }
 
 // CreateTaskRequest represents the JSON payload for creating new tasks.
+// Done is optional and lets clients record an already completed task.
 type CreateTaskRequest struct {
-	Description string `json:"description"`
+	Description string     `json:"description"`
+	Done        bool       `json:"done,omitempty"`
+	DueDate     *time.Time `json:"due_date,omitempty"`
+	Priority    int        `json:"priority,omitempty"`
+	Tags        []string   `json:"tags,omitempty"`
 }
 
-// UpdateTaskRequest represents the JSON payload for updating tasks with optional fields.
-type UpdateTaskRequest struct {
+// CreateTasksResponse lists the IDs of a created batch in request order.
+type CreateTasksResponse struct {
+	IDs []int `json:"ids"`
+}
+
+// DeleteTasksRequest lists the IDs of the tasks to delete in one batch.
+type DeleteTasksRequest struct {
+	IDs []int `json:"ids"`
+}
+
+// DeleteTasksResponse reports how many tasks a batch deleted; IDs that were missing
+// or belong to another user are skipped and not counted.
+type DeleteTasksResponse struct {
+	Deleted int `json:"deleted"`
+}
+
+// ArchiveCompletedResponse reports how many done tasks were archived; tasks archived before are not counted.
+type ArchiveCompletedResponse struct {
+	Archived int `json:"archived"`
+}
+
+// BatchUpdateTaskRequest is one operation of a batch update; at least one of description and done is required.
+type BatchUpdateTaskRequest struct {
+	ID          int     `json:"id"`
 	Description *string `json:"description,omitempty"`
 	Done        *bool   `json:"done,omitempty"`
 }
 
+// BatchUpdateTasksResponse lists the updated tasks in request order.
+type BatchUpdateTasksResponse struct {
+	Tasks []domain.Task `json:"tasks"`
+}
+
+// UpdateTasksStatusResponse reports how many tasks changed status in a bulk update.
+type UpdateTasksStatusResponse struct {
+	Updated int `json:"updated"`
+}
+
+// UpdateTaskRequest represents the JSON payload for updating tasks.
+// PATCH accepts any subset of the fields; PUT replaces the task and requires description and done.
+type UpdateTaskRequest struct {
+	Description *string    `json:"description,omitempty"`
+	Done        *bool      `json:"done,omitempty"`
+	DueDate     *time.Time `json:"due_date,omitempty"`
+	Priority    *int       `json:"priority,omitempty"`
+	// Version is the task version the client last read; when set, the update fails with 409 if it changed since.
+	Version *int `json:"version,omitempty"`
+}
+
+// TagRequest is the JSON payload for tagging a task, e.g. {"tag":"work"}.
+type TagRequest struct {
+	Tag string `json:"tag"`
+}
+
 // RegisterRequest represents the JSON payload for user registration.
 // Contains email and password fields for creating a new account.
 type RegisterRequest struct {
@@ -43,6 +99,38 @@ type LoginRequest struct {
 	Password string `json:"password"`
 }
 
+// ChangePasswordRequest represents the JSON payload for changing the current user's password.
+type ChangePasswordRequest struct {
+	CurrentPassword string `json:"current_password"`
+	NewPassword     string `json:"new_password"`
+}
+
+// LogLevelRequest is the JSON payload for changing the server log level, e.g. {"level":"debug"}.
+// It is also returned with the level in effect after the change.
+type LogLevelRequest struct {
+	Level string `json:"level"`
+}
+
+// UserResponse describes the account the request's token belongs to.
+type UserResponse struct {
+	ID        int       `json:"id"`
+	Email     string    `json:"email"`
+	CreatedAt time.Time `json:"created_at"`
+	Role      string    `json:"role"`
+}
+
+// UsersResponse is one page of registered accounts for administrators.
+type UsersResponse struct {
+	Users  []UserResponse `json:"users"`
+	Limit  int            `json:"limit"`
+	Offset int            `json:"offset"`
+}
+
+// LimitsResponse reports the input limits the server enforces, so clients can check input before sending it.
+type LimitsResponse struct {
+	MaxDescriptionLength int `json:"max_description_length"`
+}
+
 // AuthResponse represents the JSON response for successful authentication.
 // Contains the JWT token and associated email address.
 type AuthResponse struct {
@@ -55,56 +143,271 @@ type Authenticator interface {
 }
 
 type TasksServer struct {
-	store          domain.Storage
-	service        domain.TaskService
-	authService    domain.AuthService
-	authMiddleware Authenticator
-	logger         *slog.Logger
+	store           domain.Storage
+	service         domain.TaskService
+	authService     domain.AuthService
+	authMiddleware  Authenticator
+	logger          *slog.Logger
+	latency         *LatencyTracker
+	metrics         *Metrics
+	serveUI         bool
+	authRateLimit   func(http.Handler) http.Handler
+	cors            CORSPolicy
+	strictOwnership bool
+	logLevel        *slog.LevelVar
+	idempotency     *IdempotencyCache
+	limits          LimitsResponse
+	maxBodyBytes    int64
+	defaultPageSize int
+	maxPageSize     int
+	slowRequest     time.Duration
+	routes          []route
 	http.Handler
 }
 
-func NewTasksServer(store domain.Storage, authService domain.AuthService, authMiddleware Authenticator, l *slog.Logger) *TasksServer {
+// Option configures optional TasksServer behaviour.
+type Option func(*TasksServer)
+
+// WithTaskService overrides the task service built from the store by default.
+func WithTaskService(service domain.TaskService) Option {
+	return func(ts *TasksServer) {
+		ts.service = service
+	}
+}
+
+// WithAuthRateLimit wraps /login and /register in the given limiting middleware.
+func WithAuthRateLimit(limit func(http.Handler) http.Handler) Option {
+	return func(ts *TasksServer) {
+		ts.authRateLimit = limit
+	}
+}
+
+// WithStrictOwnership makes task endpoints answer 403 instead of 404 when the
+// requested task exists but belongs to another user. It reveals which task IDs
+// exist, so it is meant for internal tooling and is off by default.
+func WithStrictOwnership(enabled bool) Option {
+	return func(ts *TasksServer) {
+		ts.strictOwnership = enabled
+	}
+}
+
+// WithLogLevel exposes PUT /admin/loglevel, which changes the given level at runtime
+// so debug logging can be switched on without restarting the server.
+func WithLogLevel(level *slog.LevelVar) Option {
+	return func(ts *TasksServer) {
+		ts.logLevel = level
+	}
+}
+
+// WithIdempotencyWindow sets how long an Idempotency-Key on POST /tasks is remembered.
+// A window of zero turns the header off, so every create makes a new task.
+func WithIdempotencyWindow(window time.Duration) Option {
+	return func(ts *TasksServer) {
+		ts.idempotency = nil
+		if window > 0 {
+			ts.idempotency = NewIdempotencyCache(window)
+		}
+	}
+}
+
+// WithMaxDescriptionLength reports the task service's description limit on GET /config/limits.
+// It should match the limit given to the task service; non-positive values keep the default.
+func WithMaxDescriptionLength(maxLength int) Option {
+	return func(ts *TasksServer) {
+		if maxLength > 0 {
+			ts.limits.MaxDescriptionLength = maxLength
+		}
+	}
+}
+
+// WithMaxBodyBytes limits the size of request bodies; larger JSON payloads are refused with 413.
+// Non-positive values keep DefaultMaxBodyBytes.
+func WithMaxBodyBytes(limit int64) Option {
+	return func(ts *TasksServer) {
+		if limit > 0 {
+			ts.maxBodyBytes = limit
+		}
+	}
+}
+
+// WithPageSizes sets the page size GET /tasks uses when no limit is given and the largest
+// limit it honours; larger limits are clamped and the response reports the limit applied.
+// Non-positive values keep domain.DefaultPageLimit and domain.MaxPageLimit.
+func WithPageSizes(defaultSize, maxSize int) Option {
+	return func(ts *TasksServer) {
+		if defaultSize > 0 {
+			ts.defaultPageSize = defaultSize
+		}
+		if maxSize > 0 {
+			ts.maxPageSize = maxSize
+		}
+	}
+}
+
+// WithSlowRequestThreshold makes requests that take longer than threshold complete with a WARN
+// log line carrying slow_request=true. Zero logs every request at INFO level.
+func WithSlowRequestThreshold(threshold time.Duration) Option {
+	return func(ts *TasksServer) {
+		ts.slowRequest = threshold
+	}
+}
+
+func NewTasksServer(store domain.Storage, authService domain.AuthService, authMiddleware Authenticator, l *slog.Logger, opts ...Option) *TasksServer {
 	ts := &TasksServer{}
 	ts.store = store
 	ts.authService = authService
 	ts.authMiddleware = authMiddleware
 	ts.service = application.NewService(store)
 	ts.logger = l
+	ts.latency = NewLatencyTracker(defaultLatencyWindow)
+	ts.metrics = NewMetrics()
+	ts.idempotency = NewIdempotencyCache(defaultIdempotencyWindow)
+	ts.limits = LimitsResponse{MaxDescriptionLength: validation.DefaultMaxDescriptionLength}
+	ts.maxBodyBytes = DefaultMaxBodyBytes
+	ts.defaultPageSize = domain.DefaultPageLimit
+	ts.maxPageSize = domain.MaxPageLimit
+	ts.slowRequest = logger.DefaultSlowRequestThreshold
+	for _, opt := range opts {
+		opt(ts)
+	}
 	router := http.NewServeMux()
+	ts.routes = ts.routeTable()
+	for _, route := range ts.routes {
+		router.Handle(route.pattern, route.handler)
+	}
 
-	router.Handle("GET /", http.HandlerFunc(ts.rootHandler))
-	router.Handle("GET /health", http.HandlerFunc(ts.healthHandler))
-	router.Handle("GET /tasks", ts.authMiddleware.Authenticate(ts.tasksHandler))
-	router.Handle("POST /tasks", ts.authMiddleware.Authenticate(ts.tasksHandler))
-	router.Handle("GET /tasks/{id}", ts.authMiddleware.Authenticate(ts.taskHandler))
-	router.Handle("PUT /tasks/{id}", ts.authMiddleware.Authenticate(ts.taskHandler))
-	router.Handle("DELETE /tasks/{id}", ts.authMiddleware.Authenticate(ts.taskHandler))
-	router.Handle("POST /register", http.HandlerFunc(ts.registerHandler))
-	router.Handle("POST /login", http.HandlerFunc(ts.loginHandler))
-
-	ts.Handler = logger.LoggingMiddleware(l)(router)
+	handler := latencyMiddleware(ts.latency, router)(metricsMiddleware(ts.metrics, router)(prettyJSONMiddleware(router)))
+	ts.Handler = logger.LoggingMiddleware(l, ts.slowRequest)(corsMiddleware(ts.cors)(maxBodyMiddleware(ts.maxBodyBytes)(handler)))
 	return ts
 }
 
-// rootHandler serves the API information and available endpoints.
+// limitAuth applies the auth rate limit, if one is configured, to a credentials endpoint.
+func (ts *TasksServer) limitAuth(handler http.Handler) http.Handler {
+	if ts.authRateLimit == nil {
+		return handler
+	}
+	return ts.authRateLimit(handler)
+}
+
+// route is one entry of the server's routing table: the mux pattern, the description
+// GET / lists it with, and the handler it is served by.
+type route struct {
+	pattern     string
+	description string
+	handler     http.Handler
+}
+
+// routeTable returns every route the server serves, in the order GET / lists them.
+// The mux, GET / and Endpoints are all built from it, so they cannot drift apart.
+func (ts *TasksServer) routeTable() []route {
+	authenticated := func(handler http.HandlerFunc) http.Handler {
+		return ts.authMiddleware.Authenticate(handler)
+	}
+	requireAdmin := auth.RequireRole(domain.RoleAdmin)
+	admin := func(handler http.HandlerFunc) http.Handler {
+		return ts.authMiddleware.Authenticate(requireAdmin(handler).ServeHTTP)
+	}
+
+	routes := []route{
+		{"GET /health", "Health check", http.HandlerFunc(ts.healthHandler)},
+		{"GET /config/limits", "Input limits the server enforces", http.HandlerFunc(ts.limitsHandler)},
+		{"GET /tasks", "Get tasks (?limit=&offset=&sort=&overdue=true&tag=)", authenticated(ts.tasksHandler)},
+		{"POST /tasks", "Add task", authenticated(ts.tasksHandler)},
+		{"GET /tasks/search", "Find tasks by description (?q=)", authenticated(ts.searchTasksHandler)},
+		{"GET /tasks/stats", "Count total, done and pending tasks", authenticated(ts.taskStatsHandler)},
+		{"GET /tasks/{id}", "Get task", authenticated(ts.taskHandler)},
+		{"PUT /tasks/{id}", "Replace task description and status", authenticated(ts.taskHandler)},
+		{"PATCH /tasks/{id}", "Update some task fields", authenticated(ts.taskHandler)},
+		{"DELETE /tasks/{id}", "Delete task", authenticated(ts.taskHandler)},
+		{"POST /tasks/{id}/restore", "Restore a deleted task", authenticated(ts.restoreTaskHandler)},
+		{"POST /tasks/{id}/archive", "Hide a task from the default list", authenticated(ts.archiveTaskHandler)},
+		{"POST /tasks/{id}/toggle", "Flip task done status", authenticated(ts.toggleTaskHandler)},
+		{"POST /tasks/{id}/tags", "Tag task", authenticated(ts.taskTagsHandler)},
+		{"DELETE /tasks/{id}/tags/{tag}", "Untag task", authenticated(ts.taskTagsHandler)},
+		{"POST /tasks/batch", "Add several tasks in one request", authenticated(ts.batchTasksHandler)},
+		{"DELETE /tasks/batch", "Delete several tasks by ID", authenticated(ts.batchDeleteTasksHandler)},
+		{"POST /tasks/batch-update", "Change description or status of several tasks at once", authenticated(ts.batchUpdateTasksHandler)},
+		{"PUT /tasks/status", "Mark all tasks done or undone (?done=true)", authenticated(ts.updateAllTaskStatusHandler)},
+		{"POST /tasks/archive-completed", "Archive every done task", authenticated(ts.archiveCompletedHandler)},
+		{"POST /register", "Register user", ts.limitAuth(http.HandlerFunc(ts.registerHandler))},
+		{"POST /login", "Login user", ts.limitAuth(http.HandlerFunc(ts.loginHandler))},
+		{"GET /me", "Current user", authenticated(ts.meHandler)},
+		{"DELETE /me", "Delete account and all its tasks", authenticated(ts.deleteMeHandler)},
+		{"PUT /me/password", "Change password", ts.limitAuth(authenticated(ts.changePasswordHandler))},
+		{"GET /admin/slow-endpoints", "Latency percentiles per route (admin role only)", admin(ts.slowEndpointsHandler)},
+		{"GET /admin/users", "List all accounts (admin role only)", admin(ts.listUsersHandler)},
+	}
+	if ts.logLevel != nil {
+		routes = append(routes, route{"PUT /admin/loglevel", "Change the log level at runtime (admin role only)", admin(ts.logLevelHandler)})
+	}
+	return append(routes,
+		route{"GET /metrics", "Prometheus metrics", http.HandlerFunc(ts.metricsHandler)},
+		route{"GET /", "This message", http.HandlerFunc(ts.rootHandler)},
+	)
+}
+
+// Endpoints returns the patterns of every route the server serves, as listed by GET /.
+func (ts *TasksServer) Endpoints() []string {
+	patterns := make([]string, len(ts.routes))
+	for i, route := range ts.routes {
+		patterns[i] = route.pattern
+	}
+	return patterns
+}
+
+// rootHandler serves the API information and available endpoints,
+// or the embedded web UI when it is enabled.
 func (ts *TasksServer) rootHandler(w http.ResponseWriter, r *http.Request) {
+	if ts.serveUI && r.URL.Path == "/" {
+		serveUI(w)
+		return
+	}
+	endpoints := make([]string, len(ts.routes))
+	for i, route := range ts.routes {
+		endpoints[i] = route.pattern + " - " + route.description
+	}
 	response := map[string]interface{}{
-		"message": "Task Manager API",
-		"endpoints": []string{
-			"GET /health - Health check",
-			"GET /tasks - Get tasks",
-			"POST /tasks - Add task",
-			"GET /tasks/{id} - Get task",
-			"PUT /tasks/{id} - Update task",
-			"DELETE /tasks/{id} - Delete task",
-			"POST /register - Register user",
-			"POST /login - Login user",
-			"GET / - This message",
-		},
+		"message":   "Task Manager API",
+		"endpoints": endpoints,
 	}
 	JSONSuccess(w, response)
 }
 
+// slowEndpointsHandler reports p50/p95/p99 latency per route over the recent window, slowest first.
+func (ts *TasksServer) slowEndpointsHandler(w http.ResponseWriter, r *http.Request) {
+	JSONSuccess(w, ts.latency.Summary())
+}
+
+// logLevelHandler sets the server log level to one of debug, info, warn or error.
+func (ts *TasksServer) logLevelHandler(w http.ResponseWriter, r *http.Request) {
+	var request LogLevelRequest
+	if err := ParseJSONRequest(w, r, &request); err != nil {
+		return
+	}
+	level, err := logger.ParseLevel(request.Level)
+	if err != nil {
+		JSONError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	userID, _ := application.GetUserIDFromContext(r.Context())
+	previous := ts.logLevel.Level()
+	ts.logLevel.Set(level)
+	ts.logger.Warn("Log level changed",
+		slog.String(logger.FieldOperation, "log_level_handler"),
+		slog.Int(logger.FieldUserID, userID),
+		slog.String("previous_level", previous.String()),
+		slog.String("level", level.String()),
+	)
+	JSONSuccess(w, LogLevelRequest{Level: strings.ToLower(level.String())})
+}
+
+// metricsHandler renders request and task operation counters in Prometheus text format.
+func (ts *TasksServer) metricsHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", metricsContentType)
+	ts.metrics.WriteTo(w)
+}
+
 // tasksHandler handles GET (list all tasks) and POST (create task) requests.
 func (ts *TasksServer) tasksHandler(w http.ResponseWriter, r *http.Request) {
 	userID, err := application.GetUserIDFromContext(r.Context())
@@ -124,12 +427,250 @@ func (ts *TasksServer) tasksHandler(w http.ResponseWriter, r *http.Request) {
 }
 
 func (ts *TasksServer) processLoadTasks(w http.ResponseWriter, r *http.Request, userID int) {
-	response, err := ts.store.LoadTasks(r.Context(), userID)
+	query := r.URL.Query()
+	var includeArchived bool
+	if value := query.Get("include_archived"); value != "" {
+		var err error
+		includeArchived, err = strconv.ParseBool(value)
+		if err != nil {
+			JSONError(w, http.StatusBadRequest, "Invalid include_archived parameter")
+			return
+		}
+	}
+	var overdue bool
+	if value := query.Get("overdue"); value != "" {
+		var err error
+		overdue, err = strconv.ParseBool(value)
+		if err != nil {
+			JSONError(w, http.StatusBadRequest, "Invalid overdue parameter")
+			return
+		}
+	}
+
+	opts, err := validation.ValidateListOptions(query.Get("limit"), query.Get("offset"))
+	if err != nil {
+		JSONError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if query.Get("limit") == "" {
+		opts.Limit = ts.defaultPageSize
+	}
+	// Clamp instead of rejecting, so clients asking for too much still get a page; TaskPage.Limit tells them
+	opts.Limit = min(opts.Limit, ts.maxPageSize)
+	opts.Sort, err = validation.ValidateTaskSort(query.Get("sort"))
+	if err != nil {
+		JSONError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	opts.Filter, err = validation.ValidateCreatedRange(query.Get("created_after"), query.Get("created_before"))
+	if err != nil {
+		JSONError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	opts.Filter.IncludeArchived = includeArchived
+	if query.Has("tag") {
+		opts.Filter.Tag, err = validation.NormalizeTag(query.Get("tag"))
+		if err != nil {
+			JSONError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+	}
+	if overdue {
+		now := time.Now()
+		opts.Filter.OverdueAt = &now
+		// Without an explicit sort the most urgent tasks come first
+		if query.Get("sort") == "" {
+			opts.Sort = domain.TaskSort{Field: domain.SortByDue}
+		}
+	}
+
+	tasks, err := ts.store.LoadTasks(r.Context(), userID, opts)
 	if err != nil {
 		JSONError(w, http.StatusInternalServerError, "Failed to load tasks")
 		return
 	}
-	JSONSuccess(w, response)
+
+	total, err := ts.store.CountTasks(r.Context(), userID, opts.Filter)
+	if err != nil {
+		JSONError(w, http.StatusInternalServerError, "Failed to load tasks")
+		return
+	}
+
+	SetPaginationHeaders(w, r.URL, opts.Limit, opts.Offset, total)
+	JSONSuccess(w, domain.TaskPage{
+		Tasks:  tasks,
+		Total:  total,
+		Limit:  opts.Limit,
+		Offset: opts.Offset,
+	})
+}
+
+// batchTasksHandler creates every task of a JSON array in one transaction.
+// An invalid description rejects the whole batch with 400 naming the offending index.
+func (ts *TasksServer) batchTasksHandler(w http.ResponseWriter, r *http.Request) {
+	userID, err := application.GetUserIDFromContext(r.Context())
+	if err != nil {
+		JSONError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	var requests []CreateTaskRequest
+	if err := ParseJSONRequest(w, r, &requests); err != nil {
+		return
+	}
+
+	tasks := make([]domain.Task, len(requests))
+	for i, req := range requests {
+		tasks[i] = domain.Task{Description: req.Description, Done: req.Done, DueDate: req.DueDate, Priority: req.Priority, Tags: req.Tags}
+	}
+
+	created, err := ts.service.CreateTasks(r.Context(), tasks, userID)
+	if err != nil {
+		status, message := ErrorToStatus(err)
+		if status == http.StatusInternalServerError {
+			ts.logTaskError(r, slog.LevelError, "Failed to create tasks in database", userID, 0, err)
+			JSONError(w, status, "Failed to create tasks")
+			return
+		}
+		ts.logTaskError(r, slog.LevelWarn, "Failed to validate batch", userID, 0, err)
+		JSONError(w, status, message)
+		return
+	}
+
+	ts.metrics.AddTaskOperations("create", len(created))
+	response := CreateTasksResponse{IDs: make([]int, len(created))}
+	for i, task := range created {
+		response.IDs[i] = task.ID
+	}
+	JSONResponse(w, http.StatusCreated, response)
+}
+
+// batchDeleteTasksHandler soft-deletes the tasks listed in a JSON {"ids": [...]} body in one transaction.
+// IDs the user does not own are skipped, so the response reports how many tasks were actually deleted.
+func (ts *TasksServer) batchDeleteTasksHandler(w http.ResponseWriter, r *http.Request) {
+	userID, err := application.GetUserIDFromContext(r.Context())
+	if err != nil {
+		JSONError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	var request DeleteTasksRequest
+	if err := ParseJSONRequest(w, r, &request); err != nil {
+		return
+	}
+	if err := validation.ValidateTaskIDs(request.IDs); err != nil {
+		ts.logTaskError(r, slog.LevelWarn, "Failed to validate batch", userID, 0, err)
+		JSONError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	deleted, err := ts.store.DeleteTasks(r.Context(), request.IDs, userID)
+	if err != nil {
+		ts.logTaskError(r, slog.LevelError, "Failed to delete tasks from database", userID, 0, err)
+		JSONError(w, http.StatusInternalServerError, "Failed to delete tasks")
+		return
+	}
+
+	ts.metrics.AddTaskOperations("delete", deleted)
+	JSONSuccess(w, DeleteTasksResponse{Deleted: deleted})
+}
+
+// batchUpdateTasksHandler applies a JSON array of {"id", "description", "done"} operations in one transaction.
+// If any operation is invalid or its task is missing, nothing is changed and the error names the offending index.
+func (ts *TasksServer) batchUpdateTasksHandler(w http.ResponseWriter, r *http.Request) {
+	userID, err := application.GetUserIDFromContext(r.Context())
+	if err != nil {
+		JSONError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	var requests []BatchUpdateTaskRequest
+	if err := ParseJSONRequest(w, r, &requests); err != nil {
+		return
+	}
+
+	updates := make([]domain.TaskUpdate, len(requests))
+	for i, req := range requests {
+		updates[i] = domain.TaskUpdate{ID: req.ID, Description: req.Description, Done: req.Done}
+	}
+
+	updated, err := ts.service.UpdateTasks(r.Context(), updates, userID)
+	if err != nil {
+		status, message := ErrorToStatus(err)
+		if status == http.StatusInternalServerError {
+			ts.logTaskError(r, slog.LevelError, "Failed to update tasks in database", userID, 0, err)
+			JSONError(w, status, "Failed to update tasks")
+			return
+		}
+		ts.logTaskError(r, slog.LevelWarn, "Failed to validate batch", userID, 0, err)
+		JSONError(w, status, message)
+		return
+	}
+
+	ts.metrics.AddTaskOperations("update", len(updated))
+	JSONSuccess(w, BatchUpdateTasksResponse{Tasks: updated})
+}
+
+// updateAllTaskStatusHandler marks every task of the user done or undone as given by the done query parameter.
+func (ts *TasksServer) updateAllTaskStatusHandler(w http.ResponseWriter, r *http.Request) {
+	userID, err := application.GetUserIDFromContext(r.Context())
+	if err != nil {
+		JSONError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	done, err := strconv.ParseBool(r.URL.Query().Get("done"))
+	if err != nil {
+		JSONError(w, http.StatusBadRequest, "Invalid done parameter")
+		return
+	}
+
+	updated, err := ts.store.UpdateAllTaskStatus(r.Context(), userID, done)
+	if err != nil {
+		ts.logTaskError(r, slog.LevelError, "Failed to update status of all tasks in database", userID, 0, err)
+		JSONError(w, http.StatusInternalServerError, "Failed to update tasks")
+		return
+	}
+
+	ts.metrics.AddTaskOperations("update", updated)
+	JSONSuccess(w, UpdateTasksStatusResponse{Updated: updated})
+}
+
+// searchTasksHandler returns the user's tasks whose description contains the q query parameter.
+func (ts *TasksServer) searchTasksHandler(w http.ResponseWriter, r *http.Request) {
+	userID, err := application.GetUserIDFromContext(r.Context())
+	if err != nil {
+		JSONError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	query, err := validation.ValidateSearchQuery(r.URL.Query().Get("q"))
+	if err != nil {
+		JSONError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	tasks, err := ts.store.SearchTasks(r.Context(), userID, query)
+	if err != nil {
+		ts.logTaskError(r, slog.LevelError, "Failed to search tasks in database", userID, 0, err)
+		JSONError(w, http.StatusInternalServerError, "Failed to search tasks")
+		return
+	}
+
+	JSONSuccess(w, tasks)
+}
+
+// taskStatsHandler returns how many of the user's tasks exist, are done and are pending.
+func (ts *TasksServer) taskStatsHandler(w http.ResponseWriter, r *http.Request) {
+	userID, err := application.GetUserIDFromContext(r.Context())
+	if err != nil {
+		JSONError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	stats, err := ts.store.TaskStats(r.Context(), userID)
+	if err != nil {
+		ts.logTaskError(r, slog.LevelError, "Failed to count task stats in database", userID, 0, err)
+		JSONError(w, http.StatusInternalServerError, "Failed to get task stats")
+		return
+	}
+
+	JSONSuccess(w, stats)
 }
 
 func (ts *TasksServer) processCreateTask(w http.ResponseWriter, r *http.Request, userID int) {
@@ -138,26 +679,69 @@ func (ts *TasksServer) processCreateTask(w http.ResponseWriter, r *http.Request,
 		return
 	}
 
-	task, err := ts.service.CreateTask(r.Context(), taskRequest.Description, userID)
+	key := r.Header.Get(IdempotencyKeyHeader)
+	if key != "" && ts.idempotency != nil {
+		if len(key) > maxIdempotencyKeyLength {
+			JSONError(w, http.StatusBadRequest, IdempotencyKeyHeader+" must be at most "+strconv.Itoa(maxIdempotencyKeyLength)+" characters")
+			return
+		}
+		taskID, reserved := ts.idempotency.Reserve(userID, key)
+		if !reserved {
+			ts.replayCreateTask(w, r, userID, taskID)
+			return
+		}
+	}
+
+	task, err := ts.service.CreateTask(r.Context(), taskRequest.Description, taskRequest.Done, taskRequest.DueDate, taskRequest.Priority, taskRequest.Tags, userID)
 	if err != nil {
+		if key != "" && ts.idempotency != nil {
+			ts.idempotency.Release(userID, key)
+		}
 		ts.handleCreateTaskError(w, r, userID, err)
 		return
 	}
+	if key != "" && ts.idempotency != nil {
+		ts.idempotency.Complete(userID, key, task.ID)
+	}
 
+	ts.metrics.AddTaskOperations("create", 1)
 	JSONResponse(w, http.StatusCreated, task)
 }
 
+// replayCreateTask answers a repeated Idempotency-Key with the task the first request created,
+// or 409 Conflict while that request is still running.
+func (ts *TasksServer) replayCreateTask(w http.ResponseWriter, r *http.Request, userID, taskID int) {
+	if taskID == 0 {
+		JSONError(w, http.StatusConflict, "A request with this Idempotency-Key is still in progress")
+		return
+	}
+
+	task, err := ts.store.GetTaskByID(r.Context(), taskID, userID)
+	if err != nil {
+		status, message := ErrorToStatus(err)
+		if status == http.StatusInternalServerError {
+			ts.logTaskError(r, slog.LevelError, "Failed to load task for repeated create", userID, taskID, err)
+			JSONError(w, status, "Failed to create task")
+			return
+		}
+		JSONError(w, status, message)
+		return
+	}
+	JSONSuccess(w, task)
+}
+
 func (ts *TasksServer) handleCreateTaskError(w http.ResponseWriter, r *http.Request, userID int, err error) {
-	if errors.Is(err, domain.ErrDescriptionRequired) || errors.Is(err, domain.ErrDescriptionTooLong) || errors.Is(err, domain.ErrEmptyFieldsToUpdate) {
-		ts.logTaskError(r, slog.LevelWarn, "Failed to validate description", userID, 0, err)
-		JSONError(w, http.StatusBadRequest, err.Error())
+	status, message := ErrorToStatus(err)
+	if status == http.StatusInternalServerError {
+		ts.logTaskError(r, slog.LevelError, "Failed to create task in database", userID, 0, err)
+		JSONError(w, status, "Failed to create task")
 		return
 	}
-	ts.logTaskError(r, slog.LevelError, "Failed to create task in database", userID, 0, err)
-	JSONError(w, http.StatusInternalServerError, "Failed to create task")
+	ts.logTaskError(r, slog.LevelWarn, "Failed to validate task", userID, 0, err)
+	JSONError(w, status, message)
 }
 
-// taskHandler handles GET, PUT, and DELETE operations for individual tasks by ID.
+// taskHandler handles GET, PUT, PATCH and DELETE operations for individual tasks by ID.
 func (ts *TasksServer) taskHandler(w http.ResponseWriter, r *http.Request) {
 	userID, err := application.GetUserIDFromContext(r.Context())
 	if err != nil {
@@ -167,6 +751,7 @@ func (ts *TasksServer) taskHandler(w http.ResponseWriter, r *http.Request) {
 	idStr := r.PathValue("id")
 	id, err := validation.ValidateTaskID(idStr)
 	if err != nil {
+		ts.logTaskError(r, slog.LevelWarn, "Invalid task ID in path", userID, 0, err)
 		JSONError(w, http.StatusBadRequest, "Invalid task ID")
 		return
 	}
@@ -174,64 +759,281 @@ func (ts *TasksServer) taskHandler(w http.ResponseWriter, r *http.Request) {
 	case http.MethodGet:
 		ts.processGetTaskByID(w, r, id, userID)
 	case http.MethodPut:
-		ts.processUpdateTask(w, r, id, userID)
+		ts.processUpdateTask(w, r, id, userID, true)
+	case http.MethodPatch:
+		ts.processUpdateTask(w, r, id, userID, false)
 	case http.MethodDelete:
 		ts.processDeleteTask(w, r, id, userID)
 	}
 }
 
+// processGetTaskByID returns the task with its ETag, or 304 Not Modified without a body
+// when If-None-Match shows the client already has this version.
 func (ts *TasksServer) processGetTaskByID(w http.ResponseWriter, r *http.Request, taskID int, userID int) {
 
 	response, err := ts.store.GetTaskByID(r.Context(), taskID, userID)
 	if err != nil {
+		err = ts.ownershipError(r, userID, taskID, err)
 		ts.logTaskError(r, slog.LevelWarn, "Failed to get task by ID from database", userID, taskID, err)
-		JSONError(w, http.StatusNotFound, "Task not found")
+		status, message := ErrorToStatus(err)
+		JSONError(w, status, message)
+		return
+	}
+	etag := TaskETag(response)
+	w.Header().Set("ETag", etag)
+	if etagMatches(r.Header.Get("If-None-Match"), etag) {
+		w.WriteHeader(http.StatusNotModified)
 		return
 	}
 	JSONSuccess(w, response)
 }
 
-func (ts *TasksServer) processUpdateTask(w http.ResponseWriter, r *http.Request, taskID int, userID int) {
+// processUpdateTask changes the fields given in the request. A replace (PUT) must carry the full
+// representation, so a missing description or done is refused instead of leaving it unchanged.
+func (ts *TasksServer) processUpdateTask(w http.ResponseWriter, r *http.Request, taskID int, userID int, replace bool) {
 	var taskRequest UpdateTaskRequest
 	if err := ParseJSONRequest(w, r, &taskRequest); err != nil {
 		return
 	}
+	if replace && (taskRequest.Description == nil || taskRequest.Done == nil) {
+		JSONError(w, http.StatusBadRequest, "PUT requires both description and done, use PATCH to change single fields")
+		return
+	}
 
-	task, err := ts.service.UpdateTask(r.Context(), taskID, userID, taskRequest.Description, taskRequest.Done)
+	task, err := ts.service.UpdateTask(r.Context(), taskID, userID, taskRequest.Description, taskRequest.Done, taskRequest.DueDate, taskRequest.Priority, taskRequest.Version)
 	if err != nil {
-		ts.handleUpdateTaskError(w, r, userID, taskID, err)
+		ts.handleUpdateTaskError(w, r, userID, taskID, ts.ownershipError(r, userID, taskID, err))
 		return
 	}
 
+	ts.metrics.AddTaskOperations("update", 1)
 	JSONSuccess(w, task)
 }
 
 func (ts *TasksServer) handleUpdateTaskError(w http.ResponseWriter, r *http.Request, userID, taskID int, err error) {
-	switch {
-	case errors.Is(err, domain.ErrDescriptionRequired),
-		errors.Is(err, domain.ErrDescriptionTooLong),
-		errors.Is(err, domain.ErrEmptyFieldsToUpdate):
-		ts.logTaskError(r, slog.LevelWarn, "Failed to validate description", userID, taskID, err)
-		JSONError(w, http.StatusBadRequest, err.Error())
-	case errors.Is(err, domain.ErrTaskNotFound):
-		ts.logTaskError(r, slog.LevelWarn, "Failed to get task by ID from database to update", userID, taskID, err)
-		JSONError(w, http.StatusNotFound, "Task not found")
-	default:
+	status, message := ErrorToStatus(err)
+	if status == http.StatusInternalServerError {
 		ts.logTaskError(r, slog.LevelError, "Failed to update task in database", userID, taskID, err)
-		JSONError(w, http.StatusInternalServerError, "Failed to update task")
+		JSONError(w, status, "Failed to update task")
+		return
 	}
+	ts.logTaskError(r, slog.LevelWarn, "Refused to update task", userID, taskID, err)
+	JSONError(w, status, message)
 }
 
+// processDeleteTask soft-deletes a task so it can be restored later;
+// ?permanent=true removes it from the database instead.
 func (ts *TasksServer) processDeleteTask(w http.ResponseWriter, r *http.Request, taskID, userID int) {
-	if err := ts.store.DeleteTask(r.Context(), taskID, userID); err != nil {
+	permanent := false
+	if value := r.URL.Query().Get("permanent"); value != "" {
+		var err error
+		permanent, err = strconv.ParseBool(value)
+		if err != nil {
+			JSONError(w, http.StatusBadRequest, "Invalid permanent parameter")
+			return
+		}
+	}
+
+	deleteTask, operation := ts.store.DeleteTask, "delete"
+	if permanent {
+		deleteTask, operation = ts.store.PurgeTask, "purge"
+	}
+	if err := deleteTask(r.Context(), taskID, userID); err != nil {
+		err = ts.ownershipError(r, userID, taskID, err)
 		ts.logTaskError(r, slog.LevelWarn, "Failed to delete task from database", userID, taskID, err)
-		JSONError(w, http.StatusNotFound, "Task not found")
+		status, message := ErrorToStatus(err)
+		JSONError(w, status, message)
 		return
 	}
 
+	ts.metrics.AddTaskOperations(operation, 1)
+
 	w.WriteHeader(http.StatusNoContent)
 }
 
+// ownershipError replaces ErrTaskNotFound with ErrTaskForbidden in strict ownership mode
+// when the task exists for another user. Other errors are returned unchanged, and a failed
+// existence check falls back to the original error.
+func (ts *TasksServer) ownershipError(r *http.Request, userID, taskID int, err error) error {
+	if !ts.strictOwnership || !errors.Is(err, domain.ErrTaskNotFound) {
+		return err
+	}
+	exists, existsErr := ts.store.TaskExists(r.Context(), taskID)
+	if existsErr != nil {
+		ts.logTaskError(r, slog.LevelError, "Failed to check task existence in database", userID, taskID, existsErr)
+		return err
+	}
+	if !exists {
+		return err
+	}
+	return domain.ErrTaskForbidden
+}
+
+// restoreTaskHandler undoes a soft delete and returns the restored task.
+func (ts *TasksServer) restoreTaskHandler(w http.ResponseWriter, r *http.Request) {
+	userID, err := application.GetUserIDFromContext(r.Context())
+	if err != nil {
+		JSONError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	id, err := validation.ValidateTaskID(r.PathValue("id"))
+	if err != nil {
+		ts.logTaskError(r, slog.LevelWarn, "Invalid task ID in path", userID, 0, err)
+		JSONError(w, http.StatusBadRequest, "Invalid task ID")
+		return
+	}
+
+	if err := ts.store.RestoreTask(r.Context(), id, userID); err != nil {
+		if errors.Is(err, domain.ErrTaskNotFound) {
+			ts.logTaskError(r, slog.LevelWarn, "Failed to find deleted task to restore", userID, id, err)
+			JSONError(w, http.StatusNotFound, "Deleted task not found")
+			return
+		}
+		ts.logTaskError(r, slog.LevelError, "Failed to restore task in database", userID, id, err)
+		JSONError(w, http.StatusInternalServerError, "Failed to restore task")
+		return
+	}
+	ts.metrics.AddTaskOperations("restore", 1)
+
+	task, err := ts.store.GetTaskByID(r.Context(), id, userID)
+	if err != nil {
+		ts.logTaskError(r, slog.LevelError, "Failed to get restored task from database", userID, id, err)
+		JSONError(w, http.StatusInternalServerError, "Failed to restore task")
+		return
+	}
+
+	JSONSuccess(w, task)
+}
+
+// archiveTaskHandler hides a task from the default task list and returns the archived task.
+func (ts *TasksServer) archiveTaskHandler(w http.ResponseWriter, r *http.Request) {
+	userID, err := application.GetUserIDFromContext(r.Context())
+	if err != nil {
+		JSONError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	id, err := validation.ValidateTaskID(r.PathValue("id"))
+	if err != nil {
+		ts.logTaskError(r, slog.LevelWarn, "Invalid task ID in path", userID, 0, err)
+		JSONError(w, http.StatusBadRequest, "Invalid task ID")
+		return
+	}
+
+	if err := ts.store.ArchiveTask(r.Context(), id, userID); err != nil {
+		err = ts.ownershipError(r, userID, id, err)
+		ts.logTaskError(r, slog.LevelWarn, "Failed to archive task in database", userID, id, err)
+		status, message := ErrorToStatus(err)
+		JSONError(w, status, message)
+		return
+	}
+	ts.metrics.AddTaskOperations("archive", 1)
+
+	task, err := ts.store.GetTaskByID(r.Context(), id, userID)
+	if err != nil {
+		ts.logTaskError(r, slog.LevelError, "Failed to get archived task from database", userID, id, err)
+		JSONError(w, http.StatusInternalServerError, "Failed to archive task")
+		return
+	}
+
+	JSONSuccess(w, task)
+}
+
+// archiveCompletedHandler archives every done task of the user and reports how many were archived.
+func (ts *TasksServer) archiveCompletedHandler(w http.ResponseWriter, r *http.Request) {
+	userID, err := application.GetUserIDFromContext(r.Context())
+	if err != nil {
+		JSONError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	archived, err := ts.store.ArchiveCompleted(r.Context(), userID)
+	if err != nil {
+		ts.logTaskError(r, slog.LevelError, "Failed to archive completed tasks in database", userID, 0, err)
+		JSONError(w, http.StatusInternalServerError, "Failed to archive tasks")
+		return
+	}
+
+	ts.metrics.AddTaskOperations("archive", archived)
+	JSONSuccess(w, ArchiveCompletedResponse{Archived: archived})
+}
+
+// toggleTaskHandler flips the done status of a task in one storage write and returns the changed task.
+func (ts *TasksServer) toggleTaskHandler(w http.ResponseWriter, r *http.Request) {
+	userID, err := application.GetUserIDFromContext(r.Context())
+	if err != nil {
+		JSONError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	id, err := validation.ValidateTaskID(r.PathValue("id"))
+	if err != nil {
+		ts.logTaskError(r, slog.LevelWarn, "Invalid task ID in path", userID, 0, err)
+		JSONError(w, http.StatusBadRequest, "Invalid task ID")
+		return
+	}
+
+	task, err := ts.service.ToggleTask(r.Context(), id, userID)
+	if err != nil {
+		ts.handleUpdateTaskError(w, r, userID, id, ts.ownershipError(r, userID, id, err))
+		return
+	}
+
+	ts.metrics.AddTaskOperations("update", 1)
+	JSONSuccess(w, task)
+}
+
+// taskTagsHandler tags a task with the tag from a JSON {"tag": "..."} body (POST)
+// or removes the tag named in the path (DELETE), and returns the changed task.
+func (ts *TasksServer) taskTagsHandler(w http.ResponseWriter, r *http.Request) {
+	userID, err := application.GetUserIDFromContext(r.Context())
+	if err != nil {
+		JSONError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	id, err := validation.ValidateTaskID(r.PathValue("id"))
+	if err != nil {
+		ts.logTaskError(r, slog.LevelWarn, "Invalid task ID in path", userID, 0, err)
+		JSONError(w, http.StatusBadRequest, "Invalid task ID")
+		return
+	}
+
+	input, changeTags := r.PathValue("tag"), ts.store.RemoveTag
+	if r.Method == http.MethodPost {
+		var request TagRequest
+		if err := ParseJSONRequest(w, r, &request); err != nil {
+			return
+		}
+		input, changeTags = request.Tag, ts.store.AddTag
+	}
+	tag, err := validation.NormalizeTag(input)
+	if err != nil {
+		JSONError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if err := changeTags(r.Context(), id, tag, userID); err != nil {
+		err = ts.ownershipError(r, userID, id, err)
+		status, message := ErrorToStatus(err)
+		if status == http.StatusInternalServerError {
+			ts.logTaskError(r, slog.LevelError, "Failed to change task tags in database", userID, id, err)
+			JSONError(w, status, "Failed to change task tags")
+			return
+		}
+		ts.logTaskError(r, slog.LevelWarn, "Refused to change task tags", userID, id, err)
+		JSONError(w, status, message)
+		return
+	}
+	ts.metrics.AddTaskOperations("update", 1)
+
+	task, err := ts.store.GetTaskByID(r.Context(), id, userID)
+	if err != nil {
+		ts.logTaskError(r, slog.LevelError, "Failed to get tagged task from database", userID, id, err)
+		JSONError(w, http.StatusInternalServerError, "Failed to change task tags")
+		return
+	}
+
+	JSONSuccess(w, task)
+}
+
 // healthHandler provides service health status information.
 func (ts *TasksServer) healthHandler(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodGet {
@@ -246,6 +1048,12 @@ func (ts *TasksServer) healthHandler(w http.ResponseWriter, r *http.Request) {
 	JSONSuccess(w, response)
 }
 
+// limitsHandler returns the input limits the server enforces. It needs no token,
+// so clients can read the limits before logging in.
+func (ts *TasksServer) limitsHandler(w http.ResponseWriter, r *http.Request) {
+	JSONSuccess(w, ts.limits)
+}
+
 // RegisterHandler creates a new user account and returns a JWT token.
 func (ts *TasksServer) registerHandler(w http.ResponseWriter, r *http.Request) {
 	var registerRequest RegisterRequest
@@ -259,18 +1067,15 @@ func (ts *TasksServer) registerHandler(w http.ResponseWriter, r *http.Request) {
 
 	token, err := ts.authService.Register(r.Context(), registerRequest.Email, registerRequest.Password)
 	if err != nil {
-		switch {
-		case errors.Is(err, domain.ErrInvalidEmail), errors.Is(err, domain.ErrPasswordTooLong), errors.Is(err, domain.ErrPasswordTooShort):
-			JSONError(w, http.StatusBadRequest, err.Error())
-		case errors.Is(err, domain.ErrEmailAlreadyExists):
-			JSONError(w, http.StatusConflict, err.Error())
-		default:
+		status, message := ErrorToStatus(err)
+		if status == http.StatusInternalServerError {
 			ts.logger.Error("Registration failed",
 				slog.String(logger.FieldOperation, "register_handler"),
 				slog.String(logger.FieldError, err.Error()),
 			)
-			JSONError(w, http.StatusInternalServerError, "registration failed")
+			message = "registration failed"
 		}
+		JSONError(w, status, message)
 		return
 	}
 
@@ -295,12 +1100,18 @@ func (ts *TasksServer) loginHandler(w http.ResponseWriter, r *http.Request) {
 
 	token, err := ts.authService.Login(r.Context(), loginRequest.Email, loginRequest.Password)
 	if err != nil {
-		ts.logger.Warn("Login failed",
+		status, message := ErrorToStatus(err)
+		level := slog.LevelWarn
+		if status == http.StatusInternalServerError {
+			level = slog.LevelError
+			message = "login failed"
+		}
+		ts.logger.Log(r.Context(), level, "Login failed",
 			slog.String(logger.FieldOperation, "login_handler"),
-			slog.String("email", loginRequest.Email),
+			slog.String(logger.FieldEmail, logger.MaskEmail(loginRequest.Email)),
 			slog.String(logger.FieldError, err.Error()),
 		)
-		JSONError(w, http.StatusUnauthorized, "invalid credentials")
+		JSONError(w, status, message)
 		return
 	}
 
@@ -310,6 +1121,135 @@ func (ts *TasksServer) loginHandler(w http.ResponseWriter, r *http.Request) {
 	JSONSuccess(w, authResp)
 }
 
+// meHandler returns the account of the authenticated user.
+// A token for an account that no longer exists is rejected with 401 so clients log in again.
+func (ts *TasksServer) meHandler(w http.ResponseWriter, r *http.Request) {
+	userID, err := application.GetUserIDFromContext(r.Context())
+	if err != nil {
+		JSONError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	user, err := ts.authService.CurrentUser(r.Context(), userID)
+	if err != nil {
+		if errors.Is(err, domain.ErrUserNotFound) {
+			ts.logger.Warn("Token user not found",
+				slog.String(logger.FieldOperation, "me_handler"),
+				slog.Int(logger.FieldUserID, userID),
+			)
+			JSONError(w, http.StatusUnauthorized, "User not found")
+			return
+		}
+		ts.logger.Error("Failed to get current user",
+			slog.String(logger.FieldOperation, "me_handler"),
+			slog.Int(logger.FieldUserID, userID),
+			slog.String(logger.FieldError, err.Error()),
+		)
+		JSONError(w, http.StatusInternalServerError, "Failed to get user")
+		return
+	}
+
+	JSONSuccess(w, UserResponse{ID: user.ID, Email: user.Email, CreatedAt: user.CreatedAt, Role: user.Role})
+}
+
+// listUsersHandler returns a page of all accounts ordered by ID; the route only lets admins through.
+// Paging works like GET /tasks, including the default and maximum page size.
+func (ts *TasksServer) listUsersHandler(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	opts, err := validation.ValidateListOptions(query.Get("limit"), query.Get("offset"))
+	if err != nil {
+		JSONError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if query.Get("limit") == "" {
+		opts.Limit = ts.defaultPageSize
+	}
+	opts.Limit = min(opts.Limit, ts.maxPageSize)
+
+	users, err := ts.authService.ListUsers(r.Context(), opts.Limit, opts.Offset)
+	if err != nil {
+		ts.logger.Error("Failed to list users",
+			slog.String(logger.FieldOperation, "list_users_handler"),
+			slog.String(logger.FieldError, err.Error()),
+		)
+		JSONError(w, http.StatusInternalServerError, "Failed to list users")
+		return
+	}
+
+	response := UsersResponse{Users: make([]UserResponse, len(users)), Limit: opts.Limit, Offset: opts.Offset}
+	for i, user := range users {
+		response.Users[i] = UserResponse{ID: user.ID, Email: user.Email, CreatedAt: user.CreatedAt, Role: user.Role}
+	}
+	JSONSuccess(w, response)
+}
+
+// deleteMeHandler deletes the account of the authenticated user together with its tasks.
+func (ts *TasksServer) deleteMeHandler(w http.ResponseWriter, r *http.Request) {
+	userID, err := application.GetUserIDFromContext(r.Context())
+	if err != nil {
+		JSONError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if err := ts.authService.DeleteAccount(r.Context(), userID); err != nil {
+		if errors.Is(err, domain.ErrUserNotFound) {
+			ts.logger.Warn("Token user not found",
+				slog.String(logger.FieldOperation, "delete_me_handler"),
+				slog.Int(logger.FieldUserID, userID),
+			)
+			JSONError(w, http.StatusUnauthorized, "User not found")
+			return
+		}
+		ts.logger.Error("Failed to delete account",
+			slog.String(logger.FieldOperation, "delete_me_handler"),
+			slog.Int(logger.FieldUserID, userID),
+			slog.String(logger.FieldError, err.Error()),
+		)
+		JSONError(w, http.StatusInternalServerError, "Failed to delete account")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// changePasswordHandler replaces the authenticated user's password after checking the current one.
+func (ts *TasksServer) changePasswordHandler(w http.ResponseWriter, r *http.Request) {
+	userID, err := application.GetUserIDFromContext(r.Context())
+	if err != nil {
+		JSONError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	var request ChangePasswordRequest
+	if err := ParseJSONRequest(w, r, &request); err != nil {
+		return
+	}
+	if request.CurrentPassword == "" || request.NewPassword == "" {
+		JSONError(w, http.StatusBadRequest, "Fields must be provided for password change")
+		return
+	}
+
+	err = ts.authService.ChangePassword(r.Context(), userID, request.CurrentPassword, request.NewPassword)
+	if err != nil {
+		status, message := ErrorToStatus(err)
+		switch {
+		case errors.Is(err, domain.ErrInvalidCredentials):
+			message = "current password is incorrect"
+		case status == http.StatusInternalServerError:
+			ts.logger.Error("Failed to change password",
+				slog.String(logger.FieldOperation, "change_password_handler"),
+				slog.Int(logger.FieldUserID, userID),
+				slog.String(logger.FieldError, err.Error()),
+			)
+			message = "Failed to change password"
+		}
+		JSONError(w, status, message)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
 func (ts *TasksServer) logTaskError(r *http.Request, level slog.Level, msg string, userID, taskID int, err error) {
 	ts.logger.Log(r.Context(), level, msg,
 		slog.String(logger.FieldOperation, "task_handler"),
@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"fmt"
 	"myproject/adapters/auth"
 	"myproject/adapters/storage"
 	"myproject/adapters/webserver"
@@ -13,11 +14,13 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"path/filepath"
+	"strings"
 	"sync"
 	"testing"
 	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestCreatingTasksAndRetrievingThem(t *testing.T) {
@@ -67,6 +70,12 @@ func loadTasksRequest(t *testing.T, token string) *http.Request {
 }
 
 func setupIntegrationTest(t *testing.T) (*webserver.TasksServer, string) {
+	server, authService := newIntegrationServer(t)
+	return server, registerAndLogin(t, authService, "test@email.com")
+}
+
+// newIntegrationServer wires a TasksServer to a real database and JWT authentication.
+func newIntegrationServer(t *testing.T, opts ...webserver.Option) (*webserver.TasksServer, *application.AuthService) {
 	ctx := context.Background()
 	testLogger, err := logger.NewLogger(&logger.Config{
 		Level:       "error",
@@ -89,19 +98,152 @@ func setupIntegrationTest(t *testing.T) (*webserver.TasksServer, string) {
 		store.Close(ctx)
 	})
 
-	jwtService := auth.NewJWTService("test-secret-key-minimum-32-chars!", 24*time.Hour)
+	jwtService := auth.NewJWTService("test-secret-key-minimum-32-chars!", 24*time.Hour, 30*time.Second)
 	authService := application.NewAuthService(store, jwtService, testLogger)
 	authMiddleware := webserver.NewAuthMiddleware(jwtService, testLogger)
 
-	server := webserver.NewTasksServer(store, authService, authMiddleware, testLogger)
+	server := webserver.NewTasksServer(store, authService, authMiddleware, testLogger, opts...)
+
+	return server, authService
+}
 
-	authService.Register(ctx, "test@email.com", "password123")
-	token, err := authService.Login(ctx, "test@email.com", "password123")
+func registerAndLogin(t *testing.T, authService *application.AuthService, email string) string {
+	t.Helper()
+	ctx := context.Background()
+	authService.Register(ctx, email, "password123")
+	token, err := authService.Login(ctx, email, "password123")
 	if err != nil {
 		t.Fatalf("failed to login: %v", err)
 	}
+	return token
+}
+
+func TestTaskOwnership(t *testing.T) {
+	tests := []struct {
+		name            string
+		strict          bool
+		expectedForeign int
+	}{
+		{name: "hides tasks of other users by default", strict: false, expectedForeign: http.StatusNotFound},
+		{name: "reports tasks of other users in strict mode", strict: true, expectedForeign: http.StatusForbidden},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server, authService := newIntegrationServer(t, webserver.WithStrictOwnership(tt.strict))
+			ownerToken := registerAndLogin(t, authService, "owner@email.com")
+			otherToken := registerAndLogin(t, authService, "other@email.com")
+
+			created := httptest.NewRecorder()
+			server.ServeHTTP(created, createTaskRequest(t, "owner task", ownerToken))
+			assert.Equal(t, http.StatusCreated, created.Code)
+			var task domain.Task
+			assert.NoError(t, json.NewDecoder(created.Body).Decode(&task))
+
+			send := func(method, url string) int {
+				var body []byte
+				switch method {
+				case http.MethodPut:
+					body = []byte(`{"description":"taken over","done":true}`)
+				case http.MethodPatch:
+					body = []byte(`{"done":true}`)
+				}
+				request := httptest.NewRequest(method, url, bytes.NewReader(body))
+				request.Header.Set("Content-Type", "application/json")
+				request.Header.Set("Authorization", "Bearer "+otherToken)
+				response := httptest.NewRecorder()
+				server.ServeHTTP(response, request)
+				return response.Code
+			}
+
+			for _, method := range []string{http.MethodGet, http.MethodPut, http.MethodPatch, http.MethodDelete} {
+				assert.Equal(t, tt.expectedForeign, send(method, fmt.Sprintf("/tasks/%d", task.ID)), "%s of another user's task", method)
+				assert.Equal(t, http.StatusNotFound, send(method, "/tasks/99999"), "%s of a missing task", method)
+			}
+		})
+	}
+}
+
+func TestIdempotentCreateTask(t *testing.T) {
+	server, token := setupIntegrationTest(t)
+
+	create := func(key string) (int, domain.Task) {
+		t.Helper()
+		request := createTaskRequest(t, "pay rent", token)
+		request.Header.Set(webserver.IdempotencyKeyHeader, key)
+		response := httptest.NewRecorder()
+		server.ServeHTTP(response, request)
+		var task domain.Task
+		require.NoError(t, json.NewDecoder(response.Body).Decode(&task))
+		return response.Code, task
+	}
+
+	t.Run("first call creates the task", func(t *testing.T) {
+		code, task := create("key-1")
+
+		assert.Equal(t, http.StatusCreated, code)
+		assert.NotZero(t, task.ID)
+	})
+
+	t.Run("repeated call returns the same task", func(t *testing.T) {
+		_, first := create("key-2")
+
+		code, repeated := create("key-2")
+
+		assert.Equal(t, http.StatusOK, code)
+		assert.Equal(t, first.ID, repeated.ID)
+		assert.Equal(t, "pay rent", repeated.Description)
+	})
+
+	t.Run("a new key creates another task", func(t *testing.T) {
+		_, first := create("key-3")
+
+		code, second := create("key-4")
+
+		assert.Equal(t, http.StatusCreated, code)
+		assert.NotEqual(t, first.ID, second.ID)
+	})
+
+	response := httptest.NewRecorder()
+	server.ServeHTTP(response, loadTasksRequest(t, token))
+	assert.Len(t, webserver.HandleLoadTasksResponse(t, response.Body), 4, "only the first call of each key stores a task")
+}
+
+func TestBatchDeleteTasks(t *testing.T) {
+	server, authService := newIntegrationServer(t)
+	ownerToken := registerAndLogin(t, authService, "owner@email.com")
+	otherToken := registerAndLogin(t, authService, "other@email.com")
+
+	create := func(token string) int {
+		t.Helper()
+		response := httptest.NewRecorder()
+		server.ServeHTTP(response, createTaskRequest(t, "task", token))
+		require.Equal(t, http.StatusCreated, response.Code)
+		var task domain.Task
+		require.NoError(t, json.NewDecoder(response.Body).Decode(&task))
+		return task.ID
+	}
+	first, second := create(ownerToken), create(ownerToken)
+	foreign := create(otherToken)
 
-	return server, token
+	send := func(body, token string) *httptest.ResponseRecorder {
+		request := httptest.NewRequest(http.MethodDelete, "/tasks/batch", strings.NewReader(body))
+		request.Header.Set("Content-Type", "application/json")
+		request.Header.Set("Authorization", "Bearer "+token)
+		response := httptest.NewRecorder()
+		server.ServeHTTP(response, request)
+		return response
+	}
+
+	response := send(fmt.Sprintf(`{"ids":[%d,%d,%d,99999]}`, first, second, foreign), ownerToken)
+	assert.Equal(t, http.StatusOK, response.Code)
+	assert.JSONEq(t, `{"deleted":2}`, response.Body.String())
+
+	response = send(fmt.Sprintf(`{"ids":[%d]}`, foreign), otherToken)
+	assert.JSONEq(t, `{"deleted":1}`, response.Body.String(), "tasks of other users are left untouched")
+
+	assert.Equal(t, http.StatusBadRequest, send(`{"ids":[]}`, ownerToken).Code)
+	assert.Equal(t, http.StatusBadRequest, send(`{"ids":[1,0]}`, ownerToken).Code)
 }
 
 func TestRaceDatabaseStorage(t *testing.T) {
@@ -122,8 +264,10 @@ func TestRaceDatabaseStorage(t *testing.T) {
 	}
 	wg.Wait()
 
+	request := loadTasksRequest(t, token)
+	request.URL.RawQuery = fmt.Sprintf("limit=%d", concurrentRequests)
 	response := httptest.NewRecorder()
-	server.ServeHTTP(response, loadTasksRequest(t, token))
+	server.ServeHTTP(response, request)
 	assert.Equal(t, http.StatusOK, response.Code)
 
 	got := webserver.HandleLoadTasksResponse(t, response.Body)
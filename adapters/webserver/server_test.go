@@ -4,16 +4,24 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"fmt"
 	"io"
 	"log/slog"
 	"myproject/application"
 	"myproject/domain"
+	"myproject/domain/validation"
 	"myproject/infrastructure/testhelpers"
+	"myproject/logger"
 	"net/http"
 	"net/http/httptest"
+	"slices"
+	"strconv"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 var (
@@ -23,12 +31,14 @@ var (
 
 type StubAuth struct {
 	authCalled int
+	role       string
 }
 
 func (sa *StubAuth) Authenticate(handler http.HandlerFunc) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		sa.authCalled++
 		ctx := context.WithValue(r.Context(), application.UserIDKey, 1)
+		ctx = context.WithValue(ctx, application.RoleKey, sa.role)
 		r = r.WithContext(ctx)
 		handler(w, r)
 	}
@@ -36,12 +46,20 @@ func (sa *StubAuth) Authenticate(handler http.HandlerFunc) http.HandlerFunc {
 
 type StubAuthService struct {
 	RegisterCalled []RegisterRequest
+	RegisterErr    error
 	LoginCalled    []string
+	Users          map[int]*domain.User
+
+	ChangePasswordCalled []ChangePasswordRequest
+	ChangePasswordErr    error
+
+	ListUsersLimit  int
+	ListUsersOffset int
 }
 
 func (sas *StubAuthService) Register(ctx context.Context, email, password string) (token string, err error) {
 	sas.RegisterCalled = append(sas.RegisterCalled, RegisterRequest{email, password})
-	return "", nil
+	return "", sas.RegisterErr
 }
 
 func (sas *StubAuthService) Login(ctx context.Context, email, password string) (token string, err error) {
@@ -49,6 +67,38 @@ func (sas *StubAuthService) Login(ctx context.Context, email, password string) (
 	return "", nil
 }
 
+func (sas *StubAuthService) CurrentUser(ctx context.Context, userID int) (*domain.User, error) {
+	user, ok := sas.Users[userID]
+	if !ok {
+		return nil, domain.ErrUserNotFound
+	}
+	return user, nil
+}
+
+func (sas *StubAuthService) DeleteAccount(ctx context.Context, userID int) error {
+	if _, ok := sas.Users[userID]; !ok {
+		return domain.ErrUserNotFound
+	}
+	delete(sas.Users, userID)
+	return nil
+}
+
+func (sas *StubAuthService) ListUsers(ctx context.Context, limit, offset int) ([]domain.User, error) {
+	sas.ListUsersLimit = limit
+	sas.ListUsersOffset = offset
+	users := make([]domain.User, 0, len(sas.Users))
+	for _, user := range sas.Users {
+		users = append(users, *user)
+	}
+	slices.SortFunc(users, func(a, b domain.User) int { return a.ID - b.ID })
+	return users, nil
+}
+
+func (sas *StubAuthService) ChangePassword(ctx context.Context, userID int, currentPassword, newPassword string) error {
+	sas.ChangePasswordCalled = append(sas.ChangePasswordCalled, ChangePasswordRequest{currentPassword, newPassword})
+	return sas.ChangePasswordErr
+}
+
 func TestHealth(t *testing.T) {
 	t.Run("returns status healthy", func(t *testing.T) {
 		store := &testhelpers.StubTaskStore{}
@@ -69,6 +119,59 @@ func TestHealth(t *testing.T) {
 	})
 }
 
+func TestPrettyJSON(t *testing.T) {
+	testCases := []struct {
+		name       string
+		url        string
+		wantPretty bool
+	}{
+		{name: "compact by default", url: "/config/limits", wantPretty: false},
+		{name: "indented with pretty=true", url: "/config/limits?pretty=true", wantPretty: true},
+		{name: "compact with pretty=false", url: "/config/limits?pretty=false", wantPretty: false},
+		{name: "indented error response", url: "/unknown?pretty=1", wantPretty: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			svr := NewTasksServer(&testhelpers.StubTaskStore{}, &StubAuthService{}, dummyAuthMiddleware, dummyLogger)
+			request := httptest.NewRequest(http.MethodGet, tc.url, nil)
+			response := httptest.NewRecorder()
+
+			svr.ServeHTTP(response, request)
+
+			assert.Equal(t, "application/json", response.Header().Get("Content-Type"))
+			assert.Equal(t, tc.wantPretty, strings.Contains(response.Body.String(), "{\n  \""), response.Body.String())
+			assert.True(t, json.Valid(response.Body.Bytes()))
+		})
+	}
+}
+
+func TestLimits(t *testing.T) {
+	testCases := []struct {
+		name     string
+		opts     []Option
+		expected LimitsResponse
+	}{
+		{name: "reports the default description limit", expected: LimitsResponse{MaxDescriptionLength: validation.DefaultMaxDescriptionLength}},
+		{name: "reports the configured description limit", opts: []Option{WithMaxDescriptionLength(500)}, expected: LimitsResponse{MaxDescriptionLength: 500}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			svr := NewTasksServer(&testhelpers.StubTaskStore{}, &StubAuthService{}, dummyAuthMiddleware, dummyLogger, tc.opts...)
+			request := httptest.NewRequest(http.MethodGet, "/config/limits", nil)
+			response := httptest.NewRecorder()
+
+			svr.ServeHTTP(response, request)
+
+			assert.Equal(t, http.StatusOK, response.Code)
+			var limits LimitsResponse
+			require.NoError(t, json.NewDecoder(response.Body).Decode(&limits))
+			assert.Equal(t, tc.expected, limits)
+		})
+	}
+}
+
 func TestRoot(t *testing.T) {
 
 	t.Run("returns 200 on /", func(t *testing.T) {
@@ -82,14 +185,57 @@ func TestRoot(t *testing.T) {
 		svr.ServeHTTP(response, request)
 
 		assert.Equal(t, http.StatusOK, response.Code)
+		assert.Equal(t, "application/json", response.Header().Get("Content-Type"))
+		assert.Contains(t, response.Body.String(), "Task Manager API")
+	})
+	t.Run("lists every route the server serves", func(t *testing.T) {
+		svr := NewTasksServer(&testhelpers.StubTaskStore{}, &StubAuthService{}, dummyAuthMiddleware, dummyLogger, WithLogLevel(new(slog.LevelVar)))
+		request, err := http.NewRequest(http.MethodGet, "/", nil)
+		assert.NoError(t, err)
+		response := httptest.NewRecorder()
+
+		svr.ServeHTTP(response, request)
+
+		var body struct {
+			Endpoints []string `json:"endpoints"`
+		}
+		require.NoError(t, json.NewDecoder(response.Body).Decode(&body))
+		patterns := svr.Endpoints()
+		require.Len(t, body.Endpoints, len(patterns))
+		for i, pattern := range patterns {
+			assert.True(t, strings.HasPrefix(body.Endpoints[i], pattern+" - "), body.Endpoints[i])
+		}
+		for _, want := range []string{"GET /config/limits", "POST /tasks/batch", "GET /tasks/search", "POST /tasks/{id}/restore",
+			"POST /tasks/{id}/archive", "POST /tasks/archive-completed", "PUT /admin/loglevel"} {
+			assert.Contains(t, patterns, want)
+		}
+	})
+	t.Run("leaves out the log level route when it is disabled", func(t *testing.T) {
+		svr := NewTasksServer(&testhelpers.StubTaskStore{}, &StubAuthService{}, dummyAuthMiddleware, dummyLogger)
+		assert.NotContains(t, svr.Endpoints(), "PUT /admin/loglevel")
+	})
+	t.Run("returns web UI on / when enabled", func(t *testing.T) {
+		store := &testhelpers.StubTaskStore{}
+		authService := &StubAuthService{}
+		svr := NewTasksServer(store, authService, dummyAuthMiddleware, dummyLogger, WithUI(true))
+		request, err := http.NewRequest(http.MethodGet, "/", nil)
+		assert.NoError(t, err)
+		response := httptest.NewRecorder()
+
+		svr.ServeHTTP(response, request)
+
+		assert.Equal(t, http.StatusOK, response.Code)
+		assert.Equal(t, "text/html; charset=utf-8", response.Header().Get("Content-Type"))
+		assert.Contains(t, response.Body.String(), "<title>Task Manager</title>")
 	})
 }
 
 func TestGetTaskByID(t *testing.T) {
 	store := &testhelpers.StubTaskStore{
 		Tasks: map[int]string{
-			1: "task 1",
-			2: "task 2",
+			1:                   "task 1",
+			2:                   "task 2",
+			9223372036854775807: "large task",
 		},
 	}
 	authService := &StubAuthService{}
@@ -119,6 +265,24 @@ func TestGetTaskByID(t *testing.T) {
 			expectedDescription: "",
 			expectedStatus:      http.StatusNotFound,
 		},
+		{
+			name:                "returns task by large in-range ID",
+			url:                 "/tasks/9223372036854775807",
+			expectedDescription: "large task",
+			expectedStatus:      http.StatusOK,
+		},
+		{
+			name:                "returns 400 on ID overflowing int64",
+			url:                 "/tasks/99999999999999999999999",
+			expectedDescription: "",
+			expectedStatus:      http.StatusBadRequest,
+		},
+		{
+			name:                "returns 400 on non-numeric ID",
+			url:                 "/tasks/abc",
+			expectedDescription: "",
+			expectedStatus:      http.StatusBadRequest,
+		},
 	}
 
 	for _, tt := range tests {
@@ -140,6 +304,53 @@ func TestGetTaskByID(t *testing.T) {
 	}
 }
 
+func TestGetTaskByID_ETag(t *testing.T) {
+	store := &testhelpers.StubTaskStore{Tasks: map[int]string{1: "task 1"}}
+	svr := NewTasksServer(store, &StubAuthService{}, &StubAuth{}, dummyLogger)
+
+	response := httptest.NewRecorder()
+	svr.ServeHTTP(response, getTaskByIDRequest(t, "/tasks/1"))
+	require.Equal(t, http.StatusOK, response.Code)
+	etag := response.Header().Get("ETag")
+	require.NotEmpty(t, etag)
+
+	testCases := []struct {
+		name           string
+		ifNoneMatch    string
+		expectedStatus int
+	}{
+		{name: "returns 304 for the current tag", ifNoneMatch: etag, expectedStatus: http.StatusNotModified},
+		{name: "returns 304 when the tag is one of several", ifNoneMatch: `W/"1-9-0", ` + etag, expectedStatus: http.StatusNotModified},
+		{name: "returns 304 for any tag", ifNoneMatch: "*", expectedStatus: http.StatusNotModified},
+		{name: "returns the task for an outdated tag", ifNoneMatch: `W/"1-9-0"`, expectedStatus: http.StatusOK},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			request := getTaskByIDRequest(t, "/tasks/1")
+			request.Header.Set("If-None-Match", tc.ifNoneMatch)
+			response := httptest.NewRecorder()
+
+			svr.ServeHTTP(response, request)
+
+			assert.Equal(t, tc.expectedStatus, response.Code)
+			assert.Equal(t, etag, response.Header().Get("ETag"))
+			if tc.expectedStatus == http.StatusNotModified {
+				assert.Empty(t, response.Body.String())
+			} else {
+				assert.Contains(t, response.Body.String(), "task 1")
+			}
+		})
+	}
+
+	t.Run("changes with the version", func(t *testing.T) {
+		task := domain.Task{ID: 1, Version: 1, UpdatedAt: time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)}
+		updated := task
+		updated.Version++
+		assert.NotEqual(t, TaskETag(task), TaskETag(updated))
+	})
+}
+
 func getTaskByIDRequest(t *testing.T, url string) *http.Request {
 	t.Helper()
 	request, err := http.NewRequest(http.MethodGet, url, nil)
@@ -177,189 +388,1033 @@ func TestCreateTask(t *testing.T) {
 		err := json.NewDecoder(response.Body).Decode(&task)
 		assert.NoError(t, err)
 		assert.Equal(t, http.StatusBadRequest, response.Code)
-		assert.True(t, task == domain.Task{})
+		assert.Zero(t, task)
 		assert.Equal(t, "application/json", response.Result().Header.Get("content-type"))
 		assert.Equal(t, 1, auth.authCalled)
 	})
-}
+	t.Run("returns done task on POST with done", func(t *testing.T) {
+		body := []byte(`{"description": "task 1", "done": true}`)
+		request, err := http.NewRequest(http.MethodPost, "/tasks", bytes.NewReader(body))
+		assert.NoError(t, err)
+		request.Header.Set("Content-Type", "application/json")
+		response := httptest.NewRecorder()
 
-func createTaskRequest(t *testing.T, desription string) *http.Request {
-	t.Helper()
-	task := domain.Task{Description: desription}
-	jsonTask, err := json.Marshal(task)
+		svr.ServeHTTP(response, request)
 
-	request, err := http.NewRequest(http.MethodPost, "/tasks", bytes.NewReader(jsonTask))
-	request.Header.Set("Content-Type", "application/json")
-	assert.NoError(t, err)
-	return request
-}
+		task := domain.Task{}
+		err = json.NewDecoder(response.Body).Decode(&task)
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusCreated, response.Code)
+		assert.True(t, task.Done)
+		assert.NotNil(t, task.CompletedAt)
+	})
+	t.Run("returns task with due date on POST with due_date", func(t *testing.T) {
+		body := []byte(`{"description": "task 1", "due_date": "2099-05-01T23:59:59+02:00"}`)
+		request, err := http.NewRequest(http.MethodPost, "/tasks", bytes.NewReader(body))
+		assert.NoError(t, err)
+		request.Header.Set("Content-Type", "application/json")
+		response := httptest.NewRecorder()
 
-func TestLoadTasks(t *testing.T) {
-	t.Run("returns tasks on GET /tasks", func(t *testing.T) {
-		tasksList := []domain.Task{
-			{Description: "task 1"},
-			{Description: "task 2"},
-			{Description: "task 3"},
+		svr.ServeHTTP(response, request)
+
+		task := domain.Task{}
+		err = json.NewDecoder(response.Body).Decode(&task)
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusCreated, response.Code)
+		if assert.NotNil(t, task.DueDate) {
+			assert.True(t, time.Date(2099, 5, 1, 21, 59, 59, 0, time.UTC).Equal(*task.DueDate))
 		}
-		store := &testhelpers.StubTaskStore{Tasks: nil, CreateCall: nil, TasksTable: tasksList}
-		auth := &StubAuth{authCalled: 0}
-		authService := &StubAuthService{}
-		svr := NewTasksServer(store, authService, auth, dummyLogger)
-		request := loadTasksRequest(t)
+	})
+	t.Run("returns 400 on due date in the past", func(t *testing.T) {
+		body := []byte(`{"description": "task 1", "due_date": "2000-01-01T00:00:00Z"}`)
+		request, err := http.NewRequest(http.MethodPost, "/tasks", bytes.NewReader(body))
+		assert.NoError(t, err)
+		request.Header.Set("Content-Type", "application/json")
 		response := httptest.NewRecorder()
 
 		svr.ServeHTTP(response, request)
 
-		expectedDescription := []string{"task 1", "task 2", "task 3"}
-		got := HandleLoadTasksResponse(t, response.Body)
-		assert.Equal(t, http.StatusOK, response.Code)
-		assert.ElementsMatch(t, expectedDescription, got)
-		assert.Equal(t, "application/json", response.Result().Header.Get("content-type"))
-		assert.Equal(t, 1, auth.authCalled)
+		assert.Equal(t, http.StatusBadRequest, response.Code)
 	})
-}
-
-func loadTasksRequest(t *testing.T) *http.Request {
-	t.Helper()
-	request, err := http.NewRequest(http.MethodGet, "/tasks", nil)
-	assert.NoError(t, err)
-	return request
-}
+	t.Run("returns task with priority on POST with priority", func(t *testing.T) {
+		body := []byte(`{"description": "task 1", "priority": 3}`)
+		request, err := http.NewRequest(http.MethodPost, "/tasks", bytes.NewReader(body))
+		assert.NoError(t, err)
+		request.Header.Set("Content-Type", "application/json")
+		response := httptest.NewRecorder()
 
-func HandleLoadTasksResponse(t testing.TB, body io.Reader) (descriptions []string) {
-	t.Helper()
-	tasks := []domain.Task{}
-	err := json.NewDecoder(body).Decode(&tasks)
+		svr.ServeHTTP(response, request)
 
-	if err != nil {
-		t.Fatalf("Unable to parse response from server %q into slice of Tasks, '%v'", body, err)
-	}
-	descriptions = make([]string, len(tasks))
-	for i, task := range tasks {
-		descriptions[i] = task.Description
-	}
+		task := domain.Task{}
+		err = json.NewDecoder(response.Body).Decode(&task)
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusCreated, response.Code)
+		assert.Equal(t, domain.PriorityHigh, task.Priority)
+	})
+	t.Run("returns 400 on priority out of range", func(t *testing.T) {
+		body := []byte(`{"description": "task 1", "priority": 4}`)
+		request, err := http.NewRequest(http.MethodPost, "/tasks", bytes.NewReader(body))
+		assert.NoError(t, err)
+		request.Header.Set("Content-Type", "application/json")
+		response := httptest.NewRecorder()
 
-	return
-}
+		svr.ServeHTTP(response, request)
 
-func TestUpdateTask(t *testing.T) {
-	store := &testhelpers.StubTaskStore{
-		Tasks: map[int]string{
-			1: "task 1",
-			2: "task 2",
-		},
-	}
-	authService := &StubAuthService{}
+		assert.Equal(t, http.StatusBadRequest, response.Code)
+		assert.Contains(t, response.Body.String(), domain.ErrInvalidPriority.Error())
+	})
+	t.Run("returns normalized tags on POST with tags", func(t *testing.T) {
+		body := []byte(`{"description": "task 1", "tags": ["Work", "home", "work"]}`)
+		request, err := http.NewRequest(http.MethodPost, "/tasks", bytes.NewReader(body))
+		assert.NoError(t, err)
+		request.Header.Set("Content-Type", "application/json")
+		response := httptest.NewRecorder()
 
-	t.Run("update task 1", func(t *testing.T) {
-		auth := &StubAuth{authCalled: 0}
-		svr := NewTasksServer(store, authService, auth, dummyLogger)
+		svr.ServeHTTP(response, request)
 
-		request := updateTaskRequest(t, "/tasks/1", "new task 1")
+		task := domain.Task{}
+		err = json.NewDecoder(response.Body).Decode(&task)
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusCreated, response.Code)
+		assert.Equal(t, []string{"home", "work"}, task.Tags)
+	})
+	t.Run("returns 400 on invalid tag", func(t *testing.T) {
+		body := []byte(`{"description": "task 1", "tags": ["day job"]}`)
+		request, err := http.NewRequest(http.MethodPost, "/tasks", bytes.NewReader(body))
+		assert.NoError(t, err)
+		request.Header.Set("Content-Type", "application/json")
 		response := httptest.NewRecorder()
 
 		svr.ServeHTTP(response, request)
 
-		assert.Equal(t, "new task 1", store.Tasks[1])
-		assert.Equal(t, http.StatusOK, response.Code)
-
-		assert.Equal(t, "application/json", response.Result().Header.Get("content-type"))
-		assert.Equal(t, 1, auth.authCalled)
+		assert.Equal(t, http.StatusBadRequest, response.Code)
+		assert.Contains(t, response.Body.String(), domain.ErrInvalidTag.Error())
 	})
-	t.Run("returns 400 on empty description", func(t *testing.T) {
-		auth := &StubAuth{authCalled: 0}
-		svr := NewTasksServer(store, authService, auth, dummyLogger)
-
-		request := updateTaskRequest(t, "/tasks/1", "")
+	t.Run("returns 400 on too long Idempotency-Key", func(t *testing.T) {
+		calls := len(store.CreateCall)
+		request := createTaskRequest(t, "task 1")
+		request.Header.Set(IdempotencyKeyHeader, strings.Repeat("k", maxIdempotencyKeyLength+1))
 		response := httptest.NewRecorder()
 
 		svr.ServeHTTP(response, request)
 
 		assert.Equal(t, http.StatusBadRequest, response.Code)
-		assert.Equal(t, "application/json", response.Result().Header.Get("content-type"))
-		assert.Equal(t, 1, auth.authCalled)
+		assert.Len(t, store.CreateCall, calls, "no task should be created")
 	})
-	t.Run("returns 404, if task not found", func(t *testing.T) {
-		auth := &StubAuth{authCalled: 0}
-		svr := NewTasksServer(store, authService, auth, dummyLogger)
+}
 
-		request := updateTaskRequest(t, "/tasks/404", "new task 404")
+func TestRequestBodyLimit(t *testing.T) {
+	store := &testhelpers.StubTaskStore{}
+	svr := NewTasksServer(store, &StubAuthService{}, &StubAuth{}, dummyLogger, WithMaxBodyBytes(1024))
+
+	t.Run("returns 413 for an oversized body", func(t *testing.T) {
+		body := fmt.Sprintf(`{"description": %q}`, strings.Repeat("a", 2000))
+		request, err := http.NewRequest(http.MethodPost, "/tasks", strings.NewReader(body))
+		require.NoError(t, err)
+		request.Header.Set("Content-Type", "application/json")
 		response := httptest.NewRecorder()
 
 		svr.ServeHTTP(response, request)
 
-		assert.Equal(t, "new task 1", store.Tasks[1])
-		assert.Equal(t, http.StatusNotFound, response.Code)
-		assert.Equal(t, "application/json", response.Result().Header.Get("content-type"))
-		assert.Equal(t, 1, auth.authCalled)
+		assert.Equal(t, http.StatusRequestEntityTooLarge, response.Code)
+		assert.Contains(t, response.Body.String(), "limit is 1024 bytes")
+		assert.Empty(t, store.CreateCall)
 	})
-}
+	t.Run("accepts a body within the limit", func(t *testing.T) {
+		request := createTaskRequest(t, "task 1")
+		response := httptest.NewRecorder()
 
-func updateTaskRequest(t *testing.T, url, description string) *http.Request {
-	t.Helper()
-	task := domain.Task{ID: 1, Description: description}
-	jsonTask, err := json.Marshal(task)
-	assert.NoError(t, err)
+		svr.ServeHTTP(response, request)
 
-	request, err := http.NewRequest(http.MethodPut, url, bytes.NewReader(jsonTask))
-	request.Header.Set("Content-Type", "application/json")
-	assert.NoError(t, err)
-	return request
+		assert.Equal(t, http.StatusCreated, response.Code)
+	})
 }
 
-func TestDeleteTask(t *testing.T) {
-	store := &testhelpers.StubTaskStore{
-		Tasks: map[int]string{
-			1: "task 1",
-			2: "task 2",
-		},
-	}
-	t.Run("delete task 1", func(t *testing.T) {
-		auth := &StubAuth{authCalled: 0}
-		authService := &StubAuthService{}
-		svr := NewTasksServer(store, authService, auth, dummyLogger)
-
-		request := deleteTaskRequest(t)
+func TestCreateTasks(t *testing.T) {
+	t.Run("returns 201 with ids in order on POST /tasks/batch", func(t *testing.T) {
+		service := &testhelpers.SpyTaskService{}
+		auth := &StubAuth{}
+		svr := NewTasksServer(&testhelpers.StubTaskStore{}, &StubAuthService{}, auth, dummyLogger, WithTaskService(service))
+		request := batchTasksRequest(t, `[{"description": "task 1"}, {"description": "task 2", "done": true}]`)
 		response := httptest.NewRecorder()
 
 		svr.ServeHTTP(response, request)
 
-		_, ok := store.Tasks[1]
-		assert.True(t, !ok)
-		assert.Equal(t, http.StatusNoContent, response.Code)
+		var got CreateTasksResponse
+		assert.NoError(t, json.NewDecoder(response.Body).Decode(&got))
+		assert.Equal(t, http.StatusCreated, response.Code)
+		assert.Equal(t, []int{1, 2}, got.IDs)
+		assert.Equal(t, []domain.Task{{Description: "task 1"}, {Description: "task 2", Done: true}}, service.LastBatch)
 		assert.Equal(t, 1, auth.authCalled)
 	})
+
+	invalidBatches := []struct {
+		name          string
+		body          string
+		expectedError string
+	}{
+		{name: "empty batch", body: `[]`, expectedError: domain.ErrEmptyBatch.Error()},
+		{name: "invalid description", body: `[{"description": "task 1"}, {"description": ""}]`, expectedError: "task 1: description is required"},
+		{name: "object instead of array", body: `{"description": "task 1"}`, expectedError: "Invalid JSON format"},
+	}
+	for _, tt := range invalidBatches {
+		t.Run("returns 400 on "+tt.name, func(t *testing.T) {
+			store := &testhelpers.StubTaskStore{}
+			svr := NewTasksServer(store, &StubAuthService{}, &StubAuth{}, dummyLogger)
+			response := httptest.NewRecorder()
+
+			svr.ServeHTTP(response, batchTasksRequest(t, tt.body))
+
+			var got map[string]string
+			assert.NoError(t, json.NewDecoder(response.Body).Decode(&got))
+			assert.Equal(t, http.StatusBadRequest, response.Code)
+			assert.Equal(t, tt.expectedError, got["error"])
+			assert.Empty(t, store.CreateCall)
+		})
+	}
 }
 
-func deleteTaskRequest(t *testing.T) *http.Request {
-	t.Helper()
+func TestBatchUpdateTasks(t *testing.T) {
+	t.Run("returns 200 with the updated tasks on POST /tasks/batch-update", func(t *testing.T) {
+		service := &testhelpers.SpyTaskService{}
+		auth := &StubAuth{}
+		svr := NewTasksServer(&testhelpers.StubTaskStore{}, &StubAuthService{}, auth, dummyLogger, WithTaskService(service))
+		request := batchUpdateTasksRequest(t, `[{"id": 1, "description": "task 1"}, {"id": 2, "done": true}]`)
+		response := httptest.NewRecorder()
 
-	request, err := http.NewRequest(http.MethodDelete, "/tasks/1", nil)
-	assert.NoError(t, err)
-	return request
-}
+		svr.ServeHTTP(response, request)
 
-func TestRegister(t *testing.T) {
+		var got BatchUpdateTasksResponse
+		assert.NoError(t, json.NewDecoder(response.Body).Decode(&got))
+		assert.Equal(t, http.StatusOK, response.Code)
+		assert.Equal(t, []domain.Task{{ID: 1, Description: "task 1", Version: 1}, {ID: 2, Done: true, Version: 1}}, got.Tasks)
+		require.Len(t, service.LastUpdates, 2)
+		assert.Equal(t, "task 1", *service.LastUpdates[0].Description)
+		assert.Nil(t, service.LastUpdates[0].Done)
+		assert.True(t, *service.LastUpdates[1].Done)
+		assert.Equal(t, 1, auth.authCalled)
+	})
 
-	t.Run("register test email", func(t *testing.T) {
-		store := &testhelpers.StubTaskStore{}
-		auth := &StubAuth{}
-		authService := &StubAuthService{}
-		svr := NewTasksServer(store, authService, auth, dummyLogger)
+	t.Run("returns 404 and changes nothing when one task is missing", func(t *testing.T) {
+		store := &testhelpers.StubTaskStore{Tasks: map[int]string{1: "task 1"}}
+		svr := NewTasksServer(store, &StubAuthService{}, &StubAuth{}, dummyLogger)
+		response := httptest.NewRecorder()
 
-		request := registerRequest(t)
+		svr.ServeHTTP(response, batchUpdateTasksRequest(t, `[{"id": 1, "description": "task 1 updated"}, {"id": 2, "done": true}]`))
+
+		var got map[string]string
+		assert.NoError(t, json.NewDecoder(response.Body).Decode(&got))
+		assert.Equal(t, http.StatusNotFound, response.Code)
+		assert.Equal(t, "task 1: task not found", got["error"])
+		assert.Equal(t, "task 1", store.Tasks[1])
+	})
+
+	t.Run("returns 400 on an operation without fields", func(t *testing.T) {
+		store := &testhelpers.StubTaskStore{Tasks: map[int]string{1: "task 1"}}
+		svr := NewTasksServer(store, &StubAuthService{}, &StubAuth{}, dummyLogger)
 		response := httptest.NewRecorder()
 
-		svr.ServeHTTP(response, request)
+		svr.ServeHTTP(response, batchUpdateTasksRequest(t, `[{"id": 1}]`))
 
-		assert.Equal(t, http.StatusCreated, response.Code)
-		assert.Equal(t, RegisterRequest{"test@email.com", "test_pass"}, authService.RegisterCalled[0])
+		var got map[string]string
+		assert.NoError(t, json.NewDecoder(response.Body).Decode(&got))
+		assert.Equal(t, http.StatusBadRequest, response.Code)
+		assert.Equal(t, "task 0: "+domain.ErrEmptyFieldsToUpdate.Error(), got["error"])
+		assert.Zero(t, store.UpdateTaskCalled)
 	})
 }
 
-func registerRequest(t *testing.T) *http.Request {
+func batchUpdateTasksRequest(t *testing.T, body string) *http.Request {
 	t.Helper()
-	reg := RegisterRequest{
+	request, err := http.NewRequest(http.MethodPost, "/tasks/batch-update", bytes.NewReader([]byte(body)))
+	assert.NoError(t, err)
+	request.Header.Set("Content-Type", "application/json")
+	return request
+}
+
+func batchTasksRequest(t *testing.T, body string) *http.Request {
+	t.Helper()
+	request, err := http.NewRequest(http.MethodPost, "/tasks/batch", bytes.NewReader([]byte(body)))
+	assert.NoError(t, err)
+	request.Header.Set("Content-Type", "application/json")
+	return request
+}
+
+func createTaskRequest(t *testing.T, desription string) *http.Request {
+	t.Helper()
+	task := domain.Task{Description: desription}
+	jsonTask, err := json.Marshal(task)
+
+	request, err := http.NewRequest(http.MethodPost, "/tasks", bytes.NewReader(jsonTask))
+	request.Header.Set("Content-Type", "application/json")
+	assert.NoError(t, err)
+	return request
+}
+
+func TestLoadTasks(t *testing.T) {
+	t.Run("returns tasks on GET /tasks", func(t *testing.T) {
+		tasksList := []domain.Task{
+			{Description: "task 1"},
+			{Description: "task 2"},
+			{Description: "task 3"},
+		}
+		store := &testhelpers.StubTaskStore{Tasks: nil, CreateCall: nil, TasksTable: tasksList}
+		auth := &StubAuth{authCalled: 0}
+		authService := &StubAuthService{}
+		svr := NewTasksServer(store, authService, auth, dummyLogger)
+		request := loadTasksRequest(t)
+		response := httptest.NewRecorder()
+
+		svr.ServeHTTP(response, request)
+
+		expectedDescription := []string{"task 1", "task 2", "task 3"}
+		got := HandleLoadTasksResponse(t, response.Body)
+		assert.Equal(t, http.StatusOK, response.Code)
+		assert.ElementsMatch(t, expectedDescription, got)
+		assert.Equal(t, "application/json", response.Result().Header.Get("content-type"))
+		assert.Equal(t, 1, auth.authCalled)
+		assert.Equal(t, domain.ListOptions{Limit: domain.DefaultPageLimit}, store.LastListOptions)
+	})
+	t.Run("returns requested page with total count", func(t *testing.T) {
+		tasksList := []domain.Task{
+			{Description: "task 1"},
+			{Description: "task 2"},
+			{Description: "task 3"},
+			{Description: "task 4"},
+		}
+		store := &testhelpers.StubTaskStore{TasksTable: tasksList}
+		svr := NewTasksServer(store, &StubAuthService{}, &StubAuth{}, dummyLogger)
+		request, err := http.NewRequest(http.MethodGet, "/tasks?limit=2&offset=1", nil)
+		assert.NoError(t, err)
+		response := httptest.NewRecorder()
+
+		svr.ServeHTTP(response, request)
+
+		var page domain.TaskPage
+		assert.NoError(t, json.NewDecoder(response.Body).Decode(&page))
+		assert.Equal(t, http.StatusOK, response.Code)
+		assert.Equal(t, domain.TaskPage{
+			Tasks:  []domain.Task{{Description: "task 2"}, {Description: "task 3"}},
+			Total:  4,
+			Limit:  2,
+			Offset: 1,
+		}, page)
+		assert.Equal(t, "4", response.Header().Get("X-Total-Count"))
+		assert.Equal(t, `</tasks?limit=2&offset=3>; rel="next", </tasks?limit=2&offset=0>; rel="prev"`, response.Header().Get("Link"))
+	})
+
+	t.Run("passes sort to storage", func(t *testing.T) {
+		store := &testhelpers.StubTaskStore{}
+		svr := NewTasksServer(store, &StubAuthService{}, &StubAuth{}, dummyLogger)
+		request, err := http.NewRequest(http.MethodGet, "/tasks?sort=-created", nil)
+		assert.NoError(t, err)
+		response := httptest.NewRecorder()
+
+		svr.ServeHTTP(response, request)
+
+		assert.Equal(t, http.StatusOK, response.Code)
+		assert.Equal(t, domain.TaskSort{Field: domain.SortByCreated, Descending: true}, store.LastListOptions.Sort)
+	})
+
+	t.Run("passes created range to storage", func(t *testing.T) {
+		store := &testhelpers.StubTaskStore{}
+		svr := NewTasksServer(store, &StubAuthService{}, &StubAuth{}, dummyLogger)
+		request, err := http.NewRequest(http.MethodGet, "/tasks?created_after=2025-01-01T00:00:00Z&created_before=2025-02-01T00:00:00%2B02:00", nil)
+		assert.NoError(t, err)
+		response := httptest.NewRecorder()
+
+		svr.ServeHTTP(response, request)
+
+		assert.Equal(t, http.StatusOK, response.Code)
+		filter := store.LastListOptions.Filter
+		require.NotNil(t, filter.CreatedAfter)
+		require.NotNil(t, filter.CreatedBefore)
+		assert.True(t, filter.CreatedAfter.Equal(time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)))
+		assert.True(t, filter.CreatedBefore.Equal(time.Date(2025, 1, 31, 22, 0, 0, 0, time.UTC)))
+	})
+
+	t.Run("passes include_archived to storage", func(t *testing.T) {
+		store := &testhelpers.StubTaskStore{}
+		svr := NewTasksServer(store, &StubAuthService{}, &StubAuth{}, dummyLogger)
+		request, err := http.NewRequest(http.MethodGet, "/tasks?include_archived=true", nil)
+		assert.NoError(t, err)
+		response := httptest.NewRecorder()
+
+		svr.ServeHTTP(response, request)
+
+		assert.Equal(t, http.StatusOK, response.Code)
+		assert.True(t, store.LastListOptions.Filter.IncludeArchived)
+	})
+
+	t.Run("lists overdue tasks earliest due first on GET /tasks?overdue=true", func(t *testing.T) {
+		store := &testhelpers.StubTaskStore{}
+		svr := NewTasksServer(store, &StubAuthService{}, &StubAuth{}, dummyLogger)
+		request, err := http.NewRequest(http.MethodGet, "/tasks?overdue=true", nil)
+		assert.NoError(t, err)
+		response := httptest.NewRecorder()
+
+		before := time.Now()
+		svr.ServeHTTP(response, request)
+
+		assert.Equal(t, http.StatusOK, response.Code)
+		filter := store.LastListOptions.Filter
+		if assert.NotNil(t, filter.OverdueAt) {
+			assert.WithinRange(t, *filter.OverdueAt, before, time.Now())
+		}
+		assert.False(t, filter.IncludeArchived)
+		assert.Equal(t, domain.TaskSort{Field: domain.SortByDue}, store.LastListOptions.Sort)
+		assert.Equal(t, domain.DefaultPageLimit, store.LastListOptions.Limit)
+	})
+
+	t.Run("pages and sorts overdue tasks like the full list", func(t *testing.T) {
+		store := &testhelpers.StubTaskStore{}
+		svr := NewTasksServer(store, &StubAuthService{}, &StubAuth{}, dummyLogger)
+		request, err := http.NewRequest(http.MethodGet, "/tasks?overdue=true&include_archived=true&limit=5&offset=10&sort=-created", nil)
+		assert.NoError(t, err)
+		response := httptest.NewRecorder()
+
+		svr.ServeHTTP(response, request)
+
+		assert.Equal(t, http.StatusOK, response.Code)
+		opts := store.LastListOptions
+		assert.NotNil(t, opts.Filter.OverdueAt)
+		assert.True(t, opts.Filter.IncludeArchived)
+		assert.Equal(t, domain.TaskSort{Field: domain.SortByCreated, Descending: true}, opts.Sort)
+		assert.Equal(t, 5, opts.Limit)
+		assert.Equal(t, 10, opts.Offset)
+	})
+
+	t.Run("lists tasks with the normalized tag on GET /tasks?tag=", func(t *testing.T) {
+		store := &testhelpers.StubTaskStore{}
+		svr := NewTasksServer(store, &StubAuthService{}, &StubAuth{}, dummyLogger)
+		request, err := http.NewRequest(http.MethodGet, "/tasks?tag=+Work+", nil)
+		assert.NoError(t, err)
+		response := httptest.NewRecorder()
+
+		svr.ServeHTTP(response, request)
+
+		assert.Equal(t, http.StatusOK, response.Code)
+		assert.Equal(t, "work", store.LastListOptions.Filter.Tag)
+		assert.False(t, store.LastListOptions.Filter.IncludeArchived)
+		assert.Equal(t, domain.DefaultPageLimit, store.LastListOptions.Limit)
+	})
+
+	t.Run("combines the tag with the other list parameters", func(t *testing.T) {
+		store := &testhelpers.StubTaskStore{}
+		svr := NewTasksServer(store, &StubAuthService{}, &StubAuth{}, dummyLogger)
+		request, err := http.NewRequest(http.MethodGet, "/tasks?tag=work&overdue=true&include_archived=true&limit=5&offset=10&sort=id&created_after=2025-01-01T00:00:00Z", nil)
+		assert.NoError(t, err)
+		response := httptest.NewRecorder()
+
+		svr.ServeHTTP(response, request)
+
+		assert.Equal(t, http.StatusOK, response.Code)
+		opts := store.LastListOptions
+		assert.Equal(t, "work", opts.Filter.Tag)
+		assert.NotNil(t, opts.Filter.OverdueAt)
+		assert.NotNil(t, opts.Filter.CreatedAfter)
+		assert.True(t, opts.Filter.IncludeArchived)
+		assert.Equal(t, domain.TaskSort{Field: domain.SortByID}, opts.Sort)
+		assert.Equal(t, 5, opts.Limit)
+		assert.Equal(t, 10, opts.Offset)
+	})
+
+	invalidQueries := []struct {
+		name  string
+		query string
+	}{
+		{name: "negative limit", query: "limit=-1"},
+		{name: "zero limit", query: "limit=0"},
+		{name: "non-numeric limit", query: "limit=ten"},
+		{name: "negative offset", query: "offset=-5"},
+		{name: "non-numeric offset", query: "offset=first"},
+		{name: "unknown sort key", query: "sort=color"},
+		{name: "invalid overdue flag", query: "overdue=maybe"},
+		{name: "invalid include_archived flag", query: "include_archived=sometimes"},
+		{name: "empty tag", query: "tag="},
+		{name: "tag with a comma", query: "tag=work,home"},
+		{name: "date-only created_after", query: "created_after=2025-01-01"},
+		{name: "malformed created_before", query: "created_before=yesterday"},
+		{name: "created_after later than created_before", query: "created_after=2025-02-01T00:00:00Z&created_before=2025-01-01T00:00:00Z"},
+	}
+	for _, tt := range invalidQueries {
+		t.Run("returns 400 on "+tt.name, func(t *testing.T) {
+			store := &testhelpers.StubTaskStore{}
+			svr := NewTasksServer(store, &StubAuthService{}, &StubAuth{}, dummyLogger)
+			request, err := http.NewRequest(http.MethodGet, "/tasks?"+tt.query, nil)
+			assert.NoError(t, err)
+			response := httptest.NewRecorder()
+
+			svr.ServeHTTP(response, request)
+
+			assert.Equal(t, http.StatusBadRequest, response.Code)
+		})
+	}
+}
+
+func loadTasksRequest(t *testing.T) *http.Request {
+	t.Helper()
+	request, err := http.NewRequest(http.MethodGet, "/tasks", nil)
+	assert.NoError(t, err)
+	return request
+}
+
+func HandleLoadTasksResponse(t testing.TB, body io.Reader) (descriptions []string) {
+	t.Helper()
+	var page domain.TaskPage
+	err := json.NewDecoder(body).Decode(&page)
+
+	if err != nil {
+		t.Fatalf("Unable to parse response from server %q into a page of Tasks, '%v'", body, err)
+	}
+	descriptions = make([]string, len(page.Tasks))
+	for i, task := range page.Tasks {
+		descriptions[i] = task.Description
+	}
+
+	return
+}
+
+func TestLoadTasksPageSize(t *testing.T) {
+	testCases := []struct {
+		name          string
+		query         string
+		expectedLimit int
+	}{
+		{name: "limit above the maximum is clamped", query: "?limit=1000000", expectedLimit: 20},
+		{name: "default applied without a limit", query: "", expectedLimit: 5},
+		{name: "limit within range is kept", query: "?limit=12", expectedLimit: 12},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			store := &testhelpers.StubTaskStore{TasksTable: []domain.Task{{Description: "task 1"}}}
+			svr := NewTasksServer(store, &StubAuthService{}, &StubAuth{}, dummyLogger, WithPageSizes(5, 20))
+			request := httptest.NewRequest(http.MethodGet, "/tasks"+tc.query, nil)
+			response := httptest.NewRecorder()
+
+			svr.ServeHTTP(response, request)
+
+			var page domain.TaskPage
+			require.NoError(t, json.NewDecoder(response.Body).Decode(&page))
+			assert.Equal(t, http.StatusOK, response.Code)
+			assert.Equal(t, tc.expectedLimit, page.Limit)
+			assert.Equal(t, tc.expectedLimit, store.LastListOptions.Limit)
+		})
+	}
+
+	t.Run("maximum defaults to domain.MaxPageLimit", func(t *testing.T) {
+		store := &testhelpers.StubTaskStore{}
+		svr := NewTasksServer(store, &StubAuthService{}, &StubAuth{}, dummyLogger)
+		request := httptest.NewRequest(http.MethodGet, "/tasks?limit=5000", nil)
+		response := httptest.NewRecorder()
+
+		svr.ServeHTTP(response, request)
+
+		assert.Equal(t, http.StatusOK, response.Code)
+		assert.Equal(t, domain.MaxPageLimit, store.LastListOptions.Limit)
+	})
+}
+
+func TestSearchTasks(t *testing.T) {
+	t.Run("returns matching tasks on GET /tasks/search", func(t *testing.T) {
+		tasksList := []domain.Task{
+			{ID: 1, Description: "Buy milk"},
+			{ID: 2, Description: "Walk the dog"},
+			{ID: 3, Description: "buy bread"},
+		}
+		store := &testhelpers.StubTaskStore{TasksTable: tasksList}
+		auth := &StubAuth{}
+		svr := NewTasksServer(store, &StubAuthService{}, auth, dummyLogger)
+		request, err := http.NewRequest(http.MethodGet, "/tasks/search?q=+BUY+", nil)
+		assert.NoError(t, err)
+		response := httptest.NewRecorder()
+
+		svr.ServeHTTP(response, request)
+
+		var got []domain.Task
+		assert.NoError(t, json.NewDecoder(response.Body).Decode(&got))
+		assert.Equal(t, http.StatusOK, response.Code)
+		assert.Equal(t, []domain.Task{tasksList[0], tasksList[2]}, got)
+		assert.Equal(t, "BUY", store.LastSearchQuery)
+		assert.Equal(t, 1, auth.authCalled)
+	})
+	t.Run("returns 400 on empty query", func(t *testing.T) {
+		store := &testhelpers.StubTaskStore{}
+		svr := NewTasksServer(store, &StubAuthService{}, &StubAuth{}, dummyLogger)
+		request, err := http.NewRequest(http.MethodGet, "/tasks/search?q=%20", nil)
+		assert.NoError(t, err)
+		response := httptest.NewRecorder()
+
+		svr.ServeHTTP(response, request)
+
+		assert.Equal(t, http.StatusBadRequest, response.Code)
+		assert.Empty(t, store.LastSearchQuery)
+	})
+}
+
+func TestTaskStats(t *testing.T) {
+	t.Run("returns counts on GET /tasks/stats", func(t *testing.T) {
+		store := &testhelpers.StubTaskStore{TasksTable: []domain.Task{
+			{ID: 1, Description: "task 1", Done: true},
+			{ID: 2, Description: "task 2"},
+			{ID: 3, Description: "task 3"},
+		}}
+		auth := &StubAuth{}
+		svr := NewTasksServer(store, &StubAuthService{}, auth, dummyLogger)
+		request, err := http.NewRequest(http.MethodGet, "/tasks/stats", nil)
+		assert.NoError(t, err)
+		response := httptest.NewRecorder()
+
+		svr.ServeHTTP(response, request)
+
+		var got domain.Stats
+		assert.NoError(t, json.NewDecoder(response.Body).Decode(&got))
+		assert.Equal(t, http.StatusOK, response.Code)
+		assert.Equal(t, domain.Stats{Total: 3, Done: 1, Pending: 2}, got)
+		assert.Equal(t, 1, auth.authCalled)
+	})
+	t.Run("returns zero counts without tasks", func(t *testing.T) {
+		svr := NewTasksServer(&testhelpers.StubTaskStore{}, &StubAuthService{}, &StubAuth{}, dummyLogger)
+		request, err := http.NewRequest(http.MethodGet, "/tasks/stats", nil)
+		assert.NoError(t, err)
+		response := httptest.NewRecorder()
+
+		svr.ServeHTTP(response, request)
+
+		assert.Equal(t, http.StatusOK, response.Code)
+		assert.JSONEq(t, `{"total":0,"done":0,"pending":0}`, response.Body.String())
+	})
+}
+
+func TestUpdateAllTaskStatus(t *testing.T) {
+	t.Run("marks all tasks done on PUT /tasks/status", func(t *testing.T) {
+		store := &testhelpers.StubTaskStore{TasksTable: []domain.Task{
+			{ID: 1, Description: "task 1", Done: true},
+			{ID: 2, Description: "task 2"},
+			{ID: 3, Description: "task 3"},
+		}}
+		auth := &StubAuth{}
+		svr := NewTasksServer(store, &StubAuthService{}, auth, dummyLogger)
+		request, err := http.NewRequest(http.MethodPut, "/tasks/status?done=true", nil)
+		assert.NoError(t, err)
+		response := httptest.NewRecorder()
+
+		svr.ServeHTTP(response, request)
+
+		assert.Equal(t, http.StatusOK, response.Code)
+		assert.JSONEq(t, `{"updated":2}`, response.Body.String())
+		assert.Equal(t, 1, auth.authCalled)
+	})
+	for _, query := range []string{"", "?done=maybe"} {
+		t.Run("returns 400 on done "+query, func(t *testing.T) {
+			svr := NewTasksServer(&testhelpers.StubTaskStore{}, &StubAuthService{}, &StubAuth{}, dummyLogger)
+			request, err := http.NewRequest(http.MethodPut, "/tasks/status"+query, nil)
+			assert.NoError(t, err)
+			response := httptest.NewRecorder()
+
+			svr.ServeHTTP(response, request)
+
+			assert.Equal(t, http.StatusBadRequest, response.Code)
+		})
+	}
+}
+
+func TestArchiveCompleted(t *testing.T) {
+	store := &testhelpers.StubTaskStore{
+		Tasks:     map[int]string{1: "task 1", 2: "task 2", 3: "task 3"},
+		DoneTasks: map[int]bool{1: true, 3: true},
+	}
+	auth := &StubAuth{}
+	svr := NewTasksServer(store, &StubAuthService{}, auth, dummyLogger)
+	request, err := http.NewRequest(http.MethodPost, "/tasks/archive-completed", nil)
+	assert.NoError(t, err)
+	response := httptest.NewRecorder()
+
+	svr.ServeHTTP(response, request)
+
+	assert.Equal(t, http.StatusOK, response.Code)
+	assert.JSONEq(t, `{"archived":2}`, response.Body.String())
+	assert.ElementsMatch(t, []int{1, 3}, store.ArchivedTaskIDs)
+	assert.Equal(t, 1, auth.authCalled)
+}
+
+func TestUpdateTask(t *testing.T) {
+	store := &testhelpers.StubTaskStore{
+		Tasks: map[int]string{
+			1: "task 1",
+			2: "task 2",
+		},
+	}
+	authService := &StubAuthService{}
+
+	t.Run("update task 1", func(t *testing.T) {
+		auth := &StubAuth{authCalled: 0}
+		svr := NewTasksServer(store, authService, auth, dummyLogger)
+
+		request := updateTaskRequest(t, "/tasks/1", "new task 1")
+		response := httptest.NewRecorder()
+
+		svr.ServeHTTP(response, request)
+
+		assert.Equal(t, "new task 1", store.Tasks[1])
+		assert.Equal(t, http.StatusOK, response.Code)
+
+		assert.Equal(t, "application/json", response.Result().Header.Get("content-type"))
+		assert.Equal(t, 1, auth.authCalled)
+	})
+	t.Run("passes priority to the service", func(t *testing.T) {
+		service := &testhelpers.SpyTaskService{}
+		svr := NewTasksServer(store, authService, &StubAuth{}, dummyLogger, WithTaskService(service))
+
+		request, err := http.NewRequest(http.MethodPatch, "/tasks/1", strings.NewReader(`{"priority": 2}`))
+		require.NoError(t, err)
+		request.Header.Set("Content-Type", "application/json")
+		response := httptest.NewRecorder()
+
+		svr.ServeHTTP(response, request)
+
+		assert.Equal(t, http.StatusOK, response.Code)
+		if assert.NotNil(t, service.UpdatePriority) {
+			assert.Equal(t, domain.PriorityMedium, *service.UpdatePriority)
+		}
+		assert.Nil(t, service.UpdateDescription)
+	})
+	t.Run("returns 409 when the version is stale", func(t *testing.T) {
+		service := &testhelpers.SpyTaskService{ResultErr: domain.ErrVersionConflict}
+		svr := NewTasksServer(store, authService, &StubAuth{}, dummyLogger, WithTaskService(service))
+
+		request, err := http.NewRequest(http.MethodPatch, "/tasks/1", strings.NewReader(`{"done": true, "version": 2}`))
+		require.NoError(t, err)
+		request.Header.Set("Content-Type", "application/json")
+		response := httptest.NewRecorder()
+
+		svr.ServeHTTP(response, request)
+
+		assert.Equal(t, http.StatusConflict, response.Code)
+		assert.Contains(t, response.Body.String(), domain.ErrVersionConflict.Error())
+		if assert.NotNil(t, service.UpdateVersion) {
+			assert.Equal(t, 2, *service.UpdateVersion)
+		}
+	})
+	t.Run("returns 400 on PUT without done", func(t *testing.T) {
+		service := &testhelpers.SpyTaskService{}
+		svr := NewTasksServer(store, authService, &StubAuth{}, dummyLogger, WithTaskService(service))
+
+		request, err := http.NewRequest(http.MethodPut, "/tasks/1", strings.NewReader(`{"description": "new task 1"}`))
+		require.NoError(t, err)
+		request.Header.Set("Content-Type", "application/json")
+		response := httptest.NewRecorder()
+
+		svr.ServeHTTP(response, request)
+
+		assert.Equal(t, http.StatusBadRequest, response.Code)
+		assert.Contains(t, response.Body.String(), "use PATCH")
+		assert.Zero(t, service.LastTaskID, "service must not be called")
+	})
+	t.Run("updates only the given field on PATCH", func(t *testing.T) {
+		service := &testhelpers.SpyTaskService{}
+		svr := NewTasksServer(store, authService, &StubAuth{}, dummyLogger, WithTaskService(service))
+
+		request, err := http.NewRequest(http.MethodPatch, "/tasks/1", strings.NewReader(`{"description": "new task 1"}`))
+		require.NoError(t, err)
+		request.Header.Set("Content-Type", "application/json")
+		response := httptest.NewRecorder()
+
+		svr.ServeHTTP(response, request)
+
+		assert.Equal(t, http.StatusOK, response.Code)
+		if assert.NotNil(t, service.UpdateDescription) {
+			assert.Equal(t, "new task 1", *service.UpdateDescription)
+		}
+		assert.Nil(t, service.UpdateDone)
+	})
+	t.Run("returns 400 on empty description", func(t *testing.T) {
+		auth := &StubAuth{authCalled: 0}
+		svr := NewTasksServer(store, authService, auth, dummyLogger)
+
+		request := updateTaskRequest(t, "/tasks/1", "")
+		response := httptest.NewRecorder()
+
+		svr.ServeHTTP(response, request)
+
+		assert.Equal(t, http.StatusBadRequest, response.Code)
+		assert.Equal(t, "application/json", response.Result().Header.Get("content-type"))
+		assert.Equal(t, 1, auth.authCalled)
+	})
+	t.Run("returns 404, if task not found", func(t *testing.T) {
+		auth := &StubAuth{authCalled: 0}
+		svr := NewTasksServer(store, authService, auth, dummyLogger)
+
+		request := updateTaskRequest(t, "/tasks/404", "new task 404")
+		response := httptest.NewRecorder()
+
+		svr.ServeHTTP(response, request)
+
+		assert.Equal(t, "new task 1", store.Tasks[1])
+		assert.Equal(t, http.StatusNotFound, response.Code)
+		assert.Equal(t, "application/json", response.Result().Header.Get("content-type"))
+		assert.Equal(t, 1, auth.authCalled)
+	})
+}
+
+func updateTaskRequest(t *testing.T, url, description string) *http.Request {
+	t.Helper()
+	task := domain.Task{ID: 1, Description: description}
+	jsonTask, err := json.Marshal(task)
+	assert.NoError(t, err)
+
+	request, err := http.NewRequest(http.MethodPut, url, bytes.NewReader(jsonTask))
+	request.Header.Set("Content-Type", "application/json")
+	assert.NoError(t, err)
+	return request
+}
+
+func TestDeleteTask(t *testing.T) {
+	store := &testhelpers.StubTaskStore{
+		Tasks: map[int]string{
+			1: "task 1",
+			2: "task 2",
+		},
+	}
+	t.Run("delete task 1", func(t *testing.T) {
+		auth := &StubAuth{authCalled: 0}
+		authService := &StubAuthService{}
+		svr := NewTasksServer(store, authService, auth, dummyLogger)
+
+		request := deleteTaskRequest(t)
+		response := httptest.NewRecorder()
+
+		svr.ServeHTTP(response, request)
+
+		_, ok := store.Tasks[1]
+		assert.True(t, !ok)
+		assert.Equal(t, http.StatusNoContent, response.Code)
+		assert.Equal(t, 1, auth.authCalled)
+	})
+	t.Run("restore deleted task 1", func(t *testing.T) {
+		auth := &StubAuth{authCalled: 0}
+		svr := NewTasksServer(store, &StubAuthService{}, auth, dummyLogger)
+
+		request, err := http.NewRequest(http.MethodPost, "/tasks/1/restore", nil)
+		assert.NoError(t, err)
+		response := httptest.NewRecorder()
+
+		svr.ServeHTTP(response, request)
+
+		var got domain.Task
+		assert.NoError(t, json.NewDecoder(response.Body).Decode(&got))
+		assert.Equal(t, http.StatusOK, response.Code)
+		assert.Equal(t, domain.Task{ID: 1, Description: "task 1"}, got)
+		assert.Equal(t, "task 1", store.Tasks[1])
+		assert.Equal(t, 1, auth.authCalled)
+	})
+	t.Run("returns 404 when restoring a task that is not deleted", func(t *testing.T) {
+		svr := NewTasksServer(store, &StubAuthService{}, &StubAuth{}, dummyLogger)
+
+		request, err := http.NewRequest(http.MethodPost, "/tasks/2/restore", nil)
+		assert.NoError(t, err)
+		response := httptest.NewRecorder()
+
+		svr.ServeHTTP(response, request)
+
+		assert.Equal(t, http.StatusNotFound, response.Code)
+	})
+	t.Run("archive task 2", func(t *testing.T) {
+		auth := &StubAuth{}
+		svr := NewTasksServer(store, &StubAuthService{}, auth, dummyLogger)
+
+		request, err := http.NewRequest(http.MethodPost, "/tasks/2/archive", nil)
+		assert.NoError(t, err)
+		response := httptest.NewRecorder()
+
+		svr.ServeHTTP(response, request)
+
+		var got domain.Task
+		assert.NoError(t, json.NewDecoder(response.Body).Decode(&got))
+		assert.Equal(t, http.StatusOK, response.Code)
+		assert.Equal(t, 2, got.ID)
+		assert.Equal(t, []int{2}, store.ArchivedTaskIDs)
+		assert.Equal(t, 1, auth.authCalled)
+	})
+	t.Run("returns 404 when archiving a missing task", func(t *testing.T) {
+		svr := NewTasksServer(store, &StubAuthService{}, &StubAuth{}, dummyLogger)
+
+		request, err := http.NewRequest(http.MethodPost, "/tasks/99/archive", nil)
+		assert.NoError(t, err)
+		response := httptest.NewRecorder()
+
+		svr.ServeHTTP(response, request)
+
+		assert.Equal(t, http.StatusNotFound, response.Code)
+	})
+	t.Run("permanent delete purges task 2", func(t *testing.T) {
+		svr := NewTasksServer(store, &StubAuthService{}, &StubAuth{}, dummyLogger)
+
+		request, err := http.NewRequest(http.MethodDelete, "/tasks/2?permanent=true", nil)
+		assert.NoError(t, err)
+		response := httptest.NewRecorder()
+
+		svr.ServeHTTP(response, request)
+
+		_, ok := store.Tasks[2]
+		assert.False(t, ok)
+		assert.NotContains(t, store.DeletedTasks, 2)
+		assert.Equal(t, []int{2}, store.PurgedTaskIDs)
+		assert.Equal(t, http.StatusNoContent, response.Code)
+	})
+	t.Run("returns 400 on invalid permanent parameter", func(t *testing.T) {
+		svr := NewTasksServer(store, &StubAuthService{}, &StubAuth{}, dummyLogger)
+
+		request, err := http.NewRequest(http.MethodDelete, "/tasks/1?permanent=maybe", nil)
+		assert.NoError(t, err)
+		response := httptest.NewRecorder()
+
+		svr.ServeHTTP(response, request)
+
+		assert.Equal(t, http.StatusBadRequest, response.Code)
+		assert.Equal(t, "task 1", store.Tasks[1])
+	})
+}
+
+func TestToggleTask(t *testing.T) {
+	store := &testhelpers.StubTaskStore{
+		Tasks: map[int]string{1: "task 1"},
+	}
+	svr := NewTasksServer(store, &StubAuthService{}, &StubAuth{}, dummyLogger)
+
+	t.Run("flips the status of task 1 on POST /tasks/1/toggle", func(t *testing.T) {
+		for _, want := range []bool{true, false} {
+			request, err := http.NewRequest(http.MethodPost, "/tasks/1/toggle", nil)
+			assert.NoError(t, err)
+			response := httptest.NewRecorder()
+
+			svr.ServeHTTP(response, request)
+
+			var got domain.Task
+			assert.NoError(t, json.NewDecoder(response.Body).Decode(&got))
+			assert.Equal(t, http.StatusOK, response.Code)
+			assert.Equal(t, 1, got.ID)
+			assert.Equal(t, want, got.Done)
+		}
+	})
+	t.Run("returns 404 if task not found", func(t *testing.T) {
+		request, err := http.NewRequest(http.MethodPost, "/tasks/2/toggle", nil)
+		assert.NoError(t, err)
+		response := httptest.NewRecorder()
+
+		svr.ServeHTTP(response, request)
+
+		assert.Equal(t, http.StatusNotFound, response.Code)
+	})
+	t.Run("returns 400 on invalid task ID", func(t *testing.T) {
+		request, err := http.NewRequest(http.MethodPost, "/tasks/abc/toggle", nil)
+		assert.NoError(t, err)
+		response := httptest.NewRecorder()
+
+		svr.ServeHTTP(response, request)
+
+		assert.Equal(t, http.StatusBadRequest, response.Code)
+	})
+}
+
+func TestTaskTags(t *testing.T) {
+	store := &testhelpers.StubTaskStore{
+		Tasks: map[int]string{1: "task 1"},
+	}
+	svr := NewTasksServer(store, &StubAuthService{}, &StubAuth{}, dummyLogger)
+
+	t.Run("tags task 1 on POST /tasks/1/tags", func(t *testing.T) {
+		request, err := http.NewRequest(http.MethodPost, "/tasks/1/tags", strings.NewReader(`{"tag": " Work "}`))
+		assert.NoError(t, err)
+		request.Header.Set("Content-Type", "application/json")
+		response := httptest.NewRecorder()
+
+		svr.ServeHTTP(response, request)
+
+		var got domain.Task
+		assert.NoError(t, json.NewDecoder(response.Body).Decode(&got))
+		assert.Equal(t, http.StatusOK, response.Code)
+		assert.Equal(t, []string{"work"}, got.Tags)
+	})
+	t.Run("untags task 1 on DELETE /tasks/1/tags/work", func(t *testing.T) {
+		request, err := http.NewRequest(http.MethodDelete, "/tasks/1/tags/WORK", nil)
+		assert.NoError(t, err)
+		response := httptest.NewRecorder()
+
+		svr.ServeHTTP(response, request)
+
+		var got domain.Task
+		assert.NoError(t, json.NewDecoder(response.Body).Decode(&got))
+		assert.Equal(t, http.StatusOK, response.Code)
+		assert.Empty(t, got.Tags)
+	})
+	t.Run("returns 400 on invalid tag", func(t *testing.T) {
+		request, err := http.NewRequest(http.MethodPost, "/tasks/1/tags", strings.NewReader(`{"tag": "day job"}`))
+		assert.NoError(t, err)
+		request.Header.Set("Content-Type", "application/json")
+		response := httptest.NewRecorder()
+
+		svr.ServeHTTP(response, request)
+
+		assert.Equal(t, http.StatusBadRequest, response.Code)
+		assert.Contains(t, response.Body.String(), domain.ErrInvalidTag.Error())
+	})
+	t.Run("returns 404 if task not found", func(t *testing.T) {
+		request, err := http.NewRequest(http.MethodPost, "/tasks/2/tags", strings.NewReader(`{"tag": "work"}`))
+		assert.NoError(t, err)
+		request.Header.Set("Content-Type", "application/json")
+		response := httptest.NewRecorder()
+
+		svr.ServeHTTP(response, request)
+
+		assert.Equal(t, http.StatusNotFound, response.Code)
+	})
+}
+
+func deleteTaskRequest(t *testing.T) *http.Request {
+	t.Helper()
+
+	request, err := http.NewRequest(http.MethodDelete, "/tasks/1", nil)
+	assert.NoError(t, err)
+	return request
+}
+
+func TestRegister(t *testing.T) {
+
+	t.Run("register test email", func(t *testing.T) {
+		store := &testhelpers.StubTaskStore{}
+		auth := &StubAuth{}
+		authService := &StubAuthService{}
+		svr := NewTasksServer(store, authService, auth, dummyLogger)
+
+		request := registerRequest(t)
+		response := httptest.NewRecorder()
+
+		svr.ServeHTTP(response, request)
+
+		assert.Equal(t, http.StatusCreated, response.Code)
+		assert.Equal(t, RegisterRequest{"test@email.com", "test_pass"}, authService.RegisterCalled[0])
+	})
+	t.Run("weak password returns 400 with the broken rule", func(t *testing.T) {
+		store := &testhelpers.StubTaskStore{}
+		auth := &StubAuth{}
+		authService := &StubAuthService{RegisterErr: domain.ErrPasswordMissingDigit}
+		svr := NewTasksServer(store, authService, auth, dummyLogger)
+
+		request := registerRequest(t)
+		response := httptest.NewRecorder()
+
+		svr.ServeHTTP(response, request)
+
+		assert.Equal(t, http.StatusBadRequest, response.Code)
+		assert.Contains(t, response.Body.String(), domain.ErrPasswordMissingDigit.Error())
+	})
+}
+
+func registerRequest(t *testing.T) *http.Request {
+	t.Helper()
+	reg := RegisterRequest{
 		Email:    "test@email.com",
 		Password: "test_pass",
 	}
@@ -406,6 +1461,236 @@ func loginRequest(t *testing.T) *http.Request {
 	return request
 }
 
+func TestSetPaginationHeaders(t *testing.T) {
+	testCases := []struct {
+		name     string
+		url      string
+		limit    int
+		offset   int
+		total    int
+		wantLink string
+	}{
+		{
+			name:     "first page links only to next",
+			url:      "/tasks?limit=10&sort=-created",
+			limit:    10,
+			offset:   0,
+			total:    25,
+			wantLink: `</tasks?limit=10&offset=10&sort=-created>; rel="next"`,
+		},
+		{
+			name:     "middle page links to next and prev",
+			url:      "/tasks?limit=10&offset=10",
+			limit:    10,
+			offset:   10,
+			total:    25,
+			wantLink: `</tasks?limit=10&offset=20>; rel="next", </tasks?limit=10&offset=0>; rel="prev"`,
+		},
+		{
+			name:     "last page links only to prev",
+			url:      "/tasks?limit=10&offset=20",
+			limit:    10,
+			offset:   20,
+			total:    25,
+			wantLink: `</tasks?limit=10&offset=10>; rel="prev"`,
+		},
+		{
+			name:     "single page has no links",
+			url:      "/tasks",
+			limit:    50,
+			offset:   0,
+			total:    3,
+			wantLink: "",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			response := httptest.NewRecorder()
+
+			SetPaginationHeaders(response, httptest.NewRequest(http.MethodGet, tc.url, nil).URL, tc.limit, tc.offset, tc.total)
+
+			assert.Equal(t, strconv.Itoa(tc.total), response.Header().Get("X-Total-Count"))
+			assert.Equal(t, tc.wantLink, response.Header().Get("Link"))
+		})
+	}
+}
+
+func TestMe(t *testing.T) {
+	createdAt := time.Date(2025, 6, 1, 9, 30, 0, 0, time.UTC)
+
+	t.Run("returns the authenticated user", func(t *testing.T) {
+		auth := &StubAuth{}
+		authService := &StubAuthService{Users: map[int]*domain.User{
+			1: {ID: 1, Email: "test@email.com", PasswordHash: "secret-hash", CreatedAt: createdAt, Role: domain.RoleUser},
+		}}
+		svr := NewTasksServer(&testhelpers.StubTaskStore{}, authService, auth, dummyLogger)
+
+		response := httptest.NewRecorder()
+		svr.ServeHTTP(response, httptest.NewRequest(http.MethodGet, "/me", nil))
+
+		assert.Equal(t, http.StatusOK, response.Code)
+		assert.Equal(t, 1, auth.authCalled)
+		assert.JSONEq(t, `{"id":1,"email":"test@email.com","created_at":"2025-06-01T09:30:00Z","role":"user"}`, response.Body.String())
+	})
+	t.Run("returns 401 when the user no longer exists", func(t *testing.T) {
+		svr := NewTasksServer(&testhelpers.StubTaskStore{}, &StubAuthService{}, &StubAuth{}, dummyLogger)
+
+		response := httptest.NewRecorder()
+		svr.ServeHTTP(response, httptest.NewRequest(http.MethodGet, "/me", nil))
+
+		assert.Equal(t, http.StatusUnauthorized, response.Code)
+	})
+}
+
+func TestListUsers(t *testing.T) {
+	createdAt := time.Date(2025, 6, 1, 9, 30, 0, 0, time.UTC)
+	newAuthService := func() *StubAuthService {
+		return &StubAuthService{Users: map[int]*domain.User{
+			1: {ID: 1, Email: "admin@email.com", PasswordHash: "secret-hash", CreatedAt: createdAt, Role: domain.RoleAdmin},
+			2: {ID: 2, Email: "user@email.com", PasswordHash: "secret-hash", CreatedAt: createdAt, Role: domain.RoleUser},
+		}}
+	}
+
+	t.Run("returns all users to an admin", func(t *testing.T) {
+		authService := newAuthService()
+		svr := NewTasksServer(&testhelpers.StubTaskStore{}, authService, &StubAuth{role: domain.RoleAdmin}, dummyLogger)
+
+		response := httptest.NewRecorder()
+		svr.ServeHTTP(response, httptest.NewRequest(http.MethodGet, "/admin/users?limit=500&offset=0", nil))
+
+		assert.Equal(t, http.StatusOK, response.Code)
+		assert.JSONEq(t, `{"users":[
+			{"id":1,"email":"admin@email.com","created_at":"2025-06-01T09:30:00Z","role":"admin"},
+			{"id":2,"email":"user@email.com","created_at":"2025-06-01T09:30:00Z","role":"user"}
+		],"limit":200,"offset":0}`, response.Body.String())
+		assert.Equal(t, domain.MaxPageLimit, authService.ListUsersLimit)
+	})
+	t.Run("returns 403 to other users", func(t *testing.T) {
+		authService := newAuthService()
+		svr := NewTasksServer(&testhelpers.StubTaskStore{}, authService, &StubAuth{role: domain.RoleUser}, dummyLogger)
+
+		response := httptest.NewRecorder()
+		svr.ServeHTTP(response, httptest.NewRequest(http.MethodGet, "/admin/users", nil))
+
+		assert.Equal(t, http.StatusForbidden, response.Code)
+		assert.Zero(t, authService.ListUsersLimit)
+	})
+	t.Run("returns 400 on an invalid offset", func(t *testing.T) {
+		svr := NewTasksServer(&testhelpers.StubTaskStore{}, newAuthService(), &StubAuth{role: domain.RoleAdmin}, dummyLogger)
+
+		response := httptest.NewRecorder()
+		svr.ServeHTTP(response, httptest.NewRequest(http.MethodGet, "/admin/users?offset=-1", nil))
+
+		assert.Equal(t, http.StatusBadRequest, response.Code)
+	})
+}
+
+func TestDeleteMe(t *testing.T) {
+	t.Run("deletes the authenticated user", func(t *testing.T) {
+		auth := &StubAuth{}
+		authService := &StubAuthService{Users: map[int]*domain.User{
+			1: {ID: 1, Email: "test@email.com"},
+		}}
+		svr := NewTasksServer(&testhelpers.StubTaskStore{}, authService, auth, dummyLogger)
+
+		response := httptest.NewRecorder()
+		svr.ServeHTTP(response, httptest.NewRequest(http.MethodDelete, "/me", nil))
+
+		assert.Equal(t, http.StatusNoContent, response.Code)
+		assert.Equal(t, 1, auth.authCalled)
+		assert.Empty(t, authService.Users)
+	})
+	t.Run("returns 401 when the user no longer exists", func(t *testing.T) {
+		svr := NewTasksServer(&testhelpers.StubTaskStore{}, &StubAuthService{}, &StubAuth{}, dummyLogger)
+
+		response := httptest.NewRecorder()
+		svr.ServeHTTP(response, httptest.NewRequest(http.MethodDelete, "/me", nil))
+
+		assert.Equal(t, http.StatusUnauthorized, response.Code)
+	})
+}
+
+func TestChangePassword(t *testing.T) {
+	testCases := []struct {
+		name       string
+		body       string
+		serviceErr error
+		wantStatus int
+		wantBody   string
+	}{
+		{
+			name:       "changes the password",
+			body:       `{"current_password":"old-pass-1","new_password":"new-pass-2"}`,
+			wantStatus: http.StatusNoContent,
+		},
+		{
+			name:       "wrong current password returns 401",
+			body:       `{"current_password":"bad-pass-1","new_password":"new-pass-2"}`,
+			serviceErr: domain.ErrInvalidCredentials,
+			wantStatus: http.StatusUnauthorized,
+			wantBody:   "current password is incorrect",
+		},
+		{
+			name:       "unchanged password returns 400",
+			body:       `{"current_password":"old-pass-1","new_password":"old-pass-1"}`,
+			serviceErr: domain.ErrSamePassword,
+			wantStatus: http.StatusBadRequest,
+			wantBody:   domain.ErrSamePassword.Error(),
+		},
+		{
+			name:       "weak password returns 400",
+			body:       `{"current_password":"old-pass-1","new_password":"password"}`,
+			serviceErr: domain.ErrPasswordMissingDigit,
+			wantStatus: http.StatusBadRequest,
+			wantBody:   domain.ErrPasswordMissingDigit.Error(),
+		},
+		{
+			name:       "missing fields return 400",
+			body:       `{"current_password":"old-pass-1"}`,
+			wantStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			auth := &StubAuth{}
+			authService := &StubAuthService{ChangePasswordErr: tc.serviceErr}
+			svr := NewTasksServer(&testhelpers.StubTaskStore{}, authService, auth, dummyLogger)
+
+			request := httptest.NewRequest(http.MethodPut, "/me/password", strings.NewReader(tc.body))
+			request.Header.Set("Content-Type", "application/json")
+			response := httptest.NewRecorder()
+			svr.ServeHTTP(response, request)
+
+			assert.Equal(t, tc.wantStatus, response.Code)
+			assert.Contains(t, response.Body.String(), tc.wantBody)
+			assert.Equal(t, 1, auth.authCalled)
+		})
+	}
+}
+
+func TestAuthRateLimit(t *testing.T) {
+	var limited []string
+	rejectAll := func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			limited = append(limited, r.URL.Path)
+			JSONError(w, http.StatusTooManyRequests, "Too many requests")
+		})
+	}
+	authService := &StubAuthService{}
+	svr := NewTasksServer(&testhelpers.StubTaskStore{}, authService, &StubAuth{}, dummyLogger, WithAuthRateLimit(rejectAll))
+
+	for _, request := range []*http.Request{loginRequest(t), registerRequest(t), loadTasksRequest(t)} {
+		response := httptest.NewRecorder()
+		svr.ServeHTTP(response, request)
+	}
+
+	assert.Equal(t, []string{"/login", "/register"}, limited, "only credentials endpoints are limited")
+	assert.Empty(t, authService.LoginCalled)
+	assert.Empty(t, authService.RegisterCalled)
+}
+
 func TestLoggingMiddleware(t *testing.T) {
 	var logBuffer bytes.Buffer
 	testLogger := slog.New(slog.NewJSONHandler(&logBuffer, nil))
@@ -424,3 +1709,102 @@ func TestLoggingMiddleware(t *testing.T) {
 	assert.Contains(t, logBuffer.String(), "HTTP request started")
 	assert.Contains(t, logBuffer.String(), "HTTP request completed")
 }
+
+func TestLoggingMiddleware_RequestID(t *testing.T) {
+	testCases := []struct {
+		name      string
+		inbound   string
+		wantReuse bool
+	}{
+		{name: "reuses inbound X-Request-ID", inbound: "3f2c9a4e-1b7d-4c2a-9e8f-5d6c7b8a9f01", wantReuse: true},
+		{name: "generates one when missing", inbound: ""},
+		{name: "replaces unsafe inbound value", inbound: "abc\" injected=\"1"},
+		{name: "replaces overlong inbound value", inbound: strings.Repeat("a", 129)},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var logBuffer bytes.Buffer
+			testLogger := slog.New(slog.NewJSONHandler(&logBuffer, nil))
+			svr := NewTasksServer(&testhelpers.StubTaskStore{}, &StubAuthService{}, &StubAuth{}, testLogger)
+
+			request := httptest.NewRequest(http.MethodGet, "/health", nil)
+			if tc.inbound != "" {
+				request.Header.Set(logger.RequestIDHeader, tc.inbound)
+			}
+			response := httptest.NewRecorder()
+
+			svr.ServeHTTP(response, request)
+
+			requestID := response.Header().Get(logger.RequestIDHeader)
+			assert.NotEmpty(t, requestID)
+			if tc.wantReuse {
+				assert.Equal(t, tc.inbound, requestID)
+			} else {
+				assert.NotEqual(t, tc.inbound, requestID)
+			}
+			for _, line := range strings.Split(strings.TrimSpace(logBuffer.String()), "\n") {
+				var entry map[string]any
+				require.NoError(t, json.Unmarshal([]byte(line), &entry))
+				if entry["msg"] == "HTTP request started" || entry["msg"] == "HTTP request completed" {
+					assert.Equal(t, requestID, entry[logger.FieldRequestID])
+				}
+			}
+		})
+	}
+}
+
+func TestLogLevel(t *testing.T) {
+	send := func(svr *TasksServer, body string) *httptest.ResponseRecorder {
+		request := httptest.NewRequest(http.MethodPut, "/admin/loglevel", strings.NewReader(body))
+		request.Header.Set("Content-Type", "application/json")
+		response := httptest.NewRecorder()
+		svr.ServeHTTP(response, request)
+		return response
+	}
+
+	t.Run("changes level at runtime", func(t *testing.T) {
+		level := new(slog.LevelVar)
+		auth := &StubAuth{role: domain.RoleAdmin}
+		svr := NewTasksServer(&testhelpers.StubTaskStore{}, &StubAuthService{}, auth, dummyLogger, WithLogLevel(level))
+
+		response := send(svr, `{"level":"DEBUG"}`)
+
+		assert.Equal(t, http.StatusOK, response.Code)
+		assert.Equal(t, 1, auth.authCalled)
+		assert.Equal(t, slog.LevelDebug, level.Level())
+		var got LogLevelRequest
+		require.NoError(t, json.NewDecoder(response.Body).Decode(&got))
+		assert.Equal(t, "debug", got.Level)
+	})
+
+	t.Run("rejects unknown level", func(t *testing.T) {
+		level := new(slog.LevelVar)
+		level.Set(slog.LevelWarn)
+		svr := NewTasksServer(&testhelpers.StubTaskStore{}, &StubAuthService{}, &StubAuth{role: domain.RoleAdmin}, dummyLogger, WithLogLevel(level))
+
+		for _, body := range []string{`{"level":"verbose"}`, `{"level":""}`, `{"level":"info+2"}`} {
+			response := send(svr, body)
+			assert.Equal(t, http.StatusBadRequest, response.Code, body)
+		}
+		assert.Equal(t, slog.LevelWarn, level.Level())
+	})
+
+	t.Run("returns 403 for a non-admin user", func(t *testing.T) {
+		level := new(slog.LevelVar)
+		svr := NewTasksServer(&testhelpers.StubTaskStore{}, &StubAuthService{}, &StubAuth{role: domain.RoleUser}, dummyLogger, WithLogLevel(level))
+
+		response := send(svr, `{"level":"debug"}`)
+
+		assert.Equal(t, http.StatusForbidden, response.Code)
+		assert.Equal(t, slog.LevelInfo, level.Level())
+	})
+
+	t.Run("not exposed without a level variable", func(t *testing.T) {
+		svr := NewTasksServer(&testhelpers.StubTaskStore{}, &StubAuthService{}, &StubAuth{}, dummyLogger)
+
+		response := send(svr, `{"level":"debug"}`)
+
+		assert.NotEqual(t, http.StatusOK, response.Code)
+	})
+}
@@ -0,0 +1,25 @@
+package webserver
+
+import (
+	_ "embed"
+	"net/http"
+)
+
+// uiIndex is the single-page web UI served at "/" when enabled.
+//
+//go:embed ui/index.html
+var uiIndex []byte
+
+// WithUI serves the embedded web UI at "/" instead of the JSON endpoint list.
+func WithUI(enabled bool) Option {
+	return func(ts *TasksServer) {
+		ts.serveUI = enabled
+	}
+}
+
+// serveUI writes the embedded HTML page.
+func serveUI(w http.ResponseWriter) {
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+	w.Write(uiIndex)
+}
@@ -5,8 +5,10 @@ import (
 	"errors"
 	"log/slog"
 	"myproject/domain"
+	"myproject/domain/validation"
 	"myproject/logger"
-	"regexp"
+	"sync"
+	"time"
 
 	"golang.org/x/crypto/bcrypt"
 )
@@ -16,34 +18,116 @@ type AuthService struct {
 	userStorage    domain.UserStorage
 	tokenGenerator domain.TokenGenerator
 	logger         *slog.Logger
+	logSuccess     bool
+	hashEmails     bool
+	passwordPolicy domain.PasswordPolicy
+	bcryptCost     int
+	lockout        *loginLockout
+	// comparePassword checks a password against a stored hash; tests replace it to observe the calls
+	comparePassword func(hash, password string) error
+	dummyHashOnce   sync.Once
+	dummyHash       string
+}
+
+// DefaultBcryptCost is the bcrypt cost used for new password hashes unless WithBcryptCost says otherwise.
+const DefaultBcryptCost = bcrypt.DefaultCost
+
+// AuthServiceOption configures optional AuthService behaviour.
+type AuthServiceOption func(*AuthService)
+
+// WithSuccessLogging toggles info-level audit logs for successful logins and registrations.
+// Failures are always logged.
+func WithSuccessLogging(enabled bool) AuthServiceOption {
+	return func(s *AuthService) {
+		s.logSuccess = enabled
+	}
+}
+
+// WithHashedEmails logs a hash of the email in success audit logs instead of a masked address.
+func WithHashedEmails(enabled bool) AuthServiceOption {
+	return func(s *AuthService) {
+		s.hashEmails = enabled
+	}
+}
+
+// WithPasswordPolicy sets the strength rules Register enforces on new passwords.
+func WithPasswordPolicy(policy domain.PasswordPolicy) AuthServiceOption {
+	return func(s *AuthService) {
+		s.passwordPolicy = policy
+	}
+}
+
+// WithBcryptCost sets the bcrypt cost of new password hashes, between bcrypt.MinCost and bcrypt.MaxCost;
+// zero keeps DefaultBcryptCost. Each step doubles the time to hash a password. Existing hashes keep
+// the cost they were made with and still verify, since bcrypt stores the cost in the hash.
+func WithBcryptCost(cost int) AuthServiceOption {
+	return func(s *AuthService) {
+		if cost != 0 {
+			s.bcryptCost = cost
+		}
+	}
+}
+
+// WithLoginLockout refuses logins for an email with domain.ErrAccountLocked for window once
+// maxAttempts logins for it failed within window; zero maxAttempts disables the lockout.
+func WithLoginLockout(maxAttempts int, window time.Duration) AuthServiceOption {
+	return func(s *AuthService) {
+		s.lockout = nil
+		if maxAttempts > 0 {
+			s.lockout = newLoginLockout(maxAttempts, window)
+		}
+	}
 }
 
 // NewService creates a new authentication service with the provided dependencies.
-func NewAuthService(userStorage domain.UserStorage, tokenGenerator domain.TokenGenerator, logger *slog.Logger) *AuthService {
-	return &AuthService{
+// Successful authentications are logged unless disabled with WithSuccessLogging.
+func NewAuthService(userStorage domain.UserStorage, tokenGenerator domain.TokenGenerator, logger *slog.Logger, opts ...AuthServiceOption) *AuthService {
+	service := &AuthService{
 		userStorage:    userStorage,
 		tokenGenerator: tokenGenerator,
 		logger:         logger,
+		logSuccess:     true,
+		passwordPolicy: domain.DefaultPasswordPolicy(),
+		bcryptCost:     DefaultBcryptCost,
+	}
+	service.comparePassword = ComparePassword
+	for _, opt := range opts {
+		opt(service)
 	}
+	return service
 }
 
-// ValidatePassword checks if a password meets minimum security requirements.
-func ValidatePassword(password string) error {
-	if len(password) < 8 {
-		return domain.ErrPasswordTooShort
+// logAuthSuccess writes the audit record for a successful authentication.
+// It never includes the password or the issued token.
+func (service *AuthService) logAuthSuccess(ctx context.Context, msg, operation, email string, userID int) {
+	if !service.logSuccess {
+		return
 	}
 
-	if len(password) > 72 {
-		return domain.ErrPasswordTooLong
+	emailValue := logger.MaskEmail(email)
+	if service.hashEmails {
+		emailValue = logger.HashEmail(email)
 	}
-	return nil
+
+	service.logger.InfoContext(ctx, msg,
+		slog.String(logger.FieldOperation, operation),
+		slog.Int(logger.FieldUserID, userID),
+		slog.String(logger.FieldEmail, emailValue),
+		slog.String(logger.FieldClientIP, logger.GetClientIP(ctx)),
+		slog.String(logger.FieldRequestID, logger.GetRequestID(ctx)),
+	)
+}
+
+// ValidatePassword checks a password against the default password policy.
+func ValidatePassword(password string) error {
+	return domain.DefaultPasswordPolicy().Validate(password)
 }
 
-// HashPassword creates a bcrypt hash of the provided password for secure storage.
-func HashPassword(password string) (string, error) {
+// HashPassword creates a bcrypt hash of the provided password with the given cost for secure storage.
+func HashPassword(password string, cost int) (string, error) {
 	hashedPassword, err := bcrypt.GenerateFromPassword(
 		[]byte(password),
-		bcrypt.DefaultCost,
+		cost,
 	)
 	if err != nil {
 		return "", domain.ErrHashingFailed
@@ -61,6 +145,15 @@ func ComparePassword(hash, password string) error {
 	return nil
 }
 
+// dummyPasswordHash returns the hash Login compares against when the email is unknown.
+// It is made once, at the cost of new hashes, so the comparison takes as long as for a real account.
+func (service *AuthService) dummyPasswordHash() string {
+	service.dummyHashOnce.Do(func() {
+		service.dummyHash, _ = HashPassword("no-account-has-this-password", service.bcryptCost)
+	})
+	return service.dummyHash
+}
+
 // Register creates a new user account with the provided credentials and returns a JWT token.
 func (service *AuthService) Register(ctx context.Context, email, password string) (token string, err error) {
 	service.logger.Info("Register",
@@ -68,23 +161,22 @@ func (service *AuthService) Register(ctx context.Context, email, password string
 		slog.String(logger.FieldEmail, logger.MaskEmail(email)),
 	)
 
-	emailRegex := regexp.MustCompile(`^[a-zA-Z0-9._%+-]+@[a-zA-Z0-9.-]+\.[a-zA-Z]{2,}$`)
-	if !emailRegex.MatchString(email) {
+	if err = validation.ValidateEmail(email); err != nil {
 		service.logger.Warn("Failed to validate email",
 			slog.String(logger.FieldOperation, "user_registration"),
 			slog.String(logger.FieldEmail, logger.MaskEmail(email)),
-			slog.String(logger.FieldError, domain.ErrInvalidEmail.Error()),
+			slog.String(logger.FieldError, err.Error()),
 		)
-		return "", domain.ErrInvalidEmail
+		return "", err
 	}
 
-	if err = ValidatePassword(password); err != nil {
+	if err = service.passwordPolicy.Validate(password); err != nil {
 		service.logger.Warn("Failed to validate password",
 			slog.String(logger.FieldOperation, "user_registration"),
 			slog.String(logger.FieldEmail, logger.MaskEmail(email)),
 			slog.String(logger.FieldError, err.Error()),
 		)
-		return "", domain.ErrInvalidCredentials
+		return "", err
 	}
 
 	exists, err := service.userStorage.EmailExists(ctx, email)
@@ -106,7 +198,7 @@ func (service *AuthService) Register(ctx context.Context, email, password string
 		return "", domain.ErrEmailAlreadyExists
 	}
 
-	passwordHash, err := HashPassword(password)
+	passwordHash, err := HashPassword(password, service.bcryptCost)
 	if err != nil {
 		service.logger.Error("Failed to hash password",
 			slog.String(logger.FieldOperation, "user_registration"),
@@ -126,16 +218,12 @@ func (service *AuthService) Register(ctx context.Context, email, password string
 		return "", domain.ErrStorageFailure
 	}
 
-	token, err = service.tokenGenerator.GenerateToken(userID)
+	token, err = service.tokenGenerator.GenerateToken(userID, domain.RoleUser)
 	if err != nil {
 		return "", domain.ErrTokenGenerationFailed
 	}
 
-	service.logger.Info("User registered successfully",
-		slog.String(logger.FieldOperation, "user_registration"),
-		slog.String(logger.FieldEmail, logger.MaskEmail(email)),
-		slog.Int(logger.FieldUserID, userID),
-	)
+	service.logAuthSuccess(ctx, "User registered successfully", "user_registration", email, userID)
 
 	return token, nil
 }
@@ -147,9 +235,23 @@ func (service *AuthService) Login(ctx context.Context, email, password string) (
 		slog.String(logger.FieldEmail, logger.MaskEmail(email)),
 	)
 
+	// Checked before the account is looked up, so a locked unknown email answers like a locked account
+	if service.lockout.locked(email) {
+		service.logger.Warn("Failed login",
+			slog.String(logger.FieldOperation, "user_login"),
+			slog.String(logger.FieldEmail, logger.MaskEmail(email)),
+			slog.String(logger.FieldError, domain.ErrAccountLocked.Error()),
+		)
+		return "", domain.ErrAccountLocked
+	}
+
 	user, err := service.userStorage.GetUserByEmail(ctx, email)
 	if err != nil {
 		if errors.Is(err, domain.ErrUserNotFound) {
+			// Spend the same bcrypt time as for a wrong password, so the response time
+			// does not reveal whether the email is registered.
+			service.comparePassword(service.dummyPasswordHash(), password)
+			service.lockout.fail(email)
 			service.logger.Warn("Failed login",
 				slog.String(logger.FieldOperation, "user_login"),
 				slog.String(logger.FieldEmail, logger.MaskEmail(email)),
@@ -165,7 +267,8 @@ func (service *AuthService) Login(ctx context.Context, email, password string) (
 		return "", domain.ErrStorageFailure
 	}
 
-	if err = ComparePassword(user.PasswordHash, password); err != nil {
+	if err = service.comparePassword(user.PasswordHash, password); err != nil {
+		service.lockout.fail(email)
 		service.logger.Warn("Failed login",
 			slog.String(logger.FieldOperation, "user_login"),
 			slog.String(logger.FieldEmail, logger.MaskEmail(email)),
@@ -174,7 +277,7 @@ func (service *AuthService) Login(ctx context.Context, email, password string) (
 		return "", domain.ErrInvalidCredentials
 	}
 
-	token, err = service.tokenGenerator.GenerateToken(user.ID)
+	token, err = service.tokenGenerator.GenerateToken(user.ID, user.Role)
 	if err != nil {
 		service.logger.Error("Failed to generate token",
 			slog.String(logger.FieldOperation, "user_login"),
@@ -184,11 +287,124 @@ func (service *AuthService) Login(ctx context.Context, email, password string) (
 		return "", domain.ErrTokenGenerationFailed
 	}
 
-	service.logger.Info("Login successful",
-		slog.String(logger.FieldOperation, "user_login"),
-		slog.String(logger.FieldEmail, logger.MaskEmail(email)),
-		slog.Int(logger.FieldUserID, user.ID),
-	)
+	service.lockout.reset(email)
+	service.logAuthSuccess(ctx, "Login successful", "user_login", email, user.ID)
 
 	return token, nil
 }
+
+// CurrentUser returns the account a token was issued for.
+// Returns ErrUserNotFound when the account no longer exists.
+func (service *AuthService) CurrentUser(ctx context.Context, userID int) (*domain.User, error) {
+	user, err := service.userStorage.GetUserByID(ctx, userID)
+	if err != nil {
+		if errors.Is(err, domain.ErrUserNotFound) {
+			return nil, err
+		}
+		service.logger.Error("Failed to fetch user by id from database",
+			slog.String(logger.FieldOperation, "current_user"),
+			slog.Int(logger.FieldUserID, userID),
+			slog.String(logger.FieldError, err.Error()),
+		)
+		return nil, domain.ErrStorageFailure
+	}
+	return user, nil
+}
+
+// ListUsers returns one page of all registered accounts ordered by ID, for administrators.
+func (service *AuthService) ListUsers(ctx context.Context, limit, offset int) ([]domain.User, error) {
+	users, err := service.userStorage.ListUsers(ctx, limit, offset)
+	if err != nil {
+		service.logger.Error("Failed to list users from database",
+			slog.String(logger.FieldOperation, "list_users"),
+			slog.String(logger.FieldError, err.Error()),
+		)
+		return nil, domain.ErrStorageFailure
+	}
+	return users, nil
+}
+
+// DeleteAccount removes the user and, through the storage cascade, all of their tasks.
+// Returns ErrUserNotFound when the account no longer exists.
+func (service *AuthService) DeleteAccount(ctx context.Context, userID int) error {
+	if err := service.userStorage.DeleteUser(ctx, userID); err != nil {
+		if errors.Is(err, domain.ErrUserNotFound) {
+			return err
+		}
+		service.logger.Error("Failed to delete user from database",
+			slog.String(logger.FieldOperation, "delete_account"),
+			slog.Int(logger.FieldUserID, userID),
+			slog.String(logger.FieldError, err.Error()),
+		)
+		return domain.ErrStorageFailure
+	}
+
+	service.logger.Info("Account deleted",
+		slog.String(logger.FieldOperation, "delete_account"),
+		slog.Int(logger.FieldUserID, userID),
+	)
+	return nil
+}
+
+// ChangePassword replaces the user's password after verifying the current one.
+// Returns ErrInvalidCredentials when currentPassword is wrong, ErrSamePassword when the
+// new password equals it, and a password policy error when the new password is too weak.
+func (service *AuthService) ChangePassword(ctx context.Context, userID int, currentPassword, newPassword string) error {
+	user, err := service.userStorage.GetUserByID(ctx, userID)
+	if err != nil {
+		if errors.Is(err, domain.ErrUserNotFound) {
+			return err
+		}
+		service.logger.Error("Failed to fetch user by id from database",
+			slog.String(logger.FieldOperation, "change_password"),
+			slog.Int(logger.FieldUserID, userID),
+			slog.String(logger.FieldError, err.Error()),
+		)
+		return domain.ErrStorageFailure
+	}
+
+	if err := ComparePassword(user.PasswordHash, currentPassword); err != nil {
+		service.logger.Warn("Failed password change",
+			slog.String(logger.FieldOperation, "change_password"),
+			slog.Int(logger.FieldUserID, userID),
+			slog.String(logger.FieldError, domain.ErrInvalidCredentials.Error()),
+		)
+		return domain.ErrInvalidCredentials
+	}
+
+	if newPassword == currentPassword {
+		return domain.ErrSamePassword
+	}
+
+	if err := service.passwordPolicy.Validate(newPassword); err != nil {
+		return err
+	}
+
+	passwordHash, err := HashPassword(newPassword, service.bcryptCost)
+	if err != nil {
+		service.logger.Error("Failed to hash password",
+			slog.String(logger.FieldOperation, "change_password"),
+			slog.Int(logger.FieldUserID, userID),
+			slog.String(logger.FieldError, err.Error()),
+		)
+		return domain.ErrHashingFailed
+	}
+
+	if err := service.userStorage.UpdatePasswordHash(ctx, userID, passwordHash); err != nil {
+		if errors.Is(err, domain.ErrUserNotFound) {
+			return err
+		}
+		service.logger.Error("Failed to update password hash in database",
+			slog.String(logger.FieldOperation, "change_password"),
+			slog.Int(logger.FieldUserID, userID),
+			slog.String(logger.FieldError, err.Error()),
+		)
+		return domain.ErrStorageFailure
+	}
+
+	service.logger.Info("Password changed",
+		slog.String(logger.FieldOperation, "change_password"),
+		slog.Int(logger.FieldUserID, userID),
+	)
+	return nil
+}
@@ -0,0 +1,451 @@
+package application
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"myproject/domain"
+	"myproject/infrastructure/testhelpers"
+	"myproject/logger"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/bcrypt"
+)
+
+type stubUserStorage struct {
+	users map[string]*domain.User
+}
+
+func (s *stubUserStorage) CreateUser(ctx context.Context, email string, passwordHash string) (int, error) {
+	id := len(s.users) + 1
+	s.users[email] = &domain.User{ID: id, Email: email, PasswordHash: passwordHash}
+	return id, nil
+}
+
+func (s *stubUserStorage) GetUserByEmail(ctx context.Context, email string) (*domain.User, error) {
+	user, ok := s.users[email]
+	if !ok {
+		return nil, domain.ErrUserNotFound
+	}
+	return user, nil
+}
+
+func (s *stubUserStorage) GetUserByID(ctx context.Context, id int) (*domain.User, error) {
+	for _, user := range s.users {
+		if user.ID == id {
+			return user, nil
+		}
+	}
+	return nil, domain.ErrUserNotFound
+}
+
+func (s *stubUserStorage) DeleteUser(ctx context.Context, id int) error {
+	for email, user := range s.users {
+		if user.ID == id {
+			delete(s.users, email)
+			return nil
+		}
+	}
+	return domain.ErrUserNotFound
+}
+
+func (s *stubUserStorage) UpdatePasswordHash(ctx context.Context, id int, passwordHash string) error {
+	for _, user := range s.users {
+		if user.ID == id {
+			user.PasswordHash = passwordHash
+			return nil
+		}
+	}
+	return domain.ErrUserNotFound
+}
+
+func (s *stubUserStorage) ListUsers(ctx context.Context, limit, offset int) ([]domain.User, error) {
+	users := make([]domain.User, 0, len(s.users))
+	for _, user := range s.users {
+		users = append(users, *user)
+	}
+	slices.SortFunc(users, func(a, b domain.User) int { return a.ID - b.ID })
+	users = users[min(offset, len(users)):]
+	if limit > 0 {
+		users = users[:min(limit, len(users))]
+	}
+	return users, nil
+}
+
+func (s *stubUserStorage) EmailExists(ctx context.Context, email string) (bool, error) {
+	_, ok := s.users[email]
+	return ok, nil
+}
+
+const (
+	testEmail    = "alice@example.com"
+	testPassword = "correct-password-1"
+	testToken    = "issued-token-value"
+)
+
+func newAuthServiceWithLog(t *testing.T, opts ...AuthServiceOption) (*AuthService, *bytes.Buffer) {
+	t.Helper()
+	hash, err := HashPassword(testPassword, DefaultBcryptCost)
+	require.NoError(t, err)
+
+	storage := &stubUserStorage{users: map[string]*domain.User{
+		testEmail: {ID: 42, Email: testEmail, PasswordHash: hash},
+	}}
+	tokens := &testhelpers.StubTokenGenerator{Token: testToken, Claims: &domain.Claims{}}
+
+	var buf bytes.Buffer
+	l := slog.New(slog.NewJSONHandler(&buf, nil))
+	return NewAuthService(storage, tokens, l, opts...), &buf
+}
+
+func findLogEntry(t *testing.T, buf *bytes.Buffer, msg string) map[string]any {
+	t.Helper()
+	for _, line := range strings.Split(strings.TrimSpace(buf.String()), "\n") {
+		var entry map[string]any
+		require.NoError(t, json.Unmarshal([]byte(line), &entry))
+		if entry["msg"] == msg {
+			return entry
+		}
+	}
+	return nil
+}
+
+func TestLogin_SuccessLogging(t *testing.T) {
+	ctx := logger.WithClientIP(context.Background(), "203.0.113.7")
+
+	t.Run("logs successful login with audit fields", func(t *testing.T) {
+		service, buf := newAuthServiceWithLog(t)
+
+		_, err := service.Login(ctx, testEmail, testPassword)
+		require.NoError(t, err)
+
+		entry := findLogEntry(t, buf, "Login successful")
+		require.NotNil(t, entry)
+		assert.Equal(t, "INFO", entry["level"])
+		assert.Equal(t, float64(42), entry[logger.FieldUserID])
+		assert.Equal(t, logger.MaskEmail(testEmail), entry[logger.FieldEmail])
+		assert.Equal(t, "203.0.113.7", entry[logger.FieldClientIP])
+		assert.NotContains(t, buf.String(), testPassword)
+		assert.NotContains(t, buf.String(), testToken)
+	})
+	t.Run("logs hashed email when enabled", func(t *testing.T) {
+		service, buf := newAuthServiceWithLog(t, WithHashedEmails(true))
+
+		_, err := service.Login(ctx, testEmail, testPassword)
+		require.NoError(t, err)
+
+		entry := findLogEntry(t, buf, "Login successful")
+		require.NotNil(t, entry)
+		assert.Equal(t, logger.HashEmail(testEmail), entry[logger.FieldEmail])
+		assert.NotContains(t, buf.String(), testEmail)
+	})
+	t.Run("suppresses success log but keeps failures when disabled", func(t *testing.T) {
+		service, buf := newAuthServiceWithLog(t, WithSuccessLogging(false))
+
+		_, err := service.Login(ctx, testEmail, testPassword)
+		require.NoError(t, err)
+		_, err = service.Login(ctx, testEmail, "wrong-password")
+		require.ErrorIs(t, err, domain.ErrInvalidCredentials)
+
+		assert.Nil(t, findLogEntry(t, buf, "Login successful"))
+		assert.NotNil(t, findLogEntry(t, buf, "Failed login"))
+	})
+}
+
+func TestLogin_UnknownEmailComparesDummyHash(t *testing.T) {
+	service, _ := newAuthServiceWithLog(t)
+	var hashes []string
+	service.comparePassword = func(hash, password string) error {
+		hashes = append(hashes, hash)
+		return ComparePassword(hash, password)
+	}
+
+	_, err := service.Login(context.Background(), "nobody@example.com", testPassword)
+	require.ErrorIs(t, err, domain.ErrInvalidCredentials)
+	_, err = service.Login(context.Background(), testEmail, "wrong-password")
+	require.ErrorIs(t, err, domain.ErrInvalidCredentials)
+
+	require.Len(t, hashes, 2, "unknown and known emails should both be checked with bcrypt")
+	cost, err := bcrypt.Cost([]byte(hashes[0]))
+	require.NoError(t, err, "the dummy hash should be a valid bcrypt hash")
+	assert.Equal(t, DefaultBcryptCost, cost)
+	assert.NotEqual(t, hashes[1], hashes[0])
+}
+
+func TestLogin_Lockout(t *testing.T) {
+	ctx := context.Background()
+	newLockedOutService := func(t *testing.T) (*AuthService, *time.Time) {
+		service, _ := newAuthServiceWithLog(t, WithLoginLockout(3, 15*time.Minute))
+		now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+		service.lockout.now = func() time.Time { return now }
+		return service, &now
+	}
+	failLogins := func(t *testing.T, service *AuthService, email string, n int) {
+		for range n {
+			_, err := service.Login(ctx, email, "wrong-password")
+			require.ErrorIs(t, err, domain.ErrInvalidCredentials)
+		}
+	}
+
+	t.Run("locks after max attempts even with the right password", func(t *testing.T) {
+		service, _ := newLockedOutService(t)
+		failLogins(t, service, testEmail, 3)
+
+		_, err := service.Login(ctx, testEmail, testPassword)
+		assert.ErrorIs(t, err, domain.ErrAccountLocked)
+	})
+	t.Run("unknown email locks the same way", func(t *testing.T) {
+		service, _ := newLockedOutService(t)
+		failLogins(t, service, "nobody@example.com", 3)
+
+		_, err := service.Login(ctx, "nobody@example.com", testPassword)
+		assert.ErrorIs(t, err, domain.ErrAccountLocked)
+	})
+	t.Run("unlocks after the window", func(t *testing.T) {
+		service, now := newLockedOutService(t)
+		failLogins(t, service, testEmail, 3)
+
+		*now = now.Add(15 * time.Minute)
+		_, err := service.Login(ctx, testEmail, testPassword)
+		assert.NoError(t, err)
+	})
+	t.Run("success resets the count", func(t *testing.T) {
+		service, _ := newLockedOutService(t)
+		failLogins(t, service, testEmail, 2)
+		_, err := service.Login(ctx, testEmail, testPassword)
+		require.NoError(t, err)
+		failLogins(t, service, testEmail, 2)
+
+		_, err = service.Login(ctx, testEmail, testPassword)
+		assert.NoError(t, err)
+	})
+	t.Run("failures older than the window are forgotten", func(t *testing.T) {
+		service, now := newLockedOutService(t)
+		failLogins(t, service, testEmail, 2)
+		*now = now.Add(16 * time.Minute)
+		failLogins(t, service, testEmail, 2)
+
+		_, err := service.Login(ctx, testEmail, testPassword)
+		assert.NoError(t, err)
+	})
+	t.Run("other emails are not locked", func(t *testing.T) {
+		service, _ := newLockedOutService(t)
+		failLogins(t, service, "nobody@example.com", 3)
+
+		_, err := service.Login(ctx, testEmail, testPassword)
+		assert.NoError(t, err)
+	})
+}
+
+func TestRegister_SuccessLogging(t *testing.T) {
+	ctx := logger.WithClientIP(context.Background(), "203.0.113.7")
+
+	t.Run("logs successful registration with audit fields", func(t *testing.T) {
+		service, buf := newAuthServiceWithLog(t)
+
+		_, err := service.Register(ctx, "bob@example.com", testPassword)
+		require.NoError(t, err)
+
+		entry := findLogEntry(t, buf, "User registered successfully")
+		require.NotNil(t, entry)
+		assert.Equal(t, float64(2), entry[logger.FieldUserID])
+		assert.Equal(t, "203.0.113.7", entry[logger.FieldClientIP])
+		assert.NotContains(t, buf.String(), testPassword)
+		assert.NotContains(t, buf.String(), testToken)
+	})
+	t.Run("suppresses success log when disabled", func(t *testing.T) {
+		service, buf := newAuthServiceWithLog(t, WithSuccessLogging(false))
+
+		_, err := service.Register(ctx, "bob@example.com", testPassword)
+		require.NoError(t, err)
+
+		assert.Nil(t, findLogEntry(t, buf, "User registered successfully"))
+	})
+}
+
+func TestCurrentUser(t *testing.T) {
+	service, _ := newAuthServiceWithLog(t)
+
+	t.Run("returns the user for the token's ID", func(t *testing.T) {
+		user, err := service.CurrentUser(context.Background(), 42)
+
+		require.NoError(t, err)
+		assert.Equal(t, testEmail, user.Email)
+	})
+	t.Run("returns ErrUserNotFound for an unknown ID", func(t *testing.T) {
+		_, err := service.CurrentUser(context.Background(), 7)
+
+		assert.ErrorIs(t, err, domain.ErrUserNotFound)
+	})
+}
+
+func TestDeleteAccount(t *testing.T) {
+	service, _ := newAuthServiceWithLog(t)
+
+	t.Run("removes the user", func(t *testing.T) {
+		err := service.DeleteAccount(context.Background(), 42)
+
+		require.NoError(t, err)
+		_, err = service.CurrentUser(context.Background(), 42)
+		assert.ErrorIs(t, err, domain.ErrUserNotFound)
+	})
+	t.Run("returns ErrUserNotFound for an unknown ID", func(t *testing.T) {
+		err := service.DeleteAccount(context.Background(), 42)
+
+		assert.ErrorIs(t, err, domain.ErrUserNotFound)
+	})
+}
+
+func TestRegister_PasswordPolicy(t *testing.T) {
+	ctx := context.Background()
+
+	testCases := []struct {
+		name     string
+		opts     []AuthServiceOption
+		password string
+		wantErr  error
+	}{
+		{
+			name:     "default policy accepts letters and digits",
+			password: "password1",
+		},
+		{
+			name:     "default policy rejects a password without digits",
+			password: "password",
+			wantErr:  domain.ErrPasswordMissingDigit,
+		},
+		{
+			name:     "default policy rejects a password without letters",
+			password: "12345678",
+			wantErr:  domain.ErrPasswordMissingLetter,
+		},
+		{
+			name:     "password over the bcrypt limit is rejected",
+			password: strings.Repeat("a1", 37),
+			wantErr:  domain.ErrPasswordTooLong,
+		},
+		{
+			name:     "configured policy requires upper-case letters",
+			opts:     []AuthServiceOption{WithPasswordPolicy(domain.PasswordPolicy{MinLength: 8, RequireUpper: true})},
+			password: "password1",
+			wantErr:  domain.ErrPasswordMissingUpper,
+		},
+		{
+			name:     "configured policy raises the minimum length",
+			opts:     []AuthServiceOption{WithPasswordPolicy(domain.PasswordPolicy{MinLength: 12})},
+			password: "password1",
+			wantErr:  domain.ErrPasswordTooShort,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			service, _ := newAuthServiceWithLog(t, tc.opts...)
+
+			_, err := service.Register(ctx, "bob@example.com", tc.password)
+
+			if tc.wantErr == nil {
+				assert.NoError(t, err)
+				return
+			}
+			assert.ErrorIs(t, err, tc.wantErr)
+			_, lookupErr := service.userStorage.GetUserByEmail(ctx, "bob@example.com")
+			assert.ErrorIs(t, lookupErr, domain.ErrUserNotFound, "user must not be created")
+		})
+	}
+}
+
+func TestChangePassword(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("replaces the password", func(t *testing.T) {
+		service, _ := newAuthServiceWithLog(t)
+
+		err := service.ChangePassword(ctx, 42, testPassword, "new-password-2")
+		require.NoError(t, err)
+
+		_, err = service.Login(ctx, testEmail, testPassword)
+		assert.ErrorIs(t, err, domain.ErrInvalidCredentials)
+		_, err = service.Login(ctx, testEmail, "new-password-2")
+		assert.NoError(t, err)
+	})
+	t.Run("rejects a wrong current password", func(t *testing.T) {
+		service, _ := newAuthServiceWithLog(t)
+
+		err := service.ChangePassword(ctx, 42, "wrong-password-1", "new-password-2")
+
+		assert.ErrorIs(t, err, domain.ErrInvalidCredentials)
+	})
+	t.Run("rejects an unchanged password", func(t *testing.T) {
+		service, _ := newAuthServiceWithLog(t)
+
+		err := service.ChangePassword(ctx, 42, testPassword, testPassword)
+
+		assert.ErrorIs(t, err, domain.ErrSamePassword)
+	})
+	t.Run("rejects a weak new password", func(t *testing.T) {
+		service, _ := newAuthServiceWithLog(t)
+
+		err := service.ChangePassword(ctx, 42, testPassword, "password")
+
+		assert.ErrorIs(t, err, domain.ErrPasswordMissingDigit)
+		_, err = service.Login(ctx, testEmail, testPassword)
+		assert.NoError(t, err, "old password must keep working")
+	})
+	t.Run("returns ErrUserNotFound for an unknown ID", func(t *testing.T) {
+		service, _ := newAuthServiceWithLog(t)
+
+		err := service.ChangePassword(ctx, 7, testPassword, "new-password-2")
+
+		assert.ErrorIs(t, err, domain.ErrUserNotFound)
+	})
+}
+
+func TestBcryptCost(t *testing.T) {
+	t.Run("hashes new passwords with the configured cost", func(t *testing.T) {
+		service, _ := newAuthServiceWithLog(t, WithBcryptCost(bcrypt.MinCost))
+		_, err := service.Register(context.Background(), "bob@example.com", testPassword)
+		require.NoError(t, err)
+
+		user, err := service.userStorage.GetUserByEmail(context.Background(), "bob@example.com")
+		require.NoError(t, err)
+		cost, err := bcrypt.Cost([]byte(user.PasswordHash))
+		require.NoError(t, err)
+		assert.Equal(t, bcrypt.MinCost, cost)
+	})
+
+	t.Run("verifies hashes made with another cost", func(t *testing.T) {
+		service, _ := newAuthServiceWithLog(t, WithBcryptCost(bcrypt.MinCost))
+
+		token, err := service.Login(context.Background(), testEmail, testPassword)
+
+		require.NoError(t, err, "a hash made with the default cost should still verify")
+		assert.Equal(t, testToken, token)
+	})
+
+	t.Run("rejects a cost above the bcrypt maximum", func(t *testing.T) {
+		_, err := HashPassword(testPassword, bcrypt.MaxCost+1)
+
+		assert.ErrorIs(t, err, domain.ErrHashingFailed)
+	})
+}
+
+// BenchmarkHashPassword shows how the time to hash a password doubles with each step of the bcrypt cost.
+func BenchmarkHashPassword(b *testing.B) {
+	for _, cost := range []int{bcrypt.MinCost, DefaultBcryptCost, 12} {
+		b.Run(fmt.Sprintf("cost=%d", cost), func(b *testing.B) {
+			for b.Loop() {
+				if _, err := HashPassword(testPassword, cost); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
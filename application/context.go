@@ -10,6 +10,9 @@ type ContextKey string
 
 const UserIDKey ContextKey = "user_id"
 
+// RoleKey holds the role from the authenticated user's token.
+const RoleKey ContextKey = "role"
+
 // GetUserIDFromContext retrieves the authenticated user ID from the request context.
 func GetUserIDFromContext(ctx context.Context) (userID int, err error) {
 	userID, ok := ctx.Value(UserIDKey).(int)
@@ -18,3 +21,10 @@ func GetUserIDFromContext(ctx context.Context) (userID int, err error) {
 	}
 	return userID, nil
 }
+
+// GetUserRoleFromContext retrieves the authenticated user's role from the request context,
+// or an empty string when the request was not authenticated.
+func GetUserRoleFromContext(ctx context.Context) string {
+	role, _ := ctx.Value(RoleKey).(string)
+	return role
+}
@@ -0,0 +1,61 @@
+package application
+
+import (
+	"myproject/domain"
+	"sync"
+)
+
+// Task events reported to hooks.
+const (
+	// EventTaskCreated fires for every task created through CreateTask or CreateTasks.
+	EventTaskCreated = "task.created"
+	// EventTaskCompleted fires when UpdateTask or ToggleTask marks an open task done.
+	EventTaskCompleted = "task.completed"
+)
+
+// TaskEvent describes a change to a task of one user.
+type TaskEvent struct {
+	Name   string      `json:"event"`
+	UserID int         `json:"user_id"`
+	Task   domain.Task `json:"task"`
+}
+
+// TaskHook is called after a task event has been stored.
+// Hooks run synchronously on the request path, so slow work belongs in a goroutine.
+type TaskHook func(TaskEvent)
+
+type hookRegistry struct {
+	mu    sync.RWMutex
+	hooks map[string][]TaskHook
+}
+
+func (r *hookRegistry) register(event string, fn TaskHook) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.hooks == nil {
+		r.hooks = make(map[string][]TaskHook)
+	}
+	r.hooks[event] = append(r.hooks[event], fn)
+}
+
+func (r *hookRegistry) fire(event string, userID int, task domain.Task) {
+	r.mu.RLock()
+	hooks := r.hooks[event]
+	r.mu.RUnlock()
+	for _, fn := range hooks {
+		fn(TaskEvent{Name: event, UserID: userID, Task: task})
+	}
+}
+
+// RegisterHook calls fn after every event with the given name.
+// Hooks are only called for changes made through the Service.
+func (s *Service) RegisterHook(event string, fn TaskHook) {
+	s.hooks.register(event, fn)
+}
+
+// WithHook registers fn for the named event when the Service is created.
+func WithHook(event string, fn TaskHook) ServiceOption {
+	return func(s *Service) {
+		s.RegisterHook(event, fn)
+	}
+}
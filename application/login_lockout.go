@@ -0,0 +1,100 @@
+package application
+
+import (
+	"strings"
+	"sync"
+	"time"
+)
+
+// failedLogins counts the failed logins for one email since the first of them.
+type failedLogins struct {
+	count       int
+	first       time.Time
+	lockedUntil time.Time
+}
+
+// loginLockout locks an email for window once maxAttempts logins for it failed within window.
+// Failures are tracked for every email tried, registered or not, so a lock does not reveal
+// whether an account exists. A nil *loginLockout never locks.
+type loginLockout struct {
+	mu          sync.Mutex
+	emails      map[string]*failedLogins
+	maxAttempts int
+	window      time.Duration
+	lastSweep   time.Time
+	now         func() time.Time
+}
+
+func newLoginLockout(maxAttempts int, window time.Duration) *loginLockout {
+	return &loginLockout{
+		emails:      make(map[string]*failedLogins),
+		maxAttempts: maxAttempts,
+		window:      window,
+		now:         time.Now,
+	}
+}
+
+// lockoutKey ignores case and surrounding spaces, so variants of an email share one count.
+func lockoutKey(email string) string {
+	return strings.ToLower(strings.TrimSpace(email))
+}
+
+// locked reports whether logins for email are refused right now.
+func (l *loginLockout) locked(email string) bool {
+	if l == nil {
+		return false
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	record, ok := l.emails[lockoutKey(email)]
+	return ok && l.now().Before(record.lockedUntil)
+}
+
+// fail counts a failed login for email and locks it when the count reaches maxAttempts.
+// A count older than window starts over.
+func (l *loginLockout) fail(email string) {
+	if l == nil {
+		return
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.now()
+	l.sweep(now)
+
+	key := lockoutKey(email)
+	record, ok := l.emails[key]
+	if !ok || now.Sub(record.first) >= l.window {
+		record = &failedLogins{first: now}
+		l.emails[key] = record
+	}
+	record.count++
+	if record.count >= l.maxAttempts {
+		record.lockedUntil = now.Add(l.window)
+	}
+}
+
+// reset forgets the failed logins for email after a successful one.
+func (l *loginLockout) reset(email string) {
+	if l == nil {
+		return
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	delete(l.emails, lockoutKey(email))
+}
+
+// sweep drops the counts that expired and are not locked, at most once per window.
+func (l *loginLockout) sweep(now time.Time) {
+	if now.Sub(l.lastSweep) < l.window {
+		return
+	}
+	for key, record := range l.emails {
+		if now.Sub(record.first) >= l.window && !now.Before(record.lockedUntil) {
+			delete(l.emails, key)
+		}
+	}
+	l.lastSweep = now
+}
@@ -2,21 +2,63 @@ package application
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"myproject/domain"
 	"myproject/domain/validation"
+	"strings"
+	"time"
 )
 
 type Service struct {
-	store domain.Storage
+	store                domain.Storage
+	expander             *TemplateExpander
+	maxDescriptionLength int
+	hooks                hookRegistry
+	rejectDuplicates     bool
 }
 
-func NewService(store domain.Storage) *Service {
-	return &Service{store: store}
+// ServiceOption configures optional Service behaviour.
+type ServiceOption func(*Service)
+
+// WithTemplateExpander enables placeholder expansion in new task descriptions.
+func WithTemplateExpander(e *TemplateExpander) ServiceOption {
+	return func(s *Service) {
+		s.expander = e
+	}
 }
 
-func (s *Service) UpdateTask(ctx context.Context, taskID, userID int, description *string, done *bool) (domain.Task, error) {
-	if description == nil && done == nil {
+// WithMaxDescriptionLength sets the description limit for created and updated tasks.
+// Non-positive values keep validation.DefaultMaxDescriptionLength.
+func WithMaxDescriptionLength(maxLength int) ServiceOption {
+	return func(s *Service) {
+		if maxLength > 0 {
+			s.maxDescriptionLength = maxLength
+		}
+	}
+}
+
+// WithoutDuplicates makes CreateTask and CreateTasks reject a description the user
+// already has on a non-deleted task with domain.ErrDuplicateTask. Duplicates are allowed by default.
+func WithoutDuplicates(enabled bool) ServiceOption {
+	return func(s *Service) {
+		s.rejectDuplicates = enabled
+	}
+}
+
+func NewService(store domain.Storage, opts ...ServiceOption) *Service {
+	s := &Service{store: store, maxDescriptionLength: validation.DefaultMaxDescriptionLength}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+// UpdateTask changes the fields that are not nil; a due date can be moved but not removed.
+// A non-nil version must match the task's current version, otherwise domain.ErrVersionConflict is returned.
+// The update is stored only if the task did not change after it was read.
+func (s *Service) UpdateTask(ctx context.Context, taskID, userID int, description *string, done *bool, dueDate *time.Time, priority *int, version *int) (domain.Task, error) {
+	if description == nil && done == nil && dueDate == nil && priority == nil {
 		return domain.Task{}, domain.ErrEmptyFieldsToUpdate
 	}
 
@@ -24,41 +66,293 @@ func (s *Service) UpdateTask(ctx context.Context, taskID, userID int, descriptio
 	if err != nil {
 		return domain.Task{}, fmt.Errorf("failed to find task with id %d: %w", taskID, err)
 	}
+	if version != nil && *version != task.Version {
+		return domain.Task{}, fmt.Errorf("failed to update task with id %d at version %d: %w", taskID, *version, domain.ErrVersionConflict)
+	}
 
 	if description != nil {
-		desc := string(*description)
-		desc, err = validation.ValidateTaskDescription(desc)
-		if err != nil {
-			return domain.Task{}, fmt.Errorf("failed to validate description for task with id %d: %w", taskID, err)
-		}
-		task.Description = desc
+		task.Description = strings.TrimSpace(*description)
 	}
 
+	wasDone := task.Done
 	if done != nil {
-		task.Done = *done
+		setDone(&task, *done)
+	}
+	if dueDate != nil {
+		task.DueDate = normalizeDueDate(dueDate)
+	}
+	if priority != nil {
+		task.Priority = *priority
 	}
+	if err := task.Validate(s.maxDescriptionLength); err != nil {
+		return domain.Task{}, fmt.Errorf("failed to validate task with id %d: %w", taskID, err)
+	}
+	task.UpdatedAt = timestampNow()
 
 	if err := s.store.UpdateTask(ctx, task, userID); err != nil {
 		return domain.Task{}, fmt.Errorf("failed to update task with id %d: %w", taskID, err)
 	}
+	task.Version++
+	if task.Done && !wasDone {
+		s.hooks.fire(EventTaskCompleted, userID, task)
+	}
 	return task, nil
 }
 
-func (s *Service) CreateTask(ctx context.Context, description string, userID int) (domain.Task, error) {
-	desc, err := validation.ValidateTaskDescription(description)
+// UpdateTasks applies a batch of description and status changes in one transaction and returns the updated tasks.
+// Every operation is validated before anything is stored; the first invalid, missing or concurrently
+// changed task fails the whole batch with a *domain.BatchItemError and no task is changed.
+func (s *Service) UpdateTasks(ctx context.Context, updates []domain.TaskUpdate, userID int) ([]domain.Task, error) {
+	if len(updates) == 0 {
+		return nil, domain.ErrEmptyBatch
+	}
+
+	descriptions := make([]string, len(updates))
+	seen := make(map[int]bool, len(updates))
+	for i, update := range updates {
+		if update.ID <= 0 {
+			return nil, fmt.Errorf("failed to validate batch: %w", &domain.BatchItemError{Index: i, Err: validation.ErrInvalidTaskID})
+		}
+		if seen[update.ID] {
+			return nil, fmt.Errorf("failed to validate batch: %w", &domain.BatchItemError{Index: i, Err: validation.ErrDuplicateTaskID})
+		}
+		seen[update.ID] = true
+		if update.Description == nil && update.Done == nil {
+			return nil, fmt.Errorf("failed to validate batch: %w", &domain.BatchItemError{Index: i, Err: domain.ErrEmptyFieldsToUpdate})
+		}
+		if update.Description != nil {
+			desc, err := validation.ValidateTaskDescriptionLength(*update.Description, s.maxDescriptionLength)
+			if err != nil {
+				return nil, fmt.Errorf("failed to validate batch: %w", &domain.BatchItemError{Index: i, Err: err})
+			}
+			descriptions[i] = desc
+		}
+	}
+
+	now := timestampNow()
+	tasks := make([]domain.Task, len(updates))
+	wasDone := make([]bool, len(updates))
+	for i, update := range updates {
+		task, err := s.store.GetTaskByID(ctx, update.ID, userID)
+		if errors.Is(err, domain.ErrTaskNotFound) {
+			err = &domain.BatchItemError{Index: i, Err: err}
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to find task with id %d: %w", update.ID, err)
+		}
+		wasDone[i] = task.Done
+		if update.Description != nil {
+			task.Description = descriptions[i]
+		}
+		if update.Done != nil {
+			setDone(&task, *update.Done)
+		}
+		task.UpdatedAt = now
+		tasks[i] = task
+	}
+
+	if err := s.store.UpdateTasks(ctx, tasks, userID); err != nil {
+		return nil, fmt.Errorf("failed to update %d tasks: %w", len(tasks), err)
+	}
+	for i := range tasks {
+		tasks[i].Version++
+		if tasks[i].Done && !wasDone[i] {
+			s.hooks.fire(EventTaskCompleted, userID, tasks[i])
+		}
+	}
+	return tasks, nil
+}
+
+// ToggleTask flips the task's status in storage without reading it first,
+// so concurrent toggles cannot overwrite each other.
+func (s *Service) ToggleTask(ctx context.Context, taskID, userID int) (domain.Task, error) {
+	task, err := s.store.ToggleTaskDone(ctx, taskID, userID)
 	if err != nil {
-		return domain.Task{}, fmt.Errorf("failed to validate description: %w", err)
+		return domain.Task{}, fmt.Errorf("failed to toggle task with id %d: %w", taskID, err)
+	}
+	if task.Done {
+		s.hooks.fire(EventTaskCompleted, userID, task)
+	}
+	return task, nil
+}
+
+// CreateTask validates and stores a new task; an optional due date must not lie far in the past.
+// Tags are normalized to lowercase and duplicates are dropped.
+func (s *Service) CreateTask(ctx context.Context, description string, done bool, dueDate *time.Time, priority int, tags []string, userID int) (domain.Task, error) {
+	if s.expander != nil {
+		description = s.expander.Expand(description)
+	}
+	desc := strings.TrimSpace(description)
+	if err := (domain.Task{Description: desc, Priority: priority}).Validate(s.maxDescriptionLength); err != nil {
+		return domain.Task{}, fmt.Errorf("failed to validate task: %w", err)
 	}
 
-	newTask := domain.Task{Description: desc, Done: false}
-	id, err := s.store.CreateTask(ctx, newTask, userID)
+	now := timestampNow()
+	if dueDate != nil {
+		if err := validation.ValidateDueDate(*dueDate, now); err != nil {
+			return domain.Task{}, fmt.Errorf("failed to validate due date: %w", err)
+		}
+	}
+	tags, err := validation.NormalizeTags(tags)
 	if err != nil {
-		return domain.Task{}, fmt.Errorf("failed to create task: %w", err)
+		return domain.Task{}, fmt.Errorf("failed to validate tags: %w", err)
 	}
-	newTask.ID = id
+	newTask := domain.Task{Description: desc, DueDate: normalizeDueDate(dueDate), Priority: priority, Tags: tags, CreatedAt: now, UpdatedAt: now}
+	setDone(&newTask, done)
+	// The duplicate check and the insert share a transaction, so a concurrent create cannot slip in between.
+	err = s.store.WithTx(ctx, func(tx domain.TxStorage) error {
+		if err := s.checkDuplicate(ctx, tx, desc, userID); err != nil {
+			return err
+		}
+		id, err := tx.CreateTask(ctx, newTask, userID)
+		if err != nil {
+			return fmt.Errorf("failed to create task: %w", err)
+		}
+		newTask.ID = id
+		return nil
+	})
+	if err != nil {
+		return domain.Task{}, err
+	}
+	s.hooks.fire(EventTaskCreated, userID, newTask)
 	return newTask, nil
 }
 
+// CreateTasks validates every description before storing the whole batch in one transaction.
+// A validation failure is reported as a *domain.BatchItemError and nothing is stored.
+func (s *Service) CreateTasks(ctx context.Context, tasks []domain.Task, userID int) ([]domain.Task, error) {
+	if len(tasks) == 0 {
+		return nil, domain.ErrEmptyBatch
+	}
+
+	now := timestampNow()
+	newTasks := make([]domain.Task, len(tasks))
+	seen := make(map[string]bool, len(tasks))
+	for i, task := range tasks {
+		description := task.Description
+		if s.expander != nil {
+			description = s.expander.Expand(description)
+		}
+		desc := strings.TrimSpace(description)
+		if err := (domain.Task{Description: desc, Priority: task.Priority}).Validate(s.maxDescriptionLength); err != nil {
+			return nil, fmt.Errorf("failed to validate batch: %w", &domain.BatchItemError{Index: i, Err: err})
+		}
+		if task.DueDate != nil {
+			if err := validation.ValidateDueDate(*task.DueDate, now); err != nil {
+				return nil, fmt.Errorf("failed to validate batch: %w", &domain.BatchItemError{Index: i, Err: err})
+			}
+		}
+		tags, err := validation.NormalizeTags(task.Tags)
+		if err != nil {
+			return nil, fmt.Errorf("failed to validate batch: %w", &domain.BatchItemError{Index: i, Err: err})
+		}
+		if s.rejectDuplicates && seen[desc] {
+			return nil, fmt.Errorf("failed to validate batch: %w", &domain.BatchItemError{Index: i, Err: domain.ErrDuplicateTask})
+		}
+		seen[desc] = true
+		newTasks[i] = domain.Task{Description: desc, DueDate: normalizeDueDate(task.DueDate), Priority: task.Priority, Tags: tags, CreatedAt: now, UpdatedAt: now}
+		setDone(&newTasks[i], task.Done)
+	}
+
+	var ids []int
+	err := s.store.WithTx(ctx, func(tx domain.TxStorage) error {
+		for i, task := range newTasks {
+			if err := s.checkDuplicate(ctx, tx, task.Description, userID); errors.Is(err, domain.ErrDuplicateTask) {
+				return fmt.Errorf("failed to validate batch: %w", &domain.BatchItemError{Index: i, Err: err})
+			} else if err != nil {
+				return err
+			}
+		}
+		var err error
+		ids, err = tx.CreateTasks(ctx, newTasks, userID)
+		if err != nil {
+			return fmt.Errorf("failed to create %d tasks: %w", len(newTasks), err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	for i, id := range ids {
+		newTasks[i].ID = id
+		s.hooks.fire(EventTaskCreated, userID, newTasks[i])
+	}
+	return newTasks, nil
+}
+
+// checkDuplicate returns domain.ErrDuplicateTask when duplicates are rejected and the user
+// already has a non-deleted task with the description in store.
+func (s *Service) checkDuplicate(ctx context.Context, store domain.TxStorage, description string, userID int) error {
+	if !s.rejectDuplicates {
+		return nil
+	}
+	exists, err := store.TaskExistsByDescription(ctx, userID, description)
+	if err != nil {
+		return fmt.Errorf("failed to check for duplicate task: %w", err)
+	}
+	if exists {
+		return domain.ErrDuplicateTask
+	}
+	return nil
+}
+
 func (s *Service) GetTasks(ctx context.Context, userID int) ([]domain.Task, error) {
-	return s.store.LoadTasks(ctx, userID)
+	return s.store.LoadTasks(ctx, userID, domain.ListOptions{})
+}
+
+func (s *Service) GetTask(ctx context.Context, taskID, userID int) (domain.Task, error) {
+	task, err := s.store.GetTaskByID(ctx, taskID, userID)
+	if err != nil {
+		return domain.Task{}, fmt.Errorf("failed to find task with id %d: %w", taskID, err)
+	}
+	return task, nil
+}
+
+// ListTasks returns one page of the user's tasks along with the total task count.
+func (s *Service) ListTasks(ctx context.Context, userID int, opts domain.ListOptions) (domain.TaskPage, error) {
+	tasks, err := s.store.LoadTasks(ctx, userID, opts)
+	if err != nil {
+		return domain.TaskPage{}, fmt.Errorf("failed to load tasks: %w", err)
+	}
+	total, err := s.store.CountTasks(ctx, userID, opts.Filter)
+	if err != nil {
+		return domain.TaskPage{}, fmt.Errorf("failed to count tasks: %w", err)
+	}
+	return domain.TaskPage{Tasks: tasks, Total: total, Limit: opts.Limit, Offset: opts.Offset}, nil
+}
+
+func (s *Service) DeleteTask(ctx context.Context, taskID, userID int) error {
+	if err := s.store.DeleteTask(ctx, taskID, userID); err != nil {
+		return fmt.Errorf("failed to delete task with id %d: %w", taskID, err)
+	}
+	return nil
+}
+
+// normalizeDueDate stores due dates in UTC at second precision so storage can compare them as text.
+func normalizeDueDate(due *time.Time) *time.Time {
+	if due == nil {
+		return nil
+	}
+	v := due.UTC().Truncate(time.Second)
+	return &v
+}
+
+// timestampNow returns the current time at the second precision storage keeps created_at and updated_at in.
+func timestampNow() time.Time {
+	return time.Now().UTC().Truncate(time.Second)
+}
+
+// setDone updates the task status and keeps CompletedAt in sync with it.
+// The completion timestamp is preserved when an already done task is marked done again.
+func setDone(task *domain.Task, done bool) {
+	if !done {
+		task.Done = false
+		task.CompletedAt = nil
+		return
+	}
+	if !task.Done || task.CompletedAt == nil {
+		now := time.Now().UTC()
+		task.CompletedAt = &now
+	}
+	task.Done = true
 }
@@ -2,17 +2,25 @@ package application
 
 import (
 	"context"
+	"errors"
 	"myproject/domain"
+	"myproject/domain/validation"
 	"myproject/infrastructure/testhelpers"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 type updateTask struct {
 	taskID, userID int
 	description    *string
 	done           *bool
+	dueDate        *time.Time
+	priority       *int
+	version        *int
 }
 
 func TestUpdateTask(t *testing.T) {
@@ -46,6 +54,86 @@ func TestUpdateTask(t *testing.T) {
 			expectedUpdateCalls: 1,
 			wantErr:             false,
 		},
+		{
+			name: "update due date only",
+			up: updateTask{
+				taskID:  1,
+				userID:  1,
+				dueDate: timePtr(time.Date(2030, 1, 2, 3, 4, 5, 0, time.UTC)),
+			},
+			setupStore: &testhelpers.StubTaskStore{
+				Tasks: map[int]string{
+					1: "task 1",
+				},
+			},
+			expectedDescription: "task 1",
+			expectedUpdateCalls: 1,
+			wantErr:             false,
+		},
+		{
+			name: "update priority only",
+			up: updateTask{
+				taskID:   1,
+				userID:   1,
+				priority: intPtr(domain.PriorityMedium),
+			},
+			setupStore: &testhelpers.StubTaskStore{
+				Tasks: map[int]string{
+					1: "task 1",
+				},
+			},
+			expectedDescription: "task 1",
+			expectedUpdateCalls: 1,
+		},
+		{
+			name: "update with the current version",
+			up: updateTask{
+				taskID:      1,
+				userID:      1,
+				description: stringPtr("new task 1"),
+				version:     intPtr(0),
+			},
+			setupStore: &testhelpers.StubTaskStore{
+				Tasks: map[int]string{
+					1: "task 1",
+				},
+			},
+			expectedDescription: "new task 1",
+			expectedUpdateCalls: 1,
+		},
+		{
+			name: "error when version is stale",
+			up: updateTask{
+				taskID:      1,
+				userID:      1,
+				description: stringPtr("new task 1"),
+				version:     intPtr(3),
+			},
+			setupStore: &testhelpers.StubTaskStore{
+				Tasks: map[int]string{
+					1: "task 1",
+				},
+			},
+			expectedUpdateCalls: 0,
+			wantErr:             true,
+			expectedError:       domain.ErrVersionConflict,
+		},
+		{
+			name: "error when priority out of range",
+			up: updateTask{
+				taskID:   1,
+				userID:   1,
+				priority: intPtr(-1),
+			},
+			setupStore: &testhelpers.StubTaskStore{
+				Tasks: map[int]string{
+					1: "task 1",
+				},
+			},
+			expectedUpdateCalls: 0,
+			wantErr:             true,
+			expectedError:       domain.ErrInvalidPriority,
+		},
 		{
 			name: "error when both fields are nil",
 			up: updateTask{
@@ -96,7 +184,7 @@ func TestUpdateTask(t *testing.T) {
 			store := tt.setupStore
 			service := NewService(store)
 
-			task, err := service.UpdateTask(ctx, tt.up.taskID, tt.up.userID, tt.up.description, tt.up.done)
+			task, err := service.UpdateTask(ctx, tt.up.taskID, tt.up.userID, tt.up.description, tt.up.done, tt.up.dueDate, tt.up.priority, tt.up.version)
 			if tt.wantErr {
 				assert.Error(t, err)
 				assert.ErrorIs(t, err, tt.expectedError)
@@ -106,20 +194,34 @@ func TestUpdateTask(t *testing.T) {
 			assert.Equal(t, tt.expectedUpdateCalls, store.UpdateTaskCalled)
 			assert.Equal(t, tt.expectedDescription, task.Description)
 			assert.Equal(t, tt.expectedDone, task.Done)
+			assert.Equal(t, !tt.wantErr, !task.UpdatedAt.IsZero())
+			assert.Equal(t, tt.up.dueDate, task.DueDate)
+			if tt.up.priority != nil && !tt.wantErr {
+				assert.Equal(t, *tt.up.priority, task.Priority)
+			}
 		})
 	}
 }
 
-func stringPtr(s string) *string { return &s }
-func boolPtr(b bool) *bool       { return &b }
+func stringPtr(s string) *string     { return &s }
+func boolPtr(b bool) *bool           { return &b }
+func intPtr(i int) *int              { return &i }
+func timePtr(t time.Time) *time.Time { return &t }
 
 func TestCreateTask(t *testing.T) {
 	tests := []struct {
 		name                string
 		description         string
+		done                bool
+		dueDate             *time.Time
+		priority            int
+		tags                []string
 		expectedCreateCall  int
 		expectedDescription string
+		expectedDueDate     *time.Time
+		expectedTags        []string
 		wantErr             bool
+		expectedError       error
 	}{
 		{
 			name:                "successfully created task",
@@ -128,6 +230,14 @@ func TestCreateTask(t *testing.T) {
 			expectedDescription: "task 1",
 			wantErr:             false,
 		},
+		{
+			name:                "successfully created already done task",
+			description:         "task 1",
+			done:                true,
+			expectedCreateCall:  1,
+			expectedDescription: "task 1",
+			wantErr:             false,
+		},
 		{
 			name:                "empty description",
 			description:         "",
@@ -135,6 +245,68 @@ func TestCreateTask(t *testing.T) {
 			expectedDescription: "",
 			wantErr:             true,
 		},
+		{
+			name:               "blank description",
+			description:        "   ",
+			expectedCreateCall: 0,
+			wantErr:            true,
+			expectedError:      domain.ErrDescriptionRequired,
+		},
+		{
+			name:                "due date is stored in UTC at second precision",
+			description:         "task 1",
+			dueDate:             timePtr(time.Date(2099, 5, 1, 23, 59, 59, 999, time.FixedZone("UTC+2", 2*60*60))),
+			expectedCreateCall:  1,
+			expectedDescription: "task 1",
+			expectedDueDate:     timePtr(time.Date(2099, 5, 1, 21, 59, 59, 0, time.UTC)),
+		},
+		{
+			name:                "due date earlier today is accepted",
+			description:         "task 1",
+			dueDate:             timePtr(time.Now().UTC().Add(-time.Hour).Truncate(time.Second)),
+			expectedCreateCall:  1,
+			expectedDescription: "task 1",
+			expectedDueDate:     timePtr(time.Now().UTC().Add(-time.Hour).Truncate(time.Second)),
+		},
+		{
+			name:               "due date in the distant past",
+			description:        "task 1",
+			dueDate:            timePtr(time.Now().AddDate(0, 0, -3)),
+			expectedCreateCall: 0,
+			wantErr:            true,
+			expectedError:      domain.ErrDueDateInPast,
+		},
+		{
+			name:                "successfully created high priority task",
+			description:         "task 1",
+			priority:            domain.PriorityHigh,
+			expectedCreateCall:  1,
+			expectedDescription: "task 1",
+		},
+		{
+			name:               "priority out of range",
+			description:        "task 1",
+			priority:           4,
+			expectedCreateCall: 0,
+			wantErr:            true,
+			expectedError:      domain.ErrInvalidPriority,
+		},
+		{
+			name:                "tags are lowercased, sorted and deduplicated",
+			description:         "task 1",
+			tags:                []string{"Work", "home", "work"},
+			expectedCreateCall:  1,
+			expectedDescription: "task 1",
+			expectedTags:        []string{"home", "work"},
+		},
+		{
+			name:               "invalid tag",
+			description:        "task 1",
+			tags:               []string{"day job"},
+			expectedCreateCall: 0,
+			wantErr:            true,
+			expectedError:      domain.ErrInvalidTag,
+		},
 	}
 
 	ctx := context.Background()
@@ -143,15 +315,330 @@ func TestCreateTask(t *testing.T) {
 			store := &testhelpers.StubTaskStore{}
 			service := NewService(store)
 
-			task, err := service.CreateTask(ctx, tt.description, 1)
+			task, err := service.CreateTask(ctx, tt.description, tt.done, tt.dueDate, tt.priority, tt.tags, 1)
 			if tt.wantErr {
 				assert.Error(t, err)
+				if tt.expectedError != nil {
+					assert.ErrorIs(t, err, tt.expectedError)
+				}
 			} else {
 				assert.NoError(t, err)
 			}
 			assert.Equal(t, tt.expectedCreateCall, len(store.CreateCall))
 			assert.Equal(t, tt.expectedDescription, task.Description)
-			assert.False(t, task.Done)
+			assert.Equal(t, tt.done, task.Done)
+			assert.Equal(t, tt.done, task.CompletedAt != nil)
+			assert.Equal(t, !tt.wantErr, !task.CreatedAt.IsZero())
+			assert.Equal(t, task.CreatedAt, task.UpdatedAt)
+			assert.Equal(t, tt.expectedDueDate, task.DueDate)
+			assert.Equal(t, tt.expectedTags, task.Tags)
+			if !tt.wantErr {
+				assert.Equal(t, tt.priority, task.Priority)
+			}
+		})
+	}
+}
+
+func TestWithMaxDescriptionLength(t *testing.T) {
+	ctx := context.Background()
+	store := &testhelpers.StubTaskStore{Tasks: map[int]string{1: "task 1"}}
+	service := NewService(store, WithMaxDescriptionLength(10))
+
+	_, err := service.CreateTask(ctx, "0123456789", false, nil, 0, nil, 1)
+	assert.NoError(t, err)
+
+	_, err = service.CreateTask(ctx, "0123456789a", false, nil, 0, nil, 1)
+	assert.ErrorIs(t, err, domain.ErrDescriptionTooLong)
+	assert.ErrorContains(t, err, "max 10 characters")
+
+	_, err = service.CreateTasks(ctx, []domain.Task{{Description: "ok"}, {Description: "0123456789a"}}, 1)
+	assert.ErrorIs(t, err, domain.ErrDescriptionTooLong)
+
+	_, err = service.UpdateTask(ctx, 1, 1, stringPtr("0123456789a"), nil, nil, nil, nil)
+	assert.ErrorIs(t, err, domain.ErrDescriptionTooLong)
+	assert.ErrorContains(t, err, "max 10 characters")
+	assert.Zero(t, store.UpdateTaskCalled)
+
+	t.Run("non-positive limit keeps the default", func(t *testing.T) {
+		service := NewService(&testhelpers.StubTaskStore{}, WithMaxDescriptionLength(0))
+		_, err := service.CreateTask(ctx, strings.Repeat("a", 200), false, nil, 0, nil, 1)
+		assert.NoError(t, err)
+	})
+}
+
+func TestWithoutDuplicates(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("duplicates are allowed by default", func(t *testing.T) {
+		store := &testhelpers.StubTaskStore{Tasks: map[int]string{1: "buy milk"}}
+		service := NewService(store)
+
+		_, err := service.CreateTask(ctx, "buy milk", false, nil, 0, nil, 1)
+
+		assert.NoError(t, err)
+		assert.Len(t, store.CreateCall, 1)
+	})
+
+	t.Run("rejects a description the user already has", func(t *testing.T) {
+		store := &testhelpers.StubTaskStore{Tasks: map[int]string{1: "buy milk"}}
+		service := NewService(store, WithoutDuplicates(true))
+
+		_, err := service.CreateTask(ctx, " buy milk ", false, nil, 0, nil, 1)
+
+		assert.ErrorIs(t, err, domain.ErrDuplicateTask)
+		assert.Empty(t, store.CreateCall)
+	})
+
+	t.Run("allows a new description", func(t *testing.T) {
+		store := &testhelpers.StubTaskStore{Tasks: map[int]string{1: "buy milk"}}
+		service := NewService(store, WithoutDuplicates(true))
+
+		_, err := service.CreateTask(ctx, "buy bread", false, nil, 0, nil, 1)
+
+		assert.NoError(t, err)
+		assert.Len(t, store.CreateCall, 1)
+	})
+
+	t.Run("rejects a batch repeating a description", func(t *testing.T) {
+		store := &testhelpers.StubTaskStore{}
+		service := NewService(store, WithoutDuplicates(true))
+
+		_, err := service.CreateTasks(ctx, []domain.Task{{Description: "a"}, {Description: "b"}, {Description: "a"}}, 1)
+
+		var itemErr *domain.BatchItemError
+		require.ErrorAs(t, err, &itemErr)
+		assert.Equal(t, 2, itemErr.Index)
+		assert.ErrorIs(t, err, domain.ErrDuplicateTask)
+		assert.Empty(t, store.CreateCall)
+	})
+
+	t.Run("rejects a batch with a stored description", func(t *testing.T) {
+		store := &testhelpers.StubTaskStore{Tasks: map[int]string{1: "buy milk"}}
+		service := NewService(store, WithoutDuplicates(true))
+
+		_, err := service.CreateTasks(ctx, []domain.Task{{Description: "buy bread"}, {Description: "buy milk"}}, 1)
+
+		var itemErr *domain.BatchItemError
+		require.ErrorAs(t, err, &itemErr)
+		assert.Equal(t, 1, itemErr.Index)
+		assert.Empty(t, store.CreateCall)
+	})
+}
+
+func TestCreateTasks(t *testing.T) {
+	tests := []struct {
+		name               string
+		tasks              []domain.Task
+		expectedCreateCall int
+		expectedIndex      int
+		expectedError      error
+		wantItemErr        bool
+	}{
+		{
+			name:               "successfully created batch",
+			tasks:              []domain.Task{{Description: " task 1 "}, {Description: "task 2", Done: true}},
+			expectedCreateCall: 2,
+		},
+		{
+			name:          "empty batch",
+			tasks:         []domain.Task{},
+			expectedError: domain.ErrEmptyBatch,
+		},
+		{
+			name:          "invalid description rejects whole batch",
+			tasks:         []domain.Task{{Description: "task 1"}, {Description: ""}, {Description: "task 3"}},
+			expectedIndex: 1,
+			expectedError: domain.ErrDescriptionRequired,
+			wantItemErr:   true,
+		},
+		{
+			name:          "past due date rejects whole batch",
+			tasks:         []domain.Task{{Description: "task 1"}, {Description: "task 2", DueDate: timePtr(time.Now().AddDate(-1, 0, 0))}},
+			expectedIndex: 1,
+			expectedError: domain.ErrDueDateInPast,
+			wantItemErr:   true,
+		}, {
+			name:          "invalid tag rejects whole batch",
+			tasks:         []domain.Task{{Description: "task 1", Tags: []string{"a,b"}}},
+			expectedIndex: 0,
+			expectedError: domain.ErrInvalidTag,
+			wantItemErr:   true,
+		},
+	}
+
+	ctx := context.Background()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := &testhelpers.StubTaskStore{}
+			service := NewService(store)
+
+			created, err := service.CreateTasks(ctx, tt.tasks, 1)
+			assert.Equal(t, tt.expectedCreateCall, len(store.CreateCall))
+			if tt.expectedError != nil {
+				assert.ErrorIs(t, err, tt.expectedError)
+				var itemErr *domain.BatchItemError
+				assert.Equal(t, tt.wantItemErr, errors.As(err, &itemErr))
+				if tt.wantItemErr {
+					assert.Equal(t, tt.expectedIndex, itemErr.Index)
+				}
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, "task 1", created[0].Description)
+			assert.False(t, created[0].Done)
+			assert.True(t, created[1].Done)
+			assert.NotNil(t, created[1].CompletedAt)
+		})
+	}
+}
+
+func TestUpdateTasks(t *testing.T) {
+	ctx := context.Background()
+	newStore := func() *testhelpers.StubTaskStore {
+		return &testhelpers.StubTaskStore{Tasks: map[int]string{1: "task 1", 2: "task 2"}}
+	}
+
+	t.Run("applies every operation", func(t *testing.T) {
+		store := newStore()
+		service := NewService(store)
+
+		updated, err := service.UpdateTasks(ctx, []domain.TaskUpdate{
+			{ID: 1, Description: stringPtr(" task 1 updated ")},
+			{ID: 2, Done: boolPtr(true)},
+		}, 1)
+		require.NoError(t, err)
+		require.Len(t, updated, 2)
+		assert.Equal(t, "task 1 updated", updated[0].Description)
+		assert.False(t, updated[0].Done)
+		assert.Equal(t, "task 2", updated[1].Description)
+		assert.True(t, updated[1].Done)
+		assert.NotNil(t, updated[1].CompletedAt)
+		assert.Equal(t, map[int]string{1: "task 1 updated", 2: "task 2"}, store.Tasks)
+		assert.True(t, store.DoneTasks[2])
+	})
+
+	t.Run("a missing task rolls back the whole batch", func(t *testing.T) {
+		store := newStore()
+		service := NewService(store)
+
+		_, err := service.UpdateTasks(ctx, []domain.TaskUpdate{
+			{ID: 1, Description: stringPtr("task 1 updated")},
+			{ID: 3, Done: boolPtr(true)},
+		}, 1)
+		assert.ErrorIs(t, err, domain.ErrTaskNotFound)
+		var itemErr *domain.BatchItemError
+		require.ErrorAs(t, err, &itemErr)
+		assert.Equal(t, 1, itemErr.Index)
+		assert.Equal(t, "task 1", store.Tasks[1])
+		assert.Zero(t, store.UpdateTaskCalled)
+	})
+
+	invalid := []struct {
+		name          string
+		updates       []domain.TaskUpdate
+		expectedIndex int
+		expectedError error
+	}{
+		{name: "no field to change", updates: []domain.TaskUpdate{{ID: 1, Done: boolPtr(true)}, {ID: 2}}, expectedIndex: 1, expectedError: domain.ErrEmptyFieldsToUpdate},
+		{name: "empty description", updates: []domain.TaskUpdate{{ID: 1, Description: stringPtr("")}}, expectedIndex: 0, expectedError: domain.ErrDescriptionRequired},
+		{name: "invalid id", updates: []domain.TaskUpdate{{ID: 0, Done: boolPtr(true)}}, expectedIndex: 0, expectedError: validation.ErrInvalidTaskID},
+		{name: "repeated id", updates: []domain.TaskUpdate{{ID: 1, Done: boolPtr(true)}, {ID: 1, Description: stringPtr("task 1")}}, expectedIndex: 1, expectedError: validation.ErrDuplicateTaskID},
+	}
+	for _, tt := range invalid {
+		t.Run("rejects batch with "+tt.name, func(t *testing.T) {
+			store := newStore()
+			service := NewService(store)
+
+			_, err := service.UpdateTasks(ctx, tt.updates, 1)
+			assert.ErrorIs(t, err, tt.expectedError)
+			var itemErr *domain.BatchItemError
+			require.ErrorAs(t, err, &itemErr)
+			assert.Equal(t, tt.expectedIndex, itemErr.Index)
+			assert.Zero(t, store.UpdateTaskCalled)
 		})
 	}
+
+	t.Run("rejects an empty batch", func(t *testing.T) {
+		_, err := NewService(newStore()).UpdateTasks(ctx, nil, 1)
+		assert.ErrorIs(t, err, domain.ErrEmptyBatch)
+	})
+}
+
+func TestServiceHooks(t *testing.T) {
+	ctx := context.Background()
+	newService := func() (*Service, *[]TaskEvent) {
+		var events []TaskEvent
+		record := func(e TaskEvent) { events = append(events, e) }
+		store := &testhelpers.StubTaskStore{Tasks: map[int]string{1: "task 1"}}
+		s := NewService(store, WithHook(EventTaskCreated, record))
+		s.RegisterHook(EventTaskCompleted, record)
+		return s, &events
+	}
+
+	t.Run("create fires task.created with the stored task", func(t *testing.T) {
+		s, events := newService()
+
+		task, err := s.CreateTask(ctx, "buy milk", false, nil, 0, nil, 7)
+
+		assert.NoError(t, err)
+		assert.Equal(t, []TaskEvent{{Name: EventTaskCreated, UserID: 7, Task: task}}, *events)
+	})
+
+	t.Run("batch create fires once per task", func(t *testing.T) {
+		s, events := newService()
+
+		_, err := s.CreateTasks(ctx, []domain.Task{{Description: "a"}, {Description: "b"}}, 7)
+
+		assert.NoError(t, err)
+		if assert.Len(t, *events, 2) {
+			assert.Equal(t, "a", (*events)[0].Task.Description)
+			assert.Equal(t, "b", (*events)[1].Task.Description)
+		}
+	})
+
+	t.Run("marking an open task done fires task.completed", func(t *testing.T) {
+		s, events := newService()
+
+		task, err := s.UpdateTask(ctx, 1, 7, nil, boolPtr(true), nil, nil, nil)
+
+		assert.NoError(t, err)
+		assert.Equal(t, []TaskEvent{{Name: EventTaskCompleted, UserID: 7, Task: task}}, *events)
+		assert.True(t, (*events)[0].Task.Done)
+	})
+
+	t.Run("toggling an open task fires task.completed", func(t *testing.T) {
+		s, events := newService()
+
+		task, err := s.ToggleTask(ctx, 1, 7)
+		assert.NoError(t, err)
+		assert.Equal(t, []TaskEvent{{Name: EventTaskCompleted, UserID: 7, Task: task}}, *events)
+
+		_, err = s.ToggleTask(ctx, 1, 7)
+		assert.NoError(t, err)
+		assert.Len(t, *events, 1, "toggling back to open fires nothing")
+	})
+
+	t.Run("other updates fire nothing", func(t *testing.T) {
+		s, events := newService()
+
+		_, err := s.UpdateTask(ctx, 1, 7, stringPtr("renamed"), nil, nil, nil, nil)
+		assert.NoError(t, err)
+		_, err = s.UpdateTask(ctx, 1, 7, nil, boolPtr(false), nil, nil, nil)
+		assert.NoError(t, err)
+
+		assert.Empty(t, *events)
+	})
+
+	t.Run("failed operations fire nothing", func(t *testing.T) {
+		s, events := newService()
+
+		_, err := s.CreateTask(ctx, "", false, nil, 0, nil, 7)
+		assert.Error(t, err)
+		_, err = s.UpdateTask(ctx, 99, 7, nil, boolPtr(true), nil, nil, nil)
+		assert.Error(t, err)
+		_, err = s.ToggleTask(ctx, 99, 7)
+		assert.ErrorIs(t, err, domain.ErrTaskNotFound)
+
+		assert.Empty(t, *events)
+	})
 }
@@ -0,0 +1,46 @@
+package application
+
+import (
+	"regexp"
+	"time"
+)
+
+var placeholderPattern = regexp.MustCompile(`\{([a-z]+)\}`)
+
+// TemplateExpander replaces date placeholders such as {date} or {weekday}
+// in task descriptions using the configured timezone.
+type TemplateExpander struct {
+	location *time.Location
+	now      func() time.Time
+}
+
+// NewTemplateExpander creates an expander that renders placeholders in loc.
+// A nil location falls back to UTC.
+func NewTemplateExpander(loc *time.Location) *TemplateExpander {
+	if loc == nil {
+		loc = time.UTC
+	}
+	return &TemplateExpander{location: loc, now: time.Now}
+}
+
+// Expand returns description with known placeholders substituted.
+// Unknown placeholders are left untouched.
+func (e *TemplateExpander) Expand(description string) string {
+	now := e.now().In(e.location)
+	return placeholderPattern.ReplaceAllStringFunc(description, func(match string) string {
+		switch match {
+		case "{date}":
+			return now.Format("2006-01-02")
+		case "{time}":
+			return now.Format("15:04")
+		case "{weekday}":
+			return now.Weekday().String()
+		case "{month}":
+			return now.Month().String()
+		case "{year}":
+			return now.Format("2006")
+		default:
+			return match
+		}
+	})
+}
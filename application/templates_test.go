@@ -0,0 +1,77 @@
+package application
+
+import (
+	"context"
+	"myproject/infrastructure/testhelpers"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func fixedExpander(loc *time.Location, now time.Time) *TemplateExpander {
+	e := NewTemplateExpander(loc)
+	e.now = func() time.Time { return now }
+	return e
+}
+
+func TestTemplateExpander_Expand(t *testing.T) {
+	now := time.Date(2024, 3, 15, 22, 30, 0, 0, time.UTC)
+
+	tests := []struct {
+		name        string
+		location    *time.Location
+		description string
+		expected    string
+	}{
+		{
+			name:        "expands known placeholders",
+			location:    time.UTC,
+			description: "Report for {date} ({weekday})",
+			expected:    "Report for 2024-03-15 (Friday)",
+		},
+		{
+			name:        "expands time month and year",
+			location:    time.UTC,
+			description: "{time} {month} {year}",
+			expected:    "22:30 March 2024",
+		},
+		{
+			name:        "leaves unknown placeholders literal",
+			location:    time.UTC,
+			description: "Call {name} on {date}",
+			expected:    "Call {name} on 2024-03-15",
+		},
+		{
+			name:        "uses configured timezone",
+			location:    time.FixedZone("UTC+3", 3*60*60),
+			description: "{date} {weekday}",
+			expected:    "2024-03-16 Saturday",
+		},
+		{
+			name:        "returns description without placeholders unchanged",
+			location:    time.UTC,
+			description: "plain task",
+			expected:    "plain task",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := fixedExpander(tt.location, now)
+			assert.Equal(t, tt.expected, e.Expand(tt.description))
+		})
+	}
+}
+
+func TestCreateTask_WithTemplateExpander(t *testing.T) {
+	store := &testhelpers.StubTaskStore{Tasks: map[int]string{}}
+	now := time.Date(2024, 3, 15, 9, 0, 0, 0, time.UTC)
+	service := NewService(store, WithTemplateExpander(fixedExpander(time.UTC, now)))
+
+	task, err := service.CreateTask(context.Background(), "Standup {date}", false, nil, 0, nil, 1)
+
+	require.NoError(t, err)
+	assert.Equal(t, "Standup 2024-03-15", task.Description)
+}
@@ -1,12 +1,16 @@
 package auth
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"io"
 	"myproject/cmd/cli/client"
+	"myproject/cmd/cli/symbols"
+	"myproject/domain"
+	"myproject/domain/validation"
 	"os"
 	"path/filepath"
-	"regexp"
 	"strings"
 	"syscall"
 
@@ -27,6 +31,7 @@ type AuthManager interface {
 	// Interactive authentication
 	PromptLogin() (string, error)
 	PromptRegister() (string, error)
+	PromptChangePassword(ctx context.Context) error
 
 	// Re-authentication handling
 	HandleAuthError() (string, error)
@@ -37,30 +42,144 @@ type InputReader interface {
 	ReadInput(maxSize int) (string, error)
 }
 
+// PasswordReader defines an interface for reading a password after showing a prompt
+type PasswordReader interface {
+	ReadPassword(prompt string) (string, error)
+}
+
+// TerminalPasswordReader reads passwords with echo disabled when stdin is a terminal
+// Otherwise, e.g. when credentials are piped in, it reads a plain line from the fallback InputReader
+type TerminalPasswordReader struct {
+	fd       int
+	fallback InputReader
+	output   io.Writer
+}
+
+// NewTerminalPasswordReader creates a TerminalPasswordReader for stdin that writes prompts to output
+func NewTerminalPasswordReader(fallback InputReader, output io.Writer) *TerminalPasswordReader {
+	return &TerminalPasswordReader{
+		fd:       int(syscall.Stdin),
+		fallback: fallback,
+		output:   output,
+	}
+}
+
+// ReadPassword writes the prompt and reads a password, masked if stdin is a terminal
+// Uses golang.org/x/term package for secure terminal password reading
+func (r *TerminalPasswordReader) ReadPassword(prompt string) (string, error) {
+	fmt.Fprint(r.output, prompt)
+
+	if !term.IsTerminal(r.fd) {
+		return r.fallback.ReadInput(100)
+	}
+
+	passwordBytes, err := term.ReadPassword(r.fd)
+	if err != nil {
+		return "", err
+	}
+
+	// Print newline after password input (since ReadPassword doesn't echo)
+	fmt.Fprintln(r.output)
+
+	return string(passwordBytes), nil
+}
+
 // FileAuthManager implements AuthManager using file-based token storage
 type FileAuthManager struct {
 	tokenPath string
 	client    client.TaskClient
 	input     InputReader
+	passwords PasswordReader
 	output    io.Writer
+	sym       symbols.Set
+
+	// encrypt stores the token sealed with a passphrase instead of in plaintext
+	encrypt bool
+	// passphrase is asked for once per session and kept in memory only
+	passphrase string
+}
+
+// FileAuthManagerOption configures optional FileAuthManager behaviour
+type FileAuthManagerOption func(*FileAuthManager)
+
+// WithPasswordReader replaces the default TerminalPasswordReader, e.g. to supply credentials in tests
+func WithPasswordReader(passwords PasswordReader) FileAuthManagerOption {
+	return func(m *FileAuthManager) {
+		m.passwords = passwords
+	}
 }
 
-// NewFileAuthManager creates a new FileAuthManager with token storage in ~/.task-cli/token
-func NewFileAuthManager(client client.TaskClient, input InputReader, output io.Writer) *FileAuthManager {
+// WithSymbols sets the status prefixes of printed messages, e.g. symbols.Plain for terminals without emoji
+func WithSymbols(sym symbols.Set) FileAuthManagerOption {
+	return func(m *FileAuthManager) {
+		m.sym = sym
+	}
+}
+
+// WithTokenEncryption makes SaveToken encrypt the token with AES-GCM under a key derived from a passphrase,
+// which is asked for once per session
+func WithTokenEncryption() FileAuthManagerOption {
+	return func(m *FileAuthManager) {
+		m.encrypt = true
+	}
+}
+
+// DefaultTokenPath returns where the token is stored unless configured otherwise:
+// $XDG_CONFIG_HOME/task-cli/token when XDG_CONFIG_HOME is set, ~/.task-cli/token otherwise
+func DefaultTokenPath() string {
+	if configHome := os.Getenv("XDG_CONFIG_HOME"); configHome != "" {
+		return filepath.Join(configHome, "task-cli", "token")
+	}
 	homeDir, _ := os.UserHomeDir()
-	tokenPath := filepath.Join(homeDir, ".task-cli", "token")
+	return filepath.Join(homeDir, ".task-cli", "token")
+}
 
-	return &FileAuthManager{
+// NewFileAuthManager creates a new FileAuthManager with token storage at DefaultTokenPath
+func NewFileAuthManager(client client.TaskClient, input InputReader, output io.Writer, opts ...FileAuthManagerOption) *FileAuthManager {
+	return NewFileAuthManagerWithTokenPath(DefaultTokenPath(), client, input, output, opts...)
+}
+
+// NewFileAuthManagerWithTokenPath creates a new FileAuthManager storing the token at tokenPath
+// Passwords are read with a TerminalPasswordReader unless set with WithPasswordReader
+func NewFileAuthManagerWithTokenPath(tokenPath string, client client.TaskClient, input InputReader, output io.Writer, opts ...FileAuthManagerOption) *FileAuthManager {
+	m := &FileAuthManager{
 		tokenPath: tokenPath,
 		client:    client,
 		input:     input,
+		passwords: NewTerminalPasswordReader(input, output),
 		output:    output,
+		sym:       symbols.Emoji,
 	}
+	for _, opt := range opts {
+		opt(m)
+	}
+	return m
 }
 
-// SaveToken writes the token to file with 0600 permissions
+// SaveToken writes the token to file with 0600 permissions, encrypted if WithTokenEncryption is set
 // Creates parent directories with 0700 permissions if they don't exist
 func (m *FileAuthManager) SaveToken(token string) error {
+	if m.encrypt {
+		passphrase, err := m.tokenPassphrase(true)
+		if err != nil {
+			return err
+		}
+		return m.SaveTokenEncrypted(token, passphrase)
+	}
+	return m.writeTokenFile(token)
+}
+
+// SaveTokenEncrypted writes the token sealed with a key derived from passphrase
+func (m *FileAuthManager) SaveTokenEncrypted(token, passphrase string) error {
+	data, err := encryptToken(token, passphrase)
+	if err != nil {
+		return fmt.Errorf("failed to encrypt token: %w", err)
+	}
+	return m.writeTokenFile(data)
+}
+
+// writeTokenFile stores data as the token file
+func (m *FileAuthManager) writeTokenFile(data string) error {
 	// Create parent directory if it doesn't exist
 	dir := filepath.Dir(m.tokenPath)
 	if err := os.MkdirAll(dir, 0700); err != nil {
@@ -68,16 +187,52 @@ func (m *FileAuthManager) SaveToken(token string) error {
 	}
 
 	// Write token to file with restricted permissions
-	if err := os.WriteFile(m.tokenPath, []byte(token), 0600); err != nil {
+	if err := os.WriteFile(m.tokenPath, []byte(data), 0600); err != nil {
 		return fmt.Errorf("failed to save token: %w", err)
 	}
 
 	return nil
 }
 
-// LoadToken reads the token from file
+// LoadToken reads the token from file, asking for the passphrase if it was stored encrypted
 // Verifies file permissions on load and warns if too permissive
 func (m *FileAuthManager) LoadToken() (string, error) {
+	data, err := m.readTokenFile()
+	if err != nil {
+		return "", err
+	}
+	if !isEncryptedToken(data) {
+		return data, nil
+	}
+
+	passphrase, err := m.tokenPassphrase(false)
+	if err != nil {
+		return "", err
+	}
+	token, err := decryptToken(data, passphrase)
+	if err != nil {
+		// Forget the passphrase so the next attempt asks again
+		m.passphrase = ""
+		return "", err
+	}
+	return token, nil
+}
+
+// LoadTokenEncrypted reads a token written by SaveTokenEncrypted
+// Returns ErrWrongPassphrase if passphrase does not match the one it was saved with
+func (m *FileAuthManager) LoadTokenEncrypted(passphrase string) (string, error) {
+	data, err := m.readTokenFile()
+	if err != nil {
+		return "", err
+	}
+	if !isEncryptedToken(data) {
+		return "", fmt.Errorf("token file is not encrypted")
+	}
+	return decryptToken(data, passphrase)
+}
+
+// readTokenFile returns the trimmed content of the token file
+func (m *FileAuthManager) readTokenFile() (string, error) {
 	// Check if file exists
 	info, err := os.Stat(m.tokenPath)
 	if err != nil {
@@ -107,6 +262,34 @@ func (m *FileAuthManager) LoadToken() (string, error) {
 	return token, nil
 }
 
+// tokenPassphrase returns the passphrase of this session, asking for it the first time
+// confirm asks twice, for a passphrase that is about to encrypt a new token
+func (m *FileAuthManager) tokenPassphrase(confirm bool) (string, error) {
+	if m.passphrase != "" {
+		return m.passphrase, nil
+	}
+
+	passphrase, err := m.passwords.ReadPassword("Token passphrase: ")
+	if err != nil {
+		return "", fmt.Errorf("failed to read token passphrase: %w", err)
+	}
+	if passphrase == "" {
+		return "", fmt.Errorf("token passphrase cannot be empty")
+	}
+	if confirm {
+		confirmPassphrase, err := m.passwords.ReadPassword("Confirm token passphrase: ")
+		if err != nil {
+			return "", fmt.Errorf("failed to read passphrase confirmation: %w", err)
+		}
+		if passphrase != confirmPassphrase {
+			return "", fmt.Errorf("passphrases do not match")
+		}
+	}
+
+	m.passphrase = passphrase
+	return passphrase, nil
+}
+
 // ClearToken deletes the token file
 func (m *FileAuthManager) ClearToken() error {
 	if err := os.Remove(m.tokenPath); err != nil {
@@ -132,6 +315,10 @@ func (m *FileAuthManager) RequireAuth() (string, error) {
 	if err == nil && token != "" {
 		return token, nil
 	}
+	// A wrong passphrase should not look like a missing token
+	if errors.Is(err, ErrWrongPassphrase) {
+		return "", err
+	}
 
 	// No token found, prompt for authentication
 	fmt.Fprintln(m.output, "\nNo authentication token found.")
@@ -171,13 +358,13 @@ func (m *FileAuthManager) PromptLogin() (string, error) {
 	}
 
 	// Prompt for password (masked)
-	password, err := m.readPassword("Password: ")
+	password, err := m.passwords.ReadPassword("Password: ")
 	if err != nil {
 		return "", fmt.Errorf("failed to read password: %w", err)
 	}
 
 	// Call client.Login
-	token, err := m.client.Login(email, password)
+	token, err := m.client.Login(context.Background(), email, password)
 	if err != nil {
 		// Check if it's a 401 error
 		if apiErr, ok := err.(*client.APIError); ok && apiErr.StatusCode == 401 {
@@ -191,7 +378,7 @@ func (m *FileAuthManager) PromptLogin() (string, error) {
 		return "", fmt.Errorf("login successful but failed to save token: %w", err)
 	}
 
-	fmt.Fprintln(m.output, "✅ Login successful!")
+	fmt.Fprintf(m.output, "%s Login successful!\n", m.sym.OK)
 	return token, nil
 }
 
@@ -207,13 +394,14 @@ func (m *FileAuthManager) PromptRegister() (string, error) {
 		return "", fmt.Errorf("failed to read email: %w", err)
 	}
 
-	// Validate email format before making API call
-	if err := validateEmail(email); err != nil {
+	// Validate email format before making API call, with the same rules as the server
+	email = strings.TrimSpace(email)
+	if err := validation.ValidateEmail(email); err != nil {
 		return "", fmt.Errorf("validation failed: %w", err)
 	}
 
 	// Prompt for password (masked)
-	password, err := m.readPassword("Password (8-72 characters): ")
+	password, err := m.passwords.ReadPassword("Password (8-72 characters, letters and digits): ")
 	if err != nil {
 		return "", fmt.Errorf("failed to read password: %w", err)
 	}
@@ -224,7 +412,7 @@ func (m *FileAuthManager) PromptRegister() (string, error) {
 	}
 
 	// Prompt for password confirmation (masked)
-	confirmPassword, err := m.readPassword("Confirm password: ")
+	confirmPassword, err := m.passwords.ReadPassword("Confirm password: ")
 	if err != nil {
 		return "", fmt.Errorf("failed to read password confirmation: %w", err)
 	}
@@ -235,7 +423,7 @@ func (m *FileAuthManager) PromptRegister() (string, error) {
 	}
 
 	// Call client.Register
-	token, err := m.client.Register(email, password)
+	token, err := m.client.Register(context.Background(), email, password)
 	if err != nil {
 		// Check if it's a conflict error (user already exists)
 		if apiErr, ok := err.(*client.APIError); ok && apiErr.StatusCode == 409 {
@@ -249,19 +437,61 @@ func (m *FileAuthManager) PromptRegister() (string, error) {
 		return "", fmt.Errorf("registration successful but failed to save token: %w", err)
 	}
 
-	fmt.Fprintln(m.output, "✅ Registration successful!")
+	fmt.Fprintf(m.output, "%s Registration successful!\n", m.sym.OK)
 	return token, nil
 }
 
+// PromptChangePassword prompts for the current and new passwords (masked) and calls client.ChangePassword
+// The stored token stays valid, so nothing is saved afterwards
+func (m *FileAuthManager) PromptChangePassword(ctx context.Context) error {
+	fmt.Fprintln(m.output, "\n=== Change Password ===")
+
+	currentPassword, err := m.passwords.ReadPassword("Current password: ")
+	if err != nil {
+		return fmt.Errorf("failed to read current password: %w", err)
+	}
+
+	newPassword, err := m.passwords.ReadPassword("New password (8-72 characters, letters and digits): ")
+	if err != nil {
+		return fmt.Errorf("failed to read new password: %w", err)
+	}
+
+	// Validate password requirements before making API call
+	if err := validatePassword(newPassword); err != nil {
+		return fmt.Errorf("validation failed: %w", err)
+	}
+	if newPassword == currentPassword {
+		return fmt.Errorf("validation failed: new password must differ from the current password")
+	}
+
+	confirmPassword, err := m.passwords.ReadPassword("Confirm new password: ")
+	if err != nil {
+		return fmt.Errorf("failed to read password confirmation: %w", err)
+	}
+	if newPassword != confirmPassword {
+		return fmt.Errorf("passwords do not match")
+	}
+
+	if err := m.client.ChangePassword(ctx, currentPassword, newPassword); err != nil {
+		// A 401 here means the current password was rejected, not that the session expired
+		if client.IsAuthError(err) {
+			return fmt.Errorf("password change failed: current password is incorrect")
+		}
+		return fmt.Errorf("password change failed: %w", err)
+	}
+
+	return nil
+}
+
 // HandleAuthError handles 401 authentication errors by clearing the token and prompting for re-authentication
 // Returns a new valid token or error
 func (m *FileAuthManager) HandleAuthError() (string, error) {
 	// Clear the invalid token
 	if err := m.ClearToken(); err != nil {
-		fmt.Fprintf(m.output, "⚠️  Warning: failed to clear invalid token: %v\n", err)
+		fmt.Fprintf(m.output, "%s Warning: failed to clear invalid token: %v\n", m.sym.Warning, err)
 	}
 
-	fmt.Fprintln(m.output, "\n🔒 Your session has expired or is invalid.")
+	fmt.Fprintf(m.output, "\n%s Your session has expired or is invalid.\n", m.sym.Lock)
 	fmt.Fprintln(m.output, "Please authenticate again.")
 	fmt.Fprintln(m.output, "\nChoose an option:")
 	fmt.Fprintln(m.output, "1. Login")
@@ -286,63 +516,7 @@ func (m *FileAuthManager) HandleAuthError() (string, error) {
 	}
 }
 
-// readPassword reads password input with character masking
-// Uses golang.org/x/term package for secure terminal password reading
-func (m *FileAuthManager) readPassword(prompt string) (string, error) {
-	fmt.Fprint(m.output, prompt)
-
-	// Check if stdin is a terminal
-	fd := int(syscall.Stdin)
-	if !term.IsTerminal(fd) {
-		// Not a terminal, use the InputReader (supports testing)
-		password, err := m.input.ReadInput(100)
-		if err != nil {
-			return "", err
-		}
-		return password, nil
-	}
-
-	// Read password with masking
-	passwordBytes, err := term.ReadPassword(fd)
-	if err != nil {
-		return "", err
-	}
-
-	// Print newline after password input (since ReadPassword doesn't echo)
-	fmt.Fprintln(m.output)
-
-	return string(passwordBytes), nil
-}
-
-// validateEmail checks if an email address has a valid format
-func validateEmail(email string) error {
-	email = strings.TrimSpace(email)
-	if email == "" {
-		return fmt.Errorf("invalid email format")
-	}
-
-	// Use the same regex pattern as the server-side validation
-	emailRegex := `^[a-zA-Z0-9._%+-]+@[a-zA-Z0-9.-]+\.[a-zA-Z]{2,}$`
-	matched, err := regexp.MatchString(emailRegex, email)
-	if err != nil {
-		return fmt.Errorf("invalid email format")
-	}
-	if !matched {
-		return fmt.Errorf("invalid email format")
-	}
-
-	return nil
-}
-
-// validatePassword checks if a password meets minimum security requirements
+// validatePassword mirrors the server's default password policy so weak passwords fail before the API call
 func validatePassword(password string) error {
-	if len(password) < 8 {
-		return fmt.Errorf("password must be at least 8 characters")
-	}
-
-	if len(password) > 72 {
-		return fmt.Errorf("password must be max 72 characters")
-	}
-
-	return nil
+	return domain.DefaultPasswordPolicy().Validate(password)
 }
@@ -2,17 +2,24 @@ package auth
 
 import (
 	"bytes"
+	"context"
 	"errors"
 	"myproject/cmd/cli/client"
+	"os"
+	"path/filepath"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
-// MockInputReader is a mock implementation of InputReader for testing
+// MockInputReader is a mock implementation of InputReader and PasswordReader for testing
+// Passwords are taken from the same inputs; their prompts are recorded
 type MockInputReader struct {
-	inputs []string
-	index  int
+	inputs  []string
+	index   int
+	prompts []string
 }
 
 func NewMockInputReader(inputs ...string) *MockInputReader {
@@ -31,6 +38,11 @@ func (m *MockInputReader) ReadInput(maxSize int) (string, error) {
 	return input, nil
 }
 
+func (m *MockInputReader) ReadPassword(prompt string) (string, error) {
+	m.prompts = append(m.prompts, prompt)
+	return m.ReadInput(100)
+}
+
 // MockTaskClient is a mock implementation of TaskClient for testing
 type MockTaskClient struct {
 	loginEmail    string
@@ -42,29 +54,87 @@ type MockTaskClient struct {
 	registerPassword string
 	registerToken    string
 	registerErr      error
+
+	changePasswordCurrent string
+	changePasswordNew     string
+	changePasswordErr     error
 }
 
-func (m *MockTaskClient) Login(email, password string) (string, error) {
+func (m *MockTaskClient) Login(ctx context.Context, email, password string) (string, error) {
 	m.loginEmail = email
 	m.loginPassword = password
 	return m.loginToken, m.loginErr
 }
 
-func (m *MockTaskClient) Register(email, password string) (string, error) {
+func (m *MockTaskClient) Register(ctx context.Context, email, password string) (string, error) {
 	m.registerEmail = email
 	m.registerPassword = password
 	return m.registerToken, m.registerErr
 }
 
-func (m *MockTaskClient) GetTasks() ([]client.Task, error)                    { return nil, nil }
-func (m *MockTaskClient) GetTask(id int) (*client.Task, error)                { return nil, nil }
-func (m *MockTaskClient) CreateTask(description string) (*client.Task, error) { return nil, nil }
-func (m *MockTaskClient) UpdateTask(id int, description *string, done *bool) (*client.Task, error) {
+func (m *MockTaskClient) GetTasks(ctx context.Context, limit, offset int, query client.ListQuery) (*client.TaskList, error) {
+	return nil, nil
+}
+func (m *MockTaskClient) GetTask(ctx context.Context, id int) (*client.Task, error) { return nil, nil }
+func (m *MockTaskClient) SearchTasks(ctx context.Context, query string) ([]client.Task, error) {
+	return nil, nil
+}
+func (m *MockTaskClient) GetTasksByTag(ctx context.Context, tag string, includeArchived bool) ([]client.Task, error) {
+	return nil, nil
+}
+func (m *MockTaskClient) AddTag(ctx context.Context, id int, tag string) (*client.Task, error) {
+	return nil, nil
+}
+func (m *MockTaskClient) RemoveTag(ctx context.Context, id int, tag string) (*client.Task, error) {
+	return nil, nil
+}
+func (m *MockTaskClient) Stats(ctx context.Context) (*client.Stats, error) {
+	return nil, nil
+}
+func (m *MockTaskClient) CreateTask(ctx context.Context, description string, done bool, dueDate *time.Time, priority int) (*client.Task, error) {
+	return nil, nil
+}
+func (m *MockTaskClient) CreateTasks(ctx context.Context, descriptions []string) ([]int, error) {
+	return nil, nil
+}
+func (m *MockTaskClient) UpdateTask(ctx context.Context, id int, description *string, done *bool, version *int) (*client.Task, error) {
+	return nil, nil
+}
+func (m *MockTaskClient) DeleteTask(ctx context.Context, id int) error { return nil }
+func (m *MockTaskClient) PurgeTask(ctx context.Context, id int) error  { return nil }
+func (m *MockTaskClient) ToggleTask(ctx context.Context, id int) (*client.Task, error) {
 	return nil, nil
 }
-func (m *MockTaskClient) DeleteTask(id int) error { return nil }
-func (m *MockTaskClient) SetToken(token string)   {}
-func (m *MockTaskClient) GetServerURL() string    { return "http://localhost:8080" }
+func (m *MockTaskClient) UpdateAllTaskStatus(ctx context.Context, done bool) (int, error) {
+	return 0, nil
+}
+func (m *MockTaskClient) DeleteTasks(ctx context.Context, ids []int) (int, error) {
+	return 0, nil
+}
+func (m *MockTaskClient) RestoreTask(ctx context.Context, id int) (*client.Task, error) {
+	return nil, nil
+}
+func (m *MockTaskClient) ArchiveTask(ctx context.Context, id int) (*client.Task, error) {
+	return nil, nil
+}
+func (m *MockTaskClient) ArchiveCompleted(ctx context.Context) (int, error) {
+	return 0, nil
+}
+func (m *MockTaskClient) Me(ctx context.Context) (*client.User, error) {
+	return nil, nil
+}
+func (m *MockTaskClient) DeleteAccount(ctx context.Context) error {
+	return nil
+}
+func (m *MockTaskClient) ChangePassword(ctx context.Context, currentPassword, newPassword string) error {
+	m.changePasswordCurrent = currentPassword
+	m.changePasswordNew = newPassword
+	return m.changePasswordErr
+}
+func (m *MockTaskClient) Ping(ctx context.Context) error                     { return nil }
+func (m *MockTaskClient) Limits(ctx context.Context) (*client.Limits, error) { return nil, nil }
+func (m *MockTaskClient) SetToken(token string)                              {}
+func (m *MockTaskClient) GetServerURL() string                               { return "http://localhost:8080" }
 
 // TestFileAuthManager_HandleAuthError tests the HandleAuthError method
 func TestFileAuthManager_HandleAuthError(t *testing.T) {
@@ -128,6 +198,7 @@ func TestFileAuthManager_HandleAuthError(t *testing.T) {
 				tokenPath: "/tmp/test-token",
 				client:    mockClient,
 				input:     mockInput,
+				passwords: mockInput,
 				output:    output,
 			}
 
@@ -166,6 +237,7 @@ func TestFileAuthManager_HandleAuthError_ClearsToken(t *testing.T) {
 		tokenPath: tokenPath,
 		client:    mockClient,
 		input:     mockInput,
+		passwords: mockInput,
 		output:    output,
 	}
 
@@ -188,3 +260,199 @@ func TestFileAuthManager_HandleAuthError_ClearsToken(t *testing.T) {
 	assert.NoError(t, err)
 	assert.Equal(t, "new-token", savedToken)
 }
+
+func TestFileAuthManager_TokenPath(t *testing.T) {
+	t.Run("saves and loads the token at the given path", func(t *testing.T) {
+		tokenPath := filepath.Join(t.TempDir(), "nested", "token")
+		authMgr := NewFileAuthManagerWithTokenPath(tokenPath, &MockTaskClient{}, NewMockInputReader(), &bytes.Buffer{})
+
+		require.NoError(t, authMgr.SaveToken("saved-token"))
+
+		info, err := os.Stat(tokenPath)
+		require.NoError(t, err)
+		assert.Equal(t, os.FileMode(0600), info.Mode().Perm())
+		token, err := authMgr.LoadToken()
+		require.NoError(t, err)
+		assert.Equal(t, "saved-token", token)
+	})
+	t.Run("defaults to XDG_CONFIG_HOME when set", func(t *testing.T) {
+		configHome := t.TempDir()
+		t.Setenv("XDG_CONFIG_HOME", configHome)
+
+		assert.Equal(t, filepath.Join(configHome, "task-cli", "token"), DefaultTokenPath())
+	})
+	t.Run("falls back to the home directory", func(t *testing.T) {
+		home := t.TempDir()
+		t.Setenv("XDG_CONFIG_HOME", "")
+		t.Setenv("HOME", home)
+
+		assert.Equal(t, filepath.Join(home, ".task-cli", "token"), DefaultTokenPath())
+	})
+}
+
+func TestFileAuthManager_TokenEncryption(t *testing.T) {
+	t.Run("stores the token encrypted and loads it with the passphrase", func(t *testing.T) {
+		tokenPath := filepath.Join(t.TempDir(), "token")
+		passwords := NewMockInputReader("secret phrase", "secret phrase")
+		authMgr := NewFileAuthManagerWithTokenPath(tokenPath, &MockTaskClient{}, NewMockInputReader(), &bytes.Buffer{},
+			WithPasswordReader(passwords), WithTokenEncryption())
+
+		require.NoError(t, authMgr.SaveToken("secret-token"))
+
+		data, err := os.ReadFile(tokenPath)
+		require.NoError(t, err)
+		assert.NotContains(t, string(data), "secret-token")
+
+		// The passphrase is asked for once per session
+		token, err := authMgr.LoadToken()
+		require.NoError(t, err)
+		assert.Equal(t, "secret-token", token)
+		assert.Equal(t, []string{"Token passphrase: ", "Confirm token passphrase: "}, passwords.prompts)
+	})
+	t.Run("wrong passphrase fails with a clear error", func(t *testing.T) {
+		tokenPath := filepath.Join(t.TempDir(), "token")
+		saver := NewFileAuthManagerWithTokenPath(tokenPath, &MockTaskClient{}, NewMockInputReader(), &bytes.Buffer{})
+		require.NoError(t, saver.SaveTokenEncrypted("secret-token", "right phrase"))
+
+		authMgr := NewFileAuthManagerWithTokenPath(tokenPath, &MockTaskClient{}, NewMockInputReader(), &bytes.Buffer{},
+			WithPasswordReader(NewMockInputReader("wrong phrase")))
+
+		_, err := authMgr.LoadToken()
+		assert.ErrorIs(t, err, ErrWrongPassphrase)
+		_, err = authMgr.LoadTokenEncrypted("wrong phrase")
+		assert.ErrorIs(t, err, ErrWrongPassphrase)
+		token, err := authMgr.LoadTokenEncrypted("right phrase")
+		require.NoError(t, err)
+		assert.Equal(t, "secret-token", token)
+	})
+	t.Run("RequireAuth reports a wrong passphrase instead of prompting to log in", func(t *testing.T) {
+		tokenPath := filepath.Join(t.TempDir(), "token")
+		saver := NewFileAuthManagerWithTokenPath(tokenPath, &MockTaskClient{}, NewMockInputReader(), &bytes.Buffer{})
+		require.NoError(t, saver.SaveTokenEncrypted("secret-token", "right phrase"))
+
+		authMgr := NewFileAuthManagerWithTokenPath(tokenPath, &MockTaskClient{}, NewMockInputReader(), &bytes.Buffer{},
+			WithPasswordReader(NewMockInputReader("wrong phrase")))
+
+		_, err := authMgr.RequireAuth()
+		assert.ErrorIs(t, err, ErrWrongPassphrase)
+	})
+	t.Run("mismatched confirmation does not save", func(t *testing.T) {
+		tokenPath := filepath.Join(t.TempDir(), "token")
+		authMgr := NewFileAuthManagerWithTokenPath(tokenPath, &MockTaskClient{}, NewMockInputReader(), &bytes.Buffer{},
+			WithPasswordReader(NewMockInputReader("one", "two")), WithTokenEncryption())
+
+		err := authMgr.SaveToken("secret-token")
+
+		assert.EqualError(t, err, "passphrases do not match")
+		assert.NoFileExists(t, tokenPath)
+	})
+}
+
+// TestFileAuthManager_PromptChangePassword tests the PromptChangePassword method
+func TestFileAuthManager_PromptChangePassword(t *testing.T) {
+	testCases := []struct {
+		name              string
+		inputs            []string
+		changePasswordErr error
+		expectedErr       string
+		expectCall        bool
+	}{
+		{
+			name:       "Successful password change",
+			inputs:     []string{"password123", "newpassword456", "newpassword456"},
+			expectCall: true,
+		},
+		{
+			name:        "New password too weak",
+			inputs:      []string{"password123", "newpassword"},
+			expectedErr: "password must contain a digit",
+		},
+		{
+			name:        "New password equals current",
+			inputs:      []string{"password123", "password123"},
+			expectedErr: "must differ",
+		},
+		{
+			name:        "Confirmation does not match",
+			inputs:      []string{"password123", "newpassword456", "newpassword789"},
+			expectedErr: "passwords do not match",
+		},
+		{
+			name:              "Wrong current password",
+			inputs:            []string{"wrongpassword1", "newpassword456", "newpassword456"},
+			changePasswordErr: &client.AuthError{Message: "unauthorized"},
+			expectedErr:       "current password is incorrect",
+			expectCall:        true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			mockClient := &MockTaskClient{changePasswordErr: tc.changePasswordErr}
+			mockInput := NewMockInputReader(tc.inputs...)
+			authMgr := &FileAuthManager{
+				tokenPath: "/tmp/test-token",
+				client:    mockClient,
+				input:     mockInput,
+				passwords: mockInput,
+				output:    &bytes.Buffer{},
+			}
+
+			err := authMgr.PromptChangePassword(context.Background())
+
+			if tc.expectedErr != "" {
+				assert.ErrorContains(t, err, tc.expectedErr)
+				assert.False(t, client.IsAuthError(err), "a wrong current password must not trigger re-authentication")
+			} else {
+				assert.NoError(t, err)
+			}
+			if tc.expectCall {
+				assert.Equal(t, tc.inputs[0], mockClient.changePasswordCurrent)
+				assert.Equal(t, tc.inputs[1], mockClient.changePasswordNew)
+			} else {
+				assert.Empty(t, mockClient.changePasswordNew)
+			}
+		})
+	}
+}
+
+// TestFileAuthManager_PromptLogin_PasswordReader tests that passwords come from the injected PasswordReader
+func TestFileAuthManager_PromptLogin_PasswordReader(t *testing.T) {
+	mockClient := &MockTaskClient{loginToken: "token-123"}
+	passwords := NewMockInputReader("password123")
+	authMgr := NewFileAuthManagerWithTokenPath(
+		filepath.Join(t.TempDir(), "token"),
+		mockClient,
+		NewMockInputReader("test@example.com"),
+		&bytes.Buffer{},
+		WithPasswordReader(passwords),
+	)
+
+	token, err := authMgr.PromptLogin()
+
+	require.NoError(t, err)
+	assert.Equal(t, "token-123", token)
+	assert.Equal(t, "test@example.com", mockClient.loginEmail)
+	assert.Equal(t, "password123", mockClient.loginPassword)
+	assert.Equal(t, []string{"Password: "}, passwords.prompts)
+}
+
+// TestTerminalPasswordReader_NotTerminal tests that piped input is read through the fallback InputReader
+func TestTerminalPasswordReader_NotTerminal(t *testing.T) {
+	file, err := os.CreateTemp(t.TempDir(), "stdin")
+	require.NoError(t, err)
+	defer file.Close()
+
+	output := &bytes.Buffer{}
+	reader := &TerminalPasswordReader{
+		fd:       int(file.Fd()),
+		fallback: NewMockInputReader("password123"),
+		output:   output,
+	}
+
+	password, err := reader.ReadPassword("Password: ")
+
+	require.NoError(t, err)
+	assert.Equal(t, "password123", password)
+	assert.Equal(t, "Password: ", output.String())
+}
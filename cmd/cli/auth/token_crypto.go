@@ -0,0 +1,95 @@
+package auth
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+
+	"golang.org/x/crypto/scrypt"
+)
+
+// encryptedTokenPrefix marks a token file written by SaveTokenEncrypted, so LoadToken knows to ask for the passphrase
+const encryptedTokenPrefix = "task-cli-encrypted:v1:"
+
+// scrypt parameters for deriving the AES-256 key; slow enough to make guessing passphrases expensive
+const (
+	scryptN      = 1 << 15
+	scryptR      = 8
+	scryptP      = 1
+	tokenKeySize = 32
+	tokenSaltLen = 16
+)
+
+// ErrWrongPassphrase is returned when an encrypted token cannot be decrypted with the given passphrase
+var ErrWrongPassphrase = errors.New("wrong passphrase for the encrypted token")
+
+// isEncryptedToken reports whether the token file content was written encrypted
+func isEncryptedToken(data string) bool {
+	return strings.HasPrefix(data, encryptedTokenPrefix)
+}
+
+// encryptToken seals the token with AES-GCM under a key derived from passphrase and a random salt
+// The result is the prefix followed by base64(salt | nonce | ciphertext)
+func encryptToken(token, passphrase string) (string, error) {
+	salt := make([]byte, tokenSaltLen)
+	if _, err := rand.Read(salt); err != nil {
+		return "", fmt.Errorf("failed to generate salt: %w", err)
+	}
+	gcm, err := tokenCipher(passphrase, salt)
+	if err != nil {
+		return "", err
+	}
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", fmt.Errorf("failed to generate nonce: %w", err)
+	}
+
+	sealed := append(salt, nonce...)
+	sealed = gcm.Seal(sealed, nonce, []byte(token), nil)
+	return encryptedTokenPrefix + base64.StdEncoding.EncodeToString(sealed), nil
+}
+
+// decryptToken opens a token sealed by encryptToken
+// Returns ErrWrongPassphrase when authentication fails, which GCM cannot tell apart from a tampered file
+func decryptToken(data, passphrase string) (string, error) {
+	sealed, err := base64.StdEncoding.DecodeString(strings.TrimPrefix(data, encryptedTokenPrefix))
+	if err != nil {
+		return "", fmt.Errorf("encrypted token file is corrupt: %w", err)
+	}
+	if len(sealed) < tokenSaltLen {
+		return "", fmt.Errorf("encrypted token file is corrupt")
+	}
+	salt, rest := sealed[:tokenSaltLen], sealed[tokenSaltLen:]
+
+	gcm, err := tokenCipher(passphrase, salt)
+	if err != nil {
+		return "", err
+	}
+	if len(rest) < gcm.NonceSize() {
+		return "", fmt.Errorf("encrypted token file is corrupt")
+	}
+	nonce, ciphertext := rest[:gcm.NonceSize()], rest[gcm.NonceSize():]
+
+	plaintext, err := gcm.Open(nil, nonce, ciphertext, nil)
+	if err != nil {
+		return "", ErrWrongPassphrase
+	}
+	return string(plaintext), nil
+}
+
+// tokenCipher derives the key from passphrase and salt and returns the AES-GCM cipher for it
+func tokenCipher(passphrase string, salt []byte) (cipher.AEAD, error) {
+	key, err := scrypt.Key([]byte(passphrase), salt, scryptN, scryptR, scryptP, tokenKeySize)
+	if err != nil {
+		return nil, fmt.Errorf("failed to derive token key: %w", err)
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create token cipher: %w", err)
+	}
+	return cipher.NewGCM(block)
+}
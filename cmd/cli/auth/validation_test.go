@@ -4,54 +4,6 @@ import (
 	"testing"
 )
 
-func TestValidateEmail(t *testing.T) {
-	testCases := []struct {
-		name        string
-		email       string
-		expectError bool
-	}{
-		{
-			name:        "Valid email",
-			email:       "user@example.com",
-			expectError: false,
-		},
-		{
-			name:        "Valid email with subdomain",
-			email:       "user@mail.example.com",
-			expectError: false,
-		},
-		{
-			name:        "Invalid email - no @",
-			email:       "userexample.com",
-			expectError: true,
-		},
-		{
-			name:        "Invalid email - empty",
-			email:       "",
-			expectError: true,
-		},
-		{
-			name:        "Invalid email - no domain",
-			email:       "user@",
-			expectError: true,
-		},
-	}
-
-	for _, tc := range testCases {
-		t.Run(tc.name, func(t *testing.T) {
-			err := validateEmail(tc.email)
-
-			if tc.expectError && err == nil {
-				t.Errorf("Expected error for email %q, but got none", tc.email)
-			}
-
-			if !tc.expectError && err != nil {
-				t.Errorf("Expected no error for email %q, but got: %v", tc.email, err)
-			}
-		})
-	}
-}
-
 func TestValidatePassword(t *testing.T) {
 	testCases := []struct {
 		name        string
@@ -60,12 +12,12 @@ func TestValidatePassword(t *testing.T) {
 	}{
 		{
 			name:        "Valid password - 8 characters",
-			password:    "12345678",
+			password:    "abcd1234",
 			expectError: false,
 		},
 		{
 			name:        "Valid password - 72 characters",
-			password:    "123456789012345678901234567890123456789012345678901234567890123456789012",
+			password:    "a23456789012345678901234567890123456789012345678901234567890123456789012",
 			expectError: false,
 		},
 		{
@@ -83,6 +35,16 @@ func TestValidatePassword(t *testing.T) {
 			password:    "1234567890123456789012345678901234567890123456789012345678901234567890123",
 			expectError: true,
 		},
+		{
+			name:        "Invalid password - no digit",
+			password:    "password",
+			expectError: true,
+		},
+		{
+			name:        "Invalid password - no letter",
+			password:    "12345678",
+			expectError: true,
+		},
 	}
 
 	for _, tc := range testCases {
@@ -2,10 +2,12 @@ package main
 
 import (
 	"bytes"
+	"context"
 	"errors"
 	"myproject/cmd/cli/client"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -21,6 +23,8 @@ type MockAuthManager struct {
 	loadTokenErr       error
 	handleAuthErrToken string
 	handleAuthErrErr   error
+	changePasswordErr  error
+	changePasswordRuns int
 }
 
 func (m *MockAuthManager) LoadToken() (string, error) {
@@ -51,52 +55,246 @@ func (m *MockAuthManager) PromptRegister() (string, error) {
 	return m.registerToken, m.registerErr
 }
 
+func (m *MockAuthManager) PromptChangePassword(ctx context.Context) error {
+	m.changePasswordRuns++
+	return m.changePasswordErr
+}
+
 func (m *MockAuthManager) HandleAuthError() (string, error) {
 	return m.handleAuthErrToken, m.handleAuthErrErr
 }
 
+// mockPageSize is the default page size MockTaskClient uses to exercise paging.
+const mockPageSize = 10
+
 // MockTaskClient is a mock implementation of TaskClient for testing
 type MockTaskClient struct {
-	token            string
-	createTaskResult *client.Task
-	createTaskErr    error
-	getTaskResult    *client.Task
-	getTaskErr       error
-	updateTaskResult *client.Task
-	updateTaskErr    error
-	deleteTaskErr    error
-	getTasksResult   []client.Task
-	getTasksErr      error
-}
-
-func (m *MockTaskClient) GetTasks() ([]client.Task, error) {
+	token               string
+	createTaskResult    *client.Task
+	createTaskErr       error
+	createTaskDone      bool
+	createTaskDueDate   *time.Time
+	createTaskPriority  int
+	createTaskDescs     []string
+	createTasksIDs      []int
+	createTasksErr      error
+	createTasksBatch    []string
+	getTaskResult       *client.Task
+	getTaskErr          error
+	getTaskResults      []*client.Task
+	updateTaskResult    *client.Task
+	updateTaskErr       error
+	updateTaskDesc      *string
+	updateTaskDone      *bool
+	updateTaskVersions  []*int
+	updateTaskErrs      []error
+	deleteTaskErr       error
+	toggleTaskID        int
+	toggleTaskResult    *client.Task
+	toggleTaskErr       error
+	markAllCalls        []bool
+	markAllResult       int
+	markAllErr          error
+	deleteTasksIDs      []int
+	deleteTasksResult   int
+	deleteTasksErr      error
+	purgeTaskID         int
+	restoreTaskID       int
+	restoreTaskResult   *client.Task
+	restoreTaskErr      error
+	archiveTaskID       int
+	archiveTaskResult   *client.Task
+	archiveTaskErr      error
+	archiveAllCalls     int
+	archiveAllResult    int
+	archiveAllErr       error
+	getTasksResult      []client.Task
+	getTasksErr         error
+	getTasksOffsets     []int
+	getTasksQuery       client.ListQuery
+	searchResult        []client.Task
+	searchErr           error
+	searchQuery         string
+	tagTaskID           int
+	tagName             string
+	tagResult           *client.Task
+	tagErr              error
+	tagFilter           string
+	tagIncludeArchived  bool
+	statsResult         *client.Stats
+	statsErr            error
+	meResult            *client.User
+	meErr               error
+	deleteAccountCalled bool
+	deleteAccountErr    error
+	limitsResult        *client.Limits
+	limitsErr           error
+	pingErrs            []error
+	pingCalls           int
+}
+
+// GetTasks pages through getTasksResult like the server, using mockPageSize when limit is 0.
+func (m *MockTaskClient) GetTasks(ctx context.Context, limit, offset int, query client.ListQuery) (*client.TaskList, error) {
+	m.getTasksOffsets = append(m.getTasksOffsets, offset)
+	m.getTasksQuery = query
+	if m.getTasksErr != nil {
+		return nil, m.getTasksErr
+	}
+	if limit == 0 {
+		limit = mockPageSize
+	}
+	end := min(offset+limit, len(m.getTasksResult))
+	start := min(offset, end)
+	return &client.TaskList{
+		Tasks:  m.getTasksResult[start:end],
+		Total:  len(m.getTasksResult),
+		Limit:  limit,
+		Offset: offset,
+	}, nil
+}
+
+func (m *MockTaskClient) SearchTasks(ctx context.Context, query string) ([]client.Task, error) {
+	m.searchQuery = query
+	return m.searchResult, m.searchErr
+}
+
+func (m *MockTaskClient) GetTasksByTag(ctx context.Context, tag string, includeArchived bool) ([]client.Task, error) {
+	m.tagFilter = tag
+	m.tagIncludeArchived = includeArchived
 	return m.getTasksResult, m.getTasksErr
 }
 
-func (m *MockTaskClient) GetTask(id int) (*client.Task, error) {
+func (m *MockTaskClient) AddTag(ctx context.Context, id int, tag string) (*client.Task, error) {
+	m.tagTaskID = id
+	m.tagName = tag
+	return m.tagResult, m.tagErr
+}
+
+func (m *MockTaskClient) RemoveTag(ctx context.Context, id int, tag string) (*client.Task, error) {
+	m.tagTaskID = id
+	m.tagName = tag
+	return m.tagResult, m.tagErr
+}
+
+func (m *MockTaskClient) Stats(ctx context.Context) (*client.Stats, error) {
+	return m.statsResult, m.statsErr
+}
+
+// GetTask returns the queued getTaskResults first, then getTaskResult and getTaskErr.
+func (m *MockTaskClient) GetTask(ctx context.Context, id int) (*client.Task, error) {
+	if len(m.getTaskResults) > 0 {
+		task := m.getTaskResults[0]
+		m.getTaskResults = m.getTaskResults[1:]
+		return task, nil
+	}
 	return m.getTaskResult, m.getTaskErr
 }
 
-func (m *MockTaskClient) CreateTask(description string) (*client.Task, error) {
+func (m *MockTaskClient) CreateTask(ctx context.Context, description string, done bool, dueDate *time.Time, priority int) (*client.Task, error) {
+	m.createTaskDone = done
+	m.createTaskDueDate = dueDate
+	m.createTaskPriority = priority
+	m.createTaskDescs = append(m.createTaskDescs, description)
 	return m.createTaskResult, m.createTaskErr
 }
 
-func (m *MockTaskClient) UpdateTask(id int, description *string, done *bool) (*client.Task, error) {
+func (m *MockTaskClient) CreateTasks(ctx context.Context, descriptions []string) ([]int, error) {
+	m.createTasksBatch = descriptions
+	return m.createTasksIDs, m.createTasksErr
+}
+
+// UpdateTask fails with the queued updateTaskErrs first, then returns updateTaskResult and updateTaskErr.
+func (m *MockTaskClient) UpdateTask(ctx context.Context, id int, description *string, done *bool, version *int) (*client.Task, error) {
+	m.updateTaskDesc = description
+	m.updateTaskDone = done
+	m.updateTaskVersions = append(m.updateTaskVersions, version)
+	if len(m.updateTaskErrs) > 0 {
+		err := m.updateTaskErrs[0]
+		m.updateTaskErrs = m.updateTaskErrs[1:]
+		return nil, err
+	}
 	return m.updateTaskResult, m.updateTaskErr
 }
 
-func (m *MockTaskClient) DeleteTask(id int) error {
+func (m *MockTaskClient) DeleteTask(ctx context.Context, id int) error {
+	return m.deleteTaskErr
+}
+
+func (m *MockTaskClient) ToggleTask(ctx context.Context, id int) (*client.Task, error) {
+	m.toggleTaskID = id
+	return m.toggleTaskResult, m.toggleTaskErr
+}
+
+func (m *MockTaskClient) UpdateAllTaskStatus(ctx context.Context, done bool) (int, error) {
+	m.markAllCalls = append(m.markAllCalls, done)
+	return m.markAllResult, m.markAllErr
+}
+
+func (m *MockTaskClient) DeleteTasks(ctx context.Context, ids []int) (int, error) {
+	m.deleteTasksIDs = ids
+	return m.deleteTasksResult, m.deleteTasksErr
+}
+
+func (m *MockTaskClient) PurgeTask(ctx context.Context, id int) error {
+	m.purgeTaskID = id
 	return m.deleteTaskErr
 }
 
-func (m *MockTaskClient) Login(email, password string) (string, error) {
+func (m *MockTaskClient) RestoreTask(ctx context.Context, id int) (*client.Task, error) {
+	m.restoreTaskID = id
+	return m.restoreTaskResult, m.restoreTaskErr
+}
+
+func (m *MockTaskClient) ArchiveTask(ctx context.Context, id int) (*client.Task, error) {
+	m.archiveTaskID = id
+	return m.archiveTaskResult, m.archiveTaskErr
+}
+
+func (m *MockTaskClient) ArchiveCompleted(ctx context.Context) (int, error) {
+	m.archiveAllCalls++
+	return m.archiveAllResult, m.archiveAllErr
+}
+
+func (m *MockTaskClient) Login(ctx context.Context, email, password string) (string, error) {
 	return "", nil
 }
 
-func (m *MockTaskClient) Register(email, password string) (string, error) {
+func (m *MockTaskClient) Register(ctx context.Context, email, password string) (string, error) {
 	return "", nil
 }
 
+func (m *MockTaskClient) Me(ctx context.Context) (*client.User, error) {
+	return m.meResult, m.meErr
+}
+
+func (m *MockTaskClient) DeleteAccount(ctx context.Context) error {
+	m.deleteAccountCalled = true
+	return m.deleteAccountErr
+}
+
+func (m *MockTaskClient) ChangePassword(ctx context.Context, currentPassword, newPassword string) error {
+	return nil
+}
+
+func (m *MockTaskClient) Limits(ctx context.Context) (*client.Limits, error) {
+	if m.limitsErr != nil {
+		return nil, m.limitsErr
+	}
+	if m.limitsResult == nil {
+		return &client.Limits{}, nil
+	}
+	return m.limitsResult, nil
+}
+
+// Ping answers with the queued pingErrs in turn and succeeds once they are used up.
+func (m *MockTaskClient) Ping(ctx context.Context) error {
+	m.pingCalls++
+	if m.pingCalls <= len(m.pingErrs) {
+		return m.pingErrs[m.pingCalls-1]
+	}
+	return nil
+}
+
 func (m *MockTaskClient) SetToken(token string) {
 	m.token = token
 }
@@ -131,6 +329,18 @@ func TestNewAuthCommands(t *testing.T) {
 			expectedCmd: CommandLogout,
 			expectedErr: nil,
 		},
+		{
+			name:        "Whoami command",
+			input:       "whoami",
+			expectedCmd: CommandWhoami,
+			expectedErr: nil,
+		},
+		{
+			name:        "Passwd command",
+			input:       "passwd",
+			expectedCmd: CommandPasswd,
+			expectedErr: nil,
+		},
 		{
 			name:        "Login command uppercase",
 			input:       "LOGIN",
@@ -313,6 +523,178 @@ func TestCLI_HandleLogoutCommand(t *testing.T) {
 	}
 }
 
+// TestCLI_HandleWhoamiCommand tests the handleWhoamiCommand method
+func TestCLI_HandleWhoamiCommand(t *testing.T) {
+	testCases := []struct {
+		name           string
+		meResult       *client.User
+		meErr          error
+		expectedOutput string
+		expectedErr    bool
+	}{
+		{
+			name: "Shows logged in account",
+			meResult: &client.User{
+				ID:        7,
+				Email:     "user@example.com",
+				CreatedAt: time.Date(2024, 3, 15, 12, 0, 0, 0, time.Local),
+			},
+			expectedOutput: "👤 Logged in as user@example.com (ID: 7, registered 2024-03-15)\n",
+			expectedErr:    false,
+		},
+		{
+			name:           "Token rejected",
+			meErr:          &client.AuthError{Message: "authentication required"},
+			expectedOutput: "",
+			expectedErr:    true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// ====Arrange====
+			output := &bytes.Buffer{}
+			mockClient := &MockTaskClient{meResult: tc.meResult, meErr: tc.meErr}
+
+			cli := NewCLI(
+				NewConsoleInputReader(strings.NewReader("")),
+				output,
+				&Config{ServerURL: "http://localhost:8080"},
+				mockClient,
+				&MockAuthManager{},
+			)
+
+			// ====Act====
+			err := cli.handleWhoamiCommand(context.Background())
+
+			// ====Assert====
+			if tc.expectedErr {
+				assert.ErrorContains(t, err, "whoami failed")
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, tc.expectedOutput, output.String())
+		})
+	}
+}
+
+// TestCLI_HandlePasswdCommand tests the handlePasswdCommand method
+func TestCLI_HandlePasswdCommand(t *testing.T) {
+	testCases := []struct {
+		name              string
+		changePasswordErr error
+		expectedOutput    string
+	}{
+		{
+			name:           "Password changed",
+			expectedOutput: "✅ Password changed\n",
+		},
+		{
+			name:              "Password change rejected",
+			changePasswordErr: errors.New("password change failed: current password is incorrect"),
+			expectedOutput:    "",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// ====Arrange====
+			output := &bytes.Buffer{}
+			authManager := &MockAuthManager{changePasswordErr: tc.changePasswordErr}
+
+			cli := NewCLI(
+				NewConsoleInputReader(strings.NewReader("")),
+				output,
+				&Config{ServerURL: "http://localhost:8080"},
+				&MockTaskClient{},
+				authManager,
+			)
+
+			// ====Act====
+			err := cli.handlePasswdCommand(context.Background())
+
+			// ====Assert====
+			assert.Equal(t, tc.changePasswordErr, err)
+			assert.Equal(t, 1, authManager.changePasswordRuns)
+			assert.Equal(t, tc.expectedOutput, output.String())
+		})
+	}
+}
+
+// TestCLI_HandleDeleteAccountCommand tests the handleDeleteAccountCommand method
+func TestCLI_HandleDeleteAccountCommand(t *testing.T) {
+	user := &client.User{ID: 7, Email: "user@example.com"}
+
+	testCases := []struct {
+		name             string
+		input            string
+		deleteAccountErr error
+		clearTokenErr    error
+		expectedDeleted  bool
+		expectedCalled   bool
+		expectedOutput   string
+		expectedErr      string
+	}{
+		{
+			name:            "Deletes account when email matches",
+			input:           "User@Example.com",
+			expectedDeleted: true,
+			expectedCalled:  true,
+			expectedOutput:  "✅ Account deleted\n👋 Bye!\n",
+		},
+		{
+			name:           "Cancels when email does not match",
+			input:          "other@example.com",
+			expectedOutput: "Email does not match, account deletion canceled\n",
+		},
+		{
+			name:             "Server failure keeps the token",
+			input:            "user@example.com",
+			deleteAccountErr: errors.New("server error"),
+			expectedCalled:   true,
+			expectedErr:      "deleting account failed",
+		},
+		{
+			name:            "Token clearing failure still reports deletion",
+			input:           "user@example.com",
+			clearTokenErr:   errors.New("permission denied"),
+			expectedDeleted: true,
+			expectedCalled:  true,
+			expectedErr:     "clearing token failed",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// ====Arrange====
+			output := &bytes.Buffer{}
+			mockClient := &MockTaskClient{meResult: user, deleteAccountErr: tc.deleteAccountErr}
+
+			cli := NewCLI(
+				NewMockInputReader(tc.input),
+				output,
+				&Config{ServerURL: "http://localhost:8080"},
+				mockClient,
+				&MockAuthManager{clearTokenErr: tc.clearTokenErr},
+			)
+
+			// ====Act====
+			deleted, err := cli.handleDeleteAccountCommand(context.Background())
+
+			// ====Assert====
+			if tc.expectedErr != "" {
+				assert.ErrorContains(t, err, tc.expectedErr)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, tc.expectedDeleted, deleted)
+			assert.Equal(t, tc.expectedCalled, mockClient.deleteAccountCalled)
+			assert.Contains(t, output.String(), "Type your email to confirm:\n")
+			assert.True(t, strings.HasSuffix(output.String(), tc.expectedOutput))
+		})
+	}
+}
+
 // TestCLI_HandleAuthError tests the 401 re-authentication handling
 func TestCLI_HandleAuthError(t *testing.T) {
 	testCases := []struct {
@@ -376,6 +758,95 @@ func TestCLI_HandleAuthError(t *testing.T) {
 	}
 }
 
+// expiringSessionClient fails CreateTask with an auth error until its expired sessions are used up.
+type expiringSessionClient struct {
+	*MockTaskClient
+	expired int
+}
+
+func (c *expiringSessionClient) CreateTask(ctx context.Context, description string, done bool, dueDate *time.Time, priority int) (*client.Task, error) {
+	if c.expired > 0 {
+		c.expired--
+		c.createTaskDescs = append(c.createTaskDescs, description)
+		return nil, &client.AuthError{Message: "token expired"}
+	}
+	return c.MockTaskClient.CreateTask(ctx, description, done, dueDate, priority)
+}
+
+func TestCLI_RunCommand_RetriesAfterReauth(t *testing.T) {
+	testCases := []struct {
+		name               string
+		expired            int
+		handleAuthErrErr   error
+		expectedCalls      []string
+		expectedContains   []string
+		expectedNotContain []string
+	}{
+		{
+			name:             "Command re-run with collected input after re-authentication",
+			expired:          1,
+			expectedCalls:    []string{"buy milk", "buy milk"},
+			expectedContains: []string{"✅ Re-authentication successful!", "🔁 Retrying 'add-done'...", "✅ Task added"},
+			expectedNotContain: []string{
+				"Add done command error",
+			},
+		},
+		{
+			name:             "Command retried at most once",
+			expired:          2,
+			expectedCalls:    []string{"buy milk", "buy milk"},
+			expectedContains: []string{"🔁 Retrying 'add-done'...", "Add done command error"},
+		},
+		{
+			name:             "Command not retried when re-authentication fails",
+			expired:          1,
+			handleAuthErrErr: errors.New("re-auth failed"),
+			expectedCalls:    []string{"buy milk"},
+			expectedContains: []string{"Re-authentication failed", "Add done command error"},
+			expectedNotContain: []string{
+				"Retrying",
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// ====Arrange====
+			output := &bytes.Buffer{}
+			mockAuth := &MockAuthManager{
+				handleAuthErrToken: "new-token",
+				handleAuthErrErr:   tc.handleAuthErrErr,
+			}
+			mockClient := &expiringSessionClient{
+				MockTaskClient: &MockTaskClient{createTaskResult: &client.Task{ID: 1, Description: "buy milk"}},
+				expired:        tc.expired,
+			}
+
+			cli := NewCLI(
+				NewMockInputReader("buy milk"),
+				output,
+				&Config{ServerURL: "http://localhost:8080"},
+				mockClient,
+				mockAuth,
+			)
+
+			// ====Act====
+			exit := cli.runCommand(CommandAddDone, nil)
+
+			// ====Assert====
+			assert.False(t, exit)
+			assert.Equal(t, tc.expectedCalls, mockClient.createTaskDescs)
+			result := output.String()
+			for _, expected := range tc.expectedContains {
+				assert.Contains(t, result, expected)
+			}
+			for _, notExpected := range tc.expectedNotContain {
+				assert.NotContains(t, result, notExpected)
+			}
+		})
+	}
+}
+
 // TestClient_IsAuthError tests the IsAuthError helper function
 func TestClient_IsAuthError(t *testing.T) {
 	testCases := []struct {
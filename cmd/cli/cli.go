@@ -2,30 +2,58 @@ package main
 
 import (
 	"bufio"
+	"context"
+	"encoding/csv"
+	"encoding/json"
 	"errors"
+	"flag"
 	"fmt"
 	"io"
+	"math"
 	"myproject/cmd/cli/auth"
 	"myproject/cmd/cli/client"
+	"myproject/cmd/cli/symbols"
+	"myproject/domain"
 	"myproject/domain/validation"
+	"net/http"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strconv"
 	"strings"
+	"time"
 )
 
 const (
-	maxCommandInputSize     = 10
-	maxTaskIDInputSize      = 10
-	maxDescriptionInputSize = 200
-	maxStatusInputSize      = 10
+	maxCommandInputSize  = 50
+	maxTaskIDInputSize   = 10
+	maxStatusInputSize   = 10
+	maxSearchInputSize   = 100
+	maxPathInputSize     = 255
+	maxDueDateInputSize  = 10
+	maxPriorityInputSize = 6
+	maxTagInputSize      = 50
+	maxEmailInputSize    = 254
 )
 
+// maxUndoHistory is how many task changes the undo command can step back through.
+const maxUndoHistory = 10
+
+// dueDateLayout is the format the CLI reads and shows due dates in.
+const dueDateLayout = "2006-01-02"
+
 var (
 	ErrMaxSizeExceeded      = errors.New("input too long")
 	ErrEmptyInput           = errors.New("empty input")
 	ErrInvalidTaskId        = errors.New("invalid ID format")
 	ErrInvalidCommand       = errors.New("invalid command")
+	ErrAmbiguousCommand     = errors.New("ambiguous command")
 	ErrInvalidStatus        = errors.New("invalid status")
 	ErrDescUnchanged        = errors.New("description unchanged")
 	ErrInvalidConfirmChoice = errors.New("invalid confirm choice")
+	ErrInvalidArguments     = errors.New("invalid arguments")
+	ErrUnsupportedFormat    = errors.New("unsupported file format")
+	ErrInvalidDueDate       = errors.New("invalid due date, expected YYYY-MM-DD")
 )
 
 // InputReader defines an interface for reading user input with size validation.
@@ -39,9 +67,26 @@ type InputReader interface {
 type CLI struct {
 	input       InputReader
 	output      io.Writer
+	errOutput   io.Writer
 	client      client.TaskClient
 	authManager auth.AuthManager
 	config      *Config
+	// sym are the status prefixes printed before messages, emoji unless Config.Plain is set
+	sym symbols.Set
+	// limits are the server's input limits, fetched by LoadLimits; zero fields fall back to the config
+	limits client.Limits
+	// operationLog holds the most recent task changes, newest last, for the undo command
+	operationLog []undoableOp
+	// oneShot is set by RunOnce, which sends prompts to the error output like JSON mode
+	oneShot bool
+}
+
+// undoableOp is a task change the undo command can reverse.
+// Before is the task as it was prior to the change; Deleted marks a soft delete, which is undone by a restore.
+type undoableOp struct {
+	Name    string
+	Before  client.Task
+	Deleted bool
 }
 
 // NewCLI creates a new CLI instance with the provided dependencies.
@@ -50,9 +95,11 @@ func NewCLI(input InputReader, output io.Writer, cfg *Config, client client.Task
 	return &CLI{
 		input:       input,
 		output:      output,
+		errOutput:   os.Stderr,
 		client:      client,
 		authManager: authManager,
 		config:      cfg,
+		sym:         symbols.For(cfg != nil && cfg.Plain),
 	}
 }
 
@@ -81,6 +128,11 @@ func (c *ConsoleInputReader) ReadInput(maxSize int) (string, error) {
 		return "", err
 	}
 
+	return validateInput(input, maxSize)
+}
+
+// validateInput trims a line read by an InputReader and checks it is neither empty nor longer than maxSize.
+func validateInput(input string, maxSize int) (string, error) {
 	input = strings.TrimSpace(input)
 	if len(input) > maxSize {
 		return "", ErrMaxSizeExceeded
@@ -93,19 +145,129 @@ func (c *ConsoleInputReader) ReadInput(maxSize int) (string, error) {
 	return input, nil
 }
 
-// formatTask formats a task for display
-func formatTask(t client.Task) string {
+// formatTask formats a task for display, with its tags after the description as "#tag".
+// Open tasks show their due date, and are marked with "[!]" once it is before now.
+// Completed and high priority tasks are marked with the symbols of sym.
+func formatTask(t client.Task, now time.Time, sym symbols.Set) string {
 	status := "[ ]"
 	if t.Done {
-		status = "[✓]"
+		status = sym.Done
+	}
+	description := t.Description
+	if t.Priority == domain.PriorityHigh {
+		description = sym.Important + " " + description
+	}
+	for _, tag := range t.Tags {
+		description += " #" + tag
+	}
+	line := fmt.Sprintf("%s %d: %s", status, t.ID, description)
+	if t.Done || t.DueDate == nil {
+		return line
+	}
+
+	due := t.DueDate.Local().Format(dueDateLayout)
+	if t.DueDate.Before(now) {
+		return fmt.Sprintf("[!] %d: %s (overdue since %s)", t.ID, description, due)
+	}
+	return fmt.Sprintf("%s (due %s)", line, due)
+}
+
+// formatTaskAge formats a task like formatTask followed by how long before now it was created.
+// Tasks without a creation time, e.g. from an older server, are shown without an age.
+func formatTaskAge(t client.Task, now time.Time, sym symbols.Set) string {
+	if t.CreatedAt.IsZero() {
+		return formatTask(t, now, sym)
+	}
+	return fmt.Sprintf("%s (%s)", formatTask(t, now, sym), formatAge(now.Sub(t.CreatedAt)))
+}
+
+// formatAge renders a duration in its largest whole unit, e.g. "5m ago" or "3d ago".
+func formatAge(d time.Duration) string {
+	switch {
+	case d < time.Minute:
+		return "just now"
+	case d < time.Hour:
+		return fmt.Sprintf("%dm ago", int(d/time.Minute))
+	case d < 24*time.Hour:
+		return fmt.Sprintf("%dh ago", int(d/time.Hour))
+	default:
+		return fmt.Sprintf("%dd ago", int(d/(24*time.Hour)))
+	}
+}
+
+// displayTask formats a task for output, adding its age when enabled in the configuration.
+func (cli *CLI) displayTask(t client.Task) string {
+	now := time.Now()
+	if cli.config != nil && cli.config.ShowAge {
+		return formatTaskAge(t, now, cli.sym)
+	}
+	return formatTask(t, now, cli.sym)
+}
+
+// LoadLimits fetches the server's input limits so prompts reject input the server would refuse.
+// When the server cannot report them, e.g. an older server or the gRPC transport, the configured
+// limits stay in effect; the error is returned for the caller to report.
+func (cli *CLI) LoadLimits(ctx context.Context) error {
+	limits, err := cli.client.Limits(ctx)
+	if err != nil {
+		return fmt.Errorf("loading server limits: %w", err)
+	}
+	cli.limits = *limits
+	return nil
+}
+
+// descriptionLimit returns the server's task description limit, falling back to the configured one
+// and then to the default.
+func (cli *CLI) descriptionLimit() int {
+	if cli.limits.MaxDescriptionLength > 0 {
+		return cli.limits.MaxDescriptionLength
+	}
+	if cli.config != nil && cli.config.MaxDescriptionLength > 0 {
+		return cli.config.MaxDescriptionLength
+	}
+	return validation.DefaultMaxDescriptionLength
+}
+
+// jsonOutput reports whether results and errors are printed as JSON (--json).
+func (cli *CLI) jsonOutput() bool {
+	return cli.config != nil && cli.config.JSON
+}
+
+// messages returns the writer for prompts and progress text.
+// In JSON and one-shot mode they go to the error output so the output carries nothing but results.
+func (cli *CLI) messages() io.Writer {
+	if cli.jsonOutput() || cli.oneShot {
+		return cli.errOutput
+	}
+	return cli.output
+}
+
+// outputResult writes v as one line of JSON and returns true in JSON mode.
+// In text mode it writes nothing and returns false, leaving the decorated text to the caller.
+func (cli *CLI) outputResult(v interface{}) bool {
+	if !cli.jsonOutput() {
+		return false
+	}
+	if err := json.NewEncoder(cli.output).Encode(v); err != nil {
+		cli.outputError(fmt.Sprintf("encoding result failed: %v", err))
 	}
-	return fmt.Sprintf("%s %d: %s", status, t.ID, t.Description)
+	return true
+}
+
+// outputError writes message as {"error": message} to the error output in JSON mode,
+// or as a ❌ line to the output otherwise.
+func (cli *CLI) outputError(message string) {
+	if !cli.jsonOutput() {
+		fmt.Fprintf(cli.output, "%s %s\n", cli.sym.Error, message)
+		return
+	}
+	json.NewEncoder(cli.errOutput).Encode(map[string]string{"error": message})
 }
 
 // promptForTaskID prompts the user for a task ID and validates the input.
 // Returns the validated task ID or an error if input is invalid or exceeds size limits.
 func (cli *CLI) promptForTaskID(prompt string) (id int, err error) {
-	fmt.Fprint(cli.output, prompt)
+	fmt.Fprint(cli.messages(), prompt)
 
 	input, err := cli.input.ReadInput(maxTaskIDInputSize)
 	if err != nil {
@@ -117,112 +279,319 @@ func (cli *CLI) promptForTaskID(prompt string) (id int, err error) {
 
 // promptForTaskWithDisplay prompts for a task ID and displays the current task details.
 // Returns the task ID, task object, and any errors from validation or task retrieval.
-func (cli *CLI) promptForTaskWithDisplay(prompt string) (id int, t *client.Task, err error) {
+func (cli *CLI) promptForTaskWithDisplay(ctx context.Context, prompt string) (id int, t *client.Task, err error) {
 	id, err = cli.promptForTaskID(prompt)
 	if err != nil {
 		return 0, nil, err
 	}
 
-	t, err = cli.client.GetTask(id)
+	t, err = cli.client.GetTask(ctx, id)
 	if err != nil {
 		return 0, nil, err
 	}
 
-	fmt.Fprintf(cli.output, "Current task: '%s'\n", formatTask(*t))
+	fmt.Fprintf(cli.messages(), "Current task: '%s'\n", cli.displayTask(*t))
 
 	return id, t, nil
 }
 
 // handleAddCommand prompts for a task description and adds a new task via the API.
 // Validates input length and description format before creating the task.
-func (cli *CLI) handleAddCommand() error {
-	fmt.Fprintln(cli.output, "Enter task description:")
+func (cli *CLI) handleAddCommand(ctx context.Context) error {
+	return cli.addTask(ctx, false)
+}
+
+// handleAddDoneCommand prompts for a task description and records it as already completed.
+func (cli *CLI) handleAddDoneCommand(ctx context.Context) error {
+	return cli.addTask(ctx, true)
+}
 
-	desc, err := cli.input.ReadInput(maxDescriptionInputSize)
+// addTask reads and validates a description, then creates a task with the given done status.
+func (cli *CLI) addTask(ctx context.Context, done bool) error {
+	fmt.Fprintln(cli.messages(), "Enter task description:")
+
+	desc, err := cli.input.ReadInput(cli.descriptionLimit())
 	if err != nil {
 		return fmt.Errorf("adding task: input failed: %w", err)
 	}
 
-	desc, err = validation.ValidateTaskDescription(desc)
+	desc, err = validation.ValidateTaskDescriptionLength(desc, cli.descriptionLimit())
 	if err != nil {
 		return fmt.Errorf("adding task: validation failed: %w", err)
 	}
 
-	task, err := cli.client.CreateTask(desc)
+	var dueDate *time.Time
+	priority := domain.PriorityNone
+	if !done {
+		dueDate, err = cli.promptForDueDate()
+		if err != nil {
+			return fmt.Errorf("adding task: %w", err)
+		}
+		priority, err = cli.promptForPriority()
+		if err != nil {
+			return fmt.Errorf("adding task: %w", err)
+		}
+	}
+
+	task, err := cli.client.CreateTask(ctx, desc, done, dueDate, priority)
 	if err != nil {
 		return fmt.Errorf("adding task: creation failed: %w", err)
 	}
 
-	fmt.Fprintf(cli.output, "✅ Task added (ID: %d)\n", task.ID)
+	if cli.outputResult(task) {
+		return nil
+	}
+	fmt.Fprintf(cli.output, "%s Task added (ID: %d)\n", cli.sym.OK, task.ID)
+	return nil
+}
+
+// promptForDueDate asks for an optional due date. Empty input means the task has no due date.
+func (cli *CLI) promptForDueDate() (*time.Time, error) {
+	fmt.Fprintln(cli.messages(), "Enter due date (YYYY-MM-DD) or leave empty:")
+
+	input, err := cli.input.ReadInput(maxDueDateInputSize)
+	if errors.Is(err, ErrEmptyInput) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("input failed: %w", err)
+	}
+
+	dueDate, err := parseDueDate(input)
+	if err != nil {
+		return nil, fmt.Errorf("validation failed: %w", err)
+	}
+	return &dueDate, nil
+}
+
+// promptForPriority asks for an optional priority. Empty input keeps the lowest priority.
+func (cli *CLI) promptForPriority() (int, error) {
+	fmt.Fprintln(cli.messages(), "Enter priority (none, low, med, high or 0-3) or leave empty:")
+
+	input, err := cli.input.ReadInput(maxPriorityInputSize)
+	if errors.Is(err, ErrEmptyInput) {
+		return domain.PriorityNone, nil
+	}
+	if err != nil {
+		return 0, fmt.Errorf("input failed: %w", err)
+	}
+
+	priority, err := validation.ParsePriority(input)
+	if err != nil {
+		return 0, fmt.Errorf("validation failed: %w", err)
+	}
+	return priority, nil
+}
+
+// parseDueDate reads a YYYY-MM-DD date as the last second of that local day,
+// so a task due today does not become overdue until the day is over.
+func parseDueDate(input string) (time.Time, error) {
+	day, err := time.ParseInLocation(dueDateLayout, input, time.Local)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("%w: %q", ErrInvalidDueDate, input)
+	}
+	return day.AddDate(0, 0, 1).Add(-time.Second), nil
+}
+
+// handleAddManyCommand reads one description per line until a blank line and creates them as one batch.
+// Every line is validated before anything is sent, so an invalid line adds no tasks.
+func (cli *CLI) handleAddManyCommand(ctx context.Context) error {
+	fmt.Fprintln(cli.messages(), "Enter task descriptions, one per line (blank line to finish):")
+
+	var descriptions []string
+	for {
+		desc, err := cli.input.ReadInput(cli.descriptionLimit())
+		if errors.Is(err, ErrEmptyInput) {
+			break
+		}
+		if err != nil {
+			return fmt.Errorf("adding tasks: input failed: %w", err)
+		}
+
+		desc, err = validation.ValidateTaskDescriptionLength(desc, cli.descriptionLimit())
+		if err != nil {
+			return fmt.Errorf("adding tasks: validation of line %d failed: %w", len(descriptions)+1, err)
+		}
+		descriptions = append(descriptions, desc)
+	}
+
+	if len(descriptions) == 0 {
+		if cli.outputResult(map[string][]int{"ids": {}}) {
+			return nil
+		}
+		fmt.Fprintln(cli.output, "No tasks entered")
+		return nil
+	}
+
+	ids, err := cli.client.CreateTasks(ctx, descriptions)
+	if err != nil {
+		return fmt.Errorf("adding tasks: creation failed: %w", err)
+	}
+
+	if cli.outputResult(map[string][]int{"ids": ids}) {
+		return nil
+	}
+	fmt.Fprintf(cli.output, "%s %d tasks added (IDs: %s)\n", cli.sym.OK, len(ids), joinIDs(ids))
 	return nil
 }
 
+// joinIDs formats task IDs as a comma-separated list.
+func joinIDs(ids []int) string {
+	parts := make([]string, len(ids))
+	for i, id := range ids {
+		parts[i] = strconv.Itoa(id)
+	}
+	return strings.Join(parts, ", ")
+}
+
 // handleStatusCommand prompts for a task ID and new status, then updates the task via API.
 // Accepts 'done' or 'undone' as valid status values with proper validation.
-func (cli *CLI) handleStatusCommand() error {
-	id, _, err := cli.promptForTaskWithDisplay("Enter task ID to change status:\n")
+func (cli *CLI) handleStatusCommand(ctx context.Context) error {
+	id, before, err := cli.promptForTaskWithDisplay(ctx, "Enter task ID to change status:\n")
 	if err != nil {
 		return fmt.Errorf("updating status: task id validation failed: %w", err)
 	}
 
-	fmt.Fprint(cli.output, "Enter new status 'done' // 'undone'\n")
+	fmt.Fprint(cli.messages(), "Enter new status 'done' // 'undone'\n")
 	str, err := cli.input.ReadInput(maxStatusInputSize)
 	if err != nil {
 		return fmt.Errorf("updating status: read status for task id %d failed: %w", id, err)
 	}
 
-	var done bool
-	switch str {
+	done, err := parseStatus(str)
+	if err != nil {
+		return fmt.Errorf("updating status for task id %d: %w", id, err)
+	}
+
+	task, err := cli.client.UpdateTask(ctx, id, nil, &done, &before.Version)
+	if err != nil {
+		return fmt.Errorf("updating status for task id %d failed: %w", id, err)
+	}
+	cli.recordUndo(undoableOp{Name: "status change", Before: *before})
+
+	if cli.outputResult(task) {
+		return nil
+	}
+	fmt.Fprintf(cli.output, "%s Task (ID: %d) status is has changed\n", cli.sym.OK, id)
+	return nil
+}
+
+// handleToggleCommand prompts for a task ID and flips its status on the server in a single request,
+// so a concurrent change from another client cannot be overwritten.
+func (cli *CLI) handleToggleCommand(ctx context.Context) error {
+	id, err := cli.promptForTaskID("Enter task ID to toggle status:\n")
+	if err != nil {
+		return fmt.Errorf("toggling status: task id validation failed: %w", err)
+	}
+
+	task, err := cli.client.ToggleTask(ctx, id)
+	if err != nil {
+		return fmt.Errorf("toggling status for task id %d failed: %w", id, err)
+	}
+	before := *task
+	before.Done = !task.Done
+	cli.recordUndo(undoableOp{Name: "status change", Before: before})
+
+	if cli.outputResult(task) {
+		return nil
+	}
+	fmt.Fprintf(cli.output, "%s Task toggled: %s\n", cli.sym.OK, cli.displayTask(*task))
+	return nil
+}
+
+// parseStatus converts the 'done' or 'undone' answer of a status prompt into the done flag.
+func parseStatus(input string) (done bool, err error) {
+	switch input {
 	case "done":
-		done = true
+		return true, nil
 	case "undone":
-		done = false
+		return false, nil
 	default:
-		return fmt.Errorf("updating status: invalid status: %q for task id %d: %w (must be 'done' or 'undone')", str, id, ErrInvalidStatus)
+		return false, fmt.Errorf("invalid status: %q: %w (must be 'done' or 'undone')", input, ErrInvalidStatus)
 	}
+}
 
-	_, err = cli.client.UpdateTask(id, nil, &done)
+// handleMarkAllCommand prompts for a status and, after confirmation, applies it to every task.
+func (cli *CLI) handleMarkAllCommand(ctx context.Context) error {
+	fmt.Fprint(cli.messages(), "Enter new status for all tasks 'done' // 'undone'\n")
+	str, err := cli.input.ReadInput(maxStatusInputSize)
 	if err != nil {
-		return fmt.Errorf("updating status for task id %d failed: %w", id, err)
+		return fmt.Errorf("marking all tasks: read status failed: %w", err)
+	}
+
+	done, err := parseStatus(str)
+	if err != nil {
+		return fmt.Errorf("marking all tasks: %w", err)
+	}
+
+	fmt.Fprintf(cli.messages(), "Mark all tasks as %s? y/N:\n", str)
+	answer, err := cli.input.ReadInput(10)
+	if err != nil && !errors.Is(err, ErrEmptyInput) {
+		return fmt.Errorf("marking all tasks: read confirmation failed: %w", err)
+	}
+	if strings.ToLower(answer) != "y" {
+		if cli.outputResult(markAllResult{Done: done}) {
+			return nil
+		}
+		fmt.Fprintln(cli.output, "Status change canceled")
+		return nil
+	}
+
+	updated, err := cli.client.UpdateAllTaskStatus(ctx, done)
+	if err != nil {
+		return fmt.Errorf("marking all tasks as %s failed: %w", str, err)
 	}
 
-	fmt.Fprintf(cli.output, "✅ Task (ID: %d) status is has changed\n", id)
+	if cli.outputResult(markAllResult{Done: done, Updated: updated}) {
+		return nil
+	}
+	fmt.Fprintf(cli.output, "%s Marked %d tasks as %s\n", cli.sym.OK, updated, str)
 	return nil
 }
 
+// markAllResult is the JSON result of the markall command; Updated is zero when the user canceled.
+type markAllResult struct {
+	Done    bool `json:"done"`
+	Updated int  `json:"updated"`
+}
+
 // handleClearCommand prompts for a task ID and clears its description via API.
 // Validates the task exists before clearing the description field.
-func (cli *CLI) handleClearCommand() error {
-	id, _, err := cli.promptForTaskWithDisplay("Enter task ID you want to clear description\n")
+func (cli *CLI) handleClearCommand(ctx context.Context) error {
+	id, before, err := cli.promptForTaskWithDisplay(ctx, "Enter task ID you want to clear description\n")
 	if err != nil {
 		return fmt.Errorf("clearing task description: task id validation failed: %w", err)
 	}
 
 	emptyDesc := ""
-	_, err = cli.client.UpdateTask(id, &emptyDesc, nil)
+	task, err := cli.client.UpdateTask(ctx, id, &emptyDesc, nil, &before.Version)
 	if err != nil {
 		return fmt.Errorf("clearing task description for task id %d failed: %w", id, err)
 	}
+	cli.recordUndo(undoableOp{Name: "clear", Before: *before})
 
-	fmt.Fprintf(cli.output, "✅ Task (ID: %d) description cleared!\n", id)
+	if cli.outputResult(task) {
+		return nil
+	}
+	fmt.Fprintf(cli.output, "%s Task (ID: %d) description cleared!\n", cli.sym.OK, id)
 	return nil
 }
 
 // handleUpdateCommand prompts for a task ID and new description, then updates the task via API.
 // Validates that the new description differs from the current one before updating.
-func (cli *CLI) handleUpdateCommand() error {
-	id, t, err := cli.promptForTaskWithDisplay("Enter task ID to update:\n")
+func (cli *CLI) handleUpdateCommand(ctx context.Context) error {
+	id, t, err := cli.promptForTaskWithDisplay(ctx, "Enter task ID to update:\n")
 	if err != nil {
 		return fmt.Errorf("updating task description: task id validation failed: %w", err)
 	}
 
-	fmt.Fprint(cli.output, "Enter new description:\n")
-	desc, err := cli.input.ReadInput(maxDescriptionInputSize)
+	fmt.Fprint(cli.messages(), "Enter new description:\n")
+	desc, err := cli.input.ReadInput(cli.descriptionLimit())
 	if err != nil {
 		return fmt.Errorf("updating task description for task id %d: read description '%s' failed: %w", id, desc, err)
 	}
 
-	desc, err = validation.ValidateTaskDescription(desc)
+	desc, err = validation.ValidateTaskDescriptionLength(desc, cli.descriptionLimit())
 	if err != nil {
 		return fmt.Errorf("updating task description for task id %d: validate description '%s' failed: %w", id, desc, err)
 	}
@@ -231,24 +600,61 @@ func (cli *CLI) handleUpdateCommand() error {
 		return fmt.Errorf("updating task description for task id %d: %w", id, ErrDescUnchanged)
 	}
 
-	_, err = cli.client.UpdateTask(id, &desc, nil)
+	updated, err := cli.client.UpdateTask(ctx, id, &desc, nil, &t.Version)
+	if client.IsVersionConflict(err) {
+		t, err = cli.refreshChangedTask(ctx, id, err)
+		if err != nil {
+			return fmt.Errorf("updating task description for task id %d failed: %w", id, err)
+		}
+		updated, err = cli.client.UpdateTask(ctx, id, &desc, nil, &t.Version)
+	}
 	if err != nil {
 		return fmt.Errorf("updating task description for task id %d failed: %w", id, err)
 	}
+	cli.recordUndo(undoableOp{Name: "update", Before: *t})
 
-	fmt.Fprintf(cli.output, "✅ Task (ID: %d) updated\n", id)
+	if cli.outputResult(updated) {
+		return nil
+	}
+	fmt.Fprintf(cli.output, "%s Task (ID: %d) updated\n", cli.sym.OK, id)
 	return nil
 }
 
+// refreshChangedTask asks whether to reload a task whose update was rejected because someone else changed it.
+// On 'y' it fetches and shows the latest version, anything else returns the conflict error unchanged.
+func (cli *CLI) refreshChangedTask(ctx context.Context, id int, conflict error) (*client.Task, error) {
+	fmt.Fprintf(cli.messages(), "%s Task (ID: %d) was changed by someone else. Refresh it and apply your change again? y/N:\n", cli.sym.Warning, id)
+	str, err := cli.input.ReadInput(10)
+	if err != nil {
+		return nil, fmt.Errorf("read refresh confirmation failed: %w", err)
+	}
+	if strings.ToLower(str) != "y" {
+		return nil, conflict
+	}
+
+	t, err := cli.client.GetTask(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	fmt.Fprintf(cli.messages(), "Current task: '%s'\n", cli.displayTask(*t))
+	return t, nil
+}
+
 // handleDeleteCommand prompts for a task ID and confirmation, then deletes the task via API.
 // Requires explicit 'y' confirmation to proceed with deletion, 'n' cancels the operation.
-func (cli *CLI) handleDeleteCommand() error {
-	id, _, err := cli.promptForTaskWithDisplay("Enter task ID to delete task:\n")
+// Deleted tasks can be restored unless --permanent is given.
+func (cli *CLI) handleDeleteCommand(ctx context.Context, args []string) error {
+	permanent, err := parseDeleteArgs(args)
+	if err != nil {
+		return fmt.Errorf("deleting task: %w", err)
+	}
+
+	id, before, err := cli.promptForTaskWithDisplay(ctx, "Enter task ID to delete task:\n")
 	if err != nil {
 		return fmt.Errorf("deleting task: id validation failed: %w", err)
 	}
 
-	fmt.Fprintln(cli.output, "Enter y/N:")
+	fmt.Fprintln(cli.messages(), "Enter y/N:")
 	str, err := cli.input.ReadInput(10)
 	if err != nil {
 		return fmt.Errorf("deleting task id %d: read confirmation failed: %w", id, err)
@@ -257,12 +663,29 @@ func (cli *CLI) handleDeleteCommand() error {
 
 	switch str {
 	case "y":
-		if err = cli.client.DeleteTask(id); err != nil {
+		if permanent {
+			if err = cli.client.PurgeTask(ctx, id); err != nil {
+				return fmt.Errorf("permanently deleting task id %d failed: %w", id, err)
+			}
+			if cli.outputResult(deleteResult{ID: id, Deleted: true, Permanent: true}) {
+				return nil
+			}
+			fmt.Fprintf(cli.output, "%s Task (ID: %d) permanently deleted\n", cli.sym.OK, id)
+			return nil
+		}
+		if err = cli.client.DeleteTask(ctx, id); err != nil {
 			return fmt.Errorf("deleting task id %d failed: %w", id, err)
 		}
-		fmt.Fprintf(cli.output, "✅ Task (ID: %d) deleted\n", id)
+		cli.recordUndo(undoableOp{Name: "delete", Before: *before, Deleted: true})
+		if cli.outputResult(deleteResult{ID: id, Deleted: true}) {
+			return nil
+		}
+		fmt.Fprintf(cli.output, "%s Task (ID: %d) deleted, use 'restore' to undo\n", cli.sym.OK, id)
 		return nil
 	case "n":
+		if cli.outputResult(deleteResult{ID: id}) {
+			return nil
+		}
 		fmt.Fprintln(cli.output, "Deletion canceled")
 		return nil
 	default:
@@ -270,230 +693,1337 @@ func (cli *CLI) handleDeleteCommand() error {
 	}
 }
 
-// showHelp displays the list of available commands and their descriptions.
-// Outputs a formatted help menu to the configured output writer.
-func (cli *CLI) showHelp() {
-	fmt.Fprintln(cli.output, "\n=== Available Commands ===")
-	fmt.Fprintln(cli.output, "add      - Add a new task")
-	fmt.Fprintln(cli.output, "status   - Change task status")
-	fmt.Fprintln(cli.output, "list     - Show all tasks")
-	fmt.Fprintln(cli.output, "process  - Process all tasks in parallel")
-	fmt.Fprintln(cli.output, "clear    - Clear task description")
-	fmt.Fprintln(cli.output, "update   - Update task description")
-	fmt.Fprintln(cli.output, "delete   - Delete task")
-	fmt.Fprintln(cli.output, "login    - Login with existing account")
-	fmt.Fprintln(cli.output, "register - Register new account")
-	fmt.Fprintln(cli.output, "logout   - Logout and clear token")
-	fmt.Fprintln(cli.output, "help     - Show this help")
-	fmt.Fprintln(cli.output, "exit     - Save and exit")
-	fmt.Fprintln(cli.output, "==========================")
+// deleteResult is the JSON result of the delete command; Deleted is false when the user canceled.
+type deleteResult struct {
+	ID        int  `json:"id"`
+	Deleted   bool `json:"deleted"`
+	Permanent bool `json:"permanent"`
 }
 
-// handleError formats and displays error messages with context information.
-// Provides user-friendly error messages and handles EOF as input interruption.
-// Handles NetworkError and APIError with specific formatting for better user experience.
-func (cli *CLI) handleError(err error, context string) {
-	if errors.Is(err, io.EOF) {
-		fmt.Fprintf(cli.output, "%s: input interrupted by user\n", context)
-		return
+// handleDeleteDoneCommand lists the completed tasks and, after confirmation, deletes them in one batch.
+// Deleted tasks can still be brought back one by one with 'restore'.
+func (cli *CLI) handleDeleteDoneCommand(ctx context.Context) error {
+	tasks, err := cli.fetchAllTasks(ctx, client.ListQuery{})
+	if err != nil {
+		return fmt.Errorf("deleting completed tasks: failed to retrieve tasks: %w", err)
 	}
 
-	// Handle NetworkError - connection failures
-	var netErr *client.NetworkError
-	if errors.As(err, &netErr) {
-		fmt.Fprintf(cli.output, "❌ %s: Cannot connect to server at %s\n", context, netErr.URL)
-		fmt.Fprintln(cli.output, "   Please check that the server is running and the URL is correct")
-		return
+	var ids []int
+	for _, task := range tasks {
+		if task.Done {
+			ids = append(ids, task.ID)
+		}
 	}
-
-	// Handle APIError - server error responses
-	var apiErr *client.APIError
-	if errors.As(err, &apiErr) {
-		fmt.Fprintf(cli.output, "❌ %s: %s\n", context, apiErr.Message)
-		return
+	if len(ids) == 0 {
+		if cli.outputResult(deleteDoneResult{}) {
+			return nil
+		}
+		fmt.Fprintln(cli.output, "No completed tasks to delete")
+		return nil
 	}
 
-	// Handle all other errors with generic format
-	fmt.Fprintf(cli.output, "%s: %v\n", context, err)
-}
-
-// handleAuthError detects authentication errors and triggers re-authentication flow
-// Returns true if re-authentication was successful, false otherwise
-func (cli *CLI) handleAuthError(err error) bool {
-	if !client.IsAuthError(err) {
-		return false
+	w := cli.messages()
+	fmt.Fprintln(w, "\n=== Completed Tasks ===")
+	for _, task := range tasks {
+		if task.Done {
+			fmt.Fprintln(w, cli.displayTask(task))
+		}
 	}
+	fmt.Fprintln(w, "=======================")
+	fmt.Fprintf(w, "Delete %d completed tasks? y/N:\n", len(ids))
 
-	// Trigger re-authentication
-	token, authErr := cli.authManager.HandleAuthError()
-	if authErr != nil {
-		fmt.Fprintf(cli.output, "❌ Re-authentication failed: %v\n", authErr)
-		return false
+	answer, err := cli.input.ReadInput(10)
+	if err != nil && !errors.Is(err, ErrEmptyInput) {
+		return fmt.Errorf("deleting completed tasks: read confirmation failed: %w", err)
+	}
+	if strings.ToLower(answer) != "y" {
+		if cli.outputResult(deleteDoneResult{}) {
+			return nil
+		}
+		fmt.Fprintln(cli.output, "Deletion canceled")
+		return nil
 	}
 
-	// Update client with new token
-	cli.client.SetToken(token)
-	fmt.Fprintln(cli.output, "✅ Re-authentication successful! Please try your command again.")
-	return true
-}
-
-// handleListCommand retrieves and displays all tasks from the API
-func (cli *CLI) handleListCommand() error {
-	tasks, err := cli.client.GetTasks()
+	deleted, err := cli.client.DeleteTasks(ctx, ids)
 	if err != nil {
-		return fmt.Errorf("failed to retrieve tasks: %w", err)
+		return fmt.Errorf("deleting %d completed tasks failed: %w", len(ids), err)
 	}
 
-	if len(tasks) == 0 {
-		fmt.Fprintln(cli.output, "No tasks found")
+	if cli.outputResult(deleteDoneResult{Deleted: deleted, Skipped: len(ids) - deleted}) {
 		return nil
 	}
-
-	fmt.Fprintln(cli.output, "\n=== Your Tasks ===")
-	for _, task := range tasks {
-		fmt.Fprintln(cli.output, formatTask(task))
+	fmt.Fprintf(cli.output, "%s Deleted %d completed tasks\n", cli.sym.OK, deleted)
+	if skipped := len(ids) - deleted; skipped > 0 {
+		fmt.Fprintf(cli.output, "   %d tasks were already gone and were skipped\n", skipped)
 	}
-	fmt.Fprintln(cli.output, "==================")
-
 	return nil
 }
 
-// handleLoginCommand prompts for credentials and authenticates the user
-func (cli *CLI) handleLoginCommand() error {
-	token, err := cli.authManager.PromptLogin()
+// deleteDoneResult is the JSON result of the deletedone command; both counts are zero when nothing was deleted.
+type deleteDoneResult struct {
+	Deleted int `json:"deleted"`
+	Skipped int `json:"skipped"`
+}
+
+// handleRestoreCommand prompts for the ID of a deleted task and restores it via API.
+func (cli *CLI) handleRestoreCommand(ctx context.Context) error {
+	id, err := cli.promptForTaskID("Enter task ID to restore:\n")
 	if err != nil {
-		return fmt.Errorf("login failed: %w", err)
+		return fmt.Errorf("restoring task: task id validation failed: %w", err)
 	}
 
-	// Update client with new token
-	cli.client.SetToken(token)
+	task, err := cli.client.RestoreTask(ctx, id)
+	if err != nil {
+		return fmt.Errorf("restoring task id %d failed: %w", id, err)
+	}
 
+	if cli.outputResult(task) {
+		return nil
+	}
+	fmt.Fprintf(cli.output, "%s Task restored: %s\n", cli.sym.OK, cli.displayTask(*task))
 	return nil
 }
 
-// handleRegisterCommand prompts for credentials and registers a new user
-func (cli *CLI) handleRegisterCommand() error {
-	token, err := cli.authManager.PromptRegister()
-	if err != nil {
-		return fmt.Errorf("registration failed: %w", err)
+// parseArchiveArgs reads the flags accepted by the archive command, e.g. "--completed".
+func parseArchiveArgs(args []string) (completed bool, err error) {
+	fs := flag.NewFlagSet(string(CommandArchive), flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	fs.BoolVar(&completed, "completed", false, "archive every completed task instead of prompting for one")
+	if err := fs.Parse(args); err != nil {
+		return false, fmt.Errorf("%w: %v", ErrInvalidArguments, err)
 	}
-
-	// Update client with new token
-	cli.client.SetToken(token)
-
-	return nil
+	if fs.NArg() > 0 {
+		return false, fmt.Errorf("%w: unexpected %q", ErrInvalidArguments, fs.Arg(0))
+	}
+	return completed, nil
 }
 
-// handleLogoutCommand clears the stored authentication token
-func (cli *CLI) handleLogoutCommand() error {
-	err := cli.authManager.ClearToken()
+// handleArchiveCommand prompts for a task ID and archives the task, or archives every completed task
+// with --completed. Archived tasks are left out of 'list' unless it is given --include-archived.
+func (cli *CLI) handleArchiveCommand(ctx context.Context, args []string) error {
+	completed, err := parseArchiveArgs(args)
 	if err != nil {
-		return fmt.Errorf("logout failed: %w", err)
+		return fmt.Errorf("archiving tasks: %w", err)
 	}
 
-	fmt.Fprintln(cli.output, "✅ Logged out successfully")
-	fmt.Fprintln(cli.output, "👋 Bye!")
-	return nil
-}
+	if completed {
+		archived, err := cli.client.ArchiveCompleted(ctx)
+		if err != nil {
+			return fmt.Errorf("archiving completed tasks failed: %w", err)
+		}
+		if cli.outputResult(archiveResult{Archived: archived}) {
+			return nil
+		}
+		fmt.Fprintf(cli.output, "%s Archived %d completed tasks\n", cli.sym.OK, archived)
+		return nil
+	}
+
+	id, err := cli.promptForTaskID("Enter task ID to archive:\n")
+	if err != nil {
+		return fmt.Errorf("archiving task: task id validation failed: %w", err)
+	}
+
+	task, err := cli.client.ArchiveTask(ctx, id)
+	if err != nil {
+		return fmt.Errorf("archiving task id %d failed: %w", id, err)
+	}
+
+	if cli.outputResult(task) {
+		return nil
+	}
+	fmt.Fprintf(cli.output, "%s Task archived: %s\n", cli.sym.OK, cli.displayTask(*task))
+	return nil
+}
+
+// archiveResult is the JSON result of 'archive --completed'.
+type archiveResult struct {
+	Archived int `json:"archived"`
+}
+
+// handleShowCommand prompts for a task ID and prints every field of that task.
+// A missing task is reported as a message rather than a command error.
+func (cli *CLI) handleShowCommand(ctx context.Context) error {
+	id, err := cli.promptForTaskID("Enter task ID to show:\n")
+	if err != nil {
+		return fmt.Errorf("showing task: task id validation failed: %w", err)
+	}
+
+	task, err := cli.client.GetTask(ctx, id)
+	var apiErr *client.APIError
+	if errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusNotFound {
+		cli.outputError(fmt.Sprintf("Task %d not found", id))
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("showing task id %d failed: %w", id, err)
+	}
+
+	if cli.outputResult(task) {
+		return nil
+	}
+	fmt.Fprintln(cli.output, formatTaskDetails(*task, time.Now(), cli.sym))
+	return nil
+}
+
+// priorityNames are the names promptForPriority accepts, indexed by priority level.
+var priorityNames = []string{"none", "low", "med", "high"}
+
+// formatTaskDetails formats a task as its formatTask line followed by one line per field,
+// with the full description and times in local time.
+func formatTaskDetails(t client.Task, now time.Time, sym symbols.Set) string {
+	var b strings.Builder
+	fmt.Fprintln(&b, formatTask(t, now, sym))
+	fmt.Fprintf(&b, "  Description: %s\n", t.Description)
+
+	done := "no"
+	if t.Done {
+		done = "yes"
+		if t.CompletedAt != nil {
+			done += ", completed " + t.CompletedAt.Local().Format(time.DateTime)
+		}
+	}
+	fmt.Fprintf(&b, "  Done:        %s\n", done)
+
+	priority := strconv.Itoa(t.Priority)
+	if t.Priority >= 0 && t.Priority < len(priorityNames) {
+		priority = priorityNames[t.Priority]
+	}
+	fmt.Fprintf(&b, "  Priority:    %s\n", priority)
+	if t.DueDate != nil {
+		fmt.Fprintf(&b, "  Due:         %s\n", t.DueDate.Local().Format(dueDateLayout))
+	}
+	if len(t.Tags) > 0 {
+		fmt.Fprintf(&b, "  Tags:        %s\n", strings.Join(t.Tags, ", "))
+	}
+	if !t.CreatedAt.IsZero() {
+		fmt.Fprintf(&b, "  Created:     %s\n", t.CreatedAt.Local().Format(time.DateTime))
+	}
+	if !t.UpdatedAt.IsZero() {
+		fmt.Fprintf(&b, "  Updated:     %s\n", t.UpdatedAt.Local().Format(time.DateTime))
+	}
+	if t.ArchivedAt != nil {
+		fmt.Fprintf(&b, "  Archived:    %s\n", t.ArchivedAt.Local().Format(time.DateTime))
+	}
+	fmt.Fprintf(&b, "  Version:     %d", t.Version)
+	return b.String()
+}
+
+// handleTagCommand prompts for a task ID and a tag, and adds the tag to the task via API.
+func (cli *CLI) handleTagCommand(ctx context.Context) error {
+	id, tag, err := cli.promptForTag("Enter task ID to tag:\n")
+	if err != nil {
+		return fmt.Errorf("tagging task: %w", err)
+	}
+
+	task, err := cli.client.AddTag(ctx, id, tag)
+	if err != nil {
+		return fmt.Errorf("tagging task id %d failed: %w", id, err)
+	}
+
+	if cli.outputResult(task) {
+		return nil
+	}
+	fmt.Fprintf(cli.output, "%s Task tagged: %s\n", cli.sym.OK, cli.displayTask(*task))
+	return nil
+}
+
+// handleUntagCommand prompts for a task ID and a tag, and removes the tag from the task via API.
+func (cli *CLI) handleUntagCommand(ctx context.Context) error {
+	id, tag, err := cli.promptForTag("Enter task ID to untag:\n")
+	if err != nil {
+		return fmt.Errorf("untagging task: %w", err)
+	}
+
+	task, err := cli.client.RemoveTag(ctx, id, tag)
+	if err != nil {
+		return fmt.Errorf("untagging task id %d failed: %w", id, err)
+	}
+
+	if cli.outputResult(task) {
+		return nil
+	}
+	fmt.Fprintf(cli.output, "%s Tag removed: %s\n", cli.sym.OK, cli.displayTask(*task))
+	return nil
+}
+
+// promptForTag asks for a task ID and then a tag, returning the tag normalized the way the server stores it.
+func (cli *CLI) promptForTag(prompt string) (id int, tag string, err error) {
+	id, err = cli.promptForTaskID(prompt)
+	if err != nil {
+		return 0, "", fmt.Errorf("task id validation failed: %w", err)
+	}
+
+	fmt.Fprintln(cli.messages(), "Enter tag:")
+	input, err := cli.input.ReadInput(maxTagInputSize)
+	if err != nil {
+		return 0, "", fmt.Errorf("input failed: %w", err)
+	}
+
+	tag, err = validation.NormalizeTag(input)
+	if err != nil {
+		return 0, "", fmt.Errorf("validation failed: %w", err)
+	}
+	return id, tag, nil
+}
+
+// recordUndo remembers a task change for the undo command, dropping the oldest beyond maxUndoHistory.
+func (cli *CLI) recordUndo(op undoableOp) {
+	cli.operationLog = append(cli.operationLog, op)
+	if len(cli.operationLog) > maxUndoHistory {
+		cli.operationLog = cli.operationLog[len(cli.operationLog)-maxUndoHistory:]
+	}
+}
+
+// handleUndoCommand reverses the most recent task change: a delete is restored,
+// a status change, update or clear puts the previous description and status back.
+// A failed undo stays in the history so it can be retried.
+func (cli *CLI) handleUndoCommand(ctx context.Context) error {
+	if len(cli.operationLog) == 0 {
+		if cli.jsonOutput() {
+			cli.outputError("Nothing to undo")
+		} else {
+			fmt.Fprintln(cli.output, "Nothing to undo")
+		}
+		return nil
+	}
+
+	op := cli.operationLog[len(cli.operationLog)-1]
+	id := op.Before.ID
+
+	var task *client.Task
+	var err error
+	if op.Deleted {
+		task, err = cli.client.RestoreTask(ctx, id)
+	} else {
+		task, err = cli.client.UpdateTask(ctx, id, &op.Before.Description, &op.Before.Done, nil)
+	}
+	if err != nil {
+		return fmt.Errorf("undoing %s of task id %d failed: %w", op.Name, id, err)
+	}
+	cli.operationLog = cli.operationLog[:len(cli.operationLog)-1]
+
+	if cli.outputResult(task) {
+		return nil
+	}
+	fmt.Fprintf(cli.output, "%s Undid %s: %s\n", cli.sym.Undo, op.Name, cli.displayTask(*task))
+	return nil
+}
+
+// showHelp displays the list of available commands and their descriptions.
+// Outputs a formatted help menu to the configured output writer.
+func (cli *CLI) showHelp() {
+	w := cli.messages()
+	fmt.Fprintln(w, "\n=== Available Commands ===")
+	fmt.Fprintln(w, "add      - Add a new task")
+	fmt.Fprintln(w, "add-done - Add an already completed task")
+	fmt.Fprintln(w, "addmany  - Add several tasks, one per line")
+	fmt.Fprintln(w, "status   - Change task status")
+	fmt.Fprintln(w, "toggle   - Flip task status between done and undone")
+	fmt.Fprintln(w, "markall  - Mark all tasks done or undone")
+	fmt.Fprintln(w, "show     - Show all details of one task")
+	fmt.Fprintln(w, "list     - Show all tasks (list --sort -created; id, created, updated, done, priority, due; list --tag work; list --created-after 2025-01-01T00:00:00Z; list --include-archived)")
+	fmt.Fprintln(w, "refresh  - Reload the task list from the server")
+	fmt.Fprintln(w, "search   - Find tasks by keyword")
+	fmt.Fprintln(w, "stats    - Show how many tasks are done and pending")
+	fmt.Fprintln(w, "count    - Print the number of tasks (count --done for completed ones)")
+	fmt.Fprintln(w, "export   - Save all tasks to a .json or .csv file")
+	fmt.Fprintln(w, "import   - Add tasks from a .json or .csv file")
+	fmt.Fprintln(w, "process  - Process all tasks in parallel")
+	fmt.Fprintln(w, "clear    - Clear task description")
+	fmt.Fprintln(w, "update   - Update task description")
+	fmt.Fprintln(w, "delete   - Delete task (delete --permanent cannot be restored)")
+	fmt.Fprintln(w, "deletedone - Delete all completed tasks")
+	fmt.Fprintln(w, "restore  - Restore a deleted task")
+	fmt.Fprintln(w, "archive  - Hide a task from the list (archive --completed for all completed ones)")
+	fmt.Fprintln(w, "tag      - Add a tag to a task")
+	fmt.Fprintln(w, "untag    - Remove a tag from a task")
+	fmt.Fprintln(w, "undo     - Revert the last status change, update, clear or delete")
+	fmt.Fprintln(w, "login    - Login with existing account")
+	fmt.Fprintln(w, "register - Register new account")
+	fmt.Fprintln(w, "logout   - Logout and clear token")
+	fmt.Fprintln(w, "whoami   - Show the logged in account")
+	fmt.Fprintln(w, "deleteaccount - Delete your account and all its tasks")
+	fmt.Fprintln(w, "passwd   - Change your password")
+	fmt.Fprintln(w, "help     - Show this help")
+	fmt.Fprintln(w, "exit     - Save and exit")
+	fmt.Fprintln(w, "==========================")
+}
+
+// handleError formats and displays error messages with context information.
+// Provides user-friendly error messages and handles EOF as input interruption.
+// Handles NetworkError and APIError with specific formatting for better user experience.
+func (cli *CLI) handleError(err error, context string) {
+	requestID := requestIDOf(err)
+
+	if cli.jsonOutput() {
+		result := map[string]string{"error": fmt.Sprintf("%s: %s", context, errorMessage(err))}
+		if requestID != "" {
+			result["request_id"] = requestID
+		}
+		json.NewEncoder(cli.errOutput).Encode(result)
+		return
+	}
+
+	if errors.Is(err, io.EOF) {
+		fmt.Fprintf(cli.output, "%s: input interrupted by user\n", context)
+		return
+	}
+
+	if isCanceled(err) {
+		fmt.Fprintf(cli.output, "%s: request cancelled\n", context)
+		return
+	}
+
+	// Handle NetworkError - connection failures
+	var netErr *client.NetworkError
+	if errors.As(err, &netErr) {
+		fmt.Fprintf(cli.output, "%s %s: Cannot connect to server at %s\n", cli.sym.Error, context, netErr.URL)
+		fmt.Fprintln(cli.output, "   Please check that the server is running and the URL is correct")
+		if requestID != "" {
+			fmt.Fprintf(cli.output, "   Request ID: %s\n", requestID)
+		}
+		return
+	}
+
+	// Handle RateLimitError - the server asked us to slow down
+	var rateErr *client.RateLimitError
+	if errors.As(err, &rateErr) {
+		fmt.Fprintf(cli.output, "%s %s: %s\n", cli.sym.Wait, context, rateErr.Error())
+		if requestID != "" {
+			fmt.Fprintf(cli.output, "   Request ID: %s\n", requestID)
+		}
+		return
+	}
+
+	// Handle APIError - server error responses
+	var apiErr *client.APIError
+	if errors.As(err, &apiErr) {
+		fmt.Fprintf(cli.output, "%s %s: %s\n", cli.sym.Error, context, apiErr.Message)
+		if requestID != "" {
+			fmt.Fprintf(cli.output, "   Request ID: %s\n", requestID)
+		}
+		return
+	}
+
+	// Handle all other errors with generic format
+	fmt.Fprintf(cli.output, "%s: %v\n", context, err)
+}
+
+// errorMessage describes err without decoration, for JSON error output.
+func errorMessage(err error) string {
+	if errors.Is(err, io.EOF) {
+		return "input interrupted by user"
+	}
+	if isCanceled(err) {
+		return "request cancelled"
+	}
+	var netErr *client.NetworkError
+	if errors.As(err, &netErr) {
+		return fmt.Sprintf("cannot connect to server at %s", netErr.URL)
+	}
+	var rateErr *client.RateLimitError
+	if errors.As(err, &rateErr) {
+		return rateErr.Error()
+	}
+	var apiErr *client.APIError
+	if errors.As(err, &apiErr) {
+		return apiErr.Message
+	}
+	return err.Error()
+}
+
+// requestIDOf returns the ID of the failed request behind err, for matching it with the server logs.
+// Returns "" when err did not come from a request.
+func requestIDOf(err error) string {
+	var netErr *client.NetworkError
+	if errors.As(err, &netErr) {
+		return netErr.RequestID
+	}
+	var apiErr *client.APIError
+	if errors.As(err, &apiErr) {
+		return apiErr.RequestID
+	}
+	var authErr *client.AuthError
+	if errors.As(err, &authErr) {
+		return authErr.RequestID
+	}
+	var rateErr *client.RateLimitError
+	if errors.As(err, &rateErr) {
+		return rateErr.RequestID
+	}
+	return ""
+}
+
+// isCanceled reports whether err was caused by the command's context ending, e.g. on Ctrl-C or a deadline.
+func isCanceled(err error) bool {
+	return errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded)
+}
+
+// handleAuthError detects authentication errors and triggers re-authentication flow
+// Returns true if re-authentication was successful, false otherwise
+func (cli *CLI) handleAuthError(err error) bool {
+	if !client.IsAuthError(err) {
+		return false
+	}
+
+	// Trigger re-authentication
+	token, authErr := cli.authManager.HandleAuthError()
+	if authErr != nil {
+		cli.outputError(fmt.Sprintf("Re-authentication failed: %v", authErr))
+		return false
+	}
+
+	// Update client with new token
+	cli.client.SetToken(token)
+	fmt.Fprintf(cli.messages(), "%s Re-authentication successful!\n", cli.sym.OK)
+	return true
+}
+
+// splitCommandLine separates the command word from the arguments typed after it.
+func splitCommandLine(input string) (name string, args []string) {
+	fields := strings.Fields(input)
+	if len(fields) == 0 {
+		return "", nil
+	}
+	return fields[0], fields[1:]
+}
+
+// parseListArgs reads the flags accepted by the list command, e.g. "--sort -created", "--tag work",
+// "--created-after 2025-01-01T00:00:00Z" or "--include-archived". Tasks listed by tag come in a fixed order
+// and are not filtered by creation time, so --tag can only be combined with --include-archived.
+func parseListArgs(args []string) (query client.ListQuery, tag string, err error) {
+	var createdAfter, createdBefore string
+	fs := flag.NewFlagSet(string(CommandList), flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	fs.StringVar(&query.Sort, "sort", "", "order by id, created, updated, done, priority or due; prefix with - for descending")
+	fs.StringVar(&tag, "tag", "", "show only the tasks with this tag")
+	fs.StringVar(&createdAfter, "created-after", "", "show only the tasks created at or after this RFC 3339 time")
+	fs.StringVar(&createdBefore, "created-before", "", "show only the tasks created at or before this RFC 3339 time")
+	fs.BoolVar(&query.IncludeArchived, "include-archived", false, "show archived tasks too")
+	if err := fs.Parse(args); err != nil {
+		return client.ListQuery{}, "", fmt.Errorf("%w: %v", ErrInvalidArguments, err)
+	}
+	if fs.NArg() > 0 {
+		return client.ListQuery{}, "", fmt.Errorf("%w: unexpected %q", ErrInvalidArguments, fs.Arg(0))
+	}
+	if _, err := validation.ValidateTaskSort(query.Sort); err != nil {
+		return client.ListQuery{}, "", err
+	}
+	filter, err := validation.ValidateCreatedRange(createdAfter, createdBefore)
+	if err != nil {
+		return client.ListQuery{}, "", err
+	}
+	query.CreatedAfter, query.CreatedBefore = filter.CreatedAfter, filter.CreatedBefore
+
+	tagSet := false
+	fs.Visit(func(f *flag.Flag) { tagSet = tagSet || f.Name == "tag" })
+	if !tagSet {
+		return query, "", nil
+	}
+	if query != (client.ListQuery{IncludeArchived: query.IncludeArchived}) {
+		return client.ListQuery{}, "", fmt.Errorf("%w: --tag cannot be combined with --sort or --created-after/--created-before", ErrInvalidArguments)
+	}
+	tag, err = validation.NormalizeTag(tag)
+	if err != nil {
+		return client.ListQuery{}, "", err
+	}
+	return query, tag, nil
+}
+
+// parseDeleteArgs reads the flags accepted by the delete command, e.g. "--permanent".
+func parseDeleteArgs(args []string) (permanent bool, err error) {
+	fs := flag.NewFlagSet(string(CommandDelete), flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	fs.BoolVar(&permanent, "permanent", false, "delete the task for good instead of allowing it to be restored")
+	if err := fs.Parse(args); err != nil {
+		return false, fmt.Errorf("%w: %v", ErrInvalidArguments, err)
+	}
+	if fs.NArg() > 0 {
+		return false, fmt.Errorf("%w: unexpected %q", ErrInvalidArguments, fs.Arg(0))
+	}
+	return permanent, nil
+}
+
+// handleListCommand retrieves and displays tasks from the API one page at a time.
+// When more tasks remain after a page, the user is asked whether to show the next one.
+func (cli *CLI) handleListCommand(ctx context.Context, args []string) error {
+	query, tag, err := parseListArgs(args)
+	if err != nil {
+		return fmt.Errorf("listing tasks: %w", err)
+	}
+	if tag != "" {
+		return cli.listTasksByTag(ctx, tag, query.IncludeArchived)
+	}
+
+	if cli.jsonOutput() {
+		tasks, err := cli.fetchAllTasks(ctx, query)
+		if err != nil {
+			return fmt.Errorf("failed to retrieve tasks: %w", err)
+		}
+		cli.outputResult(tasks)
+		return nil
+	}
+
+	offset := 0
+	for {
+		list, err := cli.client.GetTasks(ctx, 0, offset, query)
+		if err != nil {
+			return fmt.Errorf("failed to retrieve tasks: %w", err)
+		}
+
+		if len(list.Tasks) == 0 {
+			if offset == 0 {
+				fmt.Fprintln(cli.output, "No tasks found")
+			}
+			return nil
+		}
+
+		cli.renderTasks(list.Tasks)
+
+		shown := offset + len(list.Tasks)
+		fmt.Fprintf(cli.output, "Showing %d-%d of %d\n", offset+1, shown, list.Total)
+		if shown >= list.Total {
+			return nil
+		}
+
+		fmt.Fprintln(cli.output, "Show next page? y/N:")
+		answer, err := cli.input.ReadInput(10)
+		if err != nil && !errors.Is(err, ErrEmptyInput) {
+			return fmt.Errorf("listing tasks: read confirmation failed: %w", err)
+		}
+		if strings.ToLower(answer) != "y" {
+			return nil
+		}
+		offset = shown
+	}
+}
+
+// renderTasks prints tasks as the framed list shown by list and refresh.
+func (cli *CLI) renderTasks(tasks []client.Task) {
+	fmt.Fprintln(cli.output, "\n=== Your Tasks ===")
+	for _, task := range tasks {
+		fmt.Fprintln(cli.output, cli.displayTask(task))
+	}
+	fmt.Fprintln(cli.output, "==================")
+}
+
+// showUpdatedTasks prints the first page of tasks after a command changed them, for Config.AutoList.
+// Unlike list it does not ask for further pages, so the next command can be typed right away.
+func (cli *CLI) showUpdatedTasks(ctx context.Context) error {
+	list, err := cli.client.GetTasks(ctx, 0, 0, client.ListQuery{})
+	if err != nil {
+		return fmt.Errorf("failed to retrieve tasks: %w", err)
+	}
+	if len(list.Tasks) == 0 {
+		fmt.Fprintln(cli.output, "No tasks found")
+		return nil
+	}
+
+	cli.renderTasks(list.Tasks)
+	if len(list.Tasks) < list.Total {
+		fmt.Fprintf(cli.output, "Showing 1-%d of %d, type 'list' to page through all\n", len(list.Tasks), list.Total)
+	}
+	return nil
+}
+
+// listTasksByTag displays every task with the given tag in a single list, archived ones too if includeArchived is set.
+func (cli *CLI) listTasksByTag(ctx context.Context, tag string, includeArchived bool) error {
+	tasks, err := cli.client.GetTasksByTag(ctx, tag, includeArchived)
+	if err != nil {
+		return fmt.Errorf("failed to retrieve tasks: %w", err)
+	}
+
+	if tasks == nil {
+		tasks = []client.Task{}
+	}
+	if cli.outputResult(tasks) {
+		return nil
+	}
+
+	if len(tasks) == 0 {
+		fmt.Fprintf(cli.output, "No tasks tagged '%s'\n", tag)
+		return nil
+	}
+
+	fmt.Fprintf(cli.output, "\n=== Tasks tagged '%s' ===\n", tag)
+	for _, task := range tasks {
+		fmt.Fprintln(cli.output, cli.displayTask(task))
+	}
+	fmt.Fprintln(cli.output, "==================")
+	return nil
+}
+
+// handleSearchCommand prompts for a keyword and displays the tasks whose description contains it.
+func (cli *CLI) handleSearchCommand(ctx context.Context) error {
+	fmt.Fprintln(cli.messages(), "Enter keyword to search:")
+
+	input, err := cli.input.ReadInput(maxSearchInputSize)
+	if err != nil {
+		return fmt.Errorf("searching tasks: input failed: %w", err)
+	}
+
+	query, err := validation.ValidateSearchQuery(input)
+	if err != nil {
+		return fmt.Errorf("searching tasks: validation failed: %w", err)
+	}
+
+	tasks, err := cli.client.SearchTasks(ctx, query)
+	if err != nil {
+		return fmt.Errorf("searching tasks: request failed: %w", err)
+	}
+
+	if tasks == nil {
+		tasks = []client.Task{}
+	}
+	if cli.outputResult(tasks) {
+		return nil
+	}
+
+	if len(tasks) == 0 {
+		fmt.Fprintf(cli.output, "No tasks matching '%s'\n", query)
+		return nil
+	}
+
+	fmt.Fprintf(cli.output, "\n=== Tasks matching '%s' ===\n", query)
+	for _, task := range tasks {
+		fmt.Fprintln(cli.output, cli.displayTask(task))
+	}
+	fmt.Fprintln(cli.output, "==================")
+	return nil
+}
+
+// fileFormat picks the export/import format from a file extension: "json" or "csv".
+func fileFormat(path string) (string, error) {
+	switch ext := strings.ToLower(filepath.Ext(path)); ext {
+	case ".json":
+		return "json", nil
+	case ".csv":
+		return "csv", nil
+	default:
+		return "", fmt.Errorf("%w %q (use .json or .csv)", ErrUnsupportedFormat, ext)
+	}
+}
+
+// exportTasks writes tasks to w as an indented JSON array or as CSV with an id,description,done header.
+// An empty task list still produces valid output: "[]" or the header row alone.
+func exportTasks(w io.Writer, tasks []client.Task, format string) error {
+	switch format {
+	case "json":
+		if tasks == nil {
+			tasks = []client.Task{}
+		}
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(tasks)
+	case "csv":
+		cw := csv.NewWriter(w)
+		if err := cw.Write([]string{"id", "description", "done"}); err != nil {
+			return err
+		}
+		for _, t := range tasks {
+			if err := cw.Write([]string{strconv.Itoa(t.ID), t.Description, strconv.FormatBool(t.Done)}); err != nil {
+				return err
+			}
+		}
+		cw.Flush()
+		return cw.Error()
+	default:
+		return fmt.Errorf("%w %q", ErrUnsupportedFormat, format)
+	}
+}
+
+// handleStatsCommand shows how many of the user's tasks are done and pending.
+func (cli *CLI) handleStatsCommand(ctx context.Context) error {
+	stats, err := cli.client.Stats(ctx)
+	if err != nil {
+		return fmt.Errorf("stats failed: %w", err)
+	}
+
+	if cli.outputResult(stats) {
+		return nil
+	}
+	fmt.Fprintln(cli.output, formatStats(*stats))
+	return nil
+}
+
+// countResult is the JSON result of the count command.
+type countResult struct {
+	Total int `json:"total"`
+	Done  int `json:"done"`
+}
+
+// parseCountArgs reads the flags accepted by the count command, e.g. "--done".
+func parseCountArgs(args []string) (done bool, err error) {
+	fs := flag.NewFlagSet(string(CommandCount), flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	fs.BoolVar(&done, "done", false, "count only the completed tasks")
+	if err := fs.Parse(args); err != nil {
+		return false, fmt.Errorf("%w: %v", ErrInvalidArguments, err)
+	}
+	if fs.NArg() > 0 {
+		return false, fmt.Errorf("%w: unexpected %q", ErrInvalidArguments, fs.Arg(0))
+	}
+	return done, nil
+}
+
+// handleCountCommand prints just the number of tasks, or of completed ones with --done, so scripts can use it.
+// The counts come from the stats endpoint; servers and transports without it are counted from the task list.
+func (cli *CLI) handleCountCommand(ctx context.Context, args []string) error {
+	done, err := parseCountArgs(args)
+	if err != nil {
+		return fmt.Errorf("counting tasks: %w", err)
+	}
+
+	var result countResult
+	stats, err := cli.client.Stats(ctx)
+	switch {
+	case err == nil:
+		result = countResult{Total: stats.Total, Done: stats.Done}
+	case isUnsupported(err):
+		tasks, err := cli.fetchAllTasks(ctx, client.ListQuery{})
+		if err != nil {
+			return fmt.Errorf("counting tasks: %w", err)
+		}
+		result.Total = len(tasks)
+		for _, task := range tasks {
+			if task.Done {
+				result.Done++
+			}
+		}
+	default:
+		return fmt.Errorf("counting tasks: %w", err)
+	}
+
+	if cli.outputResult(result) {
+		return nil
+	}
+	if done {
+		fmt.Fprintln(cli.output, result.Done)
+	} else {
+		fmt.Fprintln(cli.output, result.Total)
+	}
+	return nil
+}
+
+// formatStats summarizes task counts, e.g. "12 total, 5 done, 7 pending (42% complete)".
+// Without any tasks the completion is reported as 0%.
+func formatStats(s client.Stats) string {
+	percent := 0
+	if s.Total > 0 {
+		percent = int(math.Round(float64(s.Done) * 100 / float64(s.Total)))
+	}
+	return fmt.Sprintf("%d total, %d done, %d pending (%d%% complete)", s.Total, s.Done, s.Pending, percent)
+}
+
+// fetchAllTasks pages through the task list until every task matching query has been retrieved in its order.
+// The result is never nil, so it encodes as [] when there are no tasks.
+func (cli *CLI) fetchAllTasks(ctx context.Context, query client.ListQuery) ([]client.Task, error) {
+	tasks := []client.Task{}
+	for {
+		list, err := cli.client.GetTasks(ctx, 0, len(tasks), query)
+		if err != nil {
+			return nil, err
+		}
+		tasks = append(tasks, list.Tasks...)
+		if len(list.Tasks) == 0 || len(tasks) >= list.Total {
+			return tasks, nil
+		}
+	}
+}
+
+// handleExportCommand prompts for a file path and writes all tasks to it, choosing JSON or CSV by extension.
+func (cli *CLI) handleExportCommand(ctx context.Context) error {
+	fmt.Fprintln(cli.messages(), "Enter file path (.json or .csv):")
+
+	path, err := cli.input.ReadInput(maxPathInputSize)
+	if err != nil {
+		return fmt.Errorf("exporting tasks: input failed: %w", err)
+	}
+
+	format, err := fileFormat(path)
+	if err != nil {
+		return fmt.Errorf("exporting tasks: %w", err)
+	}
+
+	tasks, err := cli.fetchAllTasks(ctx, client.ListQuery{Sort: "id"})
+	if err != nil {
+		return fmt.Errorf("exporting tasks: failed to retrieve tasks: %w", err)
+	}
+
+	f, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("exporting tasks: %w", err)
+	}
+	if err := exportTasks(f, tasks, format); err != nil {
+		f.Close()
+		return fmt.Errorf("exporting tasks: write %s failed: %w", path, err)
+	}
+	if err := f.Close(); err != nil {
+		return fmt.Errorf("exporting tasks: write %s failed: %w", path, err)
+	}
+
+	if cli.outputResult(map[string]interface{}{"exported": len(tasks), "path": path}) {
+		return nil
+	}
+	fmt.Fprintf(cli.output, "%s Exported %d tasks to %s\n", cli.sym.OK, len(tasks), path)
+	return nil
+}
+
+// parseImportFile reads task descriptions from a JSON array of tasks or from CSV rows.
+// A CSV header row is detected by a "description" column; without one, the first column is the description.
+// Descriptions are returned as found; validating them is left to the caller.
+func parseImportFile(r io.Reader, format string) ([]string, error) {
+	switch format {
+	case "json":
+		var tasks []struct {
+			Description string `json:"description"`
+		}
+		if err := json.NewDecoder(r).Decode(&tasks); err != nil {
+			return nil, fmt.Errorf("invalid JSON: %w", err)
+		}
+		descriptions := make([]string, len(tasks))
+		for i, t := range tasks {
+			descriptions[i] = t.Description
+		}
+		return descriptions, nil
+	case "csv":
+		cr := csv.NewReader(r)
+		cr.FieldsPerRecord = -1
+		records, err := cr.ReadAll()
+		if err != nil {
+			return nil, fmt.Errorf("invalid CSV: %w", err)
+		}
+		if len(records) == 0 {
+			return nil, nil
+		}
+
+		column := 0
+		for i, field := range records[0] {
+			if strings.EqualFold(strings.TrimSpace(field), "description") {
+				column = i
+				records = records[1:]
+				break
+			}
+		}
+
+		descriptions := make([]string, 0, len(records))
+		for _, record := range records {
+			if column < len(record) {
+				descriptions = append(descriptions, record[column])
+			} else {
+				descriptions = append(descriptions, "")
+			}
+		}
+		return descriptions, nil
+	default:
+		return nil, fmt.Errorf("%w %q", ErrUnsupportedFormat, format)
+	}
+}
+
+// isUnsupported reports whether err means the server or transport does not offer the endpoint at all,
+// so the caller can fall back to older ones.
+func isUnsupported(err error) bool {
+	var apiErr *client.APIError
+	if !errors.As(err, &apiErr) {
+		return false
+	}
+	return apiErr.StatusCode == http.StatusNotFound || apiErr.StatusCode == http.StatusMethodNotAllowed || apiErr.StatusCode == http.StatusNotImplemented
+}
+
+// createImportedTasks sends descriptions through the batch endpoint,
+// falling back to creating them one by one against servers or transports without it.
+func (cli *CLI) createImportedTasks(ctx context.Context, descriptions []string) error {
+	_, err := cli.client.CreateTasks(ctx, descriptions)
+	if !isUnsupported(err) {
+		return err
+	}
+
+	for _, desc := range descriptions {
+		if _, err := cli.client.CreateTask(ctx, desc, false, nil, domain.PriorityNone); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// handleImportCommand prompts for a .json or .csv file and creates a task for each description in it.
+// Entries that fail validation are reported and skipped; the rest are still imported.
+func (cli *CLI) handleImportCommand(ctx context.Context) error {
+	fmt.Fprintln(cli.messages(), "Enter file path (.json or .csv):")
+
+	path, err := cli.input.ReadInput(maxPathInputSize)
+	if err != nil {
+		return fmt.Errorf("importing tasks: input failed: %w", err)
+	}
+
+	format, err := fileFormat(path)
+	if err != nil {
+		return fmt.Errorf("importing tasks: %w", err)
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("importing tasks: %w", err)
+	}
+	defer f.Close()
+
+	entries, err := parseImportFile(f, format)
+	if err != nil {
+		return fmt.Errorf("importing tasks: read %s failed: %w", path, err)
+	}
+
+	var descriptions []string
+	for i, entry := range entries {
+		desc, err := validation.ValidateTaskDescriptionLength(entry, cli.descriptionLimit())
+		if err != nil {
+			fmt.Fprintf(cli.messages(), "%s Skipping entry %d: %v\n", cli.sym.Warning, i+1, err)
+			continue
+		}
+		descriptions = append(descriptions, desc)
+	}
+	skipped := len(entries) - len(descriptions)
+
+	if len(descriptions) == 0 {
+		if cli.outputResult(map[string]int{"imported": 0, "skipped": skipped}) {
+			return nil
+		}
+		fmt.Fprintf(cli.output, "No tasks imported (%d skipped)\n", skipped)
+		return nil
+	}
+
+	if err := cli.createImportedTasks(ctx, descriptions); err != nil {
+		return fmt.Errorf("importing tasks: creation failed: %w", err)
+	}
+
+	if cli.outputResult(map[string]int{"imported": len(descriptions), "skipped": skipped}) {
+		return nil
+	}
+	fmt.Fprintf(cli.output, "%s Imported %d tasks (%d skipped)\n", cli.sym.OK, len(descriptions), skipped)
+	return nil
+}
+
+// handleLoginCommand prompts for credentials and authenticates the user
+func (cli *CLI) handleLoginCommand() error {
+	token, err := cli.authManager.PromptLogin()
+	if err != nil {
+		return fmt.Errorf("login failed: %w", err)
+	}
+
+	// Update client with new token
+	cli.client.SetToken(token)
+	// The history belongs to the previous account's tasks
+	cli.operationLog = nil
+
+	cli.outputResult(map[string]bool{"authenticated": true})
+	return nil
+}
+
+// handleRegisterCommand prompts for credentials and registers a new user
+func (cli *CLI) handleRegisterCommand() error {
+	token, err := cli.authManager.PromptRegister()
+	if err != nil {
+		return fmt.Errorf("registration failed: %w", err)
+	}
+
+	// Update client with new token
+	cli.client.SetToken(token)
+	cli.operationLog = nil
+
+	cli.outputResult(map[string]bool{"authenticated": true})
+	return nil
+}
+
+// handleLogoutCommand clears the stored authentication token
+func (cli *CLI) handleLogoutCommand() error {
+	err := cli.authManager.ClearToken()
+	if err != nil {
+		return fmt.Errorf("logout failed: %w", err)
+	}
+
+	if cli.outputResult(map[string]bool{"logged_out": true}) {
+		return nil
+	}
+	fmt.Fprintf(cli.output, "%s Logged out successfully\n", cli.sym.OK)
+	fmt.Fprintf(cli.output, "%s Bye!\n", cli.sym.Bye)
+	return nil
+}
+
+// handleWhoamiCommand shows which account the stored token belongs to.
+func (cli *CLI) handleWhoamiCommand(ctx context.Context) error {
+	user, err := cli.client.Me(ctx)
+	if err != nil {
+		return fmt.Errorf("whoami failed: %w", err)
+	}
+
+	if cli.outputResult(user) {
+		return nil
+	}
+	fmt.Fprintf(cli.output, "%s Logged in as %s (ID: %d, registered %s)\n", cli.sym.User, user.Email, user.ID, user.CreatedAt.Local().Format(dueDateLayout))
+	return nil
+}
+
+// handleDeleteAccountCommand deletes the logged in account after the user retypes its email.
+// On success the stored token is cleared and deleted is true, so the CLI can exit.
+func (cli *CLI) handleDeleteAccountCommand(ctx context.Context) (deleted bool, err error) {
+	user, err := cli.client.Me(ctx)
+	if err != nil {
+		return false, fmt.Errorf("deleting account: %w", err)
+	}
+
+	fmt.Fprintf(cli.messages(), "%s This permanently deletes %s and all its tasks.\n", cli.sym.Warning, user.Email)
+	fmt.Fprintln(cli.messages(), "Type your email to confirm:")
+	email, err := cli.input.ReadInput(maxEmailInputSize)
+	if err != nil {
+		return false, fmt.Errorf("deleting account: read confirmation failed: %w", err)
+	}
+	if !strings.EqualFold(email, user.Email) {
+		if cli.outputResult(map[string]bool{"deleted": false}) {
+			return false, nil
+		}
+		fmt.Fprintln(cli.output, "Email does not match, account deletion canceled")
+		return false, nil
+	}
+
+	if err := cli.client.DeleteAccount(ctx); err != nil {
+		return false, fmt.Errorf("deleting account failed: %w", err)
+	}
+	if err := cli.authManager.ClearToken(); err != nil {
+		return true, fmt.Errorf("account deleted, but clearing token failed: %w", err)
+	}
+
+	if cli.outputResult(map[string]bool{"deleted": true}) {
+		return true, nil
+	}
+	fmt.Fprintf(cli.output, "%s Account deleted\n", cli.sym.OK)
+	fmt.Fprintf(cli.output, "%s Bye!\n", cli.sym.Bye)
+	return true, nil
+}
+
+// handlePasswdCommand changes the account password; the current session stays logged in.
+func (cli *CLI) handlePasswdCommand(ctx context.Context) error {
+	if err := cli.authManager.PromptChangePassword(ctx); err != nil {
+		return err
+	}
+
+	if cli.outputResult(map[string]bool{"password_changed": true}) {
+		return nil
+	}
+	fmt.Fprintf(cli.output, "%s Password changed\n", cli.sym.OK)
+	return nil
+}
+
+// commandContext returns the context a single command runs under.
+// It is cancelled by Ctrl-C, which aborts the in-flight request instead of terminating the CLI.
+func commandContext() (context.Context, context.CancelFunc) {
+	return signal.NotifyContext(context.Background(), os.Interrupt)
+}
 
 // RunLoop starts the main command processing loop for the CLI application.
 // Continuously reads commands, executes handlers, and manages application lifecycle until exit.
 func (cli *CLI) RunLoop() {
 	cli.showHelp()
 	for {
-		fmt.Fprint(cli.output, "\nEnter command: ")
+		fmt.Fprint(cli.messages(), "\nEnter command: ")
 		input, err := cli.input.ReadInput(maxCommandInputSize)
 		if err != nil {
 			cli.handleError(err, "Input error")
 			continue
 		}
 
-		cmd, err := validateCommand(input)
+		name, args := splitCommandLine(input)
+		cmd, err := resolveCommand(name)
 		if err != nil {
-			suggestion := suggestCommand(input)
-			if suggestion != "" {
-				fmt.Fprintf(cli.output, "❌ Unknown command: '%s', maybe you wanted: '%s'\n", input, suggestion)
+			if errors.Is(err, ErrAmbiguousCommand) {
+				cli.outputError(err.Error())
 			} else {
 				cli.handleError(err, "Command validate error")
-				fmt.Fprintln(cli.output, "Type 'help' to see available commands")
+				fmt.Fprintln(cli.messages(), "Type 'help' to see available commands")
 			}
 			continue
 		}
 
-		switch Command(cmd) {
-		case CommandAdd:
-			if err := cli.handleAddCommand(); err != nil {
-				if cli.handleAuthError(err) {
-					continue
-				}
-				cli.handleError(err, "Add command error")
-			}
+		if len(args) > 0 && !cmd.takesArguments() {
+			cli.outputError(fmt.Sprintf("Command '%s' does not take arguments", cmd))
+			continue
+		}
 
-		case CommandStatus:
-			if err := cli.handleStatusCommand(); err != nil {
-				if cli.handleAuthError(err) {
-					continue
-				}
-				cli.handleError(err, "Status command error")
-			}
+		if cli.runCommand(cmd, args) {
+			return
+		}
+	}
+}
 
-		case CommandList:
-			if err := cli.handleListCommand(); err != nil {
-				if cli.handleAuthError(err) {
-					continue
-				}
-				cli.handleError(err, "List command error")
-			}
+// runCommand executes a single resolved command under its own cancellable context.
+// When the command fails because the session expired and re-authentication succeeds,
+// it is re-run once with the input it already collected, so the user does not have to retype it.
+// Returns true when the command ends the session.
+func (cli *CLI) runCommand(cmd Command, args []string) (exit bool) {
+	ctx, stop := commandContext()
+	defer stop()
 
-		case CommandProcess:
-			fmt.Fprintln(cli.output, "⚠️  Process command not available in client mode")
+	handler, ok := cli.commandHandlers(ctx, args, &exit)[cmd]
+	if !ok {
+		return false
+	}
 
-		case CommandClear:
-			if err := cli.handleClearCommand(); err != nil {
-				if cli.handleAuthError(err) {
-					continue
-				}
-				cli.handleError(err, "Clear command error")
-			}
+	input := cli.input
+	recorder := &recordingInputReader{InputReader: input}
+	cli.input = recorder
+	err := handler()
+	cli.input = input
+	if err == nil {
+		cli.autoList(ctx, cmd)
+		return exit
+	}
 
-		case CommandDelete:
-			if err := cli.handleDeleteCommand(); err != nil {
-				if cli.handleAuthError(err) {
-					continue
-				}
-				cli.handleError(err, "Delete command error")
-			}
+	if cmd.retriesAfterReauth() && cli.handleAuthError(err) {
+		fmt.Fprintf(cli.messages(), "%s Retrying '%s'...\n", cli.sym.Retry, cmd)
+		cli.input = &replayInputReader{InputReader: input, inputs: recorder.inputs}
+		err = handler()
+		cli.input = input
+		if err == nil {
+			cli.autoList(ctx, cmd)
+			return exit
+		}
+	}
+	cli.handleError(err, commandErrorContexts[cmd])
+	return exit
+}
+
+// autoList shows the updated task list after a command that changed tasks when Config.AutoList is set.
+// JSON output is left alone so every command still prints exactly one result.
+func (cli *CLI) autoList(ctx context.Context, cmd Command) {
+	if cli.config == nil || !cli.config.AutoList || cli.jsonOutput() || !cmd.changesTasks() {
+		return
+	}
+	if err := cli.showUpdatedTasks(ctx); err != nil {
+		cli.handleError(err, commandErrorContexts[CommandRefresh])
+	}
+}
 
-		case CommandHelp:
+// commandHandlers maps every command to the closure that runs it under ctx.
+// Handlers that end the session report it through exit.
+func (cli *CLI) commandHandlers(ctx context.Context, args []string, exit *bool) map[Command]func() error {
+	return map[Command]func() error{
+		CommandAdd:        func() error { return cli.handleAddCommand(ctx) },
+		CommandAddDone:    func() error { return cli.handleAddDoneCommand(ctx) },
+		CommandAddMany:    func() error { return cli.handleAddManyCommand(ctx) },
+		CommandStatus:     func() error { return cli.handleStatusCommand(ctx) },
+		CommandToggle:     func() error { return cli.handleToggleCommand(ctx) },
+		CommandMarkAll:    func() error { return cli.handleMarkAllCommand(ctx) },
+		CommandShow:       func() error { return cli.handleShowCommand(ctx) },
+		CommandList:       func() error { return cli.handleListCommand(ctx, args) },
+		CommandRefresh:    func() error { return cli.handleListCommand(ctx, nil) },
+		CommandSearch:     func() error { return cli.handleSearchCommand(ctx) },
+		CommandStats:      func() error { return cli.handleStatsCommand(ctx) },
+		CommandCount:      func() error { return cli.handleCountCommand(ctx, args) },
+		CommandExport:     func() error { return cli.handleExportCommand(ctx) },
+		CommandImport:     func() error { return cli.handleImportCommand(ctx) },
+		CommandClear:      func() error { return cli.handleClearCommand(ctx) },
+		CommandDelete:     func() error { return cli.handleDeleteCommand(ctx, args) },
+		CommandDeleteDone: func() error { return cli.handleDeleteDoneCommand(ctx) },
+		CommandRestore:    func() error { return cli.handleRestoreCommand(ctx) },
+		CommandArchive:    func() error { return cli.handleArchiveCommand(ctx, args) },
+		CommandTag:        func() error { return cli.handleTagCommand(ctx) },
+		CommandUntag:      func() error { return cli.handleUntagCommand(ctx) },
+		CommandUndo:       func() error { return cli.handleUndoCommand(ctx) },
+		CommandUpdate:     func() error { return cli.handleUpdateCommand(ctx) },
+		CommandWhoami:     func() error { return cli.handleWhoamiCommand(ctx) },
+		CommandPasswd:     func() error { return cli.handlePasswdCommand(ctx) },
+		CommandLogin:      cli.handleLoginCommand,
+		CommandRegister:   cli.handleRegisterCommand,
+		CommandProcess: func() error {
+			if cli.jsonOutput() {
+				cli.outputError("Process command not available in client mode")
+			} else {
+				fmt.Fprintf(cli.output, "%s Process command not available in client mode\n", cli.sym.Warning)
+			}
+			return nil
+		},
+		CommandHelp: func() error {
 			cli.showHelp()
+			return nil
+		},
+		CommandExit: func() error {
+			fmt.Fprintf(cli.messages(), "%s Bye!\n", cli.sym.Bye)
+			*exit = true
+			return nil
+		},
+		CommandDeleteAccount: func() error {
+			deleted, err := cli.handleDeleteAccountCommand(ctx)
+			*exit = deleted
+			return err
+		},
+		CommandLogout: func() error {
+			*exit = true
+			return cli.handleLogoutCommand()
+		},
+	}
+}
 
-		case CommandExit:
-			fmt.Fprintln(cli.output, "👋 Bye!")
-			return
+// commandErrorContexts labels the error printed when a command fails.
+var commandErrorContexts = map[Command]string{
+	CommandAdd:           "Add command error",
+	CommandAddDone:       "Add done command error",
+	CommandAddMany:       "Add many command error",
+	CommandStatus:        "Status command error",
+	CommandToggle:        "Toggle command error",
+	CommandMarkAll:       "Mark all command error",
+	CommandShow:          "Show command error",
+	CommandList:          "List command error",
+	CommandRefresh:       "Refresh command error",
+	CommandSearch:        "Search command error",
+	CommandStats:         "Stats command error",
+	CommandCount:         "Count command error",
+	CommandExport:        "Export command error",
+	CommandImport:        "Import command error",
+	CommandClear:         "Clear command error",
+	CommandDelete:        "Delete command error",
+	CommandDeleteDone:    "Delete done command error",
+	CommandRestore:       "Restore command error",
+	CommandArchive:       "Archive command error",
+	CommandTag:           "Tag command error",
+	CommandUntag:         "Untag command error",
+	CommandUndo:          "Undo command error",
+	CommandUpdate:        "Update command error",
+	CommandLogin:         "Login command error",
+	CommandRegister:      "Register command error",
+	CommandWhoami:        "Whoami command error",
+	CommandDeleteAccount: "Delete account command error",
+	CommandPasswd:        "Passwd command error",
+	CommandLogout:        "Logout command error",
+}
 
-		case CommandUpdate:
-			if err := cli.handleUpdateCommand(); err != nil {
-				if cli.handleAuthError(err) {
-					continue
-				}
-				cli.handleError(err, "Update command error")
-			}
+// recordedInput is one answer read while a command ran, kept so the command can be replayed.
+type recordedInput struct {
+	value string
+	err   error
+}
 
-		case CommandLogin:
-			if err := cli.handleLoginCommand(); err != nil {
-				cli.handleError(err, "Login command error")
-			}
+// recordingInputReader remembers everything a command reads.
+type recordingInputReader struct {
+	InputReader
+	inputs []recordedInput
+}
 
-		case CommandRegister:
-			if err := cli.handleRegisterCommand(); err != nil {
-				cli.handleError(err, "Register command error")
-			}
+func (r *recordingInputReader) ReadInput(maxSize int) (string, error) {
+	value, err := r.InputReader.ReadInput(maxSize)
+	r.inputs = append(r.inputs, recordedInput{value: value, err: err})
+	return value, err
+}
 
-		case CommandLogout:
-			if err := cli.handleLogoutCommand(); err != nil {
-				cli.handleError(err, "Logout command error")
-			}
-			return
-		}
+// replayInputReader answers with recorded inputs first and falls back to the real reader
+// when a re-run command asks for more than it read the first time.
+type replayInputReader struct {
+	InputReader
+	inputs []recordedInput
+}
+
+func (r *replayInputReader) ReadInput(maxSize int) (string, error) {
+	if len(r.inputs) == 0 {
+		return r.InputReader.ReadInput(maxSize)
 	}
+	next := r.inputs[0]
+	r.inputs = r.inputs[1:]
+	return next.value, next.err
 }
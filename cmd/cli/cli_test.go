@@ -2,20 +2,31 @@ package main
 
 import (
 	"bytes"
+	"context"
+	"encoding/json"
 	"errors"
+	"fmt"
 	"io"
 	"myproject/cmd/cli/auth"
 	"myproject/cmd/cli/client"
+	"myproject/cmd/cli/symbols"
+	"myproject/domain"
 	"myproject/domain/validation"
+	"net/http"
+	"os"
+	"path/filepath"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 // TestFormatTask tests the formatTask function
 func TestFormatTask(t *testing.T) {
 	// ====Arrange====
+	now := time.Date(2026, 10, 16, 12, 0, 0, 0, time.Local)
 	testCases := []struct {
 		name     string
 		task     client.Task
@@ -46,12 +57,189 @@ func TestFormatTask(t *testing.T) {
 			task:     client.Task{ID: 10, Description: "This is a very long task description that should not be truncated", Done: true},
 			expected: "[✓] 10: This is a very long task description that should not be truncated",
 		},
+		{
+			name:     "Task with tags",
+			task:     client.Task{ID: 6, Description: "Write report", Tags: []string{"home", "work"}},
+			expected: "[ ] 6: Write report #home #work",
+		},
+		{
+			name:     "Task due in the future",
+			task:     client.Task{ID: 3, Description: "Pay rent", DueDate: timePtr(time.Date(2026, 10, 20, 23, 59, 59, 0, time.Local))},
+			expected: "[ ] 3: Pay rent (due 2026-10-20)",
+		},
+		{
+			name:     "Overdue task",
+			task:     client.Task{ID: 3, Description: "Pay rent", DueDate: timePtr(time.Date(2026, 10, 1, 23, 59, 59, 0, time.Local))},
+			expected: "[!] 3: Pay rent (overdue since 2026-10-01)",
+		},
+		{
+			name:     "Completed task past its due date",
+			task:     client.Task{ID: 3, Description: "Pay rent", Done: true, DueDate: timePtr(time.Date(2026, 10, 1, 23, 59, 59, 0, time.Local))},
+			expected: "[✓] 3: Pay rent",
+		},
+		{
+			name:     "High priority task",
+			task:     client.Task{ID: 4, Description: "Fix the roof", Priority: domain.PriorityHigh},
+			expected: "[ ] 4: ❗ Fix the roof",
+		},
+		{
+			name:     "Overdue high priority task",
+			task:     client.Task{ID: 4, Description: "Fix the roof", Priority: domain.PriorityHigh, DueDate: timePtr(time.Date(2026, 10, 1, 23, 59, 59, 0, time.Local))},
+			expected: "[!] 4: ❗ Fix the roof (overdue since 2026-10-01)",
+		},
+		{
+			name:     "Low priority task has no marker",
+			task:     client.Task{ID: 5, Description: "Water plants", Priority: domain.PriorityLow},
+			expected: "[ ] 5: Water plants",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// ====Act====
+			result := formatTask(tc.task, now, symbols.Emoji)
+
+			// ====Assert====
+			if result != tc.expected {
+				t.Errorf("Expected %q, got %q", tc.expected, result)
+			}
+		})
+	}
+}
+
+func TestFormatTask_Plain(t *testing.T) {
+	now := time.Date(2026, 10, 15, 12, 0, 0, 0, time.Local)
+	task := client.Task{ID: 4, Description: "Fix the roof", Done: true, Priority: domain.PriorityHigh}
+
+	assert.Equal(t, "[x] 4: ! Fix the roof", formatTask(task, now, symbols.Plain))
+}
+
+func TestCLI_PlainOutput(t *testing.T) {
+	output := &bytes.Buffer{}
+	mockClient := &MockTaskClient{toggleTaskResult: &client.Task{ID: 1, Description: "Buy milk", Done: true}}
+	cli := NewCLI(NewMockInputReader("1"), output, &Config{Plain: true}, mockClient, &MockAuthManager{})
+
+	require.NoError(t, cli.handleToggleCommand(context.Background()))
+
+	assert.Contains(t, output.String(), "[OK] Task toggled: [x] 1: Buy milk")
+	assert.NotContains(t, output.String(), "✅")
+}
+
+func TestCLI_AutoList(t *testing.T) {
+	tasks := []client.Task{{ID: 1, Description: "buy milk"}, {ID: 2, Description: "call mom"}}
+	testCases := []struct {
+		name       string
+		cfg        *Config
+		cmd        Command
+		wantListed bool
+	}{
+		{name: "lists after a change", cfg: &Config{AutoList: true}, cmd: CommandToggle, wantListed: true},
+		{name: "off by default", cfg: &Config{}, cmd: CommandToggle, wantListed: false},
+		{name: "not after a read-only command", cfg: &Config{AutoList: true}, cmd: CommandSearch, wantListed: false},
+		{name: "not in JSON mode", cfg: &Config{AutoList: true, JSON: true}, cmd: CommandToggle, wantListed: false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			output := &bytes.Buffer{}
+			mockClient := &MockTaskClient{
+				toggleTaskResult: &client.Task{ID: 2, Description: "call mom"},
+				getTasksResult:   tasks,
+			}
+			cli := NewCLI(NewMockInputReader("2"), output, tc.cfg, mockClient, &MockAuthManager{})
+
+			cli.runCommand(tc.cmd, nil)
+
+			if tc.wantListed {
+				assert.Contains(t, output.String(), "=== Your Tasks ===\n[ ] 1: buy milk\n[ ] 2: call mom\n")
+			} else {
+				assert.NotContains(t, output.String(), "=== Your Tasks ===")
+			}
+		})
+	}
+}
+
+func TestCLI_RefreshCommand(t *testing.T) {
+	output := &bytes.Buffer{}
+	mockClient := &MockTaskClient{getTasksResult: []client.Task{{ID: 1, Description: "buy milk"}}}
+	cli := NewCLI(NewMockInputReader(), output, &Config{}, mockClient, &MockAuthManager{})
+
+	cli.runCommand(CommandRefresh, nil)
+
+	assert.Contains(t, output.String(), "=== Your Tasks ===\n[ ] 1: buy milk\n")
+	assert.Equal(t, []int{0}, mockClient.getTasksOffsets)
+}
+
+func timePtr(t time.Time) *time.Time { return &t }
+
+// TestParseDueDate tests that due dates are read as the end of the given local day
+func TestParseDueDate(t *testing.T) {
+	// ====Arrange====
+	testCases := []struct {
+		name        string
+		input       string
+		expected    time.Time
+		expectedErr error
+	}{
+		{name: "Valid date", input: "2026-10-20", expected: time.Date(2026, 10, 20, 23, 59, 59, 0, time.Local)},
+		{name: "Last day of year", input: "2026-12-31", expected: time.Date(2026, 12, 31, 23, 59, 59, 0, time.Local)},
+		{name: "Wrong layout", input: "20.10.2026", expectedErr: ErrInvalidDueDate},
+		{name: "Impossible date", input: "2026-02-30", expectedErr: ErrInvalidDueDate},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// ====Act====
+			dueDate, err := parseDueDate(tc.input)
+
+			// ====Assert====
+			assert.ErrorIs(t, err, tc.expectedErr)
+			assert.True(t, tc.expected.Equal(dueDate), "expected %v, got %v", tc.expected, dueDate)
+		})
+	}
+}
+
+// TestFormatTaskAge tests that formatTaskAge appends the task age in its largest whole unit
+func TestFormatTaskAge(t *testing.T) {
+	// ====Arrange====
+	now := time.Date(2025, 6, 15, 12, 0, 0, 0, time.UTC)
+	testCases := []struct {
+		name      string
+		createdAt time.Time
+		expected  string
+	}{
+		{
+			name:      "Created seconds ago",
+			createdAt: now.Add(-30 * time.Second),
+			expected:  "[ ] 1: Buy milk (just now)",
+		},
+		{
+			name:      "Created minutes ago",
+			createdAt: now.Add(-5 * time.Minute),
+			expected:  "[ ] 1: Buy milk (5m ago)",
+		},
+		{
+			name:      "Created hours ago",
+			createdAt: now.Add(-150 * time.Minute),
+			expected:  "[ ] 1: Buy milk (2h ago)",
+		},
+		{
+			name:      "Created days ago",
+			createdAt: now.Add(-73 * time.Hour),
+			expected:  "[ ] 1: Buy milk (3d ago)",
+		},
+		{
+			name:     "Unknown creation time",
+			expected: "[ ] 1: Buy milk",
+		},
 	}
 
 	for _, tc := range testCases {
 		t.Run(tc.name, func(t *testing.T) {
+			task := client.Task{ID: 1, Description: "Buy milk", CreatedAt: tc.createdAt}
+
 			// ====Act====
-			result := formatTask(tc.task)
+			result := formatTaskAge(task, now, symbols.Emoji)
 
 			// ====Assert====
 			if result != tc.expected {
@@ -284,8 +472,13 @@ func TestCLI_showHelp(t *testing.T) {
 			name: "Displays all available commands",
 			expectedCommands: []string{
 				"add",
+				"addmany",
 				"status",
+				"show",
 				"list",
+				"search",
+				"count",
+				"archive",
 				"process",
 				"clear",
 				"update",
@@ -381,6 +574,41 @@ func TestCLI_handleError(t *testing.T) {
 				"Internal server error",
 			},
 		},
+		{
+			name: "APIError with request ID",
+			err: fmt.Errorf("deleting task id 3 failed: %w", &client.APIError{
+				StatusCode: 404,
+				Message:    "Task not found",
+				RequestID:  "3f2c9a4e-1b7d-4c2a-9e8f-5d6c7b8a9f01",
+			}),
+			context: "Delete command error",
+			expectedContains: []string{
+				"Task not found",
+				"Request ID: 3f2c9a4e-1b7d-4c2a-9e8f-5d6c7b8a9f01",
+			},
+		},
+		{
+			name: "NetworkError with request ID",
+			err: &client.NetworkError{
+				URL:       "http://localhost:8080",
+				Err:       errors.New("connection refused"),
+				RequestID: "req-42",
+			},
+			context: "Connection error",
+			expectedContains: []string{
+				"Cannot connect to server",
+				"Request ID: req-42",
+			},
+		},
+		{
+			name:    "Cancelled request",
+			err:     fmt.Errorf("failed to retrieve tasks: %w", context.Canceled),
+			context: "List command error",
+			expectedContains: []string{
+				"List command error",
+				"request cancelled",
+			},
+		},
 		{
 			name:    "Generic error",
 			err:     errors.New("something went wrong"),
@@ -501,8 +729,12 @@ func TestCLI_handleAddCommand(t *testing.T) {
 	testCases := []struct {
 		name             string
 		input            string
+		dueDateInput     string
+		priorityInput    string
 		createTaskResult *client.Task
 		createTaskErr    error
+		expectedDueDate  *time.Time
+		expectedPriority int
 		expectedErr      error
 		expectedContains string
 	}{
@@ -530,6 +762,34 @@ func TestCLI_handleAddCommand(t *testing.T) {
 			expectedErr:      nil,
 			expectedContains: "✅ Task added (ID: 2)",
 		},
+		{
+			name:             "Task with due date",
+			input:            "Pay rent",
+			dueDateInput:     "2026-10-20",
+			createTaskResult: &client.Task{ID: 3, Description: "Pay rent"},
+			expectedDueDate:  timePtr(time.Date(2026, 10, 20, 23, 59, 59, 0, time.Local)),
+			expectedContains: "✅ Task added (ID: 3)",
+		},
+		{
+			name:         "Invalid due date",
+			input:        "Pay rent",
+			dueDateInput: "20/10/2026",
+			expectedErr:  ErrInvalidDueDate,
+		},
+		{
+			name:             "Task with priority",
+			input:            "Fix the roof",
+			priorityInput:    "high",
+			createTaskResult: &client.Task{ID: 4, Description: "Fix the roof", Priority: domain.PriorityHigh},
+			expectedPriority: domain.PriorityHigh,
+			expectedContains: "✅ Task added (ID: 4)",
+		},
+		{
+			name:          "Invalid priority",
+			input:         "Fix the roof",
+			priorityInput: "urgent",
+			expectedErr:   domain.ErrInvalidPriority,
+		},
 		{
 			name:             "Empty input",
 			input:            "",
@@ -586,7 +846,7 @@ func TestCLI_handleAddCommand(t *testing.T) {
 				createTaskErr:    tc.createTaskErr,
 			}
 			cli := NewCLI(
-				NewMockInputReader(tc.input),
+				NewMockInputReader(tc.input, tc.dueDateInput, tc.priorityInput),
 				output,
 				&Config{ServerURL: "http://localhost:8080"},
 				mockClient,
@@ -594,7 +854,7 @@ func TestCLI_handleAddCommand(t *testing.T) {
 			)
 
 			// ====Act====
-			err := cli.handleAddCommand()
+			err := cli.handleAddCommand(context.Background())
 
 			// ====Assert====
 			if tc.expectedErr != nil {
@@ -627,6 +887,106 @@ func TestCLI_handleAddCommand(t *testing.T) {
 
 			// Verify prompt was displayed
 			assert.Contains(t, output.String(), "Enter task description:", "Prompt should be displayed")
+			assert.Equal(t, tc.expectedDueDate, mockClient.createTaskDueDate)
+			assert.Equal(t, tc.expectedPriority, mockClient.createTaskPriority)
+		})
+	}
+}
+
+// TestCLI_handleAddDoneCommand tests that add-done creates the task as completed
+func TestCLI_handleAddDoneCommand(t *testing.T) {
+	// ====Arrange====
+	output := &bytes.Buffer{}
+	mockClient := &MockTaskClient{
+		createTaskResult: &client.Task{ID: 3, Description: "Pay rent", Done: true},
+	}
+	cli := NewCLI(
+		NewMockInputReader("Pay rent"),
+		output,
+		&Config{ServerURL: "http://localhost:8080"},
+		mockClient,
+		&MockAuthManager{loadTokenResult: "mock-token"},
+	)
+
+	// ====Act====
+	err := cli.handleAddDoneCommand(context.Background())
+
+	// ====Assert====
+	assert.NoError(t, err)
+	assert.True(t, mockClient.createTaskDone, "Task should be created as done")
+	assert.Contains(t, output.String(), "✅ Task added (ID: 3)")
+}
+
+// TestCLI_handleAddManyCommand tests that addmany sends all lines up to the blank one as a single batch
+func TestCLI_handleAddManyCommand(t *testing.T) {
+	// ====Arrange====
+	testCases := []struct {
+		name             string
+		inputs           []string
+		createTasksIDs   []int
+		createTasksErr   error
+		expectedBatch    []string
+		expectedErr      error
+		expectedContains string
+	}{
+		{
+			name:             "Creates every line until blank line",
+			inputs:           []string{"Buy milk", " Walk the dog ", "", "ignored"},
+			createTasksIDs:   []int{7, 8},
+			expectedBatch:    []string{"Buy milk", "Walk the dog"},
+			expectedContains: "✅ 2 tasks added (IDs: 7, 8)",
+		},
+		{
+			name:             "Blank first line adds nothing",
+			inputs:           []string{""},
+			expectedContains: "No tasks entered",
+		},
+		{
+			name:        "Input interrupted",
+			inputs:      []string{"Buy milk"},
+			expectedErr: io.EOF,
+		},
+		{
+			name:        "Too long line rejects whole batch",
+			inputs:      []string{"Buy milk", strings.Repeat("a", 201), ""},
+			expectedErr: ErrMaxSizeExceeded,
+		},
+		{
+			name:           "Client error",
+			inputs:         []string{"Buy milk", ""},
+			createTasksErr: &client.APIError{StatusCode: 400, Message: "task 0: description is required"},
+			expectedBatch:  []string{"Buy milk"},
+			expectedErr:    &client.APIError{},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			output := &bytes.Buffer{}
+			mockClient := &MockTaskClient{createTasksIDs: tc.createTasksIDs, createTasksErr: tc.createTasksErr}
+			cli := NewCLI(
+				NewMockInputReader(tc.inputs...),
+				output,
+				&Config{ServerURL: "http://localhost:8080"},
+				mockClient,
+				&MockAuthManager{loadTokenResult: "mock-token"},
+			)
+
+			// ====Act====
+			err := cli.handleAddManyCommand(context.Background())
+
+			// ====Assert====
+			switch tc.expectedErr.(type) {
+			case nil:
+				assert.NoError(t, err)
+			case *client.APIError:
+				var apiErr *client.APIError
+				assert.ErrorAs(t, err, &apiErr)
+			default:
+				assert.ErrorIs(t, err, tc.expectedErr)
+			}
+			assert.Equal(t, tc.expectedBatch, mockClient.createTasksBatch)
+			assert.Contains(t, output.String(), tc.expectedContains)
 		})
 	}
 }
@@ -821,7 +1181,7 @@ func TestCLI_handleStatusCommand(t *testing.T) {
 			)
 
 			// ====Act====
-			err := cli.handleStatusCommand()
+			err := cli.handleStatusCommand(context.Background())
 
 			// ====Assert====
 			if tc.expectedErr != nil {
@@ -1012,7 +1372,7 @@ func TestCLI_handleClearCommand(t *testing.T) {
 			)
 
 			// ====Act====
-			err := cli.handleClearCommand()
+			err := cli.handleClearCommand(context.Background())
 
 			// ====Assert====
 			if tc.expectedErr != nil {
@@ -1245,7 +1605,7 @@ func TestCLI_handleUpdateCommand(t *testing.T) {
 			)
 
 			// ====Act====
-			err := cli.handleUpdateCommand()
+			err := cli.handleUpdateCommand(context.Background())
 
 			// ====Assert====
 			if tc.expectedErr != nil {
@@ -1289,6 +1649,50 @@ func TestCLI_handleUpdateCommand(t *testing.T) {
 	}
 }
 
+func TestCLI_handleUpdateCommand_VersionConflict(t *testing.T) {
+	conflict := &client.APIError{StatusCode: http.StatusConflict, Message: "task was changed by another request, reload it and try again"}
+	newMock := func() *MockTaskClient {
+		return &MockTaskClient{
+			getTaskResults: []*client.Task{
+				{ID: 1, Description: "Old description", Version: 2},
+				{ID: 1, Description: "Changed elsewhere", Done: true, Version: 3},
+			},
+			updateTaskErrs:   []error{conflict},
+			updateTaskResult: &client.Task{ID: 1, Description: "New description", Done: true, Version: 4},
+		}
+	}
+
+	t.Run("refreshes the task and retries with its version", func(t *testing.T) {
+		output := &bytes.Buffer{}
+		mockClient := newMock()
+		cli := NewCLI(NewMockInputReader("1", "New description", "y"), output, &Config{}, mockClient, &MockAuthManager{})
+
+		err := cli.handleUpdateCommand(context.Background())
+
+		require.NoError(t, err)
+		require.Len(t, mockClient.updateTaskVersions, 2)
+		assert.Equal(t, 2, *mockClient.updateTaskVersions[0])
+		assert.Equal(t, 3, *mockClient.updateTaskVersions[1])
+		assert.Contains(t, output.String(), "was changed by someone else")
+		assert.Contains(t, output.String(), "Changed elsewhere")
+		assert.Contains(t, output.String(), "✅ Task (ID: 1) updated")
+		require.Len(t, cli.operationLog, 1)
+		assert.Equal(t, "Changed elsewhere", cli.operationLog[0].Before.Description)
+	})
+
+	t.Run("returns the conflict when the user declines", func(t *testing.T) {
+		output := &bytes.Buffer{}
+		mockClient := newMock()
+		cli := NewCLI(NewMockInputReader("1", "New description", "n"), output, &Config{}, mockClient, &MockAuthManager{})
+
+		err := cli.handleUpdateCommand(context.Background())
+
+		assert.True(t, client.IsVersionConflict(err))
+		assert.Len(t, mockClient.updateTaskVersions, 1)
+		assert.NotContains(t, output.String(), "✅")
+	})
+}
+
 // TestCLI_handleDeleteCommand tests the handleDeleteCommand method
 func TestCLI_handleDeleteCommand(t *testing.T) {
 	// ====Arrange====
@@ -1487,7 +1891,7 @@ func TestCLI_handleDeleteCommand(t *testing.T) {
 			)
 
 			// ====Act====
-			err := cli.handleDeleteCommand()
+			err := cli.handleDeleteCommand(context.Background(), nil)
 
 			// ====Assert====
 			if tc.expectedErr != nil {
@@ -1531,81 +1935,1568 @@ func TestCLI_handleDeleteCommand(t *testing.T) {
 	}
 }
 
-// TestCLI_handleListCommand tests the handleListCommand method
-func TestCLI_handleListCommand(t *testing.T) {
+func TestCLI_handleDeleteCommand_Permanent(t *testing.T) {
 	// ====Arrange====
 	testCases := []struct {
 		name             string
-		getTasksResult   []client.Task
-		getTasksErr      error
+		args             []string
+		expectedPurgeID  int
+		expectedContains string
 		expectedErr      error
-		expectedContains []string
+	}{
+		{name: "Soft delete by default", args: nil, expectedContains: "✅ Task (ID: 7) deleted, use 'restore' to undo"},
+		{name: "Permanent flag purges", args: []string{"--permanent"}, expectedPurgeID: 7, expectedContains: "✅ Task (ID: 7) permanently deleted"},
+		{name: "Unknown flag", args: []string{"--force"}, expectedErr: ErrInvalidArguments},
+		{name: "Stray argument", args: []string{"7"}, expectedErr: ErrInvalidArguments},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			output := &bytes.Buffer{}
+			mockClient := &MockTaskClient{getTaskResult: &client.Task{ID: 7, Description: "Task to delete"}}
+			inputReader := NewMockInputReader("7", "y")
+			cli := NewCLI(
+				inputReader,
+				output,
+				&Config{ServerURL: "http://localhost:8080"},
+				mockClient,
+				&MockAuthManager{loadTokenResult: "mock-token"},
+			)
+
+			// ====Act====
+			err := cli.handleDeleteCommand(context.Background(), tc.args)
+
+			// ====Assert====
+			if tc.expectedErr != nil {
+				assert.ErrorIs(t, err, tc.expectedErr)
+				assert.Equal(t, 0, inputReader.index, "No input should be read")
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expectedPurgeID, mockClient.purgeTaskID)
+			assert.Contains(t, output.String(), tc.expectedContains)
+		})
+	}
+}
+
+// TestCLI_handleToggleCommand tests the handleToggleCommand method
+func TestCLI_handleToggleCommand(t *testing.T) {
+	// ====Arrange====
+	testCases := []struct {
+		name             string
+		input            string
+		toggleTaskResult *client.Task
+		toggleTaskErr    error
+		expectedID       int
+		expectedErr      error
+		expectedContains string
 	}{
 		{
-			name: "Successfully list multiple tasks",
-			getTasksResult: []client.Task{
-				{ID: 1, Description: "Buy groceries", Done: false},
-				{ID: 2, Description: "Clean room", Done: true},
-				{ID: 3, Description: "Write report", Done: false},
-			},
-			getTasksErr: nil,
-			expectedErr: nil,
-			expectedContains: []string{
-				"=== Your Tasks ===",
-				"[ ] 1: Buy groceries",
-				"[✓] 2: Clean room",
-				"[ ] 3: Write report",
-				"==================",
-			},
-		},
-		{
-			name: "Successfully list single task",
-			getTasksResult: []client.Task{
-				{ID: 1, Description: "Single task", Done: false},
-			},
-			getTasksErr: nil,
-			expectedErr: nil,
-			expectedContains: []string{
-				"=== Your Tasks ===",
-				"[ ] 1: Single task",
-				"==================",
-			},
+			name:             "Toggles task status",
+			input:            "3",
+			toggleTaskResult: &client.Task{ID: 3, Description: "Buy milk", Done: true},
+			expectedID:       3,
+			expectedContains: "✅ Task toggled: [✓] 3: Buy milk",
 		},
 		{
-			name: "List completed tasks only",
-			getTasksResult: []client.Task{
-				{ID: 5, Description: "Completed task 1", Done: true},
-				{ID: 10, Description: "Completed task 2", Done: true},
-			},
-			getTasksErr: nil,
-			expectedErr: nil,
-			expectedContains: []string{
-				"=== Your Tasks ===",
-				"[✓] 5: Completed task 1",
-				"[✓] 10: Completed task 2",
-				"==================",
-			},
+			name:        "Invalid task ID",
+			input:       "abc",
+			expectedErr: validation.ErrInvalidTaskID,
 		},
 		{
-			name: "List tasks with empty descriptions",
-			getTasksResult: []client.Task{
-				{ID: 1, Description: "", Done: false},
-				{ID: 2, Description: "Normal task", Done: true},
-			},
-			getTasksErr: nil,
-			expectedErr: nil,
-			expectedContains: []string{
-				"=== Your Tasks ===",
-				"[ ] 1: ",
-				"[✓] 2: Normal task",
-				"==================",
-			},
+			name:          "Task not found",
+			input:         "4",
+			toggleTaskErr: &client.APIError{StatusCode: 404, Message: "task not found"},
+			expectedID:    4,
+			expectedErr:   &client.APIError{},
 		},
-		{
-			name: "List tasks with long descriptions",
-			getTasksResult: []client.Task{
-				{ID: 1, Description: "This is a very long task description that should be displayed completely without truncation", Done: false},
-				{ID: 2, Description: "Another long description with many words to test the display formatting", Done: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			output := &bytes.Buffer{}
+			mockClient := &MockTaskClient{toggleTaskResult: tc.toggleTaskResult, toggleTaskErr: tc.toggleTaskErr}
+			cli := NewCLI(
+				NewMockInputReader(tc.input),
+				output,
+				&Config{ServerURL: "http://localhost:8080"},
+				mockClient,
+				&MockAuthManager{loadTokenResult: "mock-token"},
+			)
+
+			// ====Act====
+			err := cli.handleToggleCommand(context.Background())
+
+			// ====Assert====
+			switch tc.expectedErr.(type) {
+			case nil:
+				assert.NoError(t, err)
+			case *client.APIError:
+				var apiErr *client.APIError
+				assert.ErrorAs(t, err, &apiErr)
+			default:
+				assert.ErrorIs(t, err, tc.expectedErr)
+			}
+			assert.Equal(t, tc.expectedID, mockClient.toggleTaskID)
+			assert.Contains(t, output.String(), tc.expectedContains)
+		})
+	}
+}
+
+// TestCLI_handleRestoreCommand tests the handleRestoreCommand method
+func TestCLI_handleRestoreCommand(t *testing.T) {
+	// ====Arrange====
+	testCases := []struct {
+		name              string
+		input             string
+		restoreTaskResult *client.Task
+		restoreTaskErr    error
+		expectedID        int
+		expectedErr       error
+		expectedContains  string
+	}{
+		{
+			name:              "Restores deleted task",
+			input:             "3",
+			restoreTaskResult: &client.Task{ID: 3, Description: "Buy milk"},
+			expectedID:        3,
+			expectedContains:  "✅ Task restored: [ ] 3: Buy milk",
+		},
+		{
+			name:        "Invalid task ID",
+			input:       "abc",
+			expectedErr: validation.ErrInvalidTaskID,
+		},
+		{
+			name:           "Task is not deleted",
+			input:          "4",
+			restoreTaskErr: &client.APIError{StatusCode: 404, Message: "Deleted task not found"},
+			expectedID:     4,
+			expectedErr:    &client.APIError{},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			output := &bytes.Buffer{}
+			mockClient := &MockTaskClient{restoreTaskResult: tc.restoreTaskResult, restoreTaskErr: tc.restoreTaskErr}
+			cli := NewCLI(
+				NewMockInputReader(tc.input),
+				output,
+				&Config{ServerURL: "http://localhost:8080"},
+				mockClient,
+				&MockAuthManager{loadTokenResult: "mock-token"},
+			)
+
+			// ====Act====
+			err := cli.handleRestoreCommand(context.Background())
+
+			// ====Assert====
+			switch tc.expectedErr.(type) {
+			case nil:
+				assert.NoError(t, err)
+			case *client.APIError:
+				var apiErr *client.APIError
+				assert.ErrorAs(t, err, &apiErr)
+			default:
+				assert.ErrorIs(t, err, tc.expectedErr)
+			}
+			assert.Equal(t, tc.expectedID, mockClient.restoreTaskID)
+			assert.Contains(t, output.String(), tc.expectedContains)
+		})
+	}
+}
+
+// TestCLI_handleArchiveCommand tests the handleArchiveCommand method
+func TestCLI_handleArchiveCommand(t *testing.T) {
+	// ====Arrange====
+	testCases := []struct {
+		name             string
+		args             []string
+		input            string
+		json             bool
+		mockClient       *MockTaskClient
+		expectedID       int
+		expectedAllCalls int
+		expectedErr      error
+		expectedContains string
+	}{
+		{
+			name:             "Archives task",
+			input:            "3",
+			mockClient:       &MockTaskClient{archiveTaskResult: &client.Task{ID: 3, Description: "Buy milk", Done: true}},
+			expectedID:       3,
+			expectedContains: "✅ Task archived: [✓] 3: Buy milk",
+		},
+		{
+			name:        "Invalid task ID",
+			input:       "abc",
+			mockClient:  &MockTaskClient{},
+			expectedErr: validation.ErrInvalidTaskID,
+		},
+		{
+			name:             "Archives completed tasks with --completed",
+			args:             []string{"--completed"},
+			mockClient:       &MockTaskClient{archiveAllResult: 4},
+			expectedAllCalls: 1,
+			expectedContains: "✅ Archived 4 completed tasks",
+		},
+		{
+			name:             "JSON reports archived count",
+			args:             []string{"--completed"},
+			json:             true,
+			mockClient:       &MockTaskClient{archiveAllResult: 2},
+			expectedAllCalls: 1,
+			expectedContains: `{"archived":2}`,
+		},
+		{
+			name:        "Unknown flag",
+			args:        []string{"--done"},
+			mockClient:  &MockTaskClient{},
+			expectedErr: ErrInvalidArguments,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			output := &bytes.Buffer{}
+			cli := NewCLI(
+				NewMockInputReader(tc.input),
+				output,
+				&Config{ServerURL: "http://localhost:8080", JSON: tc.json},
+				tc.mockClient,
+				&MockAuthManager{loadTokenResult: "mock-token"},
+			)
+
+			// ====Act====
+			err := cli.handleArchiveCommand(context.Background(), tc.args)
+
+			// ====Assert====
+			if tc.expectedErr != nil {
+				assert.ErrorIs(t, err, tc.expectedErr)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, tc.expectedID, tc.mockClient.archiveTaskID)
+			assert.Equal(t, tc.expectedAllCalls, tc.mockClient.archiveAllCalls)
+			assert.Contains(t, output.String(), tc.expectedContains)
+		})
+	}
+}
+
+// TestCLI_handleShowCommand tests the handleShowCommand method
+func TestCLI_handleShowCommand(t *testing.T) {
+	// ====Arrange====
+	created := time.Date(2025, 3, 1, 9, 30, 0, 0, time.UTC)
+	due := time.Date(2025, 3, 10, 23, 59, 59, 0, time.UTC)
+	testCases := []struct {
+		name             string
+		input            string
+		getTaskResult    *client.Task
+		getTaskErr       error
+		expectedErr      error
+		expectedContains []string
+	}{
+		{
+			name:  "Shows every field of the task",
+			input: "3",
+			getTaskResult: &client.Task{
+				ID: 3, Description: "Write the quarterly report", Priority: domain.PriorityMedium,
+				Tags: []string{"work", "q1"}, DueDate: &due, Version: 2, CreatedAt: created, UpdatedAt: created,
+			},
+			expectedContains: []string{
+				"[!] 3: Write the quarterly report #work #q1 (overdue since",
+				"Description: Write the quarterly report",
+				"Done:        no",
+				"Priority:    med",
+				"Due:         " + due.Local().Format(dueDateLayout),
+				"Tags:        work, q1",
+				"Created:     " + created.Local().Format(time.DateTime),
+				"Version:     2",
+			},
+		},
+		{
+			name:             "Shows completion time of done task",
+			input:            "4",
+			getTaskResult:    &client.Task{ID: 4, Description: "Buy milk", Done: true, CompletedAt: &created},
+			expectedContains: []string{"[✓] 4: Buy milk", "Done:        yes, completed " + created.Local().Format(time.DateTime)},
+		},
+		{
+			name:        "Invalid task ID",
+			input:       "abc",
+			expectedErr: validation.ErrInvalidTaskID,
+		},
+		{
+			name:             "Task not found",
+			input:            "5",
+			getTaskErr:       &client.APIError{StatusCode: 404, Message: "task not found"},
+			expectedContains: []string{"❌ Task 5 not found"},
+		},
+		{
+			name:        "Server error",
+			input:       "6",
+			getTaskErr:  &client.APIError{StatusCode: 500, Message: "internal error"},
+			expectedErr: &client.APIError{},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			output := &bytes.Buffer{}
+			cli := NewCLI(
+				NewMockInputReader(tc.input),
+				output,
+				&Config{ServerURL: "http://localhost:8080"},
+				&MockTaskClient{getTaskResult: tc.getTaskResult, getTaskErr: tc.getTaskErr},
+				&MockAuthManager{loadTokenResult: "mock-token"},
+			)
+
+			// ====Act====
+			err := cli.handleShowCommand(context.Background())
+
+			// ====Assert====
+			switch tc.expectedErr.(type) {
+			case nil:
+				assert.NoError(t, err)
+			case *client.APIError:
+				var apiErr *client.APIError
+				assert.ErrorAs(t, err, &apiErr)
+			default:
+				assert.ErrorIs(t, err, tc.expectedErr)
+			}
+			for _, want := range tc.expectedContains {
+				assert.Contains(t, output.String(), want)
+			}
+		})
+	}
+}
+
+// TestCLI_handleTagCommands tests the handleTagCommand and handleUntagCommand methods
+func TestCLI_handleTagCommands(t *testing.T) {
+	// ====Arrange====
+	testCases := []struct {
+		name             string
+		untag            bool
+		inputs           []string
+		tagResult        *client.Task
+		tagErr           error
+		expectedID       int
+		expectedTag      string
+		expectedErr      error
+		expectedContains string
+	}{
+		{
+			name:             "Tags task with normalized tag",
+			inputs:           []string{"3", "Work"},
+			tagResult:        &client.Task{ID: 3, Description: "Report", Tags: []string{"work"}},
+			expectedID:       3,
+			expectedTag:      "work",
+			expectedContains: "✅ Task tagged: [ ] 3: Report #work",
+		},
+		{
+			name:             "Removes tag from task",
+			untag:            true,
+			inputs:           []string{"3", "work"},
+			tagResult:        &client.Task{ID: 3, Description: "Report"},
+			expectedID:       3,
+			expectedTag:      "work",
+			expectedContains: "✅ Tag removed: [ ] 3: Report",
+		},
+		{
+			name:        "Invalid task ID",
+			inputs:      []string{"abc"},
+			expectedErr: validation.ErrInvalidTaskID,
+		},
+		{
+			name:        "Tag with a space",
+			inputs:      []string{"3", "two words"},
+			expectedErr: domain.ErrInvalidTag,
+		},
+		{
+			name:        "Task not found",
+			inputs:      []string{"9", "work"},
+			tagErr:      &client.APIError{StatusCode: 404, Message: "Task not found"},
+			expectedID:  9,
+			expectedTag: "work",
+			expectedErr: &client.APIError{},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			output := &bytes.Buffer{}
+			mockClient := &MockTaskClient{tagResult: tc.tagResult, tagErr: tc.tagErr}
+			cli := NewCLI(
+				NewMockInputReader(tc.inputs...),
+				output,
+				&Config{ServerURL: "http://localhost:8080"},
+				mockClient,
+				&MockAuthManager{loadTokenResult: "mock-token"},
+			)
+
+			// ====Act====
+			var err error
+			if tc.untag {
+				err = cli.handleUntagCommand(context.Background())
+			} else {
+				err = cli.handleTagCommand(context.Background())
+			}
+
+			// ====Assert====
+			switch tc.expectedErr.(type) {
+			case nil:
+				assert.NoError(t, err)
+			case *client.APIError:
+				var apiErr *client.APIError
+				assert.ErrorAs(t, err, &apiErr)
+			default:
+				assert.ErrorIs(t, err, tc.expectedErr)
+			}
+			assert.Equal(t, tc.expectedID, mockClient.tagTaskID)
+			assert.Equal(t, tc.expectedTag, mockClient.tagName)
+			assert.Contains(t, output.String(), tc.expectedContains)
+		})
+	}
+}
+
+// TestCLI_handleListCommand_Tag tests that list --tag fetches the tasks carrying the tag
+func TestCLI_handleListCommand_Tag(t *testing.T) {
+	// ====Arrange====
+	output := &bytes.Buffer{}
+	mockClient := &MockTaskClient{getTasksResult: []client.Task{{ID: 2, Description: "Report", Tags: []string{"work"}}}}
+	cli := NewCLI(
+		NewMockInputReader(),
+		output,
+		&Config{ServerURL: "http://localhost:8080"},
+		mockClient,
+		&MockAuthManager{loadTokenResult: "mock-token"},
+	)
+
+	// ====Act====
+	err := cli.handleListCommand(context.Background(), []string{"--tag", "work"})
+
+	// ====Assert====
+	require.NoError(t, err)
+	assert.Equal(t, "work", mockClient.tagFilter)
+	assert.Empty(t, mockClient.getTasksOffsets, "Listing by tag should not page")
+	assert.Contains(t, output.String(), "=== Tasks tagged 'work' ===")
+	assert.Contains(t, output.String(), "[ ] 2: Report #work")
+}
+
+func TestCLI_handleMarkAllCommand(t *testing.T) {
+	// ====Arrange====
+	testCases := []struct {
+		name           string
+		inputs         []string
+		markAllResult  int
+		markAllErr     error
+		expectedCalls  []bool
+		expectedOutput string
+		expectedErr    error
+	}{
+		{
+			name:           "Marks all tasks done",
+			inputs:         []string{"done", "y"},
+			markAllResult:  3,
+			expectedCalls:  []bool{true},
+			expectedOutput: "✅ Marked 3 tasks as done\n",
+		},
+		{
+			name:           "Marks all tasks undone",
+			inputs:         []string{"undone", "y"},
+			markAllResult:  1,
+			expectedCalls:  []bool{false},
+			expectedOutput: "✅ Marked 1 tasks as undone\n",
+		},
+		{
+			name:           "Canceled",
+			inputs:         []string{"done", "n"},
+			expectedOutput: "Status change canceled\n",
+		},
+		{
+			name:        "Invalid status",
+			inputs:      []string{"finished"},
+			expectedErr: ErrInvalidStatus,
+		},
+		{
+			name:          "Client error is wrapped",
+			inputs:        []string{"done", "y"},
+			markAllErr:    &client.APIError{StatusCode: 500, Message: "Server error"},
+			expectedCalls: []bool{true},
+			expectedErr:   &client.APIError{},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			output := &bytes.Buffer{}
+			mockClient := &MockTaskClient{markAllResult: tc.markAllResult, markAllErr: tc.markAllErr}
+			cli := NewCLI(
+				NewMockInputReader(tc.inputs...),
+				output,
+				&Config{ServerURL: "http://localhost:8080"},
+				mockClient,
+				&MockAuthManager{loadTokenResult: "mock-token"},
+			)
+
+			// ====Act====
+			err := cli.handleMarkAllCommand(context.Background())
+
+			// ====Assert====
+			switch tc.expectedErr.(type) {
+			case nil:
+				assert.NoError(t, err)
+			case *client.APIError:
+				var apiErr *client.APIError
+				assert.ErrorAs(t, err, &apiErr)
+			default:
+				assert.ErrorIs(t, err, tc.expectedErr)
+			}
+			assert.Equal(t, tc.expectedCalls, mockClient.markAllCalls)
+			assert.True(t, strings.HasSuffix(output.String(), tc.expectedOutput), "output %q", output.String())
+		})
+	}
+}
+
+func TestCLI_handleDeleteDoneCommand(t *testing.T) {
+	// ====Arrange====
+	tasks := []client.Task{
+		{ID: 1, Description: "Buy milk", Done: true},
+		{ID: 2, Description: "Walk the dog"},
+		{ID: 3, Description: "Call mom", Done: true},
+	}
+
+	testCases := []struct {
+		name              string
+		tasks             []client.Task
+		input             string
+		deleteTasksResult int
+		deleteTasksErr    error
+		expectedIDs       []int
+		expectedContains  []string
+		expectedErr       bool
+	}{
+		{
+			name:              "Deletes completed tasks after confirmation",
+			tasks:             tasks,
+			input:             "y",
+			deleteTasksResult: 2,
+			expectedIDs:       []int{1, 3},
+			expectedContains:  []string{"[✓] 1: Buy milk", "[✓] 3: Call mom", "Delete 2 completed tasks? y/N:", "✅ Deleted 2 completed tasks"},
+		},
+		{
+			name:              "Reports skipped tasks",
+			tasks:             tasks,
+			input:             "Y",
+			deleteTasksResult: 1,
+			expectedIDs:       []int{1, 3},
+			expectedContains:  []string{"✅ Deleted 1 completed tasks", "1 tasks were already gone and were skipped"},
+		},
+		{
+			name:             "Canceled",
+			tasks:            tasks,
+			input:            "n",
+			expectedContains: []string{"Deletion canceled"},
+		},
+		{
+			name:             "Nothing completed",
+			tasks:            tasks[1:2],
+			expectedContains: []string{"No completed tasks to delete"},
+		},
+		{
+			name:           "Client error is wrapped",
+			tasks:          tasks,
+			input:          "y",
+			deleteTasksErr: &client.APIError{StatusCode: 500, Message: "Server error"},
+			expectedIDs:    []int{1, 3},
+			expectedErr:    true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			output := &bytes.Buffer{}
+			mockClient := &MockTaskClient{
+				getTasksResult:    tc.tasks,
+				deleteTasksResult: tc.deleteTasksResult,
+				deleteTasksErr:    tc.deleteTasksErr,
+			}
+			cli := NewCLI(
+				NewMockInputReader(tc.input),
+				output,
+				&Config{ServerURL: "http://localhost:8080"},
+				mockClient,
+				&MockAuthManager{loadTokenResult: "mock-token"},
+			)
+
+			// ====Act====
+			err := cli.handleDeleteDoneCommand(context.Background())
+
+			// ====Assert====
+			if tc.expectedErr {
+				var apiErr *client.APIError
+				assert.ErrorAs(t, err, &apiErr)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, tc.expectedIDs, mockClient.deleteTasksIDs)
+			for _, expected := range tc.expectedContains {
+				assert.Contains(t, output.String(), expected)
+			}
+		})
+	}
+}
+
+func TestCLI_handleUndoCommand(t *testing.T) {
+	milk := client.Task{ID: 3, Description: "Buy milk"}
+
+	newCLI := func(output *bytes.Buffer, mockClient *MockTaskClient, inputs ...string) *CLI {
+		return NewCLI(
+			NewMockInputReader(inputs...),
+			output,
+			&Config{ServerURL: "http://localhost:8080"},
+			mockClient,
+			&MockAuthManager{loadTokenResult: "mock-token"},
+		)
+	}
+
+	t.Run("Reverts a status change", func(t *testing.T) {
+		// ====Arrange====
+		output := &bytes.Buffer{}
+		mockClient := &MockTaskClient{getTaskResult: &milk, updateTaskResult: &client.Task{ID: 3, Description: "Buy milk", Done: true}}
+		cli := newCLI(output, mockClient, "3", "done")
+		require.NoError(t, cli.handleStatusCommand(context.Background()))
+		mockClient.updateTaskResult = &milk
+
+		// ====Act====
+		err := cli.handleUndoCommand(context.Background())
+
+		// ====Assert====
+		require.NoError(t, err)
+		require.NotNil(t, mockClient.updateTaskDesc)
+		require.NotNil(t, mockClient.updateTaskDone)
+		assert.Equal(t, "Buy milk", *mockClient.updateTaskDesc)
+		assert.False(t, *mockClient.updateTaskDone)
+		assert.Contains(t, output.String(), "↩️  Undid status change: [ ] 3: Buy milk")
+		assert.Empty(t, cli.operationLog)
+	})
+
+	t.Run("Restores a deleted task", func(t *testing.T) {
+		// ====Arrange====
+		output := &bytes.Buffer{}
+		mockClient := &MockTaskClient{getTaskResult: &milk, restoreTaskResult: &milk}
+		cli := newCLI(output, mockClient, "3", "y")
+		require.NoError(t, cli.handleDeleteCommand(context.Background(), nil))
+
+		// ====Act====
+		err := cli.handleUndoCommand(context.Background())
+
+		// ====Assert====
+		require.NoError(t, err)
+		assert.Equal(t, 3, mockClient.restoreTaskID)
+		assert.Contains(t, output.String(), "↩️  Undid delete: [ ] 3: Buy milk")
+	})
+
+	t.Run("Permanent delete cannot be undone", func(t *testing.T) {
+		// ====Arrange====
+		output := &bytes.Buffer{}
+		mockClient := &MockTaskClient{getTaskResult: &milk}
+		cli := newCLI(output, mockClient, "3", "y")
+		require.NoError(t, cli.handleDeleteCommand(context.Background(), []string{"--permanent"}))
+
+		// ====Act====
+		err := cli.handleUndoCommand(context.Background())
+
+		// ====Assert====
+		require.NoError(t, err)
+		assert.Contains(t, output.String(), "Nothing to undo")
+		assert.Zero(t, mockClient.restoreTaskID)
+	})
+
+	t.Run("Failed undo is kept for a retry", func(t *testing.T) {
+		// ====Arrange====
+		output := &bytes.Buffer{}
+		mockClient := &MockTaskClient{updateTaskErr: &client.APIError{StatusCode: 500, Message: "Server error"}}
+		cli := newCLI(output, mockClient)
+		cli.recordUndo(undoableOp{Name: "update", Before: milk})
+
+		// ====Act====
+		err := cli.handleUndoCommand(context.Background())
+
+		// ====Assert====
+		var apiErr *client.APIError
+		assert.ErrorAs(t, err, &apiErr)
+		assert.Len(t, cli.operationLog, 1)
+	})
+
+	t.Run("History keeps the most recent changes", func(t *testing.T) {
+		// ====Arrange====
+		cli := newCLI(&bytes.Buffer{}, &MockTaskClient{})
+
+		// ====Act====
+		for id := 1; id <= maxUndoHistory+2; id++ {
+			cli.recordUndo(undoableOp{Name: "update", Before: client.Task{ID: id}})
+		}
+
+		// ====Assert====
+		require.Len(t, cli.operationLog, maxUndoHistory)
+		assert.Equal(t, 3, cli.operationLog[0].Before.ID)
+		assert.Equal(t, maxUndoHistory+2, cli.operationLog[maxUndoHistory-1].Before.ID)
+	})
+}
+
+// TestCLI_handleSearchCommand tests the handleSearchCommand method
+func TestCLI_handleSearchCommand(t *testing.T) {
+	// ====Arrange====
+	testCases := []struct {
+		name             string
+		input            string
+		searchResult     []client.Task
+		searchErr        error
+		expectedQuery    string
+		expectedErr      error
+		expectedContains []string
+	}{
+		{
+			name:  "Prints matching tasks",
+			input: "  milk ",
+			searchResult: []client.Task{
+				{ID: 1, Description: "Buy milk"},
+				{ID: 4, Description: "Milk the cow", Done: true},
+			},
+			expectedQuery:    "milk",
+			expectedContains: []string{"=== Tasks matching 'milk' ===", "[ ] 1: Buy milk", "[✓] 4: Milk the cow"},
+		},
+		{
+			name:             "Reports no matches",
+			input:            "dragon",
+			expectedQuery:    "dragon",
+			expectedContains: []string{"No tasks matching 'dragon'"},
+		},
+		{
+			name:        "Empty keyword",
+			input:       "",
+			expectedErr: ErrEmptyInput,
+		},
+		{
+			name:          "Client error is wrapped",
+			input:         "milk",
+			searchErr:     &client.APIError{StatusCode: 500, Message: "Server error"},
+			expectedQuery: "milk",
+			expectedErr:   &client.APIError{},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			output := &bytes.Buffer{}
+			mockClient := &MockTaskClient{searchResult: tc.searchResult, searchErr: tc.searchErr}
+			cli := NewCLI(
+				NewMockInputReader(tc.input),
+				output,
+				&Config{ServerURL: "http://localhost:8080"},
+				mockClient,
+				&MockAuthManager{loadTokenResult: "mock-token"},
+			)
+
+			// ====Act====
+			err := cli.handleSearchCommand(context.Background())
+
+			// ====Assert====
+			switch tc.expectedErr.(type) {
+			case nil:
+				assert.NoError(t, err)
+			case *client.APIError:
+				var apiErr *client.APIError
+				assert.ErrorAs(t, err, &apiErr)
+			default:
+				assert.ErrorIs(t, err, tc.expectedErr)
+			}
+			assert.Equal(t, tc.expectedQuery, mockClient.searchQuery)
+			for _, expected := range tc.expectedContains {
+				assert.Contains(t, output.String(), expected)
+			}
+		})
+	}
+}
+
+func TestCLI_handleStatsCommand(t *testing.T) {
+	// ====Arrange====
+	testCases := []struct {
+		name           string
+		statsResult    *client.Stats
+		statsErr       error
+		expectedOutput string
+		expectedErr    bool
+	}{
+		{
+			name:           "Prints counts and completion",
+			statsResult:    &client.Stats{Total: 12, Done: 5, Pending: 7},
+			expectedOutput: "12 total, 5 done, 7 pending (42% complete)\n",
+		},
+		{
+			name:           "No tasks",
+			statsResult:    &client.Stats{},
+			expectedOutput: "0 total, 0 done, 0 pending (0% complete)\n",
+		},
+		{
+			name:        "Client error is wrapped",
+			statsErr:    &client.APIError{StatusCode: 500, Message: "Server error"},
+			expectedErr: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			output := &bytes.Buffer{}
+			mockClient := &MockTaskClient{statsResult: tc.statsResult, statsErr: tc.statsErr}
+			cli := NewCLI(
+				NewMockInputReader(""),
+				output,
+				&Config{ServerURL: "http://localhost:8080"},
+				mockClient,
+				&MockAuthManager{loadTokenResult: "mock-token"},
+			)
+
+			// ====Act====
+			err := cli.handleStatsCommand(context.Background())
+
+			// ====Assert====
+			if tc.expectedErr {
+				var apiErr *client.APIError
+				assert.ErrorAs(t, err, &apiErr)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, tc.expectedOutput, output.String())
+		})
+	}
+}
+
+func TestCLI_handleCountCommand(t *testing.T) {
+	// ====Arrange====
+	serverErr := &client.APIError{StatusCode: 500, Message: "Server error"}
+	testCases := []struct {
+		name           string
+		args           []string
+		json           bool
+		mockClient     *MockTaskClient
+		expectedOutput string
+		expectedErr    error
+	}{
+		{
+			name:           "Prints total",
+			mockClient:     &MockTaskClient{statsResult: &client.Stats{Total: 12, Done: 5, Pending: 7}},
+			expectedOutput: "12\n",
+		},
+		{
+			name:           "Prints done count with --done",
+			args:           []string{"--done"},
+			mockClient:     &MockTaskClient{statsResult: &client.Stats{Total: 12, Done: 5, Pending: 7}},
+			expectedOutput: "5\n",
+		},
+		{
+			name:           "JSON has both counts",
+			args:           []string{"--done"},
+			json:           true,
+			mockClient:     &MockTaskClient{statsResult: &client.Stats{Total: 12, Done: 5, Pending: 7}},
+			expectedOutput: `{"total":12,"done":5}`,
+		},
+		{
+			name: "Counts the task list without stats endpoint",
+			args: []string{"--done"},
+			mockClient: &MockTaskClient{
+				statsErr:       &client.APIError{StatusCode: http.StatusNotImplemented, Message: "task statistics is not supported over gRPC"},
+				getTasksResult: []client.Task{{ID: 1, Done: true}, {ID: 2}, {ID: 3, Done: true}},
+			},
+			expectedOutput: "2\n",
+		},
+		{
+			name:        "Server error is not hidden by the fallback",
+			mockClient:  &MockTaskClient{statsErr: serverErr},
+			expectedErr: serverErr,
+		},
+		{
+			name:        "Unknown flag",
+			args:        []string{"--pending"},
+			mockClient:  &MockTaskClient{},
+			expectedErr: ErrInvalidArguments,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			output := &bytes.Buffer{}
+			cli := NewCLI(
+				NewMockInputReader(""),
+				output,
+				&Config{ServerURL: "http://localhost:8080", JSON: tc.json},
+				tc.mockClient,
+				&MockAuthManager{loadTokenResult: "mock-token"},
+			)
+
+			// ====Act====
+			err := cli.handleCountCommand(context.Background(), tc.args)
+
+			// ====Assert====
+			if tc.expectedErr != nil {
+				assert.ErrorIs(t, err, tc.expectedErr)
+				return
+			}
+			assert.NoError(t, err)
+			if tc.json {
+				assert.JSONEq(t, tc.expectedOutput, output.String())
+			} else {
+				assert.Equal(t, tc.expectedOutput, output.String())
+			}
+		})
+	}
+}
+
+func TestFormatStats(t *testing.T) {
+	testCases := []struct {
+		stats    client.Stats
+		expected string
+	}{
+		{stats: client.Stats{}, expected: "0 total, 0 done, 0 pending (0% complete)"},
+		{stats: client.Stats{Total: 3, Done: 3}, expected: "3 total, 3 done, 0 pending (100% complete)"},
+		{stats: client.Stats{Total: 3, Done: 1, Pending: 2}, expected: "3 total, 1 done, 2 pending (33% complete)"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.expected, func(t *testing.T) {
+			assert.Equal(t, tc.expected, formatStats(tc.stats))
+		})
+	}
+}
+
+// TestExportTasks tests the exportTasks function
+func TestExportTasks(t *testing.T) {
+	// ====Arrange====
+	tasks := []client.Task{
+		{ID: 1, Description: "Buy milk"},
+		{ID: 2, Description: "Call mom, then dad", Done: true},
+	}
+
+	testCases := []struct {
+		name        string
+		tasks       []client.Task
+		format      string
+		expected    string
+		expectedErr error
+	}{
+		{
+			name:     "CSV with header and rows",
+			tasks:    tasks,
+			format:   "csv",
+			expected: "id,description,done\n1,Buy milk,false\n2,\"Call mom, then dad\",true\n",
+		},
+		{
+			name:     "Empty CSV writes only the header",
+			format:   "csv",
+			expected: "id,description,done\n",
+		},
+		{
+			name:     "Empty JSON writes an empty array",
+			format:   "json",
+			expected: "[]\n",
+		},
+		{
+			name:        "Unknown format",
+			tasks:       tasks,
+			format:      "xml",
+			expectedErr: ErrUnsupportedFormat,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			buf := &bytes.Buffer{}
+
+			// ====Act====
+			err := exportTasks(buf, tc.tasks, tc.format)
+
+			// ====Assert====
+			assert.ErrorIs(t, err, tc.expectedErr)
+			if tc.expectedErr == nil {
+				assert.Equal(t, tc.expected, buf.String())
+			}
+		})
+	}
+
+	t.Run("JSON is an indented array that round-trips", func(t *testing.T) {
+		buf := &bytes.Buffer{}
+
+		// ====Act====
+		err := exportTasks(buf, tasks, "json")
+
+		// ====Assert====
+		assert.NoError(t, err)
+		assert.True(t, strings.HasPrefix(buf.String(), "[\n  {\n    \"id\": 1,"), "got: %s", buf.String())
+		var decoded []client.Task
+		assert.NoError(t, json.Unmarshal(buf.Bytes(), &decoded))
+		assert.Equal(t, tasks, decoded)
+	})
+}
+
+// TestFileFormat tests the fileFormat function
+func TestFileFormat(t *testing.T) {
+	// ====Arrange====
+	testCases := []struct {
+		name           string
+		path           string
+		expectedFormat string
+		expectedErr    error
+	}{
+		{name: "JSON", path: "backup/tasks.json", expectedFormat: "json"},
+		{name: "CSV", path: "tasks.csv", expectedFormat: "csv"},
+		{name: "Extension is case insensitive", path: "TASKS.CSV", expectedFormat: "csv"},
+		{name: "Unknown extension", path: "tasks.txt", expectedErr: ErrUnsupportedFormat},
+		{name: "No extension", path: "tasks", expectedErr: ErrUnsupportedFormat},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// ====Act====
+			format, err := fileFormat(tc.path)
+
+			// ====Assert====
+			assert.ErrorIs(t, err, tc.expectedErr)
+			assert.Equal(t, tc.expectedFormat, format)
+		})
+	}
+}
+
+// TestCLI_handleExportCommand tests the handleExportCommand method
+func TestCLI_handleExportCommand(t *testing.T) {
+	t.Run("Writes every page of tasks to the file", func(t *testing.T) {
+		// ====Arrange====
+		tasks := make([]client.Task, mockPageSize+2)
+		for i := range tasks {
+			tasks[i] = client.Task{ID: i + 1, Description: fmt.Sprintf("Task %d", i+1)}
+		}
+		path := filepath.Join(t.TempDir(), "tasks.csv")
+		output := &bytes.Buffer{}
+		mockClient := &MockTaskClient{getTasksResult: tasks}
+		cli := NewCLI(
+			NewMockInputReader(path),
+			output,
+			&Config{ServerURL: "http://localhost:8080"},
+			mockClient,
+			&MockAuthManager{loadTokenResult: "mock-token"},
+		)
+
+		// ====Act====
+		err := cli.handleExportCommand(context.Background())
+
+		// ====Assert====
+		assert.NoError(t, err)
+		assert.Equal(t, []int{0, mockPageSize}, mockClient.getTasksOffsets)
+		assert.Equal(t, "id", mockClient.getTasksQuery.Sort)
+		assert.Contains(t, output.String(), fmt.Sprintf("✅ Exported %d tasks to %s", len(tasks), path))
+
+		data, err := os.ReadFile(path)
+		assert.NoError(t, err)
+		lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+		assert.Len(t, lines, len(tasks)+1)
+		assert.Equal(t, "12,Task 12,false", lines[len(lines)-1])
+	})
+
+	testCases := []struct {
+		name        string
+		input       string
+		getTasksErr error
+		expectedErr error
+		expectFetch bool
+	}{
+		{
+			name:        "Unknown extension is rejected before fetching",
+			input:       "tasks.txt",
+			expectedErr: ErrUnsupportedFormat,
+		},
+		{
+			name:        "Empty path",
+			input:       "",
+			expectedErr: ErrEmptyInput,
+		},
+		{
+			name:        "Client error is wrapped",
+			input:       "tasks.json",
+			getTasksErr: &client.NetworkError{URL: "http://localhost:8080", Err: errors.New("connection refused")},
+			expectFetch: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// ====Arrange====
+			input := tc.input
+			if input != "" {
+				input = filepath.Join(t.TempDir(), input)
+			}
+			mockClient := &MockTaskClient{getTasksErr: tc.getTasksErr}
+			cli := NewCLI(
+				NewMockInputReader(input),
+				&bytes.Buffer{},
+				&Config{ServerURL: "http://localhost:8080"},
+				mockClient,
+				&MockAuthManager{loadTokenResult: "mock-token"},
+			)
+
+			// ====Act====
+			err := cli.handleExportCommand(context.Background())
+
+			// ====Assert====
+			assert.Error(t, err)
+			if tc.expectedErr != nil {
+				assert.ErrorIs(t, err, tc.expectedErr)
+			}
+			if tc.getTasksErr != nil {
+				var netErr *client.NetworkError
+				assert.ErrorAs(t, err, &netErr)
+			}
+			assert.Equal(t, tc.expectFetch, len(mockClient.getTasksOffsets) > 0)
+			if input != "" {
+				_, statErr := os.Stat(input)
+				assert.True(t, os.IsNotExist(statErr), "no file should be created on failure")
+			}
+		})
+	}
+}
+
+// TestParseImportFile tests the parseImportFile function
+func TestParseImportFile(t *testing.T) {
+	// ====Arrange====
+	testCases := []struct {
+		name                 string
+		content              string
+		format               string
+		expectedDescriptions []string
+		expectedErr          error
+	}{
+		{
+			name:                 "JSON array from export",
+			content:              `[{"id": 1, "description": "Buy milk", "done": false}, {"description": "Call mom"}]`,
+			format:               "json",
+			expectedDescriptions: []string{"Buy milk", "Call mom"},
+		},
+		{
+			name:                 "CSV with export header",
+			content:              "id,description,done\n1,Buy milk,false\n2,\"Call mom, then dad\",true\n",
+			format:               "csv",
+			expectedDescriptions: []string{"Buy milk", "Call mom, then dad"},
+		},
+		{
+			name:                 "CSV without header uses first column",
+			content:              "Buy milk\nCall mom\n",
+			format:               "csv",
+			expectedDescriptions: []string{"Buy milk", "Call mom"},
+		},
+		{
+			name:                 "CSV row missing the description column",
+			content:              "id,description\n1\n",
+			format:               "csv",
+			expectedDescriptions: []string{""},
+		},
+		{
+			name:    "Empty CSV",
+			content: "",
+			format:  "csv",
+		},
+		{
+			name:        "Malformed JSON",
+			content:     `{"description": "not an array"}`,
+			format:      "json",
+			expectedErr: &json.UnmarshalTypeError{},
+		},
+		{
+			name:        "Unknown format",
+			content:     "Buy milk",
+			format:      "txt",
+			expectedErr: ErrUnsupportedFormat,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// ====Act====
+			descriptions, err := parseImportFile(strings.NewReader(tc.content), tc.format)
+
+			// ====Assert====
+			switch tc.expectedErr.(type) {
+			case nil:
+				assert.NoError(t, err)
+			case *json.UnmarshalTypeError:
+				var typeErr *json.UnmarshalTypeError
+				assert.ErrorAs(t, err, &typeErr)
+			default:
+				assert.ErrorIs(t, err, tc.expectedErr)
+			}
+			assert.Equal(t, tc.expectedDescriptions, descriptions)
+		})
+	}
+}
+
+// TestCLI_handleImportCommand tests the handleImportCommand method
+func TestCLI_handleImportCommand(t *testing.T) {
+	// ====Arrange====
+	testCases := []struct {
+		name             string
+		fileName         string
+		content          string
+		createTasksErr   error
+		expectedBatch    []string
+		expectedOneByOne []string
+		expectedErr      error
+		expectedContains []string
+	}{
+		{
+			name:             "Imports every valid entry in one batch",
+			fileName:         "tasks.csv",
+			content:          "id,description,done\n1,Buy milk,false\n2,Call mom,true\n",
+			expectedBatch:    []string{"Buy milk", "Call mom"},
+			expectedContains: []string{"✅ Imported 2 tasks (0 skipped)"},
+		},
+		{
+			name:          "Invalid entries are skipped without aborting",
+			fileName:      "tasks.json",
+			content:       `[{"description": "Buy milk"}, {"description": ""}, {"description": "` + strings.Repeat("a", 201) + `"}]`,
+			expectedBatch: []string{"Buy milk"},
+			expectedContains: []string{
+				"⚠️  Skipping entry 2:",
+				"⚠️  Skipping entry 3:",
+				"✅ Imported 1 tasks (2 skipped)",
+			},
+		},
+		{
+			name:             "Nothing to import",
+			fileName:         "tasks.csv",
+			content:          "description\n",
+			expectedContains: []string{"No tasks imported (0 skipped)"},
+		},
+		{
+			name:             "Falls back to one by one without batch endpoint",
+			fileName:         "tasks.csv",
+			content:          "Buy milk\nCall mom\n",
+			createTasksErr:   &client.APIError{StatusCode: 404, Message: "404 page not found"},
+			expectedBatch:    []string{"Buy milk", "Call mom"},
+			expectedOneByOne: []string{"Buy milk", "Call mom"},
+			expectedContains: []string{"✅ Imported 2 tasks (0 skipped)"},
+		},
+		{
+			name:             "Falls back to one by one when the transport has no batch create",
+			fileName:         "tasks.csv",
+			content:          "Buy milk\n",
+			createTasksErr:   &client.APIError{StatusCode: 501, Message: "batch task creation is not supported over gRPC"},
+			expectedBatch:    []string{"Buy milk"},
+			expectedOneByOne: []string{"Buy milk"},
+			expectedContains: []string{"✅ Imported 1 tasks (0 skipped)"},
+		},
+		{
+			name:           "Batch failure is reported",
+			fileName:       "tasks.csv",
+			content:        "Buy milk\n",
+			createTasksErr: &client.APIError{StatusCode: 500, Message: "Server error"},
+			expectedBatch:  []string{"Buy milk"},
+			expectedErr:    &client.APIError{},
+		},
+		{
+			name:        "Unknown extension",
+			fileName:    "tasks.txt",
+			content:     "Buy milk\n",
+			expectedErr: ErrUnsupportedFormat,
+		},
+		{
+			name:        "Missing file",
+			fileName:    "missing.csv",
+			expectedErr: os.ErrNotExist,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), tc.fileName)
+			if tc.content != "" {
+				assert.NoError(t, os.WriteFile(path, []byte(tc.content), 0o600))
+			}
+			output := &bytes.Buffer{}
+			mockClient := &MockTaskClient{createTasksErr: tc.createTasksErr}
+			cli := NewCLI(
+				NewMockInputReader(path),
+				output,
+				&Config{ServerURL: "http://localhost:8080"},
+				mockClient,
+				&MockAuthManager{loadTokenResult: "mock-token"},
+			)
+
+			// ====Act====
+			err := cli.handleImportCommand(context.Background())
+
+			// ====Assert====
+			switch tc.expectedErr.(type) {
+			case nil:
+				assert.NoError(t, err)
+			case *client.APIError:
+				var apiErr *client.APIError
+				assert.ErrorAs(t, err, &apiErr)
+			default:
+				assert.ErrorIs(t, err, tc.expectedErr)
+			}
+			assert.Equal(t, tc.expectedBatch, mockClient.createTasksBatch)
+			assert.Equal(t, tc.expectedOneByOne, mockClient.createTaskDescs)
+			for _, expected := range tc.expectedContains {
+				assert.Contains(t, output.String(), expected)
+			}
+		})
+	}
+}
+
+// TestCLI_handleListCommand_Pagination tests that list asks before fetching each further page
+func TestCLI_handleListCommand_Pagination(t *testing.T) {
+	// ====Arrange====
+	tasks := make([]client.Task, mockPageSize+2)
+	for i := range tasks {
+		tasks[i] = client.Task{ID: i + 1, Description: fmt.Sprintf("task %d", i+1)}
+	}
+
+	testCases := []struct {
+		name             string
+		inputs           []string
+		expectedOffsets  []int
+		expectedContains []string
+		notContains      []string
+	}{
+		{
+			name:             "Shows next page when confirmed",
+			inputs:           []string{"y"},
+			expectedOffsets:  []int{0, mockPageSize},
+			expectedContains: []string{"Showing 1-10 of 12", "Show next page? y/N:", "[ ] 12: task 12", "Showing 11-12 of 12"},
+		},
+		{
+			name:             "Stops after first page when declined",
+			inputs:           []string{"n"},
+			expectedOffsets:  []int{0},
+			expectedContains: []string{"[ ] 10: task 10", "Showing 1-10 of 12"},
+			notContains:      []string{"[ ] 11: task 11"},
+		},
+		{
+			name:             "Stops after first page on empty answer",
+			inputs:           []string{""},
+			expectedOffsets:  []int{0},
+			expectedContains: []string{"Showing 1-10 of 12"},
+			notContains:      []string{"[ ] 11: task 11"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			output := &bytes.Buffer{}
+			mockClient := &MockTaskClient{getTasksResult: tasks}
+			cli := NewCLI(
+				NewMockInputReader(tc.inputs...),
+				output,
+				&Config{ServerURL: "http://localhost:8080"},
+				mockClient,
+				&MockAuthManager{loadTokenResult: "mock-token"},
+			)
+
+			// ====Act====
+			err := cli.handleListCommand(context.Background(), nil)
+
+			// ====Assert====
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expectedOffsets, mockClient.getTasksOffsets)
+			result := output.String()
+			for _, expected := range tc.expectedContains {
+				assert.Contains(t, result, expected)
+			}
+			for _, unexpected := range tc.notContains {
+				assert.NotContains(t, result, unexpected)
+			}
+		})
+	}
+}
+
+// TestCLI_handleListCommand_Sort tests that list forwards a valid --sort value and rejects bad arguments
+func TestCLI_handleListCommand_Sort(t *testing.T) {
+	// ====Arrange====
+	testCases := []struct {
+		name                    string
+		args                    []string
+		expectedSort            string
+		expectedTag             string
+		expectedIncludeArchived bool
+		expectedErr             error
+	}{
+		{name: "No arguments keeps server order", args: nil, expectedSort: ""},
+		{name: "Tag filter is normalized", args: []string{"--tag", "Work"}, expectedTag: "work"},
+		{name: "Tag with archived tasks", args: []string{"--tag", "work", "--include-archived"}, expectedTag: "work", expectedIncludeArchived: true},
+		{name: "Invalid tag", args: []string{"--tag", "a,b"}, expectedErr: domain.ErrInvalidTag},
+		{name: "Empty tag", args: []string{"--tag="}, expectedErr: domain.ErrInvalidTag},
+		{name: "Sort with tag", args: []string{"--sort", "id", "--tag", "work"}, expectedErr: ErrInvalidArguments},
+		{name: "Descending sort", args: []string{"--sort", "-created"}, expectedSort: "-created"},
+		{name: "Sort with equals sign", args: []string{"--sort=done"}, expectedSort: "done"},
+		{name: "Unknown sort key", args: []string{"--sort", "color"}, expectedErr: validation.ErrInvalidSort},
+		{name: "Unknown flag", args: []string{"--order", "id"}, expectedErr: ErrInvalidArguments},
+		{name: "Stray argument", args: []string{"everything"}, expectedErr: ErrInvalidArguments},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			mockClient := &MockTaskClient{getTasksResult: []client.Task{{ID: 1, Description: "task 1"}}}
+			cli := NewCLI(
+				NewMockInputReader(),
+				&bytes.Buffer{},
+				&Config{ServerURL: "http://localhost:8080"},
+				mockClient,
+				&MockAuthManager{loadTokenResult: "mock-token"},
+			)
+
+			// ====Act====
+			err := cli.handleListCommand(context.Background(), tc.args)
+
+			// ====Assert====
+			if tc.expectedErr != nil {
+				assert.ErrorIs(t, err, tc.expectedErr)
+				assert.Empty(t, mockClient.getTasksOffsets, "No request should be sent")
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expectedSort, mockClient.getTasksQuery.Sort)
+			assert.Equal(t, tc.expectedTag, mockClient.tagFilter)
+			assert.Equal(t, tc.expectedIncludeArchived, mockClient.tagIncludeArchived)
+		})
+	}
+}
+
+// TestCLI_handleListCommand_CreatedRange tests that list forwards a valid creation time range and rejects bad ones
+func TestCLI_handleListCommand_CreatedRange(t *testing.T) {
+	// ====Arrange====
+	after := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	before := time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC)
+	testCases := []struct {
+		name          string
+		args          []string
+		expectedQuery client.ListQuery
+		expectedErr   error
+	}{
+		{
+			name:          "Both bounds",
+			args:          []string{"--created-after", "2025-01-01T00:00:00Z", "--created-before", "2025-02-01T00:00:00Z"},
+			expectedQuery: client.ListQuery{CreatedAfter: &after, CreatedBefore: &before},
+		},
+		{
+			name:          "Lower bound with sort",
+			args:          []string{"--created-after=2025-01-01T00:00:00Z", "--sort", "-created"},
+			expectedQuery: client.ListQuery{Sort: "-created", CreatedAfter: &after},
+		},
+		{name: "Date without time", args: []string{"--created-after", "2025-01-01"}, expectedErr: validation.ErrInvalidCreatedAfter},
+		{name: "Reversed range", args: []string{"--created-after", "2025-02-01T00:00:00Z", "--created-before", "2025-01-01T00:00:00Z"}, expectedErr: validation.ErrInvalidCreatedRange},
+		{name: "Range with tag", args: []string{"--tag", "work", "--created-before", "2025-02-01T00:00:00Z"}, expectedErr: ErrInvalidArguments},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			mockClient := &MockTaskClient{getTasksResult: []client.Task{{ID: 1, Description: "task 1"}}}
+			cli := NewCLI(
+				NewMockInputReader(),
+				&bytes.Buffer{},
+				&Config{ServerURL: "http://localhost:8080"},
+				mockClient,
+				&MockAuthManager{loadTokenResult: "mock-token"},
+			)
+
+			// ====Act====
+			err := cli.handleListCommand(context.Background(), tc.args)
+
+			// ====Assert====
+			if tc.expectedErr != nil {
+				assert.ErrorIs(t, err, tc.expectedErr)
+				assert.Empty(t, mockClient.getTasksOffsets, "No request should be sent")
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expectedQuery, mockClient.getTasksQuery)
+		})
+	}
+}
+
+// TestCLI_handleListCommand tests the handleListCommand method
+func TestCLI_handleListCommand(t *testing.T) {
+	// ====Arrange====
+	testCases := []struct {
+		name             string
+		getTasksResult   []client.Task
+		getTasksErr      error
+		expectedErr      error
+		expectedContains []string
+	}{
+		{
+			name: "Successfully list multiple tasks",
+			getTasksResult: []client.Task{
+				{ID: 1, Description: "Buy groceries", Done: false},
+				{ID: 2, Description: "Clean room", Done: true},
+				{ID: 3, Description: "Write report", Done: false},
+			},
+			getTasksErr: nil,
+			expectedErr: nil,
+			expectedContains: []string{
+				"=== Your Tasks ===",
+				"[ ] 1: Buy groceries",
+				"[✓] 2: Clean room",
+				"[ ] 3: Write report",
+				"==================",
+				"Showing 1-3 of 3",
+			},
+		},
+		{
+			name: "Successfully list single task",
+			getTasksResult: []client.Task{
+				{ID: 1, Description: "Single task", Done: false},
+			},
+			getTasksErr: nil,
+			expectedErr: nil,
+			expectedContains: []string{
+				"=== Your Tasks ===",
+				"[ ] 1: Single task",
+				"==================",
+			},
+		},
+		{
+			name: "List completed tasks only",
+			getTasksResult: []client.Task{
+				{ID: 5, Description: "Completed task 1", Done: true},
+				{ID: 10, Description: "Completed task 2", Done: true},
+			},
+			getTasksErr: nil,
+			expectedErr: nil,
+			expectedContains: []string{
+				"=== Your Tasks ===",
+				"[✓] 5: Completed task 1",
+				"[✓] 10: Completed task 2",
+				"==================",
+			},
+		},
+		{
+			name: "List tasks with empty descriptions",
+			getTasksResult: []client.Task{
+				{ID: 1, Description: "", Done: false},
+				{ID: 2, Description: "Normal task", Done: true},
+			},
+			getTasksErr: nil,
+			expectedErr: nil,
+			expectedContains: []string{
+				"=== Your Tasks ===",
+				"[ ] 1: ",
+				"[✓] 2: Normal task",
+				"==================",
+			},
+		},
+		{
+			name: "List tasks with long descriptions",
+			getTasksResult: []client.Task{
+				{ID: 1, Description: "This is a very long task description that should be displayed completely without truncation", Done: false},
+				{ID: 2, Description: "Another long description with many words to test the display formatting", Done: true},
 			},
 			getTasksErr: nil,
 			expectedErr: nil,
@@ -1721,7 +3612,7 @@ func TestCLI_handleListCommand(t *testing.T) {
 			)
 
 			// ====Act====
-			err := cli.handleListCommand()
+			err := cli.handleListCommand(context.Background(), nil)
 
 			// ====Assert====
 			if tc.expectedErr != nil {
@@ -1931,7 +3822,7 @@ func TestCLI_promptForTaskWithDisplay(t *testing.T) {
 			)
 
 			// ====Act====
-			id, task, err := cli.promptForTaskWithDisplay(tc.prompt)
+			id, task, err := cli.promptForTaskWithDisplay(context.Background(), tc.prompt)
 
 			// ====Assert====
 			if tc.expectedErr != nil {
@@ -1978,3 +3869,179 @@ func TestCLI_promptForTaskWithDisplay(t *testing.T) {
 		})
 	}
 }
+
+// TestCLI_JSONOutput tests that --json prints results as JSON and keeps prompts and errors off the output
+func TestCLI_JSONOutput(t *testing.T) {
+	tasks := make([]client.Task, mockPageSize+2)
+	for i := range tasks {
+		tasks[i] = client.Task{ID: i + 1, Description: fmt.Sprintf("task %d", i+1)}
+	}
+
+	newJSONCLI := func(mockClient *MockTaskClient, inputs ...string) (*CLI, *bytes.Buffer, *bytes.Buffer) {
+		output, errOutput := &bytes.Buffer{}, &bytes.Buffer{}
+		cli := NewCLI(
+			NewMockInputReader(inputs...),
+			output,
+			&Config{ServerURL: "http://localhost:8080", JSON: true},
+			mockClient,
+			&MockAuthManager{loadTokenResult: "mock-token"},
+		)
+		cli.errOutput = errOutput
+		return cli, output, errOutput
+	}
+
+	t.Run("add prints the created task", func(t *testing.T) {
+		// ====Arrange====
+		mockClient := &MockTaskClient{createTaskResult: &client.Task{ID: 7, Description: "Buy milk"}}
+		cli, output, errOutput := newJSONCLI(mockClient, "Buy milk", "", "")
+
+		// ====Act====
+		err := cli.handleAddCommand(context.Background())
+
+		// ====Assert====
+		assert.NoError(t, err)
+		var task client.Task
+		assert.NoError(t, json.Unmarshal(output.Bytes(), &task))
+		assert.Equal(t, client.Task{ID: 7, Description: "Buy milk"}, task)
+		assert.Contains(t, errOutput.String(), "Enter task description:")
+	})
+	t.Run("list prints every page without asking", func(t *testing.T) {
+		// ====Arrange====
+		mockClient := &MockTaskClient{getTasksResult: tasks}
+		cli, output, errOutput := newJSONCLI(mockClient)
+
+		// ====Act====
+		err := cli.handleListCommand(context.Background(), []string{"--sort", "-created"})
+
+		// ====Assert====
+		assert.NoError(t, err)
+		var listed []client.Task
+		assert.NoError(t, json.Unmarshal(output.Bytes(), &listed))
+		assert.Equal(t, tasks, listed)
+		assert.Equal(t, []int{0, mockPageSize}, mockClient.getTasksOffsets)
+		assert.Equal(t, "-created", mockClient.getTasksQuery.Sort)
+		assert.Empty(t, errOutput.String())
+	})
+	t.Run("search without matches prints an empty array", func(t *testing.T) {
+		// ====Arrange====
+		cli, output, _ := newJSONCLI(&MockTaskClient{}, "milk")
+
+		// ====Act====
+		err := cli.handleSearchCommand(context.Background())
+
+		// ====Assert====
+		assert.NoError(t, err)
+		assert.Equal(t, "[]\n", output.String())
+	})
+	t.Run("canceled delete reports deleted false", func(t *testing.T) {
+		// ====Arrange====
+		mockClient := &MockTaskClient{getTaskResult: &client.Task{ID: 3, Description: "task 3"}}
+		cli, output, _ := newJSONCLI(mockClient, "3", "n")
+
+		// ====Act====
+		err := cli.handleDeleteCommand(context.Background(), nil)
+
+		// ====Assert====
+		assert.NoError(t, err)
+		assert.JSONEq(t, `{"id":3,"deleted":false,"permanent":false}`, output.String())
+	})
+	t.Run("errors go to the error output as JSON", func(t *testing.T) {
+		// ====Arrange====
+		cli, output, errOutput := newJSONCLI(&MockTaskClient{})
+
+		// ====Act====
+		cli.handleError(&client.APIError{StatusCode: 404, Message: "Task not found"}, "Delete command error")
+
+		// ====Assert====
+		assert.Empty(t, output.String())
+		assert.JSONEq(t, `{"error":"Delete command error: Task not found"}`, errOutput.String())
+	})
+	t.Run("errors include the request ID", func(t *testing.T) {
+		// ====Arrange====
+		cli, _, errOutput := newJSONCLI(&MockTaskClient{})
+
+		// ====Act====
+		cli.handleError(&client.APIError{StatusCode: 500, Message: "Server error", RequestID: "req-42"}, "List command error")
+
+		// ====Assert====
+		assert.JSONEq(t, `{"error":"List command error: Server error","request_id":"req-42"}`, errOutput.String())
+	})
+}
+
+func TestCLI_LoadLimits(t *testing.T) {
+	// ====Arrange====
+	description := strings.Repeat("a", 30)
+	testCases := []struct {
+		name          string
+		limitsResult  *client.Limits
+		limitsErr     error
+		configLimit   int
+		expectedLimit int
+		expectLoadErr bool
+		expectCreated bool
+	}{
+		{
+			name:          "Server limit below the input",
+			limitsResult:  &client.Limits{MaxDescriptionLength: 10},
+			configLimit:   200,
+			expectedLimit: 10,
+		},
+		{
+			name:          "Server limit overrides the config",
+			limitsResult:  &client.Limits{MaxDescriptionLength: 500},
+			configLimit:   20,
+			expectedLimit: 500,
+			expectCreated: true,
+		},
+		{
+			name:          "Server without the endpoint keeps the config",
+			limitsErr:     &client.APIError{StatusCode: 404, Message: "404 page not found"},
+			configLimit:   20,
+			expectedLimit: 20,
+			expectLoadErr: true,
+		},
+		{
+			name:          "Zero server limit keeps the config",
+			limitsResult:  &client.Limits{},
+			configLimit:   50,
+			expectedLimit: 50,
+			expectCreated: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			mockClient := &MockTaskClient{
+				limitsResult:     tc.limitsResult,
+				limitsErr:        tc.limitsErr,
+				createTaskResult: &client.Task{ID: 1, Description: description},
+			}
+			cli := NewCLI(
+				NewMockInputReader(description),
+				&bytes.Buffer{},
+				&Config{ServerURL: "http://localhost:8080", MaxDescriptionLength: tc.configLimit},
+				mockClient,
+				&MockAuthManager{loadTokenResult: "mock-token"},
+			)
+
+			// ====Act====
+			loadErr := cli.LoadLimits(context.Background())
+			addErr := cli.handleAddDoneCommand(context.Background())
+
+			// ====Assert====
+			if tc.expectLoadErr {
+				assert.Error(t, loadErr)
+			} else {
+				assert.NoError(t, loadErr)
+			}
+			assert.Equal(t, tc.expectedLimit, cli.descriptionLimit())
+			if tc.expectCreated {
+				assert.NoError(t, addErr)
+				assert.Equal(t, []string{description}, mockClient.createTaskDescs)
+			} else {
+				assert.ErrorIs(t, addErr, ErrMaxSizeExceeded)
+				assert.Empty(t, mockClient.createTaskDescs)
+			}
+		})
+	}
+}
@@ -0,0 +1,68 @@
+package client
+
+import (
+	"sync"
+	"time"
+)
+
+// cachedTask is a task kept by taskCache until it expires
+type cachedTask struct {
+	task    Task
+	expires time.Time
+}
+
+// taskCache keeps GetTask results for a short time, so a command that looks a task up
+// again does not wait for another round-trip. It is safe for concurrent use
+type taskCache struct {
+	mu      sync.Mutex
+	ttl     time.Duration
+	now     func() time.Time
+	entries map[int]cachedTask
+}
+
+// newTaskCache creates a cache whose entries live for ttl
+func newTaskCache(ttl time.Duration) *taskCache {
+	return &taskCache{
+		ttl:     ttl,
+		now:     time.Now,
+		entries: make(map[int]cachedTask),
+	}
+}
+
+// get returns a copy of the cached task, or false when it is missing or expired
+func (tc *taskCache) get(id int) (*Task, bool) {
+	tc.mu.Lock()
+	defer tc.mu.Unlock()
+
+	entry, ok := tc.entries[id]
+	if !ok {
+		return nil, false
+	}
+	if !tc.now().Before(entry.expires) {
+		delete(tc.entries, id)
+		return nil, false
+	}
+	task := entry.task
+	return &task, true
+}
+
+// put stores a copy of task until the ttl has passed
+func (tc *taskCache) put(task Task) {
+	tc.mu.Lock()
+	defer tc.mu.Unlock()
+	tc.entries[task.ID] = cachedTask{task: task, expires: tc.now().Add(tc.ttl)}
+}
+
+// invalidate drops the task with the given ID
+func (tc *taskCache) invalidate(id int) {
+	tc.mu.Lock()
+	defer tc.mu.Unlock()
+	delete(tc.entries, id)
+}
+
+// clear drops every task, e.g. when the client starts acting as another user
+func (tc *taskCache) clear() {
+	tc.mu.Lock()
+	defer tc.mu.Unlock()
+	clear(tc.entries)
+}
@@ -2,43 +2,156 @@ package client
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
+	"math"
+	"net"
 	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
 	"time"
+
+	"github.com/google/uuid"
 )
 
+// RequestIDHeader carries the ID the client generates for each request, so a failing
+// command can be matched with the server's logs
+const RequestIDHeader = "X-Request-ID"
+
+// IdempotencyKeyHeader carries the key that lets the server recognise a repeated create,
+// so a task is created only once however often the request is retried
+const IdempotencyKeyHeader = "Idempotency-Key"
+
 // TaskClient defines the interface for interacting with the task management API
+// Every request is bound to ctx, so cancelling it aborts the request and any pending retries
 type TaskClient interface {
 	// Task operations
-	GetTasks() ([]Task, error)
-	GetTask(id int) (*Task, error)
-	CreateTask(description string) (*Task, error)
-	UpdateTask(id int, description *string, done *bool) (*Task, error)
-	DeleteTask(id int) error
+	GetTasks(ctx context.Context, limit, offset int, query ListQuery) (*TaskList, error)
+	GetTask(ctx context.Context, id int) (*Task, error)
+	SearchTasks(ctx context.Context, query string) ([]Task, error)
+	GetTasksByTag(ctx context.Context, tag string, includeArchived bool) ([]Task, error)
+	Stats(ctx context.Context) (*Stats, error)
+	CreateTask(ctx context.Context, description string, done bool, dueDate *time.Time, priority int) (*Task, error)
+	CreateTasks(ctx context.Context, descriptions []string) ([]int, error)
+	UpdateTask(ctx context.Context, id int, description *string, done *bool, version *int) (*Task, error)
+	ToggleTask(ctx context.Context, id int) (*Task, error)
+	UpdateAllTaskStatus(ctx context.Context, done bool) (int, error)
+	DeleteTask(ctx context.Context, id int) error
+	DeleteTasks(ctx context.Context, ids []int) (int, error)
+	PurgeTask(ctx context.Context, id int) error
+	RestoreTask(ctx context.Context, id int) (*Task, error)
+	ArchiveTask(ctx context.Context, id int) (*Task, error)
+	ArchiveCompleted(ctx context.Context) (int, error)
+	AddTag(ctx context.Context, id int, tag string) (*Task, error)
+	RemoveTag(ctx context.Context, id int, tag string) (*Task, error)
 
 	// Authentication
-	Login(email, password string) (string, error)
-	Register(email, password string) (string, error)
+	Login(ctx context.Context, email, password string) (string, error)
+	Register(ctx context.Context, email, password string) (string, error)
+	Me(ctx context.Context) (*User, error)
+	DeleteAccount(ctx context.Context) error
+	ChangePassword(ctx context.Context, currentPassword, newPassword string) error
 
 	// Configuration
+	Ping(ctx context.Context) error
+	Limits(ctx context.Context) (*Limits, error)
 	SetToken(token string)
 	GetServerURL() string
 }
 
 // HTTPClient implements TaskClient using HTTP requests
 type HTTPClient struct {
-	baseURL    string
-	httpClient *http.Client
-	token      string
+	baseURL      string
+	requestURL   string
+	httpClient   *http.Client
+	token        string
+	maxRetries   int
+	retryBackoff time.Duration
+	// maxRateLimitWait is the longest Retry-After a rate limited request waits out before its single retry
+	maxRateLimitWait time.Duration
+	// cache holds GetTask results; nil when caching is disabled
+	cache *taskCache
+	// verbose receives a trace of every request when set with SetVerbose
+	verbose io.Writer
+}
+
+// Defaults used by NewHTTPClient
+const (
+	DefaultTimeout      = 30 * time.Second
+	DefaultRetryBackoff = 200 * time.Millisecond
+)
+
+// ClientOptions tunes request timeouts and the retry policy of HTTPClient.
+// Only idempotent requests are retried, after network errors and 5xx responses: GET, DELETE and creates
+// that carry an Idempotency-Key; the wait before each retry starts at RetryBackoff and doubles every attempt.
+// A positive CacheTTL keeps GetTask results for that long; zero disables the cache.
+// A request rejected with 429 is retried once after its Retry-After delay when that delay
+// is at most MaxRateLimitWait; zero reports every 429 as a RateLimitError right away.
+type ClientOptions struct {
+	Timeout          time.Duration
+	MaxRetries       int
+	RetryBackoff     time.Duration
+	CacheTTL         time.Duration
+	MaxRateLimitWait time.Duration
 }
 
+// unixScheme is the URL scheme used to reach the server over a unix domain socket, e.g. unix:///run/tasks.sock
+const unixScheme = "unix://"
+
 // Task represents a task in the system
 type Task struct {
-	ID          int    `json:"id"`
-	Description string `json:"description"`
-	Done        bool   `json:"done"`
+	ID          int        `json:"id"`
+	Description string     `json:"description"`
+	Done        bool       `json:"done"`
+	CompletedAt *time.Time `json:"completed_at,omitempty"`
+	DueDate     *time.Time `json:"due_date,omitempty"`
+	Priority    int        `json:"priority"`
+	Tags        []string   `json:"tags,omitempty"`
+	ArchivedAt  *time.Time `json:"archived_at,omitempty"`
+	Version     int        `json:"version"`
+	CreatedAt   time.Time  `json:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at"`
+}
+
+// ListQuery orders and narrows a task list; the zero value lists every task that is not archived in the server's default order.
+// Sort is a field such as "-created"; the created bounds are inclusive and optional
+type ListQuery struct {
+	Sort            string
+	CreatedAfter    *time.Time
+	CreatedBefore   *time.Time
+	IncludeArchived bool
+}
+
+// TaskList represents one page of tasks and the total number available on the server
+type TaskList struct {
+	Tasks  []Task `json:"tasks"`
+	Total  int    `json:"total"`
+	Limit  int    `json:"limit"`
+	Offset int    `json:"offset"`
+}
+
+// Stats counts the user's tasks on the server; Pending is the number not done yet
+type Stats struct {
+	Total   int `json:"total"`
+	Done    int `json:"done"`
+	Pending int `json:"pending"`
+}
+
+// Limits are the input limits the server enforces
+type Limits struct {
+	MaxDescriptionLength int `json:"max_description_length"`
+}
+
+// User is the account the client's token belongs to
+type User struct {
+	ID        int       `json:"id"`
+	Email     string    `json:"email"`
+	CreatedAt time.Time `json:"created_at"`
 }
 
 // AuthRequest represents login/register request payload
@@ -53,15 +166,55 @@ type AuthResponse struct {
 	Email string `json:"email"`
 }
 
+// ChangePasswordRequest represents the request body for changing the password
+type ChangePasswordRequest struct {
+	CurrentPassword string `json:"current_password"`
+	NewPassword     string `json:"new_password"`
+}
+
 // CreateTaskRequest represents task creation request
 type CreateTaskRequest struct {
-	Description string `json:"description"`
+	Description string     `json:"description"`
+	Done        bool       `json:"done,omitempty"`
+	DueDate     *time.Time `json:"due_date,omitempty"`
+	Priority    int        `json:"priority,omitempty"`
+}
+
+// CreateTasksResponse represents the IDs returned by batch task creation
+type CreateTasksResponse struct {
+	IDs []int `json:"ids"`
+}
+
+// DeleteTasksRequest represents batch task deletion request
+type DeleteTasksRequest struct {
+	IDs []int `json:"ids"`
+}
+
+// DeleteTasksResponse represents the number of tasks a batch deletion removed
+type DeleteTasksResponse struct {
+	Deleted int `json:"deleted"`
+}
+
+// ArchiveCompletedResponse represents the number of completed tasks the server archived
+type ArchiveCompletedResponse struct {
+	Archived int `json:"archived"`
+}
+
+// UpdateTasksStatusResponse represents the number of tasks a bulk status change updated
+type UpdateTasksStatusResponse struct {
+	Updated int `json:"updated"`
+}
+
+// TagRequest represents a request to tag a task
+type TagRequest struct {
+	Tag string `json:"tag"`
 }
 
 // UpdateTaskRequest represents task update request
 type UpdateTaskRequest struct {
 	Description *string `json:"description,omitempty"`
 	Done        *bool   `json:"done,omitempty"`
+	Version     *int    `json:"version,omitempty"`
 }
 
 // ErrorResponse represents an error response from the server
@@ -71,11 +224,16 @@ type ErrorResponse struct {
 
 // NetworkError represents a network connectivity error
 type NetworkError struct {
-	URL string
-	Err error
+	URL       string
+	Err       error
+	Attempts  int
+	RequestID string
 }
 
 func (e *NetworkError) Error() string {
+	if e.Attempts > 1 {
+		return fmt.Sprintf("cannot connect to server at %s after %d attempts: %v", e.URL, e.Attempts, e.Err)
+	}
 	return fmt.Sprintf("cannot connect to server at %s: %v", e.URL, e.Err)
 }
 
@@ -83,6 +241,7 @@ func (e *NetworkError) Error() string {
 type APIError struct {
 	StatusCode int
 	Message    string
+	RequestID  string
 }
 
 func (e *APIError) Error() string {
@@ -92,25 +251,99 @@ func (e *APIError) Error() string {
 // AuthError represents an authentication error (401 Unauthorized)
 // This error type signals that the stored token is invalid and re-authentication is required
 type AuthError struct {
-	Message string
+	Message   string
+	RequestID string
 }
 
 func (e *AuthError) Error() string {
 	return e.Message
 }
 
-// IsAuthError checks if an error is an authentication error
+// RateLimitError represents a 429 Too Many Requests response.
+// RetryAfter is the wait the server asked for in its Retry-After header, zero if it gave none
+type RateLimitError struct {
+	RetryAfter time.Duration
+	RequestID  string
+}
+
+func (e *RateLimitError) Error() string {
+	if e.RetryAfter <= 0 {
+		return "server busy, retry later"
+	}
+	return fmt.Sprintf("server busy, retry in %ds", int(math.Ceil(e.RetryAfter.Seconds())))
+}
+
+// IsAuthError checks if an error is, or wraps, an authentication error
 func IsAuthError(err error) bool {
-	_, ok := err.(*AuthError)
-	return ok
+	var authErr *AuthError
+	return errors.As(err, &authErr)
 }
 
-// NewHTTPClient creates a new HTTP client with the specified base URL
+// IsVersionConflict checks if an error is, or wraps, a 409 Conflict response,
+// which an update gets when the task changed since the version it sent was read
+func IsVersionConflict(err error) bool {
+	var apiErr *APIError
+	return errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusConflict
+}
+
+// NewHTTPClient creates a new HTTP client with the specified base URL, the default timeout and no retries
+// A unix:// base URL makes the client dial the given socket path instead of a TCP address
 func NewHTTPClient(baseURL string) *HTTPClient {
-	return &HTTPClient{
-		baseURL: baseURL,
-		httpClient: &http.Client{
-			Timeout: 30 * time.Second,
+	return NewHTTPClientWithOptions(baseURL, ClientOptions{
+		Timeout:      DefaultTimeout,
+		RetryBackoff: DefaultRetryBackoff,
+	})
+}
+
+// NewHTTPClientWithOptions creates a new HTTP client with the given timeout and retry policy
+func NewHTTPClientWithOptions(baseURL string, opts ClientOptions) *HTTPClient {
+	httpClient := &http.Client{
+		Timeout: opts.Timeout,
+	}
+	requestURL := baseURL
+
+	if socketPath, ok := strings.CutPrefix(baseURL, unixScheme); ok {
+		httpClient.Transport = newUnixTransport(socketPath)
+		requestURL = "http://unix"
+	}
+
+	c := &HTTPClient{
+		baseURL:          baseURL,
+		requestURL:       requestURL,
+		httpClient:       httpClient,
+		maxRetries:       max(opts.MaxRetries, 0),
+		retryBackoff:     opts.RetryBackoff,
+		maxRateLimitWait: opts.MaxRateLimitWait,
+	}
+	if opts.CacheTTL > 0 {
+		c.cache = newTaskCache(opts.CacheTTL)
+	}
+	return c
+}
+
+// NewHTTPClientWithCache creates a client like NewHTTPClient that keeps GetTask results for ttl.
+// Updating, deleting, purging or restoring a task drops its cached copy
+func NewHTTPClientWithCache(baseURL string, ttl time.Duration) *HTTPClient {
+	return NewHTTPClientWithOptions(baseURL, ClientOptions{
+		Timeout:      DefaultTimeout,
+		RetryBackoff: DefaultRetryBackoff,
+		CacheTTL:     ttl,
+	})
+}
+
+// invalidateTask drops the cached copy of a task after a request that may have changed it
+func (c *HTTPClient) invalidateTask(id int) {
+	if c.cache != nil {
+		c.cache.invalidate(id)
+	}
+}
+
+// newUnixTransport creates an HTTP transport that sends every request over the given unix socket
+func newUnixTransport(socketPath string) *http.Transport {
+	dialer := &net.Dialer{}
+	return &http.Transport{
+		DialContext: func(ctx context.Context, _, _ string) (net.Conn, error) {
+			return dialer.DialContext(ctx, "unix", socketPath)
 		},
 	}
 }
@@ -118,6 +351,16 @@ func NewHTTPClient(baseURL string) *HTTPClient {
 // SetToken sets the authentication token for subsequent requests
 func (c *HTTPClient) SetToken(token string) {
 	c.token = token
+	// Tasks cached for the previous token may belong to another user
+	if c.cache != nil {
+		c.cache.clear()
+	}
+}
+
+// SetVerbose makes the client write the method, URL, headers, status and duration of every request to w,
+// with the Authorization header redacted; nil turns the trace off
+func (c *HTTPClient) SetVerbose(w io.Writer) {
+	c.verbose = w
 }
 
 // GetServerURL returns the configured server URL
@@ -126,34 +369,44 @@ func (c *HTTPClient) GetServerURL() string {
 }
 
 // doRequest performs an HTTP request with JSON encoding/decoding
-func (c *HTTPClient) doRequest(method, path string, body, result interface{}) error {
-	var reqBody io.Reader
+// GET and DELETE requests are retried on network errors and 5xx responses according to the retry policy;
+// any request rejected with 429 is retried once if the server asks to wait no longer than maxRateLimitWait
+func (c *HTTPClient) doRequest(ctx context.Context, method, path string, body, result interface{}) error {
+	return c.doIdempotentRequest(ctx, method, path, "", body, result)
+}
+
+// doIdempotentRequest performs a request like doRequest, sending idempotencyKey with every attempt.
+// A request with a key is retried like GET and DELETE whatever its method, because the server
+// answers a repeated key with the result of the first attempt instead of running it again
+func (c *HTTPClient) doIdempotentRequest(ctx context.Context, method, path, idempotencyKey string, body, result interface{}) error {
+	var jsonData []byte
 	if body != nil {
-		jsonData, err := json.Marshal(body)
+		var err error
+		jsonData, err = json.Marshal(body)
 		if err != nil {
 			return fmt.Errorf("failed to marshal request body: %w", err)
 		}
-		reqBody = bytes.NewBuffer(jsonData)
 	}
 
-	url := c.baseURL + path
-	req, err := http.NewRequest(method, url, reqBody)
-	if err != nil {
-		return fmt.Errorf("failed to create request: %w", err)
-	}
+	// Retries reuse the ID, so the server logs show every attempt of one command
+	requestID := uuid.NewString()
 
-	// Set headers
-	req.Header.Set("Content-Type", "application/json")
-	if c.token != "" {
-		req.Header.Set("Authorization", "Bearer "+c.token)
-	}
-
-	// Execute request
-	resp, err := c.httpClient.Do(req)
+	resp, err := c.sendWithRetries(ctx, method, path, jsonData, requestID, idempotencyKey)
 	if err != nil {
-		return &NetworkError{
-			URL: c.baseURL,
-			Err: err,
+		return err
+	}
+	if resp.StatusCode == http.StatusTooManyRequests && c.maxRateLimitWait > 0 {
+		// A rejected request was not processed, so even POST and PUT are safe to repeat
+		if wait := parseRetryAfter(resp.Header.Get("Retry-After"), time.Now()); wait > 0 && wait <= c.maxRateLimitWait {
+			io.Copy(io.Discard, resp.Body)
+			resp.Body.Close()
+			if err := sleepContext(ctx, wait); err != nil {
+				return err
+			}
+			resp, err = c.sendWithRetries(ctx, method, path, jsonData, requestID, idempotencyKey)
+			if err != nil {
+				return err
+			}
 		}
 	}
 	defer resp.Body.Close()
@@ -173,6 +426,149 @@ func (c *HTTPClient) doRequest(method, path string, body, result interface{}) er
 	return nil
 }
 
+// sendWithRetries sends a request, repeating GET, DELETE and requests with an idempotency key after
+// network errors and 5xx responses according to the retry policy; the caller must close the returned response body
+func (c *HTTPClient) sendWithRetries(ctx context.Context, method, path string, jsonData []byte, requestID, idempotencyKey string) (*http.Response, error) {
+	attempts := 1
+	if method == http.MethodGet || method == http.MethodDelete || idempotencyKey != "" {
+		attempts += c.maxRetries
+	}
+
+	var resp *http.Response
+	for attempt := 1; attempt <= attempts; attempt++ {
+		if attempt > 1 {
+			if err := sleepContext(ctx, c.retryBackoff<<(attempt-2)); err != nil {
+				return nil, err
+			}
+		}
+
+		var err error
+		resp, err = c.send(ctx, method, path, jsonData, requestID, idempotencyKey)
+		if err != nil {
+			var netErr *NetworkError
+			if !errors.As(err, &netErr) {
+				return nil, err
+			}
+			netErr.Attempts = attempt
+			if attempt == attempts {
+				return nil, netErr
+			}
+			continue
+		}
+		if resp.StatusCode >= 500 && attempt < attempts {
+			io.Copy(io.Discard, resp.Body)
+			resp.Body.Close()
+			continue
+		}
+		break
+	}
+	return resp, nil
+}
+
+// send executes a single attempt of a request; a transport failure is reported as a NetworkError
+// unless it was caused by ctx being cancelled or expiring, in which case ctx.Err() is returned
+func (c *HTTPClient) send(ctx context.Context, method, path string, jsonData []byte, requestID, idempotencyKey string) (*http.Response, error) {
+	var reqBody io.Reader
+	if jsonData != nil {
+		reqBody = bytes.NewReader(jsonData)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, c.requestURL+path, reqBody)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	// Set headers
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(RequestIDHeader, requestID)
+	if idempotencyKey != "" {
+		req.Header.Set(IdempotencyKeyHeader, idempotencyKey)
+	}
+	if c.token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.token)
+	}
+
+	start := time.Now()
+	resp, err := c.httpClient.Do(req)
+	c.traceRequest(req, resp, err, time.Since(start))
+	if err != nil {
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return nil, ctxErr
+		}
+		return nil, &NetworkError{
+			URL:       c.baseURL,
+			Err:       err,
+			RequestID: requestID,
+		}
+	}
+	return resp, nil
+}
+
+// traceRequest writes one request attempt to the verbose writer, if set
+// The token is never written, so traces can be shared when reporting problems
+func (c *HTTPClient) traceRequest(req *http.Request, resp *http.Response, err error, elapsed time.Duration) {
+	if c.verbose == nil {
+		return
+	}
+
+	fmt.Fprintf(c.verbose, "--> %s %s\n", req.Method, req.URL)
+	names := make([]string, 0, len(req.Header))
+	for name := range req.Header {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	for _, name := range names {
+		value := req.Header.Get(name)
+		if name == "Authorization" {
+			value = redactAuthorization(value)
+		}
+		fmt.Fprintf(c.verbose, "    %s: %s\n", name, value)
+	}
+
+	elapsed = elapsed.Round(time.Millisecond)
+	if err != nil {
+		fmt.Fprintf(c.verbose, "<-- error: %v (%s)\n", err, elapsed)
+		return
+	}
+	fmt.Fprintf(c.verbose, "<-- %s (%s)\n", resp.Status, elapsed)
+}
+
+// redactAuthorization keeps the scheme of an Authorization header value and hides the credentials
+func redactAuthorization(value string) string {
+	if scheme, _, ok := strings.Cut(value, " "); ok {
+		return scheme + " [REDACTED]"
+	}
+	return "[REDACTED]"
+}
+
+// sleepContext waits for d or until ctx is done, whichever comes first
+func sleepContext(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// parseRetryAfter converts a Retry-After header, given in seconds or as an HTTP date, into a wait from now.
+// A missing, malformed or past value yields zero
+func parseRetryAfter(header string, now time.Time) time.Duration {
+	header = strings.TrimSpace(header)
+	if header == "" {
+		return 0
+	}
+	if seconds, err := strconv.Atoi(header); err == nil {
+		return max(time.Duration(seconds)*time.Second, 0)
+	}
+	if at, err := http.ParseTime(header); err == nil {
+		return max(at.Sub(now), 0)
+	}
+	return 0
+}
+
 // handleErrorResponse parses and returns appropriate errors for HTTP error responses
 func (c *HTTPClient) handleErrorResponse(resp *http.Response) error {
 	var errResp ErrorResponse
@@ -180,11 +576,21 @@ func (c *HTTPClient) handleErrorResponse(resp *http.Response) error {
 		// If we can't decode the error response, use status text
 		errResp.Error = resp.Status
 	}
+	requestID := resp.Request.Header.Get(RequestIDHeader)
 
 	// Handle 401 Unauthorized - return AuthError to trigger re-authentication
 	if resp.StatusCode == http.StatusUnauthorized {
 		return &AuthError{
-			Message: "Authentication required: token is invalid or expired",
+			Message:   "Authentication required: token is invalid or expired",
+			RequestID: requestID,
+		}
+	}
+
+	// Handle 429 Too Many Requests - tell the user how long to wait instead of the raw message
+	if resp.StatusCode == http.StatusTooManyRequests {
+		return &RateLimitError{
+			RetryAfter: parseRetryAfter(resp.Header.Get("Retry-After"), time.Now()),
+			RequestID:  requestID,
 		}
 	}
 
@@ -194,29 +600,32 @@ func (c *HTTPClient) handleErrorResponse(resp *http.Response) error {
 		return &APIError{
 			StatusCode: resp.StatusCode,
 			Message:    fmt.Sprintf("Server error (%d), please try again later", resp.StatusCode),
+			RequestID:  requestID,
 		}
 	case resp.StatusCode >= 400:
 		return &APIError{
 			StatusCode: resp.StatusCode,
 			Message:    errResp.Error,
+			RequestID:  requestID,
 		}
 	}
 
 	return &APIError{
 		StatusCode: resp.StatusCode,
 		Message:    errResp.Error,
+		RequestID:  requestID,
 	}
 }
 
 // Login authenticates a user and returns a JWT token
-func (c *HTTPClient) Login(email, password string) (string, error) {
+func (c *HTTPClient) Login(ctx context.Context, email, password string) (string, error) {
 	req := AuthRequest{
 		Email:    email,
 		Password: password,
 	}
 
 	var resp AuthResponse
-	if err := c.doRequest(http.MethodPost, "/login", req, &resp); err != nil {
+	if err := c.doRequest(ctx, http.MethodPost, "/login", req, &resp); err != nil {
 		return "", err
 	}
 
@@ -224,69 +633,332 @@ func (c *HTTPClient) Login(email, password string) (string, error) {
 }
 
 // Register creates a new user account and returns a JWT token
-func (c *HTTPClient) Register(email, password string) (string, error) {
+func (c *HTTPClient) Register(ctx context.Context, email, password string) (string, error) {
 	req := AuthRequest{
 		Email:    email,
 		Password: password,
 	}
 
 	var resp AuthResponse
-	if err := c.doRequest(http.MethodPost, "/register", req, &resp); err != nil {
+	if err := c.doRequest(ctx, http.MethodPost, "/register", req, &resp); err != nil {
 		return "", err
 	}
 
 	return resp.Token, nil
 }
 
-// GetTasks retrieves all tasks for the authenticated user
-func (c *HTTPClient) GetTasks() ([]Task, error) {
+// GetTasks retrieves a page of tasks for the authenticated user, ordered and filtered by query.
+// A zero limit or offset and unset query fields are omitted so the server defaults apply.
+func (c *HTTPClient) GetTasks(ctx context.Context, limit, offset int, query ListQuery) (*TaskList, error) {
+	values := url.Values{}
+	if limit > 0 {
+		values.Set("limit", strconv.Itoa(limit))
+	}
+	if offset > 0 {
+		values.Set("offset", strconv.Itoa(offset))
+	}
+	if query.Sort != "" {
+		values.Set("sort", query.Sort)
+	}
+	if query.CreatedAfter != nil {
+		values.Set("created_after", query.CreatedAfter.Format(time.RFC3339))
+	}
+	if query.CreatedBefore != nil {
+		values.Set("created_before", query.CreatedBefore.Format(time.RFC3339))
+	}
+	if query.IncludeArchived {
+		values.Set("include_archived", "true")
+	}
+
+	path := "/tasks"
+	if len(values) > 0 {
+		path += "?" + values.Encode()
+	}
+
+	var list TaskList
+	if err := c.doRequest(ctx, http.MethodGet, path, nil, &list); err != nil {
+		return nil, err
+	}
+	return &list, nil
+}
+
+// SearchTasks retrieves the tasks whose description contains query, ignoring case
+func (c *HTTPClient) SearchTasks(ctx context.Context, query string) ([]Task, error) {
 	var tasks []Task
-	if err := c.doRequest(http.MethodGet, "/tasks", nil, &tasks); err != nil {
+	path := "/tasks/search?" + url.Values{"q": {query}}.Encode()
+	if err := c.doRequest(ctx, http.MethodGet, path, nil, &tasks); err != nil {
 		return nil, err
 	}
 	return tasks, nil
 }
 
-// GetTask retrieves a specific task by ID
-func (c *HTTPClient) GetTask(id int) (*Task, error) {
+// GetTasksByTag retrieves all tasks carrying the tag, requesting page after page until the server's total is reached;
+// the server matches tags ignoring case. Archived tasks are included only if includeArchived is set
+func (c *HTTPClient) GetTasksByTag(ctx context.Context, tag string, includeArchived bool) ([]Task, error) {
+	tasks := []Task{}
+	for {
+		values := url.Values{"tag": {tag}}
+		if len(tasks) > 0 {
+			values.Set("offset", strconv.Itoa(len(tasks)))
+		}
+		if includeArchived {
+			values.Set("include_archived", "true")
+		}
+
+		var list TaskList
+		if err := c.doRequest(ctx, http.MethodGet, "/tasks?"+values.Encode(), nil, &list); err != nil {
+			return nil, err
+		}
+		tasks = append(tasks, list.Tasks...)
+		if len(list.Tasks) == 0 || len(tasks) >= list.Total {
+			return tasks, nil
+		}
+	}
+}
+
+// Stats retrieves the total, done and pending task counts of the current user
+func (c *HTTPClient) Stats(ctx context.Context) (*Stats, error) {
+	var stats Stats
+	if err := c.doRequest(ctx, http.MethodGet, "/tasks/stats", nil, &stats); err != nil {
+		return nil, err
+	}
+	return &stats, nil
+}
+
+// Ping checks that the server is up with a single GET /health, without retries,
+// so the caller decides how long to wait for it
+func (c *HTTPClient) Ping(ctx context.Context) error {
+	resp, err := c.send(ctx, http.MethodGet, "/health", nil, uuid.NewString(), "")
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 400 {
+		return c.handleErrorResponse(resp)
+	}
+	io.Copy(io.Discard, resp.Body)
+	return nil
+}
+
+// Limits retrieves the input limits the server enforces; servers without the endpoint answer 404
+func (c *HTTPClient) Limits(ctx context.Context) (*Limits, error) {
+	var limits Limits
+	if err := c.doRequest(ctx, http.MethodGet, "/config/limits", nil, &limits); err != nil {
+		return nil, err
+	}
+	return &limits, nil
+}
+
+// GetTask retrieves a specific task by ID, from the cache when enabled and still fresh
+func (c *HTTPClient) GetTask(ctx context.Context, id int) (*Task, error) {
+	if c.cache != nil {
+		if task, ok := c.cache.get(id); ok {
+			return task, nil
+		}
+	}
+
 	var task Task
 	path := fmt.Sprintf("/tasks/%d", id)
-	if err := c.doRequest(http.MethodGet, path, nil, &task); err != nil {
+	if err := c.doRequest(ctx, http.MethodGet, path, nil, &task); err != nil {
 		return nil, err
 	}
+	if c.cache != nil {
+		c.cache.put(task)
+	}
 	return &task, nil
 }
 
-// CreateTask creates a new task with the given description
-func (c *HTTPClient) CreateTask(description string) (*Task, error) {
+// CreateTask creates a new task with the given description, initial done status, optional due date and priority
+// Every attempt carries the same idempotency key, so retrying after a lost response cannot create a duplicate
+func (c *HTTPClient) CreateTask(ctx context.Context, description string, done bool, dueDate *time.Time, priority int) (*Task, error) {
 	req := CreateTaskRequest{
 		Description: description,
+		Done:        done,
+		DueDate:     dueDate,
+		Priority:    priority,
 	}
 
 	var task Task
-	if err := c.doRequest(http.MethodPost, "/tasks", req, &task); err != nil {
+	if err := c.doIdempotentRequest(ctx, http.MethodPost, "/tasks", uuid.NewString(), req, &task); err != nil {
 		return nil, err
 	}
 	return &task, nil
 }
 
+// CreateTasks creates all descriptions as one batch and returns the new IDs in order
+func (c *HTTPClient) CreateTasks(ctx context.Context, descriptions []string) ([]int, error) {
+	req := make([]CreateTaskRequest, len(descriptions))
+	for i, description := range descriptions {
+		req[i] = CreateTaskRequest{Description: description}
+	}
+
+	var resp CreateTasksResponse
+	if err := c.doRequest(ctx, http.MethodPost, "/tasks/batch", req, &resp); err != nil {
+		return nil, err
+	}
+	return resp.IDs, nil
+}
+
 // UpdateTask updates a task's description and/or done status
-func (c *HTTPClient) UpdateTask(id int, description *string, done *bool) (*Task, error) {
+// A non-nil version makes the server reject the update if the task has changed since that version
+func (c *HTTPClient) UpdateTask(ctx context.Context, id int, description *string, done *bool, version *int) (*Task, error) {
 	req := UpdateTaskRequest{
 		Description: description,
 		Done:        done,
+		Version:     version,
 	}
 
 	var task Task
 	path := fmt.Sprintf("/tasks/%d", id)
-	if err := c.doRequest(http.MethodPut, path, req, &task); err != nil {
+	err := c.doRequest(ctx, http.MethodPatch, path, req, &task)
+	c.invalidateTask(id)
+	if err != nil {
 		return nil, err
 	}
 	return &task, nil
 }
 
-// DeleteTask deletes a task by ID
-func (c *HTTPClient) DeleteTask(id int) error {
+// ToggleTask flips a task's done status on the server and returns the changed task
+func (c *HTTPClient) ToggleTask(ctx context.Context, id int) (*Task, error) {
+	var task Task
+	path := fmt.Sprintf("/tasks/%d/toggle", id)
+	err := c.doRequest(ctx, http.MethodPost, path, nil, &task)
+	c.invalidateTask(id)
+	if err != nil {
+		return nil, err
+	}
+	return &task, nil
+}
+
+// DeleteTask deletes a task by ID; the server keeps it so it can be restored
+func (c *HTTPClient) DeleteTask(ctx context.Context, id int) error {
 	path := fmt.Sprintf("/tasks/%d", id)
-	return c.doRequest(http.MethodDelete, path, nil, nil)
+	err := c.doRequest(ctx, http.MethodDelete, path, nil, nil)
+	c.invalidateTask(id)
+	return err
+}
+
+// UpdateAllTaskStatus marks every task done or undone and returns how many tasks changed status
+func (c *HTTPClient) UpdateAllTaskStatus(ctx context.Context, done bool) (int, error) {
+	var resp UpdateTasksStatusResponse
+	path := "/tasks/status?" + url.Values{"done": {strconv.FormatBool(done)}}.Encode()
+	err := c.doRequest(ctx, http.MethodPut, path, nil, &resp)
+	if c.cache != nil {
+		c.cache.clear()
+	}
+	if err != nil {
+		return 0, err
+	}
+	return resp.Updated, nil
+}
+
+// DeleteTasks deletes several tasks in one request and returns how many the server deleted;
+// IDs of missing or foreign tasks are skipped by the server
+func (c *HTTPClient) DeleteTasks(ctx context.Context, ids []int) (int, error) {
+	var resp DeleteTasksResponse
+	err := c.doRequest(ctx, http.MethodDelete, "/tasks/batch", DeleteTasksRequest{IDs: ids}, &resp)
+	for _, id := range ids {
+		c.invalidateTask(id)
+	}
+	if err != nil {
+		return 0, err
+	}
+	return resp.Deleted, nil
+}
+
+// PurgeTask permanently deletes a task by ID
+func (c *HTTPClient) PurgeTask(ctx context.Context, id int) error {
+	path := fmt.Sprintf("/tasks/%d?permanent=true", id)
+	err := c.doRequest(ctx, http.MethodDelete, path, nil, nil)
+	c.invalidateTask(id)
+	return err
+}
+
+// Me returns the account the current token was issued for
+func (c *HTTPClient) Me(ctx context.Context) (*User, error) {
+	var user User
+	if err := c.doRequest(ctx, http.MethodGet, "/me", nil, &user); err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+
+// DeleteAccount deletes the current user together with all of their tasks
+func (c *HTTPClient) DeleteAccount(ctx context.Context) error {
+	err := c.doRequest(ctx, http.MethodDelete, "/me", nil, nil)
+	if c.cache != nil {
+		c.cache.clear()
+	}
+	return err
+}
+
+// ChangePassword replaces the current user's password; the server rejects a wrong current password with 401
+func (c *HTTPClient) ChangePassword(ctx context.Context, currentPassword, newPassword string) error {
+	req := ChangePasswordRequest{
+		CurrentPassword: currentPassword,
+		NewPassword:     newPassword,
+	}
+	return c.doRequest(ctx, http.MethodPut, "/me/password", req, nil)
+}
+
+// RestoreTask brings back a deleted task and returns it
+func (c *HTTPClient) RestoreTask(ctx context.Context, id int) (*Task, error) {
+	var task Task
+	path := fmt.Sprintf("/tasks/%d/restore", id)
+	err := c.doRequest(ctx, http.MethodPost, path, nil, &task)
+	c.invalidateTask(id)
+	if err != nil {
+		return nil, err
+	}
+	return &task, nil
+}
+
+// ArchiveTask hides a task from the default task list and returns it
+func (c *HTTPClient) ArchiveTask(ctx context.Context, id int) (*Task, error) {
+	var task Task
+	path := fmt.Sprintf("/tasks/%d/archive", id)
+	err := c.doRequest(ctx, http.MethodPost, path, nil, &task)
+	c.invalidateTask(id)
+	if err != nil {
+		return nil, err
+	}
+	return &task, nil
+}
+
+// ArchiveCompleted archives every completed task and returns how many the server archived
+func (c *HTTPClient) ArchiveCompleted(ctx context.Context) (int, error) {
+	var resp ArchiveCompletedResponse
+	err := c.doRequest(ctx, http.MethodPost, "/tasks/archive-completed", nil, &resp)
+	if c.cache != nil {
+		c.cache.clear()
+	}
+	if err != nil {
+		return 0, err
+	}
+	return resp.Archived, nil
+}
+
+// AddTag tags a task and returns it with its updated tags
+func (c *HTTPClient) AddTag(ctx context.Context, id int, tag string) (*Task, error) {
+	var task Task
+	path := fmt.Sprintf("/tasks/%d/tags", id)
+	err := c.doRequest(ctx, http.MethodPost, path, TagRequest{Tag: tag}, &task)
+	c.invalidateTask(id)
+	if err != nil {
+		return nil, err
+	}
+	return &task, nil
+}
+
+// RemoveTag removes a tag from a task and returns it with its remaining tags
+func (c *HTTPClient) RemoveTag(ctx context.Context, id int, tag string) (*Task, error) {
+	var task Task
+	path := fmt.Sprintf("/tasks/%d/tags/%s", id, url.PathEscape(tag))
+	err := c.doRequest(ctx, http.MethodDelete, path, nil, &task)
+	c.invalidateTask(id)
+	if err != nil {
+		return nil, err
+	}
+	return &task, nil
 }
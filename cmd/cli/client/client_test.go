@@ -1,12 +1,22 @@
 package client
 
 import (
+	"bytes"
+	"context"
 	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
 	"net/http"
 	"net/http/httptest"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 // TestHTTPClient_HandleErrorResponse_401 tests that 401 responses return AuthError
@@ -23,7 +33,7 @@ func TestHTTPClient_HandleErrorResponse_401(t *testing.T) {
 	client.SetToken("invalid-token")
 
 	// Try to get tasks, should return AuthError
-	_, err := client.GetTasks()
+	_, err := client.GetTasks(context.Background(), 0, 0, ListQuery{})
 
 	assert.Error(t, err)
 	assert.True(t, IsAuthError(err), "Expected AuthError for 401 response")
@@ -47,7 +57,7 @@ func TestHTTPClient_HandleErrorResponse_404(t *testing.T) {
 	client.SetToken("valid-token")
 
 	// Try to get a non-existent task
-	_, err := client.GetTask(999)
+	_, err := client.GetTask(context.Background(), 999)
 
 	assert.Error(t, err)
 	assert.False(t, IsAuthError(err), "404 should not return AuthError")
@@ -72,7 +82,7 @@ func TestHTTPClient_HandleErrorResponse_500(t *testing.T) {
 	client.SetToken("valid-token")
 
 	// Try to get tasks
-	_, err := client.GetTasks()
+	_, err := client.GetTasks(context.Background(), 0, 0, ListQuery{})
 
 	assert.Error(t, err)
 	assert.False(t, IsAuthError(err), "500 should not return AuthError")
@@ -83,6 +93,798 @@ func TestHTTPClient_HandleErrorResponse_500(t *testing.T) {
 	assert.Contains(t, apiErr.Message, "Server error")
 }
 
+// TestHTTPClient_HandleErrorResponse_429 tests that 429 responses return RateLimitError with the Retry-After delay
+func TestHTTPClient_HandleErrorResponse_429(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Retry-After", "3")
+		w.Header().Set("X-Request-ID", r.Header.Get("X-Request-ID"))
+		w.WriteHeader(http.StatusTooManyRequests)
+		json.NewEncoder(w).Encode(ErrorResponse{Error: "Too many requests"})
+	}))
+	defer server.Close()
+
+	client := NewHTTPClient(server.URL)
+
+	_, err := client.GetTasks(context.Background(), 0, 0, ListQuery{})
+
+	var rateErr *RateLimitError
+	require.ErrorAs(t, err, &rateErr)
+	assert.Equal(t, 3*time.Second, rateErr.RetryAfter)
+	assert.NotEmpty(t, rateErr.RequestID)
+	assert.Equal(t, "server busy, retry in 3s", rateErr.Error())
+}
+
+// TestHTTPClient_UnixSocket tests a round-trip request over a unix domain socket
+func TestHTTPClient_UnixSocket(t *testing.T) {
+	socketPath := filepath.Join(t.TempDir(), "tasks.sock")
+	listener, err := net.Listen("unix", socketPath)
+	require.NoError(t, err)
+
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/tasks", r.URL.Path)
+		assert.Equal(t, "Bearer socket-token", r.Header.Get("Authorization"))
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(TaskList{Tasks: []Task{{ID: 1, Description: "over socket"}}, Total: 1})
+	}))
+	server.Listener = listener
+	server.Start()
+	defer server.Close()
+
+	client := NewHTTPClient("unix://" + socketPath)
+	client.SetToken("socket-token")
+
+	list, err := client.GetTasks(context.Background(), 0, 0, ListQuery{})
+
+	require.NoError(t, err)
+	assert.Equal(t, []Task{{ID: 1, Description: "over socket"}}, list.Tasks)
+	assert.Equal(t, "unix://"+socketPath, client.GetServerURL())
+}
+
+// TestHTTPClient_GetTasks_Pagination tests that limit, offset, sort, created bounds and include_archived are sent as query parameters
+func TestHTTPClient_GetTasks_Pagination(t *testing.T) {
+	after := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	before := time.Date(2025, 1, 31, 23, 59, 59, 0, time.FixedZone("CET", 3600))
+	testCases := []struct {
+		name          string
+		limit         int
+		offset        int
+		query         ListQuery
+		expectedQuery string
+	}{
+		{name: "omits defaults", limit: 0, offset: 0, expectedQuery: ""},
+		{name: "sends limit and offset", limit: 20, offset: 40, expectedQuery: "limit=20&offset=40"},
+		{name: "sends offset only", limit: 0, offset: 50, expectedQuery: "offset=50"},
+		{name: "sends sort", limit: 0, offset: 0, query: ListQuery{Sort: "-created"}, expectedQuery: "sort=-created"},
+		{
+			name:          "sends created bounds",
+			query:         ListQuery{CreatedAfter: &after, CreatedBefore: &before},
+			expectedQuery: "created_after=2025-01-01T00%3A00%3A00Z&created_before=2025-01-31T23%3A59%3A59%2B01%3A00",
+		},
+		{name: "sends include_archived", query: ListQuery{IncludeArchived: true}, expectedQuery: "include_archived=true"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				assert.Equal(t, "/tasks", r.URL.Path)
+				assert.Equal(t, tc.expectedQuery, r.URL.RawQuery)
+				w.Header().Set("Content-Type", "application/json")
+				json.NewEncoder(w).Encode(TaskList{
+					Tasks:  []Task{{ID: 41, Description: "page task"}},
+					Total:  3200,
+					Limit:  20,
+					Offset: 40,
+				})
+			}))
+			defer server.Close()
+
+			client := NewHTTPClient(server.URL)
+
+			list, err := client.GetTasks(context.Background(), tc.limit, tc.offset, tc.query)
+
+			require.NoError(t, err)
+			assert.Equal(t, 3200, list.Total)
+			assert.Equal(t, []Task{{ID: 41, Description: "page task"}}, list.Tasks)
+		})
+	}
+}
+
+func TestHTTPClient_SearchTasks(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/tasks/search", r.URL.Path)
+		assert.Equal(t, "buy milk & eggs", r.URL.Query().Get("q"))
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode([]Task{{ID: 7, Description: "Buy milk & eggs"}})
+	}))
+	defer server.Close()
+
+	client := NewHTTPClient(server.URL)
+
+	tasks, err := client.SearchTasks(context.Background(), "buy milk & eggs")
+
+	require.NoError(t, err)
+	assert.Equal(t, []Task{{ID: 7, Description: "Buy milk & eggs"}}, tasks)
+}
+
+func TestHTTPClient_GetTasksByTag(t *testing.T) {
+	t.Run("fetches every page of the tag", func(t *testing.T) {
+		var offsets []string
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, "/tasks", r.URL.Path)
+			assert.Equal(t, "work", r.URL.Query().Get("tag"))
+			assert.False(t, r.URL.Query().Has("include_archived"))
+			offsets = append(offsets, r.URL.Query().Get("offset"))
+			w.Header().Set("Content-Type", "application/json")
+			if r.URL.Query().Get("offset") == "" {
+				fmt.Fprint(w, `{"tasks":[{"id":3,"description":"Report","tags":["work"]}],"total":2}`)
+				return
+			}
+			fmt.Fprint(w, `{"tasks":[{"id":5,"description":"Review","tags":["work"]}],"total":2}`)
+		}))
+		defer server.Close()
+
+		tasks, err := NewHTTPClient(server.URL).GetTasksByTag(context.Background(), "work", false)
+
+		require.NoError(t, err)
+		assert.Equal(t, []Task{
+			{ID: 3, Description: "Report", Tags: []string{"work"}},
+			{ID: 5, Description: "Review", Tags: []string{"work"}},
+		}, tasks)
+		assert.Equal(t, []string{"", "1"}, offsets)
+	})
+
+	t.Run("asks for archived tasks", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, "true", r.URL.Query().Get("include_archived"))
+			w.Header().Set("Content-Type", "application/json")
+			fmt.Fprint(w, `{"tasks":[],"total":0}`)
+		}))
+		defer server.Close()
+
+		tasks, err := NewHTTPClient(server.URL).GetTasksByTag(context.Background(), "work", true)
+
+		require.NoError(t, err)
+		assert.Empty(t, tasks)
+	})
+}
+
+func TestHTTPClient_Tags(t *testing.T) {
+	t.Run("AddTag posts the tag", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, http.MethodPost, r.Method)
+			assert.Equal(t, "/tasks/3/tags", r.URL.Path)
+			var got TagRequest
+			assert.NoError(t, json.NewDecoder(r.Body).Decode(&got))
+			assert.Equal(t, "work", got.Tag)
+			w.Header().Set("Content-Type", "application/json")
+			fmt.Fprint(w, `{"id":3,"description":"Report","tags":["work"]}`)
+		}))
+		defer server.Close()
+
+		task, err := NewHTTPClient(server.URL).AddTag(context.Background(), 3, "work")
+
+		require.NoError(t, err)
+		assert.Equal(t, []string{"work"}, task.Tags)
+	})
+	t.Run("RemoveTag deletes the tag by name", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, http.MethodDelete, r.Method)
+			assert.Equal(t, "/tasks/3/tags/c++", r.URL.Path)
+			w.Header().Set("Content-Type", "application/json")
+			fmt.Fprint(w, `{"id":3,"description":"Report"}`)
+		}))
+		defer server.Close()
+
+		task, err := NewHTTPClient(server.URL).RemoveTag(context.Background(), 3, "c++")
+
+		require.NoError(t, err)
+		assert.Empty(t, task.Tags)
+	})
+}
+
+func TestHTTPClient_Stats(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/tasks/stats", r.URL.Path)
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"total":12,"done":5,"pending":7}`)
+	}))
+	defer server.Close()
+
+	stats, err := NewHTTPClient(server.URL).Stats(context.Background())
+
+	require.NoError(t, err)
+	assert.Equal(t, &Stats{Total: 12, Done: 5, Pending: 7}, stats)
+}
+
+func TestHTTPClient_Limits(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/config/limits", r.URL.Path)
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"max_description_length":500}`)
+	}))
+	defer server.Close()
+
+	limits, err := NewHTTPClient(server.URL).Limits(context.Background())
+
+	require.NoError(t, err)
+	assert.Equal(t, &Limits{MaxDescriptionLength: 500}, limits)
+}
+
+func TestHTTPClient_Ping(t *testing.T) {
+	requests := 0
+	status := http.StatusServiceUnavailable
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		assert.Equal(t, "/health", r.URL.Path)
+		w.WriteHeader(status)
+	}))
+	c := NewHTTPClientWithOptions(server.URL, ClientOptions{Timeout: time.Second, MaxRetries: 2, RetryBackoff: time.Millisecond})
+
+	var apiErr *APIError
+	require.ErrorAs(t, c.Ping(context.Background()), &apiErr)
+	assert.Equal(t, http.StatusServiceUnavailable, apiErr.StatusCode)
+	assert.Equal(t, 1, requests, "a ping is never retried")
+
+	status = http.StatusOK
+	assert.NoError(t, c.Ping(context.Background()))
+
+	server.Close()
+	var netErr *NetworkError
+	assert.ErrorAs(t, c.Ping(context.Background()), &netErr)
+}
+
+func TestHTTPClient_Verbose(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"total":0,"done":0,"pending":0}`)
+	}))
+	defer server.Close()
+
+	trace := &bytes.Buffer{}
+	c := NewHTTPClient(server.URL)
+	c.SetToken("secret-jwt-token")
+	c.SetVerbose(trace)
+
+	_, err := c.Stats(context.Background())
+
+	require.NoError(t, err)
+	assert.Contains(t, trace.String(), "--> GET "+server.URL+"/tasks/stats\n")
+	assert.Contains(t, trace.String(), "    Authorization: Bearer [REDACTED]\n")
+	assert.Contains(t, trace.String(), "<-- 200 OK (")
+	assert.NotContains(t, trace.String(), "secret-jwt-token")
+}
+
+func TestHTTPClient_ToggleTask(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.Equal(t, "/tasks/3/toggle", r.URL.Path)
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"id":3,"description":"Buy milk","done":true}`)
+	}))
+	defer server.Close()
+
+	task, err := NewHTTPClient(server.URL).ToggleTask(context.Background(), 3)
+
+	require.NoError(t, err)
+	assert.Equal(t, &Task{ID: 3, Description: "Buy milk", Done: true}, task)
+}
+
+func TestHTTPClient_UpdateTask_Version(t *testing.T) {
+	var got UpdateTaskRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPatch, r.Method)
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&got))
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusConflict)
+		fmt.Fprint(w, `{"error":"task was changed by another request, reload it and try again"}`)
+	}))
+	defer server.Close()
+	done, version := true, 5
+
+	_, err := NewHTTPClient(server.URL).UpdateTask(context.Background(), 3, nil, &done, &version)
+
+	assert.True(t, IsVersionConflict(err))
+	if assert.NotNil(t, got.Version) {
+		assert.Equal(t, 5, *got.Version)
+	}
+	assert.False(t, IsVersionConflict(&APIError{StatusCode: http.StatusBadRequest}))
+}
+
+func TestHTTPClient_UpdateAllTaskStatus(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPut, r.Method)
+		assert.Equal(t, "/tasks/status", r.URL.Path)
+		assert.Equal(t, "false", r.URL.Query().Get("done"))
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"updated":4}`)
+	}))
+	defer server.Close()
+
+	updated, err := NewHTTPClient(server.URL).UpdateAllTaskStatus(context.Background(), false)
+
+	require.NoError(t, err)
+	assert.Equal(t, 4, updated)
+}
+
+func TestHTTPClient_DeleteTasks(t *testing.T) {
+	var got DeleteTasksRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodDelete, r.Method)
+		assert.Equal(t, "/tasks/batch", r.URL.Path)
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&got))
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"deleted":2}`)
+	}))
+	defer server.Close()
+
+	deleted, err := NewHTTPClient(server.URL).DeleteTasks(context.Background(), []int{1, 2, 3})
+
+	require.NoError(t, err)
+	assert.Equal(t, 2, deleted)
+	assert.Equal(t, []int{1, 2, 3}, got.IDs)
+}
+
+func TestHTTPClient_ArchiveCompleted(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.Equal(t, "/tasks/archive-completed", r.URL.Path)
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"archived":3}`)
+	}))
+	defer server.Close()
+
+	archived, err := NewHTTPClient(server.URL).ArchiveCompleted(context.Background())
+
+	require.NoError(t, err)
+	assert.Equal(t, 3, archived)
+}
+
+func TestHTTPClient_CreateTasks(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.Equal(t, "/tasks/batch", r.URL.Path)
+		var batch []CreateTaskRequest
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&batch))
+		assert.Equal(t, []CreateTaskRequest{{Description: "first"}, {Description: "second"}}, batch)
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		json.NewEncoder(w).Encode(CreateTasksResponse{IDs: []int{11, 12}})
+	}))
+	defer server.Close()
+
+	client := NewHTTPClient(server.URL)
+
+	ids, err := client.CreateTasks(context.Background(), []string{"first", "second"})
+
+	require.NoError(t, err)
+	assert.Equal(t, []int{11, 12}, ids)
+}
+
+// TestHTTPClient_Retry tests which requests are retried and how many attempts are made
+func TestHTTPClient_Retry(t *testing.T) {
+	testCases := []struct {
+		name             string
+		failures         int
+		call             func(c *HTTPClient) error
+		expectedAttempts int
+		expectErr        bool
+	}{
+		{
+			name:             "GET succeeds after 5xx responses",
+			failures:         2,
+			call:             func(c *HTTPClient) error { _, err := c.GetTask(context.Background(), 1); return err },
+			expectedAttempts: 3,
+		},
+		{
+			name:             "DELETE gives up after max retries",
+			failures:         10,
+			call:             func(c *HTTPClient) error { return c.DeleteTask(context.Background(), 1) },
+			expectedAttempts: 3,
+			expectErr:        true,
+		},
+		{
+			name:     "POST without idempotency key is never retried",
+			failures: 1,
+			call: func(c *HTTPClient) error {
+				_, err := c.CreateTasks(context.Background(), []string{"task"})
+				return err
+			},
+			expectedAttempts: 1,
+			expectErr:        true,
+		},
+		{
+			name:     "CreateTask is retried with its idempotency key",
+			failures: 1,
+			call: func(c *HTTPClient) error {
+				_, err := c.CreateTask(context.Background(), "task", false, nil, 0)
+				return err
+			},
+			expectedAttempts: 2,
+		},
+		{
+			name:     "PUT is never retried",
+			failures: 1,
+			call: func(c *HTTPClient) error {
+				done := true
+				_, err := c.UpdateTask(context.Background(), 1, nil, &done, nil)
+				return err
+			},
+			expectedAttempts: 1,
+			expectErr:        true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var attempts atomic.Int32
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if attempts.Add(1) <= int32(tc.failures) {
+					w.WriteHeader(http.StatusServiceUnavailable)
+					return
+				}
+				w.Header().Set("Content-Type", "application/json")
+				json.NewEncoder(w).Encode(Task{ID: 1, Description: "task"})
+			}))
+			defer server.Close()
+
+			client := NewHTTPClientWithOptions(server.URL, ClientOptions{
+				Timeout:      time.Second,
+				MaxRetries:   2,
+				RetryBackoff: time.Millisecond,
+			})
+
+			err := tc.call(client)
+
+			assert.Equal(t, tc.expectErr, err != nil, "unexpected error: %v", err)
+			assert.Equal(t, int32(tc.expectedAttempts), attempts.Load())
+		})
+	}
+}
+
+// TestHTTPClient_CreateTask_IdempotencyKey tests that retries of one create share a key and separate creates do not
+func TestHTTPClient_CreateTask_IdempotencyKey(t *testing.T) {
+	var keys []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		keys = append(keys, r.Header.Get(IdempotencyKeyHeader))
+		if len(keys) == 1 {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(Task{ID: 1, Description: "task"})
+	}))
+	defer server.Close()
+	client := NewHTTPClientWithOptions(server.URL, ClientOptions{
+		Timeout:      time.Second,
+		MaxRetries:   2,
+		RetryBackoff: time.Millisecond,
+	})
+
+	_, err := client.CreateTask(context.Background(), "task", false, nil, 0)
+	require.NoError(t, err)
+	_, err = client.CreateTask(context.Background(), "task", false, nil, 0)
+	require.NoError(t, err)
+
+	require.Len(t, keys, 3)
+	assert.NotEmpty(t, keys[0])
+	assert.Equal(t, keys[0], keys[1], "a retry should reuse the key")
+	assert.NotEqual(t, keys[1], keys[2], "a new create should get a new key")
+}
+
+// TestHTTPClient_RateLimitRetry tests that a 429 is waited out and retried once when the delay is acceptable
+func TestHTTPClient_RateLimitRetry(t *testing.T) {
+	testCases := []struct {
+		name             string
+		maxWait          time.Duration
+		retryAfter       string
+		limited          int
+		expectedAttempts int
+		expectErr        bool
+	}{
+		{
+			name:             "POST is retried after a short Retry-After",
+			maxWait:          2 * time.Second,
+			retryAfter:       "1",
+			limited:          1,
+			expectedAttempts: 2,
+		},
+		{
+			name:             "gives up when still rate limited after the retry",
+			maxWait:          2 * time.Second,
+			retryAfter:       "1",
+			limited:          10,
+			expectedAttempts: 2,
+			expectErr:        true,
+		},
+		{
+			name:             "does not wait longer than allowed",
+			maxWait:          2 * time.Second,
+			retryAfter:       "60",
+			limited:          1,
+			expectedAttempts: 1,
+			expectErr:        true,
+		},
+		{
+			name:             "does not retry without Retry-After",
+			maxWait:          2 * time.Second,
+			limited:          1,
+			expectedAttempts: 1,
+			expectErr:        true,
+		},
+		{
+			name:             "does not retry when waiting is disabled",
+			retryAfter:       "1",
+			limited:          1,
+			expectedAttempts: 1,
+			expectErr:        true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var attempts atomic.Int32
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if attempts.Add(1) <= int32(tc.limited) {
+					if tc.retryAfter != "" {
+						w.Header().Set("Retry-After", tc.retryAfter)
+					}
+					w.WriteHeader(http.StatusTooManyRequests)
+					return
+				}
+				w.Header().Set("Content-Type", "application/json")
+				w.WriteHeader(http.StatusCreated)
+				json.NewEncoder(w).Encode(Task{ID: 1, Description: "task"})
+			}))
+			defer server.Close()
+
+			client := NewHTTPClientWithOptions(server.URL, ClientOptions{
+				Timeout:          time.Second,
+				MaxRateLimitWait: tc.maxWait,
+			})
+
+			_, err := client.CreateTask(context.Background(), "task", false, nil, 0)
+
+			if tc.expectErr {
+				var rateErr *RateLimitError
+				assert.ErrorAs(t, err, &rateErr)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, int32(tc.expectedAttempts), attempts.Load())
+		})
+	}
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	now := time.Date(2030, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	testCases := []struct {
+		name     string
+		header   string
+		expected time.Duration
+	}{
+		{name: "seconds", header: "3", expected: 3 * time.Second},
+		{name: "HTTP date", header: now.Add(10 * time.Second).Format(http.TimeFormat), expected: 10 * time.Second},
+		{name: "date in the past", header: now.Add(-time.Minute).Format(http.TimeFormat), expected: 0},
+		{name: "negative seconds", header: "-5", expected: 0},
+		{name: "malformed", header: "soon", expected: 0},
+		{name: "missing", header: "", expected: 0},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, parseRetryAfter(tc.header, now))
+		})
+	}
+}
+
+// TestHTTPClient_Retry_NetworkError tests that the final NetworkError reports every attempt
+func TestHTTPClient_Retry_NetworkError(t *testing.T) {
+	server := httptest.NewServer(http.NotFoundHandler())
+	serverURL := server.URL
+	server.Close()
+
+	client := NewHTTPClientWithOptions(serverURL, ClientOptions{
+		Timeout:      time.Second,
+		MaxRetries:   2,
+		RetryBackoff: time.Millisecond,
+	})
+
+	_, err := client.GetTasks(context.Background(), 0, 0, ListQuery{})
+
+	var netErr *NetworkError
+	require.ErrorAs(t, err, &netErr)
+	assert.Equal(t, 3, netErr.Attempts)
+	assert.Contains(t, netErr.Error(), "after 3 attempts")
+}
+
+// TestHTTPClient_RequestID tests that every request carries an X-Request-ID that retries reuse and errors report
+func TestHTTPClient_RequestID(t *testing.T) {
+	var seen []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		seen = append(seen, r.Header.Get(RequestIDHeader))
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	client := NewHTTPClientWithOptions(server.URL, ClientOptions{
+		Timeout:      time.Second,
+		MaxRetries:   1,
+		RetryBackoff: time.Millisecond,
+	})
+
+	_, err := client.GetTasks(context.Background(), 0, 0, ListQuery{})
+	_, err2 := client.GetTasks(context.Background(), 0, 0, ListQuery{})
+
+	var apiErr *APIError
+	require.ErrorAs(t, err, &apiErr)
+	require.Len(t, seen, 4)
+	assert.NotEmpty(t, seen[0])
+	assert.Equal(t, seen[0], seen[1], "retries should reuse the request ID")
+	assert.Equal(t, seen[0], apiErr.RequestID)
+	require.ErrorAs(t, err2, &apiErr)
+	assert.NotEqual(t, seen[0], seen[2], "each request should get a new ID")
+	assert.Equal(t, seen[2], apiErr.RequestID)
+}
+
+// TestHTTPClient_GetTaskCache tests that cached tasks skip the server until they expire or change
+func TestHTTPClient_GetTaskCache(t *testing.T) {
+	var gets int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.Method {
+		case http.MethodGet:
+			gets++
+			json.NewEncoder(w).Encode(Task{ID: 1, Description: "task 1"})
+		case http.MethodPatch:
+			json.NewEncoder(w).Encode(Task{ID: 1, Description: "task 1", Done: true})
+		case http.MethodDelete:
+			w.WriteHeader(http.StatusNoContent)
+		}
+	}))
+	defer server.Close()
+
+	newClient := func() (*HTTPClient, *time.Time) {
+		client := NewHTTPClientWithCache(server.URL, time.Minute)
+		now := time.Now()
+		client.cache.now = func() time.Time { return now }
+		gets = 0
+		return client, &now
+	}
+	ctx := context.Background()
+
+	t.Run("serves repeated lookups from the cache", func(t *testing.T) {
+		client, _ := newClient()
+
+		first, err := client.GetTask(ctx, 1)
+		require.NoError(t, err)
+		second, err := client.GetTask(ctx, 1)
+		require.NoError(t, err)
+
+		assert.Equal(t, 1, gets)
+		assert.Equal(t, first, second)
+		second.Description = "changed"
+		third, _ := client.GetTask(ctx, 1)
+		assert.Equal(t, "task 1", third.Description, "callers must not be able to change the cached task")
+	})
+	t.Run("fetches again after the ttl", func(t *testing.T) {
+		client, now := newClient()
+
+		client.GetTask(ctx, 1)
+		*now = now.Add(time.Minute)
+		client.GetTask(ctx, 1)
+
+		assert.Equal(t, 2, gets)
+	})
+	t.Run("update and delete invalidate the task", func(t *testing.T) {
+		client, _ := newClient()
+		done := true
+
+		client.GetTask(ctx, 1)
+		_, err := client.UpdateTask(ctx, 1, nil, &done, nil)
+		require.NoError(t, err)
+		client.GetTask(ctx, 1)
+		require.NoError(t, client.DeleteTask(ctx, 1))
+		client.GetTask(ctx, 1)
+
+		assert.Equal(t, 3, gets)
+	})
+	t.Run("changing the token clears the cache", func(t *testing.T) {
+		client, _ := newClient()
+
+		client.GetTask(ctx, 1)
+		client.SetToken("other-user")
+		client.GetTask(ctx, 1)
+
+		assert.Equal(t, 2, gets)
+	})
+	t.Run("is disabled by default", func(t *testing.T) {
+		gets = 0
+		client := NewHTTPClient(server.URL)
+
+		client.GetTask(ctx, 1)
+		client.GetTask(ctx, 1)
+
+		assert.Nil(t, client.cache)
+		assert.Equal(t, 2, gets)
+	})
+}
+
+// TestTaskCache_Concurrent tests that the cache can be used from several goroutines at once
+func TestTaskCache_Concurrent(t *testing.T) {
+	cache := newTaskCache(time.Minute)
+	var wg sync.WaitGroup
+	for i := range 8 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for id := range 100 {
+				cache.put(Task{ID: id, Description: fmt.Sprintf("task %d", i)})
+				cache.get(id)
+				cache.invalidate(id)
+			}
+			cache.clear()
+		}()
+	}
+	wg.Wait()
+}
+
+// TestHTTPClient_ContextCancel tests that cancelling the context aborts a request without retrying it
+func TestHTTPClient_ContextCancel(t *testing.T) {
+	var attempts atomic.Int32
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts.Add(1)
+		select {
+		case <-r.Context().Done():
+		case <-release:
+		}
+	}))
+	defer server.Close()
+	defer close(release)
+
+	client := NewHTTPClientWithOptions(server.URL, ClientOptions{
+		Timeout:      5 * time.Second,
+		MaxRetries:   2,
+		RetryBackoff: time.Millisecond,
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	_, err := client.GetTasks(ctx, 0, 0, ListQuery{})
+
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	var netErr *NetworkError
+	assert.False(t, errors.As(err, &netErr), "cancellation should not be reported as a network error")
+	assert.Equal(t, int32(1), attempts.Load())
+}
+
+// TestHTTPClient_ContextCancel_DuringBackoff tests that a cancelled context stops waiting for the next retry
+func TestHTTPClient_ContextCancel_DuringBackoff(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	var attempts atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts.Add(1)
+		cancel()
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	client := NewHTTPClientWithOptions(server.URL, ClientOptions{
+		Timeout:      time.Second,
+		MaxRetries:   2,
+		RetryBackoff: time.Hour,
+	})
+
+	err := client.DeleteTask(ctx, 1)
+
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.Equal(t, int32(1), attempts.Load())
+}
+
 // TestIsAuthError tests the IsAuthError helper function
 func TestIsAuthError(t *testing.T) {
 	testCases := []struct {
@@ -95,6 +897,11 @@ func TestIsAuthError(t *testing.T) {
 			err:      &AuthError{Message: "token expired"},
 			expected: true,
 		},
+		{
+			name:     "Wrapped AuthError returns true",
+			err:      fmt.Errorf("adding task: %w", &AuthError{Message: "token expired"}),
+			expected: true,
+		},
 		{
 			name:     "APIError returns false",
 			err:      &APIError{StatusCode: 404, Message: "not found"},
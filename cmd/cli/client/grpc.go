@@ -0,0 +1,373 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	pb "myproject/adapters/grpcserver"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/connectivity"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// GRPCClient implements TaskClient on top of the server's gRPC TaskManager service.
+// Operations the service does not offer fail with an APIError carrying 501 Not Implemented,
+// and gRPC status codes are translated into the same error types HTTPClient returns
+type GRPCClient struct {
+	addr    string
+	conn    *grpc.ClientConn
+	client  pb.TaskManagerClient
+	token   string
+	timeout time.Duration
+}
+
+// NewGRPCClient creates a client for the gRPC server at addr, e.g. localhost:50051.
+// The connection is established lazily on the first call; timeout bounds every call, zero uses DefaultTimeout
+func NewGRPCClient(addr string, timeout time.Duration) (*GRPCClient, error) {
+	conn, err := grpc.NewClient(addr, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create gRPC client for %s: %w", addr, err)
+	}
+	if timeout <= 0 {
+		timeout = DefaultTimeout
+	}
+	return &GRPCClient{
+		addr:    addr,
+		conn:    conn,
+		client:  pb.NewTaskManagerClient(conn),
+		timeout: timeout,
+	}, nil
+}
+
+// Close releases the underlying connection
+func (c *GRPCClient) Close() error {
+	return c.conn.Close()
+}
+
+// SetToken sets the JWT token sent as bearer authorization metadata with every call
+func (c *GRPCClient) SetToken(token string) {
+	c.token = token
+}
+
+// GetServerURL returns the gRPC server address
+func (c *GRPCClient) GetServerURL() string {
+	return c.addr
+}
+
+// callContext bounds a call by the client timeout and attaches the token, if any
+func (c *GRPCClient) callContext(ctx context.Context) (context.Context, context.CancelFunc) {
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	if c.token != "" {
+		ctx = metadata.AppendToOutgoingContext(ctx, "authorization", "Bearer "+c.token)
+	}
+	return ctx, cancel
+}
+
+// Login authenticates the user and returns a JWT token
+func (c *GRPCClient) Login(ctx context.Context, email, password string) (string, error) {
+	ctx, cancel := c.callContext(ctx)
+	defer cancel()
+
+	reply, err := c.client.Login(ctx, &pb.LoginRequest{Email: email, Password: password})
+	if err != nil {
+		return "", c.convertError(ctx, err)
+	}
+	return reply.Token, nil
+}
+
+// Register creates a new user account and returns a JWT token
+func (c *GRPCClient) Register(ctx context.Context, email, password string) (string, error) {
+	ctx, cancel := c.callContext(ctx)
+	defer cancel()
+
+	reply, err := c.client.Register(ctx, &pb.RegisterRequest{Email: email, Password: password})
+	if err != nil {
+		return "", c.convertError(ctx, err)
+	}
+	return reply.Token, nil
+}
+
+// GetTasks retrieves one page of tasks; the gRPC service neither sorts nor filters them
+func (c *GRPCClient) GetTasks(ctx context.Context, limit, offset int, query ListQuery) (*TaskList, error) {
+	if query.Sort != "" {
+		return nil, notSupported("sorting tasks")
+	}
+	if query.CreatedAfter != nil || query.CreatedBefore != nil {
+		return nil, notSupported("filtering tasks by creation time")
+	}
+	if query.IncludeArchived {
+		return nil, notSupported("listing archived tasks")
+	}
+
+	ctx, cancel := c.callContext(ctx)
+	defer cancel()
+
+	reply, err := c.client.ListTasks(ctx, &pb.ListTasksRequest{Limit: int32(limit), Offset: int32(offset)})
+	if err != nil {
+		return nil, c.convertError(ctx, err)
+	}
+
+	tasks := make([]Task, len(reply.Tasks))
+	for i, task := range reply.Tasks {
+		tasks[i] = fromProtoTask(task)
+	}
+	return &TaskList{
+		Tasks:  tasks,
+		Total:  int(reply.Total),
+		Limit:  int(reply.Limit),
+		Offset: int(reply.Offset),
+	}, nil
+}
+
+// GetTask retrieves a single task by ID
+func (c *GRPCClient) GetTask(ctx context.Context, id int) (*Task, error) {
+	ctx, cancel := c.callContext(ctx)
+	defer cancel()
+
+	reply, err := c.client.GetTask(ctx, &pb.GetTaskRequest{Id: int32(id)})
+	if err != nil {
+		return nil, c.convertError(ctx, err)
+	}
+	task := fromProtoTask(reply.Task)
+	return &task, nil
+}
+
+// CreateTask creates a new task; the gRPC service takes no due date or priority
+func (c *GRPCClient) CreateTask(ctx context.Context, description string, done bool, dueDate *time.Time, priority int) (*Task, error) {
+	if dueDate != nil || priority != 0 {
+		return nil, notSupported("due dates and priorities")
+	}
+
+	ctx, cancel := c.callContext(ctx)
+	defer cancel()
+
+	reply, err := c.client.CreateTask(ctx, &pb.CreateTaskRequest{Description: description, Done: done})
+	if err != nil {
+		return nil, c.convertError(ctx, err)
+	}
+	if reply.Task == nil {
+		return &Task{ID: int(reply.TaskId), Description: description, Done: done}, nil
+	}
+	task := fromProtoTask(reply.Task)
+	return &task, nil
+}
+
+// UpdateTask changes a task's description and/or completion status
+// If version is set, a task changed since then fails with a 409 conflict error
+func (c *GRPCClient) UpdateTask(ctx context.Context, id int, description *string, done *bool, version *int) (*Task, error) {
+	ctx, cancel := c.callContext(ctx)
+	defer cancel()
+
+	request := &pb.UpdateTaskRequest{Id: int32(id), Description: description, Done: done}
+	if version != nil {
+		v := int32(*version)
+		request.Version = &v
+	}
+	reply, err := c.client.UpdateTask(ctx, request)
+	if err != nil {
+		return nil, c.convertError(ctx, err)
+	}
+	task := fromProtoTask(reply.Task)
+	return &task, nil
+}
+
+// DeleteTask deletes a task by ID
+func (c *GRPCClient) DeleteTask(ctx context.Context, id int) error {
+	ctx, cancel := c.callContext(ctx)
+	defer cancel()
+
+	if _, err := c.client.DeleteTask(ctx, &pb.DeleteTaskRequest{Id: int32(id)}); err != nil {
+		return c.convertError(ctx, err)
+	}
+	return nil
+}
+
+// SearchTasks is not offered by the gRPC service
+func (c *GRPCClient) SearchTasks(ctx context.Context, query string) ([]Task, error) {
+	return nil, notSupported("searching tasks")
+}
+
+// GetTasksByTag is not offered by the gRPC service
+func (c *GRPCClient) GetTasksByTag(ctx context.Context, tag string, includeArchived bool) ([]Task, error) {
+	return nil, notSupported("listing tasks by tag")
+}
+
+// Stats is not offered by the gRPC service
+func (c *GRPCClient) Stats(ctx context.Context) (*Stats, error) {
+	return nil, notSupported("task statistics")
+}
+
+// CreateTasks is not offered by the gRPC service; callers fall back to CreateTask on 501
+func (c *GRPCClient) CreateTasks(ctx context.Context, descriptions []string) ([]int, error) {
+	return nil, notSupported("batch task creation")
+}
+
+// ToggleTask is not offered by the gRPC service
+func (c *GRPCClient) ToggleTask(ctx context.Context, id int) (*Task, error) {
+	return nil, notSupported("toggling task status")
+}
+
+// UpdateAllTaskStatus is not offered by the gRPC service
+func (c *GRPCClient) UpdateAllTaskStatus(ctx context.Context, done bool) (int, error) {
+	return 0, notSupported("bulk status changes")
+}
+
+// DeleteTasks is not offered by the gRPC service
+func (c *GRPCClient) DeleteTasks(ctx context.Context, ids []int) (int, error) {
+	return 0, notSupported("batch task deletion")
+}
+
+// PurgeTask is not offered by the gRPC service
+func (c *GRPCClient) PurgeTask(ctx context.Context, id int) error {
+	return notSupported("purging tasks")
+}
+
+// RestoreTask is not offered by the gRPC service
+func (c *GRPCClient) RestoreTask(ctx context.Context, id int) (*Task, error) {
+	return nil, notSupported("restoring tasks")
+}
+
+// ArchiveTask is not offered by the gRPC service
+func (c *GRPCClient) ArchiveTask(ctx context.Context, id int) (*Task, error) {
+	return nil, notSupported("archiving tasks")
+}
+
+// ArchiveCompleted is not offered by the gRPC service
+func (c *GRPCClient) ArchiveCompleted(ctx context.Context) (int, error) {
+	return 0, notSupported("archiving tasks")
+}
+
+// AddTag is not offered by the gRPC service
+func (c *GRPCClient) AddTag(ctx context.Context, id int, tag string) (*Task, error) {
+	return nil, notSupported("tags")
+}
+
+// RemoveTag is not offered by the gRPC service
+func (c *GRPCClient) RemoveTag(ctx context.Context, id int, tag string) (*Task, error) {
+	return nil, notSupported("tags")
+}
+
+// Ping checks that the gRPC server accepts connections, connecting first if the connection is idle.
+// A connection that is failing reports a NetworkError at once, while gRPC keeps reconnecting in the background
+func (c *GRPCClient) Ping(ctx context.Context) error {
+	c.conn.Connect()
+	for {
+		state := c.conn.GetState()
+		switch state {
+		case connectivity.Ready:
+			return nil
+		case connectivity.TransientFailure, connectivity.Shutdown:
+			return &NetworkError{URL: c.addr, Err: fmt.Errorf("connection is in state %s", state)}
+		}
+		if !c.conn.WaitForStateChange(ctx, state) {
+			return ctx.Err()
+		}
+	}
+}
+
+// Limits is not offered by the gRPC service
+func (c *GRPCClient) Limits(ctx context.Context) (*Limits, error) {
+	return nil, notSupported("reading server limits")
+}
+
+// Me is not offered by the gRPC service
+func (c *GRPCClient) Me(ctx context.Context) (*User, error) {
+	return nil, notSupported("account details")
+}
+
+// DeleteAccount is not offered by the gRPC service
+func (c *GRPCClient) DeleteAccount(ctx context.Context) error {
+	return notSupported("deleting accounts")
+}
+
+// ChangePassword is not offered by the gRPC service
+func (c *GRPCClient) ChangePassword(ctx context.Context, currentPassword, newPassword string) error {
+	return notSupported("changing passwords")
+}
+
+// notSupported reports an operation the gRPC service has no method for
+func notSupported(operation string) error {
+	return &APIError{
+		StatusCode: http.StatusNotImplemented,
+		Message:    operation + " is not supported over gRPC",
+	}
+}
+
+// convertError translates a gRPC status into the error types HTTPClient returns, so callers
+// handle both transports alike. A call aborted by ctx keeps ctx's error
+func (c *GRPCClient) convertError(ctx context.Context, err error) error {
+	st, ok := status.FromError(err)
+	if !ok {
+		return &NetworkError{URL: c.addr, Err: err}
+	}
+
+	switch st.Code() {
+	case codes.Canceled, codes.DeadlineExceeded:
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return ctxErr
+		}
+		return &NetworkError{URL: c.addr, Err: errors.New(st.Message())}
+	case codes.Unavailable:
+		return &NetworkError{URL: c.addr, Err: errors.New(st.Message())}
+	case codes.Unauthenticated:
+		return &AuthError{Message: st.Message()}
+	case codes.ResourceExhausted:
+		return &RateLimitError{}
+	default:
+		return &APIError{StatusCode: httpStatus(st.Code()), Message: st.Message()}
+	}
+}
+
+// httpStatus maps a gRPC code to the HTTP status the REST API answers with in the same situation
+func httpStatus(code codes.Code) int {
+	switch code {
+	case codes.InvalidArgument, codes.OutOfRange, codes.FailedPrecondition:
+		return http.StatusBadRequest
+	case codes.NotFound:
+		return http.StatusNotFound
+	case codes.AlreadyExists, codes.Aborted:
+		return http.StatusConflict
+	case codes.PermissionDenied:
+		return http.StatusForbidden
+	case codes.Unimplemented:
+		return http.StatusNotImplemented
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// fromProtoTask converts a protobuf task into the client's Task
+func fromProtoTask(pt *pb.Task) Task {
+	if pt == nil {
+		return Task{}
+	}
+	task := Task{
+		ID:          int(pt.Id),
+		Description: pt.Description,
+		Done:        pt.Done,
+		CreatedAt:   timeOf(pt.CreatedAt),
+		UpdatedAt:   timeOf(pt.UpdatedAt),
+		Version:     int(pt.Version),
+	}
+	if pt.CompletedAt != nil {
+		completedAt := pt.CompletedAt.AsTime()
+		task.CompletedAt = &completedAt
+	}
+	return task
+}
+
+// timeOf converts an optional protobuf timestamp, returning the zero time when it is unset
+func timeOf(ts *timestamppb.Timestamp) time.Time {
+	if ts == nil {
+		return time.Time{}
+	}
+	return ts.AsTime()
+}
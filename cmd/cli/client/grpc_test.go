@@ -0,0 +1,203 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"net"
+	"net/http"
+	"testing"
+	"time"
+
+	"myproject/adapters/grpcserver"
+	"myproject/domain"
+	"myproject/infrastructure/testhelpers"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+)
+
+// startGRPCServer serves the TaskManager service on a random local port and returns its address
+func startGRPCServer(t *testing.T, taskService *testhelpers.SpyTaskService, authService *testhelpers.SpyAuthService, tokens *testhelpers.StubTokenGenerator) string {
+	t.Helper()
+
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	interceptor := grpcserver.NewAuthInterceptor(tokens, logger)
+	server := grpc.NewServer(grpc.UnaryInterceptor(interceptor.UnaryInterceptor))
+	grpcserver.RegisterTaskManagerServer(server, grpcserver.NewTaskManageServer(authService, taskService, logger))
+
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	go server.Serve(lis)
+	t.Cleanup(server.Stop)
+
+	return lis.Addr().String()
+}
+
+func newTestGRPCClient(t *testing.T, addr string) *GRPCClient {
+	t.Helper()
+
+	c, err := NewGRPCClient(addr, 5*time.Second)
+	require.NoError(t, err)
+	t.Cleanup(func() { c.Close() })
+	c.SetToken("valid-token")
+	return c
+}
+
+func TestGRPCClient_Login(t *testing.T) {
+	authService := &testhelpers.SpyAuthService{ResultToken: "jwt-token"}
+	addr := startGRPCServer(t, &testhelpers.SpyTaskService{}, authService, &testhelpers.StubTokenGenerator{})
+	c := newTestGRPCClient(t, addr)
+
+	token, err := c.Login(context.Background(), "user@example.com", "password123")
+
+	require.NoError(t, err)
+	assert.Equal(t, "jwt-token", token)
+	assert.Equal(t, "user@example.com", authService.LastEmail)
+	assert.Equal(t, addr, c.GetServerURL())
+}
+
+func TestGRPCClient_TaskOperations(t *testing.T) {
+	created := time.Date(2025, 3, 1, 9, 0, 0, 0, time.UTC)
+	taskService := &testhelpers.SpyTaskService{
+		ResultTask: domain.Task{ID: 7, Description: "Buy milk", CreatedAt: created, UpdatedAt: created},
+		TasksTable: []domain.Task{
+			{ID: 1, Description: "First"},
+			{ID: 2, Description: "Second", Done: true},
+		},
+	}
+	tokens := &testhelpers.StubTokenGenerator{Claims: &domain.Claims{UserID: 42}}
+	addr := startGRPCServer(t, taskService, &testhelpers.SpyAuthService{}, tokens)
+	c := newTestGRPCClient(t, addr)
+	ctx := context.Background()
+
+	t.Run("CreateTask", func(t *testing.T) {
+		task, err := c.CreateTask(ctx, "Buy milk", true, nil, 0)
+
+		require.NoError(t, err)
+		assert.Equal(t, 7, task.ID)
+		assert.Equal(t, "Buy milk", task.Description)
+		assert.Equal(t, created, task.CreatedAt)
+		assert.True(t, taskService.LastDone)
+		assert.Equal(t, 42, taskService.LastUserID)
+	})
+
+	t.Run("GetTasks", func(t *testing.T) {
+		list, err := c.GetTasks(ctx, 10, 0, ListQuery{})
+
+		require.NoError(t, err)
+		assert.Equal(t, 2, list.Total)
+		assert.Equal(t, 10, list.Limit)
+		require.Len(t, list.Tasks, 2)
+		assert.Equal(t, "Second", list.Tasks[1].Description)
+		assert.True(t, list.Tasks[1].Done)
+	})
+
+	t.Run("GetTask", func(t *testing.T) {
+		task, err := c.GetTask(ctx, 7)
+
+		require.NoError(t, err)
+		assert.Equal(t, 7, task.ID)
+		assert.Equal(t, 7, taskService.LastTaskID)
+	})
+
+	t.Run("UpdateTask", func(t *testing.T) {
+		done := true
+		version := 2
+		_, err := c.UpdateTask(ctx, 7, nil, &done, &version)
+
+		require.NoError(t, err)
+		assert.Nil(t, taskService.UpdateDescription)
+		require.NotNil(t, taskService.UpdateDone)
+		assert.True(t, *taskService.UpdateDone)
+		require.NotNil(t, taskService.UpdateVersion)
+		assert.Equal(t, 2, *taskService.UpdateVersion)
+	})
+
+	t.Run("DeleteTask", func(t *testing.T) {
+		err := c.DeleteTask(ctx, 7)
+
+		require.NoError(t, err)
+		assert.Equal(t, []int{7}, taskService.DeletedTaskIDs)
+	})
+}
+
+func TestGRPCClient_Errors(t *testing.T) {
+	t.Run("NotFound maps to APIError 404", func(t *testing.T) {
+		taskService := &testhelpers.SpyTaskService{ResultErr: domain.ErrTaskNotFound}
+		tokens := &testhelpers.StubTokenGenerator{Claims: &domain.Claims{UserID: 1}}
+		c := newTestGRPCClient(t, startGRPCServer(t, taskService, &testhelpers.SpyAuthService{}, tokens))
+
+		_, err := c.GetTask(context.Background(), 99)
+
+		var apiErr *APIError
+		require.ErrorAs(t, err, &apiErr)
+		assert.Equal(t, http.StatusNotFound, apiErr.StatusCode)
+		assert.Equal(t, "task not found", apiErr.Message)
+	})
+
+	t.Run("Unauthenticated maps to AuthError", func(t *testing.T) {
+		tokens := &testhelpers.StubTokenGenerator{Err: errors.New("token expired")}
+		c := newTestGRPCClient(t, startGRPCServer(t, &testhelpers.SpyTaskService{}, &testhelpers.SpyAuthService{}, tokens))
+
+		_, err := c.GetTasks(context.Background(), 10, 0, ListQuery{})
+
+		assert.True(t, IsAuthError(err), "Expected AuthError, got %v", err)
+	})
+
+	t.Run("Unreachable server maps to NetworkError", func(t *testing.T) {
+		lis, err := net.Listen("tcp", "127.0.0.1:0")
+		require.NoError(t, err)
+		addr := lis.Addr().String()
+		lis.Close()
+		c := newTestGRPCClient(t, addr)
+
+		_, err = c.GetTask(context.Background(), 1)
+
+		var netErr *NetworkError
+		require.ErrorAs(t, err, &netErr)
+		assert.Equal(t, addr, netErr.URL)
+	})
+
+	t.Run("Cancelled context keeps its error", func(t *testing.T) {
+		c := newTestGRPCClient(t, startGRPCServer(t, &testhelpers.SpyTaskService{}, &testhelpers.SpyAuthService{}, &testhelpers.StubTokenGenerator{}))
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		_, err := c.GetTask(ctx, 1)
+
+		assert.ErrorIs(t, err, context.Canceled)
+	})
+}
+
+func TestGRPCClient_NotSupported(t *testing.T) {
+	c := newTestGRPCClient(t, "localhost:50051")
+	ctx := context.Background()
+	due := time.Now()
+
+	testCases := []struct {
+		name string
+		call func() error
+	}{
+		{name: "sorted list", call: func() error { _, err := c.GetTasks(ctx, 10, 0, ListQuery{Sort: "-id"}); return err }},
+		{name: "created filter", call: func() error { _, err := c.GetTasks(ctx, 10, 0, ListQuery{CreatedAfter: &due}); return err }},
+		{name: "due date", call: func() error { _, err := c.CreateTask(ctx, "task", false, &due, 0); return err }},
+		{name: "batch create", call: func() error { _, err := c.CreateTasks(ctx, []string{"a", "b"}); return err }},
+		{name: "search", call: func() error { _, err := c.SearchTasks(ctx, "milk"); return err }},
+		{name: "tags", call: func() error { _, err := c.AddTag(ctx, 1, "work"); return err }},
+		{name: "account", call: func() error { _, err := c.Me(ctx); return err }},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.call()
+
+			var apiErr *APIError
+			require.ErrorAs(t, err, &apiErr)
+			assert.Equal(t, http.StatusNotImplemented, apiErr.StatusCode)
+			assert.Contains(t, apiErr.Message, "not supported over gRPC")
+		})
+	}
+}
@@ -2,26 +2,187 @@ package main
 
 import (
 	"fmt"
+	"io"
+	"myproject/cmd/cli/auth"
+	"myproject/cmd/cli/client"
+	"myproject/domain/validation"
+	"net"
 	"net/url"
 	"os"
+	"path/filepath"
 	"strings"
+	"time"
+
+	"github.com/spf13/pflag"
+	"github.com/spf13/viper"
+)
+
+const (
+	defaultMaxRetries = 2
+	maxRetriesLimit   = 10
+)
+
+// Transports the CLI can reach the server with, selected by Config.Protocol
+const (
+	protocolHTTP = "http"
+	protocolGRPC = "grpc"
 )
 
 // Config holds the CLI configuration settings
 type Config struct {
-	ServerURL string
+	// ServerURL is the REST API URL, or the host:port of the gRPC server when Protocol is grpc
+	ServerURL string `mapstructure:"server_url"`
+	// Protocol selects the transport: http or grpc; empty means http
+	Protocol string `mapstructure:"protocol"`
+	// TokenFile is where the login token is stored between runs
+	TokenFile string `mapstructure:"token_file"`
+	// EncryptToken stores the token encrypted with a passphrase that is asked for once per session
+	EncryptToken bool `mapstructure:"encrypt_token"`
+	// AutoList shows the updated task list after every command that changes tasks
+	AutoList bool `mapstructure:"auto_list"`
+	// ShowAge appends how long ago each task was created when tasks are displayed
+	ShowAge bool `mapstructure:"show_age"`
+	// Timeout bounds every HTTP request; zero uses client.DefaultTimeout
+	Timeout time.Duration `mapstructure:"timeout"`
+	// MaxRetries is how often GET, DELETE and task create requests are retried after network errors or 5xx responses
+	MaxRetries int `mapstructure:"retries"`
+	// RetryBackoff is the wait before the first retry, doubled for every further one
+	RetryBackoff time.Duration `mapstructure:"retry_backoff"`
+	// WaitForServer is how long the CLI keeps checking GET /health on launch for a server that is still starting;
+	// zero does not wait, so an unreachable server fails the first request
+	WaitForServer time.Duration `mapstructure:"wait_for_server"`
+	// CacheTTL keeps looked-up tasks for this long to save round-trips; zero disables the cache
+	CacheTTL time.Duration `mapstructure:"cache_ttl"`
+	// RateLimitWait is the longest Retry-After the client waits out before retrying a rate limited request once; zero never waits
+	RateLimitWait time.Duration `mapstructure:"rate_limit_wait"`
+	// Verbose traces every HTTP request, with its status and duration, to stderr (--verbose)
+	Verbose bool `mapstructure:"verbose"`
+	// JSON prints command results as JSON on stdout and errors as JSON on stderr, for scripting
+	JSON bool `mapstructure:"json"`
+	// Plain prints ASCII prefixes such as [OK] and [ERR] instead of emoji (--plain, --no-emoji or NO_COLOR)
+	Plain bool `mapstructure:"plain"`
+	// MaxDescriptionLength is used when the server does not report its limit on GET /config/limits; zero uses the default of 200
+	MaxDescriptionLength int `mapstructure:"max_description_length"`
+	// ShowConfig prints the resolved configuration and exits (--show-config)
+	ShowConfig bool `mapstructure:"-"`
+	// Command is the one-shot command and its arguments given after the flags, e.g. add "buy milk";
+	// empty runs the interactive loop
+	Command []string `mapstructure:"-"`
+
+	// sources records where each setting came from: flag, env, config file or default
+	sources map[string]string
 }
 
-// LoadConfig loads configuration from environment variables with defaults
-func LoadConfig() (*Config, error) {
-	// Read server URL from environment variable, default to localhost
-	serverURL := os.Getenv("TASK_SERVER_URL")
-	if serverURL == "" {
-		serverURL = "http://localhost:8080"
+// setting ties a config key to the flag and environment variable that can set it.
+type setting struct {
+	key  string
+	flag string
+	env  string
+}
+
+// settings lists every config key in --show-config order.
+var settings = []setting{
+	{key: "server_url", flag: "server", env: "TASK_SERVER_URL"},
+	{key: "protocol", flag: "protocol", env: "TASK_CLIENT_PROTOCOL"},
+	{key: "token_file", flag: "token-file", env: "TASK_TOKEN_FILE"},
+	{key: "encrypt_token", env: "TASK_ENCRYPT_TOKEN"},
+	{key: "timeout", flag: "timeout", env: "TASK_CLIENT_TIMEOUT"},
+	{key: "retries", env: "TASK_CLIENT_RETRIES"},
+	{key: "retry_backoff", env: "TASK_CLIENT_RETRY_BACKOFF"},
+	{key: "wait_for_server", flag: "wait-for-server", env: "TASK_WAIT_FOR_SERVER"},
+	{key: "cache_ttl", env: "TASK_CLIENT_CACHE_TTL"},
+	{key: "rate_limit_wait", env: "TASK_CLIENT_RATE_LIMIT_WAIT"},
+	{key: "show_age", env: "TASK_SHOW_AGE"},
+	{key: "auto_list", flag: "auto-list", env: "TASK_AUTO_LIST"},
+	{key: "max_description_length", env: "TASK_MAX_DESCRIPTION_LENGTH"},
+	{key: "verbose", flag: "verbose", env: "TASK_CLIENT_VERBOSE"},
+	{key: "json", flag: "json"},
+	{key: "plain", flag: "plain"},
+}
+
+// LoadConfig resolves the configuration from command-line flags, environment variables,
+// the config file and defaults, in that order of precedence. The config file is
+// config.yaml next to the default token file (~/.task-cli, or $XDG_CONFIG_HOME/task-cli when set)
+// unless --config or TASK_CLI_CONFIG names another one.
+func LoadConfig(args []string) (*Config, error) {
+	v := viper.New()
+	v.SetDefault("server_url", "http://localhost:8080")
+	v.SetDefault("protocol", protocolHTTP)
+	v.SetDefault("token_file", auth.DefaultTokenPath())
+	v.SetDefault("encrypt_token", false)
+	v.SetDefault("timeout", client.DefaultTimeout)
+	v.SetDefault("retries", defaultMaxRetries)
+	v.SetDefault("retry_backoff", client.DefaultRetryBackoff)
+	v.SetDefault("wait_for_server", time.Duration(0))
+	v.SetDefault("cache_ttl", time.Duration(0))
+	v.SetDefault("rate_limit_wait", time.Duration(0))
+	v.SetDefault("show_age", false)
+	v.SetDefault("auto_list", false)
+	v.SetDefault("max_description_length", validation.DefaultMaxDescriptionLength)
+	v.SetDefault("verbose", false)
+	v.SetDefault("json", false)
+	v.SetDefault("plain", false)
+
+	fs := pflag.NewFlagSet("task-cli", pflag.ContinueOnError)
+	fs.String("config", "", "config file (default ~/.task-cli/config.yaml, or under $XDG_CONFIG_HOME/task-cli)")
+	fs.String("server", "", "server URL, e.g. https://tasks.example.com or unix:///tmp/tasks.sock")
+	fs.String("protocol", "", "transport used to reach the server: http or grpc")
+	fs.String("token-file", "", "file the login token is stored in")
+	fs.Duration("timeout", 0, "timeout for each request to the server")
+	fs.Duration("wait-for-server", 0, "keep checking for this long on launch until the server answers, e.g. 30s")
+	fs.Bool("auto-list", false, "show the task list again after every command that changes tasks")
+	fs.Bool("verbose", false, "print every HTTP request with its status and duration to stderr")
+	fs.Bool("json", false, "print command results and errors as JSON")
+	fs.Bool("plain", false, "print plain ASCII prefixes like [OK] instead of emoji")
+	fs.Bool("no-emoji", false, "same as --plain")
+	showConfig := fs.Bool("show-config", false, "print the resolved configuration and exit")
+	// Flags after the command name belong to the command, e.g. list --sort -created
+	fs.SetInterspersed(false)
+	if err := fs.Parse(args); err != nil {
+		return nil, err
+	}
+
+	configFile, _ := fs.GetString("config")
+	if configFile == "" {
+		configFile = os.Getenv("TASK_CLI_CONFIG")
+	}
+	if configFile != "" {
+		v.SetConfigFile(configFile)
+	} else {
+		v.SetConfigName("config")
+		v.SetConfigType("yaml")
+		v.AddConfigPath(filepath.Dir(auth.DefaultTokenPath()))
+	}
+	if err := v.ReadInConfig(); err != nil {
+		if _, ok := err.(viper.ConfigFileNotFoundError); !ok {
+			return nil, fmt.Errorf("failed to read config: %w", err)
+		}
 	}
 
-	config := &Config{
-		ServerURL: serverURL,
+	for _, s := range settings {
+		if s.env != "" {
+			v.BindEnv(s.key, s.env)
+		}
+		if s.flag != "" {
+			v.BindPFlag(s.key, fs.Lookup(s.flag))
+		}
+	}
+
+	config := &Config{}
+	if err := v.Unmarshal(config); err != nil {
+		return nil, fmt.Errorf("invalid configuration: %w", err)
+	}
+	config.ShowConfig = *showConfig
+	config.Command = fs.Args()
+	config.sources = make(map[string]string, len(settings))
+	for _, s := range settings {
+		config.sources[s.key] = settingSource(v, fs, s)
+	}
+	// NO_COLOR asks for plain output whatever its value (https://no-color.org), so it is not bound as a bool
+	if noEmoji, _ := fs.GetBool("no-emoji"); noEmoji {
+		config.Plain, config.sources["plain"] = true, "flag"
+	} else if !config.Plain && os.Getenv("NO_COLOR") != "" {
+		config.Plain, config.sources["plain"] = true, "env"
 	}
 
 	// Validate the configuration
@@ -32,17 +193,134 @@ func LoadConfig() (*Config, error) {
 	return config, nil
 }
 
+// settingSource reports whether a setting came from a flag, the environment, the config file or its default.
+func settingSource(v *viper.Viper, fs *pflag.FlagSet, s setting) string {
+	if s.flag != "" && fs.Changed(s.flag) {
+		return "flag"
+	}
+	if s.env != "" && os.Getenv(s.env) != "" {
+		return "env"
+	}
+	if v.ConfigFileUsed() != "" && v.InConfig(s.key) {
+		return "config file"
+	}
+	return "default"
+}
+
+// PrintConfig writes every resolved setting with its source, as shown by --show-config.
+func (c *Config) PrintConfig(w io.Writer) {
+	values := map[string]any{
+		"server_url":             c.ServerURL,
+		"protocol":               c.Protocol,
+		"token_file":             c.TokenFile,
+		"encrypt_token":          c.EncryptToken,
+		"timeout":                c.Timeout,
+		"retries":                c.MaxRetries,
+		"retry_backoff":          c.RetryBackoff,
+		"wait_for_server":        c.WaitForServer,
+		"cache_ttl":              c.CacheTTL,
+		"rate_limit_wait":        c.RateLimitWait,
+		"show_age":               c.ShowAge,
+		"auto_list":              c.AutoList,
+		"max_description_length": c.MaxDescriptionLength,
+		"verbose":                c.Verbose,
+		"json":                   c.JSON,
+		"plain":                  c.Plain,
+	}
+	for _, s := range settings {
+		source := c.sources[s.key]
+		if source == "" {
+			source = "default"
+		}
+		fmt.Fprintf(w, "%s: %v (%s)\n", s.key, values[s.key], source)
+	}
+}
+
 // Validate ensures the configuration is valid
 func (c *Config) Validate() error {
-	// Validate server URL format
-	if err := validateURL(c.ServerURL); err != nil {
-		return fmt.Errorf("invalid server URL: %w", err)
+	// Validate the server address for the chosen transport
+	switch c.Protocol {
+	case "", protocolHTTP:
+		if err := validateURL(c.ServerURL); err != nil {
+			return fmt.Errorf("invalid server URL: %w", err)
+		}
+	case protocolGRPC:
+		if err := validateGRPCAddress(c.ServerURL); err != nil {
+			return fmt.Errorf("invalid server URL: %w", err)
+		}
+	default:
+		return fmt.Errorf("protocol must be %s or %s, got: %q", protocolHTTP, protocolGRPC, c.Protocol)
+	}
+
+	// Validate retry policy
+	if c.Timeout < 0 {
+		return fmt.Errorf("timeout cannot be negative, got: %v", c.Timeout)
+	}
+	if c.MaxRetries < 0 || c.MaxRetries > maxRetriesLimit {
+		return fmt.Errorf("retries must be between 0 and %d, got: %d", maxRetriesLimit, c.MaxRetries)
+	}
+	if c.RetryBackoff < 0 {
+		return fmt.Errorf("retry backoff cannot be negative, got: %v", c.RetryBackoff)
+	}
+	if c.WaitForServer < 0 {
+		return fmt.Errorf("wait for server cannot be negative, got: %v", c.WaitForServer)
+	}
+	if c.CacheTTL < 0 {
+		return fmt.Errorf("cache TTL cannot be negative, got: %v", c.CacheTTL)
+	}
+	if c.RateLimitWait < 0 {
+		return fmt.Errorf("rate limit wait cannot be negative, got: %v", c.RateLimitWait)
+	}
+	if c.MaxDescriptionLength < 0 {
+		return fmt.Errorf("max description length cannot be negative, got: %d", c.MaxDescriptionLength)
+	}
+
+	return nil
+}
+
+// ClientOptions converts the timeout, retry, rate limit and cache settings for client.NewHTTPClientWithOptions
+func (c *Config) ClientOptions() client.ClientOptions {
+	timeout := c.Timeout
+	if timeout == 0 {
+		timeout = client.DefaultTimeout
+	}
+	return client.ClientOptions{
+		Timeout:          timeout,
+		MaxRetries:       c.MaxRetries,
+		RetryBackoff:     c.RetryBackoff,
+		CacheTTL:         c.CacheTTL,
+		MaxRateLimitWait: c.RateLimitWait,
 	}
+}
 
+// NewClient returns the TaskClient for the configured protocol
+// Verbose tracing applies to the HTTP client only
+func NewClient(cfg *Config) (client.TaskClient, error) {
+	if cfg.Protocol == protocolGRPC {
+		return client.NewGRPCClient(cfg.ServerURL, cfg.Timeout)
+	}
+	httpClient := client.NewHTTPClientWithOptions(cfg.ServerURL, cfg.ClientOptions())
+	if cfg.Verbose {
+		httpClient.SetVerbose(os.Stderr)
+	}
+	return httpClient, nil
+}
+
+// validateGRPCAddress checks that the address is a host:port pair, e.g. localhost:50051
+func validateGRPCAddress(addr string) error {
+	if addr == "" {
+		return fmt.Errorf("address cannot be empty")
+	}
+	if strings.Contains(addr, "://") {
+		return fmt.Errorf("gRPC address must be host:port without a scheme, got: %s", addr)
+	}
+	if _, port, err := net.SplitHostPort(addr); err != nil || port == "" {
+		return fmt.Errorf("gRPC address must be host:port, got: %s", addr)
+	}
 	return nil
 }
 
-// validateURL checks if the URL is a valid HTTP/HTTPS URL
+// validateURL checks if the URL is a valid HTTP/HTTPS URL or a unix:// socket path
 func validateURL(rawURL string) error {
 	if rawURL == "" {
 		return fmt.Errorf("URL cannot be empty")
@@ -54,10 +332,16 @@ func validateURL(rawURL string) error {
 		return fmt.Errorf("failed to parse URL: %w", err)
 	}
 
-	// Check if scheme is http or https
+	// Check if scheme is http, https or unix
 	scheme := strings.ToLower(parsedURL.Scheme)
+	if scheme == "unix" {
+		if parsedURL.Path == "" {
+			return fmt.Errorf("unix URL must include a socket path")
+		}
+		return nil
+	}
 	if scheme != "http" && scheme != "https" {
-		return fmt.Errorf("URL scheme must be http or https, got: %s", parsedURL.Scheme)
+		return fmt.Errorf("URL scheme must be http, https or unix, got: %s", parsedURL.Scheme)
 	}
 
 	// Check if host is present
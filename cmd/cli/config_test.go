@@ -1,15 +1,22 @@
 package main
 
 import (
+	"bytes"
+	"fmt"
+	"myproject/cmd/cli/client"
 	"os"
+	"path/filepath"
+	"slices"
+	"strings"
 	"testing"
+	"time"
 )
 
 func TestLoadConfig_DefaultURL(t *testing.T) {
 	// Clear environment variable
 	os.Unsetenv("TASK_SERVER_URL")
 
-	config, err := LoadConfig()
+	config, err := LoadConfig(nil)
 	if err != nil {
 		t.Fatalf("LoadConfig() failed: %v", err)
 	}
@@ -26,7 +33,7 @@ func TestLoadConfig_CustomURL(t *testing.T) {
 	os.Setenv("TASK_SERVER_URL", customURL)
 	defer os.Unsetenv("TASK_SERVER_URL")
 
-	config, err := LoadConfig()
+	config, err := LoadConfig(nil)
 	if err != nil {
 		t.Fatalf("LoadConfig() failed: %v", err)
 	}
@@ -36,6 +43,155 @@ func TestLoadConfig_CustomURL(t *testing.T) {
 	}
 }
 
+func TestLoadConfig_ShowAge(t *testing.T) {
+	testCases := []struct {
+		name     string
+		value    string
+		expected bool
+		wantErr  bool
+	}{
+		{name: "unset", value: "", expected: false},
+		{name: "enabled", value: "true", expected: true},
+		{name: "disabled", value: "0", expected: false},
+		{name: "invalid", value: "sometimes", wantErr: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Setenv("TASK_SHOW_AGE", tc.value)
+
+			config, err := LoadConfig(nil)
+			if tc.wantErr {
+				if err == nil {
+					t.Fatal("Expected LoadConfig() to fail")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("LoadConfig() failed: %v", err)
+			}
+			if config.ShowAge != tc.expected {
+				t.Errorf("Expected ShowAge to be %v, got %v", tc.expected, config.ShowAge)
+			}
+		})
+	}
+}
+
+func TestLoadConfig_EncryptToken(t *testing.T) {
+	config, err := LoadConfig(nil)
+	if err != nil {
+		t.Fatalf("LoadConfig() failed: %v", err)
+	}
+	if config.EncryptToken {
+		t.Error("Expected EncryptToken to be off by default")
+	}
+
+	t.Setenv("TASK_ENCRYPT_TOKEN", "true")
+	config, err = LoadConfig(nil)
+	if err != nil {
+		t.Fatalf("LoadConfig() failed: %v", err)
+	}
+	if !config.EncryptToken {
+		t.Error("Expected TASK_ENCRYPT_TOKEN=true to enable EncryptToken")
+	}
+}
+
+func TestLoadConfig_AutoList(t *testing.T) {
+	config, err := LoadConfig([]string{"--auto-list"})
+	if err != nil {
+		t.Fatalf("LoadConfig() failed: %v", err)
+	}
+	if !config.AutoList {
+		t.Error("Expected --auto-list to enable AutoList")
+	}
+
+	t.Setenv("TASK_AUTO_LIST", "true")
+	config, err = LoadConfig([]string{"--auto-list=false"})
+	if err != nil {
+		t.Fatalf("LoadConfig() failed: %v", err)
+	}
+	if config.AutoList {
+		t.Error("Expected --auto-list=false to override TASK_AUTO_LIST")
+	}
+}
+
+func TestLoadConfig_RetryPolicy(t *testing.T) {
+	t.Run("defaults", func(t *testing.T) {
+		config, err := LoadConfig(nil)
+		if err != nil {
+			t.Fatalf("LoadConfig() failed: %v", err)
+		}
+
+		expected := client.ClientOptions{Timeout: 30 * time.Second, MaxRetries: 2, RetryBackoff: 200 * time.Millisecond}
+		if config.ClientOptions() != expected {
+			t.Errorf("Expected client options %+v, got %+v", expected, config.ClientOptions())
+		}
+	})
+
+	t.Run("custom values", func(t *testing.T) {
+		t.Setenv("TASK_CLIENT_TIMEOUT", "5s")
+		t.Setenv("TASK_CLIENT_RETRIES", "4")
+		t.Setenv("TASK_CLIENT_RETRY_BACKOFF", "50ms")
+		t.Setenv("TASK_CLIENT_CACHE_TTL", "10s")
+		t.Setenv("TASK_CLIENT_RATE_LIMIT_WAIT", "15s")
+
+		config, err := LoadConfig(nil)
+		if err != nil {
+			t.Fatalf("LoadConfig() failed: %v", err)
+		}
+
+		expected := client.ClientOptions{Timeout: 5 * time.Second, MaxRetries: 4, RetryBackoff: 50 * time.Millisecond, CacheTTL: 10 * time.Second, MaxRateLimitWait: 15 * time.Second}
+		if config.ClientOptions() != expected {
+			t.Errorf("Expected client options %+v, got %+v", expected, config.ClientOptions())
+		}
+	})
+
+	t.Run("description limit", func(t *testing.T) {
+		config, err := LoadConfig(nil)
+		if err != nil {
+			t.Fatalf("LoadConfig() failed: %v", err)
+		}
+		if config.MaxDescriptionLength != 200 {
+			t.Errorf("Expected default description limit 200, got %d", config.MaxDescriptionLength)
+		}
+
+		t.Setenv("TASK_MAX_DESCRIPTION_LENGTH", "500")
+		config, err = LoadConfig(nil)
+		if err != nil {
+			t.Fatalf("LoadConfig() failed: %v", err)
+		}
+		if config.MaxDescriptionLength != 500 {
+			t.Errorf("Expected description limit 500, got %d", config.MaxDescriptionLength)
+		}
+	})
+
+	invalid := map[string]string{
+		"TASK_CLIENT_TIMEOUT":         "soon",
+		"TASK_CLIENT_RETRIES":         "many",
+		"TASK_CLIENT_RETRY_BACKOFF":   "-1s",
+		"TASK_CLIENT_CACHE_TTL":       "-5s",
+		"TASK_CLIENT_RATE_LIMIT_WAIT": "-1s",
+		"TASK_MAX_DESCRIPTION_LENGTH": "long",
+	}
+	for name, value := range invalid {
+		t.Run("invalid "+name, func(t *testing.T) {
+			t.Setenv(name, value)
+
+			if _, err := LoadConfig(nil); err == nil {
+				t.Errorf("Expected LoadConfig() to fail for %s=%q", name, value)
+			}
+		})
+	}
+
+	t.Run("too many retries", func(t *testing.T) {
+		t.Setenv("TASK_CLIENT_RETRIES", "11")
+
+		if _, err := LoadConfig(nil); err == nil {
+			t.Error("Expected LoadConfig() to fail")
+		}
+	})
+}
+
 func TestValidateURL_ValidURLs(t *testing.T) {
 	validURLs := []string{
 		"http://localhost:8080",
@@ -43,6 +199,7 @@ func TestValidateURL_ValidURLs(t *testing.T) {
 		"http://example.com",
 		"https://api.example.com:9090",
 		"http://192.168.1.1:3000",
+		"unix:///tmp/tasks.sock",
 	}
 
 	for _, url := range validURLs {
@@ -63,6 +220,7 @@ func TestValidateURL_InvalidURLs(t *testing.T) {
 		"http://",
 		"https://",
 		"ws://localhost:8080",
+		"unix://",
 	}
 
 	for _, url := range invalidURLs {
@@ -96,3 +254,231 @@ func TestConfig_Validate(t *testing.T) {
 		}
 	})
 }
+
+func TestLoadConfig_Protocol(t *testing.T) {
+	testCases := []struct {
+		name     string
+		args     []string
+		wantGRPC bool
+		wantErr  bool
+	}{
+		{name: "http by default", args: nil},
+		{name: "grpc with host and port", args: []string{"--protocol", "grpc", "--server", "localhost:50051"}, wantGRPC: true},
+		{name: "grpc rejects URL", args: []string{"--protocol", "grpc", "--server", "http://localhost:50051"}, wantErr: true},
+		{name: "grpc requires port", args: []string{"--protocol", "grpc", "--server", "localhost"}, wantErr: true},
+		{name: "http rejects host and port", args: []string{"--protocol", "http", "--server", "localhost:50051"}, wantErr: true},
+		{name: "unknown protocol", args: []string{"--protocol", "websocket"}, wantErr: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			config, err := LoadConfig(tc.args)
+			if tc.wantErr {
+				if err == nil {
+					t.Fatal("Expected LoadConfig() to fail")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("LoadConfig() failed: %v", err)
+			}
+
+			taskClient, err := NewClient(config)
+			if err != nil {
+				t.Fatalf("NewClient() failed: %v", err)
+			}
+			_, isGRPC := taskClient.(*client.GRPCClient)
+			if isGRPC != tc.wantGRPC {
+				t.Errorf("Expected gRPC client to be %v, got %T", tc.wantGRPC, taskClient)
+			}
+		})
+	}
+}
+
+func TestLoadConfig_Flags(t *testing.T) {
+	testCases := []struct {
+		name     string
+		args     []string
+		expected bool
+		wantErr  bool
+	}{
+		{name: "no flags", args: nil, expected: false},
+		{name: "json", args: []string{"--json"}, expected: true},
+		{name: "json disabled explicitly", args: []string{"--json=false"}, expected: false},
+		{name: "unknown flag", args: []string{"--yaml"}, wantErr: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			config, err := LoadConfig(tc.args)
+			if tc.wantErr {
+				if err == nil {
+					t.Fatal("Expected LoadConfig() to fail")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("LoadConfig() failed: %v", err)
+			}
+			if config.JSON != tc.expected {
+				t.Errorf("Expected JSON to be %v, got %v", tc.expected, config.JSON)
+			}
+		})
+	}
+}
+
+func TestLoadConfig_Command(t *testing.T) {
+	testCases := []struct {
+		name     string
+		args     []string
+		expected []string
+		json     bool
+	}{
+		{name: "no command runs interactively", args: []string{"--json"}, expected: []string{}, json: true},
+		{name: "command after flags", args: []string{"--json", "add", "Buy milk"}, expected: []string{"add", "Buy milk"}, json: true},
+		{name: "flags after the command belong to it", args: []string{"list", "--sort", "-created"}, expected: []string{"list", "--sort", "-created"}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			config, err := LoadConfig(tc.args)
+			if err != nil {
+				t.Fatalf("LoadConfig() failed: %v", err)
+			}
+			if !slices.Equal(config.Command, tc.expected) {
+				t.Errorf("Expected Command to be %q, got %q", tc.expected, config.Command)
+			}
+			if config.JSON != tc.json {
+				t.Errorf("Expected JSON to be %v, got %v", tc.json, config.JSON)
+			}
+		})
+	}
+}
+
+func TestLoadConfig_Plain(t *testing.T) {
+	testCases := []struct {
+		name     string
+		args     []string
+		noColor  string
+		expected bool
+	}{
+		{name: "emoji by default", expected: false},
+		{name: "plain flag", args: []string{"--plain"}, expected: true},
+		{name: "no-emoji flag", args: []string{"--no-emoji"}, expected: true},
+		{name: "NO_COLOR with any value", noColor: "yes", expected: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Setenv("NO_COLOR", tc.noColor)
+
+			config, err := LoadConfig(tc.args)
+			if err != nil {
+				t.Fatalf("LoadConfig() failed: %v", err)
+			}
+			if config.Plain != tc.expected {
+				t.Errorf("Expected Plain to be %v, got %v", tc.expected, config.Plain)
+			}
+		})
+	}
+}
+
+func TestLoadConfig_Precedence(t *testing.T) {
+	configFile := filepath.Join(t.TempDir(), "config.yaml")
+	content := "server_url: https://file.example.com\ntoken_file: /tmp/file-token\ntimeout: 7s\nshow_age: true\n"
+	if err := os.WriteFile(configFile, []byte(content), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	testCases := []struct {
+		name            string
+		env             map[string]string
+		args            []string
+		expectedURL     string
+		expectedTimeout time.Duration
+		expectedSource  string
+	}{
+		{
+			name:            "config file overrides defaults",
+			args:            []string{"--config", configFile},
+			expectedURL:     "https://file.example.com",
+			expectedTimeout: 7 * time.Second,
+			expectedSource:  "config file",
+		},
+		{
+			name:            "environment overrides config file",
+			env:             map[string]string{"TASK_SERVER_URL": "https://env.example.com", "TASK_CLIENT_TIMEOUT": "8s"},
+			args:            []string{"--config", configFile},
+			expectedURL:     "https://env.example.com",
+			expectedTimeout: 8 * time.Second,
+			expectedSource:  "env",
+		},
+		{
+			name:            "flags override environment",
+			env:             map[string]string{"TASK_SERVER_URL": "https://env.example.com", "TASK_CLIENT_TIMEOUT": "8s"},
+			args:            []string{"--config", configFile, "--server", "https://staging.example.com", "--timeout", "9s"},
+			expectedURL:     "https://staging.example.com",
+			expectedTimeout: 9 * time.Second,
+			expectedSource:  "flag",
+		},
+		{
+			name:            "config file named by environment",
+			env:             map[string]string{"TASK_CLI_CONFIG": configFile},
+			expectedURL:     "https://file.example.com",
+			expectedTimeout: 7 * time.Second,
+			expectedSource:  "config file",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			for name, value := range tc.env {
+				t.Setenv(name, value)
+			}
+
+			config, err := LoadConfig(tc.args)
+			if err != nil {
+				t.Fatalf("LoadConfig() failed: %v", err)
+			}
+
+			if config.ServerURL != tc.expectedURL {
+				t.Errorf("Expected ServerURL %q, got %q", tc.expectedURL, config.ServerURL)
+			}
+			if config.Timeout != tc.expectedTimeout {
+				t.Errorf("Expected Timeout %v, got %v", tc.expectedTimeout, config.Timeout)
+			}
+			if config.TokenFile != "/tmp/file-token" {
+				t.Errorf("Expected TokenFile from config file, got %q", config.TokenFile)
+			}
+			if !config.ShowAge {
+				t.Error("Expected ShowAge from config file")
+			}
+
+			var out bytes.Buffer
+			config.PrintConfig(&out)
+			expectedLine := fmt.Sprintf("server_url: %s (%s)", tc.expectedURL, tc.expectedSource)
+			if !strings.Contains(out.String(), expectedLine) {
+				t.Errorf("Expected --show-config output to contain %q, got:\n%s", expectedLine, out.String())
+			}
+		})
+	}
+
+	t.Run("missing config file", func(t *testing.T) {
+		if _, err := LoadConfig([]string{"--config", filepath.Join(t.TempDir(), "missing.yaml")}); err == nil {
+			t.Error("Expected LoadConfig() to fail")
+		}
+	})
+
+	t.Run("show-config and token-file flags", func(t *testing.T) {
+		config, err := LoadConfig([]string{"--show-config", "--token-file", "/tmp/staging-token"})
+		if err != nil {
+			t.Fatalf("LoadConfig() failed: %v", err)
+		}
+		if !config.ShowConfig {
+			t.Error("Expected ShowConfig to be set")
+		}
+		if config.TokenFile != "/tmp/staging-token" {
+			t.Errorf("Expected TokenFile %q, got %q", "/tmp/staging-token", config.TokenFile)
+		}
+	})
+}
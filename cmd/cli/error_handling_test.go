@@ -5,6 +5,7 @@ import (
 	"errors"
 	"myproject/cmd/cli/client"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -86,6 +87,28 @@ func TestCLI_HandleError_APIError(t *testing.T) {
 	}
 }
 
+// TestCLI_HandleError_RateLimitError tests that RateLimitError tells the user how long to wait
+func TestCLI_HandleError_RateLimitError(t *testing.T) {
+	output := &bytes.Buffer{}
+	cli := NewCLI(
+		nil,
+		output,
+		nil,
+		nil,
+		nil,
+	)
+
+	rateErr := &client.RateLimitError{
+		RetryAfter: 3 * time.Second,
+		RequestID:  "req-123",
+	}
+
+	cli.handleError(rateErr, "Create task")
+
+	expected := "⏳ Create task: server busy, retry in 3s\n   Request ID: req-123\n"
+	assert.Equal(t, expected, output.String())
+}
+
 // TestCLI_HandleError_GenericError tests that generic errors are displayed with standard format
 func TestCLI_HandleError_GenericError(t *testing.T) {
 	output := &bytes.Buffer{}
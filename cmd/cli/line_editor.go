@@ -0,0 +1,64 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	"golang.org/x/term"
+)
+
+// TerminalInputReader implements InputReader for an interactive terminal.
+// Lines can be edited with the arrow keys, Home/End and Ctrl-W, and the up and down keys
+// recall earlier lines of the session; the history is kept in memory only.
+type TerminalInputReader struct {
+	terminal *term.Terminal
+	// rawMode switches the terminal to raw mode for the duration of one ReadLine
+	rawMode func() (restore func(), err error)
+}
+
+// NewInputReader returns a TerminalInputReader when in is a terminal and a ConsoleInputReader otherwise,
+// e.g. when commands are piped in. Typed characters are echoed to echo.
+func NewInputReader(in *os.File, echo io.Writer) InputReader {
+	fd := int(in.Fd())
+	if !term.IsTerminal(fd) {
+		return NewConsoleInputReader(in)
+	}
+	return newTerminalInputReader(in, echo, func() (func(), error) {
+		state, err := term.MakeRaw(fd)
+		if err != nil {
+			return nil, err
+		}
+		return func() { term.Restore(fd, state) }, nil
+	})
+}
+
+// newTerminalInputReader creates a TerminalInputReader that edits lines read from in
+func newTerminalInputReader(in io.Reader, echo io.Writer, rawMode func() (func(), error)) *TerminalInputReader {
+	return &TerminalInputReader{
+		terminal: term.NewTerminal(struct {
+			io.Reader
+			io.Writer
+		}{in, echo}, ""),
+		rawMode: rawMode,
+	}
+}
+
+// ReadInput reads one edited line and validates it like ConsoleInputReader.
+// The terminal is only in raw mode while the line is typed, so command output prints normally.
+// Ctrl-C and Ctrl-D on an empty line return io.EOF.
+func (r *TerminalInputReader) ReadInput(maxSize int) (string, error) {
+	restore, err := r.rawMode()
+	if err != nil {
+		return "", fmt.Errorf("failed to enable line editing: %w", err)
+	}
+	line, err := r.terminal.ReadLine()
+	restore()
+	// A pasted line is still a complete line
+	if err != nil && !errors.Is(err, term.ErrPasteIndicator) {
+		return "", err
+	}
+
+	return validateInput(line, maxSize)
+}
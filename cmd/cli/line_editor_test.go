@@ -0,0 +1,65 @@
+package main
+
+import (
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTerminalInputReader(t *testing.T) {
+	newReader := func(keys string) (*TerminalInputReader, *int) {
+		restored := 0
+		reader := newTerminalInputReader(strings.NewReader(keys), io.Discard, func() (func(), error) {
+			return func() { restored++ }, nil
+		})
+		return reader, &restored
+	}
+
+	t.Run("up arrow recalls the previous line", func(t *testing.T) {
+		reader, restored := newReader("list\rstats\r\x1b[A\x1b[A\r")
+
+		for _, want := range []string{"list", "stats", "list"} {
+			got, err := reader.ReadInput(maxCommandInputSize)
+			require.NoError(t, err)
+			assert.Equal(t, want, got)
+		}
+		assert.Equal(t, 3, *restored, "raw mode is left after every line")
+	})
+	t.Run("line can be edited before it is entered", func(t *testing.T) {
+		// "lit", one step left, then the missing "s"
+		reader, _ := newReader("lit\x1b[Ds\r")
+
+		got, err := reader.ReadInput(maxCommandInputSize)
+
+		require.NoError(t, err)
+		assert.Equal(t, "list", got)
+	})
+	t.Run("validates like ConsoleInputReader", func(t *testing.T) {
+		reader, _ := newReader("   \rtoo long for the limit\r")
+
+		_, err := reader.ReadInput(maxCommandInputSize)
+		assert.ErrorIs(t, err, ErrEmptyInput)
+		_, err = reader.ReadInput(5)
+		assert.ErrorIs(t, err, ErrMaxSizeExceeded)
+	})
+	t.Run("Ctrl-D on an empty line is EOF", func(t *testing.T) {
+		reader, _ := newReader("\x04")
+
+		_, err := reader.ReadInput(maxCommandInputSize)
+
+		assert.ErrorIs(t, err, io.EOF)
+	})
+	t.Run("raw mode failure is reported", func(t *testing.T) {
+		reader := newTerminalInputReader(strings.NewReader("list\r"), io.Discard, func() (func(), error) {
+			return nil, errors.New("not a terminal")
+		})
+
+		_, err := reader.ReadInput(maxCommandInputSize)
+
+		assert.ErrorContains(t, err, "failed to enable line editing: not a terminal")
+	})
+}
@@ -1,12 +1,19 @@
 package main
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"io"
 	"log"
 	"myproject/cmd/cli/auth"
 	"myproject/cmd/cli/client"
+	"myproject/cmd/cli/symbols"
 	"os"
 	"strings"
+	"time"
+
+	"github.com/spf13/pflag"
 )
 
 // Command represents a valid user command in the task manager CLI.
@@ -14,23 +21,43 @@ import (
 type Command string
 
 const (
-	maxInputSize            = 10
-	CommandAdd      Command = "add"      // Add a new task
-	CommandStatus   Command = "status"   // Change task status
-	CommandList     Command = "list"     // Show all tasks
-	CommandProcess  Command = "process"  // Process all tasks in parallel
-	CommandClear    Command = "clear"    // Clear task description
-	CommandHelp     Command = "help"     // Show available commands
-	CommandExit     Command = "exit"     // Save and exit program
-	CommandUpdate   Command = "update"   // Update task description
-	CommandDelete   Command = "delete"   // Delete task
-	CommandLogin    Command = "login"    // Login with existing account
-	CommandRegister Command = "register" // Register new account
-	CommandLogout   Command = "logout"   // Logout and clear token
+	maxInputSize                 = 10
+	CommandAdd           Command = "add"           // Add a new task
+	CommandAddDone       Command = "add-done"      // Add an already completed task
+	CommandAddMany       Command = "addmany"       // Add several tasks at once
+	CommandStatus        Command = "status"        // Change task status
+	CommandToggle        Command = "toggle"        // Flip task status
+	CommandMarkAll       Command = "markall"       // Mark all tasks done or undone
+	CommandShow          Command = "show"          // Show one task with all its fields
+	CommandList          Command = "list"          // Show all tasks
+	CommandRefresh       Command = "refresh"       // Reload and show the task list
+	CommandSearch        Command = "search"        // Find tasks by keyword
+	CommandStats         Command = "stats"         // Count total, done and pending tasks
+	CommandCount         Command = "count"         // Print just the number of tasks
+	CommandExport        Command = "export"        // Save tasks to a JSON or CSV file
+	CommandImport        Command = "import"        // Add tasks from a JSON or CSV file
+	CommandProcess       Command = "process"       // Process all tasks in parallel
+	CommandClear         Command = "clear"         // Clear task description
+	CommandHelp          Command = "help"          // Show available commands
+	CommandExit          Command = "exit"          // Save and exit program
+	CommandUpdate        Command = "update"        // Update task description
+	CommandDelete        Command = "delete"        // Delete task
+	CommandDeleteDone    Command = "deletedone"    // Delete all completed tasks
+	CommandRestore       Command = "restore"       // Restore a deleted task
+	CommandArchive       Command = "archive"       // Archive a task or all completed tasks
+	CommandTag           Command = "tag"           // Add a tag to a task
+	CommandUntag         Command = "untag"         // Remove a tag from a task
+	CommandUndo          Command = "undo"          // Revert the last task change
+	CommandLogin         Command = "login"         // Login with existing account
+	CommandRegister      Command = "register"      // Register new account
+	CommandLogout        Command = "logout"        // Logout and clear token
+	CommandWhoami        Command = "whoami"        // Show the logged in account
+	CommandDeleteAccount Command = "deleteaccount" // Delete the account and all its tasks
+	CommandPasswd        Command = "passwd"        // Change the account password
 )
 
 var (
-	validCommands = []Command{CommandAdd, CommandStatus, CommandList, CommandProcess, CommandClear, CommandHelp, CommandExit, CommandUpdate, CommandDelete, CommandLogin, CommandRegister, CommandLogout}
+	validCommands = []Command{CommandAdd, CommandAddDone, CommandAddMany, CommandStatus, CommandToggle, CommandMarkAll, CommandShow, CommandList, CommandRefresh, CommandSearch, CommandStats, CommandCount, CommandExport, CommandImport, CommandProcess, CommandClear, CommandHelp, CommandExit, CommandUpdate, CommandDelete, CommandDeleteDone, CommandRestore, CommandArchive, CommandTag, CommandUntag, CommandUndo, CommandLogin, CommandRegister, CommandLogout, CommandWhoami, CommandDeleteAccount, CommandPasswd}
 )
 
 // isValid checks if the command is in the list of supported commands.
@@ -44,6 +71,29 @@ func (cmd Command) isValid() bool {
 	return false
 }
 
+// takesArguments reports whether the command accepts flags after its name, e.g. "list --sort id" or "count --done".
+func (cmd Command) takesArguments() bool {
+	return cmd == CommandList || cmd == CommandDelete || cmd == CommandCount || cmd == CommandArchive
+}
+
+// changesTasks reports whether the command can add, change or delete tasks, after which
+// Config.AutoList shows the updated list.
+func (cmd Command) changesTasks() bool {
+	switch cmd {
+	case CommandAdd, CommandAddDone, CommandAddMany, CommandStatus, CommandToggle, CommandMarkAll,
+		CommandImport, CommandClear, CommandUpdate, CommandDelete, CommandDeleteDone, CommandRestore,
+		CommandArchive, CommandTag, CommandUntag, CommandUndo:
+		return true
+	}
+	return false
+}
+
+// retriesAfterReauth reports whether the command is re-run after an expired session is renewed.
+// Commands that manage the session themselves are not.
+func (cmd Command) retriesAfterReauth() bool {
+	return cmd != CommandLogin && cmd != CommandRegister && cmd != CommandLogout
+}
+
 // validateCommand converts user input to a valid Command.
 // Input is normalized to lowercase before validation.
 // Returns the valid command or an error if the command is not recognized.
@@ -56,50 +106,111 @@ func validateCommand(input string) (Command, error) {
 	return "", ErrInvalidCommand
 }
 
-// suggestCommand attempts to find a command that matches the input prefix.
-// Returns the first matching command, or empty string if no match is found.
-// Used to provide helpful suggestions for typos or partial commands.
-func suggestCommand(input string) Command {
+// resolveCommand converts user input to a Command, accepting any unambiguous prefix.
+// An exact match always wins, so "add" resolves to add even though add-done shares the prefix.
+// Returns ErrAmbiguousCommand listing the candidates when several commands share the prefix,
+// or ErrInvalidCommand when none do.
+func resolveCommand(input string) (Command, error) {
+	if cmd, err := validateCommand(input); err == nil {
+		return cmd, nil
+	}
+
+	prefix := strings.ToLower(input)
+	var candidates []string
 	for _, cmd := range validCommands {
-		if strings.HasPrefix(string(cmd), input) {
-			return cmd
+		if strings.HasPrefix(string(cmd), prefix) {
+			candidates = append(candidates, string(cmd))
 		}
 	}
-	return ""
+
+	switch len(candidates) {
+	case 0:
+		return "", ErrInvalidCommand
+	case 1:
+		return Command(candidates[0]), nil
+	default:
+		return "", fmt.Errorf("%w '%s', could be: %s", ErrAmbiguousCommand, input, strings.Join(candidates, ", "))
+	}
 }
 
 func main() {
 	// Load configuration
-	cfg, err := LoadConfig()
+	cfg, err := LoadConfig(os.Args[1:])
 	if err != nil {
+		if errors.Is(err, pflag.ErrHelp) {
+			os.Exit(0)
+		}
 		log.Fatalf("Failed to load configuration: %v", err)
 	}
+	if cfg.ShowConfig {
+		cfg.PrintConfig(os.Stdout)
+		return
+	}
 
-	// Display startup banner and server URL
-	fmt.Println("🚀 Task Manager CLI (Client Mode)")
-	fmt.Printf("📡 Server: %s\n", cfg.ServerURL)
+	// Arguments after the flags run that one command and exit instead of the interactive loop
+	oneShot := len(cfg.Command) > 0
 
-	// Create HTTP client with configured server URL
-	httpClient := client.NewHTTPClient(cfg.ServerURL)
+	// In JSON and one-shot mode stdout carries only command results; banners and prompts go to stderr
+	var messages io.Writer = os.Stdout
+	if cfg.JSON || oneShot {
+		messages = os.Stderr
+	}
 
-	// Create input reader
-	inputReader := NewConsoleInputReader(os.Stdin)
+	// Emoji unless --plain, --no-emoji or NO_COLOR asks for ASCII-only output
+	sym := symbols.For(cfg.Plain)
 
-	// Create auth manager
-	authManager := auth.NewFileAuthManager(httpClient, inputReader, os.Stdout)
+	// Display startup banner and server URL
+	if !oneShot {
+		fmt.Fprintf(messages, "%s Task Manager CLI (Client Mode)\n", sym.Info)
+		fmt.Fprintf(messages, "%s Server: %s\n", sym.Server, cfg.ServerURL)
+	}
 
-	// Perform initial authentication
-	// This will show authentication prompt if no token exists
-	// and provide options: 1) Login 2) Register 3) Exit
-	token, err := authManager.RequireAuth()
+	// Create the HTTP or gRPC client with configured server URL, timeout and retry policy
+	taskClient, err := NewClient(cfg)
 	if err != nil {
-		// User chose to exit or authentication failed
-		fmt.Fprintf(os.Stdout, "❌ Authentication failed: %v\n", err)
-		os.Exit(1)
+		log.Fatalf("Failed to create client: %v", err)
 	}
 
-	// Set token in HTTP client
-	httpClient.SetToken(token)
+	// Wait for a server that is still starting, e.g. when both are launched together in development
+	if cfg.WaitForServer > 0 {
+		ctx, stop := commandContext()
+		err := waitForServer(ctx, taskClient, cfg.WaitForServer, cfg.RetryBackoff, messages, sym)
+		stop()
+		if err != nil {
+			fmt.Fprintf(messages, "%s %v\n", sym.Error, err)
+			os.Exit(exitNetwork)
+		}
+	}
+
+	// Create input reader, with line editing and history when stdin is a terminal; echo goes where prompts go, so --json keeps stdout clean
+	inputReader := NewInputReader(os.Stdin, messages)
+
+	// Create auth manager
+	authOpts := []auth.FileAuthManagerOption{auth.WithSymbols(sym)}
+	if cfg.EncryptToken {
+		authOpts = append(authOpts, auth.WithTokenEncryption())
+	}
+	authManager := auth.NewFileAuthManagerWithTokenPath(cfg.TokenFile, taskClient, inputReader, messages, authOpts...)
+
+	if oneShot {
+		// A one-shot command never stops to ask for a login; without a stored token the server rejects it
+		if token, err := authManager.LoadToken(); err == nil {
+			taskClient.SetToken(token)
+		}
+	} else {
+		// Perform initial authentication
+		// This will show authentication prompt if no token exists
+		// and provide options: 1) Login 2) Register 3) Exit
+		token, err := authManager.RequireAuth()
+		if err != nil {
+			// User chose to exit or authentication failed
+			fmt.Fprintf(messages, "%s Authentication failed: %v\n", sym.Error, err)
+			os.Exit(1)
+		}
+
+		// Set token in the client
+		taskClient.SetToken(token)
+	}
 
 	// Create and run CLI with client and auth manager
 	// Proceed to command loop after successful authentication
@@ -107,9 +218,54 @@ func main() {
 		inputReader,
 		os.Stdout,
 		cfg,
-		httpClient,
+		taskClient,
 		authManager,
 	)
 
+	// Match the prompts to the server's limits; the configured ones apply when it cannot report them
+	ctx, stop := commandContext()
+	if err := cli.LoadLimits(ctx); err != nil {
+		fmt.Fprintf(messages, "%s Using configured input limits: %v\n", sym.Warning, err)
+	}
+	stop()
+
+	if oneShot {
+		os.Exit(cli.RunOnce(cfg.Command))
+	}
 	cli.RunLoop()
 }
+
+// maxServerWaitBackoff caps the pause between two health checks while waiting for the server.
+const maxServerWaitBackoff = 5 * time.Second
+
+// waitForServer pings the server until it answers or timeout passes, pausing backoff after the first
+// failed check and doubling the pause after every further one. The first failure is reported on w,
+// so a server that is already up starts the CLI without any message.
+func waitForServer(ctx context.Context, c client.TaskClient, timeout, backoff time.Duration, w io.Writer, sym symbols.Set) error {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	if backoff <= 0 {
+		backoff = client.DefaultRetryBackoff
+	}
+	var lastErr error
+	for attempt := 1; ; attempt++ {
+		err := c.Ping(ctx)
+		if err == nil {
+			return nil
+		}
+		// A check cut short by the timeout only says the time is up; report why the one before failed
+		if lastErr == nil || ctx.Err() == nil {
+			lastErr = err
+		}
+		if attempt == 1 {
+			fmt.Fprintf(w, "%s Waiting up to %v for server %s...\n", sym.Info, timeout, c.GetServerURL())
+		}
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("server unreachable after %v: %w", timeout, lastErr)
+		case <-time.After(backoff):
+		}
+		backoff = min(backoff*2, maxServerWaitBackoff)
+	}
+}
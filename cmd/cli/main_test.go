@@ -2,9 +2,14 @@ package main
 
 import (
 	"bytes"
+	"context"
 	"errors"
+	"myproject/cmd/cli/client"
+	"myproject/cmd/cli/symbols"
+	"slices"
 	"strings"
 	"testing"
+	"time"
 )
 
 // TestCommand_isValid tests the isValid method for Command type
@@ -236,13 +241,15 @@ func TestValidateCommand(t *testing.T) {
 	}
 }
 
-// TestSuggestCommand tests the suggestCommand function
-func TestSuggestCommand(t *testing.T) {
+// TestResolveCommand tests the resolveCommand function
+func TestResolveCommand(t *testing.T) {
 	// ====Arrange====
 	testCases := []struct {
-		name            string
-		input           string
-		expectedCommand Command
+		name               string
+		input              string
+		expectedCommand    Command
+		expectedErr        error
+		expectedCandidates []string
 	}{
 		{
 			name:            "Exact match returns command",
@@ -250,55 +257,72 @@ func TestSuggestCommand(t *testing.T) {
 			expectedCommand: CommandAdd,
 		},
 		{
-			name:            "Single letter prefix match",
-			input:           "l",
+			name:            "Unique prefix resolves (lis to list)",
+			input:           "lis",
 			expectedCommand: CommandList,
 		},
 		{
-			name:            "Partial prefix match",
-			input:           "sta",
+			name:               "Single letter shared by several commands is ambiguous",
+			input:              "l",
+			expectedErr:        ErrAmbiguousCommand,
+			expectedCandidates: []string{"list", "login", "logout"},
+		},
+		{
+			name:            "Prefix is case insensitive",
+			input:           "STATU",
 			expectedCommand: CommandStatus,
 		},
 		{
-			name:            "Ambiguous prefix returns first match (login before logout)",
-			input:           "log",
-			expectedCommand: CommandLogin,
+			name:               "Prefix shared by status and stats is ambiguous",
+			input:              "sta",
+			expectedErr:        ErrAmbiguousCommand,
+			expectedCandidates: []string{"status", "stats"},
 		},
 		{
-			name:            "Longer prefix disambiguates (logout)",
-			input:           "logo",
-			expectedCommand: CommandLogout,
+			name:               "Ambiguous prefix lists candidates (log)",
+			input:              "log",
+			expectedErr:        ErrAmbiguousCommand,
+			expectedCandidates: []string{"login", "logout"},
 		},
 		{
-			name:            "Empty string matches first command",
-			input:           "",
-			expectedCommand: CommandAdd,
+			name:            "Longer prefix disambiguates (logo)",
+			input:           "logo",
+			expectedCommand: CommandLogout,
 		},
 		{
-			name:            "No match for typo",
-			input:           "addd",
-			expectedCommand: "",
+			name:               "Prefix shared with longer command is ambiguous",
+			input:              "ad",
+			expectedErr:        ErrAmbiguousCommand,
+			expectedCandidates: []string{"add", "add-done"},
 		},
 		{
-			name:            "No match for unknown prefix",
-			input:           "xyz",
-			expectedCommand: "",
+			name:        "Unknown prefix (xyz)",
+			input:       "xyz",
+			expectedErr: ErrInvalidCommand,
 		},
 		{
-			name:            "No match for input with spaces",
-			input:           "add task",
-			expectedCommand: "",
+			name:        "Typo is unknown",
+			input:       "addd",
+			expectedErr: ErrInvalidCommand,
 		},
 	}
 
 	for _, tc := range testCases {
 		t.Run(tc.name, func(t *testing.T) {
 			// ====Act====
-			result := suggestCommand(tc.input)
+			command, err := resolveCommand(tc.input)
 
 			// ====Assert====
-			if result != tc.expectedCommand {
-				t.Errorf("Expected suggestion %q for input %q, got %q", tc.expectedCommand, tc.input, result)
+			if !errors.Is(err, tc.expectedErr) {
+				t.Errorf("Expected error %v, got %v", tc.expectedErr, err)
+			}
+			if command != tc.expectedCommand {
+				t.Errorf("Expected command %q, got %q", tc.expectedCommand, command)
+			}
+			for _, candidate := range tc.expectedCandidates {
+				if !strings.Contains(err.Error(), candidate) {
+					t.Errorf("Expected candidate %q in error %q", candidate, err)
+				}
 			}
 		})
 	}
@@ -343,21 +367,49 @@ func TestCLI_RunLoop(t *testing.T) {
 			},
 		},
 		{
-			name:   "Invalid command shows suggestion",
-			inputs: []string{"ad", "exit"},
+			name:   "Unique prefix executes command",
+			inputs: []string{"proc", "exi"},
+			expectedContains: []string{
+				"⚠️  Process command not available in client mode",
+				"👋 Bye!",
+			},
+		},
+		{
+			name:   "List accepts sort flag",
+			inputs: []string{"list --sort -id", "exit"},
 			expectedContains: []string{
-				"❌ Unknown command: 'ad', maybe you wanted: 'add'",
+				"No tasks found",
+			},
+			expectedNotContain: []string{
+				"List command error",
 			},
 		},
 		{
-			name:   "Invalid command without suggestion shows error",
+			name:   "Arguments rejected for commands without flags",
+			inputs: []string{"help me", "exit"},
+			expectedContains: []string{
+				"❌ Command 'help' does not take arguments",
+			},
+		},
+		{
+			name:   "Ambiguous prefix lists candidates",
+			inputs: []string{"log", "exit"},
+			expectedContains: []string{
+				"❌ ambiguous command 'log', could be: login, logout",
+			},
+			expectedNotContain: []string{
+				"✅ Logged out successfully",
+			},
+		},
+		{
+			name:   "Invalid command shows error",
 			inputs: []string{"xyz", "exit"},
 			expectedContains: []string{
 				"Command validate error",
 				"Type 'help' to see available commands",
 			},
 			expectedNotContain: []string{
-				"maybe you wanted",
+				"could be",
 			},
 		},
 		{
@@ -422,3 +474,65 @@ func TestCLI_RunLoop(t *testing.T) {
 		})
 	}
 }
+
+// TestWaitForServer tests waiting on launch for a server that is still starting
+func TestWaitForServer(t *testing.T) {
+	unreachable := &client.NetworkError{URL: "http://localhost:8080", Err: errors.New("connection refused")}
+
+	testCases := []struct {
+		name           string
+		pingErrs       []error
+		timeout        time.Duration
+		expectedErr    bool
+		expectedPings  int
+		expectedOutput string
+	}{
+		{
+			name:          "Server already up",
+			timeout:       time.Second,
+			expectedPings: 1,
+		},
+		{
+			name:           "Server comes up while waiting",
+			pingErrs:       []error{unreachable, unreachable},
+			timeout:        time.Second,
+			expectedPings:  3,
+			expectedOutput: "Waiting up to 1s for server http://localhost:8080...",
+		},
+		{
+			name:           "Server never comes up",
+			pingErrs:       slices.Repeat([]error{unreachable}, 1000),
+			timeout:        20 * time.Millisecond,
+			expectedErr:    true,
+			expectedOutput: "Waiting up to 20ms for server",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// ====Arrange====
+			output := &bytes.Buffer{}
+			mockClient := &MockTaskClient{pingErrs: tc.pingErrs}
+
+			// ====Act====
+			err := waitForServer(context.Background(), mockClient, tc.timeout, time.Millisecond, output, symbols.For(false))
+
+			// ====Assert====
+			if tc.expectedErr {
+				if !errors.Is(err, unreachable) || !strings.Contains(err.Error(), "server unreachable after 20ms") {
+					t.Errorf("Expected server unreachable error wrapping %v, got %v", unreachable, err)
+				}
+			} else {
+				if err != nil {
+					t.Errorf("Expected no error, got %v", err)
+				}
+				if mockClient.pingCalls != tc.expectedPings {
+					t.Errorf("Expected %d pings, got %d", tc.expectedPings, mockClient.pingCalls)
+				}
+			}
+			if !strings.Contains(output.String(), tc.expectedOutput) {
+				t.Errorf("Expected output to contain %q, got %q", tc.expectedOutput, output.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,104 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"myproject/cmd/cli/client"
+	"net/http"
+	"strings"
+)
+
+// Exit codes of a one-shot command, so scripts can tell why it failed
+const (
+	exitOK       = 0
+	exitError    = 1 // any failure without a more specific code, e.g. invalid input
+	exitAuth     = 2 // the server rejected the token (401)
+	exitNetwork  = 3 // the server could not be reached
+	exitNotFound = 4 // the task or other resource does not exist (404)
+)
+
+// exitCodeFor maps the error a one-shot command ended with to its exit code.
+func exitCodeFor(err error) int {
+	if err == nil {
+		return exitOK
+	}
+	if client.IsAuthError(err) {
+		return exitAuth
+	}
+	var netErr *client.NetworkError
+	if errors.As(err, &netErr) {
+		return exitNetwork
+	}
+	var apiErr *client.APIError
+	if errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusNotFound {
+		return exitNotFound
+	}
+	return exitError
+}
+
+// RunOnce runs a single command given on the command line, e.g. `task-cli add "buy milk"`, instead of
+// the interactive loop. args are the command name and its arguments, split by oneShotArgs.
+// Returns the process exit code for the outcome of the command, see exitCodeFor.
+func (cli *CLI) RunOnce(args []string) int {
+	cli.oneShot = true
+
+	cmd, err := resolveCommand(args[0])
+	if err != nil {
+		if errors.Is(err, ErrAmbiguousCommand) {
+			cli.outputError(err.Error())
+		} else {
+			cli.handleError(fmt.Errorf("%w '%s'", err, args[0]), "Command validate error")
+		}
+		return exitError
+	}
+
+	ctx, stop := commandContext()
+	defer stop()
+
+	flags, answers := oneShotArgs(cmd, args[1:])
+	reader := &argsInputReader{answers: answers}
+	input := cli.input
+	cli.input = reader
+	defer func() { cli.input = input }()
+
+	var exit bool
+	handler := cli.commandHandlers(ctx, flags, &exit)[cmd]
+	if err := handler(); err != nil {
+		cli.handleError(err, commandErrorContexts[cmd])
+		return exitCodeFor(err)
+	}
+	if len(reader.answers) > 0 {
+		fmt.Fprintf(cli.messages(), "%s Ignored extra arguments: %s\n", cli.sym.Warning, strings.Join(reader.answers, " "))
+	}
+	return exitOK
+}
+
+// oneShotArgs splits the arguments of a one-shot command. For commands that take flags, arguments
+// starting with "-" are passed on as flags, so a flag value must be joined to its name when it does not
+// start with "-" itself, e.g. --tag=work. Every other argument answers one of the command's prompts, in order.
+func oneShotArgs(cmd Command, args []string) (flags, answers []string) {
+	for _, arg := range args {
+		if cmd.takesArguments() && strings.HasPrefix(arg, "-") {
+			flags = append(flags, arg)
+		} else {
+			answers = append(answers, arg)
+		}
+	}
+	return flags, answers
+}
+
+// argsInputReader answers the prompts of a one-shot command with its arguments.
+// Once they run out every prompt reads as empty, which optional prompts take as their default
+// and required ones reject, so a one-shot command never waits for the terminal.
+type argsInputReader struct {
+	answers []string
+}
+
+func (r *argsInputReader) ReadInput(maxSize int) (string, error) {
+	if len(r.answers) == 0 {
+		return "", ErrEmptyInput
+	}
+	answer := r.answers[0]
+	r.answers = r.answers[1:]
+	return validateInput(answer, maxSize)
+}
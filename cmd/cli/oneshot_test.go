@@ -0,0 +1,148 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"myproject/cmd/cli/client"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestCLI_RunOnce tests running a single command given on the command line
+func TestCLI_RunOnce(t *testing.T) {
+	// ====Arrange====
+	testCases := []struct {
+		name              string
+		args              []string
+		mockClient        *MockTaskClient
+		expectedCode      int
+		expectedOutput    string
+		expectedErrOutput string
+		assertClient      func(t *testing.T, m *MockTaskClient)
+	}{
+		{
+			name:           "Adds task from argument",
+			args:           []string{"add", "Buy milk"},
+			mockClient:     &MockTaskClient{createTaskResult: &client.Task{ID: 7, Description: "Buy milk"}},
+			expectedCode:   exitOK,
+			expectedOutput: "✅ Task added (ID: 7)",
+			assertClient: func(t *testing.T, m *MockTaskClient) {
+				assert.Equal(t, []string{"Buy milk"}, m.createTaskDescs)
+				assert.Nil(t, m.createTaskDueDate)
+			},
+		},
+		{
+			name: "Answers prompts in order",
+			args: []string{"status", "3", "done"},
+			mockClient: &MockTaskClient{
+				getTaskResult:    &client.Task{ID: 3, Description: "Buy milk"},
+				updateTaskResult: &client.Task{ID: 3, Description: "Buy milk", Done: true},
+			},
+			expectedCode:   exitOK,
+			expectedOutput: "✅ Task (ID: 3) status is has changed",
+			assertClient: func(t *testing.T, m *MockTaskClient) {
+				assert.True(t, *m.updateTaskDone)
+			},
+		},
+		{
+			name:         "Passes flags to commands that take them",
+			args:         []string{"delete", "--permanent", "4", "y"},
+			mockClient:   &MockTaskClient{getTaskResult: &client.Task{ID: 4, Description: "Old"}},
+			expectedCode: exitOK,
+			assertClient: func(t *testing.T, m *MockTaskClient) {
+				assert.Equal(t, 4, m.purgeTaskID)
+			},
+		},
+		{
+			name:           "Lists with flag values",
+			args:           []string{"list", "--sort", "-created"},
+			mockClient:     &MockTaskClient{getTasksResult: []client.Task{{ID: 1, Description: "Buy milk"}}},
+			expectedCode:   exitOK,
+			expectedOutput: "[ ] 1: Buy milk",
+			assertClient: func(t *testing.T, m *MockTaskClient) {
+				assert.Equal(t, "-created", m.getTasksQuery.Sort)
+			},
+		},
+		{
+			name:           "Missing required answer fails",
+			args:           []string{"add"},
+			mockClient:     &MockTaskClient{},
+			expectedCode:   exitError,
+			expectedOutput: "Add command error",
+		},
+		{
+			name:           "Missing task fails with not found code",
+			args:           []string{"toggle", "5"},
+			mockClient:     &MockTaskClient{toggleTaskErr: &client.APIError{StatusCode: 404, Message: "task not found"}},
+			expectedCode:   exitNotFound,
+			expectedOutput: "❌ Toggle command error: task not found",
+		},
+		{
+			name:           "Unknown command fails",
+			args:           []string{"frobnicate"},
+			mockClient:     &MockTaskClient{},
+			expectedCode:   exitError,
+			expectedOutput: "Command validate error: invalid command 'frobnicate'",
+		},
+		{
+			name:              "Extra arguments are reported",
+			args:              []string{"toggle", "5", "6"},
+			mockClient:        &MockTaskClient{toggleTaskResult: &client.Task{ID: 5, Description: "Buy milk", Done: true}},
+			expectedCode:      exitOK,
+			expectedErrOutput: "Ignored extra arguments: 6",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			output, errOutput := &bytes.Buffer{}, &bytes.Buffer{}
+			cli := NewCLI(
+				NewMockInputReader(),
+				output,
+				&Config{ServerURL: "http://localhost:8080"},
+				tc.mockClient,
+				&MockAuthManager{loadTokenResult: "mock-token"},
+			)
+			cli.errOutput = errOutput
+
+			// ====Act====
+			code := cli.RunOnce(tc.args)
+
+			// ====Assert====
+			assert.Equal(t, tc.expectedCode, code)
+			assert.Contains(t, output.String(), tc.expectedOutput)
+			assert.Contains(t, errOutput.String(), tc.expectedErrOutput)
+			assert.NotContains(t, output.String(), "Enter", "prompts go to the error output")
+			if tc.assertClient != nil {
+				tc.assertClient(t, tc.mockClient)
+			}
+		})
+	}
+}
+
+// TestExitCodeFor tests the mapping of one-shot command errors to exit codes
+func TestExitCodeFor(t *testing.T) {
+	testCases := []struct {
+		name     string
+		err      error
+		expected int
+	}{
+		{name: "success", err: nil, expected: exitOK},
+		{name: "expired token", err: fmt.Errorf("adding task: %w", &client.AuthError{Message: "Authentication required"}), expected: exitAuth},
+		{name: "server unreachable", err: fmt.Errorf("listing tasks: %w", &client.NetworkError{URL: "http://localhost:8080", Err: errors.New("connection refused")}), expected: exitNetwork},
+		{name: "task not found", err: fmt.Errorf("toggling task: %w", &client.APIError{StatusCode: 404, Message: "task not found"}), expected: exitNotFound},
+		{name: "other API error", err: &client.APIError{StatusCode: 400, Message: "description too long"}, expected: exitError},
+		{name: "rate limited", err: &client.RateLimitError{}, expected: exitError},
+		{name: "invalid input", err: fmt.Errorf("adding task: %w", ErrEmptyInput), expected: exitError},
+		{name: "cancelled", err: context.Canceled, expected: exitError},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, exitCodeFor(tc.err))
+		})
+	}
+}
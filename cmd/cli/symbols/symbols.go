@@ -0,0 +1,63 @@
+// Package symbols holds the status prefixes the CLI prints, as emoji or as plain ASCII
+// for terminals and logs that cannot show emoji.
+package symbols
+
+// Set is one choice of prefixes; the CLI picks it once at startup and passes it on.
+// Prefixes that are followed by a double space in emoji output carry the extra space themselves.
+type Set struct {
+	OK        string // a command succeeded
+	Error     string // a command failed
+	Warning   string // something needs attention but the command went on
+	Wait      string // the server asked to retry later
+	Undo      string // a change was reverted
+	Retry     string // a command is run again
+	Bye       string // the session ended
+	User      string // account details
+	Lock      string // the session expired
+	Info      string // startup banner
+	Server    string // the server address in the startup banner
+	Done      string // list marker of a completed task
+	Important string // precedes the description of a high priority task
+}
+
+// Emoji is the default set.
+var Emoji = Set{
+	OK:        "✅",
+	Error:     "❌",
+	Warning:   "⚠️ ",
+	Wait:      "⏳",
+	Undo:      "↩️ ",
+	Retry:     "🔁",
+	Bye:       "👋",
+	User:      "👤",
+	Lock:      "🔒",
+	Info:      "🚀",
+	Server:    "📡",
+	Done:      "[✓]",
+	Important: "❗",
+}
+
+// Plain uses ASCII only, so output stays readable everywhere and easy to grep.
+var Plain = Set{
+	OK:        "[OK]",
+	Error:     "[ERR]",
+	Warning:   "[WARN]",
+	Wait:      "[WAIT]",
+	Undo:      "[UNDO]",
+	Retry:     "[RETRY]",
+	Bye:       "[BYE]",
+	User:      "[USER]",
+	Lock:      "[AUTH]",
+	Info:      "[INFO]",
+	Server:    "[INFO]",
+	Done:      "[x]",
+	Important: "!",
+}
+
+// For returns Plain when plain is set and Emoji otherwise.
+func For(plain bool) Set {
+	if plain {
+		return Plain
+	}
+	return Emoji
+}
@@ -7,7 +7,9 @@ import (
 	"log/slog"
 	"myproject/adapters/auth"
 	"myproject/adapters/grpcserver"
+	"myproject/adapters/webhook"
 	"myproject/application"
+	"myproject/cmd/internal/wiring"
 	"myproject/config"
 	"myproject/domain"
 	"net"
@@ -23,14 +25,18 @@ type App struct {
 	logger          *slog.Logger
 	server          *grpc.Server
 	storage         domain.AppStorage
+	webhook         *webhook.Notifier
 	shutdownTimeout time.Duration
 }
 
 func NewApp(cfg *config.Config, l *slog.Logger, store domain.AppStorage) (*App, error) {
-	jwtService := auth.NewJWTService(cfg.JWTConfig.Secret, cfg.JWTConfig.Expiration)
-	authService := application.NewAuthService(store, jwtService, l)
-	taskService := application.NewService(store)
-	grpcSrv := grpcserver.NewTaskManageServer(authService, taskService, l)
+	jwtService := auth.NewJWTService(cfg.JWTConfig.Secret, cfg.JWTConfig.Expiration, cfg.AuthConfig.ClockSkewLeeway)
+	authService := application.NewAuthService(store, jwtService, l, wiring.AuthServiceOptions(cfg)...)
+	notifier := wiring.WebhookNotifier(cfg, l)
+	taskService := wiring.TaskService(cfg, store, notifier)
+	grpcSrv := grpcserver.NewTaskManageServer(authService, taskService, l,
+		grpcserver.WithPageSizes(cfg.ServerConfig.DefaultPageSize, cfg.ServerConfig.MaxPageSize),
+	)
 	authInterceptor := grpcserver.NewAuthInterceptor(jwtService, l)
 
 	server := grpc.NewServer(
@@ -43,6 +49,7 @@ func NewApp(cfg *config.Config, l *slog.Logger, store domain.AppStorage) (*App,
 		logger:          l,
 		server:          server,
 		storage:         store,
+		webhook:         notifier,
 		shutdownTimeout: cfg.ServerConfig.ShutdownTimeout,
 	}, nil
 }
@@ -74,13 +81,12 @@ func (a *App) Run(ctx context.Context) error {
 	return a.shutdown()
 }
 
+// shutdown lets in-flight RPCs finish within the shutdown timeout, then cancels whatever is
+// still running with Stop. Storage is closed only once the server no longer serves requests.
 func (a *App) shutdown() error {
-	a.logger.Info("shutting down gracefully")
+	a.logger.Info("shutting down gracefully", slog.Duration("shutdown_timeout", a.shutdownTimeout))
 
-	shutdownCtx, cancel := context.WithTimeout(
-		context.Background(),
-		a.cfg.ServerConfig.ShutdownTimeout,
-	)
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), a.shutdownTimeout)
 	defer cancel()
 
 	var errs []error
@@ -94,8 +100,14 @@ func (a *App) shutdown() error {
 	case <-done:
 		a.logger.Info("gRPC server stopped gracefully")
 	case <-shutdownCtx.Done():
-		errs = append(errs, fmt.Errorf("gRPC shutdown timed out after %v", a.cfg.ServerConfig.ShutdownTimeout))
+		a.logger.Warn("graceful shutdown timed out, stopping gRPC server", slog.Duration("shutdown_timeout", a.shutdownTimeout))
+		errs = append(errs, fmt.Errorf("gRPC shutdown timed out after %v", a.shutdownTimeout))
 		a.server.Stop()
+		<-done
+	}
+
+	if err := wiring.WaitForWebhook(shutdownCtx, a.webhook); err != nil {
+		errs = append(errs, err)
 	}
 
 	if err := a.storage.Close(shutdownCtx); err != nil {
@@ -0,0 +1,246 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"myproject/adapters/auth"
+	"myproject/adapters/grpcserver"
+	"myproject/adapters/storage"
+	"myproject/application"
+	"myproject/config"
+	"myproject/domain"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/bcrypt"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+const testSecret = "test-only-secret-min32chars-long"
+
+type slowStorage struct {
+	domain.AppStorage
+	delay   time.Duration
+	started chan struct{}
+	closed  bool
+}
+
+func (s *slowStorage) LoadTasks(ctx context.Context, userID int, opts domain.ListOptions) ([]domain.Task, error) {
+	close(s.started)
+	time.Sleep(s.delay)
+	return []domain.Task{{ID: 1, Description: "task 1"}}, nil
+}
+
+func (s *slowStorage) Close(ctx context.Context) error {
+	s.closed = true
+	return nil
+}
+
+func TestApp_Shutdown(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping graceful shutdown test in short mode")
+	}
+
+	testCases := []struct {
+		name            string
+		port            int
+		delay           time.Duration
+		shutdownTimeout time.Duration
+		wantRPCErr      bool
+		wantRunErr      string
+	}{
+		{
+			name:            "waits for in-flight RPC",
+			port:            50091,
+			delay:           500 * time.Millisecond,
+			shutdownTimeout: 5 * time.Second,
+		},
+		{
+			name:            "stops RPCs still running after the timeout",
+			port:            50092,
+			delay:           time.Second,
+			shutdownTimeout: 200 * time.Millisecond,
+			wantRPCErr:      true,
+			wantRunErr:      "timed out",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			cfg := &config.Config{
+				GRPCConfig:   config.GRPCConfig{Port: tc.port},
+				ServerConfig: config.ServerConfig{ShutdownTimeout: tc.shutdownTimeout},
+				JWTConfig:    config.JWTConfig{Secret: testSecret, Expiration: time.Hour},
+			}
+			store := &slowStorage{delay: tc.delay, started: make(chan struct{})}
+			app, err := NewApp(cfg, slog.New(slog.NewTextHandler(io.Discard, nil)), store)
+			require.NoError(t, err)
+
+			runCtx, cancelRun := context.WithCancel(context.Background())
+			t.Cleanup(cancelRun)
+			runErr := make(chan error, 1)
+			go func() {
+				runErr <- app.Run(runCtx)
+			}()
+
+			rpcErr := make(chan error, 1)
+			go func() {
+				_, err := getTasks(t, tc.port)
+				rpcErr <- err
+			}()
+
+			select {
+			case <-store.started:
+			case <-time.After(5 * time.Second):
+				t.Fatal("RPC never reached storage")
+			}
+			cancelRun()
+
+			err = <-runErr
+			if tc.wantRunErr != "" {
+				assert.ErrorContains(t, err, tc.wantRunErr)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, tc.wantRPCErr, <-rpcErr != nil)
+			assert.True(t, store.closed, "storage should be closed after the server stops")
+		})
+	}
+}
+
+// getTasks calls GetTasks as user 1, retrying until the server accepts connections.
+func getTasks(t *testing.T, port int) (*grpcserver.GetTasksReply, error) {
+	token, err := auth.NewJWTService(testSecret, time.Hour, 0).GenerateToken(1, domain.RoleUser)
+	require.NoError(t, err)
+
+	conn, err := grpc.NewClient(fmt.Sprintf("localhost:%d", port), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	require.NoError(t, err)
+	defer conn.Close()
+
+	ctx := metadata.NewOutgoingContext(context.Background(), metadata.Pairs("authorization", "Bearer "+token))
+	return grpcserver.NewTaskManagerClient(conn).GetTasks(ctx, &grpcserver.GetTasksRequest{}, grpc.WaitForReady(true))
+}
+
+func TestApp_AuthConfig(t *testing.T) {
+	t.Run("hashes passwords with the configured bcrypt cost", func(t *testing.T) {
+		cfg := &config.Config{
+			GRPCConfig:   config.GRPCConfig{Port: 50093},
+			ServerConfig: config.ServerConfig{ShutdownTimeout: 5 * time.Second},
+			JWTConfig:    config.JWTConfig{Secret: testSecret, Expiration: time.Hour},
+			AuthConfig:   config.AuthConfig{BcryptCost: bcrypt.MinCost},
+		}
+		store := newTestStorage(t)
+		client := startApp(t, cfg, store)
+
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		_, err := client.Register(ctx, &grpcserver.RegisterRequest{Email: "user@example.com", Password: "Password123!"}, grpc.WaitForReady(true))
+		require.NoError(t, err)
+
+		user, err := store.GetUserByEmail(ctx, "user@example.com")
+		require.NoError(t, err)
+		cost, err := bcrypt.Cost([]byte(user.PasswordHash))
+		require.NoError(t, err)
+		assert.Equal(t, bcrypt.MinCost, cost)
+	})
+	t.Run("locks an email after repeated failed logins", func(t *testing.T) {
+		cfg := &config.Config{
+			GRPCConfig:   config.GRPCConfig{Port: 50094},
+			ServerConfig: config.ServerConfig{ShutdownTimeout: 5 * time.Second},
+			JWTConfig:    config.JWTConfig{Secret: testSecret, Expiration: time.Hour},
+			AuthConfig:   config.AuthConfig{BcryptCost: bcrypt.MinCost, LoginMaxAttempts: 2, LoginLockoutWindow: time.Minute},
+		}
+		client := startApp(t, cfg, newTestStorage(t))
+
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		_, err := client.Register(ctx, &grpcserver.RegisterRequest{Email: "user@example.com", Password: "Password123!"}, grpc.WaitForReady(true))
+		require.NoError(t, err)
+
+		for range 2 {
+			_, err = client.Login(ctx, &grpcserver.LoginRequest{Email: "user@example.com", Password: "wrong-password"})
+			assert.Equal(t, codes.Unauthenticated, status.Code(err))
+		}
+		_, err = client.Login(ctx, &grpcserver.LoginRequest{Email: "user@example.com", Password: "Password123!"})
+		assert.Equal(t, codes.PermissionDenied, status.Code(err), "the right password is refused while locked")
+	})
+}
+
+func TestApp_Webhook(t *testing.T) {
+	events := make(chan application.TaskEvent, 1)
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var event application.TaskEvent
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&event))
+		events <- event
+	}))
+	defer receiver.Close()
+
+	cfg := &config.Config{
+		GRPCConfig:    config.GRPCConfig{Port: 50095},
+		ServerConfig:  config.ServerConfig{ShutdownTimeout: 5 * time.Second},
+		JWTConfig:     config.JWTConfig{Secret: testSecret, Expiration: time.Hour},
+		AuthConfig:    config.AuthConfig{BcryptCost: bcrypt.MinCost},
+		WebhookConfig: config.WebhookConfig{URL: receiver.URL, Timeout: time.Second},
+	}
+	client := startApp(t, cfg, newTestStorage(t))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	_, err := client.Register(ctx, &grpcserver.RegisterRequest{Email: "user@example.com", Password: "Password123!"}, grpc.WaitForReady(true))
+	require.NoError(t, err)
+	login, err := client.Login(ctx, &grpcserver.LoginRequest{Email: "user@example.com", Password: "Password123!"})
+	require.NoError(t, err)
+	ctx = metadata.NewOutgoingContext(ctx, metadata.Pairs("authorization", "Bearer "+login.Token))
+	_, err = client.CreateTask(ctx, &grpcserver.CreateTaskRequest{Description: "task 1"})
+	require.NoError(t, err)
+
+	select {
+	case event := <-events:
+		assert.Equal(t, application.EventTaskCreated, event.Name)
+		assert.Equal(t, "task 1", event.Task.Description)
+	case <-ctx.Done():
+		t.Fatal("webhook was not called")
+	}
+}
+
+// newTestStorage opens a SQLite database in a temporary directory.
+func newTestStorage(t *testing.T) *storage.DatabaseStorage {
+	t.Helper()
+	store, err := storage.NewDatabaseStorage(filepath.Join(t.TempDir(), "tasks.db"), slog.New(slog.NewTextHandler(io.Discard, nil)))
+	require.NoError(t, err)
+	return store
+}
+
+// startApp runs an App for cfg until the test ends and returns a client connected to it.
+func startApp(t *testing.T, cfg *config.Config, store domain.AppStorage) grpcserver.TaskManagerClient {
+	t.Helper()
+	app, err := NewApp(cfg, slog.New(slog.NewTextHandler(io.Discard, nil)), store)
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	runErr := make(chan error, 1)
+	go func() {
+		runErr <- app.Run(ctx)
+	}()
+	t.Cleanup(func() {
+		cancel()
+		assert.NoError(t, <-runErr)
+	})
+
+	conn, err := grpc.NewClient(fmt.Sprintf("localhost:%d", cfg.GRPCConfig.Port), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	require.NoError(t, err)
+	t.Cleanup(func() { conn.Close() })
+	return grpcserver.NewTaskManagerClient(conn)
+}
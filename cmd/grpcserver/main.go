@@ -26,7 +26,12 @@ func main() {
 		slog.String("service_name", cfg.LogConfig.ServiceName),
 	)
 
-	store, err := storage.NewDatabaseStorage(cfg.DatabaseConfig.Path, l)
+	connConfig := storage.DefaultConnectionConfig()
+	connConfig.MaxOpenConns = cfg.DatabaseConfig.MaxOpenConns
+	connConfig.MaxIdleConns = cfg.DatabaseConfig.MaxIdleConns
+	connConfig.ConnMaxLifetime = cfg.DatabaseConfig.ConnMaxLifetime
+	connConfig.BusyTimeout = cfg.DatabaseConfig.BusyTimeout
+	store, err := storage.NewDatabaseStorageWithConfig(cfg.DatabaseConfig.Path, connConfig, l)
 	if err != nil {
 		l.Error("Failed to initialize database",
 			slog.String("operation", "database_init"),
@@ -0,0 +1,26 @@
+// Package wiring builds the parts of the application both servers configure the same way,
+// so the REST and gRPC servers cannot drift apart.
+package wiring
+
+import (
+	"myproject/application"
+	"myproject/config"
+	"myproject/domain"
+)
+
+// AuthServiceOptions returns the AuthService options set by the auth section and feature flags of cfg.
+func AuthServiceOptions(cfg *config.Config) []application.AuthServiceOption {
+	return []application.AuthServiceOption{
+		application.WithSuccessLogging(cfg.Features().AuthSuccessLogging),
+		application.WithHashedEmails(cfg.AuthConfig.HashEmails),
+		application.WithBcryptCost(cfg.AuthConfig.BcryptCost),
+		application.WithLoginLockout(cfg.AuthConfig.LoginMaxAttempts, cfg.AuthConfig.LoginLockoutWindow),
+		application.WithPasswordPolicy(domain.PasswordPolicy{
+			MinLength:      cfg.AuthConfig.PasswordMinLength,
+			RequireLetter:  cfg.AuthConfig.PasswordRequireLetter,
+			RequireDigit:   cfg.AuthConfig.PasswordRequireDigit,
+			RequireUpper:   cfg.AuthConfig.PasswordRequireUpper,
+			RequireSpecial: cfg.AuthConfig.PasswordRequireSpecial,
+		}),
+	}
+}
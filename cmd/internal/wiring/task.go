@@ -0,0 +1,60 @@
+package wiring
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"myproject/adapters/webhook"
+	"myproject/application"
+	"myproject/config"
+	"myproject/domain"
+)
+
+// TaskService builds the task service with the configured description limit,
+// enabling description templates when configured and sending task events to the webhook if there is one.
+func TaskService(cfg *config.Config, s domain.Storage, notifier *webhook.Notifier) *application.Service {
+	opts := []application.ServiceOption{
+		application.WithMaxDescriptionLength(cfg.TaskConfig.MaxDescriptionLength),
+		application.WithoutDuplicates(cfg.Features().RejectDuplicates),
+	}
+	if cfg.Features().ExpandTemplates {
+		opts = append(opts, application.WithTemplateExpander(application.NewTemplateExpander(cfg.TaskConfig.Location())))
+	}
+	if notifier != nil {
+		opts = append(opts,
+			application.WithHook(application.EventTaskCreated, notifier.Notify),
+			application.WithHook(application.EventTaskCompleted, notifier.Notify),
+		)
+	}
+	return application.NewService(s, opts...)
+}
+
+// WebhookNotifier returns the notifier for the configured webhook URL, or nil when no webhook is set.
+func WebhookNotifier(cfg *config.Config, l *slog.Logger) *webhook.Notifier {
+	if cfg.WebhookConfig.URL == "" {
+		return nil
+	}
+	l.Info("Webhook enabled",
+		slog.Duration("timeout", cfg.WebhookConfig.Timeout),
+		slog.Int("retries", cfg.WebhookConfig.Retries),
+	)
+	return webhook.NewNotifier(cfg.WebhookConfig.URL, cfg.WebhookConfig.Timeout, cfg.WebhookConfig.Retries, l)
+}
+
+// WaitForWebhook waits for the notifier's pending deliveries until ctx is done; a nil notifier has none.
+func WaitForWebhook(ctx context.Context, notifier *webhook.Notifier) error {
+	if notifier == nil {
+		return nil
+	}
+	delivered := make(chan struct{})
+	go func() {
+		notifier.Wait()
+		close(delivered)
+	}()
+	select {
+	case <-delivered:
+		return nil
+	case <-ctx.Done():
+		return fmt.Errorf("webhook deliveries: %w", ctx.Err())
+	}
+}
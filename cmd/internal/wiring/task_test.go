@@ -0,0 +1,50 @@
+package wiring
+
+import (
+	"context"
+	"fmt"
+	"myproject/config"
+	"myproject/domain"
+	"myproject/infrastructure/testhelpers"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTaskService_ExpandTemplatesFeature(t *testing.T) {
+	testCases := []struct {
+		name         string
+		enabled      bool
+		wantExpanded bool
+	}{
+		{name: "expands placeholders when feature is enabled", enabled: true, wantExpanded: true},
+		{name: "skips expansion when feature is disabled", enabled: false, wantExpanded: false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			cfg := &config.Config{FeaturesConfig: config.FeaturesConfig{ExpandTemplates: tc.enabled}}
+			store := &testhelpers.StubTaskStore{Tasks: map[int]string{}}
+
+			task, err := TaskService(cfg, store, nil).CreateTask(context.Background(), "Report {year}", false, nil, 0, nil, 1)
+
+			require.NoError(t, err)
+			if tc.wantExpanded {
+				assert.Equal(t, fmt.Sprintf("Report %d", time.Now().UTC().Year()), task.Description)
+			} else {
+				assert.Equal(t, "Report {year}", task.Description)
+			}
+		})
+	}
+}
+
+func TestTaskService_RejectDuplicatesFeature(t *testing.T) {
+	cfg := &config.Config{FeaturesConfig: config.FeaturesConfig{RejectDuplicates: true}}
+	store := &testhelpers.StubTaskStore{Tasks: map[int]string{1: "Report"}}
+
+	_, err := TaskService(cfg, store, nil).CreateTask(context.Background(), "Report", false, nil, 0, nil, 1)
+
+	assert.ErrorIs(t, err, domain.ErrDuplicateTask)
+}
@@ -6,37 +6,32 @@ import (
 	"fmt"
 	"log/slog"
 	"myproject/adapters/auth"
+	"myproject/adapters/webhook"
 	"myproject/adapters/webserver"
 	"myproject/application"
+	"myproject/cmd/internal/wiring"
 	"myproject/config"
 	"myproject/domain"
+	"net"
 	"net/http"
+	"os"
 	"os/signal"
+	"strconv"
 	"syscall"
 )
 
-var endpointsList = []string{
-	"GET /",
-	"GET /health",
-	"GET /tasks",
-	"POST /tasks",
-	"GET /tasks/{id}",
-	"PUT /tasks/{id}",
-	"DELETE /tasks/{id}",
-	"POST /register",
-	"POST /login",
-}
-
 type App struct {
 	cfg     *config.Config
 	logger  *slog.Logger
 	server  *http.Server
 	storage domain.AppStorage
+	webhook *webhook.Notifier
 }
 
-func NewApp(cfg *config.Config, l *slog.Logger, s domain.AppStorage) (*App, error) {
-	jwtService := auth.NewJWTService(cfg.JWTConfig.Secret, cfg.JWTConfig.Expiration)
-	authService := application.NewAuthService(s, jwtService, l)
+// NewApp wires the HTTP server; level is the logger's level variable, exposed through PUT /admin/loglevel.
+func NewApp(cfg *config.Config, l *slog.Logger, level *slog.LevelVar, s domain.AppStorage) (*App, error) {
+	jwtService := auth.NewJWTService(cfg.JWTConfig.Secret, cfg.JWTConfig.Expiration, cfg.AuthConfig.ClockSkewLeeway)
+	authService := application.NewAuthService(s, jwtService, l, wiring.AuthServiceOptions(cfg)...)
 	authMiddleware := webserver.NewAuthMiddleware(jwtService, l)
 
 	l.Info("Database storage initialized",
@@ -47,11 +42,40 @@ func NewApp(cfg *config.Config, l *slog.Logger, s domain.AppStorage) (*App, erro
 		slog.Duration("expiration", cfg.JWTConfig.Expiration),
 	)
 
-	tasksServer := webserver.NewTasksServer(s, authService, authMiddleware, l)
+	notifier := wiring.WebhookNotifier(cfg, l)
+
+	opts := []webserver.Option{
+		webserver.WithTaskService(wiring.TaskService(cfg, s, notifier)),
+		webserver.WithUI(cfg.Features().ServeUI),
+		webserver.WithStrictOwnership(cfg.Features().StrictOwnership),
+		webserver.WithLogLevel(level),
+		webserver.WithIdempotencyWindow(cfg.ServerConfig.IdempotencyWindow),
+		webserver.WithMaxDescriptionLength(cfg.TaskConfig.MaxDescriptionLength),
+		webserver.WithMaxBodyBytes(cfg.ServerConfig.MaxBodyBytes),
+		webserver.WithPageSizes(cfg.ServerConfig.DefaultPageSize, cfg.ServerConfig.MaxPageSize),
+		webserver.WithSlowRequestThreshold(cfg.LogConfig.SlowRequestThreshold),
+		webserver.WithCORS(webserver.CORSPolicy{
+			AllowedOrigins:   cfg.CORSConfig.AllowedOrigins,
+			AllowedMethods:   cfg.CORSConfig.AllowedMethods,
+			AllowCredentials: cfg.CORSConfig.AllowCredentials,
+		}),
+	}
+	if cfg.AuthConfig.RateLimit > 0 {
+		opts = append(opts, webserver.WithAuthRateLimit(auth.RateLimitMiddleware(cfg.AuthConfig.RateLimit, cfg.AuthConfig.RateLimitWindow)))
+		l.Info("Auth rate limit enabled",
+			slog.Int("limit", cfg.AuthConfig.RateLimit),
+			slog.Duration("window", cfg.AuthConfig.RateLimitWindow),
+		)
+	}
+	tasksServer := webserver.NewTasksServer(s, authService, authMiddleware, l, opts...)
 
+	scheme := "http"
+	if cfg.TLSConfig.Enabled {
+		scheme = "https"
+	}
 	l.Info("HTTP Server initialized",
-		slog.String("server_address", fmt.Sprintf("http://%s:%d", cfg.ServerConfig.Host, cfg.ServerConfig.Port)),
-		slog.Any("endpoints", endpointsList),
+		slog.String("server_address", fmt.Sprintf("%s://%s:%d", scheme, cfg.ServerConfig.Host, cfg.ServerConfig.Port)),
+		slog.Any("endpoints", tasksServer.Endpoints()),
 		slog.Duration("shutdown_timeout", cfg.ServerConfig.ShutdownTimeout),
 	)
 
@@ -69,6 +93,7 @@ func NewApp(cfg *config.Config, l *slog.Logger, s domain.AppStorage) (*App, erro
 		logger:  l,
 		server:  server,
 		storage: s,
+		webhook: notifier,
 	}, nil
 }
 
@@ -76,11 +101,40 @@ func (a *App) Run(ctx context.Context) error {
 	ctx, stop := signal.NotifyContext(ctx, syscall.SIGINT, syscall.SIGTERM)
 	defer stop()
 
-	serverErr := make(chan error, 1)
+	lis, err := listenTCP(a.cfg.ServerConfig.Host, a.cfg.ServerConfig.Port, a.cfg.ServerConfig.AutoPort)
+	if err != nil {
+		return fmt.Errorf("failed to listen on %s: %w", a.server.Addr, err)
+	}
+	if port := lis.Addr().(*net.TCPAddr).Port; port != a.cfg.ServerConfig.Port {
+		a.logger.Warn("configured port is in use, listening on the next free port",
+			slog.Int("configured_port", a.cfg.ServerConfig.Port),
+			slog.Int("port", port),
+		)
+	}
+
+	serverErr := make(chan error, 2)
+
+	if socketPath := a.cfg.ServerConfig.UnixSocket; socketPath != "" {
+		unixLis, err := listenUnix(socketPath)
+		if err != nil {
+			lis.Close()
+			return fmt.Errorf("failed to create unix socket listener: %w", err)
+		}
+
+		go func() {
+			a.logger.Info("starting server on unix socket", slog.String("socket_path", socketPath))
+			if err := a.server.Serve(unixLis); err != nil && err != http.ErrServerClosed {
+				serverErr <- err
+			}
+		}()
+	}
 
 	go func() {
-		a.logger.Info("starting server", slog.String("server_address", a.server.Addr))
-		if err := a.server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+		a.logger.Info("starting server",
+			slog.String("server_address", lis.Addr().String()),
+			slog.Bool("tls", a.cfg.TLSConfig.Enabled),
+		)
+		if err := a.serve(lis); err != nil && err != http.ErrServerClosed {
 			serverErr <- err
 		}
 	}()
@@ -95,6 +149,41 @@ func (a *App) Run(ctx context.Context) error {
 	return a.shutdown()
 }
 
+// serve serves the TCP listener over HTTPS when TLS is enabled and plain HTTP otherwise.
+// Both return http.ErrServerClosed once shutdown starts, so graceful shutdown works the same way.
+func (a *App) serve(lis net.Listener) error {
+	if tls := a.cfg.TLSConfig; tls.Enabled {
+		return a.server.ServeTLS(lis, tls.CertFile, tls.KeyFile)
+	}
+	return a.server.Serve(lis)
+}
+
+// autoPortAttempts is how many ports after the configured one are tried when server.auto_port is on.
+const autoPortAttempts = 10
+
+// listenTCP listens on host:port. With autoPort a port that is already in use is skipped in favour of
+// the next free one among the following autoPortAttempts ports; any other error, or every port being
+// taken, is returned as is, so without autoPort a busy port fails at startup.
+func listenTCP(host string, port int, autoPort bool) (net.Listener, error) {
+	attempts := 1
+	if autoPort {
+		attempts += autoPortAttempts
+	}
+
+	var err error
+	for i := 0; i < attempts && port+i <= 65535; i++ {
+		var lis net.Listener
+		lis, err = net.Listen("tcp", net.JoinHostPort(host, strconv.Itoa(port+i)))
+		if err == nil {
+			return lis, nil
+		}
+		if !errors.Is(err, syscall.EADDRINUSE) {
+			return nil, err
+		}
+	}
+	return nil, err
+}
+
 func (a *App) shutdown() error {
 	a.logger.Info("shutting down gracefully")
 
@@ -110,6 +199,16 @@ func (a *App) shutdown() error {
 		errs = append(errs, fmt.Errorf("server shutdown: %w", err))
 	}
 
+	if err := wiring.WaitForWebhook(shutdownCtx, a.webhook); err != nil {
+		errs = append(errs, err)
+	}
+
+	if socketPath := a.cfg.ServerConfig.UnixSocket; socketPath != "" {
+		if err := removeSocket(socketPath); err != nil {
+			errs = append(errs, fmt.Errorf("unix socket cleanup: %w", err))
+		}
+	}
+
 	if err := a.storage.Close(shutdownCtx); err != nil {
 		errs = append(errs, fmt.Errorf("storage close: %w", err))
 	}
@@ -121,3 +220,42 @@ func (a *App) shutdown() error {
 	a.logger.Info("shutdown complete")
 	return nil
 }
+
+// listenUnix creates a unix domain socket listener, removing a stale socket file left by a previous run.
+// The socket is restricted to the owner so only local processes of the same user can connect.
+func listenUnix(path string) (net.Listener, error) {
+	if err := removeSocket(path); err != nil {
+		return nil, fmt.Errorf("remove stale socket: %w", err)
+	}
+
+	lis, err := net.Listen("unix", path)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := os.Chmod(path, 0600); err != nil {
+		lis.Close()
+		return nil, fmt.Errorf("set socket permissions %s: %w", path, err)
+	}
+
+	return lis, nil
+}
+
+// removeSocket removes the unix socket at path; a missing path is not an error. Anything other than
+// a socket is left in place and reported, so a mistyped server.unix_socket cannot delete a real file.
+func removeSocket(path string) error {
+	info, err := os.Lstat(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if info.Mode()&os.ModeSocket == 0 {
+		return fmt.Errorf("%s exists and is not a unix socket", path)
+	}
+	if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	return nil
+}
@@ -2,18 +2,29 @@ package main
 
 import (
 	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
 	"fmt"
+	"math/big"
 	"myproject/adapters/auth"
 	"myproject/adapters/storage"
 	"myproject/config"
 	"myproject/domain"
 	"myproject/logger"
+	"net"
 	"net/http"
 	"os"
 	"path/filepath"
+	"syscall"
 	"testing"
 	"time"
 
+	"github.com/spf13/pflag"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -24,11 +35,11 @@ type slowStorage struct {
 	started chan struct{}
 }
 
-func (s *slowStorage) LoadTasks(ctx context.Context, userID int) ([]domain.Task, error) {
+func (s *slowStorage) LoadTasks(ctx context.Context, userID int, opts domain.ListOptions) ([]domain.Task, error) {
 	close(s.started)
 
 	time.Sleep(s.delay)
-	return s.AppStorage.LoadTasks(ctx, userID)
+	return s.AppStorage.LoadTasks(ctx, userID, opts)
 }
 
 func TestApp_GracefulShutdown(t *testing.T) {
@@ -61,8 +72,8 @@ func TestApp_GracefulShutdown(t *testing.T) {
 	}, 10)
 	require.NoError(t, err, "server did not become healthy in time")
 
-	jwtService := auth.NewJWTService(cfg.JWTConfig.Secret, cfg.JWTConfig.Expiration)
-	token, err := jwtService.GenerateToken(1)
+	jwtService := auth.NewJWTService(cfg.JWTConfig.Secret, cfg.JWTConfig.Expiration, cfg.AuthConfig.ClockSkewLeeway)
+	token, err := jwtService.GenerateToken(1, domain.RoleUser)
 	require.NoError(t, err)
 
 	req := newAuthenticatedRequest(t, http.MethodGet, "http://localhost:8888/tasks", token)
@@ -99,16 +110,20 @@ func TestApp_GracefulShutdown(t *testing.T) {
 	assert.NoError(t, err)
 }
 
-func newTestApp(t *testing.T, delay time.Duration) (app *App, cfg *config.Config, slowDB *slowStorage) {
+func newTestApp(t *testing.T, delay time.Duration, configure ...func(*config.Config)) (app *App, cfg *config.Config, slowDB *slowStorage) {
 	t.Helper()
 
 	os.Setenv("TASKMANAGER_JWT_SECRET", "test-only-secret-min32chars-long")
 
+	pflag.CommandLine = pflag.NewFlagSet(os.Args[0], pflag.ExitOnError)
 	cfg, _, err := config.LoadConfig()
 	require.NoError(t, err)
 	cfg.ServerConfig.Port = 8888
+	for _, c := range configure {
+		c(cfg)
+	}
 
-	l, err := logger.NewLogger(&logger.Config{
+	l, handle, err := logger.NewLoggerWithHandle(&logger.Config{
 		Level:       "error",
 		Format:      "text",
 		Output:      "stderr",
@@ -127,12 +142,140 @@ func newTestApp(t *testing.T, delay time.Duration) (app *App, cfg *config.Config
 		started:    make(chan struct{}),
 	}
 
-	app, err = NewApp(cfg, l, slowDB)
+	app, err = NewApp(cfg, l, handle.Level, slowDB)
 	require.NoError(t, err)
 
 	return app, cfg, slowDB
 }
 
+func TestApp_TLS(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping TLS server test in short mode")
+	}
+
+	certFile, keyFile, pool := writeTestCertificate(t)
+	app, _, _ := newTestApp(t, 0, func(cfg *config.Config) {
+		cfg.ServerConfig.Port = 8889
+		cfg.TLSConfig = config.TLSConfig{Enabled: true, CertFile: certFile, KeyFile: keyFile}
+	})
+
+	runCtx, cancelRun := context.WithCancel(context.Background())
+	serverDone := make(chan error, 1)
+	go func() {
+		serverDone <- app.Run(runCtx)
+	}()
+	t.Cleanup(cancelRun)
+
+	client := &http.Client{Transport: &http.Transport{TLSClientConfig: &tls.Config{RootCAs: pool}}}
+	_, err := storage.Retry(func() (bool, error) {
+		response, err := client.Get("https://localhost:8889/health")
+		if err != nil {
+			return false, err
+		}
+		defer response.Body.Close()
+		return response.StatusCode == http.StatusOK, nil
+	}, 10)
+	require.NoError(t, err, "server did not answer over HTTPS")
+
+	cancelRun()
+	assert.NoError(t, <-serverDone)
+}
+
+func TestApp_AutoPort(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping auto port test in short mode")
+	}
+
+	occupied, err := net.Listen("tcp", ":8890")
+	require.NoError(t, err)
+	t.Cleanup(func() { occupied.Close() })
+
+	app, _, _ := newTestApp(t, 0, func(cfg *config.Config) {
+		cfg.ServerConfig.Port = 8890
+		cfg.ServerConfig.AutoPort = true
+	})
+
+	runCtx, cancelRun := context.WithCancel(context.Background())
+	serverDone := make(chan error, 1)
+	go func() {
+		serverDone <- app.Run(runCtx)
+	}()
+	t.Cleanup(cancelRun)
+
+	_, err = storage.Retry(func() (bool, error) {
+		response, err := http.Get("http://localhost:8891/health")
+		if err != nil {
+			return false, err
+		}
+		defer response.Body.Close()
+		return response.StatusCode == http.StatusOK, nil
+	}, 10)
+	require.NoError(t, err, "server did not move to the next port")
+
+	cancelRun()
+	assert.NoError(t, <-serverDone)
+}
+
+func TestApp_UnixSocketNotASocket(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "tasks.db")
+	require.NoError(t, os.WriteFile(path, []byte("data"), 0644))
+	app, _, _ := newTestApp(t, 0, func(cfg *config.Config) {
+		cfg.ServerConfig.Port = 8892
+		cfg.ServerConfig.UnixSocket = path
+	})
+
+	err := app.Run(context.Background())
+	assert.ErrorContains(t, err, "not a unix socket")
+
+	lis, err := net.Listen("tcp", ":8892")
+	require.NoError(t, err, "the TCP listener is closed when the unix socket fails")
+	lis.Close()
+}
+
+func TestListenTCP_PortInUse(t *testing.T) {
+	occupied, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer occupied.Close()
+	port := occupied.Addr().(*net.TCPAddr).Port
+
+	_, err = listenTCP("127.0.0.1", port, false)
+	assert.ErrorIs(t, err, syscall.EADDRINUSE, "without auto port a busy port must fail")
+}
+
+// writeTestCertificate creates a self-signed certificate for localhost and returns
+// the PEM file paths together with a pool that trusts it.
+func writeTestCertificate(t *testing.T) (certFile, keyFile string, pool *x509.CertPool) {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "localhost"},
+		DNSNames:     []string{"localhost"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.NoError(t, err)
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	require.NoError(t, err)
+
+	dir := t.TempDir()
+	certFile = filepath.Join(dir, "cert.pem")
+	keyFile = filepath.Join(dir, "key.pem")
+	require.NoError(t, os.WriteFile(certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0600))
+	require.NoError(t, os.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}), 0600))
+
+	cert, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+	pool = x509.NewCertPool()
+	pool.AddCert(cert)
+	return certFile, keyFile, pool
+}
+
 func newAuthenticatedRequest(t *testing.T, method, url, token string) *http.Request {
 	t.Helper()
 
@@ -141,3 +284,36 @@ func newAuthenticatedRequest(t *testing.T, method, url, token string) *http.Requ
 	req.Header.Set("Authorization", "Bearer "+token)
 	return req
 }
+
+func TestListenUnix(t *testing.T) {
+	t.Run("replaces a stale socket", func(t *testing.T) {
+		socketPath := filepath.Join(t.TempDir(), "tasks.sock")
+		stale, err := net.Listen("unix", socketPath)
+		require.NoError(t, err)
+		stale.(*net.UnixListener).SetUnlinkOnClose(false)
+		require.NoError(t, stale.Close())
+
+		lis, err := listenUnix(socketPath)
+		require.NoError(t, err)
+
+		info, err := os.Stat(socketPath)
+		require.NoError(t, err)
+		assert.Equal(t, os.ModeSocket, info.Mode().Type())
+		assert.Equal(t, os.FileMode(0600), info.Mode().Perm())
+
+		require.NoError(t, lis.Close())
+		_, err = os.Stat(socketPath)
+		assert.True(t, os.IsNotExist(err), "socket file should be removed when the listener closes")
+	})
+	t.Run("refuses to remove a file that is not a socket", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "tasks.db")
+		require.NoError(t, os.WriteFile(path, []byte("data"), 0644))
+
+		_, err := listenUnix(path)
+		assert.ErrorContains(t, err, "not a unix socket")
+
+		content, err := os.ReadFile(path)
+		require.NoError(t, err)
+		assert.Equal(t, "data", string(content))
+	})
+}
@@ -2,12 +2,16 @@ package main
 
 import (
 	"context"
+	"database/sql"
+	"fmt"
 	"log"
 	"log/slog"
 	"myproject/adapters/storage"
 	"myproject/config"
 	"myproject/logger"
 	"os"
+	"strings"
+	"time"
 
 	"github.com/spf13/pflag"
 )
@@ -24,7 +28,35 @@ func main() {
 		os.Exit(0)
 	}
 
-	l, err := logger.NewLogger(&cfg.LogConfig)
+	// Check if --migration-status flag was set
+	if pflag.Lookup("migration-status").Changed && pflag.Lookup("migration-status").Value.String() == "true" {
+		if err := migrationStatus(cfg.DatabaseConfig.Path); err != nil {
+			log.Fatal(err)
+		}
+		os.Exit(0)
+	}
+
+	// Check if --migrate-dry-run flag was set
+	if pflag.Lookup("migrate-dry-run").Changed && pflag.Lookup("migrate-dry-run").Value.String() == "true" {
+		if err := migrateDryRun(cfg.DatabaseConfig.Path); err != nil {
+			log.Fatal(err)
+		}
+		os.Exit(0)
+	}
+
+	// Check if --migrate-down flag was set
+	if pflag.Lookup("migrate-down").Changed {
+		target, err := pflag.CommandLine.GetInt("migrate-down")
+		if err != nil {
+			log.Fatal(err)
+		}
+		if err := migrateDown(cfg.DatabaseConfig.Path, target); err != nil {
+			log.Fatal(err)
+		}
+		os.Exit(0)
+	}
+
+	l, logHandle, err := logger.NewLoggerWithHandle(&cfg.LogConfig)
 	if err != nil {
 		log.Fatal(err)
 	}
@@ -36,7 +68,12 @@ func main() {
 		slog.String("service_name", cfg.LogConfig.ServiceName),
 	)
 
-	db, err := storage.NewDatabaseStorage(cfg.DatabaseConfig.Path, l)
+	connConfig := storage.DefaultConnectionConfig()
+	connConfig.MaxOpenConns = cfg.DatabaseConfig.MaxOpenConns
+	connConfig.MaxIdleConns = cfg.DatabaseConfig.MaxIdleConns
+	connConfig.ConnMaxLifetime = cfg.DatabaseConfig.ConnMaxLifetime
+	connConfig.BusyTimeout = cfg.DatabaseConfig.BusyTimeout
+	db, err := storage.NewDatabaseStorageWithConfig(cfg.DatabaseConfig.Path, connConfig, l)
 	if err != nil {
 		l.Error("Failed to initialize database",
 			slog.String("operation", "database_init"),
@@ -46,7 +83,7 @@ func main() {
 		log.Fatal(err)
 	}
 
-	app, err := NewApp(cfg, l, db)
+	app, err := NewApp(cfg, l, logHandle.Level, db)
 	if err != nil {
 		log.Fatal(err)
 	}
@@ -54,4 +91,102 @@ func main() {
 	if err := app.Run(context.Background()); err != nil {
 		l.Error("application error", slog.String("error", err.Error()))
 	}
+
+	if err := logHandle.Close(); err != nil {
+		log.Printf("failed to close log output: %v", err)
+	}
+}
+
+// migrateDown rolls the database schema back to the target version and prints the result.
+func migrateDown(path string, target int) error {
+	db, err := openExistingDatabase(path)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	migrator := storage.NewMigratorWithDefaults(db)
+	from, err := migrator.GetCurrentVersion()
+	if err != nil {
+		return fmt.Errorf("failed to read schema version: %w", err)
+	}
+
+	if err := migrator.RollbackTo(target); err != nil {
+		return fmt.Errorf("failed to roll back migrations: %w", err)
+	}
+
+	fmt.Printf("Rolled back database schema from version %d to %d\n", from, target)
+	return nil
+}
+
+// migrationStatus prints every registered migration with an applied/pending marker.
+func migrationStatus(path string) error {
+	db, err := openExistingDatabase(path)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	statuses, err := storage.NewMigratorWithDefaults(db).Status()
+	if err != nil {
+		return fmt.Errorf("failed to read migration status: %w", err)
+	}
+
+	current := 0
+	for _, status := range statuses {
+		if status.Applied && status.Version > current {
+			current = status.Version
+		}
+	}
+
+	fmt.Printf("Database: %s\n", path)
+	fmt.Printf("Schema version: %d\n", current)
+	for _, status := range statuses {
+		if !status.Applied {
+			fmt.Printf("  [ ] %d %s (pending)\n", status.Version, status.Name)
+			continue
+		}
+		appliedAt := "unknown"
+		if status.AppliedAt != nil {
+			appliedAt = status.AppliedAt.Format(time.DateTime)
+		}
+		fmt.Printf("  [x] %d %s (applied %s)\n", status.Version, status.Name, appliedAt)
+	}
+	return nil
+}
+
+// migrateDryRun prints the version, name and Up SQL of every pending migration without running any.
+func migrateDryRun(path string) error {
+	db, err := openExistingDatabase(path)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	plan, err := storage.NewMigratorWithDefaults(db).PlanMigrations()
+	if err != nil {
+		return fmt.Errorf("failed to plan migrations: %w", err)
+	}
+
+	fmt.Printf("Database: %s\n", path)
+	if len(plan) == 0 {
+		fmt.Println("No pending migrations")
+		return nil
+	}
+	for _, migration := range plan {
+		fmt.Printf("-- %d %s\n%s\n\n", migration.Version, migration.Name, strings.TrimSpace(migration.Up))
+	}
+	return nil
+}
+
+// openExistingDatabase opens the database at path without creating it when it is missing.
+func openExistingDatabase(path string) (*sql.DB, error) {
+	if _, err := os.Stat(path); err != nil {
+		return nil, fmt.Errorf("failed to open database: %w", err)
+	}
+	db, err := storage.CreateConnection(&storage.ConnectionConfig{MaxOpenConns: 1, MaxIdleConns: 1}, path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open database: %w", err)
+	}
+	return db, nil
 }
@@ -6,6 +6,7 @@ import (
 	"errors"
 	"fmt"
 	"myproject/logger"
+	"net/url"
 	"os"
 	"path/filepath"
 	"strings"
@@ -13,18 +14,31 @@ import (
 
 	"github.com/spf13/pflag"
 	"github.com/spf13/viper"
+	"golang.org/x/crypto/bcrypt"
 )
 
 // MinJWTSecretLength is the minimum required length for JWT secret keys.
 const MinJWTSecretLength = 32
 
+// MaxPasswordMinLength keeps the password length policy satisfiable within bcrypt's 72-byte limit.
+const MaxPasswordMinLength = 72
+
+// MaxClockSkewLeeway bounds the tolerance applied to JWT time-based claims.
+const MaxClockSkewLeeway = 5 * time.Minute
+
 // Config holds all application configuration settings.
 type Config struct {
 	ServerConfig   ServerConfig   `mapstructure:"server"`
 	GRPCConfig     GRPCConfig     `mapstructure:"grpc"`
 	DatabaseConfig DatabaseConfig `mapstructure:"database"`
 	JWTConfig      JWTConfig      `mapstructure:"jwt"`
+	AuthConfig     AuthConfig     `mapstructure:"auth"`
 	LogConfig      logger.Config  `mapstructure:"logging"`
+	TaskConfig     TaskConfig     `mapstructure:"tasks"`
+	FeaturesConfig FeaturesConfig `mapstructure:"features"`
+	CORSConfig     CORSConfig     `mapstructure:"cors"`
+	TLSConfig      TLSConfig      `mapstructure:"tls"`
+	WebhookConfig  WebhookConfig  `mapstructure:"webhook"`
 }
 
 // ServerConfig contains HTTP server configuration.
@@ -32,9 +46,22 @@ type ServerConfig struct {
 	Port            int           `mapstructure:"port"`
 	Host            string        `mapstructure:"host"`
 	ShutdownTimeout time.Duration `mapstructure:"shutdown_timeout"`
-	ReadTimeout     time.Duration `mapstructure:"read_timeout"`
-	WriteTimeout    time.Duration `mapstructure:"write_timeout"`
-	IdleTimeout     time.Duration `mapstructure:"idle_timeout"`
+	// ReadTimeout, WriteTimeout and IdleTimeout are passed to http.Server; 0 means no timeout.
+	// IdleTimeout is how long a keep-alive connection may wait for its next request.
+	ReadTimeout  time.Duration `mapstructure:"read_timeout"`
+	WriteTimeout time.Duration `mapstructure:"write_timeout"`
+	IdleTimeout  time.Duration `mapstructure:"idle_timeout"`
+	UnixSocket   string        `mapstructure:"unix_socket"`
+	// AutoPort moves the server to one of the next free ports when Port is already in use; meant for development
+	AutoPort bool `mapstructure:"auto_port"`
+	// IdempotencyWindow is how long an Idempotency-Key on POST /tasks is remembered; 0 ignores the header
+	IdempotencyWindow time.Duration `mapstructure:"idempotency_window"`
+	// MaxBodyBytes caps the size of request bodies; larger JSON payloads are refused with 413
+	MaxBodyBytes int64 `mapstructure:"max_body_bytes"`
+	// DefaultPageSize is the number of tasks GET /tasks returns when no limit is given; 0 keeps the default of 50
+	DefaultPageSize int `mapstructure:"default_page_size"`
+	// MaxPageSize is the largest limit GET /tasks honours, larger limits are clamped to it; 0 keeps the default of 200
+	MaxPageSize int `mapstructure:"max_page_size"`
 }
 
 type GRPCConfig struct {
@@ -42,16 +69,170 @@ type GRPCConfig struct {
 }
 
 // DatabaseConfig contains database connection settings.
+// MaxOpenConns defaults to 1 because SQLite has a single writer; raising it lets
+// reads run in parallel under WAL while concurrent writes wait up to BusyTimeout.
 type DatabaseConfig struct {
-	Path string `mapstructure:"path"`
+	Path            string        `mapstructure:"path"`
+	MaxOpenConns    int           `mapstructure:"max_open_conns"`
+	MaxIdleConns    int           `mapstructure:"max_idle_conns"`
+	ConnMaxLifetime time.Duration `mapstructure:"conn_max_lifetime"`
+	BusyTimeout     time.Duration `mapstructure:"busy_timeout"`
 }
 
 // JWTConfig contains JWT authentication settings.
+// The secret is given by exactly one of Secret, SecretFile or SecretEnv; ResolveSecret
+// reads the indirect sources into Secret so the literal never has to appear in flags or files.
 type JWTConfig struct {
-	Secret     string        `mapstructure:"secret"`
+	Secret string `mapstructure:"secret"`
+	// SecretFile names a file holding the secret, e.g. a mounted Kubernetes secret; surrounding whitespace is trimmed
+	SecretFile string `mapstructure:"secret_file"`
+	// SecretEnv names the environment variable holding the secret
+	SecretEnv  string        `mapstructure:"secret_env"`
 	Expiration time.Duration `mapstructure:"expiration"`
 }
 
+// ResolveSecret fills Secret from SecretFile or SecretEnv when one of them is set.
+// Returns an error if more than one source is set or the named file or variable yields no secret.
+func (c *JWTConfig) ResolveSecret() error {
+	var sources []string
+	if c.Secret != "" {
+		sources = append(sources, "jwt.secret")
+	}
+	if c.SecretFile != "" {
+		sources = append(sources, "jwt.secret_file")
+	}
+	if c.SecretEnv != "" {
+		sources = append(sources, "jwt.secret_env")
+	}
+	if len(sources) > 1 {
+		return fmt.Errorf("jwt secret must come from exactly one source, got %s", strings.Join(sources, " and "))
+	}
+
+	switch {
+	case c.SecretFile != "":
+		data, err := os.ReadFile(c.SecretFile)
+		if err != nil {
+			return fmt.Errorf("read jwt.secret_file: %w", err)
+		}
+		c.Secret = strings.TrimSpace(string(data))
+		if c.Secret == "" {
+			return fmt.Errorf("jwt.secret_file %s is empty", c.SecretFile)
+		}
+	case c.SecretEnv != "":
+		c.Secret = strings.TrimSpace(os.Getenv(c.SecretEnv))
+		if c.Secret == "" {
+			return fmt.Errorf("jwt.secret_env names %s, which is not set", c.SecretEnv)
+		}
+	}
+	return nil
+}
+
+// AuthConfig contains token validation, authentication audit and brute-force protection settings.
+// RateLimit is the number of /login and /register requests allowed per client IP within
+// RateLimitWindow; zero disables the limit. LoginMaxAttempts failed logins for one email within
+// LoginLockoutWindow lock it for that window; zero disables the lockout. The Password* fields form
+// the strength policy enforced on registration. BcryptCost is the cost of new password hashes; zero uses bcrypt's default.
+type AuthConfig struct {
+	ClockSkewLeeway        time.Duration `mapstructure:"clock_skew_leeway"`
+	HashEmails             bool          `mapstructure:"hash_emails"`
+	RateLimit              int           `mapstructure:"rate_limit"`
+	RateLimitWindow        time.Duration `mapstructure:"rate_limit_window"`
+	LoginMaxAttempts       int           `mapstructure:"login_max_attempts"`
+	LoginLockoutWindow     time.Duration `mapstructure:"login_lockout_window"`
+	PasswordMinLength      int           `mapstructure:"password_min_length"`
+	PasswordRequireLetter  bool          `mapstructure:"password_require_letter"`
+	PasswordRequireDigit   bool          `mapstructure:"password_require_digit"`
+	PasswordRequireUpper   bool          `mapstructure:"password_require_upper"`
+	PasswordRequireSpecial bool          `mapstructure:"password_require_special"`
+	BcryptCost             int           `mapstructure:"bcrypt_cost"`
+}
+
+// TLSConfig enables HTTPS on the TCP listener with the given certificate and private key files.
+type TLSConfig struct {
+	Enabled  bool   `mapstructure:"enabled"`
+	CertFile string `mapstructure:"cert_file"`
+	KeyFile  string `mapstructure:"key_file"`
+}
+
+// CORSConfig lists the browser origins allowed to call the HTTP API.
+// CORS stays disabled while AllowedOrigins is empty.
+type CORSConfig struct {
+	AllowedOrigins   []string `mapstructure:"allowed_origins"`
+	AllowedMethods   []string `mapstructure:"allowed_methods"`
+	AllowCredentials bool     `mapstructure:"allow_credentials"`
+}
+
+// WebhookConfig sends task created and completed events to URL; an empty URL disables it.
+// Each delivery attempt is bounded by Timeout and a failed delivery is retried Retries times.
+type WebhookConfig struct {
+	URL     string        `mapstructure:"url"`
+	Timeout time.Duration `mapstructure:"timeout"`
+	Retries int           `mapstructure:"retries"`
+}
+
+// TaskConfig contains task processing settings.
+// MaxDescriptionLength limits new and updated task descriptions; zero keeps the default of 200.
+type TaskConfig struct {
+	Timezone             string `mapstructure:"timezone"`
+	MaxDescriptionLength int    `mapstructure:"max_description_length"`
+}
+
+// Location returns the configured task timezone, falling back to UTC when unset or invalid.
+func (tc TaskConfig) Location() *time.Location {
+	if tc.Timezone == "" {
+		return time.UTC
+	}
+	loc, err := time.LoadLocation(tc.Timezone)
+	if err != nil {
+		return time.UTC
+	}
+	return loc
+}
+
+// FeaturesConfig aggregates the on/off switches for optional functionality.
+type FeaturesConfig struct {
+	ServeUI            bool `mapstructure:"serve_ui"`
+	ExpandTemplates    bool `mapstructure:"expand_templates"`
+	AuthSuccessLogging bool `mapstructure:"auth_success_logging"`
+	StrictOwnership    bool `mapstructure:"strict_ownership"`
+	// RejectDuplicates refuses to create a task whose description the user already has
+	RejectDuplicates bool `mapstructure:"reject_duplicates"`
+}
+
+// Enabled returns the config keys of all active features in declaration order.
+func (fc FeaturesConfig) Enabled() []string {
+	var enabled []string
+	if fc.ServeUI {
+		enabled = append(enabled, "serve_ui")
+	}
+	if fc.ExpandTemplates {
+		enabled = append(enabled, "expand_templates")
+	}
+	if fc.AuthSuccessLogging {
+		enabled = append(enabled, "auth_success_logging")
+	}
+	if fc.StrictOwnership {
+		enabled = append(enabled, "strict_ownership")
+	}
+	if fc.RejectDuplicates {
+		enabled = append(enabled, "reject_duplicates")
+	}
+	return enabled
+}
+
+// formatFeatures renders a feature list for show-config output.
+func formatFeatures(features []string) string {
+	if len(features) == 0 {
+		return "none"
+	}
+	return strings.Join(features, ", ")
+}
+
+// Features returns the feature switches that gate optional code paths.
+func (c *Config) Features() FeaturesConfig {
+	return c.FeaturesConfig
+}
+
 // LoadConfig loads configuration from files, environment variables, and flags.
 // Returns the parsed config, viper instance, and any error encountered.
 func LoadConfig() (*Config, *viper.Viper, error) {
@@ -64,57 +245,127 @@ func LoadConfig() (*Config, *viper.Viper, error) {
 	v.SetDefault("server.shutdown_timeout", "30s")
 	v.SetDefault("server.read_timeout", "15s")
 	v.SetDefault("server.write_timeout", "15s")
-	v.SetDefault("server.idle_timeout", "2s")
+	v.SetDefault("server.idle_timeout", "60s")
+	v.SetDefault("server.unix_socket", "")
+	v.SetDefault("server.auto_port", false)
+	v.SetDefault("server.idempotency_window", "24h")
+	v.SetDefault("server.max_body_bytes", 1<<20)
+	v.SetDefault("server.default_page_size", 50)
+	v.SetDefault("server.max_page_size", 200)
 	v.SetDefault("database.path", "./data/tasks.db")
+	v.SetDefault("database.max_open_conns", 1)
+	v.SetDefault("database.max_idle_conns", 1)
+	v.SetDefault("database.conn_max_lifetime", "1h")
+	v.SetDefault("database.busy_timeout", "5s")
 	v.SetDefault("jwt.expiration", "24h")
+	v.SetDefault("jwt.secret_file", "")
+	v.SetDefault("jwt.secret_env", "")
+	v.SetDefault("auth.clock_skew_leeway", "30s")
+	v.SetDefault("auth.hash_emails", false)
+	v.SetDefault("auth.rate_limit", 10)
+	v.SetDefault("auth.rate_limit_window", "1m")
+	v.SetDefault("auth.login_max_attempts", 5)
+	v.SetDefault("auth.login_lockout_window", "15m")
+	v.SetDefault("auth.password_min_length", 8)
+	v.SetDefault("auth.password_require_letter", true)
+	v.SetDefault("auth.password_require_digit", true)
+	v.SetDefault("auth.password_require_upper", false)
+	v.SetDefault("auth.password_require_special", false)
+	v.SetDefault("auth.bcrypt_cost", bcrypt.DefaultCost)
 	v.SetDefault("logging.level", "info")
 	v.SetDefault("logging.format", "json")
 	v.SetDefault("logging.output", "stderr")
 	v.SetDefault("logging.add_source", false)
 	v.SetDefault("logging.service_name", "task-manager-api")
 	v.SetDefault("logging.environment", "production")
+	v.SetDefault("logging.enable_rotation", false)
+	v.SetDefault("logging.max_size", 100)
+	v.SetDefault("logging.max_age", 28)
+	v.SetDefault("logging.max_backups", 3)
+	v.SetDefault("logging.slow_request_threshold", "1s")
+	v.SetDefault("tasks.timezone", "UTC")
+	v.SetDefault("tasks.max_description_length", 200)
+	v.SetDefault("features.serve_ui", false)
+	v.SetDefault("features.expand_templates", false)
+	v.SetDefault("features.strict_ownership", false)
+	v.SetDefault("features.reject_duplicates", false)
+	v.SetDefault("features.auth_success_logging", true)
+	v.SetDefault("cors.allowed_origins", []string{})
+	v.SetDefault("cors.allowed_methods", []string{"GET", "POST", "PUT", "PATCH", "DELETE"})
+	v.SetDefault("cors.allow_credentials", false)
+	v.SetDefault("tls.enabled", false)
+	v.SetDefault("tls.cert_file", "")
+	v.SetDefault("tls.key_file", "")
+	v.SetDefault("webhook.url", "")
+	v.SetDefault("webhook.timeout", "5s")
+	v.SetDefault("webhook.retries", 3)
 
 	// Define and parse flags first (before reading config file)
 	pflag.String("config", "", "Path to config file")
 	pflag.Bool("show-config", false, "Display current configuration and exit")
+	pflag.Bool("migration-status", false, "List applied and pending database migrations and exit")
+	pflag.Int("migrate-down", 0, "Roll the database schema back to the given version and exit")
+	pflag.Bool("migrate-dry-run", false, "Print the SQL of pending database migrations without running it and exit")
 	pflag.Int("port", 8080, "Server port")
 	pflag.Int("grpc-port", 50051, "gRPC server port")
 	pflag.String("host", "0.0.0.0", "Server host")
 	pflag.String("shutdown-timeout", "30s", "Graceful shutdown timeout")
 	pflag.String("read-timeout", "15s", "Server ReadTimeout")
 	pflag.String("write-timeout", "15s", "Server WriteTimeout")
-	pflag.String("idle-timeout", "2s", "Server IdleTimeout")
+	pflag.String("idle-timeout", "60s", "Server IdleTimeout")
+	pflag.String("unix-socket", "", "Unix domain socket path to serve the API on")
+	pflag.Bool("auto-port", false, "Listen on the next free port when the configured one is in use (development)")
+	pflag.String("idempotency-window", "24h", "How long an Idempotency-Key on POST /tasks is remembered (0 disables)")
+	pflag.Int64("max-body-bytes", 1<<20, "Maximum size of a request body in bytes")
+	pflag.Int("default-page-size", 50, "Number of tasks GET /tasks returns when no limit is given")
+	pflag.Int("max-page-size", 200, "Largest limit GET /tasks honours; larger limits are clamped")
+	pflag.Bool("serve-ui", false, "Serve the embedded web UI at /")
 	pflag.String("db-path", "./data/tasks.db", "Database path")
+	pflag.Int("db-max-open-conns", 1, "Maximum open database connections (0 means unlimited)")
+	pflag.Int("db-max-idle-conns", 1, "Maximum idle database connections")
+	pflag.String("db-conn-max-lifetime", "1h", "Maximum lifetime of a database connection (0 means unlimited)")
+	pflag.String("db-busy-timeout", "5s", "How long a query waits for a locked database before failing")
 	pflag.String("jwt-expiration", "24h", "JWT expiration")
+	pflag.String("clock-skew-leeway", "30s", "Allowed clock skew when validating JWT exp/nbf/iat claims")
+	pflag.Bool("log-auth-success", true, "Log successful logins and registrations")
+	pflag.Bool("hash-auth-emails", false, "Log hashed instead of masked emails for successful authentications")
+	pflag.Int("auth-rate-limit", 10, "Login/register requests allowed per client IP within the rate limit window (0 disables)")
+	pflag.String("auth-rate-limit-window", "1m", "Window for the login/register rate limit")
+	pflag.Int("auth-login-max-attempts", 5, "Failed logins for one email within the lockout window that lock it (0 disables)")
+	pflag.String("auth-login-lockout-window", "15m", "Window failed logins are counted in, and how long a locked email stays locked")
+	pflag.Int("password-min-length", 8, "Minimum number of characters in a new password")
+	pflag.Bool("password-require-letter", true, "Require new passwords to contain a letter")
+	pflag.Bool("password-require-digit", true, "Require new passwords to contain a digit")
+	pflag.Bool("password-require-upper", false, "Require new passwords to contain an upper-case letter")
+	pflag.Bool("password-require-special", false, "Require new passwords to contain a special character")
+	pflag.Int("bcrypt-cost", bcrypt.DefaultCost, "bcrypt cost of new password hashes (4-31); each step doubles the hashing time")
 	pflag.String("jwt-secret", "", "JWT Secret")
+	pflag.String("jwt-secret-file", "", "File holding the JWT secret, instead of --jwt-secret")
+	pflag.String("jwt-secret-env", "", "Environment variable holding the JWT secret, instead of --jwt-secret")
 	pflag.String("log-level", "info", "Log level (debug, info, warn, error)")
 	pflag.String("log-format", "json", "Log format (json, text)")
 	pflag.String("log-output", "stderr", "Log output (stdout, stderr, or file path)")
 	pflag.Bool("log-add-source", false, "Include source file and line in logs")
 	pflag.String("log-service-name", "task-manager-api", "Service name for logs")
 	pflag.String("log-environment", "production", "Environment name (development, staging, production)")
+	pflag.Duration("slow-request-threshold", time.Second, "Log requests taking longer than this at WARN level (0 disables)")
+	pflag.Bool("expand-templates", false, "Expand {date}/{weekday} placeholders in new task descriptions")
+	pflag.Bool("strict-ownership", false, "Answer 403 instead of 404 for tasks that belong to another user")
+	pflag.Bool("reject-duplicates", false, "Answer 409 instead of creating a task whose description the user already has")
+	pflag.String("timezone", "UTC", "Timezone used for task date placeholders")
+	pflag.Int("max-description-length", 200, "Maximum number of characters in a task description")
+	pflag.StringSlice("cors-allowed-origins", nil, "Browser origins allowed to call the API, e.g. https://app.example.com (empty disables CORS)")
+	pflag.StringSlice("cors-allowed-methods", []string{"GET", "POST", "PUT", "PATCH", "DELETE"}, "HTTP methods allowed for cross-origin requests")
+	pflag.Bool("cors-allow-credentials", false, "Allow cross-origin requests to include credentials")
+	pflag.Bool("tls", false, "Serve HTTPS instead of HTTP")
+	pflag.String("tls-cert", "", "TLS certificate file (PEM)")
+	pflag.String("tls-key", "", "TLS private key file (PEM)")
+	pflag.String("webhook-url", "", "URL that receives task created and completed events (empty disables)")
 	pflag.Parse()
 
-	// Check if custom config file was specified
-	configFile := pflag.Lookup("config").Value.String()
-	if configFile != "" {
-		// Use the specified config file
-		v.SetConfigFile(configFile)
-	} else {
-		// Use default search paths
-		v.SetConfigName("config")
-		v.SetConfigType("yaml")
-		v.AddConfigPath(".")
-		v.AddConfigPath("/etc/taskmanager/")
-		v.AddConfigPath("$HOME/.taskmanager/")
-	}
-
-	// Read config file
-	if err := v.ReadInConfig(); err != nil {
-		if _, ok := err.(viper.ConfigFileNotFoundError); !ok {
-			return nil, nil, fmt.Errorf("failed to read config: %w", err)
-		}
-		// Config file not found is OK, continue with defaults and env vars
+	// Read the config file given with --config, or else the first one found in the search paths
+	if err := readConfigFile(v, pflag.Lookup("config").Value.String(), configSearchPaths()); err != nil {
+		return nil, nil, err
 	}
 
 	// Set up environment variables
@@ -122,7 +373,7 @@ func LoadConfig() (*Config, *viper.Viper, error) {
 	v.SetEnvPrefix("TASKMANAGER")
 	v.SetEnvKeyReplacer(strings.NewReplacer(".", "_"))
 
-	// Bind flags to config keys (except --config and --show-config which are handled separately)
+	// Bind flags to config keys (except --config, --show-config, --migration-status, --migrate-down and --migrate-dry-run which are handled separately)
 	v.BindPFlag("server.port", pflag.Lookup("port"))
 	v.BindPFlag("server.host", pflag.Lookup("host"))
 	v.BindPFlag("grpc.port", pflag.Lookup("grpc-port"))
@@ -130,15 +381,54 @@ func LoadConfig() (*Config, *viper.Viper, error) {
 	v.BindPFlag("server.read_timeout", pflag.Lookup("read-timeout"))
 	v.BindPFlag("server.write_timeout", pflag.Lookup("write-timeout"))
 	v.BindPFlag("server.idle_timeout", pflag.Lookup("idle-timeout"))
+	v.BindPFlag("server.unix_socket", pflag.Lookup("unix-socket"))
+	v.BindPFlag("server.auto_port", pflag.Lookup("auto-port"))
+	v.BindPFlag("server.idempotency_window", pflag.Lookup("idempotency-window"))
+	v.BindPFlag("server.max_body_bytes", pflag.Lookup("max-body-bytes"))
+	v.BindPFlag("server.default_page_size", pflag.Lookup("default-page-size"))
+	v.BindPFlag("server.max_page_size", pflag.Lookup("max-page-size"))
 	v.BindPFlag("database.path", pflag.Lookup("db-path"))
+	v.BindPFlag("database.max_open_conns", pflag.Lookup("db-max-open-conns"))
+	v.BindPFlag("database.max_idle_conns", pflag.Lookup("db-max-idle-conns"))
+	v.BindPFlag("database.conn_max_lifetime", pflag.Lookup("db-conn-max-lifetime"))
+	v.BindPFlag("database.busy_timeout", pflag.Lookup("db-busy-timeout"))
 	v.BindPFlag("jwt.expiration", pflag.Lookup("jwt-expiration"))
 	v.BindPFlag("jwt.secret", pflag.Lookup("jwt-secret"))
+	v.BindPFlag("jwt.secret_file", pflag.Lookup("jwt-secret-file"))
+	v.BindPFlag("jwt.secret_env", pflag.Lookup("jwt-secret-env"))
+	v.BindPFlag("auth.clock_skew_leeway", pflag.Lookup("clock-skew-leeway"))
+	v.BindPFlag("auth.hash_emails", pflag.Lookup("hash-auth-emails"))
+	v.BindPFlag("auth.rate_limit", pflag.Lookup("auth-rate-limit"))
+	v.BindPFlag("auth.rate_limit_window", pflag.Lookup("auth-rate-limit-window"))
+	v.BindPFlag("auth.login_max_attempts", pflag.Lookup("auth-login-max-attempts"))
+	v.BindPFlag("auth.login_lockout_window", pflag.Lookup("auth-login-lockout-window"))
+	v.BindPFlag("auth.password_min_length", pflag.Lookup("password-min-length"))
+	v.BindPFlag("auth.password_require_letter", pflag.Lookup("password-require-letter"))
+	v.BindPFlag("auth.password_require_digit", pflag.Lookup("password-require-digit"))
+	v.BindPFlag("auth.password_require_upper", pflag.Lookup("password-require-upper"))
+	v.BindPFlag("auth.password_require_special", pflag.Lookup("password-require-special"))
+	v.BindPFlag("auth.bcrypt_cost", pflag.Lookup("bcrypt-cost"))
 	v.BindPFlag("logging.level", pflag.Lookup("log-level"))
 	v.BindPFlag("logging.format", pflag.Lookup("log-format"))
 	v.BindPFlag("logging.output", pflag.Lookup("log-output"))
 	v.BindPFlag("logging.add_source", pflag.Lookup("log-add-source"))
 	v.BindPFlag("logging.service_name", pflag.Lookup("log-service-name"))
 	v.BindPFlag("logging.environment", pflag.Lookup("log-environment"))
+	v.BindPFlag("logging.slow_request_threshold", pflag.Lookup("slow-request-threshold"))
+	v.BindPFlag("tasks.timezone", pflag.Lookup("timezone"))
+	v.BindPFlag("tasks.max_description_length", pflag.Lookup("max-description-length"))
+	v.BindPFlag("features.serve_ui", pflag.Lookup("serve-ui"))
+	v.BindPFlag("features.expand_templates", pflag.Lookup("expand-templates"))
+	v.BindPFlag("features.strict_ownership", pflag.Lookup("strict-ownership"))
+	v.BindPFlag("features.reject_duplicates", pflag.Lookup("reject-duplicates"))
+	v.BindPFlag("features.auth_success_logging", pflag.Lookup("log-auth-success"))
+	v.BindPFlag("cors.allowed_origins", pflag.Lookup("cors-allowed-origins"))
+	v.BindPFlag("cors.allowed_methods", pflag.Lookup("cors-allowed-methods"))
+	v.BindPFlag("cors.allow_credentials", pflag.Lookup("cors-allow-credentials"))
+	v.BindPFlag("tls.enabled", pflag.Lookup("tls"))
+	v.BindPFlag("tls.cert_file", pflag.Lookup("tls-cert"))
+	v.BindPFlag("tls.key_file", pflag.Lookup("tls-key"))
+	v.BindPFlag("webhook.url", pflag.Lookup("webhook-url"))
 
 	// Unmarshal config into struct
 	var config Config
@@ -146,6 +436,10 @@ func LoadConfig() (*Config, *viper.Viper, error) {
 		return nil, nil, fmt.Errorf("unmarshal config: %w", err)
 	}
 
+	if err := config.JWTConfig.ResolveSecret(); err != nil {
+		return nil, nil, fmt.Errorf("config validation failed: %w", err)
+	}
+
 	if err := config.Validate(); err != nil {
 		return nil, nil, fmt.Errorf("config validation failed: %w", err)
 	}
@@ -153,6 +447,45 @@ func LoadConfig() (*Config, *viper.Viper, error) {
 	return &config, v, nil
 }
 
+// configSearchPaths lists the directories searched for a config file when --config is not given,
+// most specific first: the working directory, $XDG_CONFIG_HOME/taskmanager (~/.config/taskmanager
+// when unset), ~/.taskmanager and /etc/taskmanager.
+func configSearchPaths() []string {
+	paths := []string{"."}
+	home, homeErr := os.UserHomeDir()
+	if xdg := os.Getenv("XDG_CONFIG_HOME"); xdg != "" {
+		paths = append(paths, filepath.Join(xdg, "taskmanager"))
+	} else if homeErr == nil {
+		paths = append(paths, filepath.Join(home, ".config", "taskmanager"))
+	}
+	if homeErr == nil {
+		paths = append(paths, filepath.Join(home, ".taskmanager"))
+	}
+	return append(paths, "/etc/taskmanager")
+}
+
+// readConfigFile reads configFile into v, or when it is empty the first file named config in searchPaths.
+// The format follows the file extension, e.g. .yaml, .json or .toml. Finding no file in the search paths
+// is not an error, as defaults and environment variables still apply; a named file that is missing
+// or malformed is, like a malformed file that was found.
+func readConfigFile(v *viper.Viper, configFile string, searchPaths []string) error {
+	if configFile != "" {
+		v.SetConfigFile(configFile)
+	} else {
+		v.SetConfigName("config")
+		for _, path := range searchPaths {
+			v.AddConfigPath(path)
+		}
+	}
+
+	if err := v.ReadInConfig(); err != nil {
+		if _, ok := err.(viper.ConfigFileNotFoundError); !ok {
+			return fmt.Errorf("failed to read config: %w", err)
+		}
+	}
+	return nil
+}
+
 // Validate checks all configuration values for correctness.
 // Returns a combined error if any validation fails.
 func (config *Config) Validate() error {
@@ -165,6 +498,39 @@ func (config *Config) Validate() error {
 		errs = append(errs, fmt.Errorf("server.shutdown_timeout must be positive, got %v", config.ServerConfig.ShutdownTimeout))
 	}
 
+	for _, timeout := range []struct {
+		key   string
+		value time.Duration
+	}{
+		{"server.read_timeout", config.ServerConfig.ReadTimeout},
+		{"server.write_timeout", config.ServerConfig.WriteTimeout},
+		{"server.idle_timeout", config.ServerConfig.IdleTimeout},
+	} {
+		if timeout.value < 0 {
+			errs = append(errs, fmt.Errorf("%s must not be negative, got %v", timeout.key, timeout.value))
+		}
+	}
+
+	if config.ServerConfig.IdempotencyWindow < 0 {
+		errs = append(errs, fmt.Errorf("server.idempotency_window must not be negative, got %v", config.ServerConfig.IdempotencyWindow))
+	}
+
+	if config.ServerConfig.MaxBodyBytes < 0 {
+		errs = append(errs, fmt.Errorf("server.max_body_bytes must not be negative, got %d", config.ServerConfig.MaxBodyBytes))
+	}
+
+	if config.ServerConfig.DefaultPageSize < 0 {
+		errs = append(errs, fmt.Errorf("server.default_page_size must not be negative, got %d", config.ServerConfig.DefaultPageSize))
+	}
+
+	if config.ServerConfig.MaxPageSize < 0 {
+		errs = append(errs, fmt.Errorf("server.max_page_size must not be negative, got %d", config.ServerConfig.MaxPageSize))
+	}
+
+	if config.ServerConfig.MaxPageSize > 0 && config.ServerConfig.DefaultPageSize > config.ServerConfig.MaxPageSize {
+		errs = append(errs, fmt.Errorf("server.default_page_size must not exceed server.max_page_size (%d), got %d", config.ServerConfig.MaxPageSize, config.ServerConfig.DefaultPageSize))
+	}
+
 	if len(config.DatabaseConfig.Path) == 0 {
 		errs = append(errs, fmt.Errorf("database path required"))
 	}
@@ -175,8 +541,24 @@ func (config *Config) Validate() error {
 		errs = append(errs, err)
 	}
 
+	if config.DatabaseConfig.MaxOpenConns < 0 {
+		errs = append(errs, fmt.Errorf("database.max_open_conns must not be negative, got %d", config.DatabaseConfig.MaxOpenConns))
+	}
+
+	if config.DatabaseConfig.MaxIdleConns < 0 {
+		errs = append(errs, fmt.Errorf("database.max_idle_conns must not be negative, got %d", config.DatabaseConfig.MaxIdleConns))
+	}
+
+	if config.DatabaseConfig.ConnMaxLifetime < 0 {
+		errs = append(errs, fmt.Errorf("database.conn_max_lifetime must not be negative, got %v", config.DatabaseConfig.ConnMaxLifetime))
+	}
+
+	if config.DatabaseConfig.BusyTimeout < 0 {
+		errs = append(errs, fmt.Errorf("database.busy_timeout must not be negative, got %v", config.DatabaseConfig.BusyTimeout))
+	}
+
 	if len(config.JWTConfig.Secret) == 0 {
-		errs = append(errs, fmt.Errorf("jwt secret required"))
+		errs = append(errs, fmt.Errorf("jwt secret required (jwt.secret, jwt.secret_file or jwt.secret_env)"))
 	} else if len(config.JWTConfig.Secret) < MinJWTSecretLength {
 		errs = append(errs, fmt.Errorf("secret must be at least 32 symbols, got %d", len(config.JWTConfig.Secret)))
 	}
@@ -185,13 +567,95 @@ func (config *Config) Validate() error {
 		errs = append(errs, fmt.Errorf("expiration must be positive, got %v", config.JWTConfig.Expiration))
 	}
 
+	if config.AuthConfig.ClockSkewLeeway < 0 || config.AuthConfig.ClockSkewLeeway > MaxClockSkewLeeway {
+		errs = append(errs, fmt.Errorf("auth.clock_skew_leeway must be between 0 and %v, got %v", MaxClockSkewLeeway, config.AuthConfig.ClockSkewLeeway))
+	}
+
+	if config.AuthConfig.RateLimit < 0 {
+		errs = append(errs, fmt.Errorf("auth.rate_limit must not be negative, got %d", config.AuthConfig.RateLimit))
+	} else if config.AuthConfig.RateLimit > 0 && config.AuthConfig.RateLimitWindow <= 0 {
+		errs = append(errs, fmt.Errorf("auth.rate_limit_window must be positive, got %v", config.AuthConfig.RateLimitWindow))
+	}
+
+	if config.AuthConfig.LoginMaxAttempts < 0 {
+		errs = append(errs, fmt.Errorf("auth.login_max_attempts must not be negative, got %d", config.AuthConfig.LoginMaxAttempts))
+	} else if config.AuthConfig.LoginMaxAttempts > 0 && config.AuthConfig.LoginLockoutWindow <= 0 {
+		errs = append(errs, fmt.Errorf("auth.login_lockout_window must be positive, got %v", config.AuthConfig.LoginLockoutWindow))
+	}
+
+	if config.AuthConfig.BcryptCost != 0 && (config.AuthConfig.BcryptCost < bcrypt.MinCost || config.AuthConfig.BcryptCost > bcrypt.MaxCost) {
+		errs = append(errs, fmt.Errorf("auth.bcrypt_cost must be between %d and %d, got %d", bcrypt.MinCost, bcrypt.MaxCost, config.AuthConfig.BcryptCost))
+	}
+
+	if config.AuthConfig.PasswordMinLength < 0 || config.AuthConfig.PasswordMinLength > MaxPasswordMinLength {
+		errs = append(errs, fmt.Errorf("auth.password_min_length must be between 0 and %d, got %d", MaxPasswordMinLength, config.AuthConfig.PasswordMinLength))
+	}
+
 	if err := config.LogConfig.Validate(); err != nil {
 		errs = append(errs, fmt.Errorf("validate log config failed: %w", err))
 	}
 
+	if config.TaskConfig.Timezone != "" {
+		if _, err := time.LoadLocation(config.TaskConfig.Timezone); err != nil {
+			errs = append(errs, fmt.Errorf("tasks.timezone '%s' is invalid: %w", config.TaskConfig.Timezone, err))
+		}
+	}
+
+	if config.TaskConfig.MaxDescriptionLength < 0 {
+		errs = append(errs, fmt.Errorf("tasks.max_description_length must not be negative, got %d", config.TaskConfig.MaxDescriptionLength))
+	}
+
+	for _, origin := range config.CORSConfig.AllowedOrigins {
+		if origin == "*" || !strings.Contains(origin, "://") {
+			errs = append(errs, fmt.Errorf("cors.allowed_origins must list explicit origins like https://app.example.com, got %q", origin))
+		}
+	}
+
+	if config.TLSConfig.Enabled {
+		if err := validateReadableFile(config.TLSConfig.CertFile); err != nil {
+			errs = append(errs, fmt.Errorf("tls.cert_file: %w", err))
+		}
+		if err := validateReadableFile(config.TLSConfig.KeyFile); err != nil {
+			errs = append(errs, fmt.Errorf("tls.key_file: %w", err))
+		}
+	}
+
+	if config.WebhookConfig.URL != "" {
+		if u, err := url.Parse(config.WebhookConfig.URL); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			errs = append(errs, fmt.Errorf("webhook.url must be an http or https URL, got %q", config.WebhookConfig.URL))
+		}
+		if config.WebhookConfig.Timeout <= 0 {
+			errs = append(errs, fmt.Errorf("webhook.timeout must be positive, got %v", config.WebhookConfig.Timeout))
+		}
+		if config.WebhookConfig.Retries < 0 {
+			errs = append(errs, fmt.Errorf("webhook.retries must not be negative, got %d", config.WebhookConfig.Retries))
+		}
+	}
+
 	return errors.Join(errs...)
 }
 
+// validateReadableFile ensures path names a regular file the server can open.
+func validateReadableFile(path string) error {
+	if path == "" {
+		return errors.New("required when TLS is enabled")
+	}
+	file, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("opening %s failed: %w", path, err)
+	}
+	defer file.Close()
+
+	info, err := file.Stat()
+	if err != nil {
+		return fmt.Errorf("reading %s failed: %w", path, err)
+	}
+	if info.IsDir() {
+		return fmt.Errorf("%s is a directory", path)
+	}
+	return nil
+}
+
 // validateDatabasePath ensures the database directory exists and is writable.
 func validateDatabasePath(path string) error {
 	dir := filepath.Dir(path)
@@ -229,21 +693,60 @@ func maskSensitive(scrt string) string {
 // getSource determines where a configuration value came from (flag, env, config file, or default).
 func getSource(v *viper.Viper, key string) string {
 	flagMap := map[string]string{
-		"server.port":             "port",
-		"server.host":             "host",
-		"server.shutdown_timeout": "shutdown-timeout",
-		"server.read_timeout":     "read-timeout",
-		"server.write_timeout":    "write-timeout",
-		"server.idle_timeout":     "idle-timeout",
-		"database.path":           "db-path",
-		"jwt.secret":              "jwt-secret",
-		"jwt.expiration":          "jwt-expiration",
-		"logging.level":           "log-level",
-		"logging.format":          "log-format",
-		"logging.output":          "log-output",
-		"logging.add_source":      "log-add-source",
-		"logging.service_name":    "log-service-name",
-		"logging.environment":     "log-environment",
+		"server.port":                    "port",
+		"server.host":                    "host",
+		"server.shutdown_timeout":        "shutdown-timeout",
+		"server.read_timeout":            "read-timeout",
+		"server.write_timeout":           "write-timeout",
+		"server.idle_timeout":            "idle-timeout",
+		"server.unix_socket":             "unix-socket",
+		"server.auto_port":               "auto-port",
+		"server.idempotency_window":      "idempotency-window",
+		"server.max_body_bytes":          "max-body-bytes",
+		"server.default_page_size":       "default-page-size",
+		"server.max_page_size":           "max-page-size",
+		"database.path":                  "db-path",
+		"database.max_open_conns":        "db-max-open-conns",
+		"database.max_idle_conns":        "db-max-idle-conns",
+		"database.conn_max_lifetime":     "db-conn-max-lifetime",
+		"database.busy_timeout":          "db-busy-timeout",
+		"jwt.secret":                     "jwt-secret",
+		"jwt.secret_file":                "jwt-secret-file",
+		"jwt.secret_env":                 "jwt-secret-env",
+		"jwt.expiration":                 "jwt-expiration",
+		"auth.clock_skew_leeway":         "clock-skew-leeway",
+		"auth.hash_emails":               "hash-auth-emails",
+		"auth.rate_limit":                "auth-rate-limit",
+		"auth.rate_limit_window":         "auth-rate-limit-window",
+		"auth.login_max_attempts":        "auth-login-max-attempts",
+		"auth.login_lockout_window":      "auth-login-lockout-window",
+		"auth.password_min_length":       "password-min-length",
+		"auth.password_require_letter":   "password-require-letter",
+		"auth.password_require_digit":    "password-require-digit",
+		"auth.password_require_upper":    "password-require-upper",
+		"auth.password_require_special":  "password-require-special",
+		"auth.bcrypt_cost":               "bcrypt-cost",
+		"logging.level":                  "log-level",
+		"logging.format":                 "log-format",
+		"logging.output":                 "log-output",
+		"logging.add_source":             "log-add-source",
+		"logging.service_name":           "log-service-name",
+		"logging.environment":            "log-environment",
+		"logging.slow_request_threshold": "slow-request-threshold",
+		"tasks.timezone":                 "timezone",
+		"tasks.max_description_length":   "max-description-length",
+		"features.serve_ui":              "serve-ui",
+		"features.expand_templates":      "expand-templates",
+		"features.strict_ownership":      "strict-ownership",
+		"features.reject_duplicates":     "reject-duplicates",
+		"features.auth_success_logging":  "log-auth-success",
+		"cors.allowed_origins":           "cors-allowed-origins",
+		"cors.allowed_methods":           "cors-allowed-methods",
+		"cors.allow_credentials":         "cors-allow-credentials",
+		"tls.enabled":                    "tls",
+		"tls.cert_file":                  "tls-cert",
+		"tls.key_file":                   "tls-key",
+		"webhook.url":                    "webhook-url",
 	}
 
 	if flagName, exists := flagMap[key]; exists {
@@ -275,15 +778,57 @@ func ShowConfig(cfg *Config, v *viper.Viper) {
 	fmt.Printf("server.read_timeout: %s (%s)\n", cfg.ServerConfig.ReadTimeout, getSource(v, "server.read_timeout"))
 	fmt.Printf("server.write_timeout: %s (%s)\n", cfg.ServerConfig.WriteTimeout, getSource(v, "server.write_timeout"))
 	fmt.Printf("server.idle_timeout: %s (%s)\n", cfg.ServerConfig.IdleTimeout, getSource(v, "server.idle_timeout"))
+	fmt.Printf("server.unix_socket: %s (%s)\n", cfg.ServerConfig.UnixSocket, getSource(v, "server.unix_socket"))
+	fmt.Printf("server.auto_port: %t (%s)\n", cfg.ServerConfig.AutoPort, getSource(v, "server.auto_port"))
+	fmt.Printf("server.idempotency_window: %s (%s)\n", cfg.ServerConfig.IdempotencyWindow, getSource(v, "server.idempotency_window"))
+	fmt.Printf("server.max_body_bytes: %d (%s)\n", cfg.ServerConfig.MaxBodyBytes, getSource(v, "server.max_body_bytes"))
+	fmt.Printf("server.default_page_size: %d (%s)\n", cfg.ServerConfig.DefaultPageSize, getSource(v, "server.default_page_size"))
+	fmt.Printf("server.max_page_size: %d (%s)\n", cfg.ServerConfig.MaxPageSize, getSource(v, "server.max_page_size"))
 	fmt.Printf("database.path: %s (%s)\n", cfg.DatabaseConfig.Path, getSource(v, "database.path"))
+	fmt.Printf("database.max_open_conns: %d (%s)\n", cfg.DatabaseConfig.MaxOpenConns, getSource(v, "database.max_open_conns"))
+	fmt.Printf("database.max_idle_conns: %d (%s)\n", cfg.DatabaseConfig.MaxIdleConns, getSource(v, "database.max_idle_conns"))
+	fmt.Printf("database.conn_max_lifetime: %s (%s)\n", cfg.DatabaseConfig.ConnMaxLifetime, getSource(v, "database.conn_max_lifetime"))
+	fmt.Printf("database.busy_timeout: %s (%s)\n", cfg.DatabaseConfig.BusyTimeout, getSource(v, "database.busy_timeout"))
 	fmt.Printf("jwt.secret: %s (%s)\n", maskSensitive(cfg.JWTConfig.Secret), getSource(v, "jwt.secret"))
+	fmt.Printf("jwt.secret_file: %s (%s)\n", cfg.JWTConfig.SecretFile, getSource(v, "jwt.secret_file"))
+	fmt.Printf("jwt.secret_env: %s (%s)\n", cfg.JWTConfig.SecretEnv, getSource(v, "jwt.secret_env"))
 	fmt.Printf("jwt.expiration: %s (%s)\n", cfg.JWTConfig.Expiration, getSource(v, "jwt.expiration"))
+	fmt.Printf("auth.clock_skew_leeway: %s (%s)\n", cfg.AuthConfig.ClockSkewLeeway, getSource(v, "auth.clock_skew_leeway"))
+	fmt.Printf("auth.hash_emails: %v (%s)\n", cfg.AuthConfig.HashEmails, getSource(v, "auth.hash_emails"))
+	fmt.Printf("auth.rate_limit: %d (%s)\n", cfg.AuthConfig.RateLimit, getSource(v, "auth.rate_limit"))
+	fmt.Printf("auth.rate_limit_window: %s (%s)\n", cfg.AuthConfig.RateLimitWindow, getSource(v, "auth.rate_limit_window"))
+	fmt.Printf("auth.login_max_attempts: %d (%s)\n", cfg.AuthConfig.LoginMaxAttempts, getSource(v, "auth.login_max_attempts"))
+	fmt.Printf("auth.login_lockout_window: %s (%s)\n", cfg.AuthConfig.LoginLockoutWindow, getSource(v, "auth.login_lockout_window"))
+	fmt.Printf("auth.password_min_length: %d (%s)\n", cfg.AuthConfig.PasswordMinLength, getSource(v, "auth.password_min_length"))
+	fmt.Printf("auth.password_require_letter: %v (%s)\n", cfg.AuthConfig.PasswordRequireLetter, getSource(v, "auth.password_require_letter"))
+	fmt.Printf("auth.password_require_digit: %v (%s)\n", cfg.AuthConfig.PasswordRequireDigit, getSource(v, "auth.password_require_digit"))
+	fmt.Printf("auth.password_require_upper: %v (%s)\n", cfg.AuthConfig.PasswordRequireUpper, getSource(v, "auth.password_require_upper"))
+	fmt.Printf("auth.password_require_special: %v (%s)\n", cfg.AuthConfig.PasswordRequireSpecial, getSource(v, "auth.password_require_special"))
+	fmt.Printf("auth.bcrypt_cost: %d (%s)\n", cfg.AuthConfig.BcryptCost, getSource(v, "auth.bcrypt_cost"))
 	fmt.Printf("logging.level: %s (%s)\n", cfg.LogConfig.Level, getSource(v, "logging.level"))
 	fmt.Printf("logging.format: %s (%s)\n", cfg.LogConfig.Format, getSource(v, "logging.format"))
 	fmt.Printf("logging.output: %s (%s)\n", cfg.LogConfig.Output, getSource(v, "logging.output"))
 	fmt.Printf("logging.add_source: %v (%s)\n", cfg.LogConfig.AddSource, getSource(v, "logging.add_source"))
 	fmt.Printf("logging.service_name: %s (%s)\n", cfg.LogConfig.ServiceName, getSource(v, "logging.service_name"))
 	fmt.Printf("logging.environment: %s (%s)\n", cfg.LogConfig.Environment, getSource(v, "logging.environment"))
+	fmt.Printf("logging.slow_request_threshold: %s (%s)\n", cfg.LogConfig.SlowRequestThreshold, getSource(v, "logging.slow_request_threshold"))
+	fmt.Printf("tasks.timezone: %s (%s)\n", cfg.TaskConfig.Timezone, getSource(v, "tasks.timezone"))
+	fmt.Printf("tasks.max_description_length: %d (%s)\n", cfg.TaskConfig.MaxDescriptionLength, getSource(v, "tasks.max_description_length"))
+	fmt.Printf("features.serve_ui: %v (%s)\n", cfg.FeaturesConfig.ServeUI, getSource(v, "features.serve_ui"))
+	fmt.Printf("features.expand_templates: %v (%s)\n", cfg.FeaturesConfig.ExpandTemplates, getSource(v, "features.expand_templates"))
+	fmt.Printf("features.auth_success_logging: %v (%s)\n", cfg.FeaturesConfig.AuthSuccessLogging, getSource(v, "features.auth_success_logging"))
+	fmt.Printf("features.strict_ownership: %v (%s)\n", cfg.FeaturesConfig.StrictOwnership, getSource(v, "features.strict_ownership"))
+	fmt.Printf("features.reject_duplicates: %v (%s)\n", cfg.FeaturesConfig.RejectDuplicates, getSource(v, "features.reject_duplicates"))
+	fmt.Printf("cors.allowed_origins: %v (%s)\n", cfg.CORSConfig.AllowedOrigins, getSource(v, "cors.allowed_origins"))
+	fmt.Printf("cors.allowed_methods: %v (%s)\n", cfg.CORSConfig.AllowedMethods, getSource(v, "cors.allowed_methods"))
+	fmt.Printf("cors.allow_credentials: %v (%s)\n", cfg.CORSConfig.AllowCredentials, getSource(v, "cors.allow_credentials"))
+	fmt.Printf("tls.enabled: %v (%s)\n", cfg.TLSConfig.Enabled, getSource(v, "tls.enabled"))
+	fmt.Printf("tls.cert_file: %s (%s)\n", cfg.TLSConfig.CertFile, getSource(v, "tls.cert_file"))
+	fmt.Printf("tls.key_file: %s (%s)\n", cfg.TLSConfig.KeyFile, getSource(v, "tls.key_file"))
+	fmt.Printf("webhook.url: %s (%s)\n", cfg.WebhookConfig.URL, getSource(v, "webhook.url"))
+	fmt.Printf("webhook.timeout: %s (%s)\n", cfg.WebhookConfig.Timeout, getSource(v, "webhook.timeout"))
+	fmt.Printf("webhook.retries: %d (%s)\n", cfg.WebhookConfig.Retries, getSource(v, "webhook.retries"))
+	fmt.Printf("Active features: %s\n", formatFeatures(cfg.Features().Enabled()))
 	fmt.Println()
 	fmt.Println("Configuration Precedence: flags > env > config file > defaults")
 }
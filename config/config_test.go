@@ -3,6 +3,7 @@ package config
 import (
 	"myproject/logger"
 	"os"
+	"path/filepath"
 	"strings"
 	"testing"
 	"time"
@@ -752,6 +753,349 @@ func TestValidation(t *testing.T) {
 			expectedErr: true,
 			errContains: "server.port must be between 1 and 65535",
 		},
+		{
+			name: "Invalid task timezone",
+			config: Config{
+				ServerConfig: ServerConfig{
+					Port:            8080,
+					Host:            "0.0.0.0",
+					ShutdownTimeout: 30 * time.Second,
+				},
+				DatabaseConfig: DatabaseConfig{
+					Path: "/tmp/test-timezone/tasks.db",
+				},
+				JWTConfig: JWTConfig{
+					Secret:     "this-is-a-valid-secret-key-with-32-characters",
+					Expiration: 24 * time.Hour,
+				},
+				LogConfig: logger.Config{
+					Level:       "info",
+					Format:      "json",
+					Output:      "stdout",
+					ServiceName: "task-manager-api",
+					Environment: "production",
+				},
+				TaskConfig: TaskConfig{
+					Timezone: "Mars/Olympus_Mons",
+				},
+			},
+			expectedErr: true,
+			errContains: "tasks.timezone",
+		},
+		{
+			name: "Negative clock skew leeway",
+			config: Config{
+				ServerConfig: ServerConfig{
+					Port:            8080,
+					Host:            "0.0.0.0",
+					ShutdownTimeout: 30 * time.Second,
+				},
+				DatabaseConfig: DatabaseConfig{
+					Path: "/tmp/test-leeway/tasks.db",
+				},
+				JWTConfig: JWTConfig{
+					Secret:     "this-is-a-valid-secret-key-with-32-characters",
+					Expiration: 24 * time.Hour,
+				},
+				AuthConfig: AuthConfig{
+					ClockSkewLeeway: -1 * time.Second,
+				},
+				LogConfig: logger.Config{
+					Level:       "info",
+					Format:      "json",
+					Output:      "stdout",
+					ServiceName: "task-manager-api",
+					Environment: "production",
+				},
+			},
+			expectedErr: true,
+			errContains: "auth.clock_skew_leeway",
+		},
+		{
+			name: "Clock skew leeway above maximum",
+			config: Config{
+				ServerConfig: ServerConfig{
+					Port:            8080,
+					Host:            "0.0.0.0",
+					ShutdownTimeout: 30 * time.Second,
+				},
+				DatabaseConfig: DatabaseConfig{
+					Path: "/tmp/test-leeway/tasks.db",
+				},
+				JWTConfig: JWTConfig{
+					Secret:     "this-is-a-valid-secret-key-with-32-characters",
+					Expiration: 24 * time.Hour,
+				},
+				AuthConfig: AuthConfig{
+					ClockSkewLeeway: time.Hour,
+				},
+				LogConfig: logger.Config{
+					Level:       "info",
+					Format:      "json",
+					Output:      "stdout",
+					ServiceName: "task-manager-api",
+					Environment: "production",
+				},
+			},
+			expectedErr: true,
+			errContains: "auth.clock_skew_leeway",
+		},
+		{
+			name: "Negative auth rate limit",
+			config: Config{
+				ServerConfig: ServerConfig{
+					Port:            8080,
+					Host:            "0.0.0.0",
+					ShutdownTimeout: 30 * time.Second,
+				},
+				DatabaseConfig: DatabaseConfig{
+					Path: "/tmp/test-rate-limit/tasks.db",
+				},
+				JWTConfig: JWTConfig{
+					Secret:     "this-is-a-valid-secret-key-with-32-characters",
+					Expiration: 24 * time.Hour,
+				},
+				AuthConfig: AuthConfig{
+					RateLimit:       -1,
+					RateLimitWindow: time.Minute,
+				},
+				LogConfig: logger.Config{
+					Level:       "info",
+					Format:      "json",
+					Output:      "stdout",
+					ServiceName: "task-manager-api",
+					Environment: "production",
+				},
+			},
+			expectedErr: true,
+			errContains: "auth.rate_limit",
+		},
+		{
+			name: "Auth rate limit without window",
+			config: Config{
+				ServerConfig: ServerConfig{
+					Port:            8080,
+					Host:            "0.0.0.0",
+					ShutdownTimeout: 30 * time.Second,
+				},
+				DatabaseConfig: DatabaseConfig{
+					Path: "/tmp/test-rate-limit/tasks.db",
+				},
+				JWTConfig: JWTConfig{
+					Secret:     "this-is-a-valid-secret-key-with-32-characters",
+					Expiration: 24 * time.Hour,
+				},
+				AuthConfig: AuthConfig{
+					RateLimit: 10,
+				},
+				LogConfig: logger.Config{
+					Level:       "info",
+					Format:      "json",
+					Output:      "stdout",
+					ServiceName: "task-manager-api",
+					Environment: "production",
+				},
+			},
+			expectedErr: true,
+			errContains: "auth.rate_limit_window",
+		},
+		{
+			name: "Password min length above bcrypt limit",
+			config: Config{
+				ServerConfig: ServerConfig{
+					Port:            8080,
+					Host:            "0.0.0.0",
+					ShutdownTimeout: 30 * time.Second,
+				},
+				DatabaseConfig: DatabaseConfig{
+					Path: "/tmp/test-password-policy/tasks.db",
+				},
+				JWTConfig: JWTConfig{
+					Secret:     "this-is-a-valid-secret-key-with-32-characters",
+					Expiration: 24 * time.Hour,
+				},
+				AuthConfig: AuthConfig{
+					PasswordMinLength: 73,
+				},
+				LogConfig: logger.Config{
+					Level:       "info",
+					Format:      "json",
+					Output:      "stdout",
+					ServiceName: "task-manager-api",
+					Environment: "production",
+				},
+			},
+			expectedErr: true,
+			errContains: "auth.password_min_length",
+		},
+		{
+			name: "Negative database busy timeout",
+			config: Config{
+				ServerConfig: ServerConfig{
+					Port:            8080,
+					Host:            "0.0.0.0",
+					ShutdownTimeout: 30 * time.Second,
+				},
+				DatabaseConfig: DatabaseConfig{
+					Path:         "/tmp/test-busy-timeout/tasks.db",
+					MaxOpenConns: 1,
+					BusyTimeout:  -time.Second,
+				},
+				JWTConfig: JWTConfig{
+					Secret:     "this-is-a-valid-secret-key-with-32-characters",
+					Expiration: 24 * time.Hour,
+				},
+				LogConfig: logger.Config{
+					Level:       "info",
+					Format:      "json",
+					Output:      "stdout",
+					ServiceName: "task-manager-api",
+					Environment: "production",
+				},
+			},
+			expectedErr: true,
+			errContains: "database.busy_timeout",
+		},
+		{
+			name: "Negative idle timeout",
+			config: Config{
+				ServerConfig: ServerConfig{
+					Port:            8080,
+					Host:            "0.0.0.0",
+					ShutdownTimeout: 30 * time.Second,
+					IdleTimeout:     -time.Second,
+				},
+				DatabaseConfig: DatabaseConfig{
+					Path: "/tmp/test-idle-timeout/tasks.db",
+				},
+				JWTConfig: JWTConfig{
+					Secret:     "this-is-a-valid-secret-key-with-32-characters",
+					Expiration: 24 * time.Hour,
+				},
+				LogConfig: logger.Config{
+					Level:       "info",
+					Format:      "json",
+					Output:      "stdout",
+					ServiceName: "task-manager-api",
+					Environment: "production",
+				},
+			},
+			expectedErr: true,
+			errContains: "server.idle_timeout must not be negative",
+		},
+		{
+			name: "Default page size above max page size",
+			config: Config{
+				ServerConfig: ServerConfig{
+					Port:            8080,
+					Host:            "0.0.0.0",
+					ShutdownTimeout: 30 * time.Second,
+					DefaultPageSize: 100,
+					MaxPageSize:     20,
+				},
+				DatabaseConfig: DatabaseConfig{
+					Path: "/tmp/test-page-size/tasks.db",
+				},
+				JWTConfig: JWTConfig{
+					Secret:     "this-is-a-valid-secret-key-with-32-characters",
+					Expiration: 24 * time.Hour,
+				},
+				LogConfig: logger.Config{
+					Level:       "info",
+					Format:      "json",
+					Output:      "stdout",
+					ServiceName: "task-manager-api",
+					Environment: "production",
+				},
+			},
+			expectedErr: true,
+			errContains: "server.default_page_size must not exceed server.max_page_size (20), got 100",
+		},
+		{
+			name: "Negative idempotency window",
+			config: Config{
+				ServerConfig: ServerConfig{
+					Port:              8080,
+					Host:              "0.0.0.0",
+					ShutdownTimeout:   30 * time.Second,
+					IdempotencyWindow: -time.Hour,
+				},
+				DatabaseConfig: DatabaseConfig{
+					Path: "/tmp/test-idempotency/tasks.db",
+				},
+				JWTConfig: JWTConfig{
+					Secret:     "this-is-a-valid-secret-key-with-32-characters",
+					Expiration: 24 * time.Hour,
+				},
+				LogConfig: logger.Config{
+					Level:       "info",
+					Format:      "json",
+					Output:      "stdout",
+					ServiceName: "task-manager-api",
+					Environment: "production",
+				},
+			},
+			expectedErr: true,
+			errContains: "server.idempotency_window",
+		},
+		{
+			name: "Bcrypt cost above the maximum",
+			config: Config{
+				ServerConfig: ServerConfig{
+					Port:            8080,
+					Host:            "0.0.0.0",
+					ShutdownTimeout: 30 * time.Second,
+				},
+				DatabaseConfig: DatabaseConfig{
+					Path: "/tmp/test-bcrypt/tasks.db",
+				},
+				JWTConfig: JWTConfig{
+					Secret:     "this-is-a-valid-secret-key-with-32-characters",
+					Expiration: 24 * time.Hour,
+				},
+				AuthConfig: AuthConfig{
+					BcryptCost: 32,
+				},
+				LogConfig: logger.Config{
+					Level:       "info",
+					Format:      "json",
+					Output:      "stdout",
+					ServiceName: "task-manager-api",
+					Environment: "production",
+				},
+			},
+			expectedErr: true,
+			errContains: "auth.bcrypt_cost",
+		},
+		{
+			name: "Wildcard CORS origin",
+			config: Config{
+				ServerConfig: ServerConfig{
+					Port:            8080,
+					Host:            "0.0.0.0",
+					ShutdownTimeout: 30 * time.Second,
+				},
+				DatabaseConfig: DatabaseConfig{
+					Path: "/tmp/test-cors/tasks.db",
+				},
+				JWTConfig: JWTConfig{
+					Secret:     "this-is-a-valid-secret-key-with-32-characters",
+					Expiration: 24 * time.Hour,
+				},
+				CORSConfig: CORSConfig{
+					AllowedOrigins: []string{"https://app.example.com", "*"},
+				},
+				LogConfig: logger.Config{
+					Level:       "info",
+					Format:      "json",
+					Output:      "stdout",
+					ServiceName: "task-manager-api",
+					Environment: "production",
+				},
+			},
+			expectedErr: true,
+			errContains: "cors.allowed_origins",
+		},
 	}
 
 	for _, tc := range testCases {
@@ -777,6 +1121,346 @@ func TestValidation(t *testing.T) {
 	}
 }
 
+func TestJWTConfigResolveSecret(t *testing.T) {
+	const secret = "file-secret-key-with-at-least-32-chars"
+	dir := t.TempDir()
+	secretFile := filepath.Join(dir, "jwt-secret")
+	if err := os.WriteFile(secretFile, []byte(secret+"\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	emptyFile := filepath.Join(dir, "empty")
+	if err := os.WriteFile(emptyFile, []byte("  \n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("TEST_JWT_SECRET", secret)
+
+	testCases := []struct {
+		name          string
+		config        JWTConfig
+		expected      string
+		errorContains string
+	}{
+		{name: "literal secret is kept", config: JWTConfig{Secret: secret}, expected: secret},
+		{name: "secret file is read and trimmed", config: JWTConfig{SecretFile: secretFile}, expected: secret},
+		{name: "secret env is read", config: JWTConfig{SecretEnv: "TEST_JWT_SECRET"}, expected: secret},
+		{name: "no source leaves the secret empty", config: JWTConfig{}, expected: ""},
+		{
+			name:          "literal and file together",
+			config:        JWTConfig{Secret: secret, SecretFile: secretFile},
+			errorContains: "jwt.secret and jwt.secret_file",
+		},
+		{
+			name:          "file and env together",
+			config:        JWTConfig{SecretFile: secretFile, SecretEnv: "TEST_JWT_SECRET"},
+			errorContains: "exactly one source",
+		},
+		{name: "missing file", config: JWTConfig{SecretFile: filepath.Join(dir, "missing")}, errorContains: "read jwt.secret_file"},
+		{name: "empty file", config: JWTConfig{SecretFile: emptyFile}, errorContains: "is empty"},
+		{name: "unset env", config: JWTConfig{SecretEnv: "TEST_JWT_SECRET_UNSET"}, errorContains: "not set"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.config.ResolveSecret()
+
+			if tc.errorContains != "" {
+				if err == nil || !strings.Contains(err.Error(), tc.errorContains) {
+					t.Fatalf("Expected error containing %q, got %v", tc.errorContains, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Expected no error, got %v", err)
+			}
+			if tc.config.Secret != tc.expected {
+				t.Errorf("Expected secret %q, got %q", tc.expected, tc.config.Secret)
+			}
+		})
+	}
+}
+
+func TestJWTSecretFromFileMustMeetMinimumLength(t *testing.T) {
+	secretFile := filepath.Join(t.TempDir(), "jwt-secret")
+	if err := os.WriteFile(secretFile, []byte("too-short"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	config := Config{
+		ServerConfig:   ServerConfig{Port: 8080, ShutdownTimeout: time.Second},
+		DatabaseConfig: DatabaseConfig{Path: filepath.Join(t.TempDir(), "tasks.db")},
+		JWTConfig:      JWTConfig{SecretFile: secretFile, Expiration: time.Hour},
+	}
+
+	if err := config.JWTConfig.ResolveSecret(); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	err := config.Validate()
+	if err == nil || !strings.Contains(err.Error(), "secret must be at least 32 symbols, got 9") {
+		t.Errorf("Expected minimum length error, got %v", err)
+	}
+}
+
+func TestValidateTLS(t *testing.T) {
+	// ====Arrange====
+	dir := t.TempDir()
+	certFile := filepath.Join(dir, "cert.pem")
+	keyFile := filepath.Join(dir, "key.pem")
+	for _, path := range []string{certFile, keyFile} {
+		if err := os.WriteFile(path, []byte("pem"), 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	testCases := []struct {
+		name        string
+		tls         TLSConfig
+		errContains []string
+	}{
+		{name: "Disabled TLS ignores missing files", tls: TLSConfig{CertFile: "/missing/cert.pem"}},
+		{name: "Readable certificate and key", tls: TLSConfig{Enabled: true, CertFile: certFile, KeyFile: keyFile}},
+		{name: "Files not set", tls: TLSConfig{Enabled: true}, errContains: []string{"tls.cert_file", "tls.key_file"}},
+		{name: "Missing key file", tls: TLSConfig{Enabled: true, CertFile: certFile, KeyFile: filepath.Join(dir, "missing.pem")}, errContains: []string{"tls.key_file"}},
+		{name: "Directory instead of file", tls: TLSConfig{Enabled: true, CertFile: dir, KeyFile: keyFile}, errContains: []string{"tls.cert_file", "is a directory"}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			config := Config{
+				ServerConfig:   ServerConfig{Port: 8080, ShutdownTimeout: 30 * time.Second},
+				DatabaseConfig: DatabaseConfig{Path: filepath.Join(dir, "tasks.db")},
+				JWTConfig:      JWTConfig{Secret: "this-is-a-valid-secret-key-with-32-characters", Expiration: time.Hour},
+				LogConfig:      logger.Config{Level: "info", Format: "json", Output: "stdout", ServiceName: "task-manager-api", Environment: "production"},
+				TLSConfig:      tc.tls,
+			}
+
+			// ====Act====
+			err := config.Validate()
+
+			// ====Assert====
+			if len(tc.errContains) == 0 {
+				if err != nil {
+					t.Errorf("Expected no validation error but got: %v", err)
+				}
+				return
+			}
+			if err == nil {
+				t.Fatal("Expected validation error but got none")
+			}
+			for _, want := range tc.errContains {
+				if !strings.Contains(err.Error(), want) {
+					t.Errorf("Expected error to contain %q, but got: %v", want, err)
+				}
+			}
+		})
+	}
+}
+
+func TestFeaturesConfig(t *testing.T) {
+	// ====Arrange====
+	testCases := []struct {
+		name            string
+		yaml            string
+		expected        FeaturesConfig
+		expectedEnabled []string
+	}{
+		{
+			name:            "Defaults keep only auth success logging on",
+			yaml:            "",
+			expected:        FeaturesConfig{AuthSuccessLogging: true},
+			expectedEnabled: []string{"auth_success_logging"},
+		},
+		{
+			name: "Features section toggles switches",
+			yaml: "features:\n  serve_ui: true\n  expand_templates: true\n  auth_success_logging: false\n  strict_ownership: true\n  reject_duplicates: true\n",
+			expected: FeaturesConfig{
+				ServeUI:          true,
+				ExpandTemplates:  true,
+				StrictOwnership:  true,
+				RejectDuplicates: true,
+			},
+			expectedEnabled: []string{"serve_ui", "expand_templates", "strict_ownership", "reject_duplicates"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			v := viper.New()
+			v.SetDefault("features.serve_ui", false)
+			v.SetDefault("features.expand_templates", false)
+			v.SetDefault("features.auth_success_logging", true)
+			v.SetDefault("features.strict_ownership", false)
+			v.SetDefault("features.reject_duplicates", false)
+			v.SetConfigType("yaml")
+			if err := v.ReadConfig(strings.NewReader(tc.yaml)); err != nil {
+				t.Fatalf("Failed to read config: %v", err)
+			}
+
+			// ====Act====
+			var config Config
+			if err := v.Unmarshal(&config); err != nil {
+				t.Fatalf("Failed to unmarshal config: %v", err)
+			}
+
+			// ====Assert====
+			if config.Features() != tc.expected {
+				t.Errorf("Expected features %+v, got %+v", tc.expected, config.Features())
+			}
+
+			enabled := config.Features().Enabled()
+			if strings.Join(enabled, ",") != strings.Join(tc.expectedEnabled, ",") {
+				t.Errorf("Expected enabled features %v, got %v", tc.expectedEnabled, enabled)
+			}
+		})
+	}
+}
+
+func TestReadConfigFile(t *testing.T) {
+	// writeConfig creates name in a new directory under root and returns the directory
+	writeConfig := func(t *testing.T, root, dir, name, content string) string {
+		t.Helper()
+		path := filepath.Join(root, dir)
+		if err := os.MkdirAll(path, 0o755); err != nil {
+			t.Fatalf("Failed to create directory: %v", err)
+		}
+		if err := os.WriteFile(filepath.Join(path, name), []byte(content), 0o644); err != nil {
+			t.Fatalf("Failed to write config file: %v", err)
+		}
+		return path
+	}
+
+	// ====Arrange====
+	testCases := []struct {
+		name         string
+		setup        func(t *testing.T, root string) (configFile string, searchPaths []string)
+		expectedPort int
+		expectError  bool
+	}{
+		{
+			name: "First search path wins",
+			setup: func(t *testing.T, root string) (string, []string) {
+				local := writeConfig(t, root, "local", "config.yaml", "server:\n  port: 1111\n")
+				xdg := writeConfig(t, root, "xdg", "config.yaml", "server:\n  port: 2222\n")
+				etc := writeConfig(t, root, "etc", "config.yaml", "server:\n  port: 3333\n")
+				return "", []string{local, xdg, etc}
+			},
+			expectedPort: 1111,
+		},
+		{
+			name: "XDG directory is used before /etc",
+			setup: func(t *testing.T, root string) (string, []string) {
+				xdg := writeConfig(t, root, "xdg", "config.yaml", "server:\n  port: 2222\n")
+				etc := writeConfig(t, root, "etc", "config.yaml", "server:\n  port: 3333\n")
+				return "", []string{filepath.Join(root, "local"), xdg, etc}
+			},
+			expectedPort: 2222,
+		},
+		{
+			name: "Last search path is used when the others have no file",
+			setup: func(t *testing.T, root string) (string, []string) {
+				etc := writeConfig(t, root, "etc", "config.yaml", "server:\n  port: 3333\n")
+				return "", []string{filepath.Join(root, "local"), filepath.Join(root, "xdg"), etc}
+			},
+			expectedPort: 3333,
+		},
+		{
+			name: "JSON config is detected by extension",
+			setup: func(t *testing.T, root string) (string, []string) {
+				return "", []string{writeConfig(t, root, "local", "config.json", `{"server": {"port": 4444}}`)}
+			},
+			expectedPort: 4444,
+		},
+		{
+			name: "TOML config is detected by extension",
+			setup: func(t *testing.T, root string) (string, []string) {
+				return "", []string{writeConfig(t, root, "local", "config.toml", "[server]\nport = 5555\n")}
+			},
+			expectedPort: 5555,
+		},
+		{
+			name: "No config file keeps defaults",
+			setup: func(t *testing.T, root string) (string, []string) {
+				return "", []string{filepath.Join(root, "local"), filepath.Join(root, "etc")}
+			},
+			expectedPort: 8080,
+		},
+		{
+			name: "Specified file overrides the search",
+			setup: func(t *testing.T, root string) (string, []string) {
+				local := writeConfig(t, root, "local", "config.yaml", "server:\n  port: 1111\n")
+				custom := writeConfig(t, root, "custom", "server.toml", "[server]\nport = 6666\n")
+				return filepath.Join(custom, "server.toml"), []string{local}
+			},
+			expectedPort: 6666,
+		},
+		{
+			name: "Missing specified file is an error",
+			setup: func(t *testing.T, root string) (string, []string) {
+				return filepath.Join(root, "missing.yaml"), nil
+			},
+			expectError: true,
+		},
+		{
+			name: "Malformed specified file is an error",
+			setup: func(t *testing.T, root string) (string, []string) {
+				custom := writeConfig(t, root, "custom", "config.json", `{"server": {`)
+				return filepath.Join(custom, "config.json"), nil
+			},
+			expectError: true,
+		},
+		{
+			name: "Malformed file in a search path is an error",
+			setup: func(t *testing.T, root string) (string, []string) {
+				return "", []string{writeConfig(t, root, "local", "config.yaml", "server: [port\n")}
+			},
+			expectError: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			configFile, searchPaths := tc.setup(t, t.TempDir())
+			v := viper.New()
+			v.SetDefault("server.port", 8080)
+
+			// ====Act====
+			err := readConfigFile(v, configFile, searchPaths)
+
+			// ====Assert====
+			if tc.expectError {
+				if err == nil {
+					t.Fatal("Expected an error, got nil")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if port := v.GetInt("server.port"); port != tc.expectedPort {
+				t.Errorf("Expected port %d, got %d", tc.expectedPort, port)
+			}
+		})
+	}
+}
+
+func TestConfigSearchPaths(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+
+	t.Run("Uses XDG_CONFIG_HOME when set", func(t *testing.T) {
+		t.Setenv("XDG_CONFIG_HOME", "/xdg")
+		expected := []string{".", "/xdg/taskmanager", filepath.Join(home, ".taskmanager"), "/etc/taskmanager"}
+		if paths := configSearchPaths(); strings.Join(paths, ",") != strings.Join(expected, ",") {
+			t.Errorf("Expected %v, got %v", expected, paths)
+		}
+	})
+	t.Run("Falls back to ~/.config when XDG_CONFIG_HOME is unset", func(t *testing.T) {
+		t.Setenv("XDG_CONFIG_HOME", "")
+		expected := []string{".", filepath.Join(home, ".config", "taskmanager"), filepath.Join(home, ".taskmanager"), "/etc/taskmanager"}
+		if paths := configSearchPaths(); strings.Join(paths, ",") != strings.Join(expected, ",") {
+			t.Errorf("Expected %v, got %v", expected, paths)
+		}
+	})
+}
+
 func TestMaskSensitive(t *testing.T) {
 	// ====Arrange====
 	testCases := []struct {
@@ -1,29 +1,100 @@
 package domain
 
-import "errors"
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
 
 var ErrEmptyFieldsToUpdate = errors.New("at least one field must be provided for update")
 var (
 	ErrTaskNotFound = errors.New("task not found")
+	// ErrTaskForbidden is only reported in strict ownership mode; by default a task
+	// of another user is indistinguishable from a missing one.
+	ErrTaskForbidden = errors.New("task belongs to another user")
+	// ErrVersionConflict means the task changed since the version the update was based on.
+	ErrVersionConflict = errors.New("task was changed by another request, reload it and try again")
 )
 
 var (
 	ErrDescriptionRequired = errors.New("description is required")
-	ErrDescriptionTooLong  = errors.New("description too long (max 200 characters)")
+	ErrDescriptionTooLong  = errors.New("description too long")
+	ErrEmptyBatch          = errors.New("at least one task is required")
+	ErrDueDateInPast       = errors.New("due date is too far in the past")
+	ErrInvalidPriority     = errors.New("priority must be between 0 and 3")
+	ErrInvalidTag          = errors.New("tag must be 1 to 32 characters without spaces or commas")
+	// ErrDuplicateTask is only reported when the service is set to reject duplicate descriptions.
+	ErrDuplicateTask = errors.New("a task with this description already exists")
 )
 
+// BatchItemError identifies the zero-based position of the task that made a batch request fail.
+type BatchItemError struct {
+	Index int
+	Err   error
+}
+
+func (e *BatchItemError) Error() string {
+	return fmt.Sprintf("task %d: %v", e.Index, e.Err)
+}
+
+func (e *BatchItemError) Unwrap() error {
+	return e.Err
+}
+
+// FieldError is a validation rule broken by one field of a task.
+type FieldError struct {
+	Field string
+	Err   error
+}
+
+func (e *FieldError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *FieldError) Unwrap() error {
+	return e.Err
+}
+
+// ValidationErrors lists every field of a task that failed Task.Validate.
+// errors.Is and errors.As look through all of them.
+type ValidationErrors []*FieldError
+
+func (e ValidationErrors) Error() string {
+	messages := make([]string, len(e))
+	for i, fieldErr := range e {
+		messages[i] = fieldErr.Error()
+	}
+	return strings.Join(messages, "; ")
+}
+
+func (e ValidationErrors) Unwrap() []error {
+	errs := make([]error, len(e))
+	for i, fieldErr := range e {
+		errs[i] = fieldErr
+	}
+	return errs
+}
+
 // Authentication errors
 var (
 	// Ошибки валидации (400 Bad Request)
-	ErrInvalidEmail     = errors.New("invalid email format")
-	ErrPasswordTooShort = errors.New("password must be at least 8 characters")
-	ErrPasswordTooLong  = errors.New("password must be max 72 bytes")
+	ErrInvalidEmail           = errors.New("invalid email format")
+	ErrPasswordTooShort       = errors.New("password is too short")
+	ErrPasswordTooLong        = errors.New("password must be max 72 bytes")
+	ErrPasswordMissingLetter  = errors.New("password must contain a letter")
+	ErrPasswordMissingDigit   = errors.New("password must contain a digit")
+	ErrPasswordMissingUpper   = errors.New("password must contain an upper-case letter")
+	ErrPasswordMissingSpecial = errors.New("password must contain a special character")
+	ErrSamePassword           = errors.New("new password must differ from the current password")
 
 	// Ошибки конфликта (409 Conflict)
 	ErrEmailAlreadyExists = errors.New("email already registered")
 
 	// Ошибки авторизации (401 Unauthorized)
 	ErrInvalidCredentials = errors.New("invalid credentials")
+
+	// Ошибки блокировки (423 Locked)
+	ErrAccountLocked = errors.New("too many failed logins, try again later")
 )
 
 // Internal errors
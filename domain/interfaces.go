@@ -1,21 +1,75 @@
 package domain
 
-import "context"
+import (
+	"context"
+	"time"
+)
 
 type TaskService interface {
-	CreateTask(ctx context.Context, description string, userID int) (Task, error)
-	UpdateTask(ctx context.Context, taskID, userID int, description *string, done *bool) (Task, error)
+	CreateTask(ctx context.Context, description string, done bool, dueDate *time.Time, priority int, tags []string, userID int) (Task, error)
+	CreateTasks(ctx context.Context, tasks []Task, userID int) ([]Task, error)
+	UpdateTask(ctx context.Context, taskID, userID int, description *string, done *bool, dueDate *time.Time, priority *int, version *int) (Task, error)
+	// UpdateTasks applies every operation or, if any of them fails, none of them.
+	UpdateTasks(ctx context.Context, updates []TaskUpdate, userID int) ([]Task, error)
+	ToggleTask(ctx context.Context, taskID, userID int) (Task, error)
 	GetTasks(ctx context.Context, userID int) ([]Task, error)
+	GetTask(ctx context.Context, taskID, userID int) (Task, error)
+	ListTasks(ctx context.Context, userID int, opts ListOptions) (TaskPage, error)
+	DeleteTask(ctx context.Context, taskID, userID int) error
 }
 
 // Storage defines the interface for task persistence operations.
 type Storage interface {
-	LoadTasks(ctx context.Context, userID int) ([]Task, error)
+	TxStorage
+	// WithTx runs fn with task operations bound to one transaction. The changes fn makes
+	// are kept only when it returns nil; an error rolls all of them back and is returned.
+	WithTx(ctx context.Context, fn func(tx TxStorage) error) error
+	Close(ctx context.Context) error
+}
+
+// TxStorage holds the task operations, which a Storage runs on its own and WithTx within a transaction.
+type TxStorage interface {
+	LoadTasks(ctx context.Context, userID int, opts ListOptions) ([]Task, error)
+	// CountTasks counts the user's non-deleted tasks that match the filter.
+	CountTasks(ctx context.Context, userID int, filter TaskFilter) (int, error)
+	// TaskStats counts a user's non-deleted, non-archived tasks by completion status.
+	TaskStats(ctx context.Context, userID int) (Stats, error)
+	// SearchTasks returns the user's non-deleted, non-archived tasks whose description contains query.
+	SearchTasks(ctx context.Context, userID int, query string) ([]Task, error)
 	GetTaskByID(ctx context.Context, id int, userID int) (task Task, err error)
+	// TaskExists reports whether a non-deleted task with the ID exists for any user.
+	TaskExists(ctx context.Context, id int) (bool, error)
+	// TaskExistsByDescription reports whether the user has a non-deleted task with exactly this description.
+	TaskExistsByDescription(ctx context.Context, userID int, description string) (bool, error)
 	CreateTask(ctx context.Context, task Task, userID int) (int, error)
+	CreateTasks(ctx context.Context, tasks []Task, userID int) ([]int, error)
+	// UpdateTask stores the task if its Version is still the stored one and increments the version,
+	// returning ErrVersionConflict otherwise.
 	UpdateTask(ctx context.Context, task Task, userID int) error
+	// UpdateTasks stores all tasks like UpdateTask in one transaction; when one of them fails,
+	// none is changed and the error is a *BatchItemError naming its position.
+	UpdateTasks(ctx context.Context, tasks []Task, userID int) error
+	// ToggleTaskDone flips the status of the user's task in a single write and returns the changed task.
+	ToggleTaskDone(ctx context.Context, id int, userID int) (Task, error)
+	// AddTag attaches a tag to the user's task; a tag the task already carries is left as is.
+	AddTag(ctx context.Context, taskID int, tag string, userID int) error
+	// RemoveTag detaches a tag from the user's task; a tag the task does not carry is ignored.
+	RemoveTag(ctx context.Context, taskID int, tag string, userID int) error
+	// UpdateAllTaskStatus sets the status of every non-deleted task of the user and
+	// returns how many tasks changed; tasks already in that status are left untouched.
+	UpdateAllTaskStatus(ctx context.Context, userID int, done bool) (int, error)
 	DeleteTask(ctx context.Context, id int, userID int) error
-	Close(ctx context.Context) error
+	// DeleteTasks soft-deletes the listed tasks the user owns, skipping any other IDs,
+	// and returns how many were deleted.
+	DeleteTasks(ctx context.Context, ids []int, userID int) (int, error)
+	RestoreTask(ctx context.Context, id int, userID int) error
+	// ArchiveTask hides the user's non-deleted task from default task lists; archiving an archived task
+	// keeps its original archive time. Returns ErrTaskNotFound if the user owns no such task.
+	ArchiveTask(ctx context.Context, id int, userID int) error
+	// ArchiveCompleted archives every done, non-deleted task of the user that is not archived yet
+	// and returns how many were archived.
+	ArchiveCompleted(ctx context.Context, userID int) (int, error)
+	PurgeTask(ctx context.Context, id int, userID int) error
 }
 
 // UserStorage defines the interface for user persistence operations.
@@ -24,6 +78,10 @@ type UserStorage interface {
 	GetUserByEmail(ctx context.Context, email string) (*User, error)
 	GetUserByID(ctx context.Context, id int) (*User, error)
 	EmailExists(ctx context.Context, email string) (bool, error)
+	DeleteUser(ctx context.Context, id int) error
+	UpdatePasswordHash(ctx context.Context, id int, passwordHash string) error
+	// ListUsers returns one page of all users ordered by ID; a zero limit returns all remaining users.
+	ListUsers(ctx context.Context, limit, offset int) ([]User, error)
 }
 
 type AppStorage interface {
@@ -34,13 +92,19 @@ type AppStorage interface {
 type AuthService interface {
 	Register(ctx context.Context, email, password string) (token string, err error)
 	Login(ctx context.Context, email, password string) (token string, err error)
+	CurrentUser(ctx context.Context, userID int) (*User, error)
+	DeleteAccount(ctx context.Context, userID int) error
+	ChangePassword(ctx context.Context, userID int, currentPassword, newPassword string) error
+	ListUsers(ctx context.Context, limit, offset int) ([]User, error)
 }
 
 type TokenGenerator interface {
-	GenerateToken(userID int) (string, error)
+	GenerateToken(userID int, role string) (string, error)
 	ValidateToken(tokenString string) (*Claims, error)
 }
 
+// Claims is the token payload. Role is empty in tokens issued before roles existed.
 type Claims struct {
-	UserID int `json:"user_id"`
+	UserID int    `json:"user_id"`
+	Role   string `json:"role,omitempty"`
 }
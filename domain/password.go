@@ -0,0 +1,78 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// MaxPasswordBytes is the longest password bcrypt can hash; longer input would be truncated silently.
+const MaxPasswordBytes = 72
+
+// PasswordPolicy describes the strength rules a new password must satisfy.
+type PasswordPolicy struct {
+	MinLength      int  // minimum number of characters
+	RequireLetter  bool // at least one letter
+	RequireDigit   bool // at least one digit
+	RequireUpper   bool // at least one upper-case letter
+	RequireSpecial bool // at least one character that is neither a letter, a digit nor a space
+}
+
+// DefaultPasswordPolicy requires 8 characters with at least one letter and one digit.
+func DefaultPasswordPolicy() PasswordPolicy {
+	return PasswordPolicy{
+		MinLength:     8,
+		RequireLetter: true,
+		RequireDigit:  true,
+	}
+}
+
+// Validate checks password against the policy and returns the first rule it breaks.
+// The bcrypt limit of MaxPasswordBytes is always enforced.
+func (p PasswordPolicy) Validate(password string) error {
+	if utf8.RuneCountInString(password) < p.MinLength {
+		return fmt.Errorf("%w: at least %d characters required", ErrPasswordTooShort, p.MinLength)
+	}
+
+	if len(password) > MaxPasswordBytes {
+		return ErrPasswordTooLong
+	}
+
+	if p.RequireLetter && !strings.ContainsFunc(password, unicode.IsLetter) {
+		return ErrPasswordMissingLetter
+	}
+	if p.RequireDigit && !strings.ContainsFunc(password, unicode.IsDigit) {
+		return ErrPasswordMissingDigit
+	}
+	if p.RequireUpper && !strings.ContainsFunc(password, unicode.IsUpper) {
+		return ErrPasswordMissingUpper
+	}
+	if p.RequireSpecial && !strings.ContainsFunc(password, isSpecial) {
+		return ErrPasswordMissingSpecial
+	}
+	return nil
+}
+
+// IsPasswordPolicyError reports whether err means the password broke a PasswordPolicy rule.
+func IsPasswordPolicyError(err error) bool {
+	for _, target := range []error{
+		ErrPasswordTooShort,
+		ErrPasswordTooLong,
+		ErrPasswordMissingLetter,
+		ErrPasswordMissingDigit,
+		ErrPasswordMissingUpper,
+		ErrPasswordMissingSpecial,
+	} {
+		if errors.Is(err, target) {
+			return true
+		}
+	}
+	return false
+}
+
+// isSpecial reports whether r is a symbol or punctuation character.
+func isSpecial(r rune) bool {
+	return !unicode.IsLetter(r) && !unicode.IsDigit(r) && !unicode.IsSpace(r)
+}
@@ -0,0 +1,51 @@
+package domain
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPasswordPolicy_Validate(t *testing.T) {
+	strict := PasswordPolicy{MinLength: 10, RequireLetter: true, RequireDigit: true, RequireUpper: true, RequireSpecial: true}
+
+	testCases := []struct {
+		name     string
+		policy   PasswordPolicy
+		password string
+		wantErr  error
+	}{
+		{"default accepts letters and digits", DefaultPasswordPolicy(), "secret123", nil},
+		{"default counts characters, not bytes", DefaultPasswordPolicy(), "пароль12", nil},
+		{"default rejects short password", DefaultPasswordPolicy(), "abc123", ErrPasswordTooShort},
+		{"default rejects missing letter", DefaultPasswordPolicy(), "12345678", ErrPasswordMissingLetter},
+		{"default rejects missing digit", DefaultPasswordPolicy(), "abcdefgh", ErrPasswordMissingDigit},
+		{"bcrypt limit applies to every policy", PasswordPolicy{}, strings.Repeat("a", 73), ErrPasswordTooLong},
+		{"bcrypt limit counts bytes", PasswordPolicy{}, strings.Repeat("я", 37), ErrPasswordTooLong},
+		{"strict rejects missing upper", strict, "secret123!", ErrPasswordMissingUpper},
+		{"strict rejects missing special", strict, "Secret1234", ErrPasswordMissingSpecial},
+		{"strict accepts compliant password", strict, "Secret123!", nil},
+		{"empty policy accepts anything short of the limit", PasswordPolicy{}, "", nil},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.policy.Validate(tc.password)
+
+			if tc.wantErr == nil {
+				assert.NoError(t, err)
+				return
+			}
+			assert.ErrorIs(t, err, tc.wantErr)
+			assert.True(t, IsPasswordPolicyError(err))
+		})
+	}
+}
+
+func TestIsPasswordPolicyError(t *testing.T) {
+	assert.True(t, IsPasswordPolicyError(ErrPasswordMissingDigit))
+	assert.False(t, IsPasswordPolicyError(ErrInvalidEmail))
+	assert.False(t, IsPasswordPolicyError(errors.New("password must contain a digit")))
+}
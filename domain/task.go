@@ -1,8 +1,145 @@
 package domain
 
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+)
+
 // Task represents a single task with ID, description, and completion status.
+// CreatedAt and UpdatedAt are kept by storage with second precision.
+// An open task whose DueDate has passed is overdue.
+// Version counts the changes made to the task since it was created; updates carrying an
+// older version are rejected so concurrent edits cannot overwrite each other.
+// Tags are lowercase, unique per task and sorted by name.
+// An archived task has ArchivedAt set and is left out of task lists unless they ask for archived tasks.
 type Task struct {
-	ID          int    `json:"id"`
-	Description string `json:"description"`
-	Done        bool   `json:"done"`
+	ID          int        `json:"id"`
+	Description string     `json:"description"`
+	Done        bool       `json:"done"`
+	CompletedAt *time.Time `json:"completed_at,omitempty"`
+	DueDate     *time.Time `json:"due_date,omitempty"`
+	Priority    int        `json:"priority"`
+	Tags        []string   `json:"tags,omitempty"`
+	ArchivedAt  *time.Time `json:"archived_at,omitempty"`
+	Version     int        `json:"version"`
+	CreatedAt   time.Time  `json:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at"`
+}
+
+// Validate checks the fields of a task before it is stored and reports every field that breaks a rule:
+// the description must not be blank nor longer than maxDescriptionLength bytes, and the priority must
+// lie between PriorityNone and PriorityHigh. Several failures are returned together as ValidationErrors.
+func (t Task) Validate(maxDescriptionLength int) error {
+	var errs ValidationErrors
+	switch {
+	case strings.TrimSpace(t.Description) == "":
+		errs = append(errs, &FieldError{Field: "description", Err: ErrDescriptionRequired})
+	case len(t.Description) > maxDescriptionLength:
+		errs = append(errs, &FieldError{Field: "description", Err: fmt.Errorf("%w (max %d characters)", ErrDescriptionTooLong, maxDescriptionLength)})
+	}
+	if t.Priority < PriorityNone || t.Priority > PriorityHigh {
+		errs = append(errs, &FieldError{Field: "priority", Err: ErrInvalidPriority})
+	}
+	if len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
+
+// Task priorities. New and existing tasks default to PriorityNone, the lowest level.
+const (
+	PriorityNone   = 0
+	PriorityLow    = 1
+	PriorityMedium = 2
+	PriorityHigh   = 3
+)
+
+// Stats summarizes a user's tasks. Pending counts the tasks that are not done yet.
+type Stats struct {
+	Total   int `json:"total"`
+	Done    int `json:"done"`
+	Pending int `json:"pending"`
+}
+
+// DefaultPageLimit is the number of tasks returned when a list request does not specify a limit.
+const DefaultPageLimit = 50
+
+// MaxPageLimit is the largest page the REST API returns unless configured otherwise; larger limits are clamped to it.
+const MaxPageLimit = 200
+
+// ListOptions selects a page of a user's tasks. A zero Limit returns all remaining tasks.
+type ListOptions struct {
+	Limit  int
+	Offset int
+	Sort   TaskSort
+	Filter TaskFilter
+}
+
+// TaskFilter narrows a task list to tasks created within a time range. Both bounds are
+// inclusive and optional; the zero value keeps every task that is not archived.
+// IncludeArchived keeps archived tasks as well.
+// OverdueAt, when set, keeps only the tasks that are overdue at that time, and a non-empty Tag
+// only the tasks carrying that normalized tag.
+type TaskFilter struct {
+	CreatedAfter    *time.Time
+	CreatedBefore   *time.Time
+	IncludeArchived bool
+	OverdueAt       *time.Time
+	Tag             string
+}
+
+// Matches reports whether the task falls within the filter's bounds and is not archived, unless archived tasks are included.
+func (f TaskFilter) Matches(task Task) bool {
+	if task.ArchivedAt != nil && !f.IncludeArchived {
+		return false
+	}
+	if f.OverdueAt != nil && (task.Done || task.DueDate == nil || !task.DueDate.Before(*f.OverdueAt)) {
+		return false
+	}
+	if f.Tag != "" && !slices.Contains(task.Tags, f.Tag) {
+		return false
+	}
+	if f.CreatedAfter != nil && task.CreatedAt.Before(*f.CreatedAfter) {
+		return false
+	}
+	if f.CreatedBefore != nil && task.CreatedAt.After(*f.CreatedBefore) {
+		return false
+	}
+	return true
+}
+
+// SortField names a task attribute a list can be ordered by.
+type SortField string
+
+const (
+	SortByID       SortField = "id"
+	SortByCreated  SortField = "created"
+	SortByUpdated  SortField = "updated"
+	SortByDone     SortField = "done"
+	SortByPriority SortField = "priority"
+	SortByDue      SortField = "due"
+)
+
+// TaskSort orders a task list. The zero value keeps the default order:
+// open tasks first, newest first within each group.
+type TaskSort struct {
+	Field      SortField
+	Descending bool
+}
+
+// TaskPage is one page of tasks together with the total number of tasks the user owns.
+type TaskPage struct {
+	Tasks  []Task `json:"tasks"`
+	Total  int    `json:"total"`
+	Limit  int    `json:"limit"`
+	Offset int    `json:"offset"`
+}
+
+// TaskUpdate is one operation of a batch update. Fields left nil keep their current value.
+type TaskUpdate struct {
+	ID          int
+	Description *string
+	Done        *bool
 }
@@ -0,0 +1,52 @@
+package domain
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTask_Validate(t *testing.T) {
+	const maxLength = 10
+
+	testCases := []struct {
+		name    string
+		task    Task
+		wantErr error
+	}{
+		{"accepts valid task", Task{Description: "Buy milk", Priority: PriorityHigh}, nil},
+		{"accepts description at the limit", Task{Description: strings.Repeat("a", maxLength)}, nil},
+		{"rejects empty description", Task{Description: ""}, ErrDescriptionRequired},
+		{"rejects blank description", Task{Description: "   "}, ErrDescriptionRequired},
+		{"rejects too long description", Task{Description: strings.Repeat("a", maxLength+1)}, ErrDescriptionTooLong},
+		{"rejects negative priority", Task{Description: "Buy milk", Priority: -1}, ErrInvalidPriority},
+		{"rejects priority above high", Task{Description: "Buy milk", Priority: PriorityHigh + 1}, ErrInvalidPriority},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.task.Validate(maxLength)
+
+			if tc.wantErr == nil {
+				assert.NoError(t, err)
+				return
+			}
+			assert.ErrorIs(t, err, tc.wantErr)
+		})
+	}
+}
+
+func TestTask_Validate_ReportsEveryField(t *testing.T) {
+	err := Task{Description: "", Priority: 7}.Validate(10)
+
+	var validationErrs ValidationErrors
+	assert.True(t, errors.As(err, &validationErrs))
+	assert.Len(t, validationErrs, 2)
+	assert.Equal(t, "description", validationErrs[0].Field)
+	assert.Equal(t, "priority", validationErrs[1].Field)
+	assert.ErrorIs(t, err, ErrDescriptionRequired)
+	assert.ErrorIs(t, err, ErrInvalidPriority)
+	assert.Equal(t, "description is required; priority must be between 0 and 3", err.Error())
+}
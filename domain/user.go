@@ -3,9 +3,17 @@ package domain
 import "time"
 
 // User represents a user account with authentication credentials.
+// Role decides which administrative endpoints the user may call.
 type User struct {
 	ID           int       `json:"id"`
 	Email        string    `json:"email"`
 	PasswordHash string    `json:"-"`
 	CreatedAt    time.Time `json:"created_at"`
+	Role         string    `json:"role"`
 }
+
+// User roles. Every account starts as RoleUser; RoleAdmin is granted in the database.
+const (
+	RoleUser  = "user"
+	RoleAdmin = "admin"
+)
@@ -5,23 +5,47 @@ import (
 	"fmt"
 	"myproject/domain"
 	"regexp"
+	"slices"
 	"strconv"
 	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
 )
 
 var (
 	ErrInvalidTaskID    = errors.New("invalid task ID")
-	ErrInvalidEmail     = errors.New("invalid email format")
-	ErrPasswordTooShort = errors.New("password must be at least 8 characters")
-	ErrPasswordTooLong  = errors.New("password must be max 72 characters")
+	ErrDuplicateTaskID  = errors.New("task ID appears more than once in the batch")
+	ErrInvalidEmail     = domain.ErrInvalidEmail
+	ErrPasswordTooShort = domain.ErrPasswordTooShort
+	ErrPasswordTooLong  = domain.ErrPasswordTooLong
+
+	// ErrTaskIDNotNumber and ErrTaskIDOutOfRange refine ErrInvalidTaskID for logging;
+	// both still match ErrInvalidTaskID with errors.Is.
+	ErrTaskIDNotNumber  = fmt.Errorf("%w: not a number", ErrInvalidTaskID)
+	ErrTaskIDOutOfRange = fmt.Errorf("%w: out of range", ErrInvalidTaskID)
+
+	ErrInvalidLimit  = errors.New("limit must be a positive integer")
+	ErrInvalidOffset = errors.New("offset must be a non-negative integer")
+
+	ErrEmptySearchQuery = errors.New("search query is required")
+	ErrInvalidSort      = errors.New("sort must be one of id, created, updated, done or priority, optionally prefixed with -")
+
+	ErrInvalidCreatedAfter  = errors.New("created_after must be an RFC 3339 timestamp")
+	ErrInvalidCreatedBefore = errors.New("created_before must be an RFC 3339 timestamp")
+	ErrInvalidCreatedRange  = errors.New("created_after must not be later than created_before")
 )
 
 // ValidateTaskID converts a string input to a valid task ID.
-// Returns the parsed ID if valid (positive integer), or an error if invalid.
+// Returns the parsed ID if valid (positive integer), ErrTaskIDOutOfRange if the number
+// does not fit into an int, or ErrTaskIDNotNumber for any other malformed input.
 func ValidateTaskID(input string) (int, error) {
 	id, err := strconv.Atoi(input)
 	if err != nil {
-		return 0, ErrInvalidTaskID
+		if errors.Is(err, strconv.ErrRange) {
+			return 0, ErrTaskIDOutOfRange
+		}
+		return 0, ErrTaskIDNotNumber
 	}
 	if id <= 0 {
 		return 0, ErrInvalidTaskID
@@ -29,21 +53,195 @@ func ValidateTaskID(input string) (int, error) {
 	return id, nil
 }
 
+// ValidateTaskIDs checks the IDs of a batch request.
+// Returns domain.ErrEmptyBatch for an empty list, or a domain.BatchItemError wrapping
+// ErrInvalidTaskID for the first ID that is not positive.
+func ValidateTaskIDs(ids []int) error {
+	if len(ids) == 0 {
+		return domain.ErrEmptyBatch
+	}
+	for i, id := range ids {
+		if id <= 0 {
+			return &domain.BatchItemError{Index: i, Err: ErrInvalidTaskID}
+		}
+	}
+	return nil
+}
+
+// ValidateListOptions parses limit and offset query values into list options.
+// An empty limit defaults to domain.DefaultPageLimit and an empty offset to 0.
+func ValidateListOptions(limitStr, offsetStr string) (domain.ListOptions, error) {
+	opts := domain.ListOptions{Limit: domain.DefaultPageLimit}
+
+	if limitStr != "" {
+		limit, err := strconv.Atoi(limitStr)
+		if err != nil || limit <= 0 {
+			return domain.ListOptions{}, ErrInvalidLimit
+		}
+		opts.Limit = limit
+	}
+
+	if offsetStr != "" {
+		offset, err := strconv.Atoi(offsetStr)
+		if err != nil || offset < 0 {
+			return domain.ListOptions{}, ErrInvalidOffset
+		}
+		opts.Offset = offset
+	}
+
+	return opts, nil
+}
+
+// ValidateTaskSort parses a sort query value such as "created" or "-created".
+// A leading "-" selects descending order; an empty value keeps the default order.
+func ValidateTaskSort(input string) (domain.TaskSort, error) {
+	if input == "" {
+		return domain.TaskSort{}, nil
+	}
+	field, descending := strings.CutPrefix(input, "-")
+	switch domain.SortField(field) {
+	case domain.SortByID, domain.SortByCreated, domain.SortByUpdated, domain.SortByDone, domain.SortByPriority, domain.SortByDue:
+		return domain.TaskSort{Field: domain.SortField(field), Descending: descending}, nil
+	}
+	return domain.TaskSort{}, ErrInvalidSort
+}
+
+// ValidateCreatedRange parses created_after and created_before query values, e.g. "2025-01-31T00:00:00Z",
+// into a task filter. An empty value leaves that bound open.
+func ValidateCreatedRange(afterStr, beforeStr string) (domain.TaskFilter, error) {
+	var filter domain.TaskFilter
+	if afterStr != "" {
+		after, err := time.Parse(time.RFC3339, afterStr)
+		if err != nil {
+			return domain.TaskFilter{}, ErrInvalidCreatedAfter
+		}
+		filter.CreatedAfter = &after
+	}
+	if beforeStr != "" {
+		before, err := time.Parse(time.RFC3339, beforeStr)
+		if err != nil {
+			return domain.TaskFilter{}, ErrInvalidCreatedBefore
+		}
+		filter.CreatedBefore = &before
+	}
+	if filter.CreatedAfter != nil && filter.CreatedBefore != nil && filter.CreatedAfter.After(*filter.CreatedBefore) {
+		return domain.TaskFilter{}, ErrInvalidCreatedRange
+	}
+	return filter, nil
+}
+
+// ValidateSearchQuery trims a search keyword and rejects it if nothing is left.
+func ValidateSearchQuery(input string) (string, error) {
+	query := strings.TrimSpace(input)
+	if query == "" {
+		return "", ErrEmptySearchQuery
+	}
+	return query, nil
+}
+
+// DefaultMaxDescriptionLength is the task description limit used when none is configured.
+const DefaultMaxDescriptionLength = 200
+
 // ValidateTaskDescription validates and sanitizes task description input.
-// Returns trimmed description or error if empty or exceeds 200 characters.
+// Returns trimmed description or error if empty or exceeds DefaultMaxDescriptionLength characters.
 func ValidateTaskDescription(input string) (string, error) {
+	return ValidateTaskDescriptionLength(input, DefaultMaxDescriptionLength)
+}
+
+// ValidateTaskDescriptionLength validates and sanitizes task description input against maxLength.
+// Returns trimmed description, ErrDescriptionRequired if empty, or an error wrapping
+// ErrDescriptionTooLong that reports the limit.
+func ValidateTaskDescriptionLength(input string, maxLength int) (string, error) {
 	if len(input) == 0 {
 		return "", domain.ErrDescriptionRequired
 	}
 
 	input = strings.TrimSpace(input)
-	if len(input) > 200 {
-		return "", domain.ErrDescriptionTooLong
+	if len(input) > maxLength {
+		return "", fmt.Errorf("%w (max %d characters)", domain.ErrDescriptionTooLong, maxLength)
 	}
 
 	return input, nil
 }
 
+// MaxDueDatePast is how far before now a new task's due date may lie, leaving room for clock skew and time zones.
+const MaxDueDatePast = 24 * time.Hour
+
+// ValidateDueDate rejects due dates more than MaxDueDatePast before now.
+func ValidateDueDate(due, now time.Time) error {
+	if due.Before(now.Add(-MaxDueDatePast)) {
+		return domain.ErrDueDateInPast
+	}
+	return nil
+}
+
+// ValidatePriority rejects priorities outside domain.PriorityNone to domain.PriorityHigh.
+func ValidatePriority(priority int) error {
+	if priority < domain.PriorityNone || priority > domain.PriorityHigh {
+		return domain.ErrInvalidPriority
+	}
+	return nil
+}
+
+// priorityNames are the names ParsePriority accepts besides the numbers 0 to 3.
+var priorityNames = map[string]int{
+	"none":   domain.PriorityNone,
+	"low":    domain.PriorityLow,
+	"med":    domain.PriorityMedium,
+	"medium": domain.PriorityMedium,
+	"high":   domain.PriorityHigh,
+}
+
+// ParsePriority reads a priority given as 0 to 3 or as none, low, med(ium) or high, ignoring case.
+func ParsePriority(input string) (int, error) {
+	input = strings.ToLower(strings.TrimSpace(input))
+	if priority, ok := priorityNames[input]; ok {
+		return priority, nil
+	}
+	priority, err := strconv.Atoi(input)
+	if err != nil {
+		return 0, domain.ErrInvalidPriority
+	}
+	if err := ValidatePriority(priority); err != nil {
+		return 0, err
+	}
+	return priority, nil
+}
+
+// MaxTagLength is the longest tag NormalizeTag accepts, in characters.
+const MaxTagLength = 32
+
+// NormalizeTag trims and lowercases a tag so tags match regardless of case.
+// Returns domain.ErrInvalidTag if the tag is empty, too long or contains whitespace or commas.
+func NormalizeTag(input string) (string, error) {
+	tag := strings.ToLower(strings.TrimSpace(input))
+	if tag == "" || utf8.RuneCountInString(tag) > MaxTagLength || strings.ContainsFunc(tag, isTagSeparator) {
+		return "", domain.ErrInvalidTag
+	}
+	return tag, nil
+}
+
+// NormalizeTags normalizes every tag and drops duplicates, returning the tags sorted by name.
+func NormalizeTags(input []string) ([]string, error) {
+	if len(input) == 0 {
+		return nil, nil
+	}
+	tags := make([]string, 0, len(input))
+	for _, tag := range input {
+		tag, err := NormalizeTag(tag)
+		if err != nil {
+			return nil, err
+		}
+		tags = append(tags, tag)
+	}
+	slices.Sort(tags)
+	return slices.Compact(tags), nil
+}
+
+func isTagSeparator(r rune) bool {
+	return r == ',' || unicode.IsSpace(r)
+}
+
 // ExtractTaskIDFromPath extracts and validates a task ID from a URL path.
 // Expects paths like "/tasks/123" and returns the numeric ID or validation error.
 func ExtractTaskIDFromPath(path string) (int, error) {
@@ -64,33 +262,23 @@ func ExtractTaskIDFromPath(path string) (int, error) {
 	return id, nil
 }
 
+// emailRegex accepts ASCII addresses whose local part and domain labels are separated by single dots
+// and whose top-level domain has at least two letters. Unicode domains must be given in punycode (xn--).
+var emailRegex = regexp.MustCompile(`^[a-zA-Z0-9_%+-]+(\.[a-zA-Z0-9_%+-]+)*@([a-zA-Z0-9-]+\.)+[a-zA-Z]{2,}$`)
+
 // ValidateEmail checks if an email address has a valid format.
-// Returns the email if valid, or an error if the format is invalid.
+// Both the server and the CLI call it, so they accept exactly the same addresses.
+// Surrounding whitespace is not trimmed; callers trim user input first.
+// Returns ErrInvalidEmail if the format is invalid.
 func ValidateEmail(email string) error {
-	email = strings.TrimSpace(email)
-	if email == "" {
-		return ErrInvalidEmail
-	}
-
-	// Use the same regex pattern as the server-side validation
-	emailRegex := regexp.MustCompile(`^[a-zA-Z0-9._%+-]+@[a-zA-Z0-9.-]+\.[a-zA-Z]{2,}$`)
 	if !emailRegex.MatchString(email) {
 		return ErrInvalidEmail
 	}
-
 	return nil
 }
 
-// ValidatePassword checks if a password meets minimum security requirements.
-// Password must be between 8 and 72 characters (bcrypt limitation).
+// ValidatePassword checks a password against the default password policy:
+// at least 8 characters with a letter and a digit, and no more than 72 bytes (bcrypt limitation).
 func ValidatePassword(password string) error {
-	if len(password) < 8 {
-		return ErrPasswordTooShort
-	}
-
-	if len(password) > 72 {
-		return ErrPasswordTooLong
-	}
-
-	return nil
+	return domain.DefaultPasswordPolicy().Validate(password)
 }
@@ -2,7 +2,12 @@ package validation
 
 import (
 	"errors"
+	"myproject/domain"
+	"reflect"
+	"slices"
+	"strings"
 	"testing"
+	"time"
 )
 
 func TestValidateTaskID(t *testing.T) {
@@ -25,11 +30,23 @@ func TestValidateTaskID(t *testing.T) {
 			expectedID:  9223372036854775807,
 			expectedErr: nil,
 		},
+		{
+			name:        "ID overflowing int64",
+			input:       "99999999999999999999999",
+			expectedID:  0,
+			expectedErr: ErrTaskIDOutOfRange,
+		},
+		{
+			name:        "Negative ID overflowing int64",
+			input:       "-99999999999999999999999",
+			expectedID:  0,
+			expectedErr: ErrTaskIDOutOfRange,
+		},
 		{
 			name:        "Invalid ID with letters",
 			input:       "abc",
 			expectedID:  0,
-			expectedErr: ErrInvalidTaskID,
+			expectedErr: ErrTaskIDNotNumber,
 		},
 		{
 			name:        "ID with trailing letters",
@@ -85,6 +102,10 @@ func TestValidateTaskID(t *testing.T) {
 				t.Errorf("Expected %v, got %v", tc.expectedErr, err)
 			}
 
+			if tc.expectedErr != nil && !errors.Is(err, ErrInvalidTaskID) {
+				t.Errorf("Expected %v to match ErrInvalidTaskID", err)
+			}
+
 			if id != tc.expectedID {
 				t.Errorf("Expected ID %d, got %d", tc.expectedID, id)
 			}
@@ -92,6 +113,454 @@ func TestValidateTaskID(t *testing.T) {
 	}
 }
 
+func TestValidateTaskIDs(t *testing.T) {
+	// ====Arrange====
+	testCases := []struct {
+		name          string
+		input         []int
+		expectedErr   error
+		expectedIndex int
+	}{
+		{
+			name:  "Positive IDs",
+			input: []int{1, 2, 3},
+		},
+		{
+			name:        "Empty list",
+			input:       []int{},
+			expectedErr: domain.ErrEmptyBatch,
+		},
+		{
+			name:          "Zero ID",
+			input:         []int{1, 0},
+			expectedErr:   ErrInvalidTaskID,
+			expectedIndex: 1,
+		},
+		{
+			name:          "Negative ID",
+			input:         []int{-5, 2},
+			expectedErr:   ErrInvalidTaskID,
+			expectedIndex: 0,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// ====Act====
+			err := ValidateTaskIDs(tc.input)
+
+			// ====Assert====
+			if !errors.Is(err, tc.expectedErr) {
+				t.Errorf("Expected %v, got %v", tc.expectedErr, err)
+			}
+
+			var itemErr *domain.BatchItemError
+			if errors.As(err, &itemErr) && itemErr.Index != tc.expectedIndex {
+				t.Errorf("Expected index %d, got %d", tc.expectedIndex, itemErr.Index)
+			}
+		})
+	}
+}
+
+func TestValidateListOptions(t *testing.T) {
+	// ====Arrange====
+	testCases := []struct {
+		name         string
+		limit        string
+		offset       string
+		expectedOpts domain.ListOptions
+		expectedErr  error
+	}{
+		{
+			name:         "Defaults when absent",
+			expectedOpts: domain.ListOptions{Limit: domain.DefaultPageLimit},
+		},
+		{
+			name:         "Valid limit and offset",
+			limit:        "20",
+			offset:       "40",
+			expectedOpts: domain.ListOptions{Limit: 20, Offset: 40},
+		},
+		{
+			name:        "Zero limit",
+			limit:       "0",
+			expectedErr: ErrInvalidLimit,
+		},
+		{
+			name:        "Negative limit",
+			limit:       "-1",
+			expectedErr: ErrInvalidLimit,
+		},
+		{
+			name:        "Non-numeric limit",
+			limit:       "all",
+			expectedErr: ErrInvalidLimit,
+		},
+		{
+			name:        "Negative offset",
+			offset:      "-10",
+			expectedErr: ErrInvalidOffset,
+		},
+		{
+			name:        "Overflowing offset",
+			offset:      "99999999999999999999999",
+			expectedErr: ErrInvalidOffset,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// ====Act====
+			opts, err := ValidateListOptions(tc.limit, tc.offset)
+
+			// ====Assert====
+			if !errors.Is(err, tc.expectedErr) {
+				t.Errorf("Expected %v, got %v", tc.expectedErr, err)
+			}
+
+			if opts != tc.expectedOpts {
+				t.Errorf("Expected options %+v, got %+v", tc.expectedOpts, opts)
+			}
+		})
+	}
+}
+
+func TestValidateCreatedRange(t *testing.T) {
+	// ====Arrange====
+	after := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	before := time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC)
+	testCases := []struct {
+		name           string
+		after          string
+		before         string
+		expectedFilter domain.TaskFilter
+		expectedErr    error
+	}{
+		{
+			name:           "Open range when absent",
+			expectedFilter: domain.TaskFilter{},
+		},
+		{
+			name:           "Both bounds",
+			after:          "2025-01-01T00:00:00Z",
+			before:         "2025-02-01T00:00:00Z",
+			expectedFilter: domain.TaskFilter{CreatedAfter: &after, CreatedBefore: &before},
+		},
+		{
+			name:           "Lower bound only",
+			after:          "2025-01-01T00:00:00Z",
+			expectedFilter: domain.TaskFilter{CreatedAfter: &after},
+		},
+		{
+			name:        "Date without time",
+			after:       "2025-01-01",
+			expectedErr: ErrInvalidCreatedAfter,
+		},
+		{
+			name:        "Malformed upper bound",
+			before:      "yesterday",
+			expectedErr: ErrInvalidCreatedBefore,
+		},
+		{
+			name:        "Reversed range",
+			after:       "2025-02-01T00:00:00Z",
+			before:      "2025-01-01T00:00:00Z",
+			expectedErr: ErrInvalidCreatedRange,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// ====Act====
+			filter, err := ValidateCreatedRange(tc.after, tc.before)
+
+			// ====Assert====
+			if !errors.Is(err, tc.expectedErr) {
+				t.Errorf("Expected %v, got %v", tc.expectedErr, err)
+			}
+			if !reflect.DeepEqual(filter, tc.expectedFilter) {
+				t.Errorf("Expected filter %+v, got %+v", tc.expectedFilter, filter)
+			}
+		})
+	}
+}
+
+func TestValidateTaskSort(t *testing.T) {
+	// ====Arrange====
+	testCases := []struct {
+		name         string
+		input        string
+		expectedSort domain.TaskSort
+		expectedErr  error
+	}{
+		{
+			name:         "Default order when absent",
+			expectedSort: domain.TaskSort{},
+		},
+		{
+			name:         "Ascending field",
+			input:        "created",
+			expectedSort: domain.TaskSort{Field: domain.SortByCreated},
+		},
+		{
+			name:         "Descending field",
+			input:        "-id",
+			expectedSort: domain.TaskSort{Field: domain.SortByID, Descending: true},
+		},
+		{
+			name:         "Done status",
+			input:        "done",
+			expectedSort: domain.TaskSort{Field: domain.SortByDone},
+		},
+		{
+			name:         "Priority descending",
+			input:        "-priority",
+			expectedSort: domain.TaskSort{Field: domain.SortByPriority, Descending: true},
+		},
+		{
+			name:         "Due date",
+			input:        "due",
+			expectedSort: domain.TaskSort{Field: domain.SortByDue},
+		},
+		{
+			name:        "Unknown field",
+			input:       "color",
+			expectedErr: ErrInvalidSort,
+		},
+		{
+			name:        "SQL fragment",
+			input:       "id; DROP TABLE tasks",
+			expectedErr: ErrInvalidSort,
+		},
+		{
+			name:        "Lone dash",
+			input:       "-",
+			expectedErr: ErrInvalidSort,
+		},
+		{
+			name:        "Case sensitive",
+			input:       "ID",
+			expectedErr: ErrInvalidSort,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// ====Act====
+			sort, err := ValidateTaskSort(tc.input)
+
+			// ====Assert====
+			if !errors.Is(err, tc.expectedErr) {
+				t.Errorf("Expected %v, got %v", tc.expectedErr, err)
+			}
+
+			if sort != tc.expectedSort {
+				t.Errorf("Expected sort %+v, got %+v", tc.expectedSort, sort)
+			}
+		})
+	}
+}
+
+func TestValidateSearchQuery(t *testing.T) {
+	// ====Arrange====
+	testCases := []struct {
+		name          string
+		input         string
+		expectedQuery string
+		expectedErr   error
+	}{
+		{
+			name:          "Keyword is trimmed",
+			input:         "  milk ",
+			expectedQuery: "milk",
+		},
+		{
+			name:        "Empty query",
+			input:       "",
+			expectedErr: ErrEmptySearchQuery,
+		},
+		{
+			name:        "Whitespace only",
+			input:       " \t ",
+			expectedErr: ErrEmptySearchQuery,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// ====Act====
+			query, err := ValidateSearchQuery(tc.input)
+
+			// ====Assert====
+			if !errors.Is(err, tc.expectedErr) {
+				t.Errorf("Expected %v, got %v", tc.expectedErr, err)
+			}
+
+			if query != tc.expectedQuery {
+				t.Errorf("Expected query %q, got %q", tc.expectedQuery, query)
+			}
+		})
+	}
+}
+
+func TestValidateTaskDescriptionLength(t *testing.T) {
+	// ====Arrange====
+	testCases := []struct {
+		name          string
+		input         string
+		maxLength     int
+		expectedDesc  string
+		expectedErr   error
+		expectedInMsg string
+	}{
+		{name: "Description is trimmed", input: "  buy milk ", maxLength: 10, expectedDesc: "buy milk"},
+		{name: "Exactly at the limit", input: "0123456789", maxLength: 10, expectedDesc: "0123456789"},
+		{name: "Over the limit", input: "0123456789a", maxLength: 10, expectedErr: domain.ErrDescriptionTooLong, expectedInMsg: "max 10 characters"},
+		{name: "Default limit", input: strings.Repeat("a", 201), maxLength: DefaultMaxDescriptionLength, expectedErr: domain.ErrDescriptionTooLong, expectedInMsg: "max 200 characters"},
+		{name: "Empty description", input: "", maxLength: 10, expectedErr: domain.ErrDescriptionRequired},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// ====Act====
+			desc, err := ValidateTaskDescriptionLength(tc.input, tc.maxLength)
+
+			// ====Assert====
+			if !errors.Is(err, tc.expectedErr) {
+				t.Errorf("Expected %v, got %v", tc.expectedErr, err)
+			}
+
+			if err != nil && !strings.Contains(err.Error(), tc.expectedInMsg) {
+				t.Errorf("Expected error to contain %q, got %q", tc.expectedInMsg, err.Error())
+			}
+
+			if desc != tc.expectedDesc {
+				t.Errorf("Expected description %q, got %q", tc.expectedDesc, desc)
+			}
+		})
+	}
+}
+
+func TestValidateDueDate(t *testing.T) {
+	// ====Arrange====
+	now := time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC)
+	testCases := []struct {
+		name        string
+		due         time.Time
+		expectedErr error
+	}{
+		{name: "Future due date", due: now.AddDate(0, 1, 0)},
+		{name: "Earlier today", due: now.Add(-6 * time.Hour)},
+		{name: "Exactly at the limit", due: now.Add(-MaxDueDatePast)},
+		{name: "Beyond the limit", due: now.Add(-MaxDueDatePast - time.Second), expectedErr: domain.ErrDueDateInPast},
+		{name: "Last year", due: now.AddDate(-1, 0, 0), expectedErr: domain.ErrDueDateInPast},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// ====Act====
+			err := ValidateDueDate(tc.due, now)
+
+			// ====Assert====
+			if !errors.Is(err, tc.expectedErr) {
+				t.Errorf("Expected %v, got %v", tc.expectedErr, err)
+			}
+		})
+	}
+}
+
+func TestParsePriority(t *testing.T) {
+	// ====Arrange====
+	testCases := []struct {
+		name             string
+		input            string
+		expectedPriority int
+		expectedErr      error
+	}{
+		{name: "Lowest number", input: "0", expectedPriority: domain.PriorityNone},
+		{name: "Highest number", input: "3", expectedPriority: domain.PriorityHigh},
+		{name: "Name", input: "low", expectedPriority: domain.PriorityLow},
+		{name: "Short name", input: "med", expectedPriority: domain.PriorityMedium},
+		{name: "Name ignores case and spaces", input: " High ", expectedPriority: domain.PriorityHigh},
+		{name: "Above range", input: "4", expectedErr: domain.ErrInvalidPriority},
+		{name: "Negative", input: "-1", expectedErr: domain.ErrInvalidPriority},
+		{name: "Unknown name", input: "urgent", expectedErr: domain.ErrInvalidPriority},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// ====Act====
+			priority, err := ParsePriority(tc.input)
+
+			// ====Assert====
+			if !errors.Is(err, tc.expectedErr) {
+				t.Errorf("Expected %v, got %v", tc.expectedErr, err)
+			}
+			if priority != tc.expectedPriority {
+				t.Errorf("Expected priority %d, got %d", tc.expectedPriority, priority)
+			}
+		})
+	}
+}
+
+func TestNormalizeTag(t *testing.T) {
+	// ====Arrange====
+	testCases := []struct {
+		name        string
+		input       string
+		expectedTag string
+		expectedErr error
+	}{
+		{name: "Lowercase tag", input: "work", expectedTag: "work"},
+		{name: "Mixed case and spaces", input: "  Work ", expectedTag: "work"},
+		{name: "Non-ASCII tag", input: "Дом", expectedTag: "дом"},
+		{name: "Longest tag", input: strings.Repeat("a", MaxTagLength), expectedTag: strings.Repeat("a", MaxTagLength)},
+		{name: "Empty tag", input: "   ", expectedErr: domain.ErrInvalidTag},
+		{name: "Too long", input: strings.Repeat("a", MaxTagLength+1), expectedErr: domain.ErrInvalidTag},
+		{name: "Inner space", input: "day job", expectedErr: domain.ErrInvalidTag},
+		{name: "Comma", input: "work,home", expectedErr: domain.ErrInvalidTag},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// ====Act====
+			tag, err := NormalizeTag(tc.input)
+
+			// ====Assert====
+			if !errors.Is(err, tc.expectedErr) {
+				t.Errorf("Expected %v, got %v", tc.expectedErr, err)
+			}
+			if tag != tc.expectedTag {
+				t.Errorf("Expected tag %q, got %q", tc.expectedTag, tag)
+			}
+		})
+	}
+}
+
+func TestNormalizeTags(t *testing.T) {
+	t.Run("Sorts and drops duplicates", func(t *testing.T) {
+		// ====Act====
+		tags, err := NormalizeTags([]string{"Work", "home", "work"})
+
+		// ====Assert====
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if !slices.Equal(tags, []string{"home", "work"}) {
+			t.Errorf("Expected [home work], got %v", tags)
+		}
+	})
+	t.Run("Rejects an invalid tag", func(t *testing.T) {
+		// ====Act====
+		_, err := NormalizeTags([]string{"work", ""})
+
+		// ====Assert====
+		if !errors.Is(err, domain.ErrInvalidTag) {
+			t.Errorf("Expected %v, got %v", domain.ErrInvalidTag, err)
+		}
+	})
+}
+
 func TestValidateEmail(t *testing.T) {
 	testCases := []struct {
 		name        string
@@ -153,14 +622,69 @@ func TestValidateEmail(t *testing.T) {
 			email:       "user@example.c",
 			expectError: true,
 		},
+		{
+			name:        "Valid email - punycode domain",
+			email:       "user@xn--mnchen-3ya.de",
+			expectError: false,
+		},
+		{
+			name:        "Invalid email - leading dot",
+			email:       ".user@example.com",
+			expectError: true,
+		},
+		{
+			name:        "Invalid email - trailing dot in local part",
+			email:       "user.@example.com",
+			expectError: true,
+		},
+		{
+			name:        "Invalid email - consecutive dots",
+			email:       "first..last@example.com",
+			expectError: true,
+		},
+		{
+			name:        "Invalid email - domain starts with dot",
+			email:       "user@.example.com",
+			expectError: true,
+		},
+		{
+			name:        "Invalid email - empty domain label",
+			email:       "user@example..com",
+			expectError: true,
+		},
+		{
+			name:        "Invalid email - missing TLD after dot",
+			email:       "user@example.",
+			expectError: true,
+		},
+		{
+			name:        "Invalid email - numeric TLD",
+			email:       "user@example.123",
+			expectError: true,
+		},
+		{
+			name:        "Invalid email - unicode domain",
+			email:       "user@münchen.de",
+			expectError: true,
+		},
+		{
+			name:        "Invalid email - surrounding whitespace",
+			email:       " user@example.com ",
+			expectError: true,
+		},
+		{
+			name:        "Invalid email - two @",
+			email:       "user@@example.com",
+			expectError: true,
+		},
 	}
 
 	for _, tc := range testCases {
 		t.Run(tc.name, func(t *testing.T) {
 			err := ValidateEmail(tc.email)
 
-			if tc.expectError && err == nil {
-				t.Errorf("Expected error for email %q, but got none", tc.email)
+			if tc.expectError && !errors.Is(err, ErrInvalidEmail) {
+				t.Errorf("Expected ErrInvalidEmail for email %q, got: %v", tc.email, err)
 			}
 
 			if !tc.expectError && err != nil {
@@ -178,12 +702,12 @@ func TestValidatePassword(t *testing.T) {
 	}{
 		{
 			name:        "Valid password - 8 characters",
-			password:    "12345678",
+			password:    "abcd1234",
 			expectedErr: nil,
 		},
 		{
 			name:        "Valid password - 72 characters",
-			password:    "123456789012345678901234567890123456789012345678901234567890123456789012",
+			password:    "a23456789012345678901234567890123456789012345678901234567890123456789012",
 			expectedErr: nil,
 		},
 		{
@@ -206,6 +730,11 @@ func TestValidatePassword(t *testing.T) {
 			password:    "1234567890123456789012345678901234567890123456789012345678901234567890123",
 			expectedErr: ErrPasswordTooLong,
 		},
+		{
+			name:        "Invalid password - digits only",
+			password:    "12345678",
+			expectedErr: domain.ErrPasswordMissingLetter,
+		},
 	}
 
 	for _, tc := range testCases {
@@ -2,26 +2,94 @@ package testhelpers
 
 import (
 	"context"
+	"maps"
 	"myproject/domain"
+	"slices"
+	"strings"
+	"time"
 )
 
 type SpyTaskService struct {
-	LastDescription string
-	LastUserID      int
-	ResultTask      domain.Task
-	ResultErr       error
-	TasksTable      []domain.Task
-	GetTasksError   error
+	LastDescription   string
+	LastDone          bool
+	LastDueDate       *time.Time
+	LastPriority      int
+	LastTags          []string
+	LastUserID        int
+	LastTaskID        int
+	LastListOptions   domain.ListOptions
+	UpdateDescription *string
+	UpdateDone        *bool
+	UpdateDueDate     *time.Time
+	UpdatePriority    *int
+	UpdateVersion     *int
+	ResultTask        domain.Task
+	ResultErr         error
+	TasksTable        []domain.Task
+	GetTasksError     error
+	DeletedTaskIDs    []int
+	LastBatch         []domain.Task
+	LastUpdates       []domain.TaskUpdate
 }
 
-func (ts *SpyTaskService) CreateTask(ctx context.Context, description string, userID int) (domain.Task, error) {
+func (ts *SpyTaskService) CreateTask(ctx context.Context, description string, done bool, dueDate *time.Time, priority int, tags []string, userID int) (domain.Task, error) {
 	ts.LastDescription = description
+	ts.LastDone = done
+	ts.LastDueDate = dueDate
+	ts.LastPriority = priority
+	ts.LastTags = tags
 	ts.LastUserID = userID
 	return ts.ResultTask, ts.ResultErr
 }
 
-func (ts *SpyTaskService) UpdateTask(ctx context.Context, taskID, userID int, description *string, done *bool) (domain.Task, error) {
-	return domain.Task{}, nil
+func (ts *SpyTaskService) CreateTasks(ctx context.Context, tasks []domain.Task, userID int) ([]domain.Task, error) {
+	ts.LastBatch = tasks
+	ts.LastUserID = userID
+	if ts.ResultErr != nil {
+		return nil, ts.ResultErr
+	}
+	created := make([]domain.Task, len(tasks))
+	for i, task := range tasks {
+		task.ID = i + 1
+		created[i] = task
+	}
+	return created, nil
+}
+
+func (ts *SpyTaskService) UpdateTask(ctx context.Context, taskID, userID int, description *string, done *bool, dueDate *time.Time, priority *int, version *int) (domain.Task, error) {
+	ts.LastTaskID = taskID
+	ts.LastUserID = userID
+	ts.UpdateDescription = description
+	ts.UpdateDone = done
+	ts.UpdateDueDate = dueDate
+	ts.UpdatePriority = priority
+	ts.UpdateVersion = version
+	return ts.ResultTask, ts.ResultErr
+}
+
+func (ts *SpyTaskService) UpdateTasks(ctx context.Context, updates []domain.TaskUpdate, userID int) ([]domain.Task, error) {
+	ts.LastUpdates = updates
+	ts.LastUserID = userID
+	if ts.ResultErr != nil {
+		return nil, ts.ResultErr
+	}
+	updated := make([]domain.Task, len(updates))
+	for i, update := range updates {
+		updated[i] = domain.Task{ID: update.ID, Version: 1}
+		if update.Description != nil {
+			updated[i].Description = *update.Description
+		}
+		if update.Done != nil {
+			updated[i].Done = *update.Done
+		}
+	}
+	return updated, nil
+}
+
+func (ts *SpyTaskService) ToggleTask(ctx context.Context, taskID, userID int) (domain.Task, error) {
+	ts.LastTaskID = taskID
+	ts.LastUserID = userID
+	return ts.ResultTask, ts.ResultErr
 }
 
 func (ts *SpyTaskService) GetTasks(ctx context.Context, userID int) ([]domain.Task, error) {
@@ -29,11 +97,43 @@ func (ts *SpyTaskService) GetTasks(ctx context.Context, userID int) ([]domain.Ta
 	return ts.TasksTable, ts.GetTasksError
 }
 
+func (ts *SpyTaskService) GetTask(ctx context.Context, taskID, userID int) (domain.Task, error) {
+	ts.LastTaskID = taskID
+	ts.LastUserID = userID
+	return ts.ResultTask, ts.ResultErr
+}
+
+func (ts *SpyTaskService) ListTasks(ctx context.Context, userID int, opts domain.ListOptions) (domain.TaskPage, error) {
+	ts.LastUserID = userID
+	ts.LastListOptions = opts
+	if ts.GetTasksError != nil {
+		return domain.TaskPage{}, ts.GetTasksError
+	}
+	return domain.TaskPage{Tasks: ts.TasksTable, Total: len(ts.TasksTable), Limit: opts.Limit, Offset: opts.Offset}, nil
+}
+
+func (ts *SpyTaskService) DeleteTask(ctx context.Context, taskID, userID int) error {
+	ts.LastTaskID = taskID
+	ts.LastUserID = userID
+	if ts.ResultErr != nil {
+		return ts.ResultErr
+	}
+	ts.DeletedTaskIDs = append(ts.DeletedTaskIDs, taskID)
+	return nil
+}
+
 type StubTaskStore struct {
 	Tasks            map[int]string
 	CreateCall       []int
 	TasksTable       []domain.Task
 	UpdateTaskCalled int
+	LastListOptions  domain.ListOptions
+	LastSearchQuery  string
+	TaskTags         map[int][]string
+	DeletedTasks     map[int]string
+	PurgedTaskIDs    []int
+	DoneTasks        map[int]bool
+	ArchivedTaskIDs  []int
 }
 
 func (s *StubTaskStore) GetTaskByID(ctx context.Context, id int, userID int) (task domain.Task, err error) {
@@ -41,7 +141,7 @@ func (s *StubTaskStore) GetTaskByID(ctx context.Context, id int, userID int) (ta
 	if !ok {
 		return domain.Task{}, domain.ErrTaskNotFound
 	}
-	return domain.Task{ID: id, Description: t}, nil
+	return domain.Task{ID: id, Description: t, Done: s.DoneTasks[id], Tags: s.TaskTags[id]}, nil
 }
 
 func (s *StubTaskStore) CreateTask(ctx context.Context, task domain.Task, userID int) (int, error) {
@@ -49,8 +149,74 @@ func (s *StubTaskStore) CreateTask(ctx context.Context, task domain.Task, userID
 	return task.ID, nil
 }
 
-func (s *StubTaskStore) LoadTasks(ctx context.Context, userID int) ([]domain.Task, error) {
-	return s.TasksTable, nil
+func (s *StubTaskStore) CreateTasks(ctx context.Context, tasks []domain.Task, userID int) ([]int, error) {
+	ids := make([]int, len(tasks))
+	for i, task := range tasks {
+		s.CreateCall = append(s.CreateCall, task.ID)
+		ids[i] = task.ID
+	}
+	return ids, nil
+}
+
+func (s *StubTaskStore) LoadTasks(ctx context.Context, userID int, opts domain.ListOptions) ([]domain.Task, error) {
+	s.LastListOptions = opts
+	if opts.Offset >= len(s.TasksTable) {
+		return []domain.Task{}, nil
+	}
+	end := len(s.TasksTable)
+	if opts.Limit > 0 && opts.Offset+opts.Limit < end {
+		end = opts.Offset + opts.Limit
+	}
+	return s.TasksTable[opts.Offset:end], nil
+}
+
+func (s *StubTaskStore) CountTasks(ctx context.Context, userID int, filter domain.TaskFilter) (int, error) {
+	return len(s.TasksTable), nil
+}
+
+func (s *StubTaskStore) TaskStats(ctx context.Context, userID int) (domain.Stats, error) {
+	stats := domain.Stats{Total: len(s.TasksTable)}
+	for _, task := range s.TasksTable {
+		if task.Done {
+			stats.Done++
+		}
+	}
+	stats.Pending = stats.Total - stats.Done
+	return stats, nil
+}
+
+func (s *StubTaskStore) SearchTasks(ctx context.Context, userID int, query string) ([]domain.Task, error) {
+	s.LastSearchQuery = query
+	tasks := make([]domain.Task, 0)
+	for _, task := range s.TasksTable {
+		if strings.Contains(strings.ToLower(task.Description), strings.ToLower(query)) {
+			tasks = append(tasks, task)
+		}
+	}
+	return tasks, nil
+}
+
+func (s *StubTaskStore) AddTag(ctx context.Context, taskID int, tag string, userID int) error {
+	if _, ok := s.Tasks[taskID]; !ok {
+		return domain.ErrTaskNotFound
+	}
+	if s.TaskTags == nil {
+		s.TaskTags = make(map[int][]string)
+	}
+	if !slices.Contains(s.TaskTags[taskID], tag) {
+		s.TaskTags[taskID] = append(s.TaskTags[taskID], tag)
+	}
+	return nil
+}
+
+func (s *StubTaskStore) RemoveTag(ctx context.Context, taskID int, tag string, userID int) error {
+	if _, ok := s.Tasks[taskID]; !ok {
+		return domain.ErrTaskNotFound
+	}
+	if tags, ok := s.TaskTags[taskID]; ok {
+		s.TaskTags[taskID] = slices.DeleteFunc(tags, func(t string) bool { return t == tag })
+	}
+	return nil
 }
 
 func (s *StubTaskStore) UpdateTask(ctx context.Context, task domain.Task, userID int) error {
@@ -59,20 +225,140 @@ func (s *StubTaskStore) UpdateTask(ctx context.Context, task domain.Task, userID
 	return nil
 }
 
+func (s *StubTaskStore) UpdateTasks(ctx context.Context, tasks []domain.Task, userID int) error {
+	for i, task := range tasks {
+		if _, ok := s.Tasks[task.ID]; !ok {
+			return &domain.BatchItemError{Index: i, Err: domain.ErrTaskNotFound}
+		}
+	}
+	if s.DoneTasks == nil {
+		s.DoneTasks = make(map[int]bool)
+	}
+	for _, task := range tasks {
+		s.UpdateTaskCalled++
+		s.Tasks[task.ID] = task.Description
+		s.DoneTasks[task.ID] = task.Done
+	}
+	return nil
+}
+
+func (s *StubTaskStore) ToggleTaskDone(ctx context.Context, id int, userID int) (domain.Task, error) {
+	if _, ok := s.Tasks[id]; !ok {
+		return domain.Task{}, domain.ErrTaskNotFound
+	}
+	if s.DoneTasks == nil {
+		s.DoneTasks = make(map[int]bool)
+	}
+	s.DoneTasks[id] = !s.DoneTasks[id]
+	return s.GetTaskByID(ctx, id, userID)
+}
+
 func (s *StubTaskStore) DeleteTask(ctx context.Context, id int, userID int) error {
+	if desc, ok := s.Tasks[id]; ok {
+		if s.DeletedTasks == nil {
+			s.DeletedTasks = make(map[int]string)
+		}
+		s.DeletedTasks[id] = desc
+	}
 	delete(s.Tasks, id)
 	return nil
 }
 
+func (s *StubTaskStore) UpdateAllTaskStatus(ctx context.Context, userID int, done bool) (int, error) {
+	updated := 0
+	for i := range s.TasksTable {
+		if s.TasksTable[i].Done != done {
+			s.TasksTable[i].Done = done
+			updated++
+		}
+	}
+	return updated, nil
+}
+
+func (s *StubTaskStore) DeleteTasks(ctx context.Context, ids []int, userID int) (int, error) {
+	deleted := 0
+	for _, id := range ids {
+		if _, ok := s.Tasks[id]; ok {
+			s.DeleteTask(ctx, id, userID)
+			deleted++
+		}
+	}
+	return deleted, nil
+}
+
+func (s *StubTaskStore) RestoreTask(ctx context.Context, id int, userID int) error {
+	desc, ok := s.DeletedTasks[id]
+	if !ok {
+		return domain.ErrTaskNotFound
+	}
+	delete(s.DeletedTasks, id)
+	s.Tasks[id] = desc
+	return nil
+}
+
+func (s *StubTaskStore) ArchiveTask(ctx context.Context, id int, userID int) error {
+	if _, ok := s.Tasks[id]; !ok {
+		return domain.ErrTaskNotFound
+	}
+	if !slices.Contains(s.ArchivedTaskIDs, id) {
+		s.ArchivedTaskIDs = append(s.ArchivedTaskIDs, id)
+	}
+	return nil
+}
+
+func (s *StubTaskStore) ArchiveCompleted(ctx context.Context, userID int) (int, error) {
+	archived := 0
+	for id, done := range s.DoneTasks {
+		if _, ok := s.Tasks[id]; ok && done && !slices.Contains(s.ArchivedTaskIDs, id) {
+			s.ArchivedTaskIDs = append(s.ArchivedTaskIDs, id)
+			archived++
+		}
+	}
+	return archived, nil
+}
+
+func (s *StubTaskStore) PurgeTask(ctx context.Context, id int, userID int) error {
+	s.PurgedTaskIDs = append(s.PurgedTaskIDs, id)
+	delete(s.Tasks, id)
+	delete(s.DeletedTasks, id)
+	return nil
+}
+
+func (s *StubTaskStore) TaskExistsByDescription(ctx context.Context, userID int, description string) (bool, error) {
+	for _, desc := range s.Tasks {
+		if desc == description {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (s *StubTaskStore) TaskExists(ctx context.Context, id int) (bool, error) {
+	_, ok := s.Tasks[id]
+	return ok, nil
+}
+
+// WithTx runs fn on the stub itself and restores the task descriptions when fn fails.
+func (s *StubTaskStore) WithTx(ctx context.Context, fn func(tx domain.TxStorage) error) error {
+	tasks := maps.Clone(s.Tasks)
+	if err := fn(s); err != nil {
+		s.Tasks = tasks
+		return err
+	}
+	return nil
+}
+
 func (s *StubTaskStore) Close(ctx context.Context) error {
 	return nil
 }
 
 type SpyAuthService struct {
 	ResultToken  string
+	ResultUser   *domain.User
 	ResultErr    error
 	LastEmail    string
 	LastPassword string
+	LastUserID   int
 }
 
 func (s *SpyAuthService) Register(ctx context.Context, email, password string) (string, error) {
@@ -87,17 +373,44 @@ func (s *SpyAuthService) Login(ctx context.Context, email, password string) (str
 	return s.ResultToken, s.ResultErr
 }
 
+func (s *SpyAuthService) CurrentUser(ctx context.Context, userID int) (*domain.User, error) {
+	s.LastUserID = userID
+	return s.ResultUser, s.ResultErr
+}
+
+func (s *SpyAuthService) DeleteAccount(ctx context.Context, userID int) error {
+	s.LastUserID = userID
+	return s.ResultErr
+}
+
+func (s *SpyAuthService) ListUsers(ctx context.Context, limit, offset int) ([]domain.User, error) {
+	if s.ResultErr != nil {
+		return nil, s.ResultErr
+	}
+	if s.ResultUser == nil {
+		return []domain.User{}, nil
+	}
+	return []domain.User{*s.ResultUser}, nil
+}
+
+func (s *SpyAuthService) ChangePassword(ctx context.Context, userID int, currentPassword, newPassword string) error {
+	s.LastUserID = userID
+	s.LastPassword = newPassword
+	return s.ResultErr
+}
+
 type StubTokenGenerator struct {
 	Token  string
 	Claims *domain.Claims
 	Err    error
 }
 
-func (tg *StubTokenGenerator) GenerateToken(userID int) (string, error) {
+func (tg *StubTokenGenerator) GenerateToken(userID int, role string) (string, error) {
 	if tg.Err != nil {
 		return "", tg.Err
 	}
 	tg.Claims.UserID = userID
+	tg.Claims.Role = role
 	return tg.Token, nil
 }
 func (tg *StubTokenGenerator) ValidateToken(tokenString string) (*domain.Claims, error) {
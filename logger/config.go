@@ -6,8 +6,11 @@ import (
 	"log/slog"
 	"slices"
 	"strings"
+	"time"
 )
 
+var validLevels = []string{"debug", "info", "warn", "error"}
+
 // Config holds logger configuration for structured logging.
 type Config struct {
 	Level          string `mapstructure:"level"`        // log level: "debug", "info", "warn", or "error"
@@ -20,13 +23,17 @@ type Config struct {
 	MaxSize        int    `mapstructure:"max_size"`
 	MaxAge         int    `mapstructure:"max_age"`
 	MaxBackups     int    `mapstructure:"max_backups"`
+	// SlowRequestThreshold makes LoggingMiddleware log requests that take longer at WARN level; 0 disables it
+	SlowRequestThreshold time.Duration `mapstructure:"slow_request_threshold"`
 }
 
+// DefaultSlowRequestThreshold is the duration above which a request is logged as slow unless configured otherwise.
+const DefaultSlowRequestThreshold = time.Second
+
 // Validate checks all configuration values for correctness.
 // Returns a combined error if any validation fails.
 func (cfg *Config) Validate() error {
 	var errs []error
-	validLevels := []string{"debug", "info", "warn", "error"}
 	if !slices.Contains(validLevels, strings.ToLower(cfg.Level)) {
 		errs = append(errs, fmt.Errorf("invalid level '%s', should be 'debug', 'info', 'warn', 'error'", cfg.Level))
 	}
@@ -48,6 +55,10 @@ func (cfg *Config) Validate() error {
 		errs = append(errs, fmt.Errorf("environment required"))
 	}
 
+	if cfg.SlowRequestThreshold < 0 {
+		errs = append(errs, fmt.Errorf("logging.slow_request_threshold must not be negative, got %v", cfg.SlowRequestThreshold))
+	}
+
 	if cfg.EnableRotation {
 		if cfg.MaxSize <= 0 {
 			errs = append(errs, fmt.Errorf("logging.max_size must be positive when rotation is enabled, got %d", cfg.MaxSize))
@@ -75,3 +86,13 @@ func parseLevel(levelStr string) (level slog.Level) {
 	}
 	return level
 }
+
+// ParseLevel converts one of "debug", "info", "warn" or "error" (in any case) to slog.Level.
+// Unlike parseLevel it rejects anything else instead of falling back to INFO.
+func ParseLevel(levelStr string) (slog.Level, error) {
+	levelStrToLow := strings.ToLower(levelStr)
+	if !slices.Contains(validLevels, levelStrToLow) {
+		return 0, fmt.Errorf("invalid level '%s', should be 'debug', 'info', 'warn', 'error'", levelStr)
+	}
+	return parseLevel(levelStrToLow), nil
+}
@@ -9,6 +9,7 @@ import (
 	"encoding/hex"
 	"fmt"
 	"log/slog"
+	"net/http"
 	"time"
 )
 
@@ -18,8 +19,15 @@ const (
 	requestIDKey contextKey = iota
 	traceIDKey
 	loggerKey
+	clientIPKey
 )
 
+// RequestIDHeader is the header a caller can set to choose the request ID; the ID in use is echoed back in it.
+const RequestIDHeader = "X-Request-ID"
+
+// maxRequestIDLength bounds how much of an inbound request ID ends up in every log line.
+const maxRequestIDLength = 128
+
 // GenerateRequestID creates a unique request ID combining timestamp and random data.
 func GenerateRequestID() string {
 	timeNow := time.Now().UnixMilli()
@@ -32,6 +40,21 @@ func GenerateRequestID() string {
 	return fmt.Sprintf("req_%d_%s", timeNow, hex.EncodeToString(randomBytes))
 }
 
+// requestIDFrom returns the X-Request-ID of r when it is a safe log value, or a generated ID otherwise.
+// Accepted IDs are at most 128 letters, digits, '-', '_' or '.', which covers UUIDs and our own IDs.
+func requestIDFrom(r *http.Request) string {
+	id := r.Header.Get(RequestIDHeader)
+	if id == "" || len(id) > maxRequestIDLength {
+		return GenerateRequestID()
+	}
+	for _, c := range id {
+		if !(c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' || c == '-' || c == '_' || c == '.') {
+			return GenerateRequestID()
+		}
+	}
+	return id
+}
+
 // WithRequestID stores a request ID in the context for request correlation.
 func WithRequestID(ctx context.Context, requestID string) context.Context {
 	return context.WithValue(ctx, requestIDKey, requestID)
@@ -62,6 +85,21 @@ func GetTraceID(ctx context.Context) string {
 	return traceID
 }
 
+// WithClientIP stores the remote client address in the context for audit logging.
+func WithClientIP(ctx context.Context, clientIP string) context.Context {
+	return context.WithValue(ctx, clientIPKey, clientIP)
+}
+
+// GetClientIP retrieves the client IP from the context.
+func GetClientIP(ctx context.Context) string {
+	clientIP, ok := ctx.Value(clientIPKey).(string)
+	if !ok {
+		return ""
+	}
+
+	return clientIP
+}
+
 // WithLogger stores a logger instance in the context.
 func WithLogger(ctx context.Context, logger *slog.Logger) context.Context {
 	return context.WithValue(ctx, loggerKey, logger)
@@ -3,6 +3,8 @@
 package logger
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
 	"strings"
 )
 
@@ -19,6 +21,9 @@ const (
 	FieldEmail      = "email" // Always masked
 	FieldTraceID    = "trace_id"
 	FieldSpanID     = "span_id"
+	FieldClientIP   = "client_ip"
+	// FieldSlowRequest marks requests that took longer than Config.SlowRequestThreshold
+	FieldSlowRequest = "slow_request"
 )
 
 // MaskEmail masks an email address for privacy protection.
@@ -38,6 +43,13 @@ func MaskEmail(email string) string {
 	return userName[:1] + "***" + userName[len(userName)-1:] + "@" + domain
 }
 
+// HashEmail returns a stable, non-reversible identifier for an email address,
+// allowing audit logs to correlate events without exposing the address.
+func HashEmail(email string) string {
+	sum := sha256.Sum256([]byte(strings.ToLower(strings.TrimSpace(email))))
+	return "sha256:" + hex.EncodeToString(sum[:8])
+}
+
 // MaskToken masks authentication tokens and API keys for security.
 func MaskToken(token string) string {
 	if len(token) <= 8 {
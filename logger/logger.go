@@ -56,9 +56,9 @@ func getWriter(cfg *Config) (io.Writer, error) {
 
 // createHandler creates and configures a slog.Handler based on the format specified in cfg.
 // Supports "json" and "text" formats. Defaults to JSON for invalid formats.
-func createHandler(cfg *Config, writer io.Writer) slog.Handler {
+func createHandler(cfg *Config, writer io.Writer, level slog.Leveler) slog.Handler {
 	opts := slog.HandlerOptions{
-		Level:     parseLevel(cfg.Level),
+		Level:     level,
 		AddSource: cfg.AddSource,
 	}
 
@@ -81,23 +81,51 @@ func createHandler(cfg *Config, writer io.Writer) slog.Handler {
 // (service name and environment) that appear in all log entries.
 // Returns an error if the configuration is invalid or output destination cannot be created.
 func NewLogger(cfg *Config) (*slog.Logger, error) {
+	logger, _, err := NewLoggerWithHandle(cfg)
+	return logger, err
+}
+
+// Handle gives access to the runtime state of a logger created by NewLoggerWithHandle.
+type Handle struct {
+	// Level is read by the handler on every record, so setting it changes the minimum level immediately.
+	Level  *slog.LevelVar
+	writer io.Writer
+}
+
+// Close flushes and closes the log file when the logger writes to one.
+// Stdout and stderr are left open.
+func (h *Handle) Close() error {
+	if h.writer == os.Stdout || h.writer == os.Stderr {
+		return nil
+	}
+	if closer, ok := h.writer.(io.Closer); ok {
+		return closer.Close()
+	}
+	return nil
+}
+
+// NewLoggerWithHandle is like NewLogger but also returns a Handle to change the
+// level at runtime and to close the log file on shutdown.
+func NewLoggerWithHandle(cfg *Config) (*slog.Logger, *Handle, error) {
 	if err := cfg.Validate(); err != nil {
-		return nil, fmt.Errorf("failed to validate config: %w", err)
+		return nil, nil, fmt.Errorf("failed to validate config: %w", err)
 	}
 
 	writer, err := getWriter(cfg)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get writer: %w", err)
+		return nil, nil, fmt.Errorf("failed to get writer: %w", err)
 	}
 
-	handler := createHandler(cfg, writer)
+	level := new(slog.LevelVar)
+	level.Set(parseLevel(cfg.Level))
+	handler := createHandler(cfg, writer, level)
 
 	logger := slog.New(handler).With(
 		slog.String("service", cfg.ServiceName),
 		slog.String("environment", cfg.Environment),
 	)
 
-	return logger, nil
+	return logger, &Handle{Level: level, writer: writer}, nil
 }
 
 // NewDefault creates a logger with sensible defaults for development.
@@ -0,0 +1,117 @@
+package logger
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewLoggerWithHandle_RotatesFile(t *testing.T) {
+	dir := t.TempDir()
+	l, handle, err := NewLoggerWithHandle(&Config{
+		Level:          "info",
+		Format:         "text",
+		Output:         filepath.Join(dir, "app.log"),
+		ServiceName:    "test-service",
+		Environment:    "test",
+		EnableRotation: true,
+		MaxSize:        1,
+		MaxAge:         1,
+		MaxBackups:     2,
+	})
+	require.NoError(t, err)
+
+	payload := strings.Repeat("x", 1024)
+	for range 1100 {
+		l.Info("filling log", slog.String("payload", payload))
+	}
+	require.NoError(t, handle.Close())
+
+	// Backups are compressed in the background; wait so the temp dir is not removed under it.
+	assert.Eventually(t, func() bool {
+		compressed, _ := filepath.Glob(filepath.Join(dir, "app-*.log.gz"))
+		pending, _ := filepath.Glob(filepath.Join(dir, "app-*.log"))
+		return len(compressed) > 0 && len(pending) == 0
+	}, 5*time.Second, 10*time.Millisecond, "writing past max_size should leave a compressed backup file")
+
+	current, err := os.Stat(filepath.Join(dir, "app.log"))
+	require.NoError(t, err)
+	assert.Less(t, current.Size(), int64(1024*1024))
+}
+
+func TestHandle_Level(t *testing.T) {
+	logFile := filepath.Join(t.TempDir(), "app.log")
+	l, handle, err := NewLoggerWithHandle(&Config{
+		Level:       "warn",
+		Format:      "text",
+		Output:      logFile,
+		ServiceName: "test-service",
+		Environment: "test",
+	})
+	require.NoError(t, err)
+
+	l.Info("hidden")
+	handle.Level.Set(slog.LevelDebug)
+	l.Debug("visible")
+	require.NoError(t, handle.Close())
+
+	content, err := os.ReadFile(logFile)
+	require.NoError(t, err)
+	assert.NotContains(t, string(content), "hidden")
+	assert.Contains(t, string(content), "visible")
+}
+
+func TestParseLevel(t *testing.T) {
+	for _, valid := range []string{"debug", "INFO", "Warn", "error"} {
+		_, err := ParseLevel(valid)
+		assert.NoError(t, err, valid)
+	}
+	for _, invalid := range []string{"", "verbose", "info+2"} {
+		_, err := ParseLevel(invalid)
+		assert.Error(t, err, invalid)
+	}
+}
+
+func TestLoggingMiddleware_SlowRequest(t *testing.T) {
+	completed := func(t *testing.T, handler http.Handler) map[string]any {
+		var logBuffer bytes.Buffer
+		logger := slog.New(slog.NewJSONHandler(&logBuffer, nil))
+		LoggingMiddleware(logger, 10*time.Millisecond)(handler).
+			ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/tasks", nil))
+
+		for _, line := range strings.Split(strings.TrimSpace(logBuffer.String()), "\n") {
+			var entry map[string]any
+			require.NoError(t, json.Unmarshal([]byte(line), &entry))
+			if entry["msg"] == "HTTP request completed" {
+				return entry
+			}
+		}
+		t.Fatal("no completion log line")
+		return nil
+	}
+
+	t.Run("logs a request past the threshold at WARN", func(t *testing.T) {
+		entry := completed(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			time.Sleep(30 * time.Millisecond)
+		}))
+
+		assert.Equal(t, "WARN", entry["level"])
+		assert.Equal(t, true, entry[FieldSlowRequest])
+	})
+	t.Run("logs a fast request at INFO", func(t *testing.T) {
+		entry := completed(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+
+		assert.Equal(t, "INFO", entry["level"])
+		assert.NotContains(t, entry, FieldSlowRequest)
+	})
+}
@@ -2,6 +2,7 @@ package logger
 
 import (
 	"log/slog"
+	"net"
 	"net/http"
 	"runtime/debug"
 	"time"
@@ -25,14 +26,27 @@ func recoverPanic(logger *slog.Logger, w http.ResponseWriter, r *http.Request) {
 	}
 }
 
+// clientIP extracts the host part of the request's remote address.
+func clientIP(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
+
 // LoggingMiddleware returns HTTP middleware that logs request start/completion with structured fields.
-// Generates unique request IDs for correlation and includes method, path, duration, and user_agent in logs.
-func LoggingMiddleware(logger *slog.Logger) func(http.Handler) http.Handler {
+// Reuses a valid inbound X-Request-ID, or generates one, for correlation and echoes it in the response.
+// Includes method, path, duration, and user_agent in logs.
+// Requests taking longer than slowThreshold complete at WARN level with slow_request=true; zero disables this.
+func LoggingMiddleware(logger *slog.Logger, slowThreshold time.Duration) func(http.Handler) http.Handler {
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-			// Generate request ID and add to context
-			requestID := GenerateRequestID()
+			// Take the caller's request ID or generate one, and add it to context
+			requestID := requestIDFrom(r)
+			w.Header().Set(RequestIDHeader, requestID)
 			ctx := WithRequestID(r.Context(), requestID)
+			ctx = WithClientIP(ctx, clientIP(r))
 			r = r.WithContext(ctx)
 
 			// Record start time
@@ -53,15 +67,20 @@ func LoggingMiddleware(logger *slog.Logger) func(http.Handler) http.Handler {
 			next.ServeHTTP(w, r)
 
 			// Calculate duration
-			duration := time.Since(start).Milliseconds()
-
-			// Log request completion
-			logger.Info("HTTP request completed",
+			elapsed := time.Since(start)
+			attrs := []any{
 				slog.String(FieldRequestID, requestID),
 				slog.String(FieldMethod, r.Method),
 				slog.String(FieldPath, r.URL.Path),
-				slog.Int64(FieldDuration, duration),
-			)
+				slog.Int64(FieldDuration, elapsed.Milliseconds()),
+			}
+
+			// Log request completion, at WARN level when it was slow so it stands out
+			if slowThreshold > 0 && elapsed > slowThreshold {
+				logger.Warn("HTTP request completed", append(attrs, slog.Bool(FieldSlowRequest, true))...)
+				return
+			}
+			logger.Info("HTTP request completed", attrs...)
 		})
 	}
 }